@@ -0,0 +1,137 @@
+// Package analytics counts how the catalog is used: the detail views of
+// services and the terms searched. Only daily counts per organization are
+// kept, never who viewed or searched.
+package analytics
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"com.kong.connect/domain"
+	"com.kong.connect/logging"
+	"com.kong.connect/tenant"
+)
+
+const (
+	// maxTermLength truncates the terms counted, in characters
+	maxTermLength = 100
+	// maxPendingTerms bounds the distinct terms counted between flushes;
+	// further terms are dropped until the next flush
+	maxPendingTerms = 10000
+)
+
+// Store keeps the usage counted by a Tracker
+type Store interface {
+	AddUsage(ctx context.Context, usage domain.Usage) error
+}
+
+type viewKey struct {
+	orgID, serviceID int
+	day              string
+}
+
+type searchKey struct {
+	orgID     int
+	term, day string
+}
+
+// Tracker counts views and searches in memory and adds the counts to a
+// Store periodically, which keeps the database off the path of every read;
+// counts not yet flushed when an instance crashes are lost.
+type Tracker struct {
+	store Store
+	now   func() time.Time
+
+	mu       sync.Mutex
+	views    map[viewKey]int64
+	searches map[searchKey]int64
+}
+
+// NewTracker creates a tracker keeping its counts in store
+func NewTracker(store Store) *Tracker {
+	return &Tracker{store: store, now: time.Now, views: make(map[viewKey]int64), searches: make(map[searchKey]int64)}
+}
+
+// RecordView counts a detail view of service id of the organization of ctx
+func (t *Tracker) RecordView(ctx context.Context, id int) {
+	key := viewKey{orgID: tenant.FromContext(ctx), serviceID: id, day: t.day()}
+	t.mu.Lock()
+	t.views[key]++
+	t.mu.Unlock()
+}
+
+// RecordSearch counts a search of the organization of ctx for the
+// normalized term
+func (t *Tracker) RecordSearch(ctx context.Context, term string) {
+	term = NormalizeTerm(term)
+	if term == "" {
+		return
+	}
+	key := searchKey{orgID: tenant.FromContext(ctx), term: term, day: t.day()}
+	t.mu.Lock()
+	if _, ok := t.searches[key]; ok || len(t.searches) < maxPendingTerms {
+		t.searches[key]++
+	}
+	t.mu.Unlock()
+}
+
+func (t *Tracker) day() string {
+	return t.now().UTC().Format(domain.AnalyticsDayFormat)
+}
+
+// NormalizeTerm lowercases a term and collapses its spaces, so that
+// searches differing only by case or spacing are counted together
+func NormalizeTerm(term string) string {
+	term = strings.Join(strings.Fields(strings.ToLower(term)), " ")
+	if utf8.RuneCountInString(term) > maxTermLength {
+		term = string([]rune(term)[:maxTermLength])
+	}
+	return term
+}
+
+// Flush adds the counts since the previous flush to the store
+func (t *Tracker) Flush(ctx context.Context) error {
+	t.mu.Lock()
+	views, searches := t.views, t.searches
+	t.views, t.searches = make(map[viewKey]int64), make(map[searchKey]int64)
+	t.mu.Unlock()
+	if len(views) == 0 && len(searches) == 0 {
+		return nil
+	}
+
+	var usage domain.Usage
+	for key, count := range views {
+		usage.Views = append(usage.Views, domain.ServiceViewCount{OrgID: key.orgID, ServiceID: key.serviceID, Day: key.day, Views: count})
+	}
+	for key, count := range searches {
+		usage.Searches = append(usage.Searches, domain.SearchTermCount{OrgID: key.orgID, Term: key.term, Day: key.day, Searches: count})
+	}
+	return t.store.AddUsage(ctx, usage)
+}
+
+// Run flushes the counts every interval until ctx is done, and once more
+// then
+func (t *Tracker) Run(ctx context.Context, interval time.Duration, logger *slog.Logger) {
+	logger = logging.Component(logger, "analytics")
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if err := t.Flush(ctx); err != nil {
+				logger.Warn("failed to flush usage", "error", err)
+			}
+		case <-ctx.Done():
+			flushCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 2*time.Second)
+			defer cancel()
+			if err := t.Flush(flushCtx); err != nil {
+				logger.Warn("failed to flush usage", "error", err)
+			}
+			return
+		}
+	}
+}
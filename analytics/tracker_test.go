@@ -0,0 +1,67 @@
+package analytics
+
+import (
+	"context"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"com.kong.connect/domain"
+	"com.kong.connect/tenant"
+)
+
+type usageStore struct {
+	flushes []domain.Usage
+}
+
+func (s *usageStore) AddUsage(_ context.Context, usage domain.Usage) error {
+	s.flushes = append(s.flushes, usage)
+	return nil
+}
+
+func TestTrackerCountsPerOrganizationAndDay(t *testing.T) {
+	store := &usageStore{}
+	tracker := NewTracker(store)
+	day := time.Date(2024, 3, 1, 23, 59, 0, 0, time.UTC)
+	tracker.now = func() time.Time { return day }
+
+	ctx := context.Background()
+	other := tenant.NewContext(ctx, 2)
+	tracker.RecordView(ctx, 1)
+	tracker.RecordView(ctx, 1)
+	tracker.RecordView(other, 1)
+	tracker.RecordSearch(ctx, "  Payments   API ")
+	tracker.RecordSearch(ctx, "payments api")
+	tracker.RecordSearch(ctx, "   ")
+	day = day.Add(time.Minute)
+	tracker.RecordView(ctx, 1)
+
+	require.NoError(t, tracker.Flush(ctx))
+	require.Len(t, store.flushes, 1)
+	views := store.flushes[0].Views
+	sort.Slice(views, func(i, j int) bool {
+		return views[i].OrgID < views[j].OrgID || views[i].OrgID == views[j].OrgID && views[i].Day < views[j].Day
+	})
+	assert.Equal(t, []domain.ServiceViewCount{
+		{OrgID: tenant.DefaultOrg, ServiceID: 1, Day: "2024-03-01", Views: 2},
+		{OrgID: tenant.DefaultOrg, ServiceID: 1, Day: "2024-03-02", Views: 1},
+		{OrgID: 2, ServiceID: 1, Day: "2024-03-01", Views: 1},
+	}, views)
+	assert.Equal(t, []domain.SearchTermCount{{OrgID: tenant.DefaultOrg, Term: "payments api", Day: "2024-03-01", Searches: 2}}, store.flushes[0].Searches)
+
+	// Counts are flushed once; nothing new is not flushed at all
+	require.NoError(t, tracker.Flush(ctx))
+	assert.Len(t, store.flushes, 1)
+}
+
+func TestNormalizeTermTruncates(t *testing.T) {
+	long := ""
+	for range maxTermLength + 10 {
+		long += "é"
+	}
+	assert.Equal(t, maxTermLength, len([]rune(NormalizeTerm(long))))
+	assert.Equal(t, "kong gateway", NormalizeTerm("KONG\tGateway"))
+}
@@ -0,0 +1,33 @@
+package audit
+
+import (
+	"context"
+	"net/http"
+
+	"com.kong.connect/clientip"
+)
+
+// Actor identifies who performed a change and from where
+type Actor struct {
+	Principal string
+	IP        string
+}
+
+type contextKey struct{}
+
+// NewContext returns a copy of ctx carrying the actor
+func NewContext(ctx context.Context, actor Actor) context.Context {
+	return context.WithValue(ctx, contextKey{}, actor)
+}
+
+// FromContext returns the actor stored in ctx, or the zero Actor when there is none
+func FromContext(ctx context.Context) Actor {
+	actor, _ := ctx.Value(contextKey{}).(Actor)
+	return actor
+}
+
+// ClientIP returns the IP address of the client that sent the request, the
+// one behind the trusted proxies when middleware.ClientIP resolved it
+func ClientIP(r *http.Request) string {
+	return clientip.FromRequest(r)
+}
@@ -0,0 +1,139 @@
+package cache
+
+import (
+	"container/list"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// LRU is an in-process cache holding up to a fixed number of entries, each
+// for at most a TTL. When full, the least recently used entry is evicted.
+type LRU[K comparable, V any] struct {
+	size int
+	ttl  time.Duration
+	now  func() time.Time
+
+	mu         sync.Mutex
+	entries    map[K]*list.Element
+	order      *list.List // Front is the most recently used
+	generation uint64
+
+	hits      prometheus.Counter
+	misses    prometheus.Counter
+	evictions prometheus.Counter
+}
+
+type entry[K comparable, V any] struct {
+	key     K
+	value   V
+	expires time.Time
+}
+
+// NewLRU creates a cache of size entries kept for ttl. name labels its
+// metrics, so that several caches can be registered side by side.
+func NewLRU[K comparable, V any](name string, size int, ttl time.Duration) *LRU[K, V] {
+	labels := prometheus.Labels{"cache": name}
+	return &LRU[K, V]{
+		size:    size,
+		ttl:     ttl,
+		now:     time.Now,
+		entries: make(map[K]*list.Element),
+		order:   list.New(),
+		hits: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "cache_hits_total", Help: "Lookups answered from the cache.", ConstLabels: labels,
+		}),
+		misses: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "cache_misses_total", Help: "Lookups not found in the cache or expired.", ConstLabels: labels,
+		}),
+		evictions: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "cache_evictions_total", Help: "Entries evicted to make room for new ones.", ConstLabels: labels,
+		}),
+	}
+}
+
+// Get returns the unexpired value of key
+func (c *LRU[K, V]) Get(key K) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if element, ok := c.entries[key]; ok {
+		e := element.Value.(*entry[K, V])
+		if c.now().Before(e.expires) {
+			c.order.MoveToFront(element)
+			c.hits.Inc()
+			return e.value, true
+		}
+		c.removeElement(element)
+	}
+	c.misses.Inc()
+	var zero V
+	return zero, false
+}
+
+// Generation identifies the state of the cache for Add. Take it before
+// reading the source of a value.
+func (c *LRU[K, V]) Generation() uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.generation
+}
+
+// Add caches value under key unless an entry was removed since generation
+// was taken, in which case value may predate the write that removed it
+func (c *LRU[K, V]) Add(key K, value V, generation uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if generation != c.generation {
+		return
+	}
+
+	expires := c.now().Add(c.ttl)
+	if element, ok := c.entries[key]; ok {
+		element.Value = &entry[K, V]{key: key, value: value, expires: expires}
+		c.order.MoveToFront(element)
+		return
+	}
+	c.entries[key] = c.order.PushFront(&entry[K, V]{key: key, value: value, expires: expires})
+	if c.order.Len() > c.size {
+		c.removeElement(c.order.Back())
+		c.evictions.Inc()
+	}
+}
+
+// Remove invalidates key
+func (c *LRU[K, V]) Remove(key K) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.generation++
+	if element, ok := c.entries[key]; ok {
+		c.removeElement(element)
+	}
+}
+
+// Purge invalidates every entry
+func (c *LRU[K, V]) Purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.generation++
+	c.entries = make(map[K]*list.Element)
+	c.order.Init()
+}
+
+// Len returns the number of entries, expired ones included
+func (c *LRU[K, V]) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
+
+// Collectors returns the cache metrics for registration with metrics.Registry
+func (c *LRU[K, V]) Collectors() []prometheus.Collector {
+	return []prometheus.Collector{c.hits, c.misses, c.evictions}
+}
+
+func (c *LRU[K, V]) removeElement(element *list.Element) {
+	c.order.Remove(element)
+	delete(c.entries, element.Value.(*entry[K, V]).key)
+}
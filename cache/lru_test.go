@@ -0,0 +1,67 @@
+package cache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLRUEvictsLeastRecentlyUsed(t *testing.T) {
+	c := NewLRU[int, string]("test", 2, time.Minute)
+	c.Add(1, "one", c.Generation())
+	c.Add(2, "two", c.Generation())
+	_, ok := c.Get(1)
+	require.True(t, ok)
+
+	c.Add(3, "three", c.Generation())
+	_, ok = c.Get(2)
+	assert.False(t, ok, "2 was the least recently used")
+	value, ok := c.Get(1)
+	assert.True(t, ok)
+	assert.Equal(t, "one", value)
+	assert.Equal(t, 2, c.Len())
+
+	assert.Equal(t, 2.0, testutil.ToFloat64(c.hits))
+	assert.Equal(t, 1.0, testutil.ToFloat64(c.misses))
+	assert.Equal(t, 1.0, testutil.ToFloat64(c.evictions))
+}
+
+func TestLRUExpiresEntries(t *testing.T) {
+	now := time.Now()
+	c := NewLRU[int, string]("test", 10, time.Minute)
+	c.now = func() time.Time { return now }
+	c.Add(1, "one", c.Generation())
+
+	now = now.Add(59 * time.Second)
+	_, ok := c.Get(1)
+	assert.True(t, ok)
+	now = now.Add(time.Second)
+	_, ok = c.Get(1)
+	assert.False(t, ok)
+	assert.Zero(t, c.Len())
+}
+
+func TestLRUSkipsValuesLoadedAcrossAnInvalidation(t *testing.T) {
+	c := NewLRU[int, string]("test", 10, time.Minute)
+	generation := c.Generation()
+	// A write removes the entry while the old value is being loaded
+	c.Remove(1)
+	c.Add(1, "stale", generation)
+	_, ok := c.Get(1)
+	assert.False(t, ok)
+
+	c.Add(1, "fresh", c.Generation())
+	c.Purge()
+	_, ok = c.Get(1)
+	assert.False(t, ok)
+}
+
+func TestLRUMetricsRegisterPerCache(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(NewLRU[int, string]("services", 1, time.Minute).Collectors()...)
+	registry.MustRegister(NewLRU[string, int]("users", 1, time.Minute).Collectors()...)
+}
@@ -0,0 +1,124 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/redis/go-redis/v9"
+)
+
+// Shared is a cache kept in Redis, so that the instances of a deployment
+// share its entries and a restarted instance starts warm. Entries belong to a
+// scope, such as the listings or one service, and their keys are prefixed
+// with the generation of the scope stored in Redis: Invalidate bumps it,
+// which drops the entries of the scope at once and leaves them to expire.
+// Values are stored as JSON.
+//
+// Redis errors count as misses, so the cache fails open to the database.
+type Shared struct {
+	client redis.Cmdable
+	prefix string
+	ttl    time.Duration
+
+	hits   prometheus.Counter
+	misses prometheus.Counter
+	errors prometheus.Counter
+}
+
+// NewShared creates a cache storing entries under prefix for ttl. name
+// labels its metrics like those of LRU.
+func NewShared(client redis.Cmdable, name, prefix string, ttl time.Duration) *Shared {
+	labels := prometheus.Labels{"cache": name}
+	return &Shared{
+		client: client,
+		prefix: prefix,
+		ttl:    ttl,
+		hits: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "cache_hits_total", Help: "Lookups answered from the cache.", ConstLabels: labels,
+		}),
+		misses: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "cache_misses_total", Help: "Lookups not found in the cache or expired.", ConstLabels: labels,
+		}),
+		errors: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "cache_errors_total", Help: "Cache operations that failed, e.g. because Redis was unreachable.", ConstLabels: labels,
+		}),
+	}
+}
+
+// Generation returns the current generation of scope. Take it before reading
+// the source of a value and pass it to Get and Set, so that a value read
+// across an invalidation is stored under a generation no one reads anymore.
+func (c *Shared) Generation(ctx context.Context, scope string) (int64, error) {
+	generation, err := c.client.Get(ctx, c.generationKey(scope)).Int64()
+	if errors.Is(err, redis.Nil) {
+		return 0, nil
+	}
+	if err != nil {
+		c.errors.Inc()
+	}
+	return generation, err
+}
+
+// Get decodes the entry of key into dest and reports whether it was found
+func (c *Shared) Get(ctx context.Context, generation int64, key string, dest any) bool {
+	payload, err := c.client.Get(ctx, c.key(generation, key)).Bytes()
+	if err == nil {
+		err = json.Unmarshal(payload, dest)
+	}
+	switch {
+	case err == nil:
+		c.hits.Inc()
+		return true
+	case !errors.Is(err, redis.Nil):
+		c.errors.Inc()
+	}
+	c.misses.Inc()
+	return false
+}
+
+// Set stores value as the entry of key
+func (c *Shared) Set(ctx context.Context, generation int64, key string, value any) {
+	payload, err := json.Marshal(value)
+	if err == nil {
+		err = c.client.Set(ctx, c.key(generation, key), payload, c.ttl).Err()
+	}
+	if err != nil {
+		c.errors.Inc()
+	}
+}
+
+// Invalidate drops the entries of scopes by moving each to its next
+// generation. Generations expire once the entries of every older generation
+// have, so that scopes that stop changing don't leave keys behind; the
+// generation then starts over from 0.
+func (c *Shared) Invalidate(ctx context.Context, scopes ...string) error {
+	pipe := c.client.TxPipeline()
+	for _, scope := range scopes {
+		pipe.Incr(ctx, c.generationKey(scope))
+		// Twice the TTL covers values read before a bump and stored after it
+		pipe.Expire(ctx, c.generationKey(scope), 2*c.ttl)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		c.errors.Inc()
+		return err
+	}
+	return nil
+}
+
+// Collectors returns the cache metrics for registration with metrics.Registry
+func (c *Shared) Collectors() []prometheus.Collector {
+	return []prometheus.Collector{c.hits, c.misses, c.errors}
+}
+
+// generationKey does not start with a digit, unlike the keys of entries
+func (c *Shared) generationKey(scope string) string {
+	return c.prefix + scope + ":generation"
+}
+
+func (c *Shared) key(generation int64, key string) string {
+	return c.prefix + strconv.FormatInt(generation, 10) + ":" + key
+}
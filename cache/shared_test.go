@@ -0,0 +1,69 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type entryValue struct {
+	Name string `json:"name"`
+}
+
+func TestSharedInvalidatesScopes(t *testing.T) {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { client.Close() })
+	ctx := context.Background()
+	c := NewShared(client, "test", "catalog:", time.Minute)
+
+	generation, err := c.Generation(ctx, "service:1")
+	require.NoError(t, err)
+	var value entryValue
+	assert.False(t, c.Get(ctx, generation, "service:1", &value))
+	c.Set(ctx, generation, "service:1", entryValue{Name: "Billing"})
+	require.True(t, c.Get(ctx, generation, "service:1", &value))
+	assert.Equal(t, "Billing", value.Name)
+	assert.Equal(t, time.Minute, server.TTL("catalog:0:service:1"))
+	c.Set(ctx, 0, "service:2", entryValue{Name: "Payments"})
+
+	// Entries written under an older generation are never read again, while
+	// other scopes keep theirs
+	require.NoError(t, c.Invalidate(ctx, "service:1"))
+	next, err := c.Generation(ctx, "service:1")
+	require.NoError(t, err)
+	assert.Equal(t, generation+1, next)
+	assert.False(t, c.Get(ctx, next, "service:1", &value))
+	other, err := c.Generation(ctx, "service:2")
+	require.NoError(t, err)
+	assert.True(t, c.Get(ctx, other, "service:2", &value))
+
+	// Generations outlive the entries written under them
+	assert.Equal(t, 2*time.Minute, server.TTL("catalog:service:1:generation"))
+
+	assert.Equal(t, 2.0, testutil.ToFloat64(c.hits))
+	assert.Equal(t, 2.0, testutil.ToFloat64(c.misses))
+}
+
+func TestSharedFailsOpen(t *testing.T) {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr(), MaxRetries: -1, DialerRetries: 1})
+	t.Cleanup(func() { client.Close() })
+	ctx := context.Background()
+	c := NewShared(client, "test", "catalog:", time.Minute)
+	server.Close()
+
+	_, err := c.Generation(ctx, "service:1")
+	assert.Error(t, err)
+	var value entryValue
+	assert.False(t, c.Get(ctx, 0, "service:1", &value))
+	c.Set(ctx, 0, "service:1", entryValue{Name: "Billing"})
+	assert.Error(t, c.Invalidate(ctx, "service:1"))
+	assert.Equal(t, 4.0, testutil.ToFloat64(c.errors))
+}
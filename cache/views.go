@@ -0,0 +1,100 @@
+package cache
+
+import (
+	"context"
+	"log/slog"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"com.kong.connect/logging"
+)
+
+// maxTrackedViews bounds the tally to the services viewed most
+const maxTrackedViews = 10000
+
+// Views counts how often services are viewed and adds the counts to a tally
+// in Redis, a sorted set shared by the instances of a deployment, so that an
+// instance starting after a deploy knows which services to warm its cache
+// with. Views are counted in memory and flushed periodically, which keeps
+// Redis off the path of every read; counts not yet flushed when an instance
+// crashes are lost.
+type Views struct {
+	client redis.Cmdable
+	key    string
+
+	mu     sync.Mutex
+	counts map[int]int64
+}
+
+// NewViews creates a counter keeping its tally under key
+func NewViews(client redis.Cmdable, key string) *Views {
+	return &Views{client: client, key: key, counts: make(map[int]int64)}
+}
+
+// Record counts a view of service id
+func (v *Views) Record(id int) {
+	v.mu.Lock()
+	v.counts[id]++
+	v.mu.Unlock()
+}
+
+// Flush adds the views counted since the previous flush to the tally
+func (v *Views) Flush(ctx context.Context) error {
+	v.mu.Lock()
+	counts := v.counts
+	v.counts = make(map[int]int64)
+	v.mu.Unlock()
+	if len(counts) == 0 {
+		return nil
+	}
+
+	pipe := v.client.Pipeline()
+	for id, count := range counts {
+		pipe.ZIncrBy(ctx, v.key, float64(count), strconv.Itoa(id))
+	}
+	pipe.ZRemRangeByRank(ctx, v.key, 0, -maxTrackedViews-1)
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// Top returns the ids of the n services viewed most, most viewed first
+func (v *Views) Top(ctx context.Context, n int) ([]int, error) {
+	members, err := v.client.ZRevRange(ctx, v.key, 0, int64(n)-1).Result()
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]int, 0, len(members))
+	for _, member := range members {
+		// Members are written by Flush only, skip anything else
+		if id, err := strconv.Atoi(member); err == nil {
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
+
+// Run flushes the counts every interval until ctx is done, and once more
+// then
+func (v *Views) Run(ctx context.Context, interval time.Duration, logger *slog.Logger) {
+	logger = logging.Component(logger, "cache")
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if err := v.Flush(ctx); err != nil {
+				logger.Warn("failed to flush service views", "error", err)
+			}
+		case <-ctx.Done():
+			flushCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 2*time.Second)
+			defer cancel()
+			if err := v.Flush(flushCtx); err != nil {
+				logger.Warn("failed to flush service views", "error", err)
+			}
+			return
+		}
+	}
+}
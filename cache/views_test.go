@@ -0,0 +1,62 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestViewsTallyAcrossInstances(t *testing.T) {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { client.Close() })
+	ctx := context.Background()
+
+	first, second := NewViews(client, "views"), NewViews(client, "views")
+	for _, id := range []int{1, 2, 2, 3, 3, 3} {
+		first.Record(id)
+	}
+	second.Record(1)
+	second.Record(1)
+	second.Record(1)
+
+	// Nothing reaches Redis before a flush
+	top, err := first.Top(ctx, 10)
+	require.NoError(t, err)
+	assert.Empty(t, top)
+
+	require.NoError(t, first.Flush(ctx))
+	require.NoError(t, second.Flush(ctx))
+	require.NoError(t, second.Flush(ctx))
+
+	top, err = first.Top(ctx, 2)
+	require.NoError(t, err)
+	assert.Equal(t, []int{1, 3}, top)
+}
+
+func TestViewsFlushWhenStopped(t *testing.T) {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { client.Close() })
+
+	views := NewViews(client, "views")
+	views.Record(7)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		views.Run(ctx, time.Hour, nil)
+	}()
+	cancel()
+	<-done
+
+	top, err := views.Top(context.Background(), 1)
+	require.NoError(t, err)
+	assert.Equal(t, []int{7}, top)
+}
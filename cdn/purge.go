@@ -0,0 +1,87 @@
+// Package cdn purges catalog responses cached by CDNs and proxies. Cacheable
+// responses carry surrogate keys naming what they contain, and every change
+// of the catalog purges the keys it affects.
+package cdn
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"com.kong.connect/domain"
+	"com.kong.connect/logging"
+)
+
+// ListingKey tags responses listing or aggregating services: listings,
+// search results and statistics
+const ListingKey = "services"
+
+// purgeTimeout bounds a purge request
+const purgeTimeout = 5 * time.Second
+
+// ServiceKey tags responses about the service with id
+func ServiceKey(id string) string {
+	return "service-" + id
+}
+
+// Purger asks a purge endpoint to drop the cached responses of every change
+// published to it. It posts {"surrogate_keys": [...]} to the endpoint, with
+// the token as bearer token when set.
+type Purger struct {
+	url    string
+	token  string
+	client *http.Client
+	logger *slog.Logger
+}
+
+// NewPurger creates a purger posting to url; a nil client uses http.DefaultClient
+func NewPurger(url, token string, client *http.Client, logger *slog.Logger) *Purger {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &Purger{url: url, token: token, client: client, logger: logging.Component(logger, "cdn")}
+}
+
+// Publish purges the listings and the service of event in the background.
+// A failed purge is logged; the responses then expire with their max-age.
+func (p *Purger) Publish(event domain.ChangeEvent) {
+	keys := []string{ListingKey, ServiceKey(strconv.Itoa(event.ServiceID))}
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), purgeTimeout)
+		defer cancel()
+		if err := p.Purge(ctx, keys); err != nil {
+			p.logger.Warn("failed to purge cached responses", "keys", keys, "error", err)
+		}
+	}()
+}
+
+// Purge drops the cached responses tagged with any of keys
+func (p *Purger) Purge(ctx context.Context, keys []string) error {
+	body, err := json.Marshal(map[string][]string{"surrogate_keys": keys})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if p.token != "" {
+		req.Header.Set("Authorization", "Bearer "+p.token)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("purge endpoint answered %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+package cdn
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"com.kong.connect/domain"
+)
+
+func TestPurgerPostsSurrogateKeys(t *testing.T) {
+	purged := make(chan []string, 1)
+	endpoint := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer purge-token", r.Header.Get("Authorization"))
+		var body struct {
+			SurrogateKeys []string `json:"surrogate_keys"`
+		}
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		purged <- body.SurrogateKeys
+	}))
+	defer endpoint.Close()
+
+	NewPurger(endpoint.URL, "purge-token", nil, nil).Publish(domain.ChangeEvent{Type: domain.EventServiceUpdated, ServiceID: 4})
+	select {
+	case keys := <-purged:
+		assert.Equal(t, []string{"services", "service-4"}, keys)
+	case <-time.After(5 * time.Second):
+		t.Fatal("no purge request")
+	}
+}
+
+func TestPurgeReportsRejectedRequests(t *testing.T) {
+	endpoint := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer endpoint.Close()
+
+	err := NewPurger(endpoint.URL, "", nil, nil).Purge(context.Background(), []string{ListingKey})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "401")
+}
@@ -0,0 +1,100 @@
+package clientip
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+)
+
+// Headers through which proxies forward the address of the client
+const (
+	ForwardedForHeader = "X-Forwarded-For"
+	RealIPHeader       = "X-Real-IP"
+)
+
+type contextKey struct{}
+
+// NewContext returns a copy of ctx carrying the client IP
+func NewContext(ctx context.Context, ip string) context.Context {
+	return context.WithValue(ctx, contextKey{}, ip)
+}
+
+// FromRequest returns the client IP resolved for r by Resolve, or the IP of
+// the peer when none was
+func FromRequest(r *http.Request) string {
+	if ip, ok := r.Context().Value(contextKey{}).(string); ok && ip != "" {
+		return ip
+	}
+	return peer(r)
+}
+
+// ParseTrustedProxies parses CIDRs, such as 10.0.0.0/8, and single addresses
+func ParseTrustedProxies(list []string) ([]netip.Prefix, error) {
+	proxies := make([]netip.Prefix, 0, len(list))
+	for _, item := range list {
+		if !strings.Contains(item, "/") {
+			addr, err := netip.ParseAddr(item)
+			if err != nil {
+				return nil, fmt.Errorf("%q is neither a CIDR nor an IP address", item)
+			}
+			proxies = append(proxies, netip.PrefixFrom(addr.Unmap(), addr.Unmap().BitLen()))
+			continue
+		}
+		prefix, err := netip.ParsePrefix(item)
+		if err != nil {
+			return nil, fmt.Errorf("%q is neither a CIDR nor an IP address", item)
+		}
+		proxies = append(proxies, prefix.Masked())
+	}
+	return proxies, nil
+}
+
+// Resolve returns the IP of the client that sent r. The forwarding headers
+// are only believed when the peer is a trusted proxy, since anybody else can
+// set them: X-Forwarded-For is then read from the right, skipping the trusted
+// proxies the request went through, and X-Real-IP is used without it.
+func Resolve(r *http.Request, trusted []netip.Prefix) string {
+	client, err := netip.ParseAddr(peer(r))
+	if err != nil || !isTrusted(client, trusted) {
+		return peer(r)
+	}
+
+	if forwarded := r.Header.Values(ForwardedForHeader); len(forwarded) > 0 {
+		hops := strings.Split(strings.Join(forwarded, ","), ",")
+		for i := len(hops) - 1; i >= 0; i-- {
+			hop, err := netip.ParseAddr(strings.TrimSpace(hops[i]))
+			if err != nil {
+				break
+			}
+			client = hop
+			if !isTrusted(hop, trusted) {
+				break
+			}
+		}
+	} else if realIP, err := netip.ParseAddr(strings.TrimSpace(r.Header.Get(RealIPHeader))); err == nil {
+		client = realIP
+	}
+	return client.Unmap().String()
+}
+
+func isTrusted(addr netip.Addr, trusted []netip.Prefix) bool {
+	addr = addr.Unmap()
+	for _, prefix := range trusted {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// peer returns the IP of the peer that sent r
+func peer(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
@@ -0,0 +1,77 @@
+package clientip
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolve(t *testing.T) {
+	trusted, err := ParseTrustedProxies([]string{"10.0.0.0/8", "192.168.1.1", "::1"})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name         string
+		remoteAddr   string
+		forwardedFor []string
+		realIP       string
+		want         string
+	}{
+		{"direct client", "203.0.113.7:5123", nil, "", "203.0.113.7"},
+		{"untrusted peer cannot spoof", "203.0.113.7:5123", []string{"198.51.100.1"}, "198.51.100.2", "203.0.113.7"},
+		{"trusted proxy", "10.1.2.3:443", []string{"198.51.100.1"}, "", "198.51.100.1"},
+		{"chain of trusted proxies", "10.1.2.3:443", []string{"198.51.100.1, 192.168.1.1", "10.9.9.9"}, "", "198.51.100.1"},
+		{"spoofed left of the client", "10.1.2.3:443", []string{"1.2.3.4, 198.51.100.1"}, "", "198.51.100.1"},
+		{"only proxies", "10.1.2.3:443", []string{"10.0.0.1"}, "", "10.0.0.1"},
+		{"garbage stops the walk", "10.1.2.3:443", []string{"198.51.100.1, unknown, 10.0.0.1"}, "", "10.0.0.1"},
+		{"real IP without forwarded for", "10.1.2.3:443", nil, "198.51.100.2", "198.51.100.2"},
+		{"forwarded for wins over real IP", "10.1.2.3:443", []string{"198.51.100.1"}, "198.51.100.2", "198.51.100.1"},
+		{"trusted proxy without headers", "10.1.2.3:443", nil, "", "10.1.2.3"},
+		{"IPv6 proxy", "[::1]:443", []string{"2001:db8::1"}, "", "2001:db8::1"},
+		{"IPv4 mapped client", "10.1.2.3:443", []string{"::ffff:198.51.100.1"}, "", "198.51.100.1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/", nil)
+			r.RemoteAddr = tt.remoteAddr
+			for _, value := range tt.forwardedFor {
+				r.Header.Add(ForwardedForHeader, value)
+			}
+			if tt.realIP != "" {
+				r.Header.Set(RealIPHeader, tt.realIP)
+			}
+			assert.Equal(t, tt.want, Resolve(r, trusted))
+		})
+	}
+}
+
+func TestResolveTrustsNobodyByDefault(t *testing.T) {
+	r := httptest.NewRequest("GET", "/", nil)
+	r.RemoteAddr = "10.1.2.3:443"
+	r.Header.Set(ForwardedForHeader, "198.51.100.1")
+	assert.Equal(t, "10.1.2.3", Resolve(r, nil))
+}
+
+func TestParseTrustedProxies(t *testing.T) {
+	proxies, err := ParseTrustedProxies([]string{"10.1.2.3/8", "fd00::/8", "127.0.0.1"})
+	require.NoError(t, err)
+	assert.Equal(t, "10.0.0.0/8", proxies[0].String())
+	assert.Equal(t, "fd00::/8", proxies[1].String())
+	assert.Equal(t, "127.0.0.1/32", proxies[2].String())
+
+	_, err = ParseTrustedProxies([]string{"10.0.0.0/33"})
+	assert.Error(t, err)
+	_, err = ParseTrustedProxies([]string{"proxy.internal"})
+	assert.Error(t, err)
+}
+
+func TestFromRequestFallsBackToThePeer(t *testing.T) {
+	r := httptest.NewRequest("GET", "/", nil)
+	r.RemoteAddr = "203.0.113.7:5123"
+	assert.Equal(t, "203.0.113.7", FromRequest(r))
+
+	r = r.WithContext(NewContext(r.Context(), "198.51.100.1"))
+	assert.Equal(t, "198.51.100.1", FromRequest(r))
+}
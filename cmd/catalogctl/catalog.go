@@ -0,0 +1,168 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+
+	"com.kong.connect/domain"
+)
+
+// catalog is the YAML document written by export and read by import
+type catalog struct {
+	Services []catalogService `yaml:"services"`
+}
+
+type catalogService struct {
+	Name        string   `yaml:"name"`
+	Description string   `yaml:"description"`
+	Status      string   `yaml:"status,omitempty"`
+	Owner       string   `yaml:"owner,omitempty"`
+	Tags        []string `yaml:"tags,omitempty"`
+	Versions    []string `yaml:"versions,omitempty"`
+}
+
+func (s catalogService) input() domain.ServiceInput {
+	return domain.ServiceInput{
+		Name:        s.Name,
+		Description: s.Description,
+		Status:      s.Status,
+		Owner:       s.Owner,
+		Tags:        s.Tags,
+	}
+}
+
+// newCatalog converts services as returned by the API
+func newCatalog(services []domain.ServiceWithVersions) catalog {
+	c := catalog{Services: make([]catalogService, 0, len(services))}
+	for _, service := range services {
+		entry := catalogService{
+			Name:        service.Name,
+			Description: service.Description,
+			Status:      service.Status,
+			Owner:       service.Owner,
+			Tags:        service.Tags,
+		}
+		for _, version := range service.Versions {
+			entry.Versions = append(entry.Versions, version.Version)
+		}
+		c.Services = append(c.Services, entry)
+	}
+	return c
+}
+
+func readCatalog(r io.Reader) (catalog, error) {
+	var c catalog
+	decoder := yaml.NewDecoder(r)
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&c); err != nil && !errors.Is(err, io.EOF) {
+		return catalog{}, fmt.Errorf("failed to parse catalog: %v", err)
+	}
+
+	seen := map[string]bool{}
+	for i, service := range c.Services {
+		if service.Name == "" {
+			return catalog{}, fmt.Errorf("service %d has no name", i+1)
+		}
+		if seen[service.Name] {
+			return catalog{}, fmt.Errorf("service %q is listed twice", service.Name)
+		}
+		seen[service.Name] = true
+	}
+	return c, nil
+}
+
+func writeCatalog(w io.Writer, c catalog) error {
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(c); err != nil {
+		return err
+	}
+	return encoder.Close()
+}
+
+// importResult counts the changes made by importCatalog
+type importResult struct {
+	Created, Updated, Unchanged, Versions int
+}
+
+// importCatalog creates the services of c that do not exist yet, replaces the
+// fields of those that do, matched by name, and adds missing versions.
+// Services and versions missing from c are left alone.
+func importCatalog(ctx context.Context, api *client, c catalog) (importResult, error) {
+	var result importResult
+	existing, err := api.listServices(ctx, "")
+	if err != nil {
+		return result, err
+	}
+	byName := make(map[string]domain.ServiceWithVersions, len(existing))
+	for _, service := range existing {
+		byName[service.Name] = service
+	}
+
+	for _, entry := range c.Services {
+		current, found := byName[entry.Name]
+		have := map[string]bool{}
+		var id int
+		if found {
+			for _, version := range current.Versions {
+				have[version.Version] = true
+			}
+			id = current.ID
+			if unchanged(current.Service, entry) {
+				result.Unchanged++
+			} else {
+				if _, err := api.updateService(ctx, id, entry.input()); err != nil {
+					return result, fmt.Errorf("failed to update %q: %w", entry.Name, err)
+				}
+				result.Updated++
+			}
+		} else {
+			created, err := api.createService(ctx, entry.input())
+			if err != nil {
+				return result, fmt.Errorf("failed to create %q: %w", entry.Name, err)
+			}
+			id = created.ID
+			result.Created++
+		}
+
+		for _, version := range entry.Versions {
+			if have[version] {
+				continue
+			}
+			err := api.createVersion(ctx, id, version)
+			var apiErr *apiError
+			if errors.As(err, &apiErr) && apiErr.Status == http.StatusConflict {
+				continue
+			}
+			if err != nil {
+				return result, fmt.Errorf("failed to add version %s to %q: %w", version, entry.Name, err)
+			}
+			result.Versions++
+		}
+	}
+	return result, nil
+}
+
+// unchanged reports whether importing entry would leave service as it is, so
+// that re-importing an export does not rewrite every service
+func unchanged(service domain.Service, entry catalogService) bool {
+	status := entry.Status
+	if status == "" {
+		status = domain.StatusActive
+	}
+	if service.Description != entry.Description || service.Status != status || service.Owner != entry.Owner {
+		return false
+	}
+	tags := append([]string(nil), entry.Tags...)
+	sort.Strings(tags)
+	current := append([]string(nil), service.Tags...)
+	sort.Strings(current)
+	return slices.Equal(tags, current)
+}
@@ -0,0 +1,232 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"com.kong.connect/domain"
+	"com.kong.connect/problem"
+)
+
+// listPageSize is the largest page the API serves
+const listPageSize = 100
+
+// client calls the catalog API with a bearer token, acting for the
+// organization org when it is not empty
+type client struct {
+	baseURL string
+	token   string
+	org     string
+	http    *http.Client
+}
+
+func newClient(baseURL, token, org string, timeout time.Duration) *client {
+	return &client{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		token:   token,
+		org:     org,
+		http:    &http.Client{Timeout: timeout},
+	}
+}
+
+// apiError is a non-2xx response, described by its problem details when present
+type apiError struct {
+	Status  int
+	Details problem.Details
+}
+
+func (e *apiError) Error() string {
+	msg := fmt.Sprintf("%d %s", e.Status, http.StatusText(e.Status))
+	if e.Details.Detail != "" {
+		msg += ": " + e.Details.Detail
+	}
+	for _, param := range e.Details.InvalidParams {
+		msg += fmt.Sprintf("; %s: %s", param.Name, param.Reason)
+	}
+	if e.Details.RequestID != "" {
+		msg += " (request " + e.Details.RequestID + ")"
+	}
+	return msg
+}
+
+// do sends body as JSON and decodes a JSON response into out when out is not nil
+func (c *client) do(ctx context.Context, method, path string, body, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(payload)
+	}
+
+	req, err := c.newRequest(ctx, method, path, reader)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		apiErr := &apiError{Status: resp.StatusCode}
+		_ = json.NewDecoder(resp.Body).Decode(&apiErr.Details)
+		return apiErr
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode %s %s response: %v", method, path, err)
+	}
+	return nil
+}
+
+// newRequest returns a request of the API accepting JSON, authenticated
+// with the token of the client and acting for its organization
+func (c *client) newRequest(ctx context.Context, method, path string, body io.Reader) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+	if c.org != "" {
+		req.Header.Set("X-Org", c.org)
+	}
+	return req, nil
+}
+
+// listServices pages through all services matching search, ordered by name
+func (c *client) listServices(ctx context.Context, search string) ([]domain.ServiceWithVersions, error) {
+	services := []domain.ServiceWithVersions{}
+	for page := 1; ; page++ {
+		query := url.Values{
+			"sort_by":   {"name"},
+			"sort_dir":  {"asc"},
+			"page":      {strconv.Itoa(page)},
+			"page_size": {strconv.Itoa(listPageSize)},
+		}
+		if search != "" {
+			query.Set("search", search)
+		}
+
+		var resp domain.ServiceListResponse
+		if err := c.do(ctx, http.MethodGet, "/api/v1/services?"+query.Encode(), nil, &resp); err != nil {
+			return nil, err
+		}
+		services = append(services, resp.Services...)
+		if page >= resp.TotalPages || len(resp.Services) == 0 {
+			return services, nil
+		}
+	}
+}
+
+func (c *client) getService(ctx context.Context, id int) (*domain.ServiceWithVersions, error) {
+	var service domain.ServiceWithVersions
+	if err := c.do(ctx, http.MethodGet, "/api/v1/services/"+strconv.Itoa(id), nil, &service); err != nil {
+		return nil, err
+	}
+	return &service, nil
+}
+
+func (c *client) createService(ctx context.Context, input domain.ServiceInput) (*domain.Service, error) {
+	var service domain.Service
+	if err := c.do(ctx, http.MethodPost, "/api/v1/services", input, &service); err != nil {
+		return nil, err
+	}
+	return &service, nil
+}
+
+func (c *client) updateService(ctx context.Context, id int, input domain.ServiceInput) (*domain.Service, error) {
+	var service domain.Service
+	if err := c.do(ctx, http.MethodPut, "/api/v1/services/"+strconv.Itoa(id), input, &service); err != nil {
+		return nil, err
+	}
+	return &service, nil
+}
+
+func (c *client) deleteService(ctx context.Context, id int) error {
+	return c.do(ctx, http.MethodDelete, "/api/v1/services/"+strconv.Itoa(id), nil, nil)
+}
+
+func (c *client) createVersion(ctx context.Context, serviceID int, version string) error {
+	path := "/api/v1/services/" + strconv.Itoa(serviceID) + "/versions"
+	return c.do(ctx, http.MethodPost, path, domain.VersionInput{Version: version}, nil)
+}
+
+func (c *client) createUser(ctx context.Context, input domain.UserInput) (*domain.User, error) {
+	var user domain.User
+	if err := c.do(ctx, http.MethodPost, "/api/v1/users", input, &user); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+func (c *client) disableUser(ctx context.Context, username string) (*domain.User, error) {
+	var user domain.User
+	if err := c.do(ctx, http.MethodPost, "/api/v1/users/"+url.PathEscape(username)+"/disable", nil, &user); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+func (c *client) listUsers(ctx context.Context) ([]domain.User, error) {
+	var resp domain.UserListResponse
+	if err := c.do(ctx, http.MethodGet, "/api/v1/users", nil, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Users, nil
+}
+
+func (c *client) issueToken(ctx context.Context, input domain.TokenInput) (*domain.IssuedToken, error) {
+	var token domain.IssuedToken
+	if err := c.do(ctx, http.MethodPost, "/api/v1/tokens", input, &token); err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+func (c *client) revokeToken(ctx context.Context, id int) error {
+	return c.do(ctx, http.MethodDelete, "/api/v1/tokens/"+strconv.Itoa(id), nil, nil)
+}
+
+func (c *client) listTokens(ctx context.Context) ([]domain.APIToken, error) {
+	var resp domain.TokenListResponse
+	if err := c.do(ctx, http.MethodGet, "/api/v1/tokens", nil, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Tokens, nil
+}
+
+func (c *client) createOrganization(ctx context.Context, input domain.OrganizationInput) (*domain.Organization, error) {
+	var organization domain.Organization
+	if err := c.do(ctx, http.MethodPost, "/api/v1/organizations", input, &organization); err != nil {
+		return nil, err
+	}
+	return &organization, nil
+}
+
+func (c *client) listOrganizations(ctx context.Context) ([]domain.Organization, error) {
+	var resp domain.OrganizationListResponse
+	if err := c.do(ctx, http.MethodGet, "/api/v1/organizations", nil, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Organizations, nil
+}
@@ -0,0 +1,405 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"com.kong.connect/domain"
+	"com.kong.connect/middleware"
+)
+
+// tokenBytes is the entropy of minted tokens
+const tokenBytes = 32
+
+func runList(ctx context.Context, c *cli, args []string) error {
+	flags := flag.NewFlagSet("list", flag.ContinueOnError)
+	search := flags.String("search", "", "only services whose name or description contains `text`")
+	output := flags.String("o", "table", "output `format`: table, json or yaml")
+	if err := c.parseFlags(flags, args, 0, 0); err != nil {
+		return err
+	}
+
+	services, err := c.client().listServices(ctx, *search)
+	if err != nil {
+		return err
+	}
+
+	switch *output {
+	case "table":
+		tw := tabwriter.NewWriter(c.stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(tw, "ID\tNAME\tSTATUS\tOWNER\tVERSIONS\tTAGS")
+		for _, s := range services {
+			fmt.Fprintf(tw, "%d\t%s\t%s\t%s\t%d\t%s\n", s.ID, s.Name, s.Status, s.Owner, len(s.Versions), strings.Join(s.Tags, ","))
+		}
+		return tw.Flush()
+	case "yaml":
+		return writeCatalog(c.stdout, newCatalog(services))
+	default:
+		return writeOutput(c.stdout, *output, services)
+	}
+}
+
+func runGet(ctx context.Context, c *cli, args []string) error {
+	flags := flag.NewFlagSet("get", flag.ContinueOnError)
+	output := flags.String("o", "yaml", "output `format`: json or yaml")
+	if err := c.parseFlags(flags, args, 1, 1); err != nil {
+		return err
+	}
+	id, err := parseID(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	service, err := c.client().getService(ctx, id)
+	if err != nil {
+		return err
+	}
+	if *output == "yaml" {
+		return writeCatalog(c.stdout, newCatalog([]domain.ServiceWithVersions{*service}))
+	}
+	return writeOutput(c.stdout, *output, service)
+}
+
+func runCreate(ctx context.Context, c *cli, args []string) error {
+	flags := flag.NewFlagSet("create", flag.ContinueOnError)
+	var entry catalogService
+	flags.StringVar(&entry.Name, "name", "", "service name (required)")
+	flags.StringVar(&entry.Description, "description", "", "service description (required)")
+	flags.StringVar(&entry.Status, "status", "", "active, deprecated or archived (default active)")
+	flags.StringVar(&entry.Owner, "owner", "", "owning team")
+	tags := flags.String("tags", "", "comma separated tags")
+	versions := flags.String("versions", "", "comma separated versions to add")
+	if err := c.parseFlags(flags, args, 0, 0); err != nil {
+		return err
+	}
+	if entry.Name == "" || entry.Description == "" {
+		fmt.Fprintln(c.stderr, "-name and -description are required")
+		flags.Usage()
+		return errUsage
+	}
+	entry.Tags = splitList(*tags)
+
+	api := c.client()
+	created, err := api.createService(ctx, entry.input())
+	if err != nil {
+		return err
+	}
+	for _, version := range splitList(*versions) {
+		if err := api.createVersion(ctx, created.ID, version); err != nil {
+			return fmt.Errorf("service %d created, but adding version %s failed: %v", created.ID, version, err)
+		}
+	}
+	fmt.Fprintf(c.stdout, "created service %d %q\n", created.ID, created.Name)
+	return nil
+}
+
+func runDelete(ctx context.Context, c *cli, args []string) error {
+	flags := flag.NewFlagSet("delete", flag.ContinueOnError)
+	if err := c.parseFlags(flags, args, 1, -1); err != nil {
+		return err
+	}
+
+	ids := make([]int, 0, flags.NArg())
+	for _, arg := range flags.Args() {
+		id, err := parseID(arg)
+		if err != nil {
+			return err
+		}
+		ids = append(ids, id)
+	}
+
+	api := c.client()
+	for _, id := range ids {
+		if err := api.deleteService(ctx, id); err != nil {
+			return fmt.Errorf("failed to delete service %d: %v", id, err)
+		}
+		fmt.Fprintf(c.stdout, "deleted service %d\n", id)
+	}
+	return nil
+}
+
+func runExport(ctx context.Context, c *cli, args []string) error {
+	flags := flag.NewFlagSet("export", flag.ContinueOnError)
+	file := flags.String("f", "", "write to `file` instead of stdout")
+	if err := c.parseFlags(flags, args, 0, 0); err != nil {
+		return err
+	}
+
+	services, err := c.client().listServices(ctx, "")
+	if err != nil {
+		return err
+	}
+
+	var w io.Writer = c.stdout
+	if *file != "" {
+		f, err := os.Create(*file)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+	if err := writeCatalog(w, newCatalog(services)); err != nil {
+		return err
+	}
+	if *file != "" {
+		fmt.Fprintf(c.stdout, "exported %d services to %s\n", len(services), *file)
+	}
+	return nil
+}
+
+func runImport(ctx context.Context, c *cli, args []string) error {
+	flags := flag.NewFlagSet("import", flag.ContinueOnError)
+	file := flags.String("f", "", "YAML catalog `file` to import, - for stdin (required)")
+	if err := c.parseFlags(flags, args, 0, 0); err != nil {
+		return err
+	}
+	if *file == "" {
+		fmt.Fprintln(c.stderr, "-f is required")
+		flags.Usage()
+		return errUsage
+	}
+
+	var r io.Reader = c.stdin
+	if *file != "-" {
+		f, err := os.Open(*file)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		r = f
+	}
+	cat, err := readCatalog(r)
+	if err != nil {
+		return err
+	}
+
+	result, err := importCatalog(ctx, c.client(), cat)
+	fmt.Fprintf(c.stdout, "services: %d created, %d updated, %d unchanged; versions: %d added\n", result.Created, result.Updated, result.Unchanged, result.Versions)
+	return err
+}
+
+// runUsers creates, disables and lists the users API tokens are issued to
+func runUsers(ctx context.Context, c *cli, args []string) error {
+	flags := flag.NewFlagSet("users", flag.ContinueOnError)
+	roles := flags.String("roles", "viewer", "comma separated `roles` of a created user: admin, viewer")
+	output := flags.String("o", "table", "output `format` of list: table, json or yaml")
+	action, args := subcommand(args)
+	if err := c.parseFlags(flags, args, 0, 1); err != nil {
+		return err
+	}
+
+	switch action {
+	case "create", "disable":
+		if flags.NArg() != 1 {
+			flags.Usage()
+			return errUsage
+		}
+		if action == "disable" {
+			user, err := c.client().disableUser(ctx, flags.Arg(0))
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(c.stdout, "disabled user %s\n", user.Username)
+			return nil
+		}
+		user, err := c.client().createUser(ctx, domain.UserInput{Username: flags.Arg(0), Roles: splitList(*roles)})
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(c.stdout, "created user %s (%s)\n", user.Username, strings.Join(user.Roles, ","))
+		return nil
+	case "list":
+		users, err := c.client().listUsers(ctx)
+		if err != nil {
+			return err
+		}
+		if *output != "table" {
+			return writeOutput(c.stdout, *output, users)
+		}
+		tw := tabwriter.NewWriter(c.stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(tw, "ID\tUSERNAME\tROLES\tDISABLED\tCREATED")
+		for _, u := range users {
+			fmt.Fprintf(tw, "%d\t%s\t%s\t%t\t%s\n", u.ID, u.Username, strings.Join(u.Roles, ","), u.Disabled, u.CreatedAt.Format(time.RFC3339))
+		}
+		return tw.Flush()
+	default:
+		flags.Usage()
+		return errUsage
+	}
+}
+
+// runOrganizations creates and lists the organizations of the deployment
+func runOrganizations(ctx context.Context, c *cli, args []string) error {
+	flags := flag.NewFlagSet("organizations", flag.ContinueOnError)
+	name := flags.String("name", "", "display `name` of a created organization; defaults to its slug")
+	output := flags.String("o", "table", "output `format` of list: table, json or yaml")
+	action, args := subcommand(args)
+	if err := c.parseFlags(flags, args, 0, 1); err != nil {
+		return err
+	}
+
+	switch action {
+	case "create":
+		if flags.NArg() != 1 {
+			flags.Usage()
+			return errUsage
+		}
+		organization, err := c.client().createOrganization(ctx, domain.OrganizationInput{Slug: flags.Arg(0), Name: *name})
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(c.stdout, "created organization %s (%s)\n", organization.Slug, organization.Name)
+		return nil
+	case "list":
+		organizations, err := c.client().listOrganizations(ctx)
+		if err != nil {
+			return err
+		}
+		if *output != "table" {
+			return writeOutput(c.stdout, *output, organizations)
+		}
+		tw := tabwriter.NewWriter(c.stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(tw, "ID\tSLUG\tNAME\tCREATED")
+		for _, o := range organizations {
+			fmt.Fprintf(tw, "%d\t%s\t%s\t%s\n", o.ID, o.Slug, o.Name, o.CreatedAt.Format(time.RFC3339))
+		}
+		return tw.Flush()
+	default:
+		flags.Usage()
+		return errUsage
+	}
+}
+
+// runTokens issues, revokes and lists API tokens. An issued token is printed
+// once; the server keeps only its hash.
+func runTokens(ctx context.Context, c *cli, args []string) error {
+	flags := flag.NewFlagSet("tokens", flag.ContinueOnError)
+	user := flags.String("user", "", "`username` to issue the token to")
+	name := flags.String("name", "", "what the issued token is for, e.g. ci")
+	output := flags.String("o", "table", "output `format` of list: table, json or yaml")
+	action, args := subcommand(args)
+	if err := c.parseFlags(flags, args, 0, 1); err != nil {
+		return err
+	}
+
+	switch action {
+	case "issue":
+		if *user == "" || flags.NArg() != 0 {
+			fmt.Fprintln(c.stderr, "-user is required")
+			flags.Usage()
+			return errUsage
+		}
+		token, err := c.client().issueToken(ctx, domain.TokenInput{Username: *user, Name: *name})
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(c.stdout, token.Token)
+		fmt.Fprintf(c.stderr, "Issued token %d to %s. It is shown only once; store it now.\n", token.ID, token.Username)
+		return nil
+	case "revoke":
+		if flags.NArg() != 1 {
+			flags.Usage()
+			return errUsage
+		}
+		id, err := parseID(flags.Arg(0))
+		if err != nil {
+			return err
+		}
+		if err := c.client().revokeToken(ctx, id); err != nil {
+			return err
+		}
+		fmt.Fprintf(c.stdout, "revoked token %d\n", id)
+		return nil
+	case "list":
+		tokens, err := c.client().listTokens(ctx)
+		if err != nil {
+			return err
+		}
+		if *output != "table" {
+			return writeOutput(c.stdout, *output, tokens)
+		}
+		tw := tabwriter.NewWriter(c.stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(tw, "ID\tUSER\tNAME\tCREATED\tREVOKED")
+		for _, t := range tokens {
+			revoked := "-"
+			if t.RevokedAt != nil {
+				revoked = t.RevokedAt.Format(time.RFC3339)
+			}
+			fmt.Fprintf(tw, "%d\t%s\t%s\t%s\t%s\n", t.ID, t.Username, t.Name, t.CreatedAt.Format(time.RFC3339), revoked)
+		}
+		return tw.Flush()
+	default:
+		flags.Usage()
+		return errUsage
+	}
+}
+
+// subcommand splits the action, such as create in "users create alice", from its arguments
+func subcommand(args []string) (string, []string) {
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		return "", args
+	}
+	return args[0], args[1:]
+}
+
+// runMintKey generates a random bearer token. Tokens are configured through
+// AUTH_TOKENS rather than stored by the API, so the entry is printed for the
+// operator to add there, or to the secret AUTH_TOKENS references.
+func runMintKey(ctx context.Context, c *cli, args []string) error {
+	flags := flag.NewFlagSet("mint-key", flag.ContinueOnError)
+	user := flags.String("user", "", "username the token authenticates as (required)")
+	roles := flags.String("roles", "viewer", "`roles` separated by |, e.g. admin or viewer")
+	if err := c.parseFlags(flags, args, 0, 0); err != nil {
+		return err
+	}
+	if *user == "" {
+		fmt.Fprintln(c.stderr, "-user is required")
+		flags.Usage()
+		return errUsage
+	}
+
+	raw := make([]byte, tokenBytes)
+	if _, err := rand.Read(raw); err != nil {
+		return err
+	}
+	token := base64.RawURLEncoding.EncodeToString(raw)
+	entry := token + "=" + *user + ":" + *roles
+	if _, err := middleware.ParseTokens(entry); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(c.stdout, "token: %s\nentry: %s\n", token, entry)
+	fmt.Fprintln(c.stderr, "Append the entry to AUTH_TOKENS, or the secret it references, and send SIGHUP to the server.")
+	return nil
+}
+
+func writeOutput(w io.Writer, format string, v interface{}) error {
+	switch format {
+	case "json":
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(v)
+	default:
+		return fmt.Errorf("unknown output format %q", format)
+	}
+}
+
+func parseID(arg string) (int, error) {
+	id, err := strconv.Atoi(arg)
+	if err != nil || id < 1 {
+		return 0, errors.New("service ID must be a positive integer, got " + strconv.Quote(arg))
+	}
+	return id, nil
+}
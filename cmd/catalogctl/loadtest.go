@@ -0,0 +1,345 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"com.kong.connect/domain"
+)
+
+// loadEndpoints are the endpoints the load test requests, in report order
+var loadEndpoints = []string{"list", "detail", "search"}
+
+// maxLoadRPS is the highest -rps, at which requests are due a nanosecond apart
+const maxLoadRPS = float64(time.Second)
+
+// loadPercentiles are the latency percentiles reported for every endpoint
+var loadPercentiles = []float64{50, 90, 95, 99}
+
+// loadTarget is what the load test requests: the services to get and the
+// terms to search for, taken from the catalog
+type loadTarget struct {
+	pageSize int
+	ids      []int
+	terms    []string
+}
+
+// path returns a random request of endpoint
+func (t *loadTarget) path(endpoint string) string {
+	switch endpoint {
+	case "detail":
+		return "/api/v1/services/" + strconv.Itoa(t.ids[rand.N(len(t.ids))])
+	case "search":
+		return "/api/v1/search?" + url.Values{"q": {t.terms[rand.N(len(t.terms))]}}.Encode()
+	default:
+		query := url.Values{"page": {"1"}, "page_size": {strconv.Itoa(t.pageSize)}}
+		if len(t.terms) > 0 && rand.N(4) == 0 {
+			query.Set("search", t.terms[rand.N(len(t.terms))])
+		}
+		return "/api/v1/services?" + query.Encode()
+	}
+}
+
+// loadResult is the outcome of the requests of an endpoint
+type loadResult struct {
+	Endpoint string `json:"endpoint"`
+	Requests int    `json:"requests"`
+	// Errors counts the responses by status, and failed requests as 0
+	Errors  map[int]int `json:"errors,omitempty"`
+	Dropped int         `json:"dropped"`
+	RPS     float64     `json:"rps"`
+	// LatencyMS holds the latency percentiles as p50, p90, p95 and p99, and
+	// the slowest request as max
+	LatencyMS map[string]float64 `json:"latency_ms"`
+
+	latencies []time.Duration
+}
+
+func (r *loadResult) failed() int {
+	failed := 0
+	for _, count := range r.Errors {
+		failed += count
+	}
+	return failed
+}
+
+// summarize fills in the rate and latencies of requests made over elapsed
+func (r *loadResult) summarize(elapsed time.Duration) {
+	r.RPS = float64(r.Requests) / elapsed.Seconds()
+	r.LatencyMS = map[string]float64{}
+	if len(r.latencies) == 0 {
+		return
+	}
+	slices.Sort(r.latencies)
+	for _, p := range loadPercentiles {
+		r.LatencyMS["p"+strconv.FormatFloat(p, 'f', -1, 64)] = milliseconds(percentile(r.latencies, p))
+	}
+	r.LatencyMS["max"] = milliseconds(r.latencies[len(r.latencies)-1])
+}
+
+// percentile returns the nearest-rank percentile p of sorted latencies
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	return sorted[max(rank, 1)-1]
+}
+
+func milliseconds(d time.Duration) float64 {
+	return math.Round(float64(d)/float64(time.Microsecond)) / 1000
+}
+
+// parseMix parses the shares of the endpoints, such as list=6,detail=3,search=1
+func parseMix(s string) (map[string]int, error) {
+	mix := map[string]int{}
+	total := 0
+	for _, entry := range splitList(s) {
+		endpoint, share, ok := strings.Cut(entry, "=")
+		weight, err := strconv.Atoi(strings.TrimSpace(share))
+		endpoint = strings.TrimSpace(endpoint)
+		if !ok || err != nil || weight < 0 {
+			return nil, fmt.Errorf("invalid share %q, expected endpoint=weight", entry)
+		}
+		if !slices.Contains(loadEndpoints, endpoint) {
+			return nil, fmt.Errorf("unknown endpoint %q, expected %s", endpoint, strings.Join(loadEndpoints, ", "))
+		}
+		mix[endpoint] = weight
+		total += weight
+	}
+	if total == 0 {
+		return nil, errors.New("the mix requests no endpoint")
+	}
+	return mix, nil
+}
+
+// pick returns a random endpoint of mix, by weight
+func pick(mix map[string]int) string {
+	total := 0
+	for _, weight := range mix {
+		total += weight
+	}
+	n := rand.N(total)
+	for _, endpoint := range loadEndpoints {
+		if n < mix[endpoint] {
+			return endpoint
+		}
+		n -= mix[endpoint]
+	}
+	return loadEndpoints[0]
+}
+
+func runLoadTest(ctx context.Context, c *cli, args []string) error {
+	flags := flag.NewFlagSet("loadtest", flag.ContinueOnError)
+	rps := flags.Float64("rps", 50, "requests per second, across endpoints")
+	duration := flags.Duration("duration", 30*time.Second, "how long to send requests")
+	mixFlag := flags.String("mix", "list=6,detail=3,search=1", "share of the requests of each endpoint: list, detail and search")
+	concurrency := flags.Int("concurrency", 64, "most requests in flight; requests due beyond it are dropped")
+	pageSize := flags.Int("page-size", 12, "page size of listings")
+	output := flags.String("o", "table", "output `format`: table or json")
+	if err := c.parseFlags(flags, args, 0, 0); err != nil {
+		return err
+	}
+	mix, err := parseMix(*mixFlag)
+	if err == nil && (!(*rps > 0 && *rps <= maxLoadRPS) || *duration <= 0 || *concurrency < 1 || *pageSize < 1 || *pageSize > listPageSize) {
+		err = fmt.Errorf("-rps must be positive and at most %.0f, -duration and -concurrency positive and -page-size between 1 and %d", maxLoadRPS, listPageSize)
+	}
+	if err != nil {
+		fmt.Fprintln(c.stderr, err)
+		flags.Usage()
+		return errUsage
+	}
+
+	api := c.client()
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.MaxIdleConnsPerHost = *concurrency
+	api.http.Transport = transport
+	target, err := discoverTarget(ctx, api, *pageSize)
+	if err != nil {
+		return err
+	}
+	if len(target.ids) == 0 && (mix["detail"] > 0 || mix["search"] > 0) {
+		return errors.New("the catalog has no services to get or search for")
+	}
+
+	results := map[string]*loadResult{}
+	for _, endpoint := range loadEndpoints {
+		results[endpoint] = &loadResult{Endpoint: endpoint, Errors: map[int]int{}}
+	}
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	inFlight := make(chan struct{}, *concurrency)
+
+	// Requests are sent on schedule whether or not earlier ones answered, so
+	// that a slow server shows as latency rather than as a lower rate
+	interval := time.Duration(float64(time.Second) / *rps)
+	start := time.Now()
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+schedule:
+	for due := start; due.Before(start.Add(*duration)); due = due.Add(interval) {
+		timer.Reset(time.Until(due))
+		select {
+		case <-ctx.Done():
+			break schedule
+		case <-timer.C:
+		}
+
+		endpoint := pick(mix)
+		select {
+		case inFlight <- struct{}{}:
+		default:
+			mu.Lock()
+			results[endpoint].Dropped++
+			mu.Unlock()
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-inFlight }()
+			sent := time.Now()
+			status, err := api.fetch(ctx, target.path(endpoint))
+			latency := time.Since(sent)
+			if ctx.Err() != nil {
+				return // Interrupted, not an answer of the server
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			result := results[endpoint]
+			result.Requests++
+			switch {
+			case err != nil:
+				result.Errors[0]++
+			case status >= http.StatusBadRequest:
+				result.Errors[status]++
+			default:
+				result.latencies = append(result.latencies, latency)
+			}
+		}()
+	}
+	wg.Wait()
+	elapsed := time.Since(start)
+
+	report := make([]*loadResult, 0, len(loadEndpoints)+1)
+	total := &loadResult{Endpoint: "total", Errors: map[int]int{}}
+	for _, endpoint := range loadEndpoints {
+		result := results[endpoint]
+		if mix[endpoint] == 0 {
+			continue
+		}
+		total.Requests += result.Requests
+		total.Dropped += result.Dropped
+		total.latencies = append(total.latencies, result.latencies...)
+		for status, count := range result.Errors {
+			total.Errors[status] += count
+		}
+		result.summarize(elapsed)
+		report = append(report, result)
+	}
+	total.summarize(elapsed)
+	report = append(report, total)
+
+	if err := writeLoadReport(c.stdout, *output, report); err != nil {
+		return err
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	var problems []string
+	if failed := total.failed(); failed > 0 {
+		problems = append(problems, fmt.Sprintf("%d of %d requests failed", failed, total.Requests))
+	}
+	if total.Dropped > 0 {
+		problems = append(problems, fmt.Sprintf("%d requests were dropped at the -concurrency limit", total.Dropped))
+	}
+	if len(problems) > 0 {
+		return errors.New(strings.Join(problems, ", "))
+	}
+	return nil
+}
+
+// discoverTarget takes the services to get and the terms to search for from
+// the first page of the catalog
+func discoverTarget(ctx context.Context, api *client, pageSize int) (*loadTarget, error) {
+	var resp domain.ServiceListResponse
+	path := "/api/v1/services?" + url.Values{"page_size": {strconv.Itoa(listPageSize)}}.Encode()
+	if err := api.do(ctx, http.MethodGet, path, nil, &resp); err != nil {
+		return nil, err
+	}
+	target := &loadTarget{pageSize: pageSize}
+	for _, service := range resp.Services {
+		target.ids = append(target.ids, service.ID)
+		if words := strings.Fields(service.Name); len(words) > 0 {
+			target.terms = append(target.terms, strings.ToLower(words[0]))
+		}
+	}
+	return target, nil
+}
+
+// fetch sends a GET request and returns the status of the response, read in
+// full as clients would
+func (c *client) fetch(ctx context.Context, path string) (int, error) {
+	req, err := c.newRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return 0, err
+	}
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	if _, err := io.Copy(io.Discard, resp.Body); err != nil {
+		return 0, err
+	}
+	return resp.StatusCode, nil
+}
+
+func writeLoadReport(w io.Writer, format string, report []*loadResult) error {
+	if format != "table" {
+		return writeOutput(w, format, report)
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "ENDPOINT\tREQUESTS\tERRORS\tDROPPED\tRPS\tP50\tP90\tP95\tP99\tMAX")
+	for _, result := range report {
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%d\t%.1f", result.Endpoint, result.Requests, result.failed(), result.Dropped, result.RPS)
+		for _, key := range []string{"p50", "p90", "p95", "p99", "max"} {
+			if latency, ok := result.LatencyMS[key]; ok {
+				fmt.Fprintf(tw, "\t%.1fms", latency)
+			} else {
+				fmt.Fprint(tw, "\t-")
+			}
+		}
+		fmt.Fprintln(tw)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	total := report[len(report)-1]
+	statuses := make([]int, 0, len(total.Errors))
+	for status := range total.Errors {
+		statuses = append(statuses, status)
+	}
+	slices.Sort(statuses)
+	for _, status := range statuses {
+		reason := "request failed"
+		if status > 0 {
+			reason = strconv.Itoa(status) + " " + http.StatusText(status)
+		}
+		fmt.Fprintf(w, "%d × %s\n", total.Errors[status], reason)
+	}
+	return nil
+}
@@ -0,0 +1,168 @@
+// Command catalogctl manages the service catalog through its HTTP API.
+//
+//	catalogctl [global flags] <command> [flags] [args]
+//
+// The server, token and organization default to $CATALOG_URL, $CATALOG_TOKEN
+// and $CATALOG_ORG.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"time"
+)
+
+// command is a subcommand of catalogctl
+type command struct {
+	usage   string
+	summary string
+	run     func(ctx context.Context, c *cli, args []string) error
+}
+
+var commands map[string]command
+
+func init() {
+	commands = map[string]command{
+		"list":          {"[-search text] [-o table|json|yaml]", "List services", runList},
+		"get":           {"[-o json|yaml] <id>", "Show a service with its versions", runGet},
+		"create":        {"-name name -description text [-status s] [-owner o] [-tags a,b] [-versions 1.0.0,...]", "Create a service", runCreate},
+		"delete":        {"<id>...", "Delete services", runDelete},
+		"export":        {"[-f file]", "Write the catalog as YAML", runExport},
+		"import":        {"-f file", "Create or update services from a YAML catalog, matched by name", runImport},
+		"mint-key":      {"-user name -roles role|role", "Generate an API token and its AUTH_TOKENS entry", runMintKey},
+		"users":         {"create [-roles admin,viewer] <username> | disable <username> | list [-o table|json|yaml]", "Create, disable and list users", runUsers},
+		"tokens":        {"issue -user name [-name label] | revoke <id> | list [-o table|json|yaml]", "Issue, revoke and list API tokens of users", runTokens},
+		"organizations": {"create [-name name] <slug> | list [-o table|json|yaml]", "Create and list organizations", runOrganizations},
+		"loadtest":      {"[-rps n] [-duration d] [-mix list=6,detail=3,search=1] [-concurrency n] [-page-size n] [-o table|json]", "Request listings, services and searches at a fixed rate and report their latency", runLoadTest},
+	}
+}
+
+// errUsage reports invalid flags or arguments, which were already printed
+var errUsage = errors.New("invalid usage")
+
+// cli carries the global flags and outputs to commands
+type cli struct {
+	server  string
+	token   string
+	org     string
+	timeout time.Duration
+	stdin   io.Reader
+	stdout  io.Writer
+	stderr  io.Writer
+}
+
+func (c *cli) client() *client {
+	return newClient(c.server, c.token, c.org, c.timeout)
+}
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	os.Exit(run(ctx, os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// run executes the command line and returns the exit code: 0 on success, 1
+// when the command fails and 2 on usage errors
+func run(ctx context.Context, args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	c := &cli{stdin: stdin, stdout: stdout, stderr: stderr}
+
+	global := flag.NewFlagSet("catalogctl", flag.ContinueOnError)
+	global.SetOutput(stderr)
+	global.StringVar(&c.server, "server", envOr("CATALOG_URL", "http://localhost:8080"), "catalog API `url`; defaults to $CATALOG_URL")
+	global.StringVar(&c.token, "token", os.Getenv("CATALOG_TOKEN"), "bearer `token`; defaults to $CATALOG_TOKEN")
+	global.StringVar(&c.org, "org", os.Getenv("CATALOG_ORG"), "organization `slug` to act for; defaults to $CATALOG_ORG")
+	global.DurationVar(&c.timeout, "timeout", 30*time.Second, "timeout of each API request")
+	global.Usage = func() { c.usage(global) }
+	if err := global.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 2
+	}
+
+	if global.NArg() == 0 {
+		c.usage(global)
+		return 2
+	}
+	name, args := global.Arg(0), global.Args()[1:]
+	cmd, ok := commands[name]
+	if !ok {
+		fmt.Fprintf(stderr, "unknown command %q\n\n", name)
+		c.usage(global)
+		return 2
+	}
+
+	if err := cmd.run(ctx, c, args); err != nil {
+		switch {
+		case errors.Is(err, flag.ErrHelp):
+			return 0
+		case errors.Is(err, errUsage):
+			return 2
+		}
+		fmt.Fprintf(stderr, "catalogctl %s: %v\n", name, err)
+		return 1
+	}
+	return 0
+}
+
+// parseFlags parses the flags of a command and checks that it received
+// between min and max positional arguments; max < 0 means no limit
+func (c *cli) parseFlags(flags *flag.FlagSet, args []string, min, max int) error {
+	flags.SetOutput(c.stderr)
+	flags.Usage = func() {
+		cmd := commands[flags.Name()]
+		fmt.Fprintf(c.stderr, "Usage: catalogctl %s %s\n\n%s\n", flags.Name(), cmd.usage, cmd.summary)
+		flags.PrintDefaults()
+	}
+
+	if err := flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return err
+		}
+		return errUsage
+	}
+	if flags.NArg() < min || (max >= 0 && flags.NArg() > max) {
+		flags.Usage()
+		return errUsage
+	}
+	return nil
+}
+
+func (c *cli) usage(global *flag.FlagSet) {
+	fmt.Fprintf(c.stderr, "Usage: catalogctl [global flags] <command> [flags] [args]\n\nCommands:\n")
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(c.stderr, "  %-9s %s\n", name, commands[name].summary)
+	}
+	fmt.Fprintf(c.stderr, "\nGlobal flags:\n")
+	global.PrintDefaults()
+}
+
+func envOr(name, fallback string) string {
+	if value := os.Getenv(name); value != "" {
+		return value
+	}
+	return fallback
+}
+
+// splitList splits a comma separated flag value, dropping empty entries
+func splitList(raw string) []string {
+	var list []string
+	for _, item := range strings.Split(raw, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
@@ -0,0 +1,243 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"com.kong.connect/handler"
+	"com.kong.connect/middleware"
+	"com.kong.connect/repository"
+	"com.kong.connect/service"
+	"com.kong.connect/testsupport"
+)
+
+// newTestServer serves the API over a freshly seeded database, accepting
+// issued tokens besides the static ones and organizations created through it
+func newTestServer(t *testing.T) string {
+	t.Helper()
+	db := testsupport.NewDB(t)
+
+	svc := service.NewServiceService(repository.NewServiceRepository(db))
+	middleware.SetTokenLookup(handler.TokenLookup(svc))
+	t.Cleanup(func() { middleware.SetTokenLookup(nil) })
+	middleware.SetOrgLookup(handler.OrgLookup(svc))
+	t.Cleanup(func() { middleware.SetOrgLookup(nil) })
+
+	serviceHandler := handler.NewServiceHandler(svc)
+	server := httptest.NewServer(handler.SetupRouter(serviceHandler))
+	t.Cleanup(server.Close)
+	return server.URL
+}
+
+// catalogctl runs a command and returns its exit code and output
+func catalogctl(t *testing.T, url string, stdin string, args ...string) (int, string, string) {
+	t.Helper()
+	var stdout, stderr bytes.Buffer
+	args = append([]string{"-server", url, "-token", "admin-token"}, args...)
+	code := run(context.Background(), args, strings.NewReader(stdin), &stdout, &stderr)
+	return code, stdout.String(), stderr.String()
+}
+
+func TestListCreateAndDelete(t *testing.T) {
+	url := newTestServer(t)
+
+	code, out, _ := catalogctl(t, url, "", "list", "-search", "contact")
+	require.Equal(t, 0, code)
+	assert.Contains(t, out, "Contact Us")
+	assert.NotContains(t, out, "Security")
+
+	code, out, errOut := catalogctl(t, url, "", "create", "-name", "Billing", "-description", "Invoices", "-owner", "payments-team", "-tags", "payments, internal", "-versions", "1.0.0,1.1.0")
+	require.Equal(t, 0, code, errOut)
+	assert.Contains(t, out, `created service 9 "Billing"`)
+
+	code, out, _ = catalogctl(t, url, "", "get", "9")
+	require.Equal(t, 0, code)
+	assert.Contains(t, out, "owner: payments-team")
+	assert.Contains(t, out, "- 1.1.0")
+
+	code, _, errOut = catalogctl(t, url, "", "create", "-name", "Billing", "-description", "Again")
+	assert.Equal(t, 1, code)
+	assert.Contains(t, errOut, "409 Conflict")
+
+	code, out, _ = catalogctl(t, url, "", "delete", "9")
+	require.Equal(t, 0, code)
+	assert.Contains(t, out, "deleted service 9")
+
+	code, _, errOut = catalogctl(t, url, "", "get", "9")
+	assert.Equal(t, 1, code)
+	assert.Contains(t, errOut, "404 Not Found")
+}
+
+func TestOrgSelectsTheCatalog(t *testing.T) {
+	url := newTestServer(t)
+
+	code, out, errOut := catalogctl(t, url, "", "-org", "default", "list", "-search", "contact")
+	require.Equal(t, 0, code, errOut)
+	assert.Contains(t, out, "Contact Us")
+
+	code, _, errOut = catalogctl(t, url, "", "-org", "payments", "list")
+	assert.Equal(t, 1, code)
+	assert.Contains(t, errOut, "404 Not Found")
+
+	code, out, errOut = catalogctl(t, url, "", "organizations", "create", "-name", "Payments", "payments")
+	require.Equal(t, 0, code, errOut)
+	assert.Contains(t, out, "created organization payments (Payments)")
+	code, out, _ = catalogctl(t, url, "", "organizations", "list")
+	require.Equal(t, 0, code)
+	assert.Contains(t, out, "default")
+	assert.Contains(t, out, "payments")
+
+	code, out, errOut = catalogctl(t, url, "", "-org", "payments", "list")
+	require.Equal(t, 0, code, errOut)
+	assert.NotContains(t, out, "Contact Us")
+}
+
+func TestExportAndImportRoundTrip(t *testing.T) {
+	url := newTestServer(t)
+	file := filepath.Join(t.TempDir(), "catalog.yaml")
+
+	code, out, errOut := catalogctl(t, url, "", "export", "-f", file)
+	require.Equal(t, 0, code, errOut)
+	assert.Contains(t, out, "exported 8 services")
+
+	// Importing the unchanged export adds nothing
+	code, out, errOut = catalogctl(t, url, "", "import", "-f", file)
+	require.Equal(t, 0, code, errOut)
+	assert.Contains(t, out, "services: 0 created, 0 updated, 8 unchanged; versions: 0 added")
+
+	content, err := os.ReadFile(file)
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "name: Collect Monday")
+
+	code, out, errOut = catalogctl(t, url, `
+services:
+  - name: Security
+    description: Secrets and keys
+    status: deprecated
+    owner: platform-team
+    versions: [1.2.0, 1.3.0]
+  - name: Billing
+    description: Invoices
+`, "import", "-f", "-")
+	require.Equal(t, 0, code, errOut)
+	assert.Contains(t, out, "services: 1 created, 1 updated, 0 unchanged; versions: 1 added")
+
+	code, out, _ = catalogctl(t, url, "", "list", "-o", "json", "-search", "Security")
+	require.Equal(t, 0, code)
+	assert.Contains(t, out, `"status": "deprecated"`)
+	assert.Contains(t, out, `"version": "1.3.0"`)
+
+	code, _, errOut = catalogctl(t, url, "services:\n  - name: A\n    colour: red\n", "import", "-f", "-")
+	assert.Equal(t, 1, code)
+	assert.Contains(t, errOut, "field colour not found")
+}
+
+func TestMintKey(t *testing.T) {
+	code, out, _ := catalogctl(t, "http://unused", "", "mint-key", "-user", "ci", "-roles", "admin")
+	require.Equal(t, 0, code)
+
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	require.Len(t, lines, 2)
+	token := strings.TrimPrefix(lines[0], "token: ")
+	assert.Len(t, token, 43)
+
+	tokens, err := middleware.ParseTokens(strings.TrimPrefix(lines[1], "entry: "))
+	require.NoError(t, err)
+	assert.Equal(t, middleware.UserClaims{Username: "ci", Roles: []string{"admin"}}, tokens[token])
+}
+
+func TestBootstrapUserAndToken(t *testing.T) {
+	url := newTestServer(t)
+
+	code, out, errOut := catalogctl(t, url, "", "users", "create", "-roles", "admin,viewer", "alice")
+	require.Equal(t, 0, code, errOut)
+	assert.Equal(t, "created user alice (admin,viewer)\n", out)
+
+	code, out, errOut = catalogctl(t, url, "", "tokens", "issue", "-user", "alice", "-name", "laptop")
+	require.Equal(t, 0, code, errOut)
+	token := strings.TrimSpace(out)
+	assert.Contains(t, errOut, "shown only once")
+
+	// The issued token works in place of the static one
+	var stdout, stderr bytes.Buffer
+	require.Equal(t, 0, run(context.Background(), []string{"-server", url, "-token", token, "tokens", "list"}, nil, &stdout, &stderr), stderr.String())
+	assert.Contains(t, stdout.String(), "alice")
+	assert.Contains(t, stdout.String(), "laptop")
+
+	code, out, _ = catalogctl(t, url, "", "tokens", "revoke", "1")
+	require.Equal(t, 0, code)
+	assert.Equal(t, "revoked token 1\n", out)
+	assert.Equal(t, 1, run(context.Background(), []string{"-server", url, "-token", token, "list"}, nil, &stdout, &stderr))
+	assert.Contains(t, stderr.String(), "401 Unauthorized")
+
+	code, out, _ = catalogctl(t, url, "", "users", "disable", "alice")
+	require.Equal(t, 0, code)
+	assert.Equal(t, "disabled user alice\n", out)
+	code, out, _ = catalogctl(t, url, "", "users", "list", "-o", "json")
+	require.Equal(t, 0, code)
+	assert.Contains(t, out, `"disabled": true`)
+
+	code, _, errOut = catalogctl(t, url, "", "users", "create", "-roles", "owner", "bob")
+	assert.Equal(t, 1, code)
+	assert.Contains(t, errOut, `unknown role "owner"`)
+}
+
+func TestLoadTest(t *testing.T) {
+	url := newTestServer(t)
+
+	code, out, errOut := catalogctl(t, url, "", "loadtest", "-rps", "200", "-duration", "500ms", "-o", "json")
+	require.Equal(t, 0, code, errOut)
+	var report []loadResult
+	require.NoError(t, json.Unmarshal([]byte(out), &report))
+	require.Len(t, report, 4)
+	total := 0
+	for _, result := range report[:3] {
+		assert.Positive(t, result.Requests, result.Endpoint)
+		assert.Empty(t, result.Errors, result.Endpoint)
+		assert.LessOrEqual(t, result.LatencyMS["p50"], result.LatencyMS["p99"], result.Endpoint)
+		assert.LessOrEqual(t, result.LatencyMS["p99"], result.LatencyMS["max"], result.Endpoint)
+		total += result.Requests
+	}
+	assert.Equal(t, "total", report[3].Endpoint)
+	assert.Equal(t, total, report[3].Requests)
+	assert.InDelta(t, 100, total, 10)
+
+	code, out, errOut = catalogctl(t, url, "", "-token", "", "loadtest", "-rps", "100", "-duration", "100ms", "-mix", "list=1")
+	assert.Equal(t, 1, code)
+	assert.Contains(t, errOut, "401 Unauthorized")
+	assert.Empty(t, out)
+}
+
+func TestPercentile(t *testing.T) {
+	latencies := make([]time.Duration, 0, 100)
+	for i := 1; i <= 100; i++ {
+		latencies = append(latencies, time.Duration(i)*time.Millisecond)
+	}
+	assert.Equal(t, 50*time.Millisecond, percentile(latencies, 50))
+	assert.Equal(t, 99*time.Millisecond, percentile(latencies, 99))
+	assert.Equal(t, 100*time.Millisecond, percentile(latencies, 100))
+	assert.Equal(t, 7*time.Millisecond, percentile([]time.Duration{7 * time.Millisecond}, 99))
+}
+
+func TestUsageErrors(t *testing.T) {
+	for _, args := range [][]string{{}, {"deploy"}, {"get"}, {"get", "1", "2"}, {"create", "-name", "x"}, {"import"}, {"mint-key"},
+		{"users"}, {"users", "create"}, {"users", "rename", "x"}, {"tokens", "issue"}, {"tokens", "revoke"},
+		{"loadtest", "-rps", "0"}, {"loadtest", "-rps", "2e9"}, {"loadtest", "-rps", "NaN"}, {"loadtest", "-mix", "list=1,upload=2"}, {"loadtest", "-mix", "list=0"}, {"loadtest", "extra"}} {
+		code, _, _ := catalogctl(t, "http://unused", "", args...)
+		assert.Equal(t, 2, code, args)
+	}
+
+	code, _, errOut := catalogctl(t, "http://unused", "", "delete", "abc")
+	assert.Equal(t, 1, code)
+	assert.Contains(t, errOut, "positive integer")
+}
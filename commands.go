@@ -0,0 +1,187 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"runtime/debug"
+	"strings"
+
+	"com.kong.connect/database"
+	"com.kong.connect/domain"
+	"com.kong.connect/repository"
+)
+
+// exportPageSize is the number of services read per query while exporting
+const exportPageSize = 100
+
+// runMigrate brings the database schema up to date without starting the server
+func runMigrate(a *app, args []string) error {
+	if err := a.parseFlags(flag.NewFlagSet("migrate", flag.ContinueOnError), args); err != nil {
+		return err
+	}
+
+	db, err := database.Open(a.cfg.Database.DSN)
+	if err != nil {
+		return err
+	}
+	defer database.Close(db)
+
+	if err := database.Migrate(db); err != nil {
+		return err
+	}
+	a.logger.Info("database migrated", "path", a.cfg.Database.DSN)
+	return nil
+}
+
+// runSeed inserts a seed profile, the services of a file written by export or
+// a generated catalog, leaving databases that already hold services untouched
+func runSeed(a *app, args []string) error {
+	flags := flag.NewFlagSet("seed", flag.ContinueOnError)
+	profile := flags.String("profile", database.SampleProfile, "built-in seed `profile` ("+strings.Join(database.Profiles(), ", ")+")")
+	file := flags.String("file", "", "seed the services of an export `file` instead of a profile")
+	generate := flags.Int("generate", 0, "seed `count` generated services instead of a profile")
+	maxVersions := flags.Int("max-versions", 5, "most `versions` of a generated service")
+	randomSeed := flags.Uint64("random-seed", 1, "`seed` of the generated catalog; the same seed generates the same services")
+	if err := a.parseFlags(flags, args); err != nil {
+		return err
+	}
+	if *file != "" && *generate > 0 {
+		return fmt.Errorf("-file and -generate cannot be combined")
+	}
+
+	var services []database.SeedService
+	var err error
+	if *file != "" {
+		services, err = readSeedFile(*file)
+	} else if *generate != 0 {
+		services, err = database.Generate(database.GenerateOptions{Services: *generate, MaxVersions: *maxVersions, Seed: *randomSeed})
+	} else {
+		services, err = database.Profile(*profile)
+	}
+	if err != nil {
+		return err
+	}
+
+	db, err := database.Open(a.cfg.Database.DSN)
+	if err != nil {
+		return err
+	}
+	defer database.Close(db)
+
+	if err := database.Migrate(db); err != nil {
+		return err
+	}
+	seeded, err := database.SeedServices(db, services)
+	if err != nil {
+		return err
+	}
+	if !seeded {
+		a.logger.Info("catalog is not empty, nothing seeded", "path", a.cfg.Database.DSN)
+	}
+	return nil
+}
+
+// readSeedFile reads the JSON array of services written by export
+func readSeedFile(path string) ([]database.SeedService, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read seed file: %v", err)
+	}
+	var exported []domain.ServiceWithVersions
+	if err := json.Unmarshal(content, &exported); err != nil {
+		return nil, fmt.Errorf("invalid seed file %s: %v", path, err)
+	}
+
+	services := make([]database.SeedService, len(exported))
+	for i, service := range exported {
+		services[i] = database.SeedService{
+			Name:        service.Name,
+			Description: service.Description,
+			Status:      service.Status,
+			Owner:       service.Owner,
+			Tags:        service.Tags,
+		}
+		for _, version := range service.Versions {
+			services[i].Versions = append(services[i].Versions, version.Version)
+		}
+	}
+	return services, nil
+}
+
+// runExport writes every service with its tags and versions as a JSON array
+func runExport(a *app, args []string) error {
+	flags := flag.NewFlagSet("export", flag.ContinueOnError)
+	output := flags.String("o", "", "write to `file` instead of stdout")
+	if err := a.parseFlags(flags, args); err != nil {
+		return err
+	}
+
+	db, err := database.Open(a.cfg.Database.DSN)
+	if err != nil {
+		return err
+	}
+	defer database.Close(db)
+
+	services, err := exportCatalog(context.Background(), repository.NewServiceRepository(db))
+	if err != nil {
+		return err
+	}
+
+	var w io.Writer = a.stdout
+	if *output != "" {
+		file, err := os.Create(*output)
+		if err != nil {
+			return fmt.Errorf("failed to create export file: %v", err)
+		}
+		defer file.Close()
+		w = file
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(services); err != nil {
+		return fmt.Errorf("failed to write export: %v", err)
+	}
+	if *output != "" {
+		a.logger.Info("catalog exported", "services", len(services), "file", *output)
+	}
+	return nil
+}
+
+// exportCatalog reads all services ordered by name, page by page
+func exportCatalog(ctx context.Context, repo *repository.ServiceRepository) ([]domain.ServiceWithVersions, error) {
+	services := []domain.ServiceWithVersions{}
+	for page := 1; ; page++ {
+		batch, total, err := repo.GetAll(ctx, domain.ServiceQuery{SortBy: "name", SortDir: "asc", Page: page, PageSize: exportPageSize})
+		if err != nil {
+			return nil, fmt.Errorf("failed to read services: %v", err)
+		}
+		services = append(services, batch...)
+		if len(batch) < exportPageSize || len(services) >= total {
+			return services, nil
+		}
+	}
+}
+
+// runVersion prints the version, the VCS revision the binary was built from and the Go version
+func runVersion(a *app, args []string) error {
+	if err := a.parseFlags(flag.NewFlagSet("version", flag.ContinueOnError), args); err != nil {
+		return err
+	}
+
+	revision := "unknown"
+	if info, ok := debug.ReadBuildInfo(); ok {
+		for _, setting := range info.Settings {
+			if setting.Key == "vcs.revision" {
+				revision = setting.Value
+			}
+		}
+	}
+	fmt.Fprintf(a.stdout, "kong-connect %s (revision %s, %s)\n", version, revision, runtime.Version())
+	return nil
+}
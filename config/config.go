@@ -0,0 +1,683 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/mail"
+	"net/netip"
+	"net/url"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"com.kong.connect/clientip"
+	"com.kong.connect/consul"
+	"com.kong.connect/domain"
+	"com.kong.connect/email"
+	"com.kong.connect/errreport"
+	"com.kong.connect/jobs"
+	"com.kong.connect/kube"
+	"com.kong.connect/logging"
+	"com.kong.connect/metrics"
+	"com.kong.connect/middleware"
+	"com.kong.connect/notify"
+	"com.kong.connect/probe"
+	"com.kong.connect/scm"
+	"com.kong.connect/secrets"
+	"com.kong.connect/transport"
+)
+
+// setting maps a key of the configuration file to the environment variable overriding it
+type setting struct {
+	key string
+	env string
+}
+
+// settings lists every configuration key; the file may only use these keys
+var settings = []setting{
+	{"server.port", "PORT"},
+	{"server.request_timeout", "REQUEST_TIMEOUT"},
+	{"server.read_header_timeout", "HTTP_READ_HEADER_TIMEOUT"},
+	{"server.read_timeout", "HTTP_READ_TIMEOUT"},
+	{"server.write_timeout", "HTTP_WRITE_TIMEOUT"},
+	{"server.idle_timeout", "HTTP_IDLE_TIMEOUT"},
+	{"server.shutdown_timeout", "SHUTDOWN_TIMEOUT"},
+	{"server.drain_grace_period", "DRAIN_GRACE_PERIOD"},
+	{"server.max_body_bytes", "MAX_BODY_BYTES"},
+	{"server.max_response_bytes", "MAX_RESPONSE_BYTES"},
+	{"server.max_in_flight", "MAX_IN_FLIGHT_REQUESTS"},
+	{"server.queue_timeout", "REQUEST_QUEUE_TIMEOUT"},
+	{"server.lenient_query_params", "LENIENT_QUERY_PARAMS"},
+	{"server.fast_json", "FAST_JSON"},
+	{"server.debug_endpoints", "DEBUG_ENDPOINTS"},
+	{"server.http2_disabled", "HTTP2_DISABLED"},
+	{"server.http2_cleartext", "HTTP2_CLEARTEXT"},
+	{"server.trusted_proxies", "TRUSTED_PROXIES"},
+	{"fault_injection.latency_percent", "FAULT_LATENCY_PERCENT"},
+	{"fault_injection.latency", "FAULT_LATENCY"},
+	{"fault_injection.error_percent", "FAULT_ERROR_PERCENT"},
+	{"fault_injection.error_statuses", "FAULT_ERROR_STATUSES"},
+	{"fault_injection.drop_percent", "FAULT_DROP_PERCENT"},
+	{"tls.cert_file", "TLS_CERT_FILE"},
+	{"tls.key_file", "TLS_KEY_FILE"},
+	{"tls.autocert_domains", "TLS_AUTOCERT_DOMAINS"},
+	{"tls.autocert_cache_dir", "TLS_AUTOCERT_CACHE_DIR"},
+	{"tls.autocert_email", "TLS_AUTOCERT_EMAIL"},
+	{"tls.redirect_addr", "TLS_REDIRECT_ADDR"},
+	{"database.driver", "DB_DRIVER"},
+	{"database.dsn", "DB_PATH"},
+	{"database.seed_on_start", "SEED_ON_START"},
+	{"search.accent_sensitive", "SEARCH_ACCENT_SENSITIVE"},
+	{"auth.tokens", "AUTH_TOKENS"},
+	{"cors.allowed_origins", "CORS_ALLOWED_ORIGINS"},
+	{"features.flags", "FEATURE_FLAGS"},
+	{"logging.format", "LOG_FORMAT"},
+	{"logging.level", "LOG_LEVEL"},
+	{"logging.access_log_format", "ACCESS_LOG_FORMAT"},
+	{"logging.debug_body_bytes", "DEBUG_BODY_BYTES"},
+	{"rate_limit.limits", "RATE_LIMITS"},
+	{"rate_limit.redis_url", "RATE_LIMIT_REDIS_URL"},
+	{"redis.url", "REDIS_URL"},
+	{"ui.enabled", "UI_ENABLED"},
+	{"metrics.enabled", "METRICS_ENABLED"},
+	{"metrics.latency_slos", "LATENCY_SLOS"},
+	{"audit.retention_days", "AUDIT_RETENTION_DAYS"},
+	{"retention.schedule", "RETENTION_SCHEDULE"},
+	{"retention.dry_run", "RETENTION_DRY_RUN"},
+	{"jobs.leader_election", "JOBS_LEADER_ELECTION"},
+	{"webhooks.max_attempts", "WEBHOOK_MAX_ATTEMPTS"},
+	{"webhooks.retention_days", "WEBHOOK_RETENTION_DAYS"},
+	{"cache.service_size", "SERVICE_CACHE_SIZE"},
+	{"cache.service_ttl", "SERVICE_CACHE_TTL"},
+	{"cache.shared_ttl", "SHARED_CACHE_TTL"},
+	{"cache.warm_pages", "CACHE_WARM_PAGES"},
+	{"cache.warm_services", "CACHE_WARM_SERVICES"},
+	{"http_cache.max_age", "HTTP_CACHE_MAX_AGE"},
+	{"http_cache.stale_while_revalidate", "HTTP_CACHE_STALE_WHILE_REVALIDATE"},
+	{"http_cache.purge_url", "HTTP_CACHE_PURGE_URL"},
+	{"http_cache.purge_token", "HTTP_CACHE_PURGE_TOKEN"},
+	{"kafka.brokers", "KAFKA_BROKERS"},
+	{"kafka.topic", "KAFKA_TOPIC"},
+	{"nats.url", "NATS_URL"},
+	{"nats.subject_prefix", "NATS_SUBJECT_PREFIX"},
+	{"consul.address", "CONSUL_ADDR"},
+	{"consul.token", "CONSUL_TOKEN"},
+	{"consul.datacenter", "CONSUL_DATACENTER"},
+	{"consul.conflict_policy", "CONSUL_CONFLICT_POLICY"},
+	{"consul.import_interval", "CONSUL_IMPORT_INTERVAL"},
+	{"kubernetes.discovery", "KUBE_DISCOVERY"},
+	{"kubernetes.kubeconfig", "KUBECONFIG"},
+	{"kubernetes.namespace", "KUBE_NAMESPACE"},
+	{"kubernetes.conflict_policy", "KUBE_CONFLICT_POLICY"},
+	{"health_checks.interval", "HEALTH_CHECK_INTERVAL"},
+	{"health_checks.timeout", "HEALTH_CHECK_TIMEOUT"},
+	{"health_checks.retention_days", "HEALTH_CHECK_RETENTION_DAYS"},
+	{"repositories.refresh_interval", "REPOSITORY_REFRESH_INTERVAL"},
+	{"repositories.github_api_url", "GITHUB_API_URL"},
+	{"repositories.github_token", "GITHUB_TOKEN"},
+	{"repositories.gitlab_api_url", "GITLAB_API_URL"},
+	{"repositories.gitlab_token", "GITLAB_TOKEN"},
+	{"analytics.flush_interval", "ANALYTICS_FLUSH_INTERVAL"},
+	{"analytics.retention_days", "ANALYTICS_RETENTION_DAYS"},
+	{"notifications.channels", "NOTIFY_CHANNELS"},
+	{"email.smtp_addr", "SMTP_ADDR"},
+	{"email.smtp_username", "SMTP_USERNAME"},
+	{"email.smtp_password", "SMTP_PASSWORD"},
+	{"email.from", "SMTP_FROM"},
+	{"email.templates_dir", "EMAIL_TEMPLATES_DIR"},
+	{"sentry.dsn", "SENTRY_DSN"},
+	{"sentry.environment", "SENTRY_ENVIRONMENT"},
+	{"sentry.release", "SENTRY_RELEASE"},
+}
+
+// secretSettings may hold a reference to a secrets manager, such as
+// vault://secret/data/kong-connect#dsn, instead of the value itself
+var secretSettings = []string{"DB_PATH", "AUTH_TOKENS", "REDIS_URL", "RATE_LIMIT_REDIS_URL", "SENTRY_DSN", "HTTP_CACHE_PURGE_TOKEN", "NATS_URL", "CONSUL_TOKEN", "NOTIFY_CHANNELS", "SMTP_PASSWORD", "GITHUB_TOKEN", "GITLAB_TOKEN"}
+
+// secretTimeout bounds reading the secrets of one Load
+const secretTimeout = 10 * time.Second
+
+// secretResolver is kept across loads so that reloads reuse the providers
+var secretResolver = secrets.NewResolver()
+
+// Defaults of the settings that are not empty by default
+var defaults = map[string]string{
+	"PORT":                     "8080",
+	"REQUEST_TIMEOUT":          "30s",
+	"HTTP_READ_HEADER_TIMEOUT": "5s",
+	"HTTP_READ_TIMEOUT":        "15s",
+	// Leaves room for 30 second CPU profiles from /debug/pprof/profile
+	"HTTP_WRITE_TIMEOUT":     "60s",
+	"HTTP_IDLE_TIMEOUT":      "120s",
+	"SHUTDOWN_TIMEOUT":       "30s",
+	"DRAIN_GRACE_PERIOD":     "15s",
+	"MAX_BODY_BYTES":         "1048576",
+	"MAX_RESPONSE_BYTES":     "8388608",
+	"MAX_IN_FLIGHT_REQUESTS": "100",
+	"REQUEST_QUEUE_TIMEOUT":  "5s",
+	"TLS_AUTOCERT_CACHE_DIR": "./certs",
+	"DB_DRIVER":              "sqlite3",
+	"DB_PATH":                "./services.db",
+	"ACCESS_LOG_FORMAT":      middleware.AccessLogStructured,
+	"DEBUG_BODY_BYTES":       "0",
+	"RATE_LIMITS":            DefaultRateLimits,
+	"AUTH_TOKENS":            DefaultAuthTokens,
+	"UI_ENABLED":             "true",
+	"METRICS_ENABLED":        "true",
+	"LATENCY_SLOS":           metrics.DefaultLatencySLOs,
+	"AUDIT_RETENTION_DAYS":   "365",
+	"RETENTION_SCHEDULE":     "@hourly",
+	"WEBHOOK_MAX_ATTEMPTS":   "8",
+	"WEBHOOK_RETENTION_DAYS": "30",
+	"KAFKA_TOPIC":            "kong-connect.catalog-changes",
+	"NATS_SUBJECT_PREFIX":    "kong-connect.catalog",
+	"CONSUL_CONFLICT_POLICY": domain.ConflictSkip,
+	"CONSUL_IMPORT_INTERVAL": "1m",
+	"KUBE_CONFLICT_POLICY":   domain.ConflictSkip,
+	"SERVICE_CACHE_SIZE":     "1000",
+	"SERVICE_CACHE_TTL":      "30s",
+	"SHARED_CACHE_TTL":       "0s",
+	"CACHE_WARM_PAGES":       "0",
+	"CACHE_WARM_SERVICES":    "0",
+
+	"HEALTH_CHECK_INTERVAL":       "1m",
+	"HEALTH_CHECK_TIMEOUT":        "5s",
+	"HEALTH_CHECK_RETENTION_DAYS": "7",
+
+	"REPOSITORY_REFRESH_INTERVAL": "1h",
+	"GITHUB_API_URL":              scm.DefaultGitHubURL,
+	"GITLAB_API_URL":              scm.DefaultGitLabURL,
+
+	"ANALYTICS_FLUSH_INTERVAL": "1m",
+	"ANALYTICS_RETENTION_DAYS": "90",
+
+	"HTTP_CACHE_MAX_AGE":                "0s",
+	"HTTP_CACHE_STALE_WHILE_REVALIDATE": "0s",
+
+	"FAULT_LATENCY_PERCENT": "0",
+	"FAULT_LATENCY":         "0s",
+	"FAULT_ERROR_PERCENT":   "0",
+	"FAULT_ERROR_STATUSES":  "500,502,503,504",
+	"FAULT_DROP_PERCENT":    "0",
+}
+
+// Config holds the settings of the server
+type Config struct {
+	Port     string
+	Database DatabaseConfig
+	Logging  logging.Config
+	// AccessLogFormat is structured, json, combined or off
+	AccessLogFormat string
+	// DebugBodyBytes, unless 0, logs requests and responses with their
+	// bodies cut at this size at debug level, secrets redacted
+	DebugBodyBytes int
+	// Runtime holds the settings reloaded on SIGHUP
+	Runtime Runtime
+
+	RequestTimeout    time.Duration
+	ReadHeaderTimeout time.Duration
+	ReadTimeout       time.Duration
+	WriteTimeout      time.Duration
+	IdleTimeout       time.Duration
+	ShutdownTimeout   time.Duration
+	// DrainGracePeriod is how long POST /drain keeps serving with failing
+	// readiness before shutting down; 0 disables the endpoint
+	DrainGracePeriod time.Duration
+
+	MaxBodyBytes int64
+	// MaxResponseBytes bounds JSON responses; together with MaxInFlight it
+	// bounds the memory held by responses being sent. 0 disables the bound.
+	MaxResponseBytes int
+	// MaxInFlight bounds the API requests handled at once, others queue for
+	// up to QueueTimeout; 0 disables the limit
+	MaxInFlight        int
+	QueueTimeout       time.Duration
+	LenientQueryParams bool
+	FastJSON           bool
+	DebugEndpoints     bool
+	TLS                transport.TLSConfig
+	HTTP2              transport.HTTP2Config
+	// TrustedProxies are the proxies whose X-Forwarded-For and X-Real-IP
+	// headers name the client IP; other peers are the client
+	TrustedProxies []netip.Prefix
+	// Faults injects latency, errors and dropped connections into API
+	// requests; for test deployments only
+	Faults middleware.Faults
+
+	// UIEnabled serves the embedded web UI at /
+	UIEnabled bool
+
+	// SearchAccentSensitive makes searches tell accented letters apart
+	SearchAccentSensitive bool
+
+	// RedisURL coordinates the replicas of a deployment: change events are
+	// relayed between them and, without RateLimitRedisURL, rate limits shared
+	RedisURL          string
+	RateLimitRedisURL string
+	MetricsEnabled    bool
+	LatencySLOs       []metrics.LatencySLO
+	// AuditRetentionDays bounds how long audit entries are kept; 0 keeps them forever
+	AuditRetentionDays int
+	// RetentionSchedule runs the job deleting expired rows
+	RetentionSchedule jobs.Schedule
+	// RetentionDryRun logs what the retention job would delete without deleting it
+	RetentionDryRun bool
+	Sentry          errreport.SentryConfig
+
+	// JobsLeaderElection runs background jobs on one replica at a time
+	JobsLeaderElection bool
+
+	// WebhookMaxAttempts bounds the attempts to deliver an event to a webhook;
+	// WebhookRetentionDays bounds how long finished deliveries stay logged,
+	// 0 keeps them forever
+	WebhookMaxAttempts   int
+	WebhookRetentionDays int
+
+	// ServiceCacheSize bounds the services cached by ID; 0 disables the cache
+	ServiceCacheSize int
+	ServiceCacheTTL  time.Duration
+	// SharedCacheTTL caches listings and details in REDIS_URL; 0 disables it
+	SharedCacheTTL time.Duration
+	// CacheWarmPages and CacheWarmServices are read at startup to fill the
+	// caches: pages of the default listing and the services viewed most
+	CacheWarmPages    int
+	CacheWarmServices int
+
+	// HTTPCache lets CDNs and proxies cache catalog reads; changes are purged
+	// through HTTPCachePurgeURL when set
+	HTTPCache           middleware.HTTPCache
+	HTTPCachePurgeURL   string
+	HTTPCachePurgeToken string
+
+	// KafkaBrokers, when set, records change events in the outbox with their
+	// writes and ships them to KafkaTopic
+	KafkaBrokers []string
+	KafkaTopic   string
+	// NATSURL, the alternative to KafkaBrokers, ships them to JetStream on
+	// the subject NATSSubjectPrefix.<event type>
+	NATSURL           string
+	NATSSubjectPrefix string
+
+	// Consul, when its address is set, imports the services of Consul's
+	// catalog every Consul.Interval
+	Consul consul.Config
+	// Kubernetes, when enabled, registers the annotated Services and
+	// Ingresses of a cluster and keeps them in sync
+	Kubernetes kube.Config
+
+	// Probe, unless its interval is 0, probes the health checks of services;
+	// HealthCheckRetentionDays bounds how long their results are kept, 0
+	// keeps them forever
+	Probe                    probe.Config
+	HealthCheckRetentionDays int
+
+	// Repositories, unless its interval is 0, refreshes the default branch
+	// and latest release of the source repositories linked to services
+	Repositories scm.Config
+
+	// AnalyticsFlushInterval, unless 0, counts the views and searches of the
+	// catalog and stores them this often; AnalyticsRetentionDays bounds how
+	// long they are kept, 0 keeps them forever
+	AnalyticsFlushInterval time.Duration
+	AnalyticsRetentionDays int
+
+	// NotifyChannels are the Slack and Teams webhooks notified of created,
+	// deprecated and deleted services
+	NotifyChannels []notify.Channel
+	// Email, when its SMTP address is set, emails owners and subscribers
+	// about deprecated services and ownership changes
+	Email email.Config
+}
+
+// DatabaseConfig selects the database; sqlite3 is the only driver, with the file path as DSN
+type DatabaseConfig struct {
+	Driver string
+	DSN    string
+	// SeedOnStart inserts the sample catalog into an empty database at startup
+	SeedOnStart bool
+}
+
+// Load reads the configuration file at path, when path is not empty, and
+// overrides its settings with the environment and then with overrides, which
+// are keyed by file key such as server.port or by environment variable name.
+// YAML (.yaml, .yml) and TOML (.toml) files are supported; other files are
+// read as KEY=VALUE lines using the environment variable names. Secret
+// settings may reference Vault, AWS Secrets Manager or GCP Secret Manager and
+// are read on every Load, so a reload picks up rotated secrets. Every setting
+// is validated, so a bad file or variable is rejected as a whole.
+func Load(path string, overrides map[string]string) (*Config, error) {
+	values := make(map[string]string, len(settings))
+	if path != "" {
+		if err := readFile(path, values); err != nil {
+			return nil, err
+		}
+	}
+	for _, s := range settings {
+		if value := os.Getenv(s.env); value != "" {
+			values[s.env] = value
+		}
+	}
+	for key, value := range overrides {
+		env, ok := lookupSetting(key)
+		if !ok {
+			return nil, fmt.Errorf("unknown setting %q", key)
+		}
+		values[env] = value
+	}
+	for env, value := range defaults {
+		if values[env] == "" {
+			values[env] = value
+		}
+	}
+	if err := resolveSecrets(values); err != nil {
+		return nil, err
+	}
+	return parse(values)
+}
+
+// resolveSecrets replaces secret references in the secret settings with the secrets
+func resolveSecrets(values map[string]string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), secretTimeout)
+	defer cancel()
+
+	for _, env := range secretSettings {
+		secret, err := secretResolver.Resolve(ctx, values[env])
+		if err != nil {
+			return fmt.Errorf("invalid %s: %v", env, err)
+		}
+		values[env] = secret
+	}
+	return nil
+}
+
+// lookupSetting returns the environment variable name of a file key or environment variable name
+func lookupSetting(key string) (string, bool) {
+	for _, s := range settings {
+		if key == s.key || key == s.env {
+			return s.env, true
+		}
+	}
+	return "", false
+}
+
+// parse validates the settings and converts them to their types
+func parse(values map[string]string) (*Config, error) {
+	p := parser{values: values}
+	cfg := &Config{
+		Port: values["PORT"],
+		Database: DatabaseConfig{
+			Driver:      values["DB_DRIVER"],
+			DSN:         values["DB_PATH"],
+			SeedOnStart: p.boolean("SEED_ON_START"),
+		},
+		Logging: logging.Config{
+			Format: values["LOG_FORMAT"],
+			Level:  values["LOG_LEVEL"],
+		},
+		AccessLogFormat: values["ACCESS_LOG_FORMAT"],
+		DebugBodyBytes:  p.integer("DEBUG_BODY_BYTES", 0),
+
+		RequestTimeout:    p.duration("REQUEST_TIMEOUT"),
+		ReadHeaderTimeout: p.duration("HTTP_READ_HEADER_TIMEOUT"),
+		ReadTimeout:       p.duration("HTTP_READ_TIMEOUT"),
+		WriteTimeout:      p.duration("HTTP_WRITE_TIMEOUT"),
+		IdleTimeout:       p.duration("HTTP_IDLE_TIMEOUT"),
+		ShutdownTimeout:   p.duration("SHUTDOWN_TIMEOUT"),
+		DrainGracePeriod:  p.duration("DRAIN_GRACE_PERIOD"),
+
+		MaxBodyBytes:       int64(p.integer("MAX_BODY_BYTES", 1)),
+		MaxResponseBytes:   p.integer("MAX_RESPONSE_BYTES", 0),
+		MaxInFlight:        p.integer("MAX_IN_FLIGHT_REQUESTS", 0),
+		QueueTimeout:       p.duration("REQUEST_QUEUE_TIMEOUT"),
+		LenientQueryParams: p.boolean("LENIENT_QUERY_PARAMS"),
+		FastJSON:           p.boolean("FAST_JSON"),
+		DebugEndpoints:     p.boolean("DEBUG_ENDPOINTS"),
+		TLS: transport.TLSConfig{
+			CertFile:         values["TLS_CERT_FILE"],
+			KeyFile:          values["TLS_KEY_FILE"],
+			AutocertDomains:  splitList(values["TLS_AUTOCERT_DOMAINS"]),
+			AutocertCacheDir: values["TLS_AUTOCERT_CACHE_DIR"],
+			AutocertEmail:    values["TLS_AUTOCERT_EMAIL"],
+			RedirectAddr:     values["TLS_REDIRECT_ADDR"],
+		},
+		HTTP2: transport.HTTP2Config{
+			Disabled:  p.boolean("HTTP2_DISABLED"),
+			Cleartext: p.boolean("HTTP2_CLEARTEXT"),
+		},
+
+		UIEnabled: p.boolean("UI_ENABLED"),
+
+		SearchAccentSensitive: p.boolean("SEARCH_ACCENT_SENSITIVE"),
+
+		RedisURL:             values["REDIS_URL"],
+		RateLimitRedisURL:    values["RATE_LIMIT_REDIS_URL"],
+		MetricsEnabled:       p.boolean("METRICS_ENABLED"),
+		AuditRetentionDays:   p.integer("AUDIT_RETENTION_DAYS", 0),
+		RetentionDryRun:      p.boolean("RETENTION_DRY_RUN"),
+		JobsLeaderElection:   p.boolean("JOBS_LEADER_ELECTION"),
+		WebhookMaxAttempts:   p.integer("WEBHOOK_MAX_ATTEMPTS", 1),
+		WebhookRetentionDays: p.integer("WEBHOOK_RETENTION_DAYS", 0),
+		ServiceCacheSize:     p.integer("SERVICE_CACHE_SIZE", 0),
+		ServiceCacheTTL:      p.duration("SERVICE_CACHE_TTL"),
+		SharedCacheTTL:       p.duration("SHARED_CACHE_TTL"),
+		CacheWarmPages:       p.integer("CACHE_WARM_PAGES", 0),
+		CacheWarmServices:    p.integer("CACHE_WARM_SERVICES", 0),
+		HTTPCache: middleware.HTTPCache{
+			MaxAge:               p.duration("HTTP_CACHE_MAX_AGE"),
+			StaleWhileRevalidate: p.duration("HTTP_CACHE_STALE_WHILE_REVALIDATE"),
+		},
+		Faults: middleware.Faults{
+			LatencyPercent: p.percent("FAULT_LATENCY_PERCENT"),
+			Latency:        p.duration("FAULT_LATENCY"),
+			ErrorPercent:   p.percent("FAULT_ERROR_PERCENT"),
+			ErrorStatuses:  p.integers("FAULT_ERROR_STATUSES"),
+			DropPercent:    p.percent("FAULT_DROP_PERCENT"),
+		},
+		HTTPCachePurgeURL:   values["HTTP_CACHE_PURGE_URL"],
+		HTTPCachePurgeToken: values["HTTP_CACHE_PURGE_TOKEN"],
+		KafkaBrokers:        splitList(values["KAFKA_BROKERS"]),
+		KafkaTopic:          values["KAFKA_TOPIC"],
+		NATSURL:             values["NATS_URL"],
+		NATSSubjectPrefix:   values["NATS_SUBJECT_PREFIX"],
+		Consul: consul.Config{
+			Address:        values["CONSUL_ADDR"],
+			Token:          values["CONSUL_TOKEN"],
+			Datacenter:     values["CONSUL_DATACENTER"],
+			ConflictPolicy: strings.ToLower(values["CONSUL_CONFLICT_POLICY"]),
+			Interval:       p.duration("CONSUL_IMPORT_INTERVAL"),
+		},
+		Kubernetes: kube.Config{
+			Enabled:        p.boolean("KUBE_DISCOVERY"),
+			Kubeconfig:     values["KUBECONFIG"],
+			Namespace:      values["KUBE_NAMESPACE"],
+			ConflictPolicy: strings.ToLower(values["KUBE_CONFLICT_POLICY"]),
+		},
+		Probe: probe.Config{
+			Interval: p.duration("HEALTH_CHECK_INTERVAL"),
+			Timeout:  p.duration("HEALTH_CHECK_TIMEOUT"),
+		},
+		HealthCheckRetentionDays: p.integer("HEALTH_CHECK_RETENTION_DAYS", 0),
+		AnalyticsFlushInterval:   p.duration("ANALYTICS_FLUSH_INTERVAL"),
+		AnalyticsRetentionDays:   p.integer("ANALYTICS_RETENTION_DAYS", 0),
+		Repositories: scm.Config{
+			Interval:    p.duration("REPOSITORY_REFRESH_INTERVAL"),
+			GitHubURL:   values["GITHUB_API_URL"],
+			GitHubToken: values["GITHUB_TOKEN"],
+			GitLabURL:   values["GITLAB_API_URL"],
+			GitLabToken: values["GITLAB_TOKEN"],
+		},
+		Email: email.Config{
+			Addr:         values["SMTP_ADDR"],
+			Username:     values["SMTP_USERNAME"],
+			Password:     values["SMTP_PASSWORD"],
+			From:         values["SMTP_FROM"],
+			TemplatesDir: values["EMAIL_TEMPLATES_DIR"],
+		},
+		Sentry: errreport.SentryConfig{
+			DSN:         values["SENTRY_DSN"],
+			Environment: values["SENTRY_ENVIRONMENT"],
+			Release:     values["SENTRY_RELEASE"],
+		},
+	}
+	if p.err != nil {
+		return nil, p.err
+	}
+
+	if _, err := strconv.ParseUint(cfg.Port, 10, 16); err != nil {
+		return nil, fmt.Errorf("invalid PORT: %q is not a port number", cfg.Port)
+	}
+	if cfg.Database.Driver != "sqlite3" {
+		return nil, fmt.Errorf("invalid DB_DRIVER: unsupported driver %q, only sqlite3 is available", cfg.Database.Driver)
+	}
+	if f := strings.ToLower(cfg.Logging.Format); f != "" && f != "text" && f != "json" {
+		return nil, fmt.Errorf("invalid LOG_FORMAT: unknown format %q", cfg.Logging.Format)
+	}
+	if !middleware.ValidAccessLogFormat(cfg.AccessLogFormat) {
+		return nil, fmt.Errorf("invalid ACCESS_LOG_FORMAT: unknown format %q", cfg.AccessLogFormat)
+	}
+	if err := cfg.TLS.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid TLS settings: %v", err)
+	}
+	if err := cfg.Faults.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid fault injection settings: %v", err)
+	}
+	if cfg.SharedCacheTTL > 0 && cfg.RedisURL == "" {
+		return nil, fmt.Errorf("invalid SHARED_CACHE_TTL: the shared cache needs REDIS_URL")
+	}
+	if len(cfg.KafkaBrokers) > 0 && cfg.NATSURL != "" {
+		return nil, fmt.Errorf("invalid NATS_URL: change events are shipped to Kafka or NATS, not both")
+	}
+	if !slices.Contains(domain.ConflictPolicies, cfg.Consul.ConflictPolicy) {
+		return nil, fmt.Errorf("invalid CONSUL_CONFLICT_POLICY: unknown policy %q", cfg.Consul.ConflictPolicy)
+	}
+	if cfg.Consul.Address != "" && cfg.Consul.Interval <= 0 {
+		return nil, fmt.Errorf("invalid CONSUL_IMPORT_INTERVAL: must be positive")
+	}
+	if !slices.Contains(domain.ConflictPolicies, cfg.Kubernetes.ConflictPolicy) {
+		return nil, fmt.Errorf("invalid KUBE_CONFLICT_POLICY: unknown policy %q", cfg.Kubernetes.ConflictPolicy)
+	}
+	if cfg.Probe.Interval > 0 && (cfg.Probe.Timeout <= 0 || cfg.Probe.Timeout > cfg.Probe.Interval) {
+		return nil, fmt.Errorf("invalid HEALTH_CHECK_TIMEOUT: must be positive and at most HEALTH_CHECK_INTERVAL")
+	}
+	if !absoluteHTTPURL(cfg.Repositories.GitHubURL) {
+		return nil, fmt.Errorf("invalid GITHUB_API_URL: must be an absolute http or https URL")
+	}
+	if !absoluteHTTPURL(cfg.Repositories.GitLabURL) {
+		return nil, fmt.Errorf("invalid GITLAB_API_URL: must be an absolute http or https URL")
+	}
+	if cfg.Email.Addr != "" {
+		if _, _, err := net.SplitHostPort(cfg.Email.Addr); err != nil {
+			return nil, fmt.Errorf("invalid SMTP_ADDR: expected host:port")
+		}
+		if _, err := mail.ParseAddress(cfg.Email.From); err != nil {
+			return nil, fmt.Errorf("invalid SMTP_FROM: the sender of the emails must be an email address")
+		}
+	}
+
+	var err error
+	if cfg.RetentionSchedule, err = jobs.ParseSchedule(values["RETENTION_SCHEDULE"]); err != nil {
+		return nil, fmt.Errorf("invalid RETENTION_SCHEDULE: %v", err)
+	}
+	if cfg.LatencySLOs, err = metrics.ParseLatencySLOs(values["LATENCY_SLOS"]); err != nil {
+		return nil, fmt.Errorf("invalid LATENCY_SLOS: %v", err)
+	}
+	if cfg.TrustedProxies, err = clientip.ParseTrustedProxies(splitList(values["TRUSTED_PROXIES"])); err != nil {
+		return nil, fmt.Errorf("invalid TRUSTED_PROXIES: %v", err)
+	}
+	if cfg.NotifyChannels, err = notify.ParseChannels(values["NOTIFY_CHANNELS"]); err != nil {
+		return nil, fmt.Errorf("invalid NOTIFY_CHANNELS: %v", err)
+	}
+	runtime, err := parseRuntime(values)
+	if err != nil {
+		return nil, err
+	}
+	cfg.Runtime = *runtime
+
+	return cfg, nil
+}
+
+// absoluteHTTPURL reports whether value is an absolute http or https URL
+func absoluteHTTPURL(value string) bool {
+	u, err := url.Parse(value)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+// parser converts settings, keeping the first error
+type parser struct {
+	values map[string]string
+	err    error
+}
+
+func (p *parser) fail(env, format string, args ...interface{}) {
+	if p.err == nil {
+		p.err = fmt.Errorf("invalid %s: %s", env, fmt.Sprintf(format, args...))
+	}
+}
+
+// duration parses a non-negative Go duration such as 15s
+func (p *parser) duration(env string) time.Duration {
+	value, err := time.ParseDuration(p.values[env])
+	if err != nil || value < 0 {
+		p.fail(env, "must be a non-negative duration, got %q", p.values[env])
+	}
+	return value
+}
+
+// integer parses an integer of at least min
+func (p *parser) integer(env string, min int) int {
+	value, err := strconv.Atoi(p.values[env])
+	if err != nil || value < min {
+		p.fail(env, "must be an integer of at least %d, got %q", min, p.values[env])
+	}
+	return value
+}
+
+// percent parses a percentage such as 2.5
+func (p *parser) percent(env string) float64 {
+	value, err := strconv.ParseFloat(p.values[env], 64)
+	if err != nil || value < 0 || value > 100 {
+		p.fail(env, "must be a percentage between 0 and 100, got %q", p.values[env])
+	}
+	return value
+}
+
+// integers parses a comma separated list of integers
+func (p *parser) integers(env string) []int {
+	var values []int
+	for _, item := range splitList(p.values[env]) {
+		value, err := strconv.Atoi(item)
+		if err != nil {
+			p.fail(env, "must be a list of integers, got %q", p.values[env])
+			return nil
+		}
+		values = append(values, value)
+	}
+	return values
+}
+
+// boolean parses true or false; unset means false
+func (p *parser) boolean(env string) bool {
+	raw := p.values[env]
+	if raw == "" {
+		return false
+	}
+	value, err := strconv.ParseBool(raw)
+	if err != nil {
+		p.fail(env, "must be true or false, got %q", raw)
+	}
+	return value
+}
+
+// splitList splits a comma separated list, dropping empty entries
+func splitList(raw string) []string {
+	var list []string
+	for _, item := range strings.Split(raw, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
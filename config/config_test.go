@@ -0,0 +1,209 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"com.kong.connect/features"
+	"com.kong.connect/middleware"
+	"com.kong.connect/scm"
+	"com.kong.connect/secrets"
+)
+
+func TestLoadDefaults(t *testing.T) {
+	clearEnv(t)
+
+	cfg, err := Load("", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "8080", cfg.Port)
+	assert.Equal(t, DatabaseConfig{Driver: "sqlite3", DSN: "./services.db", SeedOnStart: false}, cfg.Database)
+	assert.Equal(t, 30*time.Second, cfg.RequestTimeout)
+	assert.Equal(t, 60*time.Second, cfg.WriteTimeout)
+	assert.Equal(t, int64(1<<20), cfg.MaxBodyBytes)
+	assert.Equal(t, 365, cfg.AuditRetentionDays)
+	assert.Equal(t, "./certs", cfg.TLS.AutocertCacheDir)
+	assert.True(t, cfg.MetricsEnabled)
+	assert.NotEmpty(t, cfg.LatencySLOs)
+	assert.NotNil(t, cfg.RetentionSchedule)
+	assert.False(t, cfg.RetentionDryRun)
+	assert.False(t, cfg.TLS.Enabled())
+	assert.Equal(t, scm.Config{Interval: time.Hour, GitHubURL: scm.DefaultGitHubURL, GitLabURL: scm.DefaultGitLabURL}, cfg.Repositories)
+	assert.False(t, cfg.Faults.Enabled())
+}
+
+func TestLoadYAMLWithEnvironmentOverrides(t *testing.T) {
+	clearEnv(t)
+	t.Setenv("PORT", "9090")
+
+	path := writeFile(t, "config.yaml", `
+server:
+  port: 8081
+  request_timeout: 10s
+  debug_endpoints: true
+  trusted_proxies: [10.0.0.0/8, 192.168.1.10]
+database:
+  dsn: /var/lib/catalog/services.db
+auth:
+  tokens:
+    - s3cr3t=ci:admin
+cors:
+  allowed_origins: [https://portal.example.com, https://admin.example.com]
+logging:
+  format: json
+  level: warn
+metrics:
+  latency_slos: off
+features:
+  flags:
+    - lenient_query_params=10%
+`)
+
+	cfg, err := Load(path, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "9090", cfg.Port)
+	assert.Equal(t, 10*time.Second, cfg.RequestTimeout)
+	assert.True(t, cfg.DebugEndpoints)
+	assert.Equal(t, []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8"), netip.MustParsePrefix("192.168.1.10/32")}, cfg.TrustedProxies)
+	assert.Equal(t, "/var/lib/catalog/services.db", cfg.Database.DSN)
+	assert.Equal(t, "json", cfg.Logging.Format)
+	assert.Equal(t, "warn", cfg.Runtime.LogLevel)
+	assert.Equal(t, []string{"https://portal.example.com", "https://admin.example.com"}, cfg.Runtime.CORSOrigins)
+	assert.Contains(t, cfg.Runtime.Tokens, "s3cr3t")
+	assert.NotContains(t, cfg.Runtime.Tokens, "admin-token")
+	assert.Empty(t, cfg.LatencySLOs)
+	assert.Equal(t, features.Rollouts{features.LenientQueryParams: 10}, cfg.Runtime.Features)
+}
+
+func TestLoadTOML(t *testing.T) {
+	clearEnv(t)
+
+	path := writeFile(t, "config.toml", `
+[server]
+port = 8443
+http2_cleartext = true
+
+[tls]
+autocert_domains = ["catalog.example.com"]
+redirect_addr = ":80"
+
+[rate_limit]
+limits = "read=20:40,write=5:10"
+
+[fault_injection]
+error_percent = 2.5
+error_statuses = [502, 503]
+`)
+
+	cfg, err := Load(path, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "8443", cfg.Port)
+	assert.True(t, cfg.HTTP2.Cleartext)
+	assert.Equal(t, []string{"catalog.example.com"}, cfg.TLS.AutocertDomains)
+	assert.Equal(t, ":80", cfg.TLS.RedirectAddr)
+	assert.Len(t, cfg.Runtime.RateLimits, 2)
+	assert.Equal(t, middleware.Faults{ErrorPercent: 2.5, ErrorStatuses: []int{502, 503}}, cfg.Faults)
+}
+
+func TestLoadRejectsInvalidConfig(t *testing.T) {
+	clearEnv(t)
+
+	for name, content := range map[string]string{
+		"unknown key":     "server:\n  prot: 8080\n",
+		"unknown section": "queue:\n  size: 10\n",
+		"bad port":        "server:\n  port: http\n",
+		"bad duration":    "server:\n  request_timeout: soon\n",
+		"bad bool":        "server:\n  debug_endpoints: maybe\n",
+		"bad driver":      "database:\n  driver: postgres\n",
+		"bad log format":  "logging:\n  format: xml\n",
+		"bad access log":  "logging:\n  access_log_format: verbose\n",
+		"bad proxy":       "server:\n  trusted_proxies: [lb.internal]\n",
+		"half tls":        "tls:\n  cert_file: cert.pem\n",
+		"bad schedule":    "retention:\n  schedule: sometimes\n",
+		"shared no redis": "cache:\n  shared_ttl: 1m\n",
+		"kafka and nats":  "kafka:\n  brokers: [kafka:9092]\nnats:\n  url: nats://nats:4222\n",
+		"bad conflict":    "consul:\n  conflict_policy: ignore\n",
+		"bad kube policy": "kubernetes:\n  conflict_policy: replace\n",
+		"bad channel":     "notifications:\n  channels: [email=ops@example.com]\n",
+		"smtp no from":    "email:\n  smtp_addr: smtp.example.com:587\n",
+		"slow probes":     "health_checks:\n  interval: 10s\n  timeout: 30s\n",
+		"relative api":    "repositories:\n  github_api_url: api.github.com\n",
+		"unknown flag":    "features:\n  flags: [v3_api=on]\n",
+		"nested list":     "cors:\n  allowed_origins: [[a]]\n",
+		"bad percent":     "fault_injection:\n  error_percent: 150\n",
+		"bad statuses":    "fault_injection:\n  error_percent: 5\n  error_statuses: [404]\n",
+		"bad yaml":        "server: [",
+	} {
+		_, err := Load(writeFile(t, "config.yaml", content), nil)
+		assert.Error(t, err, name)
+	}
+
+	t.Setenv("MAX_BODY_BYTES", "0")
+	_, err := Load("", nil)
+	assert.Error(t, err)
+}
+
+func TestLoadOverridesTakePrecedence(t *testing.T) {
+	clearEnv(t)
+	t.Setenv("PORT", "9090")
+
+	path := writeFile(t, "config.yaml", "server:\n  port: 8081\n")
+	cfg, err := Load(path, map[string]string{"server.port": "7070", "LOG_LEVEL": "debug"})
+	require.NoError(t, err)
+	assert.Equal(t, "7070", cfg.Port)
+	assert.Equal(t, "debug", cfg.Runtime.LogLevel)
+
+	_, err = Load(path, map[string]string{"server.prot": "7070"})
+	assert.Error(t, err)
+}
+
+// staticSecrets serves secrets from a map
+type staticSecrets map[string]string
+
+func (s staticSecrets) Secret(_ context.Context, ref string) ([]byte, error) {
+	secret, ok := s[ref]
+	if !ok {
+		return nil, fmt.Errorf("secret %s not found", ref)
+	}
+	return []byte(secret), nil
+}
+
+func TestLoadResolvesSecretReferences(t *testing.T) {
+	clearEnv(t)
+	resolver := secrets.NewResolver()
+	resolver.Register("vault", staticSecrets{
+		"secret/data/kong-connect": `{"dsn": "/var/lib/kong-connect/services.db", "tokens": "rotated=admin:admin"}`,
+	})
+	previous := secretResolver
+	secretResolver = resolver
+	t.Cleanup(func() { secretResolver = previous })
+
+	t.Setenv("DB_PATH", "vault://secret/data/kong-connect#dsn")
+	path := writeFile(t, "config.yaml", "auth:\n  tokens: vault://secret/data/kong-connect#tokens\n")
+	cfg, err := Load(path, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "/var/lib/kong-connect/services.db", cfg.Database.DSN)
+	assert.Equal(t, []string{"rotated"}, keys(cfg.Runtime.Tokens))
+
+	// Only secret settings are resolved
+	_, err = Load(path, map[string]string{"TLS_CERT_FILE": "vault://secret/data/kong-connect#cert", "TLS_KEY_FILE": "key.pem"})
+	assert.NoError(t, err)
+
+	_, err = Load(path, map[string]string{"DB_PATH": "vault://secret/data/kong-connect#missing"})
+	assert.ErrorContains(t, err, "invalid DB_PATH")
+	_, err = Load(path, map[string]string{"DB_PATH": "vault://secret/data/other#dsn"})
+	assert.ErrorContains(t, err, "secret/data/other not found")
+}
+
+func keys[V any](m map[string]V) []string {
+	list := make([]string, 0, len(m))
+	for key := range m {
+		list = append(list, key)
+	}
+	return list
+}
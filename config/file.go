@@ -0,0 +1,134 @@
+package config
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// readFile reads the settings of a configuration file into values, keyed by
+// their environment variable names
+func readFile(path string, values map[string]string) error {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml", ".toml":
+		return readStructuredFile(path, values)
+	default:
+		return readEnvFile(path, values)
+	}
+}
+
+// readStructuredFile reads a YAML or TOML file of nested sections such as
+//
+//	server:
+//	  port: 8080
+//
+// Lists are joined with commas, so they can be written either way.
+func readStructuredFile(path string, values map[string]string) error {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read config: %v", err)
+	}
+
+	tree := map[string]interface{}{}
+	if strings.EqualFold(filepath.Ext(path), ".toml") {
+		err = toml.Unmarshal(content, &tree)
+	} else {
+		err = yaml.Unmarshal(content, &tree)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to parse %s: %v", path, err)
+	}
+
+	envByKey := make(map[string]string, len(settings))
+	for _, s := range settings {
+		envByKey[s.key] = s.env
+	}
+
+	flat := make(map[string]string)
+	if err := flatten("", tree, flat); err != nil {
+		return fmt.Errorf("%s: %v", path, err)
+	}
+	keys := make([]string, 0, len(flat))
+	for key := range flat {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		env, ok := envByKey[key]
+		if !ok {
+			return fmt.Errorf("%s: unknown setting %q", path, key)
+		}
+		values[env] = flat[key]
+	}
+	return nil
+}
+
+// flatten turns nested sections into dotted keys with string values
+func flatten(prefix string, node interface{}, out map[string]string) error {
+	switch v := node.(type) {
+	case map[string]interface{}:
+		for key, child := range v {
+			if prefix != "" {
+				key = prefix + "." + key
+			}
+			if err := flatten(key, child, out); err != nil {
+				return err
+			}
+		}
+	case []interface{}:
+		items := make([]string, 0, len(v))
+		for _, item := range v {
+			switch item.(type) {
+			case map[string]interface{}, []interface{}:
+				return fmt.Errorf("%s: list items must be plain values", prefix)
+			}
+			items = append(items, fmt.Sprint(item))
+		}
+		out[prefix] = strings.Join(items, ",")
+	case nil:
+		out[prefix] = ""
+	default:
+		out[prefix] = fmt.Sprint(v)
+	}
+	return nil
+}
+
+// readEnvFile reads KEY=VALUE lines into values; blank lines and lines
+// starting with # are ignored, and only known settings are accepted
+func readEnvFile(path string, values map[string]string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open config: %v", err)
+	}
+	defer file.Close()
+
+	known := make(map[string]struct{}, len(settings))
+	for _, s := range settings {
+		known[s.env] = struct{}{}
+	}
+
+	scanner := bufio.NewScanner(file)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		key, value, ok := strings.Cut(text, "=")
+		key = strings.TrimSpace(key)
+		if !ok {
+			return fmt.Errorf("%s:%d: want KEY=VALUE", path, line)
+		}
+		if _, ok := known[key]; !ok {
+			return fmt.Errorf("%s:%d: unknown setting %s", path, line, key)
+		}
+		values[key] = strings.TrimSpace(value)
+	}
+	return scanner.Err()
+}
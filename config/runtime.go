@@ -0,0 +1,51 @@
+package config
+
+import (
+	"fmt"
+	"log/slog"
+
+	"com.kong.connect/features"
+	"com.kong.connect/middleware"
+	"com.kong.connect/ratelimit"
+)
+
+// Defaults of the runtime settings when neither the environment nor the file sets them
+const (
+	DefaultRateLimits = "read=50:100,search=10:20,write=10:20"
+	DefaultAuthTokens = "admin-token=admin:admin,viewer-token=viewer:viewer"
+)
+
+// Runtime holds the settings that can change while the server is running
+type Runtime struct {
+	LogLevel    string
+	CORSOrigins []string
+	RateLimits  map[string]ratelimit.Limit
+	Tokens      map[string]middleware.UserClaims
+	Features    features.Rollouts
+}
+
+// parseRuntime validates LOG_LEVEL, CORS_ALLOWED_ORIGINS, RATE_LIMITS, AUTH_TOKENS and FEATURE_FLAGS
+func parseRuntime(values map[string]string) (*Runtime, error) {
+	cfg := &Runtime{LogLevel: values["LOG_LEVEL"]}
+	if cfg.LogLevel != "" {
+		var level slog.Level
+		if err := level.UnmarshalText([]byte(cfg.LogLevel)); err != nil {
+			return nil, fmt.Errorf("invalid LOG_LEVEL: unknown level %q", cfg.LogLevel)
+		}
+	}
+
+	cfg.CORSOrigins = splitList(values["CORS_ALLOWED_ORIGINS"])
+
+	var err error
+	if cfg.RateLimits, err = ratelimit.ParseLimits(values["RATE_LIMITS"]); err != nil {
+		return nil, fmt.Errorf("invalid RATE_LIMITS: %v", err)
+	}
+	if cfg.Tokens, err = middleware.ParseTokens(values["AUTH_TOKENS"]); err != nil {
+		return nil, fmt.Errorf("invalid AUTH_TOKENS: %v", err)
+	}
+	if cfg.Features, err = features.ParseRollouts(values["FEATURE_FLAGS"]); err != nil {
+		return nil, fmt.Errorf("invalid FEATURE_FLAGS: %v", err)
+	}
+
+	return cfg, nil
+}
@@ -0,0 +1,69 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"com.kong.connect/ratelimit"
+)
+
+// clearEnv unsets every setting for the duration of the test
+func clearEnv(t *testing.T) {
+	t.Helper()
+	for _, s := range settings {
+		t.Setenv(s.env, "")
+	}
+}
+
+// writeFile writes a config file into a temporary directory and returns its path
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestLoadRuntimeSettingsFromEnvFile(t *testing.T) {
+	clearEnv(t)
+	t.Setenv("RATE_LIMITS", "read=1:1")
+
+	path := writeFile(t, "runtime.env", `
+# reloaded on SIGHUP
+LOG_LEVEL=debug
+CORS_ALLOWED_ORIGINS=https://portal.example.com, https://admin.example.com
+RATE_LIMITS=read=5:5
+AUTH_TOKENS=s3cr3t=ci:admin|viewer
+`)
+
+	cfg, err := Load(path, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "debug", cfg.Runtime.LogLevel)
+	assert.Equal(t, []string{"https://portal.example.com", "https://admin.example.com"}, cfg.Runtime.CORSOrigins)
+	// The environment overrides the file
+	assert.Equal(t, map[string]ratelimit.Limit{"read": {Rate: 1, Burst: 1}}, cfg.Runtime.RateLimits)
+	require.Contains(t, cfg.Runtime.Tokens, "s3cr3t")
+	assert.Equal(t, []string{"admin", "viewer"}, cfg.Runtime.Tokens["s3cr3t"].Roles)
+}
+
+func TestLoadRuntimeDefaults(t *testing.T) {
+	clearEnv(t)
+
+	cfg, err := Load("", nil)
+	require.NoError(t, err)
+	assert.Contains(t, cfg.Runtime.RateLimits, "search")
+	assert.Contains(t, cfg.Runtime.Tokens, "admin-token")
+	assert.Empty(t, cfg.Runtime.CORSOrigins)
+}
+
+func TestLoadRuntimeRejectsInvalidSettings(t *testing.T) {
+	clearEnv(t)
+
+	for _, content := range []string{"LOG_LEVEL=loud", "RATE_LIMITS=read", "AUTH_TOKENS=nouser", "UNKNOWN=1", "garbage"} {
+		_, err := Load(writeFile(t, "runtime.env", content), nil)
+		assert.Error(t, err, content)
+	}
+}
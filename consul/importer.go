@@ -0,0 +1,170 @@
+// Package consul imports the services registered in Consul's catalog into
+// the catalog of the default organization. Plain Consul tags become catalog
+// tags and "key=value" tags set the metadata of the service:
+//
+//	owner=payments-team
+//	status=deprecated
+//	description=Card payments
+//	version=2.1.0
+//
+// Other "key=value" tags are ignored. Consul merges the tags of every
+// instance of a service, so instances running different versions import all
+// of them.
+package consul
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"com.kong.connect/audit"
+	"com.kong.connect/domain"
+	"com.kong.connect/logging"
+)
+
+// Source identifies the services created by the importer
+const Source = "consul"
+
+const (
+	// TokenHeader carries the ACL token of the Consul requests
+	TokenHeader = "X-Consul-Token"
+	// requestTimeout bounds the catalog request
+	requestTimeout = 10 * time.Second
+	// maxResponseBytes bounds the catalog read
+	maxResponseBytes = 16 << 20
+)
+
+// Config configures the importer
+type Config struct {
+	// Address is the base URL of the Consul HTTP API, e.g.
+	// http://127.0.0.1:8500; empty disables the import
+	Address    string
+	Token      string
+	Datacenter string
+	// ConflictPolicy is a domain conflict policy applied to catalog services
+	// of the same name the importer did not create
+	ConflictPolicy string
+	Interval       time.Duration
+}
+
+// Catalog creates and updates the imported services
+type Catalog interface {
+	ImportServices(ctx context.Context, source string, services []domain.CatalogService, policy string) (*domain.ImportResult, error)
+}
+
+// Importer reads the services of Consul's catalog into the catalog
+type Importer struct {
+	cfg     Config
+	catalog Catalog
+	client  *http.Client
+	logger  *slog.Logger
+}
+
+// NewImporter creates an importer; a nil client uses http.DefaultClient
+func NewImporter(cfg Config, catalog Catalog, client *http.Client, logger *slog.Logger) *Importer {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &Importer{cfg: cfg, catalog: catalog, client: client, logger: logging.Component(logger, "consul")}
+}
+
+// Import creates and updates the catalog services of every service
+// registered in Consul; it is run by a job every Config.Interval. Services
+// deregistered from Consul are kept.
+func (i *Importer) Import(ctx context.Context) error {
+	registered, err := i.services(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to read the Consul catalog: %v", err)
+	}
+
+	names := make([]string, 0, len(registered))
+	for name := range registered {
+		// Consul registers itself
+		if name != "consul" {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	services := make([]domain.CatalogService, len(names))
+	for n, name := range names {
+		services[n] = catalogService(name, registered[name])
+	}
+
+	ctx = audit.NewContext(ctx, audit.Actor{Principal: Source})
+	result, err := i.catalog.ImportServices(ctx, Source, services, i.cfg.ConflictPolicy)
+	if err != nil {
+		return err
+	}
+	i.logger.InfoContext(ctx, "imported Consul services", "services", len(services),
+		"created", result.Created, "updated", result.Updated, "unchanged", result.Unchanged,
+		"conflicts", len(result.Conflicts), "invalid", len(result.Invalid))
+	if len(result.Conflicts) > 0 {
+		i.logger.WarnContext(ctx, "skipped Consul services already in the catalog",
+			"services", result.Conflicts, "conflict_policy", i.cfg.ConflictPolicy)
+	}
+	return nil
+}
+
+// services returns the tags of every service registered in Consul by name
+func (i *Importer) services(ctx context.Context) (map[string][]string, error) {
+	ctx, cancel := context.WithTimeout(ctx, requestTimeout)
+	defer cancel()
+
+	endpoint := strings.TrimSuffix(i.cfg.Address, "/") + "/v1/catalog/services"
+	if i.cfg.Datacenter != "" {
+		endpoint += "?dc=" + url.QueryEscape(i.cfg.Datacenter)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	if i.cfg.Token != "" {
+		req.Header.Set(TokenHeader, i.cfg.Token)
+	}
+
+	resp, err := i.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return nil, fmt.Errorf("status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	var registered map[string][]string
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxResponseBytes)).Decode(&registered); err != nil {
+		return nil, fmt.Errorf("invalid response: %v", err)
+	}
+	return registered, nil
+}
+
+// catalogService maps a Consul service and its tags to a catalog service
+func catalogService(name string, tags []string) domain.CatalogService {
+	service := domain.CatalogService{Name: name, Tags: []string{}, Versions: []string{}}
+	for _, tag := range tags {
+		key, value, ok := strings.Cut(tag, "=")
+		if !ok {
+			service.Tags = append(service.Tags, tag)
+			continue
+		}
+		switch strings.TrimSpace(key) {
+		case "owner":
+			service.Owner = value
+		case "status":
+			service.Status = value
+		case "description":
+			service.Description = value
+		case "version":
+			service.Versions = append(service.Versions, strings.TrimSpace(value))
+		}
+	}
+	return service
+}
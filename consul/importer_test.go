@@ -0,0 +1,69 @@
+package consul
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"com.kong.connect/audit"
+	"com.kong.connect/domain"
+)
+
+// recordingCatalog keeps the services of the last import
+type recordingCatalog struct {
+	services []domain.CatalogService
+	policy   string
+	actor    audit.Actor
+}
+
+func (c *recordingCatalog) ImportServices(ctx context.Context, source string, services []domain.CatalogService, policy string) (*domain.ImportResult, error) {
+	c.services, c.policy, c.actor = services, policy, audit.FromContext(ctx)
+	return &domain.ImportResult{Created: len(services)}, nil
+}
+
+func TestTagsMapToMetadata(t *testing.T) {
+	service := catalogService("payments", []string{
+		"owner=payments-team", "status=deprecated", "description=Card payments",
+		"version=1.0.0", "version= 1.1.0", "traefik.enable=true", "http", "internal",
+	})
+	assert.Equal(t, domain.CatalogService{
+		Name:        "payments",
+		Description: "Card payments",
+		Status:      "deprecated",
+		Owner:       "payments-team",
+		Tags:        []string{"http", "internal"},
+		Versions:    []string{"1.0.0", "1.1.0"},
+	}, service)
+}
+
+func TestImportReadsTheConsulCatalog(t *testing.T) {
+	consul := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/catalog/services" || r.URL.Query().Get("dc") != "eu-west" || r.Header.Get(TokenHeader) != "secret" {
+			http.Error(w, "ACL not found", http.StatusForbidden)
+			return
+		}
+		w.Write([]byte(`{"consul":[],"orders":["version=2.0.0"],"billing":["owner=finance"]}`))
+	}))
+	defer consul.Close()
+
+	catalog := &recordingCatalog{}
+	cfg := Config{Address: consul.URL + "/", Token: "secret", Datacenter: "eu-west", ConflictPolicy: domain.ConflictMerge}
+	require.NoError(t, NewImporter(cfg, catalog, nil, nil).Import(context.Background()))
+
+	// Consul itself is left out and the services are sorted by name
+	require.Len(t, catalog.services, 2)
+	assert.Equal(t, "billing", catalog.services[0].Name)
+	assert.Equal(t, "finance", catalog.services[0].Owner)
+	assert.Equal(t, []string{"2.0.0"}, catalog.services[1].Versions)
+	assert.Equal(t, domain.ConflictMerge, catalog.policy)
+	assert.Equal(t, Source, catalog.actor.Principal)
+
+	cfg.Token = "wrong"
+	err := NewImporter(cfg, catalog, nil, nil).Import(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "status 403: ACL not found")
+}
@@ -3,125 +3,857 @@ package database
 import (
 	"database/sql"
 	"fmt"
-	"log"
+	"log/slog"
+	"strings"
+	"sync/atomic"
 
-	_ "github.com/mattn/go-sqlite3"
+	"github.com/mattn/go-sqlite3"
+
+	"com.kong.connect/fold"
+	"com.kong.connect/semver"
 )
 
-// DB holds the database connection
-var DB *sql.DB
+// driverName is the SQLite driver with the functions folding text for search,
+// which the triggers keeping the folded columns of services call
+const driverName = "sqlite3_fold"
+
+func init() {
+	sql.Register(driverName, &sqlite3.SQLiteDriver{
+		ConnectHook: func(conn *sqlite3.SQLiteConn) error {
+			if err := conn.RegisterFunc("fold_case", fold.Case, true); err != nil {
+				return err
+			}
+			return conn.RegisterFunc("fold_accents", fold.Accents, true)
+		},
+	})
+}
 
-// InitDB initializes the database connection and creates tables
-func InitDB(dbPath string) error {
-	var err error
-	DB, err = sql.Open("sqlite3", dbPath)
+// logger returns the database component logger, resolved lazily so that it
+// follows the default logger configured at startup
+func logger() *slog.Logger {
+	return slog.Default().With("component", "database")
+}
+
+// InitDB opens the database, creates tables and seeds the sample profile into
+// an empty catalog; the server seeds only when SEED_ON_START is set
+func InitDB(dbPath string) (*sql.DB, error) {
+	db, err := Open(dbPath)
 	if err != nil {
-		return fmt.Errorf("failed to open database: %v", err)
+		return nil, err
+	}
+	if err := Migrate(db); err != nil {
+		db.Close()
+		return nil, err
+	}
+	if _, err := Seed(db); err != nil {
+		db.Close()
+		return nil, err
 	}
 
-	if err = DB.Ping(); err != nil {
-		return fmt.Errorf("failed to ping database: %v", err)
+	logger().Info("database initialized", "path", dbPath)
+	return db, nil
+}
+
+// MemoryPath opens an ephemeral database held in memory, for tests and
+// demos; it may be followed by driver options, as in :memory:?_busy_timeout=0
+const MemoryPath = ":memory:"
+
+// memoryDatabases numbers the in-memory databases, so that each Open of
+// MemoryPath gets a database of its own
+var memoryDatabases atomic.Int64
+
+// dataSourceName returns the data source of dbPath. SQLite gives every
+// connection to :memory: a database of its own, so MemoryPath is opened as a
+// database of the memdb VFS instead, which the connections of the pool share.
+// Unlike a shared cache, it keeps file locking, so writers wait out the busy
+// timeout rather than fail at once with "database table is locked".
+func dataSourceName(dbPath string) string {
+	options, ok := strings.CutPrefix(dbPath, MemoryPath)
+	if !ok || (options != "" && !strings.HasPrefix(options, "?")) {
+		return dbPath
+	}
+	dsn := fmt.Sprintf("file:/kong-connect-%d?vfs=memdb", memoryDatabases.Add(1))
+	if options = strings.TrimPrefix(options, "?"); options != "" {
+		dsn += "&" + options
 	}
+	return dsn
+}
 
-	if err = createTables(); err != nil {
-		return fmt.Errorf("failed to create tables: %v", err)
+// Open opens the database connection without touching the schema. The
+// connection is owned by the caller, which passes it to the repositories and
+// closes it with Close. An in-memory database lives as long as the
+// connection: the pool keeps idle connections open, and the last one to
+// close takes the data with it.
+func Open(dbPath string) (*sql.DB, error) {
+	db, err := sql.Open(driverName, dataSourceName(dbPath))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %v", err)
+	}
+
+	if err = db.Ping(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to ping database: %v", err)
 	}
+	return db, nil
+}
 
-	if err = seedData(); err != nil {
-		return fmt.Errorf("failed to seed data: %v", err)
+// Migrate creates missing tables, indexes and columns
+func Migrate(db *sql.DB) error {
+	if err := createTables(db); err != nil {
+		return fmt.Errorf("failed to create tables: %v", err)
 	}
+	return nil
+}
 
-	log.Println("Database initialized successfully")
+// Close closes the database connection, waiting for in-flight queries to finish
+func Close(db *sql.DB) error {
+	if err := db.Close(); err != nil {
+		return fmt.Errorf("failed to close database: %v", err)
+	}
+	logger().Info("database closed")
 	return nil
 }
 
 // createTables creates the necessary tables
-func createTables() error {
-	serviceTable := `
-	CREATE TABLE IF NOT EXISTS services (
+func createTables(db *sql.DB) error {
+	// Every service, token and audit entry belongs to an organization;
+	// organization 1 holds those of databases created before organizations
+	organizationTable := `
+	CREATE TABLE IF NOT EXISTS organizations (
 		id INTEGER PRIMARY KEY AUTOINCREMENT,
-		name TEXT NOT NULL UNIQUE,
-		description TEXT NOT NULL,
-		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
-		updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+		slug TEXT NOT NULL UNIQUE,
+		name TEXT NOT NULL,
+		created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
 	);`
 
+	if _, err := db.Exec(organizationTable); err != nil {
+		return err
+	}
+	if _, err := db.Exec("INSERT OR IGNORE INTO organizations (id, slug, name) VALUES (1, 'default', 'Default')"); err != nil {
+		return err
+	}
+
+	if _, err := db.Exec(fmt.Sprintf(serviceTable, "services")); err != nil {
+		return err
+	}
+
 	versionTable := `
 	CREATE TABLE IF NOT EXISTS service_versions (
 		id INTEGER PRIMARY KEY AUTOINCREMENT,
 		service_id INTEGER NOT NULL,
 		version TEXT NOT NULL,
+		original_version TEXT,
 		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
 		FOREIGN KEY (service_id) REFERENCES services (id) ON DELETE CASCADE,
 		UNIQUE(service_id, version)
 	);`
 
-	log.Println("Creating services table")
-	if _, err := DB.Exec(serviceTable); err != nil {
+	tagTable := `
+	CREATE TABLE IF NOT EXISTS service_tags (
+		service_id INTEGER NOT NULL,
+		tag TEXT NOT NULL,
+		FOREIGN KEY (service_id) REFERENCES services (id) ON DELETE CASCADE,
+		PRIMARY KEY (service_id, tag)
+	);`
+
+	// Each environment of a service, e.g. prod, runs one of its versions
+	environmentTable := `
+	CREATE TABLE IF NOT EXISTS service_environments (
+		service_id INTEGER NOT NULL,
+		environment TEXT NOT NULL,
+		version_id INTEGER NOT NULL,
+		deployed_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+		FOREIGN KEY (service_id) REFERENCES services (id) ON DELETE CASCADE,
+		FOREIGN KEY (version_id) REFERENCES service_versions (id) ON DELETE CASCADE,
+		PRIMARY KEY (service_id, environment)
+	);`
+
+	if _, err := db.Exec(versionTable); err != nil {
+		return err
+	}
+
+	if _, err := db.Exec(environmentTable); err != nil {
+		return err
+	}
+
+	// The OpenAPI spec of a version as uploaded, with the metadata extracted
+	// from it as JSON
+	specTable := `
+	CREATE TABLE IF NOT EXISTS version_specs (
+		version_id INTEGER PRIMARY KEY,
+		service_id INTEGER NOT NULL,
+		content BLOB NOT NULL,
+		content_type TEXT NOT NULL,
+		metadata TEXT NOT NULL,
+		FOREIGN KEY (version_id) REFERENCES service_versions (id) ON DELETE CASCADE
+	);`
+
+	if _, err := db.Exec(specTable); err != nil {
+		return err
+	}
+
+	auditTable := `
+	CREATE TABLE IF NOT EXISTS audit_logs (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		org_id INTEGER NOT NULL DEFAULT 1,
+		created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+		principal TEXT NOT NULL,
+		action TEXT NOT NULL,
+		resource_type TEXT NOT NULL,
+		resource_id INTEGER NOT NULL,
+		ip TEXT NOT NULL DEFAULT '',
+		request_id TEXT NOT NULL DEFAULT '',
+		before TEXT,
+		after TEXT,
+		changes TEXT NOT NULL DEFAULT '{}'
+	);`
+
+	if _, err := db.Exec(tagTable); err != nil {
+		return err
+	}
+
+	if _, err := db.Exec(auditTable); err != nil {
+		return err
+	}
+
+	// Users and their API tokens are managed through /api/v1/users and
+	// /api/v1/tokens; only a SHA-256 hash of each token is stored
+	userTable := `
+	CREATE TABLE IF NOT EXISTS users (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		username TEXT NOT NULL UNIQUE,
+		roles TEXT NOT NULL,
+		disabled INTEGER NOT NULL DEFAULT 0,
+		created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+	);`
+
+	tokenTable := `
+	CREATE TABLE IF NOT EXISTS api_tokens (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		user_id INTEGER NOT NULL,
+		org_id INTEGER NOT NULL DEFAULT 1,
+		name TEXT NOT NULL DEFAULT '',
+		token_hash TEXT NOT NULL UNIQUE,
+		created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+		revoked_at DATETIME,
+		FOREIGN KEY (user_id) REFERENCES users (id),
+		FOREIGN KEY (org_id) REFERENCES organizations (id)
+	);`
+
+	if _, err := db.Exec(userTable); err != nil {
+		return err
+	}
+
+	if _, err := db.Exec(tokenTable); err != nil {
+		return err
+	}
+
+	// The instance holding an unexpired lease runs the background jobs;
+	// expires_at is in Unix milliseconds
+	leaseTable := `
+	CREATE TABLE IF NOT EXISTS job_leases (
+		name TEXT PRIMARY KEY,
+		holder TEXT NOT NULL,
+		expires_at INTEGER NOT NULL
+	);`
+
+	if _, err := db.Exec(leaseTable); err != nil {
+		return err
+	}
+
+	// Webhooks of an organization receive its change events; events lists the
+	// event types delivered, comma separated, or is empty for all of them
+	webhookTable := `
+	CREATE TABLE IF NOT EXISTS webhooks (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		org_id INTEGER NOT NULL DEFAULT 1,
+		url TEXT NOT NULL,
+		secret TEXT NOT NULL,
+		events TEXT NOT NULL DEFAULT '',
+		created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+		FOREIGN KEY (org_id) REFERENCES organizations (id)
+	);`
+
+	// Each event delivered to a webhook is logged with the outcome of its last
+	// attempt; pending deliveries are retried from next_attempt_at, in Unix
+	// milliseconds
+	deliveryTable := `
+	CREATE TABLE IF NOT EXISTS webhook_deliveries (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		webhook_id INTEGER NOT NULL,
+		event TEXT NOT NULL,
+		payload TEXT NOT NULL,
+		status TEXT NOT NULL DEFAULT 'pending',
+		attempts INTEGER NOT NULL DEFAULT 0,
+		response_code INTEGER NOT NULL DEFAULT 0,
+		error TEXT NOT NULL DEFAULT '',
+		next_attempt_at INTEGER,
+		created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+		delivered_at DATETIME,
+		FOREIGN KEY (webhook_id) REFERENCES webhooks (id) ON DELETE CASCADE
+	);`
+
+	if _, err := db.Exec(webhookTable); err != nil {
+		return err
+	}
+
+	if _, err := db.Exec(deliveryTable); err != nil {
+		return err
+	}
+
+	// With a Kafka or NATS publisher, writes record their change events here
+	// in the same transaction, until they are shipped
+	outboxTable := `
+	CREATE TABLE IF NOT EXISTS event_outbox (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		org_id INTEGER NOT NULL,
+		service_id INTEGER NOT NULL,
+		type TEXT NOT NULL,
+		payload TEXT NOT NULL,
+		created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+	);`
+
+	if _, err := db.Exec(outboxTable); err != nil {
+		return err
+	}
+
+	// Services created by an importer, such as the Consul one, which keeps
+	// them in sync with their source
+	importTable := `
+	CREATE TABLE IF NOT EXISTS service_imports (
+		service_id INTEGER PRIMARY KEY,
+		org_id INTEGER NOT NULL,
+		source TEXT NOT NULL,
+		imported_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+	);`
+
+	if _, err := db.Exec(importTable); err != nil {
+		return err
+	}
+
+	// Email notification settings of users, identified by username so that
+	// static tokens have them too, and the services they subscribed to
+	preferenceTable := `
+	CREATE TABLE IF NOT EXISTS notification_preferences (
+		username TEXT PRIMARY KEY,
+		email TEXT NOT NULL DEFAULT '',
+		deprecations INTEGER NOT NULL DEFAULT 1,
+		ownership_changes INTEGER NOT NULL DEFAULT 1,
+		mentions INTEGER NOT NULL DEFAULT 1,
+		updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+	);`
+
+	subscriptionTable := `
+	CREATE TABLE IF NOT EXISTS service_subscriptions (
+		service_id INTEGER NOT NULL,
+		username TEXT NOT NULL,
+		org_id INTEGER NOT NULL,
+		created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+		PRIMARY KEY (service_id, username)
+	);`
+
+	if _, err := db.Exec(preferenceTable); err != nil {
+		return err
+	}
+	if _, err := db.Exec(subscriptionTable); err != nil {
+		return err
+	}
+	if _, err := db.Exec("CREATE INDEX IF NOT EXISTS idx_service_subscriptions_username ON service_subscriptions (username)"); err != nil {
+		return err
+	}
+
+	// Comments on services; replies point at the first comment of their thread
+	commentTable := `
+	CREATE TABLE IF NOT EXISTS service_comments (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		service_id INTEGER NOT NULL,
+		org_id INTEGER NOT NULL,
+		parent_id INTEGER,
+		author TEXT NOT NULL,
+		body TEXT NOT NULL,
+		mentions TEXT NOT NULL DEFAULT '',
+		created_at DATETIME NOT NULL
+	);`
+	if _, err := db.Exec(commentTable); err != nil {
+		return err
+	}
+	if _, err := db.Exec("CREATE INDEX IF NOT EXISTS idx_service_comments_service ON service_comments (service_id, parent_id)"); err != nil {
+		return err
+	}
+
+	// The sunsets scheduled for services, with version_id 0, and versions.
+	// Times are stored as text so that versions can select them in a
+	// subquery.
+	sunsetTable := `
+	CREATE TABLE IF NOT EXISTS sunsets (
+		service_id INTEGER NOT NULL,
+		version_id INTEGER NOT NULL DEFAULT 0,
+		org_id INTEGER NOT NULL,
+		deprecate_at TEXT NOT NULL DEFAULT '',
+		archive_at TEXT NOT NULL,
+		status TEXT NOT NULL DEFAULT 'active',
+		reminded_days INTEGER NOT NULL DEFAULT 0,
+		PRIMARY KEY (service_id, version_id)
+	);`
+	if _, err := db.Exec(sunsetTable); err != nil {
+		return err
+	}
+
+	// The source repository linked to a service, with the details of its
+	// last refresh
+	sourceRepositoryTable := `
+	CREATE TABLE IF NOT EXISTS service_repositories (
+		service_id INTEGER PRIMARY KEY,
+		org_id INTEGER NOT NULL,
+		url TEXT NOT NULL,
+		provider TEXT NOT NULL,
+		path TEXT NOT NULL,
+		default_branch TEXT NOT NULL DEFAULT '',
+		release_tag TEXT NOT NULL DEFAULT '',
+		release_name TEXT NOT NULL DEFAULT '',
+		release_url TEXT NOT NULL DEFAULT '',
+		release_published_at DATETIME,
+		error TEXT NOT NULL DEFAULT '',
+		refreshed_at DATETIME
+	);`
+	if _, err := db.Exec(sourceRepositoryTable); err != nil {
+		return err
+	}
+
+	// Requests to hand services over to another owner, kept once resolved
+	// as their ownership history
+	transferTable := `
+	CREATE TABLE IF NOT EXISTS ownership_transfers (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		service_id INTEGER NOT NULL,
+		org_id INTEGER NOT NULL,
+		from_owner TEXT NOT NULL,
+		to_owner TEXT NOT NULL,
+		reason TEXT NOT NULL DEFAULT '',
+		requested_by TEXT NOT NULL,
+		status TEXT NOT NULL,
+		resolved_by TEXT NOT NULL DEFAULT '',
+		requested_at DATETIME NOT NULL,
+		resolved_at DATETIME
+	);`
+	if _, err := db.Exec(transferTable); err != nil {
+		return err
+	}
+	if _, err := db.Exec("CREATE INDEX IF NOT EXISTS idx_ownership_transfers_service ON ownership_transfers (service_id, status)"); err != nil {
+		return err
+	}
+
+	// The services users starred, which listings can be limited to
+	favoriteTable := `
+	CREATE TABLE IF NOT EXISTS service_favorites (
+		service_id INTEGER NOT NULL,
+		username TEXT NOT NULL,
+		org_id INTEGER NOT NULL,
+		created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+		PRIMARY KEY (service_id, username)
+	);`
+	if _, err := db.Exec(favoriteTable); err != nil {
+		return err
+	}
+	if _, err := db.Exec("CREATE INDEX IF NOT EXISTS idx_service_favorites_username ON service_favorites (username)"); err != nil {
+		return err
+	}
+
+	// The health check declared by a service with the outcome of its last
+	// probe, and the outcome of every probe until retention purges it
+	healthCheckTable := `
+	CREATE TABLE IF NOT EXISTS service_health_checks (
+		service_id INTEGER PRIMARY KEY,
+		org_id INTEGER NOT NULL,
+		url TEXT NOT NULL,
+		status TEXT NOT NULL DEFAULT 'unknown',
+		latency_ms INTEGER NOT NULL DEFAULT 0,
+		error TEXT NOT NULL DEFAULT '',
+		checked_at DATETIME
+	);`
+
+	healthResultTable := `
+	CREATE TABLE IF NOT EXISTS service_health_results (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		service_id INTEGER NOT NULL,
+		url TEXT NOT NULL,
+		status TEXT NOT NULL,
+		latency_ms INTEGER NOT NULL,
+		error TEXT NOT NULL DEFAULT '',
+		checked_at DATETIME NOT NULL
+	);`
+
+	if _, err := db.Exec(healthCheckTable); err != nil {
+		return err
+	}
+	if _, err := db.Exec(healthResultTable); err != nil {
+		return err
+	}
+	if _, err := db.Exec("CREATE INDEX IF NOT EXISTS idx_service_health_results_service ON service_health_results (service_id)"); err != nil {
+		return err
+	}
+	if _, err := db.Exec("CREATE INDEX IF NOT EXISTS idx_service_health_results_checked_at ON service_health_results (checked_at)"); err != nil {
+		return err
+	}
+
+	// The descriptions of services in other languages, shown to clients
+	// accepting them
+	translationTable := `
+	CREATE TABLE IF NOT EXISTS service_translations (
+		service_id INTEGER NOT NULL,
+		language TEXT NOT NULL,
+		description TEXT NOT NULL,
+		FOREIGN KEY (service_id) REFERENCES services (id) ON DELETE CASCADE,
+		PRIMARY KEY (service_id, language)
+	);`
+	if _, err := db.Exec(translationTable); err != nil {
 		return err
 	}
-	log.Println("Created services table")
 
-	if _, err := DB.Exec(versionTable); err != nil {
+	// The service level objectives of a service, measured by its probe results
+	sloTable := `
+	CREATE TABLE IF NOT EXISTS service_slos (
+		service_id INTEGER PRIMARY KEY,
+		org_id INTEGER NOT NULL,
+		availability_target REAL NOT NULL,
+		latency_target_ms INTEGER NOT NULL DEFAULT 0,
+		updated_at DATETIME NOT NULL
+	);`
+	if _, err := db.Exec(sloTable); err != nil {
+		return err
+	}
+
+	// Daily counts of the detail views of services and of the terms searched
+	usageViewTable := `
+	CREATE TABLE IF NOT EXISTS usage_service_views (
+		org_id INTEGER NOT NULL,
+		service_id INTEGER NOT NULL,
+		day TEXT NOT NULL,
+		views INTEGER NOT NULL,
+		PRIMARY KEY (org_id, service_id, day)
+	);`
+
+	usageSearchTable := `
+	CREATE TABLE IF NOT EXISTS usage_search_terms (
+		org_id INTEGER NOT NULL,
+		term TEXT NOT NULL,
+		day TEXT NOT NULL,
+		searches INTEGER NOT NULL,
+		PRIMARY KEY (org_id, term, day)
+	);`
+
+	if _, err := db.Exec(usageViewTable); err != nil {
+		return err
+	}
+	if _, err := db.Exec(usageSearchTable); err != nil {
+		return err
+	}
+	// Reports and retention purges filter usage by day
+	if _, err := db.Exec("CREATE INDEX IF NOT EXISTS idx_usage_service_views_day ON usage_service_views (day)"); err != nil {
+		return err
+	}
+	if _, err := db.Exec("CREATE INDEX IF NOT EXISTS idx_usage_search_terms_day ON usage_search_terms (day)"); err != nil {
+		return err
+	}
+
+	// Retention purges and the admin query filter audit entries by time
+	if _, err := db.Exec("CREATE INDEX IF NOT EXISTS idx_audit_logs_created_at ON audit_logs (created_at)"); err != nil {
+		return err
+	}
+
+	// Databases created before status and owner existed need the columns added
+	if err := addColumnIfMissing(db, "services", "status", "TEXT NOT NULL DEFAULT 'active'"); err != nil {
+		return err
+	}
+	if err := addColumnIfMissing(db, "services", "owner", "TEXT NOT NULL DEFAULT ''"); err != nil {
+		return err
+	}
+
+	if err := addColumnIfMissing(db, "api_tokens", "org_id", "INTEGER NOT NULL DEFAULT 1"); err != nil {
+		return err
+	}
+	if err := addColumnIfMissing(db, "audit_logs", "org_id", "INTEGER NOT NULL DEFAULT 1"); err != nil {
+		return err
+	}
+	// Preferences saved before mentions existed keep them on
+	if err := addColumnIfMissing(db, "notification_preferences", "mentions", "INTEGER NOT NULL DEFAULT 1"); err != nil {
+		return err
+	}
+	if err := normalizeVersions(db); err != nil {
+		return err
+	}
+	if err := scopeServicesToOrganizations(db); err != nil {
+		return err
+	}
+	if err := createSearchColumns(db); err != nil {
+		return err
+	}
+
+	// Indexes may cover the columns added above
+	if err := createIndexes(db); err != nil {
+		return err
+	}
+
+	if err := createRowCounts(db); err != nil {
 		return err
 	}
 
 	return nil
 }
 
-// seedData inserts sample data based on the UI
-func seedData() error {
-	// Check if data already exists
-	log.Println("Checking seed data")
-	var count int
-	err := DB.QueryRow("SELECT COUNT(*) FROM services").Scan(&count)
+// normalizeVersions adds the original_version column to databases created
+// before versions were normalized on write, and normalizes their versions
+// once, keeping the original of those it changes. A version whose canonical
+// form the service already has, such as "v1.2" next to "1.2.0", is left as is.
+func normalizeVersions(db *sql.DB) error {
+	normalized, err := hasColumn(db, "service_versions", "original_version")
+	if err != nil || normalized {
+		return err
+	}
+
+	logger().Info("normalizing versions")
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("ALTER TABLE service_versions ADD COLUMN original_version TEXT"); err != nil {
+		return err
+	}
+	// The oldest of the versions sharing a canonical form gets it
+	rows, err := tx.Query("SELECT id, version FROM service_versions ORDER BY id")
 	if err != nil {
 		return err
 	}
-	if count > 0 {
-		return nil // Data already exists
+	type original struct {
+		id      int
+		version string
+	}
+	var originals []original
+	for rows.Next() {
+		var o original
+		if err := rows.Scan(&o.id, &o.version); err != nil {
+			rows.Close()
+			return err
+		}
+		if semver.Canonical(o.version) != o.version {
+			originals = append(originals, o)
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
 	}
 
-	services := []struct {
-		name, description string
-		versions          []string
-	}{
-		{"Locate Us", "Lorem ipsum dolor sit amet, consectetur adipiscing elit. Turpis non a, pellentesque ipsum aliquet id...", []string{"1.0.0", "1.1.0", "2.0.0"}},
-		{"Collect Monday", "Lorem ipsum dolor sit amet, consectetur adipiscing elit. Turpis non a, pellentesque ipsum aliquet id...", []string{"1.0.0", "1.2.0", "2.1.0"}},
-		{"Contact Us", "Lorem ipsum dolor sit amet, consectetur adipiscing elit. Turpis non a, pellentesque ipsum aliquet id...", []string{"1.0.0", "1.1.0", "1.2.0"}},
-		{"FX Rates International", "Lorem ipsum dolor sit amet, consectetur adipiscing elit. Turpis non a, pellentesque ipsum aliquet id...", []string{"1.0.0", "2.0.0", "3.0.0"}},
-		{"Notifications", "Lorem ipsum dolor sit amet, consectetur adipiscing elit. Turpis non a, pellentesque ipsum aliquet id...", []string{"1.0.0", "1.1.0", "1.2.0"}},
-		{"Priority Services", "Lorem ipsum dolor sit amet, consectetur adipiscing elit. Turpis non a, pellentesque ipsum aliquet id...", []string{"1.0.0", "2.0.0", "2.1.0"}},
-		{"Reporting", "Lorem ipsum dolor sit amet, consectetur adipiscing elit. Turpis non a, pellentesque ipsum aliquet id...", []string{"1.0.0", "1.1.0", "2.0.0"}},
-		{"Security", "Lorem ipsum dolor sit amet, consectetur adipiscing elit. Turpis non a, pellentesque ipsum aliquet id...", []string{"1.0.0", "1.1.0", "1.2.0"}},
+	for _, o := range originals {
+		canonical := semver.Canonical(o.version)
+		if _, err := tx.Exec(`UPDATE service_versions SET version = ?, original_version = ?
+			WHERE id = ? AND NOT EXISTS (
+				SELECT 1 FROM service_versions other
+				WHERE other.service_id = service_versions.service_id AND other.version = ?
+			)`, canonical, o.version, o.id, canonical); err != nil {
+			return err
+		}
 	}
+	return tx.Commit()
+}
 
-	for _, service := range services {
-		// Insert service
-		result, err := DB.Exec(
-			"INSERT INTO services (name, description) VALUES (?, ?)",
-			service.name, service.description,
-		)
-		if err != nil {
+// serviceTable is the definition of the services table, formatted with its
+// name. Names are unique within an organization.
+const serviceTable = `
+	CREATE TABLE IF NOT EXISTS %s (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		org_id INTEGER NOT NULL DEFAULT 1 REFERENCES organizations (id),
+		name TEXT NOT NULL,
+		description TEXT NOT NULL,
+		status TEXT NOT NULL DEFAULT 'active',
+		owner TEXT NOT NULL DEFAULT '',
+		folded_name TEXT,
+		folded_description TEXT,
+		unaccented_name TEXT,
+		unaccented_description TEXT,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		UNIQUE (org_id, name)
+	);`
+
+// scopeServicesToOrganizations moves the services of a database created
+// before organizations existed to the default organization. SQLite cannot
+// change a UNIQUE constraint in place, so the table is rebuilt; its indexes
+// and triggers are recreated by the steps that follow. Foreign keys are not
+// enforced, so dropping the old table leaves versions and tags in place.
+func scopeServicesToOrganizations(db *sql.DB) error {
+	scoped, err := hasColumn(db, "services", "org_id")
+	if err != nil || scoped {
+		return err
+	}
+
+	logger().Info("moving services to the default organization")
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	statements := []string{
+		fmt.Sprintf(serviceTable, "services_scoped"),
+		`INSERT INTO services_scoped (id, name, description, status, owner, created_at, updated_at)
+		SELECT id, name, description, status, owner, created_at, updated_at FROM services`,
+		// Keeps AUTOINCREMENT from reusing the IDs of deleted services
+		`UPDATE sqlite_sequence SET seq = (SELECT seq FROM sqlite_sequence WHERE name = 'services')
+		WHERE name = 'services_scoped'`,
+		"DROP TABLE services",
+		"ALTER TABLE services_scoped RENAME TO services",
+	}
+	for _, statement := range statements {
+		if _, err := tx.Exec(statement); err != nil {
 			return err
 		}
+	}
+	return tx.Commit()
+}
 
-		serviceID, err := result.LastInsertId()
-		if err != nil {
+// searchColumns are the columns of services searched instead of the name and
+// description: folded with fold.Case, and also stripped of their accents with
+// fold.Accents
+var searchColumns = []string{"folded_name", "folded_description", "unaccented_name", "unaccented_description"}
+
+// createSearchColumns adds the search columns of services and the triggers
+// keeping them, then fills those of the rows written without the triggers,
+// such as by databases created before them
+func createSearchColumns(db *sql.DB) error {
+	for _, column := range searchColumns {
+		if err := addColumnIfMissing(db, "services", column, "TEXT"); err != nil {
 			return err
 		}
+	}
 
-		// Insert versions
-		for _, version := range service.versions {
-			_, err := DB.Exec(
-				"INSERT INTO service_versions (service_id, version) VALUES (?, ?)",
-				serviceID, version,
-			)
-			if err != nil {
-				return err
-			}
+	const fill = `UPDATE services SET
+		folded_name = fold_case(name), folded_description = fold_case(description),
+		unaccented_name = fold_accents(name), unaccented_description = fold_accents(description)`
+	statements := []string{
+		`CREATE TRIGGER IF NOT EXISTS services_search_insert AFTER INSERT ON services
+		BEGIN
+			` + fill + ` WHERE id = NEW.id;
+		END`,
+		`CREATE TRIGGER IF NOT EXISTS services_search_update AFTER UPDATE OF name, description ON services
+		BEGIN
+			` + fill + ` WHERE id = NEW.id;
+		END`,
+		fill + " WHERE folded_name IS NULL",
+	}
+	for _, statement := range statements {
+		if _, err := db.Exec(statement); err != nil {
+			return err
 		}
 	}
+	return nil
+}
+
+// createRowCounts creates the per-organization service counts kept by
+// triggers, which serve the total of unfiltered listings without a COUNT(*)
+// over the table. The counts are recomputed on every migration, so that
+// databases created before the triggers, or changed with them dropped, start
+// out exact.
+func createRowCounts(db *sql.DB) error {
+	statements := []string{
+		`CREATE TABLE IF NOT EXISTS row_counts (
+			name TEXT PRIMARY KEY,
+			count INTEGER NOT NULL
+		)`,
+		`CREATE TRIGGER IF NOT EXISTS services_count_insert AFTER INSERT ON services
+		BEGIN
+			INSERT INTO row_counts (name, count) VALUES ('services:' || NEW.org_id, 1)
+			ON CONFLICT (name) DO UPDATE SET count = count + 1;
+		END`,
+		`CREATE TRIGGER IF NOT EXISTS services_count_delete AFTER DELETE ON services
+		BEGIN
+			UPDATE row_counts SET count = count - 1 WHERE name = 'services:' || OLD.org_id;
+		END`,
+	}
+	for _, statement := range statements {
+		if _, err := db.Exec(statement); err != nil {
+			return err
+		}
+	}
+
+	// In one transaction, so that no write slips in between counting and storing
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	if _, err := tx.Exec("DELETE FROM row_counts WHERE name = 'services' OR name LIKE 'services:%'"); err != nil {
+		return err
+	}
+	if _, err := tx.Exec("INSERT INTO row_counts (name, count) SELECT 'services:' || org_id, COUNT(*) FROM services GROUP BY org_id"); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// indexes back the sorting, filtering and grouping of listings, stats and
+// version pages, which are scoped to an organization. Listings by name need
+// none: the UNIQUE (org_id, name) constraint is indexed, and so is
+// UNIQUE(service_id, version), but version pages are ordered by creation time
+// within a service.
+var indexes = []string{
+	"CREATE INDEX IF NOT EXISTS idx_services_org_created_at ON services (org_id, created_at)",
+	"CREATE INDEX IF NOT EXISTS idx_services_org_updated_at ON services (org_id, updated_at)",
+	"CREATE INDEX IF NOT EXISTS idx_services_org_status ON services (org_id, status)",
+	"CREATE INDEX IF NOT EXISTS idx_service_versions_service_id_created_at ON service_versions (service_id, created_at)",
+	"CREATE INDEX IF NOT EXISTS idx_service_environments_version_id ON service_environments (version_id)",
+	"CREATE INDEX IF NOT EXISTS idx_webhooks_org_id ON webhooks (org_id)",
+	"CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_webhook_id ON webhook_deliveries (webhook_id, id)",
+	"CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_next_attempt_at ON webhook_deliveries (next_attempt_at)",
+}
 
+// createIndexes creates the missing indexes, also on databases created before
+// they existed
+func createIndexes(db *sql.DB) error {
+	for _, index := range indexes {
+		if _, err := db.Exec(index); err != nil {
+			return err
+		}
+	}
 	return nil
 }
+
+// addColumnIfMissing adds a column to an existing table unless it is already present
+func addColumnIfMissing(db *sql.DB, table, column, definition string) error {
+	present, err := hasColumn(db, table, column)
+	if err != nil || present {
+		return err
+	}
+
+	logger().Info("adding column", "table", table, "column", column)
+	_, err = db.Exec(fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", table, column, definition))
+	return err
+}
+
+// hasColumn reports whether a table has a column
+func hasColumn(db *sql.DB, table, column string) (bool, error) {
+	rows, err := db.Query(fmt.Sprintf("PRAGMA table_info(%s)", table))
+	if err != nil {
+		return false, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			cid        int
+			name, typ  string
+			notNull    int
+			defaultVal sql.NullString
+			primaryKey int
+		)
+		if err := rows.Scan(&cid, &name, &typ, &notNull, &defaultVal, &primaryKey); err != nil {
+			return false, err
+		}
+		if name == column {
+			return true, nil
+		}
+	}
+	return false, rows.Err()
+}
@@ -0,0 +1,129 @@
+package database
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"strings"
+)
+
+// GenerateOptions shapes a synthetic catalog
+type GenerateOptions struct {
+	// Services is the number of services to generate
+	Services int
+	// MaxVersions bounds the versions of a service; most services have few
+	MaxVersions int
+	// Seed makes the catalog reproducible: the same options generate the
+	// same services
+	Seed uint64
+}
+
+// Word lists of the generated names. Earlier words are picked more often, so
+// that some prefixes are shared by many services, as in real catalogs.
+var (
+	generatedDomains = []string{
+		"Payments", "Billing", "Orders", "Customers", "Accounts", "Identity", "Search", "Inventory",
+		"Shipping", "Notifications", "Pricing", "Catalog", "Checkout", "Reporting", "Analytics", "Fraud",
+		"Loyalty", "Reviews", "Recommendations", "Ledger", "Invoices", "Subscriptions", "Refunds", "Tax",
+		"Compliance", "Audit", "Onboarding", "Documents", "Messaging", "Scheduling", "Maps", "Media",
+	}
+	generatedKinds      = []string{"API", "Service", "Gateway", "Worker", "Sync", "Events", "Admin", "Export", "Webhooks", "Portal"}
+	generatedQualifiers = []string{"", "", "", "", "EU", "US", "APAC", "Internal", "Legacy", "Partner", "Mobile", "Batch"}
+	generatedOwners     = []string{"platform-team", "payments-team", "web-team", "data-team", "identity-team", "growth-team", "ops-team", "mobile-team"}
+	generatedTags       = []string{"public", "internal", "payments", "pii", "beta", "analytics", "messaging", "critical", "partner", "batch"}
+	generatedVerbs      = []string{"Manages", "Exposes", "Synchronizes", "Publishes", "Validates", "Aggregates", "Tracks", "Serves"}
+	generatedObjects    = []string{"records", "events", "requests", "reports", "settings", "lookups", "notifications", "exports"}
+)
+
+// Generate returns a synthetic catalog for trying pagination, search and
+// query plans at scale. Names combine a business domain, a kind and an
+// optional qualifier, skewed towards the first words of each list; names that
+// repeat get a numeric suffix, so every name is unique.
+func Generate(opts GenerateOptions) ([]SeedService, error) {
+	if opts.Services < 0 {
+		return nil, fmt.Errorf("invalid number of services: %d", opts.Services)
+	}
+	if opts.MaxVersions < 0 {
+		return nil, fmt.Errorf("invalid number of versions: %d", opts.MaxVersions)
+	}
+
+	random := rand.New(rand.NewPCG(opts.Seed, opts.Seed))
+	names := make(map[string]int, opts.Services)
+	services := make([]SeedService, opts.Services)
+	for i := range services {
+		domain := skewed(random, generatedDomains)
+		kind := skewed(random, generatedKinds)
+
+		parts := []string{domain, kind}
+		if qualifier := skewed(random, generatedQualifiers); qualifier != "" {
+			parts = append(parts, qualifier)
+		}
+		name := strings.Join(parts, " ")
+		names[name]++
+		if count := names[name]; count > 1 {
+			name = fmt.Sprintf("%s %d", name, count)
+		}
+
+		services[i] = SeedService{
+			Name:        name,
+			Description: fmt.Sprintf("%s %s %s for the %s domain.", skewed(random, generatedVerbs), strings.ToLower(domain), skewed(random, generatedObjects), strings.ToLower(domain)),
+			Status:      generatedStatus(random),
+			Owner:       skewed(random, generatedOwners),
+			Tags:        generatedTagSet(random),
+			Versions:    generatedVersions(random, opts.MaxVersions),
+		}
+	}
+	return services, nil
+}
+
+// skewed picks a word with a probability decreasing with its position
+func skewed(random *rand.Rand, words []string) string {
+	// The minimum of two uniform picks favours low indexes
+	return words[min(random.IntN(len(words)), random.IntN(len(words)))]
+}
+
+// generatedStatus is active for most services
+func generatedStatus(random *rand.Rand) string {
+	switch n := random.IntN(100); {
+	case n < 5:
+		return "archived"
+	case n < 15:
+		return "deprecated"
+	default:
+		return "active"
+	}
+}
+
+// generatedTagSet returns up to three distinct tags
+func generatedTagSet(random *rand.Rand) []string {
+	var tags []string
+	for range random.IntN(4) {
+		tag := skewed(random, generatedTags)
+		if !slices.Contains(tags, tag) {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+// generatedVersions returns between one and maxVersions ascending versions
+func generatedVersions(random *rand.Rand, maxVersions int) []string {
+	if maxVersions == 0 {
+		return nil
+	}
+	count := 1 + min(random.IntN(maxVersions), random.IntN(maxVersions))
+	versions := make([]string, count)
+	major, minor, patch := 1, 0, 0
+	for i := range versions {
+		versions[i] = fmt.Sprintf("%d.%d.%d", major, minor, patch)
+		switch n := random.IntN(10); {
+		case n == 0:
+			major, minor, patch = major+1, 0, 0
+		case n < 5:
+			minor, patch = minor+1, 0
+		default:
+			patch++
+		}
+	}
+	return versions
+}
@@ -0,0 +1,130 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+
+	"com.kong.connect/semver"
+)
+
+// SampleProfile is the seed profile of the sample catalog shown in the UI mockups
+const SampleProfile = "sample"
+
+// SeedService is a service inserted when seeding, with its tags and versions
+type SeedService struct {
+	Name        string
+	Description string
+	Status      string
+	Owner       string
+	Tags        []string
+	Versions    []string
+}
+
+// profiles are the built-in seed profiles by name
+var profiles = map[string][]SeedService{
+	SampleProfile: {
+		{Name: "Locate Us", Description: loremIpsum, Owner: "web-team", Versions: []string{"1.0.0", "1.1.0", "2.0.0"}, Tags: []string{"public", "maps"}},
+		{Name: "Collect Monday", Description: loremIpsum, Owner: "payments-team", Versions: []string{"1.0.0", "1.2.0", "2.1.0"}, Tags: []string{"payments"}},
+		{Name: "Contact Us", Description: loremIpsum, Owner: "web-team", Versions: []string{"1.0.0", "1.1.0", "1.2.0"}, Tags: []string{"public"}},
+		{Name: "FX Rates International", Description: loremIpsum, Owner: "payments-team", Versions: []string{"1.0.0", "2.0.0", "3.0.0"}, Tags: []string{"payments", "public"}},
+		{Name: "Notifications", Description: loremIpsum, Owner: "platform-team", Versions: []string{"1.0.0", "1.1.0", "1.2.0"}, Tags: []string{"messaging"}},
+		{Name: "Priority Services", Description: loremIpsum, Owner: "platform-team", Versions: []string{"1.0.0", "2.0.0", "2.1.0"}},
+		{Name: "Reporting", Description: loremIpsum, Owner: "data-team", Versions: []string{"1.0.0", "1.1.0", "2.0.0"}, Tags: []string{"analytics"}},
+		{Name: "Security", Description: loremIpsum, Owner: "platform-team", Versions: []string{"1.0.0", "1.1.0", "1.2.0"}, Tags: []string{"internal"}},
+	},
+}
+
+const loremIpsum = "Lorem ipsum dolor sit amet, consectetur adipiscing elit. Turpis non a, pellentesque ipsum aliquet id..."
+
+// Profiles lists the names of the built-in seed profiles
+func Profiles() []string {
+	names := make([]string, 0, len(profiles))
+	for name := range profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Profile returns the services of a built-in seed profile
+func Profile(name string) ([]SeedService, error) {
+	services, ok := profiles[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown seed profile %q, available profiles: %s", name, strings.Join(Profiles(), ", "))
+	}
+	return services, nil
+}
+
+// Seed inserts the sample profile when the catalog is empty and reports whether it did
+func Seed(db *sql.DB) (bool, error) {
+	return SeedServices(db, profiles[SampleProfile])
+}
+
+// SeedServices inserts services when the catalog is empty and reports whether
+// it did. Services are inserted in one transaction, so a failed seed leaves
+// the catalog empty and can be retried.
+func SeedServices(db *sql.DB, services []SeedService) (bool, error) {
+	seeded, err := seedServices(db, services)
+	if err != nil {
+		return false, fmt.Errorf("failed to seed data: %v", err)
+	}
+	return seeded, nil
+}
+
+func seedServices(db *sql.DB, services []SeedService) (bool, error) {
+	tx, err := db.Begin()
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	var count int
+	if err := tx.QueryRow("SELECT COUNT(*) FROM services").Scan(&count); err != nil {
+		return false, err
+	}
+	if count > 0 {
+		return false, nil // Data already exists
+	}
+	logger().Info("seeding catalog", "services", len(services))
+
+	for _, service := range services {
+		status := service.Status
+		if status == "" {
+			status = "active"
+		}
+		result, err := tx.Exec(
+			"INSERT INTO services (name, description, status, owner) VALUES (?, ?, ?, ?)",
+			service.Name, service.Description, status, service.Owner,
+		)
+		if err != nil {
+			return false, fmt.Errorf("service %q: %v", service.Name, err)
+		}
+
+		serviceID, err := result.LastInsertId()
+		if err != nil {
+			return false, err
+		}
+
+		for _, version := range service.Versions {
+			// Versions are stored canonical, like those created through the API
+			var original sql.NullString
+			if canonical := semver.Canonical(version); canonical != version {
+				original = sql.NullString{String: version, Valid: true}
+				version = canonical
+			}
+			if _, err := tx.Exec("INSERT INTO service_versions (service_id, version, original_version) VALUES (?, ?, ?)", serviceID, version, original); err != nil {
+				return false, fmt.Errorf("service %q: version %s: %v", service.Name, version, err)
+			}
+		}
+
+		for _, tag := range service.Tags {
+			if _, err := tx.Exec("INSERT INTO service_tags (service_id, tag) VALUES (?, ?)", serviceID, tag); err != nil {
+				return false, fmt.Errorf("service %q: tag %s: %v", service.Name, tag, err)
+			}
+		}
+	}
+
+	return true, tx.Commit()
+}
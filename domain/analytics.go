@@ -0,0 +1,62 @@
+package domain
+
+// AnalyticsDayFormat is the format of the days usage is counted by, in UTC
+const AnalyticsDayFormat = "2006-01-02"
+
+// Usage holds the views and searches counted since usage was last stored
+type Usage struct {
+	Views    []ServiceViewCount
+	Searches []SearchTermCount
+}
+
+// ServiceViewCount counts the detail views of a service during a day
+type ServiceViewCount struct {
+	OrgID     int
+	ServiceID int
+	Day       string
+	Views     int64
+}
+
+// SearchTermCount counts the searches of a normalized term in an
+// organization during a day
+type SearchTermCount struct {
+	OrgID    int
+	Term     string
+	Day      string
+	Searches int64
+}
+
+// AnalyticsQuery selects the days an analytics report covers, the last of
+// them today, and how many entries each list holds
+type AnalyticsQuery struct {
+	Days  int
+	Limit int
+}
+
+// AnalyticsReport represents how the catalog of the organization was used
+// between two days, both included
+type AnalyticsReport struct {
+	Since       string         `json:"since"`
+	Until       string         `json:"until"`
+	TopServices []ServiceViews `json:"top_services"`
+	// TopSearches are the terms searched most
+	TopSearches []SearchTermStats `json:"top_searches"`
+	// TrendingSearches are the terms whose searches grew most since the as
+	// many days before
+	TrendingSearches []SearchTermStats `json:"trending_searches"`
+}
+
+// ServiceViews counts the detail views of a service
+type ServiceViews struct {
+	ServiceID   int    `json:"service_id"`
+	ServiceName string `json:"service_name"`
+	Views       int64  `json:"views"`
+}
+
+// SearchTermStats counts the searches of a term during the days of a report
+// and during as many days before
+type SearchTermStats struct {
+	Term             string `json:"term"`
+	Searches         int64  `json:"searches"`
+	PreviousSearches int64  `json:"previous_searches"`
+}
@@ -0,0 +1,69 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Audited actions
+const (
+	AuditActionCreate = "create"
+	AuditActionUpdate = "update"
+	AuditActionDelete = "delete"
+)
+
+// Audited resource types
+const (
+	AuditResourceService = "service"
+	AuditResourceVersion = "version"
+	AuditResourceUser    = "user"
+	AuditResourceToken   = "token"
+	AuditResourceOrg     = "organization"
+	AuditResourceWebhook = "webhook"
+	// AuditResourceTransfer is an ownership transfer of a service
+	AuditResourceTransfer = "ownership_transfer"
+	// AuditResourceComment is a comment on a service
+	AuditResourceComment = "comment"
+)
+
+// AuditChange holds the old and new value of a single field
+type AuditChange struct {
+	Before interface{} `json:"before"`
+	After  interface{} `json:"after"`
+}
+
+// AuditEntry records a single mutation of the catalog
+type AuditEntry struct {
+	ID           int                    `json:"id"`
+	Timestamp    time.Time              `json:"timestamp"`
+	Principal    string                 `json:"principal"`
+	Action       string                 `json:"action"`
+	ResourceType string                 `json:"resource_type"`
+	ResourceID   int                    `json:"resource_id"`
+	IP           string                 `json:"ip"`
+	RequestID    string                 `json:"request_id,omitempty"`
+	Before       json.RawMessage        `json:"before,omitempty"`
+	After        json.RawMessage        `json:"after,omitempty"`
+	Changes      map[string]AuditChange `json:"changes"`
+}
+
+// AuditQuery represents the filters for listing audit entries
+type AuditQuery struct {
+	Principal    string    `json:"principal"`
+	Action       string    `json:"action"`
+	ResourceType string    `json:"resource_type"`
+	ResourceID   int       `json:"resource_id"`
+	Since        time.Time `json:"since"`
+	Until        time.Time `json:"until"`
+	Page         int       `json:"page"`
+	PageSize     int       `json:"page_size"`
+}
+
+// AuditListResponse represents one page of audit entries, newest first
+type AuditListResponse struct {
+	Entries    []AuditEntry `json:"entries"`
+	Total      int          `json:"total"`
+	Page       int          `json:"page"`
+	PageSize   int          `json:"page_size"`
+	TotalPages int          `json:"total_pages"`
+}
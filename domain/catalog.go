@@ -0,0 +1,45 @@
+package domain
+
+// CatalogDocument is the desired state of the whole catalog, as applied by
+// POST /api/v1/catalog:apply. It has the format of catalogctl export.
+type CatalogDocument struct {
+	Services []CatalogService `json:"services" yaml:"services"`
+}
+
+// CatalogService is the desired state of a service, matched by name
+type CatalogService struct {
+	Name        string   `json:"name" yaml:"name"`
+	Description string   `json:"description" yaml:"description"`
+	Status      string   `json:"status" yaml:"status"`
+	Owner       string   `json:"owner" yaml:"owner"`
+	Tags        []string `json:"tags" yaml:"tags"`
+	Versions    []string `json:"versions" yaml:"versions"`
+}
+
+// Catalog apply actions
+const (
+	CatalogActionCreate = "create"
+	CatalogActionUpdate = "update"
+	CatalogActionDelete = "delete"
+)
+
+// CatalogChange is the change of one service needed to reach the desired state
+type CatalogChange struct {
+	Action    string `json:"action"`
+	Service   string `json:"service"`
+	ServiceID int    `json:"service_id,omitempty"`
+	// Fields lists the changed fields of updated services
+	Fields          map[string]AuditChange `json:"fields,omitempty"`
+	VersionsAdded   []string               `json:"versions_added,omitempty"`
+	VersionsRemoved []string               `json:"versions_removed,omitempty"`
+}
+
+// CatalogApplyResult lists the changes of an apply in document order, followed by deletions
+type CatalogApplyResult struct {
+	DryRun    bool            `json:"dry_run"`
+	Changes   []CatalogChange `json:"changes"`
+	Created   int             `json:"created"`
+	Updated   int             `json:"updated"`
+	Deleted   int             `json:"deleted"`
+	Unchanged int             `json:"unchanged"`
+}
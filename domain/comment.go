@@ -0,0 +1,44 @@
+package domain
+
+import "time"
+
+// Comment is a message about a service, e.g. a question of a consumer to its
+// owners. Comments without a parent start a thread; replies belong to the
+// thread of the comment they answer.
+type Comment struct {
+	ID        int `json:"id"`
+	ServiceID int `json:"service_id"`
+	// ParentID is the first comment of the thread of a reply
+	ParentID *int   `json:"parent_id,omitempty"`
+	Author   string `json:"author"`
+	Body     string `json:"body"`
+	// Mentions are the users mentioned in the body as @username
+	Mentions  []string  `json:"mentions"`
+	CreatedAt time.Time `json:"created_at"`
+	// Replies are the replies to the first comment of a thread, oldest first
+	Replies []Comment `json:"replies,omitempty"`
+}
+
+// CommentInput represents the writable fields of a comment
+type CommentInput struct {
+	Body string `json:"body"`
+	// ParentID is the comment replied to, if any
+	ParentID *int `json:"parent_id,omitempty"`
+}
+
+// CommentQuery selects a page of the threads of a service
+type CommentQuery struct {
+	ServiceID int
+	Page      int
+	PageSize  int
+}
+
+// CommentListResponse represents one page of the threads of a service, the
+// most recently started first
+type CommentListResponse struct {
+	Comments   []Comment `json:"comments"`
+	Total      int       `json:"total"`
+	Page       int       `json:"page"`
+	PageSize   int       `json:"page_size"`
+	TotalPages int       `json:"total_pages"`
+}
@@ -0,0 +1,53 @@
+package domain
+
+import "time"
+
+// Change event types published when the catalog is modified
+const (
+	EventServiceCreated = "service.created"
+	EventServiceUpdated = "service.updated"
+	EventServiceDeleted = "service.deleted"
+	EventVersionCreated = "version.created"
+	EventVersionDeleted = "version.deleted"
+	// A version was deployed to an environment, or an environment stopped
+	// running the service
+	EventVersionDeployed   = "version.deployed"
+	EventVersionUndeployed = "version.undeployed"
+	// The OpenAPI spec of a version was uploaded, replaced or removed
+	EventVersionSpecUpdated = "version.spec_updated"
+	// A probe of the health check of a service changed its status
+	EventServiceHealthChanged = "service.health_changed"
+	// The sunset of a service or a version is SunsetReminderDays away
+	EventServiceSunsetReminder = "service.sunset_reminder"
+	EventVersionSunsetReminder = "version.sunset_reminder"
+	// The sunset of a version was scheduled, cancelled, or deprecated or
+	// archived the version
+	EventVersionSunsetUpdated = "version.sunset_updated"
+)
+
+// ChangeEvent describes a single modification of the catalog
+type ChangeEvent struct {
+	Type string `json:"type"`
+	// OrgID is the organization of the service; events are only delivered
+	// within it
+	OrgID     int             `json:"org_id"`
+	ServiceID int             `json:"service_id"`
+	Tags      []string        `json:"tags"`
+	Service   *Service        `json:"service,omitempty"`
+	Version   *ServiceVersion `json:"version,omitempty"`
+	// Previous is the service before a service.updated change
+	Previous  *Service  `json:"previous,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// OutboxEvent is a change event recorded in the same transaction as its
+// write, waiting to be shipped to the data platform
+type OutboxEvent struct {
+	ID        int
+	OrgID     int
+	ServiceID int
+	Type      string
+	// Payload is the ChangeEvent encoded as JSON
+	Payload   []byte
+	CreatedAt time.Time
+}
@@ -0,0 +1,65 @@
+package domain
+
+import "time"
+
+// Health statuses of a service with a health check
+const (
+	// HealthUnknown is the status of a health check not probed yet
+	HealthUnknown = "unknown"
+	HealthUp      = "up"
+	HealthDown    = "down"
+)
+
+// ServiceHealth is the health check of a service with the outcome of its
+// last probe
+type ServiceHealth struct {
+	URL    string `json:"url"`
+	Status string `json:"status"`
+	// LatencyMS is how long the last probe took to answer, in milliseconds
+	LatencyMS int64      `json:"latency_ms"`
+	Error     string     `json:"error,omitempty"`
+	CheckedAt *time.Time `json:"checked_at,omitempty"`
+}
+
+// HealthCheckInput declares the URL probing a service. The service is up
+// while the URL answers GET requests with a 2xx status.
+type HealthCheckInput struct {
+	URL string `json:"url"`
+}
+
+// HealthCheck is a health check to probe, in any organization
+type HealthCheck struct {
+	OrgID     int
+	ServiceID int
+	URL       string
+}
+
+// HealthResult is the outcome of a probe of a health check
+type HealthResult struct {
+	ServiceID int       `json:"service_id"`
+	URL       string    `json:"url"`
+	Status    string    `json:"status"`
+	LatencyMS int64     `json:"latency_ms"`
+	Error     string    `json:"error,omitempty"`
+	CheckedAt time.Time `json:"checked_at"`
+}
+
+// HealthHistoryQuery selects a page of the probe results of a service
+type HealthHistoryQuery struct {
+	ServiceID int
+	// Since, when set, drops the results of probes before it
+	Since    time.Time
+	Page     int
+	PageSize int
+}
+
+// HealthHistoryResponse represents the current health of a service and one
+// page of its probe results, newest first
+type HealthHistoryResponse struct {
+	Health     *ServiceHealth `json:"health"`
+	Results    []HealthResult `json:"results"`
+	Total      int            `json:"total"`
+	Page       int            `json:"page"`
+	PageSize   int            `json:"page_size"`
+	TotalPages int            `json:"total_pages"`
+}
@@ -0,0 +1,28 @@
+package domain
+
+// Import conflict policies decide what an import does with a catalog service
+// of the same name that it did not create. The services it created are
+// always kept in sync with their source.
+const (
+	// ConflictSkip leaves the service as it is
+	ConflictSkip = "skip"
+	// ConflictMerge adds the imported tags and versions, keeping the fields
+	ConflictMerge = "merge"
+	// ConflictOverwrite takes the service over: it is then synced like the
+	// services the import created
+	ConflictOverwrite = "overwrite"
+)
+
+// ConflictPolicies lists the valid conflict policies
+var ConflictPolicies = []string{ConflictSkip, ConflictMerge, ConflictOverwrite}
+
+// ImportResult counts the services of an import by outcome. Conflicts names
+// the services left alone under ConflictSkip, Invalid the imported services
+// that are not valid catalog services.
+type ImportResult struct {
+	Created   int      `json:"created"`
+	Updated   int      `json:"updated"`
+	Unchanged int      `json:"unchanged"`
+	Conflicts []string `json:"conflicts"`
+	Invalid   []string `json:"invalid"`
+}
@@ -9,16 +9,48 @@ type Service struct {
 	ID          int       `json:"id" db:"id"`
 	Name        string    `json:"name" db:"name"`
 	Description string    `json:"description" db:"description"`
+	Status      string    `json:"status" db:"status"`
+	Owner       string    `json:"owner" db:"owner"`
+	Tags        []string  `json:"tags"`
 	CreatedAt   time.Time `json:"created_at" db:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
+	// Health is the health check of the service and its current status, if
+	// the service declared one
+	Health *ServiceHealth `json:"health,omitempty"`
+	// SLO holds the service level objectives of the service, if any
+	SLO *ServiceSLO `json:"slo,omitempty"`
+	// Sunset schedules the deprecation and archival of the service, if any
+	Sunset *Sunset `json:"sunset,omitempty"`
+	// Repository is the repository of the source code of the service, if
+	// linked
+	Repository *SourceRepository `json:"repository,omitempty"`
+	// Translations holds the description in other languages by language,
+	// if translated
+	Translations map[string]string `json:"translations,omitempty"`
 }
 
+// Service statuses
+const (
+	StatusActive     = "active"
+	StatusDeprecated = "deprecated"
+	StatusArchived   = "archived"
+)
+
 // ServiceVersion represents a version of a service
 type ServiceVersion struct {
-	ID        int       `json:"id" db:"id"`
-	ServiceID int       `json:"service_id" db:"service_id"`
-	Version   string    `json:"version" db:"version"`
-	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	ID        int    `json:"id" db:"id"`
+	ServiceID int    `json:"service_id" db:"service_id"`
+	Version   string `json:"version" db:"version"`
+	// OriginalVersion is the version as it was submitted, such as "v1.2",
+	// when Version is its canonical form
+	OriginalVersion string    `json:"original_version,omitempty" db:"original_version"`
+	CreatedAt       time.Time `json:"created_at" db:"created_at"`
+	// Environments the version is deployed to, in alphabetical order
+	Environments []string `json:"environments,omitempty"`
+	// Spec describes the OpenAPI spec attached to the version, if any
+	Spec *SpecMetadata `json:"spec,omitempty"`
+	// Sunset schedules the deprecation and archival of the version, if any
+	Sunset *Sunset `json:"sunset,omitempty"`
 }
 
 // ServiceWithVersions represents a service with its versions
@@ -38,9 +70,42 @@ type ServiceListResponse struct {
 
 // ServiceQuery represents query parameters for filtering and sorting services
 type ServiceQuery struct {
-	Search   string `json:"search"`
-	SortBy   string `json:"sort_by"`  // name, created_at, updated_at
-	SortDir  string `json:"sort_dir"` // asc, desc
-	Page     int    `json:"page"`
-	PageSize int    `json:"page_size"`
+	Search string `json:"search"`
+	// Environment limits the listing to services deployed to it
+	Environment string `json:"environment"`
+	// HasVersions, when set, limits the listing to services with versions,
+	// or to those without any; MinVersions, unless 0, to services with at
+	// least that many
+	HasVersions *bool  `json:"has_versions,omitempty"`
+	MinVersions int    `json:"min_versions,omitempty"`
+	SortBy      string `json:"sort_by"`  // name, created_at, updated_at
+	SortDir     string `json:"sort_dir"` // asc, desc
+	Page        int    `json:"page"`
+	PageSize    int    `json:"page_size"`
+
+	// Favorites limits the listing to the services User starred
+	Favorites bool   `json:"favorites"`
+	User      string `json:"user"`
+}
+
+// ServiceInput represents the writable fields of a service for create and update requests
+type ServiceInput struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Status      string   `json:"status"`
+	Owner       string   `json:"owner"`
+	Tags        []string `json:"tags"`
+}
+
+// VersionInput represents the writable fields of a service version
+type VersionInput struct {
+	Version string `json:"version"`
+	// Original is the version as submitted, when Version is its canonical
+	// form; the service sets it
+	Original string `json:"-"`
+}
+
+// DeploymentInput names the version an environment of a service runs
+type DeploymentInput struct {
+	Version string `json:"version"`
 }
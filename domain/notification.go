@@ -0,0 +1,45 @@
+package domain
+
+import "time"
+
+// Email notifications, which users can turn off in their preferences
+const (
+	// NotifyDeprecation is sent when a service becomes deprecated
+	NotifyDeprecation = "deprecation"
+	// NotifyOwnershipChange is sent when a service changes owner
+	NotifyOwnershipChange = "ownership_change"
+	// NotifyMention is sent when a comment mentions a user
+	NotifyMention = "mention"
+	// NotifySunset is sent ahead of the sunset of a service or a version;
+	// users receive it along with deprecations
+	NotifySunset = "sunset"
+)
+
+// NotificationPreferences are the email notification settings of a user.
+// Users receive the notifications of the services they own or subscribed to,
+// and of the comments mentioning them, at Email; without an email address
+// they receive none.
+type NotificationPreferences struct {
+	Email            string `json:"email"`
+	Deprecations     bool   `json:"deprecations"`
+	OwnershipChanges bool   `json:"ownership_changes"`
+	Mentions         bool   `json:"mentions"`
+}
+
+// Subscription is a service a user receives the notifications of
+type Subscription struct {
+	ServiceID   int       `json:"service_id"`
+	ServiceName string    `json:"service_name"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// SubscriptionListResponse represents the response for listing subscriptions
+type SubscriptionListResponse struct {
+	Subscriptions []Subscription `json:"subscriptions"`
+}
+
+// EmailRecipient is a user receiving a notification
+type EmailRecipient struct {
+	Username string
+	Email    string
+}
@@ -0,0 +1,24 @@
+package domain
+
+import "time"
+
+// Organization owns a catalog of services and the tokens acting on it; the
+// organizations of a deployment don't see each other's services
+type Organization struct {
+	ID int `json:"id"`
+	// Slug identifies the organization in the X-Org header
+	Slug      string    `json:"slug"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// OrganizationInput represents the fields of an organization create request
+type OrganizationInput struct {
+	Slug string `json:"slug"`
+	Name string `json:"name"`
+}
+
+// OrganizationListResponse represents the response for listing organizations
+type OrganizationListResponse struct {
+	Organizations []Organization `json:"organizations"`
+}
@@ -0,0 +1,31 @@
+package domain
+
+// SearchQuery represents the parameters of a relevance-ranked search
+type SearchQuery struct {
+	Query    string `json:"q"`
+	Page     int    `json:"page"`
+	PageSize int    `json:"page_size"`
+}
+
+// SearchHighlight holds the matched fields with the search terms wrapped in <mark> tags
+type SearchHighlight struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+}
+
+// SearchResult represents a single ranked search hit
+type SearchResult struct {
+	Service   Service         `json:"service"`
+	Score     int             `json:"score"`
+	Highlight SearchHighlight `json:"highlight"`
+}
+
+// SearchResponse represents the response for a search request
+type SearchResponse struct {
+	Query      string         `json:"query"`
+	Results    []SearchResult `json:"results"`
+	Total      int            `json:"total"`
+	Page       int            `json:"page"`
+	PageSize   int            `json:"page_size"`
+	TotalPages int            `json:"total_pages"`
+}
@@ -0,0 +1,74 @@
+package domain
+
+import "time"
+
+// LatencyPercentile is the percent of the successful probes of a service
+// that must answer within its latency target
+const LatencyPercentile = 95
+
+// SLO attainment statuses
+const (
+	SLOMet      = "met"
+	SLOBreached = "breached"
+	// SLONoData is the status of services not probed during the window
+	SLONoData = "no_data"
+)
+
+// ServiceSLO are the service level objectives of a service, measured by the
+// probes of its health check
+type ServiceSLO struct {
+	// AvailabilityTarget is the percent of probes finding the service up,
+	// e.g. 99.9
+	AvailabilityTarget float64 `json:"availability_target"`
+	// LatencyTargetMS, unless 0, is the latency within which
+	// LatencyPercentile percent of the successful probes answer
+	LatencyTargetMS int64 `json:"latency_target_ms,omitempty"`
+}
+
+// SLOReportQuery selects the window of an SLO report
+type SLOReportQuery struct {
+	Since time.Time
+	Until time.Time
+}
+
+// SLOProbeCounts are the probes of a service with SLOs during a window
+type SLOProbeCounts struct {
+	ServiceID   int
+	ServiceName string
+	SLO         ServiceSLO
+	Probes      int
+	UpProbes    int
+	// FastProbes are the probes up within the latency target
+	FastProbes int
+}
+
+// SLOAttainment is how a service did against its SLOs during a window.
+// Percentages are 0 without probes.
+type SLOAttainment struct {
+	ServiceID   int        `json:"service_id"`
+	ServiceName string     `json:"service_name"`
+	SLO         ServiceSLO `json:"slo"`
+	Probes      int        `json:"probes"`
+	UpProbes    int        `json:"up_probes"`
+	// Availability is the percent of probes finding the service up
+	Availability float64 `json:"availability"`
+	// LatencyAttainment is the percent of the probes up answering within the
+	// latency target, without a latency target 0
+	LatencyAttainment float64 `json:"latency_attainment"`
+	// ErrorBudgetRemaining is the percent of the down probes the availability
+	// target allows that are left; it is negative once the target is missed
+	ErrorBudgetRemaining float64 `json:"error_budget_remaining"`
+	Status               string  `json:"status"`
+}
+
+// SLOReport represents the SLO attainment of every service of the
+// organization with SLOs during a window, by service name
+type SLOReport struct {
+	Since    time.Time       `json:"since"`
+	Until    time.Time       `json:"until"`
+	Services []SLOAttainment `json:"services"`
+	// Met, Breached and NoData count the services by status
+	Met      int `json:"met"`
+	Breached int `json:"breached"`
+	NoData   int `json:"no_data"`
+}
@@ -0,0 +1,61 @@
+package domain
+
+import "time"
+
+// Providers hosting the source repositories of services
+const (
+	ProviderGitHub = "github"
+	ProviderGitLab = "gitlab"
+)
+
+// SourceRepository is the repository of the source code of a service, with
+// the details its provider reported at the last refresh
+type SourceRepository struct {
+	// URL is the normalized web URL of the repository, e.g.
+	// https://github.com/kong/connect
+	URL      string `json:"url"`
+	Provider string `json:"provider"`
+	// Path is the repository within its provider, owner/name on GitHub and
+	// the project path, subgroups included, on GitLab
+	Path          string   `json:"path"`
+	DefaultBranch string   `json:"default_branch,omitempty"`
+	LatestRelease *Release `json:"latest_release,omitempty"`
+	// Error is why the last refresh failed, if it did; the details are those
+	// of the refresh before
+	Error       string     `json:"error,omitempty"`
+	RefreshedAt *time.Time `json:"refreshed_at,omitempty"`
+}
+
+// Release is a published release of a source repository
+type Release struct {
+	Tag         string     `json:"tag"`
+	Name        string     `json:"name,omitempty"`
+	URL         string     `json:"url,omitempty"`
+	PublishedAt *time.Time `json:"published_at,omitempty"`
+}
+
+// SourceRepositoryInput links a service to the repository of its source
+// code, by the URL of the repository on GitHub or GitLab
+type SourceRepositoryInput struct {
+	URL string `json:"url"`
+}
+
+// LinkedRepository is a source repository to refresh, in any organization
+type LinkedRepository struct {
+	OrgID     int
+	ServiceID int
+	URL       string
+	Provider  string
+	Path      string
+}
+
+// RepositoryDetails is the outcome of a refresh of a linked repository. A
+// failed refresh only records its Error.
+type RepositoryDetails struct {
+	ServiceID     int
+	URL           string
+	DefaultBranch string
+	LatestRelease *Release
+	Error         string
+	RefreshedAt   time.Time
+}
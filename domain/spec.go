@@ -0,0 +1,60 @@
+package domain
+
+import "time"
+
+// SpecMetadata describes the OpenAPI spec of a version, extracted when it is
+// uploaded
+type SpecMetadata struct {
+	// OpenAPI is the version of the OpenAPI specification, e.g. 3.0.3
+	OpenAPI    string `json:"openapi"`
+	Title      string `json:"title"`
+	APIVersion string `json:"api_version"`
+	Paths      int    `json:"paths"`
+	Operations int    `json:"operations"`
+	// OperationsByMethod counts the operations by lowercase HTTP method
+	OperationsByMethod map[string]int `json:"operations_by_method"`
+	UploadedAt         time.Time      `json:"uploaded_at"`
+}
+
+// VersionSpec is the OpenAPI spec of a version as uploaded
+type VersionSpec struct {
+	Content []byte
+	// ContentType is application/json or application/yaml
+	ContentType string
+	Metadata    SpecMetadata
+}
+
+// SpecComparison lists the differences between the specs of two versions of
+// a service, by endpoint
+type SpecComparison struct {
+	From    string            `json:"from"`
+	To      string            `json:"to"`
+	Added   []EndpointChanges `json:"added"`
+	Removed []EndpointChanges `json:"removed"`
+	Changed []EndpointChanges `json:"changed"`
+	// Breaking is set when any change may break the clients of From
+	Breaking bool `json:"breaking"`
+}
+
+// EndpointChanges are the changes of one operation, identified by method
+// and path
+type EndpointChanges struct {
+	Method   string       `json:"method"`
+	Path     string       `json:"path"`
+	Breaking bool         `json:"breaking"`
+	Changes  []SpecChange `json:"changes,omitempty"`
+}
+
+// SpecChange is a change of an operation
+type SpecChange struct {
+	Description string `json:"description"`
+	Breaking    bool   `json:"breaking"`
+}
+
+// DefinitionImport overrides the metadata of an API definition imported as
+// a service; empty fields keep the definition's
+type DefinitionImport struct {
+	Name    string
+	Owner   string
+	Version string
+}
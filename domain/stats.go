@@ -0,0 +1,17 @@
+package domain
+
+// StatBucket is the number of services sharing a single value of a dimension
+type StatBucket struct {
+	Value string `json:"value"`
+	Count int    `json:"count"`
+}
+
+// CatalogStats represents aggregate numbers about the catalog
+type CatalogStats struct {
+	TotalServices   int          `json:"total_services"`
+	TotalVersions   int          `json:"total_versions"`
+	ByStatus        []StatBucket `json:"by_status"`
+	ByOwner         []StatBucket `json:"by_owner"`
+	ByTag           []StatBucket `json:"by_tag"`
+	RecentlyUpdated []Service    `json:"recently_updated"`
+}
@@ -0,0 +1,36 @@
+package domain
+
+import "time"
+
+// SunsetReminderDays are how many days ahead of a sunset its reminders are
+// published, the earliest first
+var SunsetReminderDays = []int{30, 7, 1}
+
+// Sunset schedules the end of life of a service or a version: it becomes
+// deprecated at DeprecateAt, if set, and archived at ArchiveAt
+type Sunset struct {
+	DeprecateAt *time.Time `json:"deprecate_at,omitempty"`
+	ArchiveAt   time.Time  `json:"archive_at"`
+	// Status is the status the schedule has reached: active, deprecated or
+	// archived
+	Status string `json:"status"`
+}
+
+// SunsetInput represents the fields of a sunset schedule
+type SunsetInput struct {
+	DeprecateAt *time.Time `json:"deprecate_at"`
+	ArchiveAt   time.Time  `json:"archive_at"`
+}
+
+// DueSunset is a sunset, of a service or of one of its versions, that has a
+// step due: a reminder, the deprecation or the archival
+type DueSunset struct {
+	OrgID     int
+	ServiceID int
+	// VersionID is 0 for the sunset of the service itself
+	VersionID int
+	Sunset
+	// RemindedDays is the reminder last published, in days ahead of the
+	// sunset; 0 before the first
+	RemindedDays int
+}
@@ -0,0 +1,41 @@
+package domain
+
+import "time"
+
+// Statuses of an ownership transfer
+const (
+	// TransferPending awaits the confirmation of the receiving owner
+	TransferPending   = "pending"
+	TransferCompleted = "completed"
+	TransferDeclined  = "declined"
+)
+
+// OwnershipTransfer is a request to hand a service over to another owner.
+// It completes once the receiving owner, or an admin, confirms it.
+type OwnershipTransfer struct {
+	ID          int    `json:"id"`
+	ServiceID   int    `json:"service_id"`
+	FromOwner   string `json:"from_owner"`
+	ToOwner     string `json:"to_owner"`
+	Reason      string `json:"reason,omitempty"`
+	RequestedBy string `json:"requested_by"`
+	Status      string `json:"status"`
+	// ResolvedBy is the user who confirmed or declined the transfer
+	ResolvedBy  string     `json:"resolved_by,omitempty"`
+	RequestedAt time.Time  `json:"requested_at"`
+	ResolvedAt  *time.Time `json:"resolved_at,omitempty"`
+}
+
+// TransferInput represents the fields of an ownership transfer request
+type TransferInput struct {
+	Owner  string `json:"owner"`
+	Reason string `json:"reason"`
+	// Override completes the transfer without confirmation; admins only
+	Override bool `json:"override"`
+}
+
+// TransferListResponse represents the ownership transfers of a service,
+// newest first
+type TransferListResponse struct {
+	Transfers []OwnershipTransfer `json:"transfers"`
+}
@@ -0,0 +1,14 @@
+package domain
+
+// TranslationInput is the description of a service in a language, as
+// submitted
+type TranslationInput struct {
+	Description string `json:"description"`
+}
+
+// Translation is the description of a service in a language other than the
+// one it was written in, shown to clients accepting that language
+type Translation struct {
+	Language    string `json:"language"`
+	Description string `json:"description"`
+}
@@ -0,0 +1,67 @@
+package domain
+
+import "time"
+
+// Roles a user can hold
+const (
+	RoleAdmin  = "admin"
+	RoleViewer = "viewer"
+)
+
+// User is an account that API tokens are issued to
+type User struct {
+	ID        int       `json:"id"`
+	Username  string    `json:"username"`
+	Roles     []string  `json:"roles"`
+	Disabled  bool      `json:"disabled"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// UserInput represents the fields of a user create request
+type UserInput struct {
+	Username string   `json:"username"`
+	Roles    []string `json:"roles"`
+}
+
+// APIToken describes an issued bearer token; the token itself is only
+// returned once, when it is issued
+type APIToken struct {
+	ID     int `json:"id"`
+	UserID int `json:"user_id"`
+	// OrgID is the organization the token acts for
+	OrgID     int        `json:"org_id"`
+	Username  string     `json:"username"`
+	Name      string     `json:"name"`
+	CreatedAt time.Time  `json:"created_at"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+}
+
+// TokenGrant is what an issued token authenticates: its user, acting for
+// the organization the token was issued in
+type TokenGrant struct {
+	User  User
+	OrgID int
+}
+
+// TokenInput represents the fields of a token issue request
+type TokenInput struct {
+	Username string `json:"username"`
+	// Name describes what the token is used for, e.g. "ci"
+	Name string `json:"name"`
+}
+
+// IssuedToken is a newly issued token together with its secret
+type IssuedToken struct {
+	APIToken
+	Token string `json:"token"`
+}
+
+// UserListResponse represents the response for listing users
+type UserListResponse struct {
+	Users []User `json:"users"`
+}
+
+// TokenListResponse represents the response for listing tokens
+type TokenListResponse struct {
+	Tokens []APIToken `json:"tokens"`
+}
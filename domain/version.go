@@ -0,0 +1,19 @@
+package domain
+
+// VersionQuery represents query parameters for listing the versions of a service
+type VersionQuery struct {
+	ServiceID int    `json:"service_id"`
+	SortBy    string `json:"sort_by"`  // semver, created_at
+	SortDir   string `json:"sort_dir"` // asc, desc
+	Page      int    `json:"page"`
+	PageSize  int    `json:"page_size"`
+}
+
+// VersionListResponse represents the response for listing the versions of a service
+type VersionListResponse struct {
+	Versions   []ServiceVersion `json:"versions"`
+	Total      int              `json:"total"`
+	Page       int              `json:"page"`
+	PageSize   int              `json:"page_size"`
+	TotalPages int              `json:"total_pages"`
+}
@@ -0,0 +1,90 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Delivery statuses: pending deliveries are retried until they succeed or
+// run out of attempts
+const (
+	DeliveryPending   = "pending"
+	DeliveryDelivered = "delivered"
+	DeliveryFailed    = "failed"
+)
+
+// EventTypes lists every change event type, which webhooks can filter on
+var EventTypes = []string{
+	EventServiceCreated,
+	EventServiceUpdated,
+	EventServiceDeleted,
+	EventVersionCreated,
+	EventVersionDeleted,
+	EventVersionDeployed,
+	EventVersionUndeployed,
+	EventVersionSpecUpdated,
+	EventServiceHealthChanged,
+	EventServiceSunsetReminder,
+	EventVersionSunsetReminder,
+	EventVersionSunsetUpdated,
+}
+
+// Webhook receives the change events of its organization as signed POST
+// requests; its secret is only returned once, when it is created
+type Webhook struct {
+	ID  int    `json:"id"`
+	URL string `json:"url"`
+	// Events lists the event types delivered; empty delivers all of them
+	Events    []string  `json:"events"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// WebhookInput represents the fields of a webhook create request
+type WebhookInput struct {
+	URL    string   `json:"url"`
+	Events []string `json:"events"`
+	// Secret signs the deliveries; a random one is generated when empty
+	Secret string `json:"secret"`
+}
+
+// CreatedWebhook is a newly created webhook together with its secret
+type CreatedWebhook struct {
+	Webhook
+	Secret string `json:"secret"`
+}
+
+// WebhookDelivery logs an event delivered to a webhook and the outcome of
+// its last attempt
+type WebhookDelivery struct {
+	ID        int             `json:"id"`
+	WebhookID int             `json:"webhook_id"`
+	Event     string          `json:"event"`
+	Payload   json.RawMessage `json:"payload"`
+	Status    string          `json:"status"`
+	Attempts  int             `json:"attempts"`
+	// ResponseCode is the HTTP status of the last attempt, 0 when it got no response
+	ResponseCode  int        `json:"response_code"`
+	Error         string     `json:"error,omitempty"`
+	NextAttemptAt *time.Time `json:"next_attempt_at,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+	DeliveredAt   *time.Time `json:"delivered_at,omitempty"`
+}
+
+// PendingDelivery is a delivery due for an attempt with the endpoint and
+// secret of its webhook
+type PendingDelivery struct {
+	WebhookDelivery
+	URL    string
+	Secret string
+}
+
+// WebhookListResponse represents the response for listing webhooks
+type WebhookListResponse struct {
+	Webhooks []Webhook `json:"webhooks"`
+}
+
+// WebhookDeliveryListResponse represents the response for listing the
+// deliveries of a webhook, most recent first
+type WebhookDeliveryListResponse struct {
+	Deliveries []WebhookDelivery `json:"deliveries"`
+}
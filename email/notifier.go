@@ -0,0 +1,326 @@
+// Package email emails service owners and subscribers when a service is
+// deprecated, changes owner or nears its sunset, and users when a comment
+// mentions them. Users set the address they receive the
+// emails at, and the notifications they want, through
+// /api/v1/me/notification-preferences and subscribe to services through
+// /api/v1/services/{id}/subscription.
+package email
+
+import (
+	"bytes"
+	"context"
+	"embed"
+	"fmt"
+	"log/slog"
+	"mime"
+	"net/mail"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"time"
+
+	"com.kong.connect/domain"
+	"com.kong.connect/logging"
+	"com.kong.connect/tenant"
+)
+
+// notifyTimeout bounds listing the recipients and sending the emails of one
+// notification
+const notifyTimeout = time.Minute
+
+//go:embed templates/*.tmpl
+var defaultTemplates embed.FS
+
+// Config configures the SMTP server and the messages
+type Config struct {
+	// Addr is the host:port of the SMTP server; empty sends no email
+	Addr     string
+	Username string
+	Password string
+	// From is the sender address of the emails
+	From string
+	// TemplatesDir holds deprecation.tmpl, ownership_change.tmpl,
+	// mention.tmpl and sunset.tmpl replacing the built-in templates; empty
+	// uses the built-in ones
+	TemplatesDir string
+}
+
+// Recipients lists the users to email a notification about a service of
+// the organization of ctx: its subscribers and the given owners, according
+// to their preferences
+type Recipients interface {
+	EmailRecipients(ctx context.Context, serviceID int, notification string, owners []string) ([]domain.EmailRecipient, error)
+	// MentionRecipients lists the users among usernames to email about a
+	// comment mentioning them, according to their preferences
+	MentionRecipients(ctx context.Context, usernames []string) ([]domain.EmailRecipient, error)
+}
+
+// Sender sends a message to one address
+type Sender interface {
+	Send(ctx context.Context, from, to string, message []byte) error
+}
+
+// TemplateData is the data of a template
+type TemplateData struct {
+	Notification string
+	Service      *domain.Service
+	// Previous is the service before the change
+	Previous *domain.Service
+	// Version is the version of a sunset, if it is not the service's
+	Version *domain.ServiceVersion
+	// Sunset is the sunset reminded of
+	Sunset *domain.Sunset
+	// Comment is the comment mentioning the recipient
+	Comment   *domain.Comment
+	Recipient domain.EmailRecipient
+	OrgID     int
+}
+
+// Notifier emails the notifications of the change events published to it
+type Notifier struct {
+	from       string
+	sender     Sender
+	recipients Recipients
+	templates  map[string]*template.Template
+	logger     *slog.Logger
+}
+
+// NewNotifier creates a notifier sending through sender, or through the
+// SMTP server of cfg when sender is nil
+func NewNotifier(cfg Config, sender Sender, recipients Recipients, logger *slog.Logger) (*Notifier, error) {
+	templates, err := loadTemplates(cfg.TemplatesDir)
+	if err != nil {
+		return nil, err
+	}
+	if sender == nil {
+		sender = NewSMTPSender(cfg.Addr, cfg.Username, cfg.Password)
+	}
+	return &Notifier{
+		from:       cfg.From,
+		sender:     sender,
+		recipients: recipients,
+		templates:  templates,
+		logger:     logging.Component(logger, "email"),
+	}, nil
+}
+
+// loadTemplates parses the template of each notification from dir, falling
+// back to the built-in one when dir does not have it
+func loadTemplates(dir string) (map[string]*template.Template, error) {
+	templates := make(map[string]*template.Template)
+	for _, notification := range []string{domain.NotifyDeprecation, domain.NotifyOwnershipChange, domain.NotifyMention, domain.NotifySunset} {
+		name := notification + ".tmpl"
+		text, err := defaultTemplates.ReadFile("templates/" + name)
+		if err != nil {
+			return nil, err
+		}
+		if dir != "" {
+			custom, err := os.ReadFile(filepath.Join(dir, name))
+			if err == nil {
+				text = custom
+			} else if !os.IsNotExist(err) {
+				return nil, fmt.Errorf("failed to read email template: %v", err)
+			}
+		}
+
+		parsed, err := template.New(name).Option("missingkey=error").Parse(string(text))
+		if err != nil {
+			return nil, fmt.Errorf("invalid email template %s: %v", name, err)
+		}
+		// Fail at startup rather than on the first notification
+		sample := TemplateData{Notification: notification, Service: &domain.Service{}, Previous: &domain.Service{},
+			Version: &domain.ServiceVersion{}, Sunset: &domain.Sunset{}, Comment: &domain.Comment{}}
+		if _, _, err := render(parsed, sample); err != nil {
+			return nil, fmt.Errorf("invalid email template %s: %v", name, err)
+		}
+		templates[notification] = parsed
+	}
+	return templates, nil
+}
+
+// Publish emails the notifications of event, if any, in the background.
+// Failures are logged and not retried.
+func (n *Notifier) Publish(event domain.ChangeEvent) {
+	for _, notification := range Notifications(event) {
+		go func(notification string) {
+			if err := n.notify(notification, event); err != nil {
+				n.logger.Error("failed to email notification", "notification", notification,
+					"service_id", event.ServiceID, "error", err)
+			}
+		}(notification)
+	}
+}
+
+// Notifications returns the notifications of a change event: the update of
+// a service to deprecated, the change of its owner and the reminders of
+// sunsets
+func Notifications(event domain.ChangeEvent) []string {
+	if event.Service == nil {
+		return nil
+	}
+	switch event.Type {
+	case domain.EventServiceSunsetReminder:
+		if event.Service.Sunset != nil {
+			return []string{domain.NotifySunset}
+		}
+		return nil
+	case domain.EventVersionSunsetReminder:
+		if event.Version != nil && event.Version.Sunset != nil {
+			return []string{domain.NotifySunset}
+		}
+		return nil
+	}
+	if event.Type != domain.EventServiceUpdated || event.Previous == nil {
+		return nil
+	}
+	var notifications []string
+	if event.Service.Status == domain.StatusDeprecated && event.Previous.Status != domain.StatusDeprecated {
+		notifications = append(notifications, domain.NotifyDeprecation)
+	}
+	if event.Service.Owner != event.Previous.Owner {
+		notifications = append(notifications, domain.NotifyOwnershipChange)
+	}
+	return notifications
+}
+
+// notify emails a notification to every recipient, one message each so
+// that recipients do not see one another
+func (n *Notifier) notify(notification string, event domain.ChangeEvent) error {
+	ctx, cancel := context.WithTimeout(tenant.NewContext(context.Background(), event.OrgID), notifyTimeout)
+	defer cancel()
+
+	owners := []string{event.Service.Owner}
+	if notification == domain.NotifyOwnershipChange {
+		owners = append(owners, event.Previous.Owner)
+	}
+	recipients, err := n.recipients.EmailRecipients(ctx, event.ServiceID, notification, owners)
+	if err != nil {
+		return err
+	}
+	// Owners given as an email address are emailed without preferences
+	for _, owner := range owners {
+		if address, err := mail.ParseAddress(owner); err == nil && address.Address == owner {
+			recipients = append(recipients, domain.EmailRecipient{Username: owner, Email: owner})
+		}
+	}
+
+	sent := make(map[string]bool)
+	for _, recipient := range recipients {
+		if sent[strings.ToLower(recipient.Email)] {
+			continue
+		}
+		sent[strings.ToLower(recipient.Email)] = true
+
+		message, err := n.message(notification, event, recipient)
+		if err != nil {
+			return err
+		}
+		if err := n.sender.Send(ctx, n.from, recipient.Email, message); err != nil {
+			n.logger.ErrorContext(ctx, "failed to send email", "notification", notification,
+				"service_id", event.ServiceID, "recipient", recipient.Username, "error", err)
+		}
+	}
+	return nil
+}
+
+// NotifyMentions emails the users a comment on a service mentions, in the
+// background. Failures are logged and not retried.
+func (n *Notifier) NotifyMentions(ctx context.Context, service *domain.Service, comment *domain.Comment) {
+	orgID := tenant.FromContext(ctx)
+	go func() {
+		if err := n.notifyMentions(orgID, service, comment); err != nil {
+			n.logger.Error("failed to email notification", "notification", domain.NotifyMention,
+				"service_id", service.ID, "comment_id", comment.ID, "error", err)
+		}
+	}()
+}
+
+// notifyMentions emails a mention to every user the comment mentions who
+// wants to be notified
+func (n *Notifier) notifyMentions(orgID int, service *domain.Service, comment *domain.Comment) error {
+	ctx, cancel := context.WithTimeout(tenant.NewContext(context.Background(), orgID), notifyTimeout)
+	defer cancel()
+
+	recipients, err := n.recipients.MentionRecipients(ctx, comment.Mentions)
+	if err != nil {
+		return err
+	}
+	sent := make(map[string]bool)
+	for _, recipient := range recipients {
+		if sent[strings.ToLower(recipient.Email)] {
+			continue
+		}
+		sent[strings.ToLower(recipient.Email)] = true
+
+		message, err := n.compose(TemplateData{
+			Notification: domain.NotifyMention,
+			Service:      service,
+			Comment:      comment,
+			Recipient:    recipient,
+			OrgID:        orgID,
+		})
+		if err != nil {
+			return err
+		}
+		if err := n.sender.Send(ctx, n.from, recipient.Email, message); err != nil {
+			n.logger.ErrorContext(ctx, "failed to send email", "notification", domain.NotifyMention,
+				"service_id", service.ID, "recipient", recipient.Username, "error", err)
+		}
+	}
+	return nil
+}
+
+// message renders the email of a notification of a change event to a
+// recipient
+func (n *Notifier) message(notification string, event domain.ChangeEvent, recipient domain.EmailRecipient) ([]byte, error) {
+	data := TemplateData{
+		Notification: notification,
+		Service:      event.Service,
+		Previous:     event.Previous,
+		Recipient:    recipient,
+		OrgID:        event.OrgID,
+	}
+	if notification == domain.NotifySunset {
+		data.Sunset = event.Service.Sunset
+		if event.Type == domain.EventVersionSunsetReminder {
+			data.Version, data.Sunset = event.Version, event.Version.Sunset
+		}
+	}
+	return n.compose(data)
+}
+
+// compose renders the template of data.Notification as a message to
+// data.Recipient
+func (n *Notifier) compose(data TemplateData) ([]byte, error) {
+	subject, body, err := render(n.templates[data.Notification], data)
+	if err != nil {
+		return nil, err
+	}
+
+	var message bytes.Buffer
+	fmt.Fprintf(&message, "From: %s\r\n", n.from)
+	fmt.Fprintf(&message, "To: %s\r\n", data.Recipient.Email)
+	fmt.Fprintf(&message, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", subject))
+	fmt.Fprintf(&message, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	message.WriteString("MIME-Version: 1.0\r\n")
+	message.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	message.WriteString("Content-Transfer-Encoding: 8bit\r\n\r\n")
+	message.WriteString(strings.ReplaceAll(body, "\n", "\r\n"))
+	return message.Bytes(), nil
+}
+
+// render executes a template, which starts with a "Subject:" line followed
+// by the body
+func render(tmpl *template.Template, data TemplateData) (string, string, error) {
+	var out strings.Builder
+	if err := tmpl.Execute(&out, data); err != nil {
+		return "", "", err
+	}
+	first, body, _ := strings.Cut(strings.ReplaceAll(out.String(), "\r\n", "\n"), "\n")
+	subject, ok := strings.CutPrefix(first, "Subject:")
+	if !ok {
+		return "", "", fmt.Errorf("the template must start with a Subject: line")
+	}
+	return strings.TrimSpace(subject), body, nil
+}
@@ -0,0 +1,254 @@
+package email
+
+import (
+	"context"
+	"net"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"com.kong.connect/domain"
+)
+
+func TestNotificationsOfChangeEvents(t *testing.T) {
+	active := &domain.Service{Status: domain.StatusActive, Owner: "web-team"}
+	deprecated := &domain.Service{Status: domain.StatusDeprecated, Owner: "web-team"}
+	transferred := &domain.Service{Status: domain.StatusDeprecated, Owner: "maps-team"}
+	sunsetting := &domain.Service{Status: domain.StatusActive, Owner: "web-team", Sunset: &domain.Sunset{Status: domain.StatusActive}}
+	version := &domain.ServiceVersion{Version: "1.0.0", Sunset: &domain.Sunset{Status: domain.StatusActive}}
+	for _, test := range []struct {
+		event domain.ChangeEvent
+		want  []string
+	}{
+		{domain.ChangeEvent{Type: domain.EventServiceUpdated, Service: deprecated, Previous: active}, []string{domain.NotifyDeprecation}},
+		{domain.ChangeEvent{Type: domain.EventServiceUpdated, Service: transferred, Previous: deprecated}, []string{domain.NotifyOwnershipChange}},
+		{domain.ChangeEvent{Type: domain.EventServiceUpdated, Service: transferred, Previous: active}, []string{domain.NotifyDeprecation, domain.NotifyOwnershipChange}},
+		{domain.ChangeEvent{Type: domain.EventServiceUpdated, Service: deprecated, Previous: deprecated}, nil},
+		{domain.ChangeEvent{Type: domain.EventServiceCreated, Service: deprecated}, nil},
+		{domain.ChangeEvent{Type: domain.EventServiceDeleted, Service: deprecated, Previous: active}, nil},
+		{domain.ChangeEvent{Type: domain.EventServiceSunsetReminder, Service: sunsetting}, []string{domain.NotifySunset}},
+		{domain.ChangeEvent{Type: domain.EventVersionSunsetReminder, Service: active, Version: version}, []string{domain.NotifySunset}},
+		{domain.ChangeEvent{Type: domain.EventVersionSunsetUpdated, Service: active, Version: version}, nil},
+	} {
+		assert.Equal(t, test.want, Notifications(test.event), "%s of %+v", test.event.Type, test.event.Service)
+	}
+}
+
+func TestMessageRendersTemplate(t *testing.T) {
+	notifier, err := NewNotifier(Config{From: "catalog@example.com"}, nil, nil, nil)
+	require.NoError(t, err)
+
+	event := domain.ChangeEvent{
+		Type:     domain.EventServiceUpdated,
+		Service:  &domain.Service{ID: 7, Name: "Café", Owner: "maps-team"},
+		Previous: &domain.Service{ID: 7, Name: "Café", Owner: "web-team"},
+	}
+	message, err := notifier.message(domain.NotifyOwnershipChange, event, domain.EmailRecipient{Username: "ana", Email: "ana@example.com"})
+	require.NoError(t, err)
+
+	header, body, ok := strings.Cut(string(message), "\r\n\r\n")
+	require.True(t, ok)
+	assert.Contains(t, header, "From: catalog@example.com\r\n")
+	assert.Contains(t, header, "To: ana@example.com\r\n")
+	// Non-ASCII subjects are encoded
+	assert.Contains(t, header, "Subject: =?utf-8?q?[Kong_Connect]_Caf=C3=A9_is_now_owned_by_maps-team?=\r\n")
+	assert.Contains(t, header, "Content-Type: text/plain; charset=utf-8\r\n")
+	assert.True(t, strings.HasPrefix(body, "Hello ana,\r\n"), body)
+	assert.Contains(t, body, "from web-team to maps-team.")
+	assert.NotContains(t, strings.ReplaceAll(body, "\r\n", ""), "\n")
+}
+
+func TestMessageRendersVersionSunset(t *testing.T) {
+	notifier, err := NewNotifier(Config{From: "catalog@example.com"}, nil, nil, nil)
+	require.NoError(t, err)
+
+	archiveAt := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	event := domain.ChangeEvent{
+		Type:    domain.EventVersionSunsetReminder,
+		Service: &domain.Service{ID: 7, Name: "Locate Us", Owner: "web-team"},
+		Version: &domain.ServiceVersion{ID: 3, Version: "1.0.0", Sunset: &domain.Sunset{ArchiveAt: archiveAt, Status: domain.StatusActive}},
+	}
+	message, err := notifier.message(domain.NotifySunset, event, domain.EmailRecipient{Username: "ana", Email: "ana@example.com"})
+	require.NoError(t, err)
+
+	assert.Contains(t, string(message), "Subject: [Kong Connect] Locate Us 1.0.0 will be archived on 2026-03-01\r\n")
+	assert.Contains(t, string(message), "Version 1.0.0 of the service Locate Us (ID 7) reaches its sunset on\r\n2026-03-01 12:00 UTC")
+	assert.NotContains(t, string(message), "deprecated from")
+}
+
+func TestTemplatesDirReplacesTemplates(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "deprecation.tmpl"), []byte("Subject: {{.Service.Name}} retires\nBye {{.Recipient.Username}}\n"), 0o644))
+	notifier, err := NewNotifier(Config{TemplatesDir: dir}, nil, nil, nil)
+	require.NoError(t, err)
+
+	event := domain.ChangeEvent{Service: &domain.Service{Name: "Ledger", Owner: "b"}, Previous: &domain.Service{Name: "Ledger", Owner: "a"}}
+	recipient := domain.EmailRecipient{Username: "ana", Email: "ana@example.com"}
+	message, err := notifier.message(domain.NotifyDeprecation, event, recipient)
+	require.NoError(t, err)
+	assert.Contains(t, string(message), "Subject: Ledger retires\r\n")
+	assert.True(t, strings.HasSuffix(string(message), "\r\n\r\nBye ana\r\n"))
+
+	// The other template stays the built-in one
+	message, err = notifier.message(domain.NotifyOwnershipChange, event, recipient)
+	require.NoError(t, err)
+	assert.Contains(t, string(message), "Subject: [Kong Connect] Ledger is now owned by b\r\n")
+
+	for name, template := range map[string]string{
+		"no subject": "Bye {{.Recipient.Username}}\n",
+		"bad field":  "Subject: {{.Service.Version}}\n",
+		"bad syntax": "Subject: {{.Service.Name\n",
+	} {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "deprecation.tmpl"), []byte(template), 0o644))
+		_, err := NewNotifier(Config{TemplatesDir: dir}, nil, nil, nil)
+		assert.Error(t, err, name)
+	}
+}
+
+// fakeSMTP accepts one message and reports its envelope and data
+func fakeSMTP(t *testing.T) (string, <-chan []string) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+
+	received := make(chan []string, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		text := textproto.NewConn(conn)
+		text.PrintfLine("220 localhost ESMTP")
+
+		var lines []string
+		for {
+			line, err := text.ReadLine()
+			if err != nil {
+				return
+			}
+			command := strings.ToUpper(strings.SplitN(line, " ", 2)[0])
+			switch command {
+			case "EHLO":
+				text.PrintfLine("250-localhost")
+				text.PrintfLine("250 AUTH PLAIN")
+			case "AUTH":
+				lines = append(lines, line)
+				text.PrintfLine("235 OK")
+			case "MAIL", "RCPT":
+				lines = append(lines, line)
+				text.PrintfLine("250 OK")
+			case "DATA":
+				text.PrintfLine("354 Go ahead")
+				data, _ := text.ReadDotBytes()
+				lines = append(lines, string(data))
+				text.PrintfLine("250 OK")
+			case "QUIT":
+				text.PrintfLine("221 Bye")
+				received <- lines
+				return
+			default:
+				text.PrintfLine("502 Unknown")
+			}
+		}
+	}()
+	return listener.Addr().String(), received
+}
+
+func TestSMTPSenderSendsMessage(t *testing.T) {
+	addr, received := fakeSMTP(t)
+	sender := NewSMTPSender(addr, "catalog", "secret")
+
+	require.NoError(t, sender.Send(context.Background(), "catalog@example.com", "ana@example.com", []byte("Subject: Hi\r\n\r\nHello\r\n")))
+	lines := <-received
+	require.Len(t, lines, 4)
+	assert.True(t, strings.HasPrefix(lines[0], "AUTH PLAIN "), "authenticates to localhost without TLS")
+	assert.Equal(t, "MAIL FROM:<catalog@example.com>", lines[1])
+	assert.Equal(t, "RCPT TO:<ana@example.com>", lines[2])
+	assert.Equal(t, "Subject: Hi\n\nHello\n", lines[3])
+}
+
+// recordingSender keeps the recipients of the messages sent
+type recordingSender struct {
+	to chan string
+}
+
+func (s *recordingSender) Send(_ context.Context, _, to string, _ []byte) error {
+	s.to <- to
+	return nil
+}
+
+// fixedRecipients returns the same recipients for every notification
+type fixedRecipients struct {
+	recipients []domain.EmailRecipient
+	owners     chan []string
+}
+
+func (f fixedRecipients) EmailRecipients(_ context.Context, _ int, _ string, owners []string) ([]domain.EmailRecipient, error) {
+	f.owners <- owners
+	return f.recipients, nil
+}
+
+func (f fixedRecipients) MentionRecipients(_ context.Context, usernames []string) ([]domain.EmailRecipient, error) {
+	f.owners <- usernames
+	return f.recipients, nil
+}
+
+func TestPublishEmailsEachRecipientOnce(t *testing.T) {
+	sender := &recordingSender{to: make(chan string, 10)}
+	recipients := fixedRecipients{
+		recipients: []domain.EmailRecipient{
+			{Username: "ana", Email: "ana@example.com"},
+			{Username: "bob", Email: "Ana@Example.com"},
+			{Username: "cy", Email: "cy@example.com"},
+		},
+		owners: make(chan []string, 1),
+	}
+	notifier, err := NewNotifier(Config{From: "catalog@example.com"}, sender, recipients, nil)
+	require.NoError(t, err)
+
+	notifier.Publish(domain.ChangeEvent{
+		Type:     domain.EventServiceUpdated,
+		Service:  &domain.Service{Name: "Ledger", Owner: "finance@example.com"},
+		Previous: &domain.Service{Name: "Ledger", Owner: "accounting"},
+	})
+	assert.Equal(t, []string{"finance@example.com", "accounting"}, <-recipients.owners)
+
+	var sent []string
+	for range 3 {
+		sent = append(sent, <-sender.to)
+	}
+	// The owner given as an email address is emailed too
+	assert.ElementsMatch(t, []string{"ana@example.com", "cy@example.com", "finance@example.com"}, sent)
+	assert.Empty(t, sender.to)
+}
+
+func TestNotifyMentionsEmailsMentionedUsers(t *testing.T) {
+	sender := &recordingSender{to: make(chan string, 10)}
+	recipients := fixedRecipients{
+		recipients: []domain.EmailRecipient{
+			{Username: "ana", Email: "ana@example.com"},
+			{Username: "bob", Email: "bob@example.com"},
+		},
+		owners: make(chan []string, 1),
+	}
+	notifier, err := NewNotifier(Config{From: "catalog@example.com"}, sender, recipients, nil)
+	require.NoError(t, err)
+
+	comment := &domain.Comment{ID: 3, ServiceID: 7, Author: "cy", Body: "@ana @bob see the new version", Mentions: []string{"ana", "bob"}}
+	notifier.NotifyMentions(context.Background(), &domain.Service{ID: 7, Name: "Ledger"}, comment)
+	assert.Equal(t, []string{"ana", "bob"}, <-recipients.owners)
+	assert.ElementsMatch(t, []string{"ana@example.com", "bob@example.com"}, []string{<-sender.to, <-sender.to})
+
+	message, err := notifier.compose(TemplateData{Notification: domain.NotifyMention, Service: &domain.Service{ID: 7, Name: "Ledger"},
+		Comment: comment, Recipient: recipients.recipients[0]})
+	require.NoError(t, err)
+	assert.Contains(t, string(message), "Subject: [Kong Connect] cy mentioned you on Ledger\r\n")
+	assert.Contains(t, string(message), "@ana @bob see the new version")
+}
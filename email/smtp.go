@@ -0,0 +1,86 @@
+package email
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"net/smtp"
+	"time"
+)
+
+// smtpTimeout bounds the conversation with the SMTP server for one message
+const smtpTimeout = 30 * time.Second
+
+// SMTPSender sends messages through an SMTP server, upgrading the
+// connection with STARTTLS when the server offers it
+type SMTPSender struct {
+	addr     string
+	host     string
+	username string
+	password string
+}
+
+// NewSMTPSender creates a sender for the SMTP server at addr (host:port),
+// authenticating with PLAIN when username is set
+func NewSMTPSender(addr, username, password string) *SMTPSender {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		host = addr
+	}
+	return &SMTPSender{addr: addr, host: host, username: username, password: password}
+}
+
+// Send sends message from the address from to the address to
+func (s *SMTPSender) Send(ctx context.Context, from, to string, message []byte) error {
+	deadline := time.Now().Add(smtpTimeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	dialer := net.Dialer{Deadline: deadline}
+	conn, err := dialer.DialContext(ctx, "tcp", s.addr)
+	if err != nil {
+		return err
+	}
+	// net/smtp takes no context; the deadline bounds the whole conversation
+	if err := conn.SetDeadline(deadline); err != nil {
+		conn.Close()
+		return err
+	}
+
+	client, err := smtp.NewClient(conn, s.host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: s.host}); err != nil {
+			return err
+		}
+	}
+	if s.username != "" {
+		// PlainAuth refuses to send the password over an unencrypted
+		// connection to another host than localhost
+		if err := client.Auth(smtp.PlainAuth("", s.username, s.password, s.host)); err != nil {
+			return err
+		}
+	}
+	if err := client.Mail(from); err != nil {
+		return err
+	}
+	if err := client.Rcpt(to); err != nil {
+		return err
+	}
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(message); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
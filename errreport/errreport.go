@@ -0,0 +1,31 @@
+package errreport
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// Event describes a panic or server error together with the request it occurred in
+type Event struct {
+	Err       error
+	Panic     bool
+	Status    int
+	Request   *http.Request
+	RequestID string
+	User      string
+}
+
+// Reporter sends events to an error tracking service
+type Reporter interface {
+	Report(ctx context.Context, event Event)
+	// Flush waits up to timeout for queued events to be sent
+	Flush(timeout time.Duration) bool
+}
+
+// Nop discards every event; it is used when no error tracking is configured
+type Nop struct{}
+
+func (Nop) Report(context.Context, Event) {}
+
+func (Nop) Flush(time.Duration) bool { return true }
@@ -0,0 +1,70 @@
+package errreport
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/getsentry/sentry-go"
+)
+
+// SentryConfig configures the Sentry reporter
+type SentryConfig struct {
+	DSN         string
+	Environment string
+	Release     string
+}
+
+// Sentry reports events to Sentry. Credentials such as the Authorization
+// header and cookies are stripped from the captured request.
+type Sentry struct {
+	client *sentry.Client
+}
+
+// NewSentry creates a Sentry reporter
+func NewSentry(cfg SentryConfig) (*Sentry, error) {
+	return newSentry(sentry.ClientOptions{
+		Dsn:              cfg.DSN,
+		Environment:      cfg.Environment,
+		Release:          cfg.Release,
+		AttachStacktrace: true,
+	})
+}
+
+func newSentry(opts sentry.ClientOptions) (*Sentry, error) {
+	client, err := sentry.NewClient(opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create sentry client: %v", err)
+	}
+	return &Sentry{client: client}, nil
+}
+
+// Report captures the event with its request, request ID and user
+func (s *Sentry) Report(ctx context.Context, event Event) {
+	scope := sentry.NewScope()
+	if event.Request != nil {
+		scope.SetRequest(event.Request)
+	}
+	if event.RequestID != "" {
+		scope.SetTag("request_id", event.RequestID)
+	}
+	if event.Status != 0 {
+		scope.SetTag("http.status_code", strconv.Itoa(event.Status))
+	}
+	if event.User != "" {
+		scope.SetUser(sentry.User{Username: event.User})
+	}
+	if event.Panic {
+		scope.SetLevel(sentry.LevelFatal)
+	} else {
+		scope.SetLevel(sentry.LevelError)
+	}
+
+	sentry.NewHub(s.client, scope).CaptureException(event.Err)
+}
+
+// Flush waits up to timeout for queued events to be sent
+func (s *Sentry) Flush(timeout time.Duration) bool {
+	return s.client.Flush(timeout)
+}
@@ -0,0 +1,55 @@
+package errreport
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingTransport keeps events instead of sending them
+type recordingTransport struct {
+	mu     sync.Mutex
+	events []*sentry.Event
+}
+
+func (t *recordingTransport) Flush(time.Duration) bool              { return true }
+func (t *recordingTransport) FlushWithContext(context.Context) bool { return true }
+func (t *recordingTransport) Configure(sentry.ClientOptions)        {}
+func (t *recordingTransport) Close()                                {}
+func (t *recordingTransport) SendEvent(event *sentry.Event) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.events = append(t.events, event)
+}
+
+func TestSentryReportsRequestContext(t *testing.T) {
+	transport := &recordingTransport{}
+	reporter, err := newSentry(sentry.ClientOptions{Dsn: "https://key@sentry.example.com/1", Transport: transport})
+	require.NoError(t, err)
+
+	req := httptest.NewRequest("GET", "/api/v1/services", nil)
+	req.Header.Set("Authorization", "Bearer admin-token")
+	reporter.Report(context.Background(), Event{
+		Err:       errors.New("database is locked"),
+		Status:    500,
+		Request:   req,
+		RequestID: "abc123",
+		User:      "admin",
+	})
+
+	require.Len(t, transport.events, 1)
+	event := transport.events[0]
+	assert.Equal(t, "database is locked", event.Exception[0].Value)
+	assert.Equal(t, "abc123", event.Tags["request_id"])
+	assert.Equal(t, "500", event.Tags["http.status_code"])
+	assert.Equal(t, "admin", event.User.Username)
+	assert.Equal(t, sentry.LevelError, event.Level)
+	assert.NotContains(t, event.Request.Headers, "Authorization")
+}
@@ -0,0 +1,62 @@
+package events
+
+import (
+	"sync"
+
+	"com.kong.connect/domain"
+)
+
+// Publisher publishes catalog change events
+type Publisher interface {
+	Publish(event domain.ChangeEvent)
+}
+
+// Publishers publishes every event to each of its publishers in turn
+type Publishers []Publisher
+
+// Publish delivers an event to every publisher
+func (p Publishers) Publish(event domain.ChangeEvent) {
+	for _, publisher := range p {
+		publisher.Publish(event)
+	}
+}
+
+// Bus is an in-process publish/subscribe hub for catalog change events
+type Bus struct {
+	mu       sync.RWMutex
+	nextID   int
+	handlers map[int]func(domain.ChangeEvent)
+}
+
+// NewBus creates a new event bus
+func NewBus() *Bus {
+	return &Bus{handlers: make(map[int]func(domain.ChangeEvent))}
+}
+
+// Subscribe registers a handler for every published event and returns a
+// function that removes it. Handlers run synchronously on the publishing
+// goroutine and must not block.
+func (b *Bus) Subscribe(handler func(domain.ChangeEvent)) func() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	id := b.nextID
+	b.nextID++
+	b.handlers[id] = handler
+
+	return func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		delete(b.handlers, id)
+	}
+}
+
+// Publish delivers an event to every subscriber
+func (b *Bus) Publish(event domain.ChangeEvent) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for _, handler := range b.handlers {
+		handler(event)
+	}
+}
@@ -0,0 +1,102 @@
+package events
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"log/slog"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"com.kong.connect/domain"
+	"com.kong.connect/logging"
+)
+
+// relayPublishTimeout bounds publishing an event to Redis, which happens on
+// the goroutine of the request that made the change
+const relayPublishTimeout = 2 * time.Second
+
+// Relay shares catalog change events between the instances of a deployment
+// through a Redis channel, so that WebSocket clients see the changes made on
+// every instance and not only on the one they are connected to
+type Relay struct {
+	client   *redis.Client
+	channel  string
+	instance string
+	local    Publisher
+	logger   *slog.Logger
+}
+
+// envelope tags an event with the instance that published it, so that an
+// instance does not deliver its own events twice
+type envelope struct {
+	Instance string             `json:"instance"`
+	Event    domain.ChangeEvent `json:"event"`
+}
+
+// NewRelay creates a relay delivering events to local, typically a Bus, and
+// to the other instances subscribed to channel
+func NewRelay(client *redis.Client, channel string, local Publisher, logger *slog.Logger) *Relay {
+	id := make([]byte, 8)
+	rand.Read(id)
+	return &Relay{
+		client:   client,
+		channel:  channel,
+		instance: hex.EncodeToString(id),
+		local:    local,
+		logger:   logging.Component(logger, "events"),
+	}
+}
+
+// Publish delivers event locally and to the other instances. The change is
+// already committed, so failing to reach Redis is logged rather than returned.
+func (r *Relay) Publish(event domain.ChangeEvent) {
+	r.local.Publish(event)
+
+	payload, err := json.Marshal(envelope{Instance: r.instance, Event: event})
+	if err != nil {
+		r.logger.Error("failed to encode change event", "error", err)
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), relayPublishTimeout)
+	defer cancel()
+	if err := r.client.Publish(ctx, r.channel, payload).Err(); err != nil {
+		r.logger.Warn("failed to relay change event", "type", event.Type, "service_id", event.ServiceID, "error", err)
+	}
+}
+
+// Run delivers the events published by other instances locally until ctx is
+// done. The subscription reconnects by itself when Redis restarts; events
+// published while it is down are lost.
+func (r *Relay) Run(ctx context.Context) error {
+	sub := r.client.Subscribe(ctx, r.channel)
+	defer sub.Close()
+
+	for {
+		msg, err := sub.ReceiveMessage(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			r.logger.Warn("change event subscription interrupted", "error", err)
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-time.After(time.Second):
+			}
+			continue
+		}
+
+		var received envelope
+		if err := json.Unmarshal([]byte(msg.Payload), &received); err != nil {
+			r.logger.Warn("ignoring malformed change event", "error", err)
+			continue
+		}
+		if received.Instance == r.instance {
+			continue
+		}
+		r.local.Publish(received.Event)
+	}
+}
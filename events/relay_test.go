@@ -0,0 +1,59 @@
+package events
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"com.kong.connect/domain"
+)
+
+// recorder collects the events delivered to an instance
+type recorder struct {
+	mu     sync.Mutex
+	events []domain.ChangeEvent
+}
+
+func (r *recorder) Publish(event domain.ChangeEvent) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, event)
+}
+
+func (r *recorder) count() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.events)
+}
+
+func TestRelayDeliversEventsToEveryInstance(t *testing.T) {
+	server := miniredis.RunT(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var a, b recorder
+	relays := make([]*Relay, 2)
+	for i, local := range []*recorder{&a, &b} {
+		client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+		t.Cleanup(func() { client.Close() })
+		relays[i] = NewRelay(client, "catalog-events", local, nil)
+		go relays[i].Run(ctx)
+	}
+	require.Eventually(t, func() bool { return server.PubSubNumSub("catalog-events")["catalog-events"] == 2 }, 5*time.Second, 10*time.Millisecond)
+
+	event := domain.ChangeEvent{Type: domain.EventServiceUpdated, ServiceID: 7, Tags: []string{"payments"}}
+	relays[0].Publish(event)
+
+	require.Eventually(t, func() bool { return b.count() == 1 }, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, 7, b.events[0].ServiceID)
+	assert.Equal(t, []string{"payments"}, b.events[0].Tags)
+	// The publishing instance delivers its own event once, without the round trip
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, 1, a.count())
+}
@@ -0,0 +1,139 @@
+package features
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"strconv"
+	"strings"
+	"sync/atomic"
+
+	"com.kong.connect/audit"
+)
+
+// Flags consulted by the handlers and the service layer
+const (
+	// LenientQueryParams ignores unknown or invalid query parameters instead of
+	// answering 400, like LENIENT_QUERY_PARAMS but for a share of the users
+	LenientQueryParams = "lenient_query_params"
+)
+
+// Known lists the flags a deployment may set with what they change. Flags are
+// declared here first, so that a typo in the configuration is rejected.
+var Known = map[string]string{
+	LenientQueryParams: "ignore unknown or invalid query parameters",
+}
+
+// Rollouts maps each flag to the percentage of users it is enabled for
+type Rollouts map[string]int
+
+// ParseRollouts parses flags written as "flag=rollout" pairs separated by
+// commas, where rollout is on, off or a percentage such as 25%, e.g.
+// "lenient_query_params=10%". Flags not listed are off.
+func ParseRollouts(spec string) (Rollouts, error) {
+	rollouts := Rollouts{}
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		name, value, ok := strings.Cut(entry, "=")
+		name, value = strings.TrimSpace(name), strings.TrimSpace(value)
+		if !ok {
+			return nil, fmt.Errorf("invalid feature flag %q: want flag=on|off|percent%%", entry)
+		}
+		if _, known := Known[name]; !known {
+			return nil, fmt.Errorf("unknown feature flag %q, known flags are %s", name, strings.Join(names(), ", "))
+		}
+
+		switch value {
+		case "on":
+			rollouts[name] = 100
+		case "off":
+			rollouts[name] = 0
+		default:
+			percent, err := strconv.Atoi(strings.TrimSuffix(value, "%"))
+			if err != nil || percent < 0 || percent > 100 {
+				return nil, fmt.Errorf("invalid feature flag %q: rollout must be on, off or a percentage from 0%% to 100%%", entry)
+			}
+			rollouts[name] = percent
+		}
+	}
+	return rollouts, nil
+}
+
+func names() []string {
+	list := make([]string, 0, len(Known))
+	for name := range Known {
+		list = append(list, name)
+	}
+	sort.Strings(list)
+	return list
+}
+
+// Enabled reports whether flag is on for the user identified by key. A user
+// falls in the same bucket for a flag on every request, so raising the
+// percentage only adds users.
+func (r Rollouts) Enabled(flag, key string) bool {
+	percent := r[flag]
+	switch {
+	case percent <= 0:
+		return false
+	case percent >= 100:
+		return true
+	}
+
+	h := fnv.New32a()
+	h.Write([]byte(flag))
+	h.Write([]byte{0})
+	h.Write([]byte(key))
+	return int(h.Sum32()%100) < percent
+}
+
+// Store holds the flags of the running server. It is safe for concurrent use
+// and can be replaced while requests are being served.
+type Store struct {
+	rollouts atomic.Pointer[Rollouts]
+}
+
+// NewStore creates a store with the given rollouts
+func NewStore(rollouts Rollouts) *Store {
+	s := &Store{}
+	s.Set(rollouts)
+	return s
+}
+
+// Set replaces all rollouts
+func (s *Store) Set(rollouts Rollouts) {
+	copied := make(Rollouts, len(rollouts))
+	for flag, percent := range rollouts {
+		copied[flag] = percent
+	}
+	s.rollouts.Store(&copied)
+}
+
+// Load returns the current rollouts
+func (s *Store) Load() Rollouts {
+	return *s.rollouts.Load()
+}
+
+type contextKey struct{}
+
+// NewContext returns a copy of ctx carrying rollouts, so that a request sees
+// the same flags from start to end even when they are reloaded meanwhile
+func NewContext(ctx context.Context, rollouts Rollouts) context.Context {
+	return context.WithValue(ctx, contextKey{}, rollouts)
+}
+
+// FromContext returns the rollouts stored in ctx; without any every flag is off
+func FromContext(ctx context.Context) Rollouts {
+	rollouts, _ := ctx.Value(contextKey{}).(Rollouts)
+	return rollouts
+}
+
+// Enabled reports whether flag is on for the authenticated principal of ctx
+func Enabled(ctx context.Context, flag string) bool {
+	return FromContext(ctx).Enabled(flag, audit.FromContext(ctx).Principal)
+}
@@ -0,0 +1,66 @@
+package features
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"com.kong.connect/audit"
+)
+
+func TestParseRollouts(t *testing.T) {
+	rollouts, err := ParseRollouts("")
+	require.NoError(t, err)
+	assert.Empty(t, rollouts)
+
+	for spec, want := range map[string]int{
+		"lenient_query_params=on":   100,
+		"lenient_query_params=off":  0,
+		"lenient_query_params=25%":  25,
+		" lenient_query_params = 5": 5,
+	} {
+		rollouts, err := ParseRollouts(spec)
+		require.NoError(t, err, spec)
+		assert.Equal(t, Rollouts{LenientQueryParams: want}, rollouts, spec)
+	}
+
+	for _, spec := range []string{"lenient_query_params", "lenient_query_params=maybe", "lenient_query_params=101%", "cursor_pagination=on"} {
+		_, err := ParseRollouts(spec)
+		assert.Error(t, err, spec)
+	}
+}
+
+func TestRolloutIsStablePerUser(t *testing.T) {
+	rollouts := Rollouts{LenientQueryParams: 30}
+	enabled := 0
+	for i := 0; i < 1000; i++ {
+		user := "user-" + strconv.Itoa(i)
+		on := rollouts.Enabled(LenientQueryParams, user)
+		assert.Equal(t, on, rollouts.Enabled(LenientQueryParams, user))
+		// Raising the percentage keeps the users already enabled
+		if on {
+			enabled++
+			assert.True(t, Rollouts{LenientQueryParams: 60}.Enabled(LenientQueryParams, user))
+		}
+	}
+	assert.InDelta(t, 300, enabled, 60)
+
+	assert.True(t, Rollouts{LenientQueryParams: 100}.Enabled(LenientQueryParams, ""))
+	assert.False(t, Rollouts{}.Enabled(LenientQueryParams, "admin"))
+}
+
+func TestEnabledUsesContextPrincipal(t *testing.T) {
+	store := NewStore(Rollouts{LenientQueryParams: 100})
+	ctx := NewContext(context.Background(), store.Load())
+	ctx = audit.NewContext(ctx, audit.Actor{Principal: "admin"})
+	assert.True(t, Enabled(ctx, LenientQueryParams))
+
+	// Requests keep the flags they started with
+	store.Set(Rollouts{})
+	assert.True(t, Enabled(ctx, LenientQueryParams))
+	assert.False(t, Enabled(NewContext(context.Background(), store.Load()), LenientQueryParams))
+	assert.False(t, Enabled(context.Background(), LenientQueryParams))
+}
@@ -0,0 +1,29 @@
+// Package fold normalizes text for search, so that queries match however
+// the catalog and the user type accented and non-ASCII letters.
+package fold
+
+import (
+	"unicode"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// Case returns s in NFC with its case folded, so that "STRASSE" and
+// "Straße" both become "strasse"
+func Case(s string) string {
+	// Casers are stateful and cannot be shared between goroutines
+	return norm.NFC.String(cases.Fold().String(norm.NFC.String(s)))
+}
+
+// Accents returns Case(s) without its accents and other combining marks, so
+// that "Sécurité" becomes "securite"
+func Accents(s string) string {
+	unaccented, _, err := transform.String(transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC), Case(s))
+	if err != nil {
+		return Case(s)
+	}
+	return unaccented
+}
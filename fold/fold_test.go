@@ -0,0 +1,32 @@
+package fold
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCase(t *testing.T) {
+	for input, want := range map[string]string{
+		"Payments API":         "payments api",
+		"Straße":               "strasse",
+		"ΣΊΣΥΦΟΣ":              "σίσυφοσ",
+		"Se\u0301curite\u0301": "sécurité", // decomposed accents are composed
+		"Sécurité":             "sécurité",
+	} {
+		assert.Equal(t, want, Case(input), input)
+	}
+}
+
+func TestAccents(t *testing.T) {
+	for input, want := range map[string]string{
+		"Sécurité":             "securite",
+		"Se\u0301curite\u0301": "securite",
+		"Ångström Ĳ":           "angstrom ĳ",
+		"naïve façade":         "naive facade",
+		"東京":                   "東京",
+		"":                     "",
+	} {
+		assert.Equal(t, want, Accents(input), input)
+	}
+}
@@ -0,0 +1,101 @@
+package fold
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Matcher finds terms in text once both are folded, reporting the matches at
+// their place in the original text, e.g. to highlight "securite" in
+// "Sécurité"
+type Matcher struct {
+	fold  func(string) string
+	terms []string
+}
+
+// NewMatcher returns a Matcher of terms folded with fold, such as Case or
+// Accents. Terms are tried in order at each place of the text.
+func NewMatcher(fold func(string) string, terms []string) *Matcher {
+	m := &Matcher{fold: fold}
+	for _, term := range terms {
+		if folded := fold(term); folded != "" {
+			m.terms = append(m.terms, folded)
+		}
+	}
+	return m
+}
+
+// FindStringIndex returns the byte range of the first match in text, or nil
+func (m *Matcher) FindStringIndex(text string) []int {
+	if matches := m.FindAllStringIndex(text, 1); len(matches) > 0 {
+		return matches[0]
+	}
+	return nil
+}
+
+// FindAllStringIndex returns the byte ranges of up to n successive,
+// non-overlapping matches in text, or of all of them if n < 0. Matches span
+// whole characters: a letter and the accents combined with it fold together.
+func (m *Matcher) FindAllStringIndex(text string, n int) [][]int {
+	if len(m.terms) == 0 || n == 0 {
+		return nil
+	}
+
+	// The folded text, and the range in text of the character each of its
+	// bytes was folded from
+	var folded strings.Builder
+	var starts, ends []int
+	for i := 0; i < len(text); {
+		end := i + characterLength(text[i:])
+		part := m.fold(text[i:end])
+		folded.WriteString(part)
+		for range len(part) {
+			starts = append(starts, i)
+			ends = append(ends, end)
+		}
+		i = end
+	}
+	haystack := folded.String()
+
+	var matches [][]int
+	for i := 0; i < len(haystack); {
+		// Matches start and end on the folded bytes of whole characters
+		if i > 0 && starts[i] == starts[i-1] {
+			i++
+			continue
+		}
+		length := 0
+		for _, term := range m.terms {
+			end := i + len(term)
+			if strings.HasPrefix(haystack[i:], term) && (end == len(haystack) || starts[end] != starts[end-1]) {
+				length = len(term)
+				break
+			}
+		}
+		if length == 0 {
+			i++
+			continue
+		}
+		matches = append(matches, []int{starts[i], ends[i+length-1]})
+		if len(matches) == n {
+			break
+		}
+		i += length
+	}
+	return matches
+}
+
+// characterLength returns the length of the first rune of text together with
+// the combining marks following it
+func characterLength(text string) int {
+	_, length := utf8.DecodeRuneInString(text)
+	for length < len(text) {
+		r, size := utf8.DecodeRuneInString(text[length:])
+		if !unicode.Is(unicode.Mn, r) {
+			break
+		}
+		length += size
+	}
+	return length
+}
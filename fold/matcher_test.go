@@ -0,0 +1,60 @@
+package fold
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatcherFindsFoldedTerms(t *testing.T) {
+	matcher := NewMatcher(Accents, []string{"securite", "STRASSE"})
+	text := "Sécurité an der Straße"
+	matches := matcher.FindAllStringIndex(text, -1)
+	if assert.Len(t, matches, 2) {
+		assert.Equal(t, "Sécurité", text[matches[0][0]:matches[0][1]])
+		assert.Equal(t, "Straße", text[matches[1][0]:matches[1][1]])
+	}
+
+	// Decomposed accents are matched with their letter
+	decomposed := "Sécurité"
+	assert.Equal(t, []int{0, len(decomposed)}, matcher.FindStringIndex(decomposed))
+
+	assert.Nil(t, NewMatcher(Case, []string{"securite"}).FindStringIndex(text))
+	assert.Equal(t, []int{0, 10}, NewMatcher(Case, []string{"sécurité"}).FindStringIndex(text))
+}
+
+func TestMatcherMatchesWholeCharacters(t *testing.T) {
+	// "ß" folds to "ss", of which a single "s" is no match
+	assert.Nil(t, NewMatcher(Case, []string{"tras"}).FindStringIndex("Straße"))
+
+	matcher := NewMatcher(Case, []string{"a.b"})
+	assert.Equal(t, [][]int{{4, 7}}, matcher.FindAllStringIndex("axb a.b", -1))
+	assert.Len(t, NewMatcher(Case, []string{"a"}).FindAllStringIndex("a a a", 2), 2)
+	assert.Nil(t, NewMatcher(Case, []string{" "}).FindStringIndex("a"))
+	assert.Nil(t, NewMatcher(Case, nil).FindStringIndex("a"))
+}
+
+// FuzzMatcher finds arbitrary terms in arbitrary text. Matches must be in
+// order, not overlap, and span text that folds to one of the terms.
+func FuzzMatcher(f *testing.F) {
+	f.Add("Sécurité an der Straße", "securite")
+	f.Add("Straße", "tras")
+	f.Add("axb a.b", "a.b")
+	f.Add("Sécurité", "sécurité")
+	f.Add("\xff\xfe", "\xfe")
+	f.Add("İstanbul", "i̇stanbul")
+	f.Add("ﬃ", "ffi")
+
+	f.Fuzz(func(t *testing.T, text, term string) {
+		for _, fold := range []func(string) string{Case, Accents} {
+			last := 0
+			for _, match := range NewMatcher(fold, []string{term}).FindAllStringIndex(text, -1) {
+				if !assert.True(t, last <= match[0] && match[0] < match[1] && match[1] <= len(text), "match %v after %d in %q", match, last, text) {
+					return
+				}
+				assert.Equal(t, fold(term), fold(text[match[0]:match[1]]))
+				last = match[1]
+			}
+		}
+	})
+}
@@ -0,0 +1,27 @@
+package handler
+
+import (
+	"net/http"
+
+	"com.kong.connect/domain"
+)
+
+// GetAnalytics handles GET /api/v1/analytics
+func (h *ServiceHandler) GetAnalytics(w http.ResponseWriter, r *http.Request) {
+	params := newQueryParser(r, h.strictQuery(r), "days", "limit")
+	query := domain.AnalyticsQuery{
+		Days:  params.PositiveInt("days", 30),
+		Limit: params.PositiveInt("limit", 10),
+	}
+	if !params.Validate(w, r) {
+		return
+	}
+
+	report, err := h.service.GetAnalytics(r.Context(), query)
+	if err != nil {
+		h.writeError(w, r, "get analytics", err)
+		return
+	}
+
+	h.writeJSON(w, r, http.StatusOK, report)
+}
@@ -0,0 +1,39 @@
+package handler
+
+import (
+	"net/http"
+
+	"com.kong.connect/domain"
+)
+
+// GetAuditLogs handles GET /api/v1/audit-logs
+func (h *ServiceHandler) GetAuditLogs(w http.ResponseWriter, r *http.Request) {
+	params := newQueryParser(r, h.strictQuery(r),
+		"principal", "action", "resource_type", "resource_id", "since", "until", "page", "page_size")
+	query := domain.AuditQuery{
+		Principal: params.String("principal"),
+		Action:    params.OneOf("action", domain.AuditActionCreate, domain.AuditActionUpdate, domain.AuditActionDelete),
+		ResourceType: params.OneOf("resource_type", domain.AuditResourceService, domain.AuditResourceVersion,
+			domain.AuditResourceUser, domain.AuditResourceToken, domain.AuditResourceTransfer, domain.AuditResourceComment),
+		ResourceID: params.PositiveInt("resource_id", 0),
+		Since:      params.Time("since"),
+		Until:      params.Time("until"),
+		Page:       params.PositiveInt("page", 1),
+		PageSize:   params.PageSize(50, maxPageSize),
+	}
+	if !params.Validate(w, r) {
+		return
+	}
+
+	response, err := h.service.GetAuditLogs(r.Context(), query)
+	if err != nil {
+		h.writeError(w, r, "get audit logs", err)
+		return
+	}
+
+	if !params.ValidatePage(w, r, response.Page, response.TotalPages) {
+		return
+	}
+	setPaginationHeaders(w, r, response.Total, response.Page, response.PageSize, response.TotalPages)
+	h.writeJSON(w, r, http.StatusOK, response)
+}
@@ -0,0 +1,72 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+
+	"gopkg.in/yaml.v3"
+
+	"com.kong.connect/domain"
+	"com.kong.connect/i18n"
+	"com.kong.connect/problem"
+)
+
+// ApplyCatalog handles POST /api/v1/catalog:apply
+func (h *ServiceHandler) ApplyCatalog(w http.ResponseWriter, r *http.Request) {
+	// Always strict: a mistyped dry_run must not apply the document
+	params := newQueryParser(r, true, "dry_run")
+	dryRun := params.Bool("dry_run")
+	if !params.Validate(w, r) {
+		return
+	}
+
+	var document domain.CatalogDocument
+	if !decodeCatalog(w, r, &document) {
+		return
+	}
+
+	result, err := h.service.ApplyCatalog(r.Context(), document, dryRun)
+	if err != nil {
+		h.writeError(w, r, "apply catalog", err)
+		return
+	}
+
+	h.writeJSON(w, r, http.StatusOK, result)
+}
+
+// decodeCatalog decodes a YAML or JSON catalog document, chosen by the
+// Content-Type, rejecting unknown fields so that typos do not go unnoticed
+func decodeCatalog(w http.ResponseWriter, r *http.Request, document *domain.CatalogDocument) bool {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			problem.Error(w, r, http.StatusRequestEntityTooLarge, i18n.Sprintf(i18n.FromContext(r.Context()), "Request body must not exceed %d bytes", tooLarge.Limit))
+			return false
+		}
+		problem.Error(w, r, http.StatusBadRequest, "Invalid request body")
+		return false
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	switch mediaType {
+	case "application/yaml", "application/x-yaml", "text/yaml":
+		decoder := yaml.NewDecoder(bytes.NewReader(body))
+		decoder.KnownFields(true)
+		err = decoder.Decode(document)
+	default:
+		decoder := json.NewDecoder(bytes.NewReader(body))
+		decoder.DisallowUnknownFields()
+		err = decoder.Decode(document)
+	}
+	if err != nil {
+		problem.Error(w, r, http.StatusBadRequest, fmt.Sprintf("Invalid catalog document: %v", err))
+		return false
+	}
+	return true
+}
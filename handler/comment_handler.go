@@ -0,0 +1,97 @@
+package handler
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+
+	"github.com/gorilla/mux"
+
+	"com.kong.connect/domain"
+	"com.kong.connect/middleware"
+	"com.kong.connect/problem"
+)
+
+// isAdmin reports whether the authenticated user of r has the admin role
+func isAdmin(r *http.Request) bool {
+	user, ok := r.Context().Value(middleware.UserContextKey).(*middleware.UserClaims)
+	return ok && user != nil && slices.Contains(user.Roles, "admin")
+}
+
+// CreateComment handles POST /api/v1/services/{id}/comments
+func (h *ServiceHandler) CreateComment(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		problem.Error(w, r, http.StatusBadRequest, "Invalid service ID")
+		return
+	}
+
+	var input domain.CommentInput
+	if !decodeJSON(w, r, &input) {
+		return
+	}
+
+	comment, err := h.service.CreateComment(r.Context(), id, currentUser(r), input)
+	if err != nil {
+		h.writeError(w, r, "create comment", err)
+		return
+	}
+
+	h.writeJSON(w, r, http.StatusCreated, comment)
+}
+
+// GetComments handles GET /api/v1/services/{id}/comments
+func (h *ServiceHandler) GetComments(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		problem.Error(w, r, http.StatusBadRequest, "Invalid service ID")
+		return
+	}
+
+	params := newQueryParser(r, h.strictQuery(r), "page", "page_size")
+	query := domain.CommentQuery{
+		ServiceID: id,
+		Page:      params.PositiveInt("page", 1),
+		PageSize:  params.PageSize(20, maxPageSize),
+	}
+	if !params.Validate(w, r) {
+		return
+	}
+
+	response, err := h.service.GetComments(r.Context(), query)
+	if err != nil {
+		h.writeError(w, r, "get comments", err)
+		return
+	}
+
+	if !params.ValidatePage(w, r, response.Page, response.TotalPages) {
+		return
+	}
+	setPaginationHeaders(w, r, response.Total, response.Page, response.PageSize, response.TotalPages)
+
+	// Commenting is not a catalog write, which is what purges HTTP caches
+	w.Header().Set("Cache-Control", "no-store")
+	h.writeJSON(w, r, http.StatusOK, response)
+}
+
+// DeleteComment handles DELETE /api/v1/services/{id}/comments/{commentId}
+func (h *ServiceHandler) DeleteComment(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		problem.Error(w, r, http.StatusBadRequest, "Invalid service ID")
+		return
+	}
+	commentID, err := strconv.Atoi(vars["commentId"])
+	if err != nil {
+		problem.Error(w, r, http.StatusBadRequest, "Invalid comment ID")
+		return
+	}
+
+	if err := h.service.DeleteComment(r.Context(), id, commentID, currentUser(r), isAdmin(r)); err != nil {
+		h.writeError(w, r, "delete comment", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,28 @@
+package handler
+
+import (
+	"expvar"
+	"net/http"
+	"net/http/pprof"
+
+	"github.com/gorilla/mux"
+
+	"com.kong.connect/middleware"
+)
+
+// mountDebugRoutes exposes net/http/pprof under /debug/pprof and expvar at
+// /debug/vars, restricted to admins since profiles reveal internals and
+// CPU profiling is expensive
+func mountDebugRoutes(router *mux.Router) {
+	admin := func(h http.HandlerFunc) http.HandlerFunc {
+		return middleware.AuthorizeRoles(h, "admin")
+	}
+
+	router.HandleFunc("/debug/vars", admin(expvar.Handler().ServeHTTP)).Methods("GET")
+	router.HandleFunc("/debug/pprof/cmdline", admin(pprof.Cmdline)).Methods("GET")
+	router.HandleFunc("/debug/pprof/profile", admin(pprof.Profile)).Methods("GET")
+	router.HandleFunc("/debug/pprof/symbol", admin(pprof.Symbol)).Methods("GET", "POST")
+	router.HandleFunc("/debug/pprof/trace", admin(pprof.Trace)).Methods("GET")
+	// Index also serves the named profiles such as heap, goroutine and allocs
+	router.PathPrefix("/debug/pprof/").HandlerFunc(admin(pprof.Index)).Methods("GET")
+}
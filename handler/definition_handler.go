@@ -0,0 +1,32 @@
+package handler
+
+import (
+	"net/http"
+
+	"com.kong.connect/domain"
+)
+
+// ImportDefinition handles POST /api/v1/services:import
+func (h *ServiceHandler) ImportDefinition(w http.ResponseWriter, r *http.Request) {
+	params := newQueryParser(r, h.strictQuery(r), "name", "owner", "version")
+	overrides := domain.DefinitionImport{
+		Name:    params.String("name"),
+		Owner:   params.String("owner"),
+		Version: params.String("version"),
+	}
+	if !params.Validate(w, r) {
+		return
+	}
+	content, ok := readBody(w, r)
+	if !ok {
+		return
+	}
+
+	created, err := h.service.ImportDefinition(r.Context(), content, overrides)
+	if err != nil {
+		h.writeError(w, r, "import definition", err)
+		return
+	}
+
+	h.writeJSON(w, r, http.StatusCreated, created)
+}
@@ -0,0 +1,43 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"com.kong.connect/i18n"
+	"com.kong.connect/problem"
+	"com.kong.connect/service"
+)
+
+// serviceErrors maps the kinds of service errors to their HTTP status and
+// problem type
+var serviceErrors = []struct {
+	kind        error
+	status      int
+	problemType string
+}{
+	{service.ErrNotFound, http.StatusNotFound, problem.TypeNotFound},
+	{service.ErrValidation, http.StatusBadRequest, problem.TypeValidation},
+	{service.ErrConflict, http.StatusConflict, problem.TypeConflict},
+	{service.ErrForbidden, http.StatusForbidden, problem.TypeForbidden},
+	{service.ErrUnprocessable, http.StatusUnprocessableEntity, problem.TypeUnprocessable},
+}
+
+// writeError maps an error of the service layer to a problem response, with
+// its detail in the language of the client. Errors of no kind are internal errors, logged as failures of action.
+func (h *ServiceHandler) writeError(w http.ResponseWriter, r *http.Request, action string, err error) {
+	var serviceErr *service.Error
+	if errors.As(err, &serviceErr) {
+		for _, mapping := range serviceErrors {
+			if errors.Is(serviceErr, mapping.kind) {
+				detail := serviceErr.Detail
+				if serviceErr.Localized.Format != "" {
+					detail = serviceErr.Localized.In(i18n.FromContext(r.Context()))
+				}
+				problem.Write(w, r, problem.Details{Type: mapping.problemType, Status: mapping.status, Detail: detail})
+				return
+			}
+		}
+	}
+	h.internalError(w, r, "failed to "+action, err)
+}
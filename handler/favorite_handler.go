@@ -0,0 +1,42 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+
+	"com.kong.connect/problem"
+)
+
+// FavoriteService handles PUT /api/v1/services/{id}/favorite
+func (h *ServiceHandler) FavoriteService(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		problem.Error(w, r, http.StatusBadRequest, "Invalid service ID")
+		return
+	}
+
+	if err := h.service.FavoriteService(r.Context(), id, currentUser(r)); err != nil {
+		h.writeError(w, r, "favorite service", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// UnfavoriteService handles DELETE /api/v1/services/{id}/favorite
+func (h *ServiceHandler) UnfavoriteService(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		problem.Error(w, r, http.StatusBadRequest, "Invalid service ID")
+		return
+	}
+
+	if err := h.service.UnfavoriteService(r.Context(), id, currentUser(r)); err != nil {
+		h.writeError(w, r, "unfavorite service", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,81 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+
+	"com.kong.connect/domain"
+	"com.kong.connect/problem"
+)
+
+// SetHealthCheck handles PUT /api/v1/services/{id}/health-check
+func (h *ServiceHandler) SetHealthCheck(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		problem.Error(w, r, http.StatusBadRequest, "Invalid service ID")
+		return
+	}
+
+	var input domain.HealthCheckInput
+	if !decodeJSON(w, r, &input) {
+		return
+	}
+
+	health, err := h.service.SetHealthCheck(r.Context(), id, input)
+	if err != nil {
+		h.writeError(w, r, "set health check", err)
+		return
+	}
+
+	h.writeJSON(w, r, http.StatusOK, health)
+}
+
+// DeleteHealthCheck handles DELETE /api/v1/services/{id}/health-check
+func (h *ServiceHandler) DeleteHealthCheck(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		problem.Error(w, r, http.StatusBadRequest, "Invalid service ID")
+		return
+	}
+
+	if err := h.service.DeleteHealthCheck(r.Context(), id); err != nil {
+		h.writeError(w, r, "delete health check", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetHealthHistory handles GET /api/v1/services/{id}/health
+func (h *ServiceHandler) GetHealthHistory(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		problem.Error(w, r, http.StatusBadRequest, "Invalid service ID")
+		return
+	}
+
+	params := newQueryParser(r, h.strictQuery(r), "since", "page", "page_size")
+	query := domain.HealthHistoryQuery{
+		ServiceID: id,
+		Since:     params.Time("since"),
+		Page:      params.PositiveInt("page", 1),
+		PageSize:  params.PageSize(100, maxHealthHistoryPageSize),
+	}
+	if !params.Validate(w, r) {
+		return
+	}
+
+	response, err := h.service.GetHealthHistory(r.Context(), query)
+	if err != nil {
+		h.writeError(w, r, "get health history", err)
+		return
+	}
+
+	if !params.ValidatePage(w, r, response.Page, response.TotalPages) {
+		return
+	}
+	setPaginationHeaders(w, r, response.Total, response.Page, response.PageSize, response.TotalPages)
+	h.writeJSON(w, r, http.StatusOK, response)
+}
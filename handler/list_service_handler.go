@@ -1,59 +1,116 @@
 package handler
 
 import (
-	"encoding/json"
-	"log"
+	"log/slog"
 	"net/http"
 	"strconv"
 
 	"github.com/gorilla/mux"
 
 	"com.kong.connect/domain"
+	"com.kong.connect/features"
+	"com.kong.connect/logging"
+	"com.kong.connect/middleware"
+	"com.kong.connect/problem"
 	"com.kong.connect/service"
 )
 
 // ServiceHandler handles HTTP requests for services
 type ServiceHandler struct {
 	service service.ServiceServiceInterface
+	logger  *slog.Logger
+	// lenientQuery ignores bad query parameters for everyone, regardless of feature flags
+	lenientQuery bool
+	// fastJSON encodes listings and details with jsonenc instead of encoding/json
+	fastJSON bool
+	// maxResponseBytes bounds the size of JSON responses; 0 disables the bound
+	maxResponseBytes int
+}
+
+// HandlerOption configures optional behaviour of the handler
+type HandlerOption func(*ServiceHandler)
+
+// WithLenientQueryParams restores the legacy behaviour of ignoring unknown
+// query parameters and falling back to defaults for invalid values
+func WithLenientQueryParams() HandlerOption {
+	return func(h *ServiceHandler) {
+		h.lenientQuery = true
+	}
+}
+
+// WithFastJSON encodes service listings and details without reflection,
+// producing the same bytes as encoding/json in about half the time
+func WithFastJSON() HandlerOption {
+	return func(h *ServiceHandler) {
+		h.fastJSON = true
+	}
+}
+
+// WithMaxResponseSize answers 500 instead of sending responses larger than
+// limit bytes, such as a listing of services with thousands of versions each
+func WithMaxResponseSize(limit int) HandlerOption {
+	return func(h *ServiceHandler) {
+		h.maxResponseBytes = limit
+	}
+}
+
+// WithLogger sets the logger used for handler errors
+func WithLogger(logger *slog.Logger) HandlerOption {
+	return func(h *ServiceHandler) {
+		h.logger = logging.Component(logger, "handler")
+	}
 }
 
 // NewServiceHandler creates a new service handler
-func NewServiceHandler(service service.ServiceServiceInterface) *ServiceHandler {
-	return &ServiceHandler{service: service}
+func NewServiceHandler(service service.ServiceServiceInterface, opts ...HandlerOption) *ServiceHandler {
+	h := &ServiceHandler{service: service, logger: logging.Component(nil, "handler")}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
+}
+
+// strictQuery reports whether bad query parameters of r are rejected, which the
+// lenient_query_params feature flag turns off for a share of the users
+func (h *ServiceHandler) strictQuery(r *http.Request) bool {
+	return !h.lenientQuery && !features.Enabled(r.Context(), features.LenientQueryParams)
 }
 
 // GetServices handles GET /api/services
 func (h *ServiceHandler) GetServices(w http.ResponseWriter, r *http.Request) {
 	// Parse query parameters
+	params := newQueryParser(r, h.strictQuery(r), "search", "environment", "has_versions", "min_versions", "favorites", "sort_by", "sort_dir", "page", "page_size")
 	query := domain.ServiceQuery{
-		Search:   r.URL.Query().Get("search"),
-		SortBy:   r.URL.Query().Get("sort_by"),
-		SortDir:  r.URL.Query().Get("sort_dir"),
-		Page:     1,
-		PageSize: 12,
+		Search:      params.String("search"),
+		Environment: params.String("environment"),
+		HasVersions: params.OptionalBool("has_versions"),
+		MinVersions: params.PositiveInt("min_versions", 0),
+		Favorites:   params.Bool("favorites"),
+		SortBy:      params.OneOf("sort_by", "name", "created_at", "updated_at"),
+		SortDir:     params.OneOf("sort_dir", "asc", "desc"),
+		Page:        params.PositiveInt("page", 1),
+		PageSize:    params.PageSize(12, maxPageSize),
 	}
-
-	if pageStr := r.URL.Query().Get("page"); pageStr != "" {
-		if page, err := strconv.Atoi(pageStr); err == nil && page > 0 {
-			query.Page = page
-		}
+	if !params.Validate(w, r) {
+		return
 	}
-
-	if pageSizeStr := r.URL.Query().Get("page_size"); pageSizeStr != "" {
-		if pageSize, err := strconv.Atoi(pageSizeStr); err == nil && pageSize > 0 {
-			query.PageSize = pageSize
-		}
+	if query.Favorites {
+		query.User = currentUser(r)
+		// Starring is not a catalog write, which is what purges HTTP caches
+		w.Header().Set("Cache-Control", "no-store")
 	}
 
-	response, err := h.service.GetServices(query)
+	response, err := h.service.GetServices(r.Context(), query)
 	if err != nil {
-		log.Printf("Error getting services: %v", err)
-		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		h.writeError(w, r, "get services", err)
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(response)
+	if !params.ValidatePage(w, r, response.Page, response.TotalPages) {
+		return
+	}
+	setPaginationHeaders(w, r, response.Total, response.Page, response.PageSize, response.TotalPages)
+	h.writeJSON(w, r, http.StatusOK, response)
 }
 
 // GetServiceByID handles GET /api/services/{id}
@@ -61,27 +118,29 @@ func (h *ServiceHandler) GetServiceByID(w http.ResponseWriter, r *http.Request)
 	vars := mux.Vars(r)
 	idStr, exists := vars["id"]
 	if !exists {
-		http.Error(w, "Service ID is required", http.StatusBadRequest)
+		problem.Error(w, r, http.StatusBadRequest, "Service ID is required")
 		return
 	}
 
 	id, err := strconv.Atoi(idStr)
 	if err != nil {
-		http.Error(w, "Invalid service ID", http.StatusBadRequest)
+		problem.Error(w, r, http.StatusBadRequest, "Invalid service ID")
 		return
 	}
 
-	service, err := h.service.GetServiceByID(id)
+	service, err := h.service.GetServiceByID(r.Context(), id)
 	if err != nil {
-		if err.Error() == "service not found" {
-			http.Error(w, "Service not found", http.StatusNotFound)
-			return
-		}
-		log.Printf("Error getting service by ID: %v", err)
-		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		h.writeError(w, r, "get service by ID", err)
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(service)
+	h.writeJSON(w, r, http.StatusOK, service)
+}
+
+// internalError logs an unexpected error, records it for error reporting and
+// answers with a generic 500 problem
+func (h *ServiceHandler) internalError(w http.ResponseWriter, r *http.Request, msg string, err error) {
+	h.logger.ErrorContext(r.Context(), msg, "error", err)
+	middleware.RecordError(r.Context(), err)
+	problem.Error(w, r, http.StatusInternalServerError, "Internal server error")
 }
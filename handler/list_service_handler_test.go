@@ -0,0 +1,94 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"com.kong.connect/domain"
+	"com.kong.connect/service"
+	"com.kong.connect/service/mocks"
+	"com.kong.connect/testsupport"
+)
+
+// newMockService returns a mock of the service expecting no calls, which
+// fail the test unless expected
+func newMockService(t *testing.T) *mocks.MockServiceServiceInterface {
+	return mocks.NewMockServiceServiceInterface(gomock.NewController(t))
+}
+
+func TestGetServicesPassesTheQueryToTheService(t *testing.T) {
+	svc := newMockService(t)
+	query := testsupport.Query().Search("contact").Sort("name", "desc").MinVersions(2).Page(1, 5).Build()
+	svc.EXPECT().GetServices(gomock.Any(), query).Return(&domain.ServiceListResponse{
+		Services:   []domain.ServiceWithVersions{testsupport.Service(3, "Contact Us").Build()},
+		Total:      1,
+		Page:       1,
+		PageSize:   5,
+		TotalPages: 1,
+	}, nil)
+	h := NewServiceHandler(svc)
+
+	w := httptest.NewRecorder()
+	h.GetServices(w, httptest.NewRequest(http.MethodGet, "/api/v1/services?search=contact&sort_by=name&sort_dir=desc&min_versions=2&page_size=5", nil))
+
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, "1", w.Header().Get("X-Total-Count"))
+	var response domain.ServiceListResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	require.Len(t, response.Services, 1)
+	assert.Equal(t, "Contact Us", response.Services[0].Name)
+}
+
+func TestGetServicesRejectsBadQueriesBeforeTheService(t *testing.T) {
+	h := NewServiceHandler(newMockService(t))
+
+	w := httptest.NewRecorder()
+	h.GetServices(w, httptest.NewRequest(http.MethodGet, "/api/v1/services?sort_by=owner", nil))
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, "application/problem+json", w.Header().Get("Content-Type"))
+}
+
+func TestGetServiceByIDMapsServiceErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		status int
+	}{
+		{"not found", &service.Error{Kind: service.ErrNotFound, Message: "service not found", Detail: "Service not found"}, http.StatusNotFound},
+		{"forbidden", &service.Error{Kind: service.ErrForbidden, Message: "forbidden", Detail: "forbidden"}, http.StatusForbidden},
+		{"internal", errors.New("database is locked"), http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := newMockService(t)
+			svc.EXPECT().GetServiceByID(gomock.Any(), 42).Return(nil, tt.err)
+			h := NewServiceHandler(svc)
+
+			w := httptest.NewRecorder()
+			r := mux.SetURLVars(httptest.NewRequest(http.MethodGet, "/api/v1/services/42", nil), map[string]string{"id": "42"})
+			h.GetServiceByID(w, r)
+
+			assert.Equal(t, tt.status, w.Code)
+			assert.NotContains(t, w.Body.String(), "database is locked")
+		})
+	}
+}
+
+func TestGetServiceByIDRejectsBadIDs(t *testing.T) {
+	h := NewServiceHandler(newMockService(t))
+
+	w := httptest.NewRecorder()
+	r := mux.SetURLVars(httptest.NewRequest(http.MethodGet, "/api/v1/services/abc", nil), map[string]string{"id": "abc"})
+	h.GetServiceByID(w, r)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
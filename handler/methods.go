@@ -0,0 +1,58 @@
+package handler
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+
+	"com.kong.connect/i18n"
+	"com.kong.connect/problem"
+
+	"github.com/gorilla/mux"
+)
+
+// routableMethods are the methods a route may be registered for
+var routableMethods = []string{"GET", "HEAD", "POST", "PUT", "PATCH", "DELETE"}
+
+// allowedMethods returns the methods router routes the path of r for, with
+// OPTIONS, sorted; none when no route serves the path
+func allowedMethods(router *mux.Router, r *http.Request) []string {
+	var methods []string
+	for _, method := range routableMethods {
+		probe := new(http.Request)
+		*probe = *r
+		probe.Method = method
+		var match mux.RouteMatch
+		if router.Match(probe, &match) && match.MatchErr == nil {
+			methods = append(methods, method)
+		}
+	}
+	if len(methods) == 0 {
+		return nil
+	}
+	methods = append(methods, "OPTIONS")
+	slices.Sort(methods)
+	return methods
+}
+
+// methodNotAllowed is the method not allowed handler of router. It answers
+// OPTIONS, including CORS preflight requests, with the methods the routes of
+// the path accept, and other methods with 405 and the same Allow header.
+func methodNotAllowed(router *mux.Router) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		methods := allowedMethods(router, r)
+		if len(methods) == 0 {
+			http.NotFound(w, r)
+			return
+		}
+		allow := strings.Join(methods, ", ")
+		w.Header().Set("Allow", allow)
+
+		if r.Method == "OPTIONS" {
+			w.Header().Set("Access-Control-Allow-Methods", allow)
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		problem.Error(w, r, http.StatusMethodNotAllowed, i18n.Sprintf(i18n.FromContext(r.Context()), "%s is not allowed on %s; use %s", r.Method, r.URL.Path, allow))
+	})
+}
@@ -0,0 +1,95 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+
+	"com.kong.connect/domain"
+	"com.kong.connect/middleware"
+	"com.kong.connect/problem"
+)
+
+// currentUser returns the username of the authenticated user of r
+func currentUser(r *http.Request) string {
+	if user, ok := r.Context().Value(middleware.UserContextKey).(*middleware.UserClaims); ok && user != nil {
+		return user.Username
+	}
+	return ""
+}
+
+// GetNotificationPreferences handles GET /api/v1/me/notification-preferences
+func (h *ServiceHandler) GetNotificationPreferences(w http.ResponseWriter, r *http.Request) {
+	preferences, err := h.service.GetNotificationPreferences(r.Context(), currentUser(r))
+	if err != nil {
+		h.writeError(w, r, "get notification preferences", err)
+		return
+	}
+
+	// Preferences are per user and their changes are not catalog writes, which
+	// is what purges HTTP caches
+	w.Header().Set("Cache-Control", "no-store")
+	h.writeJSON(w, r, http.StatusOK, preferences)
+}
+
+// UpdateNotificationPreferences handles PUT /api/v1/me/notification-preferences
+func (h *ServiceHandler) UpdateNotificationPreferences(w http.ResponseWriter, r *http.Request) {
+	var preferences domain.NotificationPreferences
+	if !decodeJSON(w, r, &preferences) {
+		return
+	}
+
+	updated, err := h.service.UpdateNotificationPreferences(r.Context(), currentUser(r), preferences)
+	if err != nil {
+		h.writeError(w, r, "update notification preferences", err)
+		return
+	}
+
+	h.writeJSON(w, r, http.StatusOK, updated)
+}
+
+// ListSubscriptions handles GET /api/v1/me/subscriptions
+func (h *ServiceHandler) ListSubscriptions(w http.ResponseWriter, r *http.Request) {
+	response, err := h.service.ListSubscriptions(r.Context(), currentUser(r))
+	if err != nil {
+		h.writeError(w, r, "list subscriptions", err)
+		return
+	}
+
+	// Subscribing is not a catalog write, which is what purges HTTP caches
+	w.Header().Set("Cache-Control", "no-store")
+	h.writeJSON(w, r, http.StatusOK, response)
+}
+
+// SubscribeService handles PUT /api/v1/services/{id}/subscription
+func (h *ServiceHandler) SubscribeService(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		problem.Error(w, r, http.StatusBadRequest, "Invalid service ID")
+		return
+	}
+
+	if err := h.service.SubscribeService(r.Context(), id, currentUser(r)); err != nil {
+		h.writeError(w, r, "subscribe to service", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// UnsubscribeService handles DELETE /api/v1/services/{id}/subscription
+func (h *ServiceHandler) UnsubscribeService(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		problem.Error(w, r, http.StatusBadRequest, "Invalid service ID")
+		return
+	}
+
+	if err := h.service.UnsubscribeService(r.Context(), id, currentUser(r)); err != nil {
+		h.writeError(w, r, "unsubscribe from service", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
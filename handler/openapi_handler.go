@@ -0,0 +1,21 @@
+package handler
+
+import (
+	_ "embed"
+	"net/http"
+	"strconv"
+)
+
+// openAPIDocument describes the API, checked against the responses of the
+// server by the contract tests
+//
+//go:embed openapi.yaml
+var openAPIDocument []byte
+
+// GetOpenAPI handles GET /api/v1/openapi.yaml
+func GetOpenAPI(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/yaml")
+	w.Header().Set("Content-Length", strconv.Itoa(len(openAPIDocument)))
+	w.WriteHeader(http.StatusOK)
+	w.Write(openAPIDocument)
+}
@@ -0,0 +1,34 @@
+package handler
+
+import (
+	"net/http"
+
+	"com.kong.connect/domain"
+)
+
+// CreateOrganization handles POST /api/v1/organizations
+func (h *ServiceHandler) CreateOrganization(w http.ResponseWriter, r *http.Request) {
+	var input domain.OrganizationInput
+	if !decodeJSON(w, r, &input) {
+		return
+	}
+
+	organization, err := h.service.CreateOrganization(r.Context(), input)
+	if err != nil {
+		h.writeError(w, r, "create organization", err)
+		return
+	}
+
+	h.writeJSON(w, r, http.StatusCreated, organization)
+}
+
+// ListOrganizations handles GET /api/v1/organizations
+func (h *ServiceHandler) ListOrganizations(w http.ResponseWriter, r *http.Request) {
+	response, err := h.service.ListOrganizations(r.Context())
+	if err != nil {
+		h.writeError(w, r, "list organizations", err)
+		return
+	}
+
+	h.writeJSON(w, r, http.StatusOK, response)
+}
@@ -0,0 +1,54 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Bounds of the page_size parameter, which the services also clamp to
+const (
+	maxPageSize              = 100
+	maxHealthHistoryPageSize = 500
+)
+
+// setPaginationHeaders emits X-Total-Count and an RFC 5988 Link header with
+// first/prev/next/last relations, so generic clients can paginate without
+// understanding the response envelope
+func setPaginationHeaders(w http.ResponseWriter, r *http.Request, total, page, pageSize, totalPages int) {
+	w.Header().Set("X-Total-Count", strconv.Itoa(total))
+
+	last := totalPages
+	if last < 1 {
+		last = 1
+	}
+
+	links := []string{
+		pageLink(r.URL, 1, pageSize, "first"),
+	}
+	if page > 1 {
+		prev := page - 1
+		if prev > last {
+			prev = last
+		}
+		links = append(links, pageLink(r.URL, prev, pageSize, "prev"))
+	}
+	if page < last {
+		links = append(links, pageLink(r.URL, page+1, pageSize, "next"))
+	}
+	links = append(links, pageLink(r.URL, last, pageSize, "last"))
+
+	w.Header().Set("Link", strings.Join(links, ", "))
+}
+
+// pageLink renders a single Link entry pointing at the given page of the current request
+func pageLink(u *url.URL, page, pageSize int, rel string) string {
+	query := u.Query()
+	query.Set("page", strconv.Itoa(page))
+	query.Set("page_size", strconv.Itoa(pageSize))
+
+	target := url.URL{Path: u.Path, RawQuery: query.Encode()}
+	return fmt.Sprintf(`<%s>; rel="%s"`, target.String(), rel)
+}
@@ -0,0 +1,42 @@
+package handler
+
+import (
+	"net/http"
+	"path"
+
+	"github.com/gorilla/mux"
+)
+
+// canonicalPath cleans a request path: duplicate slashes, . and ..
+// segments and the trailing slash are removed, so /api/v1/services/ and
+// //api/v1/services name /api/v1/services
+func canonicalPath(p string) string {
+	if p == "" {
+		return "/"
+	}
+	return path.Clean("/" + p)
+}
+
+// canonicalPaths is the not found handler of router. A request whose path
+// is not canonical is routed again with the canonical path, rather than
+// redirected, so that writes keep their method and body; other requests are
+// not found.
+func canonicalPaths(router *mux.Router) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		canonical := canonicalPath(r.URL.Path)
+		if canonical == r.URL.Path {
+			http.NotFound(w, r)
+			return
+		}
+
+		rewritten := new(http.Request)
+		*rewritten = *r
+		url := *r.URL
+		url.Path, url.RawPath = canonical, ""
+		if r.URL.RawPath != "" {
+			url.RawPath = canonicalPath(r.URL.RawPath)
+		}
+		rewritten.URL = &url
+		router.ServeHTTP(w, rewritten)
+	})
+}
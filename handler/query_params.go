@@ -0,0 +1,183 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"com.kong.connect/problem"
+)
+
+// queryParser reads query parameters against a fixed set of allowed names.
+// In strict mode unknown, repeated and malformed parameters are collected as
+// problems; in lenient mode they are ignored and the defaults apply.
+type queryParser struct {
+	values   url.Values
+	strict   bool
+	problems []problem.InvalidParam
+}
+
+// newQueryParser creates a parser for the request, rejecting parameters outside allowed in strict mode
+func newQueryParser(r *http.Request, strict bool, allowed ...string) *queryParser {
+	p := &queryParser{values: r.URL.Query(), strict: strict}
+	if !strict {
+		return p
+	}
+
+	known := make(map[string]struct{}, len(allowed))
+	for _, name := range allowed {
+		known[name] = struct{}{}
+	}
+	for name, values := range p.values {
+		if _, ok := known[name]; !ok {
+			p.invalid(name, "unknown parameter")
+			continue
+		}
+		if len(values) > 1 {
+			p.invalid(name, "must not be repeated")
+		}
+	}
+	return p
+}
+
+// String returns the raw value of a parameter
+func (p *queryParser) String(name string) string {
+	return p.values.Get(name)
+}
+
+// Required returns the trimmed value of a parameter that must be present, in lenient mode too
+func (p *queryParser) Required(name string) string {
+	value := strings.TrimSpace(p.values.Get(name))
+	if value == "" {
+		p.problems = append(p.problems, problem.InvalidParam{Name: name, Reason: "is required"})
+	}
+	return value
+}
+
+// PositiveInt returns a parameter parsed as an integer greater than zero, or def when absent
+func (p *queryParser) PositiveInt(name string, def int) int {
+	raw := p.values.Get(name)
+	if raw == "" {
+		return def
+	}
+
+	value, err := strconv.Atoi(raw)
+	if err != nil || value <= 0 {
+		p.invalid(name, "must be a positive integer")
+		return def
+	}
+	return value
+}
+
+// PageSize returns the page_size parameter, an integer from 1 to max, or def
+// when absent; larger sizes are rejected, or clamped to max in lenient mode
+func (p *queryParser) PageSize(def, max int) int {
+	pageSize := p.PositiveInt("page_size", def)
+	if pageSize > max {
+		p.invalid("page_size", fmt.Sprintf("must be at most %d", max))
+		return max
+	}
+	return pageSize
+}
+
+// OneOf returns a parameter that must be one of the allowed values, or "" when absent or invalid
+func (p *queryParser) OneOf(name string, allowed ...string) string {
+	raw := p.values.Get(name)
+	if raw == "" {
+		return ""
+	}
+
+	for _, value := range allowed {
+		if raw == value {
+			return raw
+		}
+	}
+	p.invalid(name, fmt.Sprintf("must be one of: %s", strings.Join(allowed, ", ")))
+	return ""
+}
+
+// Bool returns a parameter parsed as true or false, or false when absent or invalid
+func (p *queryParser) Bool(name string) bool {
+	raw := p.values.Get(name)
+	if raw == "" {
+		return false
+	}
+
+	value, err := strconv.ParseBool(raw)
+	if err != nil {
+		p.invalid(name, "must be true or false")
+		return false
+	}
+	return value
+}
+
+// OptionalBool returns a parameter parsed as true or false, or nil when absent
+// or invalid
+func (p *queryParser) OptionalBool(name string) *bool {
+	raw := p.values.Get(name)
+	if raw == "" {
+		return nil
+	}
+
+	value, err := strconv.ParseBool(raw)
+	if err != nil {
+		p.invalid(name, "must be true or false")
+		return nil
+	}
+	return &value
+}
+
+// Time returns a parameter parsed as an RFC 3339 timestamp, or the zero time when absent or invalid
+func (p *queryParser) Time(name string) time.Time {
+	raw := p.values.Get(name)
+	if raw == "" {
+		return time.Time{}
+	}
+
+	value, err := time.Parse(time.RFC3339, raw)
+	if err != nil {
+		p.invalid(name, "must be an RFC 3339 timestamp")
+		return time.Time{}
+	}
+	return value
+}
+
+// invalid records a problem with a parameter, unless the parser is lenient.
+// Names are recorded as JSON will encode them, so that they sort as shown.
+func (p *queryParser) invalid(name, reason string) {
+	if p.strict {
+		p.problems = append(p.problems, problem.InvalidParam{Name: strings.ToValidUTF8(name, "\uFFFD"), Reason: reason})
+	}
+}
+
+// ValidatePage writes a 400 problem response and returns false when page is
+// beyond the last of totalPages, so that clients paging past the end learn
+// of it instead of getting an empty page. The first page is valid also
+// without results.
+func (p *queryParser) ValidatePage(w http.ResponseWriter, r *http.Request, page, totalPages int) bool {
+	if last := max(totalPages, 1); page > last {
+		p.invalid("page", fmt.Sprintf("must be at most %d, the last page", last))
+	}
+	return p.Validate(w, r)
+}
+
+// Validate writes a 400 problem response and returns false when any parameter was rejected
+func (p *queryParser) Validate(w http.ResponseWriter, r *http.Request) bool {
+	if len(p.problems) == 0 {
+		return true
+	}
+	sort.SliceStable(p.problems, func(i, j int) bool { return p.problems[i].Name < p.problems[j].Name })
+
+	problem.Write(w, r, problem.Details{
+		Type:          problem.TypeInvalidQuery,
+		Title:         "Invalid query parameters",
+		Status:        http.StatusBadRequest,
+		Detail:        "One or more query parameters are unknown or have invalid values",
+		InvalidParams: p.problems,
+	})
+	return false
+}
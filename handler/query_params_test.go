@@ -0,0 +1,82 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"com.kong.connect/domain"
+	"com.kong.connect/problem"
+)
+
+// FuzzServiceListQuery lists services with arbitrary query strings, in
+// strict and lenient mode. The service must only ever be asked for valid
+// queries, and anything else be answered with a problem naming the
+// parameters at fault.
+func FuzzServiceListQuery(f *testing.F) {
+	for _, query := range []string{
+		"",
+		"page=2&page_size=5&sort_by=name&sort_dir=desc",
+		"search=contact&environment=prod&has_versions=true&min_versions=2&favorites=1",
+		"page=0&page_size=-1&min_versions=abc",
+		"page=99999999999999999999&page_size=1000",
+		"sort_by=owner&sort_dir=sideways&has_versions=maybe",
+		"page=1&page=2&colour=red",
+		"search=%25%27%3B+DROP+TABLE+services%3B+--",
+		"search=%00&environment=%ff",
+		"page=+1&page_size=0x10",
+		"%zz=1&;&=",
+	} {
+		f.Add(query, true)
+		f.Add(query, false)
+	}
+
+	f.Fuzz(func(t *testing.T, rawQuery string, strict bool) {
+		var got *domain.ServiceQuery
+		var opts []HandlerOption
+		if !strict {
+			opts = append(opts, WithLenientQueryParams())
+		}
+		svc := newMockService(t)
+		svc.EXPECT().GetServices(gomock.Any(), gomock.Any()).MaxTimes(1).DoAndReturn(func(ctx context.Context, query domain.ServiceQuery) (*domain.ServiceListResponse, error) {
+			got = &query
+			return &domain.ServiceListResponse{Services: []domain.ServiceWithVersions{}, Page: query.Page, PageSize: query.PageSize}, nil
+		})
+		h := NewServiceHandler(svc, opts...)
+
+		r := httptest.NewRequest(http.MethodGet, "/api/v1/services", nil)
+		r.URL.RawQuery = rawQuery
+		w := httptest.NewRecorder()
+		h.GetServices(w, r)
+
+		if got != nil {
+			assert.GreaterOrEqual(t, got.Page, 1)
+			assert.True(t, got.PageSize >= 1 && got.PageSize <= maxPageSize, "page size %d", got.PageSize)
+			assert.GreaterOrEqual(t, got.MinVersions, 0)
+			assert.Contains(t, []string{"", "name", "created_at", "updated_at"}, got.SortBy)
+			assert.Contains(t, []string{"", "asc", "desc"}, got.SortDir)
+		}
+		switch w.Code {
+		case http.StatusOK:
+			require.NotNil(t, got)
+		case http.StatusBadRequest:
+			require.True(t, strict, "lenient queries are never rejected: %s", w.Body.String())
+			var details problem.Details
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &details))
+			require.NotEmpty(t, details.InvalidParams)
+			assert.True(t, slices.IsSortedFunc(details.InvalidParams, func(a, b problem.InvalidParam) int {
+				return strings.Compare(a.Name, b.Name)
+			}))
+		default:
+			t.Fatalf("unexpected status %d: %s", w.Code, w.Body.String())
+		}
+	})
+}
@@ -0,0 +1,256 @@
+package handler
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"com.kong.connect/i18n"
+	"com.kong.connect/problem"
+)
+
+// decodeJSON decodes the request body into v, writing a problem response and
+// returning false when the body is too large or malformed. Unknown fields,
+// at any depth, are rejected so that typos such as "descripton" do not go
+// unnoticed; the problem lists each of them, and fields of the wrong type,
+// in its invalid_params.
+func decodeJSON(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	body, ok := readBody(w, r)
+	if !ok {
+		return false
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.DisallowUnknownFields()
+	err := decoder.Decode(v)
+	if err == nil {
+		return true
+	}
+
+	if params := invalidFields(body, reflect.TypeOf(v), err); len(params) > 0 {
+		problem.Write(w, r, problem.Details{
+			Type:          problem.TypeInvalidBody,
+			Title:         "Invalid request body",
+			Status:        http.StatusBadRequest,
+			Detail:        "One or more fields of the request body are unknown or have invalid values",
+			InvalidParams: params,
+		})
+		return false
+	}
+	writeBodyError(w, r, err)
+	return false
+}
+
+// readBody reads the raw request body, writing a problem response and
+// returning false when the body is too large or cannot be read
+func readBody(w http.ResponseWriter, r *http.Request) ([]byte, bool) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		writeBodyError(w, r, err)
+		return nil, false
+	}
+	return body, true
+}
+
+// writeBodyError reports a request body that could not be read or decoded
+func writeBodyError(w http.ResponseWriter, r *http.Request, err error) {
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		problem.Error(w, r, http.StatusRequestEntityTooLarge, i18n.Sprintf(i18n.FromContext(r.Context()), "Request body must not exceed %d bytes", tooLarge.Limit))
+		return
+	}
+	problem.Error(w, r, http.StatusBadRequest, "Invalid request body")
+}
+
+// invalidFields explains err, the error of decoding body into a value of
+// type t, field by field. It returns nil when err is not about fields, such
+// as malformed JSON.
+func invalidFields(body []byte, t reflect.Type, err error) []problem.InvalidParam {
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) && typeErr.Field != "" {
+		return []problem.InvalidParam{{Name: typeErr.Field, Reason: "must be " + jsonKind(typeErr.Type)}}
+	}
+	if !strings.HasPrefix(err.Error(), "json: unknown field ") {
+		return nil
+	}
+
+	// The decoder stops at the first unknown field; walk the body to list
+	// all of them
+	var value interface{}
+	if json.Unmarshal(body, &value) != nil {
+		return nil
+	}
+	var params []problem.InvalidParam
+	collectUnknownFields(value, t, "", &params)
+	sort.SliceStable(params, func(i, j int) bool { return params[i].Name < params[j].Name })
+	return params
+}
+
+// collectUnknownFields appends the fields of value, decoded from JSON, that
+// a value of type t has no field for. Fields match case-insensitively, like
+// encoding/json matches them.
+func collectUnknownFields(value interface{}, t reflect.Type, path string, params *[]problem.InvalidParam) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	// Types decoding themselves accept whatever they accept
+	if reflect.PointerTo(t).Implements(reflect.TypeFor[json.Unmarshaler]()) ||
+		reflect.PointerTo(t).Implements(reflect.TypeFor[encoding.TextUnmarshaler]()) {
+		return
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return
+		}
+		fields := jsonFields(t)
+		for key, item := range object {
+			field, ok := lookupField(fields, key)
+			if !ok {
+				reason := "unknown field"
+				if suggestion := closestField(fields, key); suggestion != "" {
+					reason += fmt.Sprintf(", did you mean %q?", suggestion)
+				}
+				*params = append(*params, problem.InvalidParam{Name: joinPath(path, key), Reason: reason})
+				continue
+			}
+			collectUnknownFields(item, field.Type, joinPath(path, key), params)
+		}
+	case reflect.Map:
+		if object, ok := value.(map[string]interface{}); ok {
+			for key, item := range object {
+				collectUnknownFields(item, t.Elem(), joinPath(path, key), params)
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		if array, ok := value.([]interface{}); ok {
+			for i, item := range array {
+				collectUnknownFields(item, t.Elem(), joinPath(path, strconv.Itoa(i)), params)
+			}
+		}
+	}
+}
+
+// jsonFields returns the fields of struct type t by their JSON names,
+// including the promoted fields of embedded structs
+func jsonFields(t reflect.Type) map[string]reflect.StructField {
+	fields := map[string]reflect.StructField{}
+	for _, field := range reflect.VisibleFields(t) {
+		if !field.IsExported() || len(field.Index) > 1 && !promoted(t, field) {
+			continue
+		}
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		embedded := field.Type
+		if embedded.Kind() == reflect.Pointer {
+			embedded = embedded.Elem()
+		}
+		if field.Anonymous && name == "" && embedded.Kind() == reflect.Struct {
+			continue // Its fields are visible themselves
+		}
+		if name == "" {
+			name = field.Name
+		}
+		fields[name] = field
+	}
+	return fields
+}
+
+// promoted reports whether a field of an embedded struct is promoted to t
+// by encoding/json, which only flattens untagged embedded structs
+func promoted(t reflect.Type, field reflect.StructField) bool {
+	for i := 1; i < len(field.Index); i++ {
+		embedded := t.FieldByIndex(field.Index[:i])
+		if name, _, _ := strings.Cut(embedded.Tag.Get("json"), ","); name != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// lookupField finds the field named key, preferring an exact match
+func lookupField(fields map[string]reflect.StructField, key string) (reflect.StructField, bool) {
+	if field, ok := fields[key]; ok {
+		return field, true
+	}
+	for name, field := range fields {
+		if strings.EqualFold(name, key) {
+			return field, true
+		}
+	}
+	return reflect.StructField{}, false
+}
+
+// closestField returns the field name within two edits of key, such as
+// "description" for "descripton", or ""
+func closestField(fields map[string]reflect.StructField, key string) string {
+	best, bestDistance := "", 3
+	for name := range fields {
+		if distance := editDistance(strings.ToLower(name), strings.ToLower(key)); distance < bestDistance || distance == bestDistance && name < best {
+			best, bestDistance = name, distance
+		}
+	}
+	return best
+}
+
+// editDistance is the Levenshtein distance between a and b
+func editDistance(a, b string) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(b)]
+}
+
+// joinPath names key within path the way encoding/json names fields, e.g.
+// endpoints.1.url
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// jsonKind describes the JSON values that decode into t
+func jsonKind(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.String:
+		return "a string"
+	case reflect.Bool:
+		return "a boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "an integer"
+	case reflect.Float32, reflect.Float64:
+		return "a number"
+	case reflect.Slice, reflect.Array:
+		return "an array"
+	case reflect.Map, reflect.Struct:
+		return "an object"
+	}
+	return "a " + t.String()
+}
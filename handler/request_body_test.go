@@ -0,0 +1,90 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"com.kong.connect/problem"
+)
+
+type endpointInput struct {
+	URL string `json:"url"`
+}
+
+type baseInput struct {
+	Name string `json:"name"`
+}
+
+type nestedInput struct {
+	baseInput
+	Internal  string                   `json:"-"`
+	Endpoints []endpointInput          `json:"endpoints"`
+	Labels    map[string]endpointInput `json:"labels"`
+	Primary   *endpointInput           `json:"primary"`
+	Meta      json.RawMessage          `json:"meta"`
+	Since     time.Time                `json:"since"`
+}
+
+func decodeTestBody(t *testing.T, body string) (*httptest.ResponseRecorder, bool) {
+	t.Helper()
+	w := httptest.NewRecorder()
+	var input nestedInput
+	ok := decodeJSON(w, httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body)), &input)
+	return w, ok
+}
+
+func TestDecodeJSONListsUnknownFieldsAtAnyDepth(t *testing.T) {
+	w, ok := decodeTestBody(t, `{
+		"name": "Billing",
+		"Internal": "x",
+		"endpoints": [{"url": "https://a"}, {"ulr": "https://b"}],
+		"labels": {"prod": {"url": "https://c", "weight": 1}},
+		"primary": {"uri": "https://d"},
+		"meta": {"anything": true},
+		"since": "2024-01-02T00:00:00Z"
+	}`)
+	require.False(t, ok)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	var details problem.Details
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &details))
+	assert.Equal(t, problem.TypeInvalidBody, details.Type)
+	assert.Equal(t, []problem.InvalidParam{
+		{Name: "Internal", Reason: "unknown field"},
+		{Name: "endpoints.1.ulr", Reason: `unknown field, did you mean "url"?`},
+		{Name: "labels.prod.weight", Reason: "unknown field"},
+		{Name: "primary.uri", Reason: `unknown field, did you mean "url"?`},
+	}, details.InvalidParams)
+}
+
+func TestDecodeJSONAcceptsKnownFields(t *testing.T) {
+	_, ok := decodeTestBody(t, `{"NAME": "Billing", "endpoints": [{"url": "https://a"}], "meta": {"anything": true}}`)
+	assert.True(t, ok)
+}
+
+func TestDecodeJSONReportsFieldsOfTheWrongType(t *testing.T) {
+	w, ok := decodeTestBody(t, `{"endpoints": [{"url": 42}]}`)
+	require.False(t, ok)
+
+	var details problem.Details
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &details))
+	assert.Equal(t, []problem.InvalidParam{{Name: "endpoints.0.url", Reason: "must be a string"}}, details.InvalidParams)
+}
+
+func TestDecodeJSONRejectsMalformedBodies(t *testing.T) {
+	w, ok := decodeTestBody(t, `{"name": `)
+	require.False(t, ok)
+
+	var details problem.Details
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &details))
+	assert.Equal(t, http.StatusBadRequest, details.Status)
+	assert.Equal(t, "Invalid request body", details.Detail)
+	assert.Empty(t, details.InvalidParams)
+}
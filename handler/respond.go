@@ -0,0 +1,71 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+
+	"com.kong.connect/domain"
+	"com.kong.connect/jsonenc"
+)
+
+// maxPooledBuffer keeps the buffers of unusually large responses, such as an
+// export-sized listing, from being held by the pool
+const maxPooledBuffer = 1 << 20
+
+// responseBuffers are reused across responses, so that encoding a response
+// allocates nothing once the pool is warm
+var responseBuffers = sync.Pool{
+	New: func() interface{} { return new(bytes.Buffer) },
+}
+
+// writeJSON encodes v in full before writing anything, so that the response
+// carries its Content-Length and an encoding error, or a response over the
+// size limit, still turns into a 500
+func (h *ServiceHandler) writeJSON(w http.ResponseWriter, r *http.Request, status int, v interface{}) {
+	buf := responseBuffers.Get().(*bytes.Buffer)
+	buf.Reset()
+	defer func() {
+		if buf.Cap() <= maxPooledBuffer {
+			responseBuffers.Put(buf)
+		}
+	}()
+
+	if err := h.encodeJSON(buf, v); err != nil {
+		h.internalError(w, r, "failed to encode response", err)
+		return
+	}
+	if h.maxResponseBytes > 0 && buf.Len() > h.maxResponseBytes {
+		h.internalError(w, r, "response too large", fmt.Errorf("response of %d bytes exceeds the limit of %d bytes", buf.Len(), h.maxResponseBytes))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Length", strconv.Itoa(buf.Len()))
+	w.WriteHeader(status)
+	w.Write(buf.Bytes())
+}
+
+// encodeJSON writes v to buf followed by a newline, as json.Encoder does
+func (h *ServiceHandler) encodeJSON(buf *bytes.Buffer, v interface{}) error {
+	if h.fastJSON {
+		var encoded []byte
+		var err error
+		switch v := v.(type) {
+		case *domain.ServiceListResponse:
+			encoded, err = jsonenc.AppendServiceList(buf.AvailableBuffer(), v)
+		case *domain.ServiceWithVersions:
+			encoded, err = jsonenc.AppendService(buf.AvailableBuffer(), v)
+		}
+		// The times jsonenc rejects fall through to encoding/json, which
+		// rejects them too
+		if encoded != nil && err == nil {
+			buf.Write(encoded)
+			return buf.WriteByte('\n')
+		}
+	}
+	return json.NewEncoder(buf).Encode(v)
+}
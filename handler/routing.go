@@ -1,9 +1,24 @@
 package handler
 
 import (
+	"com.kong.connect/cdn"
+	"com.kong.connect/errreport"
+	"com.kong.connect/features"
+	"com.kong.connect/health"
+	"com.kong.connect/logging"
+	"com.kong.connect/metrics"
 	"com.kong.connect/middleware"
-	"log"
+	"com.kong.connect/ratelimit"
+	"com.kong.connect/realtime"
+	"com.kong.connect/web"
+	"io"
+	"log/slog"
 	"net/http"
+	"net/netip"
+	"os"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/gorilla/mux"
 )
@@ -12,21 +27,732 @@ type Route struct {
 	Path    string
 	Method  string
 	Handler http.HandlerFunc
+	// Roles allowed to call the route; routes without roles are public
+	Roles []string
+	// DeploymentWide routes manage the whole deployment rather than one
+	// organization's catalog, and refuse tokens issued in an organization
+	DeploymentWide bool
 }
 
-func SetupRouter(serviceHandler *ServiceHandler) *mux.Router {
-	router := mux.NewRouter()
+// routerConfig holds the optional features of the router
+type routerConfig struct {
+	hub             *realtime.Hub
+	logger          *slog.Logger
+	accessLogFormat string
+	accessLogOutput io.Writer
+	debugBodyBytes  int
+	debug           bool
+	requestTimeout  time.Duration
+	maxInFlight     int
+	queueTimeout    time.Duration
+	rateLimitStore  ratelimit.Store
+	rateLimits      *ratelimit.Policy
+	maxBodyBytes    int64
+	cors            *middleware.CORS
+	trustedProxies  []netip.Prefix
+	errorReporter   errreport.Reporter
+	metrics         *metrics.Registry
+	features        *features.Store
+	readiness       *health.Checker
+	drainGrace      time.Duration
+	httpCache       middleware.HTTPCache
+	faults          middleware.Faults
+	ui              http.Handler
+}
+
+// RouterOption enables an optional feature of the router
+type RouterOption func(*routerConfig)
+
+// WithWebSocket mounts the change notification socket at /ws
+func WithWebSocket(hub *realtime.Hub) RouterOption {
+	return func(c *routerConfig) {
+		c.hub = hub
+	}
+}
+
+// WithRequestLogger sets the logger used to log incoming requests
+func WithRequestLogger(logger *slog.Logger) RouterOption {
+	return func(c *routerConfig) {
+		c.logger = logger
+	}
+}
+
+// WithAccessLog selects the access log format (structured, json, combined or
+// off); the json and combined formats are written to out
+func WithAccessLog(format string, out io.Writer) RouterOption {
+	return func(c *routerConfig) {
+		c.accessLogFormat = format
+		c.accessLogOutput = out
+	}
+}
+
+// WithDebugLog logs the headers and bodies, up to maxBytes, of every request
+// and response at debug level, with their secrets redacted
+func WithDebugLog(maxBytes int) RouterOption {
+	return func(c *routerConfig) {
+		c.debugBodyBytes = maxBytes
+	}
+}
+
+// WithDebugEndpoints mounts the admin-only pprof and expvar endpoints under /debug
+func WithDebugEndpoints() RouterOption {
+	return func(c *routerConfig) {
+		c.debug = true
+	}
+}
+
+// WithRequestTimeout cancels API requests that run longer than timeout, or
+// than the deadline of the client when shorter, and answers them with 503.
+// WebSocket and debug endpoints are exempt.
+func WithRequestTimeout(timeout time.Duration) RouterOption {
+	return func(c *routerConfig) {
+		c.requestTimeout = timeout
+	}
+}
+
+// WithConcurrencyLimit bounds the API requests handled at once to limit;
+// further requests wait up to queueTimeout for a slot and are then answered
+// with 503. Health checks and WebSocket connections are exempt.
+func WithConcurrencyLimit(limit int, queueTimeout time.Duration) RouterOption {
+	return func(c *routerConfig) {
+		c.maxInFlight = limit
+		c.queueTimeout = queueTimeout
+	}
+}
+
+// WithRateLimit limits API requests per client. Limits are looked up in the
+// policy by route group (read, search or write), falling back to the
+// "default" group; groups without a limit are not limited.
+func WithRateLimit(store ratelimit.Store, policy *ratelimit.Policy) RouterOption {
+	return func(c *routerConfig) {
+		c.rateLimitStore = store
+		c.rateLimits = policy
+	}
+}
+
+// WithMaxBodySize caps the request body of write endpoints at limit bytes (default 1 MiB)
+func WithMaxBodySize(limit int64) RouterOption {
+	return func(c *routerConfig) {
+		c.maxBodyBytes = limit
+	}
+}
+
+// WithCORS sets the CORS policy; by default every origin is allowed
+func WithCORS(cors *middleware.CORS) RouterOption {
+	return func(c *routerConfig) {
+		c.cors = cors
+	}
+}
+
+// WithTrustedProxies believes the client IP that the proxies in trusted
+// forward in X-Forwarded-For or X-Real-IP
+func WithTrustedProxies(trusted []netip.Prefix) RouterOption {
+	return func(c *routerConfig) {
+		c.trustedProxies = trusted
+	}
+}
+
+// WithErrorReporter sends panics and 5xx responses to reporter
+func WithErrorReporter(reporter errreport.Reporter) RouterOption {
+	return func(c *routerConfig) {
+		c.errorReporter = reporter
+	}
+}
+
+// WithMetrics records request latencies and latency SLOs in registry and
+// serves it in the Prometheus format at /metrics
+func WithMetrics(registry *metrics.Registry) RouterOption {
+	return func(c *routerConfig) {
+		c.metrics = registry
+	}
+}
+
+// WithFeatures exposes the feature flags of store to handlers through the request context
+func WithFeatures(store *features.Store) RouterOption {
+	return func(c *routerConfig) {
+		c.features = store
+	}
+}
+
+// WithReadiness serves the dependency checks of checker at /readyz; the
+// instance is unready, with status 503, while a critical dependency is down
+func WithReadiness(checker *health.Checker) RouterOption {
+	return func(c *routerConfig) {
+		c.readiness = checker
+	}
+}
+
+// WithDrain mounts the admin-only POST /drain, which fails /readyz and
+// shuts the server down once grace has passed so that load balancers stop
+// routing new traffic first. It requires WithReadiness.
+func WithDrain(grace time.Duration) RouterOption {
+	return func(c *routerConfig) {
+		c.drainGrace = grace
+	}
+}
+
+// WithHTTPCache lets browsers, CDNs and proxies cache successful catalog
+// reads under policy, tagged with the surrogate keys of the cdn package
+func WithHTTPCache(policy middleware.HTTPCache) RouterOption {
+	return func(c *routerConfig) {
+		c.httpCache = policy
+	}
+}
+
+// WithFaultInjection injects faults into a share of the API requests, for
+// clients to test their retries against. Health checks and WebSocket
+// connections are exempt.
+func WithFaultInjection(faults middleware.Faults) RouterOption {
+	return func(c *routerConfig) {
+		c.faults = faults
+	}
+}
+
+// WithUI serves ui at every GET path that is not an API, WebSocket, metrics,
+// debug or health path; ui typically serves the embedded web UI
+func WithUI(ui http.Handler) RouterOption {
+	return func(c *routerConfig) {
+		c.ui = ui
+	}
+}
+
+func SetupRouter(serviceHandler *ServiceHandler, opts ...RouterOption) *mux.Router {
+	// Paths are cleaned by canonicalPaths, which routes them again instead of
+	// redirecting
+	router := mux.NewRouter().SkipClean(true)
+	router.NotFoundHandler = canonicalPaths(router)
+
+	config := routerConfig{
+		logger:          slog.Default(),
+		accessLogFormat: middleware.AccessLogStructured,
+		accessLogOutput: os.Stdout,
+		maxBodyBytes:    1 << 20,
+		cors:            middleware.NewCORS(nil),
+		errorReporter:   errreport.Nop{},
+	}
+	for _, opt := range opts {
+		opt(&config)
+	}
+
+	routes := apiRoutes(serviceHandler)
+	for i := range routes {
+		if routes[i].DeploymentWide {
+			routes[i].Handler = middleware.DeploymentWide(routes[i].Handler).ServeHTTP
+		}
+		if len(routes[i].Roles) > 0 {
+			routes[i].Handler = middleware.AuthorizeRoles(routes[i].Handler, routes[i].Roles...)
+		}
+	}
 
-	routes := []Route{
+	if config.httpCache.MaxAge > 0 {
+		for i := range routes {
+			if routes[i].Method == "GET" && slices.Contains(routes[i].Roles, "viewer") {
+				routes[i].Handler = middleware.CacheControl(config.httpCache, surrogateKeys(routes[i]))(routes[i].Handler).ServeHTTP
+			}
+		}
+	}
+
+	bodyLimit := middleware.MaxBodySize(config.maxBodyBytes)
+	for i := range routes {
+		if routes[i].Method != "GET" {
+			routes[i].Handler = bodyLimit(routes[i].Handler).ServeHTTP
+		}
+	}
+
+	// Applied without a request timeout too, for the deadlines of clients
+	timeout := middleware.Timeout(config.requestTimeout)
+	for i := range routes {
+		routes[i].Handler = timeout(routes[i].Handler).ServeHTTP
+	}
+
+	// Applied outside the timeout so that queueing does not eat into it, and
+	// inside the rate limit so that limited clients don't take a place in the
+	// queue
+	if config.maxInFlight > 0 {
+		limit := middleware.ConcurrencyLimit(config.maxInFlight, config.queueTimeout)
+		for i := range routes {
+			if routes[i].Path != "/health" {
+				routes[i].Handler = limit(routes[i].Handler).ServeHTTP
+			}
+		}
+	}
+
+	if config.rateLimitStore != nil {
+		logger := logging.Component(config.logger, "http")
+		for i := range routes {
+			if routes[i].Path == "/health" {
+				continue
+			}
+			limit := middleware.RateLimit(config.rateLimitStore, config.rateLimits, rateLimitGroup(routes[i]), logger)
+			routes[i].Handler = limit(routes[i].Handler).ServeHTTP
+		}
+	}
+
+	// Applied outermost, where the network would fail requests, so that
+	// injected faults take no rate limit or place in the queue
+	if config.faults.Enabled() {
+		inject := middleware.FaultInjection(config.faults)
+		for i := range routes {
+			if routes[i].Path != "/health" {
+				routes[i].Handler = inject(routes[i].Handler).ServeHTTP
+			}
+		}
+	}
+
+	if config.hub != nil {
+		routes = append(routes, Route{
+			Path:    "/ws",
+			Method:  "GET",
+			Handler: config.hub.ServeWS, // Authenticates during the handshake
+		})
+	}
+
+	for _, route := range routes {
+		router.HandleFunc(route.Path, route.Handler).Methods(route.Method)
+	}
+
+	if config.metrics != nil {
+		router.Handle("/metrics", config.metrics.Handler()).Methods("GET") // Scraped without auth, like /health
+	}
+
+	if config.readiness != nil {
+		router.Handle("/readyz", config.readiness.Handler()).Methods("GET") // Probed without auth, like /health
+		if config.drainGrace > 0 {
+			router.HandleFunc("/drain", middleware.AuthorizeRoles(config.readiness.DrainHandler(config.drainGrace).ServeHTTP, "admin")).Methods("POST")
+		}
+	}
+
+	if config.debug {
+		mountDebugRoutes(router)
+	}
+
+	// Registered last so that every other route takes precedence; unknown API
+	// paths are excluded and keep answering 404 instead of the UI. The
+	// matcher comes first: a matching path prefix would make mux forget that
+	// an earlier route matched the path but not the method.
+	if config.ui != nil {
+		router.MatcherFunc(func(r *http.Request, _ *mux.RouteMatch) bool { return web.Serves(canonicalPath(r.URL.Path)) }).
+			PathPrefix("/").Methods("GET", "HEAD").
+			Handler(config.ui)
+	}
+
+	// The layers of the stack fix the order: the server timing covers all
+	// the middleware, and panics are recovered before the request is logged.
+	// Routes authenticate and authorize in the inner layers of their own
+	// stack, see middleware.AuthorizeRoles.
+	stack := &middleware.Stack{}
+	stack.Use(middleware.LayerInstrumentation, middleware.ResponseMeta(APIVersion), middleware.Tracing)
+	if config.metrics != nil {
+		stack.Use(middleware.LayerInstrumentation, middleware.Metrics(config.metrics))
+	}
+	stack.Use(middleware.LayerRecovery, middleware.ErrorReporting(config.errorReporter, logging.Component(config.logger, "http")))
+	stack.Use(middleware.LayerRequestID, middleware.RequestID, middleware.ClientIP(config.trustedProxies), middleware.Locale)
+	if config.features != nil {
+		stack.Use(middleware.LayerRequestID, middleware.Features(config.features))
+	}
+	stack.Use(middleware.LayerLogging, middleware.AccessLog(config.accessLogFormat, logging.Component(config.logger, "http"), config.accessLogOutput))
+	if config.debugBodyBytes > 0 {
+		stack.Use(middleware.LayerLogging, middleware.DebugLog(logging.Component(config.logger, "http"), config.debugBodyBytes))
+	}
+	stack.Use(middleware.LayerCORS, config.cors.Middleware)
+	router.Use(stack.Then)
+	// Requests matching a path but not its methods bypass the middleware of
+	// the router, so the stack wraps their handler too
+	router.MethodNotAllowedHandler = stack.Then(methodNotAllowed(router))
+
+	return router
+}
+
+// APIVersion is the version of the API served under /api/v1, reported in the
+// API-Version header of every response
+const APIVersion = "v1"
+
+// apiRoutes is the route table of the API. SetupRouter applies authorization,
+// body limits, timeouts and rate limits to every entry, so new routes get the
+// same treatment as existing ones.
+func apiRoutes(serviceHandler *ServiceHandler) []Route {
+	return []Route{
 		{
 			Path:    "/api/v1/services",
 			Method:  "GET",
-			Handler: middleware.AuthorizeRoles(serviceHandler.GetServices, "admin", "viewer"),
+			Handler: serviceHandler.GetServices,
+			Roles:   []string{"admin", "viewer"},
+		},
+		{
+			Path:    "/api/v1/services/{id}",
+			Method:  "GET",
+			Handler: serviceHandler.GetServiceByID,
+			Roles:   []string{"admin", "viewer"},
+		},
+		{
+			Path:    "/api/v1/services",
+			Method:  "POST",
+			Handler: serviceHandler.CreateService,
+			Roles:   []string{"admin"},
+		},
+		{
+			Path:    "/api/v1/services:import",
+			Method:  "POST",
+			Handler: serviceHandler.ImportDefinition,
+			Roles:   []string{"admin"},
 		},
 		{
 			Path:    "/api/v1/services/{id}",
+			Method:  "PUT",
+			Handler: serviceHandler.UpdateService,
+			Roles:   []string{"admin"},
+		},
+		{
+			Path:    "/api/v1/services/{id}",
+			Method:  "DELETE",
+			Handler: serviceHandler.DeleteService,
+			Roles:   []string{"admin"},
+		},
+		{
+			Path:    "/api/v1/services/{id}/versions",
+			Method:  "GET",
+			Handler: serviceHandler.GetServiceVersions,
+			Roles:   []string{"admin", "viewer"},
+		},
+		{
+			Path:    "/api/v1/services/{id}/versions",
+			Method:  "POST",
+			Handler: serviceHandler.CreateVersion,
+			Roles:   []string{"admin"},
+		},
+		{
+			Path:    "/api/v1/services/{id}/versions/compare",
+			Method:  "GET",
+			Handler: serviceHandler.CompareVersions,
+			Roles:   []string{"admin", "viewer"},
+		},
+		{
+			Path:    "/api/v1/services/{id}/versions/{versionId}",
+			Method:  "DELETE",
+			Handler: serviceHandler.DeleteVersion,
+			Roles:   []string{"admin"},
+		},
+		{
+			Path:    "/api/v1/services/{id}/health",
+			Method:  "GET",
+			Handler: serviceHandler.GetHealthHistory,
+			Roles:   []string{"admin", "viewer"},
+		},
+		{
+			Path:    "/api/v1/services/{id}/health-check",
+			Method:  "PUT",
+			Handler: serviceHandler.SetHealthCheck,
+			Roles:   []string{"admin"},
+		},
+		{
+			Path:    "/api/v1/services/{id}/health-check",
+			Method:  "DELETE",
+			Handler: serviceHandler.DeleteHealthCheck,
+			Roles:   []string{"admin"},
+		},
+		{
+			Path:    "/api/v1/services/{id}/repository",
+			Method:  "PUT",
+			Handler: serviceHandler.SetSourceRepository,
+			Roles:   []string{"admin"},
+		},
+		{
+			Path:    "/api/v1/services/{id}/repository",
+			Method:  "DELETE",
+			Handler: serviceHandler.DeleteSourceRepository,
+			Roles:   []string{"admin"},
+		},
+		{
+			Path:    "/api/v1/services/{id}/slo",
+			Method:  "PUT",
+			Handler: serviceHandler.SetSLO,
+			Roles:   []string{"admin"},
+		},
+		{
+			Path:    "/api/v1/services/{id}/slo",
+			Method:  "DELETE",
+			Handler: serviceHandler.DeleteSLO,
+			Roles:   []string{"admin"},
+		},
+		{
+			Path:    "/api/v1/services/{id}/translations/{language}",
+			Method:  "PUT",
+			Handler: serviceHandler.SetTranslation,
+			Roles:   []string{"admin"},
+		},
+		{
+			Path:    "/api/v1/services/{id}/translations/{language}",
+			Method:  "DELETE",
+			Handler: serviceHandler.DeleteTranslation,
+			Roles:   []string{"admin"},
+		},
+		{
+			Path:    "/api/v1/services/{id}/sunset",
+			Method:  "PUT",
+			Handler: serviceHandler.SetSunset,
+			Roles:   []string{"admin"},
+		},
+		{
+			Path:    "/api/v1/services/{id}/sunset",
+			Method:  "DELETE",
+			Handler: serviceHandler.DeleteSunset,
+			Roles:   []string{"admin"},
+		},
+		{
+			Path:    "/api/v1/services/{id}/versions/{versionId}/sunset",
+			Method:  "PUT",
+			Handler: serviceHandler.SetSunset,
+			Roles:   []string{"admin"},
+		},
+		{
+			Path:    "/api/v1/services/{id}/versions/{versionId}/sunset",
+			Method:  "DELETE",
+			Handler: serviceHandler.DeleteSunset,
+			Roles:   []string{"admin"},
+		},
+		{
+			Path:    "/api/v1/services/{id}/environments/{environment}",
+			Method:  "PUT",
+			Handler: serviceHandler.DeployVersion,
+			Roles:   []string{"admin"},
+		},
+		{
+			Path:    "/api/v1/services/{id}/environments/{environment}",
+			Method:  "DELETE",
+			Handler: serviceHandler.UndeployEnvironment,
+			Roles:   []string{"admin"},
+		},
+		{
+			Path:    "/api/v1/search",
 			Method:  "GET",
-			Handler: middleware.AuthorizeRoles(serviceHandler.GetServiceByID, "admin", "viewer"),
+			Handler: serviceHandler.SearchServices,
+			Roles:   []string{"admin", "viewer"},
+		},
+		{
+			Path:    "/api/v1/stats",
+			Method:  "GET",
+			Handler: serviceHandler.GetStats,
+			Roles:   []string{"admin", "viewer"},
+		},
+		{
+			Path:    "/api/v1/slo-report",
+			Method:  "GET",
+			Handler: serviceHandler.GetSLOReport,
+			Roles:   []string{"admin", "viewer"},
+		},
+		{
+			Path:    "/api/v1/analytics",
+			Method:  "GET",
+			Handler: serviceHandler.GetAnalytics,
+			Roles:   []string{"admin"},
+		},
+		{
+			Path:    "/api/v1/audit-logs",
+			Method:  "GET",
+			Handler: serviceHandler.GetAuditLogs,
+			Roles:   []string{"admin"},
+		},
+		{
+			Path:    "/api/v1/catalog:apply",
+			Method:  "POST",
+			Handler: serviceHandler.ApplyCatalog,
+			Roles:   []string{"admin"},
+		},
+		{
+			Path:           "/api/v1/users",
+			Method:         "GET",
+			Handler:        serviceHandler.ListUsers,
+			Roles:          []string{"admin"},
+			DeploymentWide: true,
+		},
+		{
+			Path:           "/api/v1/users",
+			Method:         "POST",
+			Handler:        serviceHandler.CreateUser,
+			Roles:          []string{"admin"},
+			DeploymentWide: true,
+		},
+		{
+			Path:           "/api/v1/users/{username}/disable",
+			Method:         "POST",
+			Handler:        serviceHandler.DisableUser,
+			Roles:          []string{"admin"},
+			DeploymentWide: true,
+		},
+		{
+			Path:           "/api/v1/organizations",
+			Method:         "GET",
+			Handler:        serviceHandler.ListOrganizations,
+			Roles:          []string{"admin"},
+			DeploymentWide: true,
+		},
+		{
+			Path:           "/api/v1/organizations",
+			Method:         "POST",
+			Handler:        serviceHandler.CreateOrganization,
+			Roles:          []string{"admin"},
+			DeploymentWide: true,
+		},
+		{
+			Path:    "/api/v1/tokens",
+			Method:  "GET",
+			Handler: serviceHandler.ListTokens,
+			Roles:   []string{"admin"},
+		},
+		{
+			Path:    "/api/v1/tokens",
+			Method:  "POST",
+			Handler: serviceHandler.IssueToken,
+			Roles:   []string{"admin"},
+		},
+		{
+			Path:    "/api/v1/tokens/{id}",
+			Method:  "DELETE",
+			Handler: serviceHandler.RevokeToken,
+			Roles:   []string{"admin"},
+		},
+		{
+			Path:    "/api/v1/webhooks",
+			Method:  "GET",
+			Handler: serviceHandler.ListWebhooks,
+			Roles:   []string{"admin"},
+		},
+		{
+			Path:    "/api/v1/webhooks",
+			Method:  "POST",
+			Handler: serviceHandler.CreateWebhook,
+			Roles:   []string{"admin"},
+		},
+		{
+			Path:    "/api/v1/webhooks/{id}",
+			Method:  "DELETE",
+			Handler: serviceHandler.DeleteWebhook,
+			Roles:   []string{"admin"},
+		},
+		{
+			Path:    "/api/v1/webhooks/{id}/deliveries",
+			Method:  "GET",
+			Handler: serviceHandler.ListWebhookDeliveries,
+			Roles:   []string{"admin"},
+		},
+		{
+			Path:    "/api/v1/services/{id}/versions/{versionId}/spec",
+			Method:  "GET",
+			Handler: serviceHandler.GetVersionSpec,
+			Roles:   []string{"admin", "viewer"},
+		},
+		{
+			Path:    "/api/v1/services/{id}/versions/{versionId}/spec",
+			Method:  "PUT",
+			Handler: serviceHandler.UploadVersionSpec,
+			Roles:   []string{"admin"},
+		},
+		{
+			Path:    "/api/v1/services/{id}/versions/{versionId}/spec",
+			Method:  "DELETE",
+			Handler: serviceHandler.DeleteVersionSpec,
+			Roles:   []string{"admin"},
+		},
+		{
+			Path:    "/api/v1/services/{id}/versions/{versionId}/docs",
+			Method:  "GET",
+			Handler: serviceHandler.GetVersionDocs,
+			Roles:   []string{"admin", "viewer"},
+		},
+		{
+			Path:    "/api/v1/services/{id}/versions/{versionId}/postman",
+			Method:  "GET",
+			Handler: serviceHandler.GetVersionPostman,
+			Roles:   []string{"admin", "viewer"},
+		},
+		{
+			Path:    "/api/v1/services/{id}/subscription",
+			Method:  "PUT",
+			Handler: serviceHandler.SubscribeService,
+			Roles:   []string{"admin", "viewer"},
+		},
+		{
+			Path:    "/api/v1/services/{id}/subscription",
+			Method:  "DELETE",
+			Handler: serviceHandler.UnsubscribeService,
+			Roles:   []string{"admin", "viewer"},
+		},
+		{
+			Path:    "/api/v1/services/{id}/comments",
+			Method:  "GET",
+			Handler: serviceHandler.GetComments,
+			Roles:   []string{"admin", "viewer"},
+		},
+		{
+			Path:    "/api/v1/services/{id}/comments",
+			Method:  "POST",
+			Handler: serviceHandler.CreateComment,
+			Roles:   []string{"admin", "viewer"},
+		},
+		{
+			Path:    "/api/v1/services/{id}/comments/{commentId}",
+			Method:  "DELETE",
+			Handler: serviceHandler.DeleteComment,
+			Roles:   []string{"admin", "viewer"},
+		},
+		{
+			Path:    "/api/v1/services/{id}/transfer-ownership",
+			Method:  "POST",
+			Handler: serviceHandler.TransferOwnership,
+			Roles:   []string{"admin", "viewer"},
+		},
+		{
+			Path:    "/api/v1/services/{id}/transfer-ownership/confirm",
+			Method:  "POST",
+			Handler: serviceHandler.ConfirmTransfer,
+			Roles:   []string{"admin", "viewer"},
+		},
+		{
+			Path:    "/api/v1/services/{id}/transfer-ownership/decline",
+			Method:  "POST",
+			Handler: serviceHandler.DeclineTransfer,
+			Roles:   []string{"admin", "viewer"},
+		},
+		{
+			Path:    "/api/v1/services/{id}/ownership-transfers",
+			Method:  "GET",
+			Handler: serviceHandler.GetTransfers,
+			Roles:   []string{"admin", "viewer"},
+		},
+		{
+			Path:    "/api/v1/services/{id}/favorite",
+			Method:  "PUT",
+			Handler: serviceHandler.FavoriteService,
+			Roles:   []string{"admin", "viewer"},
+		},
+		{
+			Path:    "/api/v1/services/{id}/favorite",
+			Method:  "DELETE",
+			Handler: serviceHandler.UnfavoriteService,
+			Roles:   []string{"admin", "viewer"},
+		},
+		{
+			Path:    "/api/v1/me/subscriptions",
+			Method:  "GET",
+			Handler: serviceHandler.ListSubscriptions,
+			Roles:   []string{"admin", "viewer"},
+		},
+		{
+			Path:    "/api/v1/me/notification-preferences",
+			Method:  "GET",
+			Handler: serviceHandler.GetNotificationPreferences,
+			Roles:   []string{"admin", "viewer"},
+		},
+		{
+			Path:    "/api/v1/me/notification-preferences",
+			Method:  "PUT",
+			Handler: serviceHandler.UpdateNotificationPreferences,
+			Roles:   []string{"admin", "viewer"},
+		},
+		{
+			Path:    "/api/v1/openapi.yaml",
+			Method:  "GET",
+			Handler: GetOpenAPI, // Public, for generating clients
 		},
 		{
 			Path:    "/health",
@@ -34,43 +760,32 @@ func SetupRouter(serviceHandler *ServiceHandler) *mux.Router {
 			Handler: healthCheckHandler, // No auth required
 		},
 	}
+}
 
-	for _, route := range routes {
-		router.HandleFunc(route.Path, route.Handler).Methods(route.Method)
+// rateLimitGroup classifies a route for rate limiting; search is limited
+// separately from other reads since it is the most expensive query
+func rateLimitGroup(route Route) string {
+	switch {
+	case route.Path == "/api/v1/search":
+		return "search"
+	case route.Method == "GET":
+		return "read"
+	default:
+		return "write"
 	}
+}
 
-	// Add middleware as usual
-	router.Use(corsMiddleware)
-	router.Use(loggingMiddleware)
-
-	return router
+// surrogateKeys tags the responses of a catalog read with what they contain,
+// so that a change of a service purges them
+func surrogateKeys(route Route) func(r *http.Request) []string {
+	if strings.Contains(route.Path, "{id}") {
+		return func(r *http.Request) []string { return []string{cdn.ServiceKey(mux.Vars(r)["id"])} }
+	}
+	return func(*http.Request) []string { return []string{cdn.ListingKey} }
 }
 
 func healthCheckHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
 	w.WriteHeader(http.StatusOK)
 	w.Write([]byte("OK"))
 }
-
-// corsMiddleware adds CORS headers
-func corsMiddleware(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Set("Access-Control-Allow-Origin", "*")
-		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
-
-		if r.Method == "OPTIONS" {
-			w.WriteHeader(http.StatusOK)
-			return
-		}
-
-		next.ServeHTTP(w, r)
-	})
-}
-
-// loggingMiddleware logs HTTP requests
-func loggingMiddleware(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		log.Printf("%s %s %s", r.Method, r.RequestURI, r.RemoteAddr)
-		next.ServeHTTP(w, r)
-	})
-}
@@ -0,0 +1,32 @@
+package handler
+
+import (
+	"net/http"
+
+	"com.kong.connect/domain"
+)
+
+// SearchServices handles GET /api/v1/search
+func (h *ServiceHandler) SearchServices(w http.ResponseWriter, r *http.Request) {
+	params := newQueryParser(r, h.strictQuery(r), "q", "page", "page_size")
+	query := domain.SearchQuery{
+		Query:    params.Required("q"),
+		Page:     params.PositiveInt("page", 1),
+		PageSize: params.PageSize(12, maxPageSize),
+	}
+	if !params.Validate(w, r) {
+		return
+	}
+
+	response, err := h.service.SearchServices(r.Context(), query)
+	if err != nil {
+		h.writeError(w, r, "search services", err)
+		return
+	}
+
+	if !params.ValidatePage(w, r, response.Page, response.TotalPages) {
+		return
+	}
+	setPaginationHeaders(w, r, response.Total, response.Page, response.PageSize, response.TotalPages)
+	h.writeJSON(w, r, http.StatusOK, response)
+}
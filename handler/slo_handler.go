@@ -0,0 +1,69 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+
+	"com.kong.connect/domain"
+	"com.kong.connect/problem"
+)
+
+// SetSLO handles PUT /api/v1/services/{id}/slo
+func (h *ServiceHandler) SetSLO(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		problem.Error(w, r, http.StatusBadRequest, "Invalid service ID")
+		return
+	}
+
+	var input domain.ServiceSLO
+	if !decodeJSON(w, r, &input) {
+		return
+	}
+
+	slo, err := h.service.SetSLO(r.Context(), id, input)
+	if err != nil {
+		h.writeError(w, r, "set slo", err)
+		return
+	}
+
+	h.writeJSON(w, r, http.StatusOK, slo)
+}
+
+// DeleteSLO handles DELETE /api/v1/services/{id}/slo
+func (h *ServiceHandler) DeleteSLO(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		problem.Error(w, r, http.StatusBadRequest, "Invalid service ID")
+		return
+	}
+
+	if err := h.service.DeleteSLO(r.Context(), id); err != nil {
+		h.writeError(w, r, "delete slo", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetSLOReport handles GET /api/v1/slo-report
+func (h *ServiceHandler) GetSLOReport(w http.ResponseWriter, r *http.Request) {
+	params := newQueryParser(r, h.strictQuery(r), "since", "until")
+	query := domain.SLOReportQuery{
+		Since: params.Time("since"),
+		Until: params.Time("until"),
+	}
+	if !params.Validate(w, r) {
+		return
+	}
+
+	report, err := h.service.GetSLOReport(r.Context(), query)
+	if err != nil {
+		h.writeError(w, r, "get slo report", err)
+		return
+	}
+
+	h.writeJSON(w, r, http.StatusOK, report)
+}
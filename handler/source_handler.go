@@ -0,0 +1,49 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+
+	"com.kong.connect/domain"
+	"com.kong.connect/problem"
+)
+
+// SetSourceRepository handles PUT /api/v1/services/{id}/repository
+func (h *ServiceHandler) SetSourceRepository(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		problem.Error(w, r, http.StatusBadRequest, "Invalid service ID")
+		return
+	}
+
+	var input domain.SourceRepositoryInput
+	if !decodeJSON(w, r, &input) {
+		return
+	}
+
+	repository, err := h.service.SetSourceRepository(r.Context(), id, input)
+	if err != nil {
+		h.writeError(w, r, "set repository", err)
+		return
+	}
+
+	h.writeJSON(w, r, http.StatusOK, repository)
+}
+
+// DeleteSourceRepository handles DELETE /api/v1/services/{id}/repository
+func (h *ServiceHandler) DeleteSourceRepository(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		problem.Error(w, r, http.StatusBadRequest, "Invalid service ID")
+		return
+	}
+
+	if err := h.service.DeleteSourceRepository(r.Context(), id); err != nil {
+		h.writeError(w, r, "delete repository", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,153 @@
+package handler
+
+import (
+	"bytes"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+
+	"com.kong.connect/problem"
+)
+
+// docsPolicy only lets the docs page apply its own inline styles
+const docsPolicy = "default-src 'none'; style-src 'unsafe-inline'; frame-ancestors 'self'"
+
+// versionIDs parses the service and version IDs of a version route, writing
+// a problem response when one is malformed
+func versionIDs(w http.ResponseWriter, r *http.Request) (int, int, bool) {
+	vars := mux.Vars(r)
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		problem.Error(w, r, http.StatusBadRequest, "Invalid service ID")
+		return 0, 0, false
+	}
+	versionID, err := strconv.Atoi(vars["versionId"])
+	if err != nil {
+		problem.Error(w, r, http.StatusBadRequest, "Invalid version ID")
+		return 0, 0, false
+	}
+	return id, versionID, true
+}
+
+// UploadVersionSpec handles PUT /api/v1/services/{id}/versions/{versionId}/spec
+func (h *ServiceHandler) UploadVersionSpec(w http.ResponseWriter, r *http.Request) {
+	id, versionID, ok := versionIDs(w, r)
+	if !ok {
+		return
+	}
+	content, ok := readBody(w, r)
+	if !ok {
+		return
+	}
+
+	version, err := h.service.UploadVersionSpec(r.Context(), id, versionID, content)
+	if err != nil {
+		h.writeError(w, r, "upload spec", err)
+		return
+	}
+
+	h.writeJSON(w, r, http.StatusOK, version)
+}
+
+// GetVersionSpec handles GET /api/v1/services/{id}/versions/{versionId}/spec
+func (h *ServiceHandler) GetVersionSpec(w http.ResponseWriter, r *http.Request) {
+	id, versionID, ok := versionIDs(w, r)
+	if !ok {
+		return
+	}
+
+	spec, err := h.service.GetVersionSpec(r.Context(), id, versionID)
+	if err != nil {
+		h.writeError(w, r, "get spec", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", spec.ContentType)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Header().Set("Content-Length", strconv.Itoa(len(spec.Content)))
+	w.WriteHeader(http.StatusOK)
+	w.Write(spec.Content)
+}
+
+// DeleteVersionSpec handles DELETE /api/v1/services/{id}/versions/{versionId}/spec
+func (h *ServiceHandler) DeleteVersionSpec(w http.ResponseWriter, r *http.Request) {
+	id, versionID, ok := versionIDs(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.service.DeleteVersionSpec(r.Context(), id, versionID); err != nil {
+		h.writeError(w, r, "delete spec", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetVersionDocs handles GET /api/v1/services/{id}/versions/{versionId}/docs
+func (h *ServiceHandler) GetVersionDocs(w http.ResponseWriter, r *http.Request) {
+	id, versionID, ok := versionIDs(w, r)
+	if !ok {
+		return
+	}
+
+	page, err := h.service.GetVersionDocs(r.Context(), id, versionID)
+	if err != nil {
+		h.writeError(w, r, "get docs", err)
+		return
+	}
+	var buf bytes.Buffer
+	if err := page.Render(&buf); err != nil {
+		h.internalError(w, r, "failed to render docs", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Content-Security-Policy", docsPolicy)
+	w.Header().Set("Content-Length", strconv.Itoa(buf.Len()))
+	w.WriteHeader(http.StatusOK)
+	w.Write(buf.Bytes())
+}
+
+// GetVersionPostman handles GET /api/v1/services/{id}/versions/{versionId}/postman
+func (h *ServiceHandler) GetVersionPostman(w http.ResponseWriter, r *http.Request) {
+	id, versionID, ok := versionIDs(w, r)
+	if !ok {
+		return
+	}
+
+	collection, err := h.service.GetVersionPostman(r.Context(), id, versionID)
+	if err != nil {
+		h.writeError(w, r, "export postman collection", err)
+		return
+	}
+
+	// Browsers save the collection under the name Postman gives its exports
+	filename := collection.Info.Name + " " + collection.Info.Version + ".postman_collection.json"
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": filename}))
+	h.writeJSON(w, r, http.StatusOK, collection)
+}
+
+// CompareVersions handles GET /api/v1/services/{id}/versions/compare
+func (h *ServiceHandler) CompareVersions(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		problem.Error(w, r, http.StatusBadRequest, "Invalid service ID")
+		return
+	}
+	params := newQueryParser(r, h.strictQuery(r), "from", "to")
+	from, to := params.Required("from"), params.Required("to")
+	if !params.Validate(w, r) {
+		return
+	}
+
+	comparison, err := h.service.CompareVersions(r.Context(), id, from, to)
+	if err != nil {
+		h.writeError(w, r, "compare versions", err)
+		return
+	}
+
+	h.writeJSON(w, r, http.StatusOK, comparison)
+}
@@ -0,0 +1,22 @@
+package handler
+
+import (
+	"net/http"
+)
+
+// GetStats handles GET /api/v1/stats
+func (h *ServiceHandler) GetStats(w http.ResponseWriter, r *http.Request) {
+	params := newQueryParser(r, h.strictQuery(r), "recent")
+	recentLimit := params.PositiveInt("recent", 0)
+	if !params.Validate(w, r) {
+		return
+	}
+
+	stats, err := h.service.GetStats(r.Context(), recentLimit)
+	if err != nil {
+		h.writeError(w, r, "get stats", err)
+		return
+	}
+
+	h.writeJSON(w, r, http.StatusOK, stats)
+}
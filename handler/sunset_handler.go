@@ -0,0 +1,67 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+
+	"com.kong.connect/domain"
+	"com.kong.connect/problem"
+)
+
+// sunsetTarget parses the service and, on the routes of a version, the
+// version of a sunset; versionID is 0 for the sunset of the service
+func sunsetTarget(w http.ResponseWriter, r *http.Request) (serviceID, versionID int, ok bool) {
+	vars := mux.Vars(r)
+	serviceID, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		problem.Error(w, r, http.StatusBadRequest, "Invalid service ID")
+		return 0, 0, false
+	}
+	if raw, ok := vars["versionId"]; ok {
+		if versionID, err = strconv.Atoi(raw); err != nil || versionID <= 0 {
+			problem.Error(w, r, http.StatusBadRequest, "Invalid version ID")
+			return 0, 0, false
+		}
+	}
+	return serviceID, versionID, true
+}
+
+// SetSunset handles PUT /api/v1/services/{id}/sunset and
+// PUT /api/v1/services/{id}/versions/{versionId}/sunset
+func (h *ServiceHandler) SetSunset(w http.ResponseWriter, r *http.Request) {
+	serviceID, versionID, ok := sunsetTarget(w, r)
+	if !ok {
+		return
+	}
+
+	var input domain.SunsetInput
+	if !decodeJSON(w, r, &input) {
+		return
+	}
+
+	sunset, err := h.service.SetSunset(r.Context(), serviceID, versionID, input)
+	if err != nil {
+		h.writeError(w, r, "set sunset", err)
+		return
+	}
+
+	h.writeJSON(w, r, http.StatusOK, sunset)
+}
+
+// DeleteSunset handles DELETE /api/v1/services/{id}/sunset and
+// DELETE /api/v1/services/{id}/versions/{versionId}/sunset
+func (h *ServiceHandler) DeleteSunset(w http.ResponseWriter, r *http.Request) {
+	serviceID, versionID, ok := sunsetTarget(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.service.DeleteSunset(r.Context(), serviceID, versionID); err != nil {
+		h.writeError(w, r, "delete sunset", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
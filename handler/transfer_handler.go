@@ -0,0 +1,92 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+
+	"com.kong.connect/domain"
+	"com.kong.connect/problem"
+)
+
+// TransferOwnership handles POST /api/v1/services/{id}/transfer-ownership,
+// answering 202 while the transfer awaits confirmation
+func (h *ServiceHandler) TransferOwnership(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		problem.Error(w, r, http.StatusBadRequest, "Invalid service ID")
+		return
+	}
+
+	var input domain.TransferInput
+	if !decodeJSON(w, r, &input) {
+		return
+	}
+
+	transfer, err := h.service.TransferOwnership(r.Context(), id, currentUser(r), isAdmin(r), input)
+	if err != nil {
+		h.writeError(w, r, "transfer ownership", err)
+		return
+	}
+
+	status := http.StatusOK
+	if transfer.Status == domain.TransferPending {
+		status = http.StatusAccepted
+	}
+	h.writeJSON(w, r, status, transfer)
+}
+
+// ConfirmTransfer handles POST /api/v1/services/{id}/transfer-ownership/confirm
+func (h *ServiceHandler) ConfirmTransfer(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		problem.Error(w, r, http.StatusBadRequest, "Invalid service ID")
+		return
+	}
+
+	transfer, err := h.service.ConfirmTransfer(r.Context(), id, currentUser(r), isAdmin(r))
+	if err != nil {
+		h.writeError(w, r, "confirm transfer", err)
+		return
+	}
+
+	h.writeJSON(w, r, http.StatusOK, transfer)
+}
+
+// DeclineTransfer handles POST /api/v1/services/{id}/transfer-ownership/decline
+func (h *ServiceHandler) DeclineTransfer(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		problem.Error(w, r, http.StatusBadRequest, "Invalid service ID")
+		return
+	}
+
+	transfer, err := h.service.DeclineTransfer(r.Context(), id, currentUser(r), isAdmin(r))
+	if err != nil {
+		h.writeError(w, r, "decline transfer", err)
+		return
+	}
+
+	h.writeJSON(w, r, http.StatusOK, transfer)
+}
+
+// GetTransfers handles GET /api/v1/services/{id}/ownership-transfers
+func (h *ServiceHandler) GetTransfers(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		problem.Error(w, r, http.StatusBadRequest, "Invalid service ID")
+		return
+	}
+
+	response, err := h.service.GetTransfers(r.Context(), id)
+	if err != nil {
+		h.writeError(w, r, "get transfers", err)
+		return
+	}
+
+	// Transfers change the catalog only once they complete, and the catalog
+	// writes are what purge HTTP caches
+	w.Header().Set("Cache-Control", "no-store")
+	h.writeJSON(w, r, http.StatusOK, response)
+}
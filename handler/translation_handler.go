@@ -0,0 +1,51 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+
+	"com.kong.connect/domain"
+	"com.kong.connect/problem"
+)
+
+// SetTranslation handles PUT /api/v1/services/{id}/translations/{language}
+func (h *ServiceHandler) SetTranslation(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		problem.Error(w, r, http.StatusBadRequest, "Invalid service ID")
+		return
+	}
+
+	var input domain.TranslationInput
+	if !decodeJSON(w, r, &input) {
+		return
+	}
+
+	translation, err := h.service.SetTranslation(r.Context(), id, vars["language"], input)
+	if err != nil {
+		h.writeError(w, r, "set translation", err)
+		return
+	}
+
+	h.writeJSON(w, r, http.StatusOK, translation)
+}
+
+// DeleteTranslation handles DELETE /api/v1/services/{id}/translations/{language}
+func (h *ServiceHandler) DeleteTranslation(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		problem.Error(w, r, http.StatusBadRequest, "Invalid service ID")
+		return
+	}
+
+	if err := h.service.DeleteTranslation(r.Context(), id, vars["language"]); err != nil {
+		h.writeError(w, r, "delete translation", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
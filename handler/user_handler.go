@@ -0,0 +1,118 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+
+	"com.kong.connect/domain"
+	"com.kong.connect/middleware"
+	"com.kong.connect/problem"
+	"com.kong.connect/service"
+)
+
+// TokenLookup authenticates tokens issued through the API, for middleware.SetTokenLookup
+func TokenLookup(svc service.ServiceServiceInterface) middleware.TokenLookup {
+	return func(ctx context.Context, token string) (*middleware.UserClaims, error) {
+		grant, err := svc.AuthenticateToken(ctx, token)
+		if err != nil || grant == nil {
+			return nil, err
+		}
+		return &middleware.UserClaims{Username: grant.User.Username, Roles: grant.User.Roles, OrgID: grant.OrgID}, nil
+	}
+}
+
+// OrgLookup resolves the organizations selected with the X-Org header, for middleware.SetOrgLookup
+func OrgLookup(svc service.ServiceServiceInterface) middleware.OrgLookup {
+	return func(ctx context.Context, slug string) (int, error) {
+		organization, err := svc.GetOrganizationBySlug(ctx, slug)
+		if err != nil || organization == nil {
+			return 0, err
+		}
+		return organization.ID, nil
+	}
+}
+
+// CreateUser handles POST /api/v1/users
+func (h *ServiceHandler) CreateUser(w http.ResponseWriter, r *http.Request) {
+	var input domain.UserInput
+	if !decodeJSON(w, r, &input) {
+		return
+	}
+
+	user, err := h.service.CreateUser(r.Context(), input)
+	if err != nil {
+		h.writeError(w, r, "create user", err)
+		return
+	}
+
+	h.writeJSON(w, r, http.StatusCreated, user)
+}
+
+// ListUsers handles GET /api/v1/users
+func (h *ServiceHandler) ListUsers(w http.ResponseWriter, r *http.Request) {
+	response, err := h.service.ListUsers(r.Context())
+	if err != nil {
+		h.writeError(w, r, "list users", err)
+		return
+	}
+
+	h.writeJSON(w, r, http.StatusOK, response)
+}
+
+// DisableUser handles POST /api/v1/users/{username}/disable
+func (h *ServiceHandler) DisableUser(w http.ResponseWriter, r *http.Request) {
+	user, err := h.service.DisableUser(r.Context(), mux.Vars(r)["username"])
+	if err != nil {
+		h.writeError(w, r, "disable user", err)
+		return
+	}
+
+	h.writeJSON(w, r, http.StatusOK, user)
+}
+
+// IssueToken handles POST /api/v1/tokens
+func (h *ServiceHandler) IssueToken(w http.ResponseWriter, r *http.Request) {
+	var input domain.TokenInput
+	if !decodeJSON(w, r, &input) {
+		return
+	}
+
+	token, err := h.service.IssueToken(r.Context(), input)
+	if err != nil {
+		h.writeError(w, r, "issue token", err)
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	h.writeJSON(w, r, http.StatusCreated, token)
+}
+
+// ListTokens handles GET /api/v1/tokens
+func (h *ServiceHandler) ListTokens(w http.ResponseWriter, r *http.Request) {
+	response, err := h.service.ListTokens(r.Context())
+	if err != nil {
+		h.writeError(w, r, "list tokens", err)
+		return
+	}
+
+	h.writeJSON(w, r, http.StatusOK, response)
+}
+
+// RevokeToken handles DELETE /api/v1/tokens/{id}
+func (h *ServiceHandler) RevokeToken(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		problem.Error(w, r, http.StatusBadRequest, "Invalid token ID")
+		return
+	}
+
+	if err := h.service.RevokeToken(r.Context(), id); err != nil {
+		h.writeError(w, r, "revoke token", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,44 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+
+	"com.kong.connect/domain"
+	"com.kong.connect/problem"
+)
+
+// GetServiceVersions handles GET /api/v1/services/{id}/versions
+func (h *ServiceHandler) GetServiceVersions(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		problem.Error(w, r, http.StatusBadRequest, "Invalid service ID")
+		return
+	}
+
+	params := newQueryParser(r, h.strictQuery(r), "sort_by", "sort_dir", "page", "page_size")
+	query := domain.VersionQuery{
+		ServiceID: id,
+		SortBy:    params.OneOf("sort_by", "semver", "created_at"),
+		SortDir:   params.OneOf("sort_dir", "asc", "desc"),
+		Page:      params.PositiveInt("page", 1),
+		PageSize:  params.PageSize(12, maxPageSize),
+	}
+	if !params.Validate(w, r) {
+		return
+	}
+
+	response, err := h.service.GetServiceVersions(r.Context(), query)
+	if err != nil {
+		h.writeError(w, r, "get service versions", err)
+		return
+	}
+
+	if !params.ValidatePage(w, r, response.Page, response.TotalPages) {
+		return
+	}
+	setPaginationHeaders(w, r, response.Total, response.Page, response.PageSize, response.TotalPages)
+	h.writeJSON(w, r, http.StatusOK, response)
+}
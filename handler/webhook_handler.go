@@ -0,0 +1,72 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+
+	"com.kong.connect/domain"
+	"com.kong.connect/problem"
+)
+
+// CreateWebhook handles POST /api/v1/webhooks
+func (h *ServiceHandler) CreateWebhook(w http.ResponseWriter, r *http.Request) {
+	var input domain.WebhookInput
+	if !decodeJSON(w, r, &input) {
+		return
+	}
+
+	webhook, err := h.service.CreateWebhook(r.Context(), input)
+	if err != nil {
+		h.writeError(w, r, "create webhook", err)
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	h.writeJSON(w, r, http.StatusCreated, webhook)
+}
+
+// ListWebhooks handles GET /api/v1/webhooks
+func (h *ServiceHandler) ListWebhooks(w http.ResponseWriter, r *http.Request) {
+	response, err := h.service.ListWebhooks(r.Context())
+	if err != nil {
+		h.writeError(w, r, "list webhooks", err)
+		return
+	}
+
+	h.writeJSON(w, r, http.StatusOK, response)
+}
+
+// DeleteWebhook handles DELETE /api/v1/webhooks/{id}
+func (h *ServiceHandler) DeleteWebhook(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		problem.Error(w, r, http.StatusBadRequest, "Invalid webhook ID")
+		return
+	}
+
+	if err := h.service.DeleteWebhook(r.Context(), id); err != nil {
+		h.writeError(w, r, "delete webhook", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ListWebhookDeliveries handles GET /api/v1/webhooks/{id}/deliveries
+func (h *ServiceHandler) ListWebhookDeliveries(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		problem.Error(w, r, http.StatusBadRequest, "Invalid webhook ID")
+		return
+	}
+
+	response, err := h.service.ListWebhookDeliveries(r.Context(), id)
+	if err != nil {
+		h.writeError(w, r, "list webhook deliveries", err)
+		return
+	}
+
+	h.writeJSON(w, r, http.StatusOK, response)
+}
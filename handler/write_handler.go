@@ -0,0 +1,149 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+
+	"com.kong.connect/domain"
+	"com.kong.connect/problem"
+)
+
+// CreateService handles POST /api/v1/services
+func (h *ServiceHandler) CreateService(w http.ResponseWriter, r *http.Request) {
+	var input domain.ServiceInput
+	if !decodeJSON(w, r, &input) {
+		return
+	}
+
+	created, err := h.service.CreateService(r.Context(), input)
+	if err != nil {
+		h.writeError(w, r, "create service", err)
+		return
+	}
+
+	h.writeJSON(w, r, http.StatusCreated, created)
+}
+
+// UpdateService handles PUT /api/v1/services/{id}
+func (h *ServiceHandler) UpdateService(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		problem.Error(w, r, http.StatusBadRequest, "Invalid service ID")
+		return
+	}
+
+	var input domain.ServiceInput
+	if !decodeJSON(w, r, &input) {
+		return
+	}
+
+	updated, err := h.service.UpdateService(r.Context(), id, input)
+	if err != nil {
+		h.writeError(w, r, "update service", err)
+		return
+	}
+
+	h.writeJSON(w, r, http.StatusOK, updated)
+}
+
+// DeleteService handles DELETE /api/v1/services/{id}
+func (h *ServiceHandler) DeleteService(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		problem.Error(w, r, http.StatusBadRequest, "Invalid service ID")
+		return
+	}
+
+	if err := h.service.DeleteService(r.Context(), id); err != nil {
+		h.writeError(w, r, "delete service", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// CreateVersion handles POST /api/v1/services/{id}/versions
+func (h *ServiceHandler) CreateVersion(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		problem.Error(w, r, http.StatusBadRequest, "Invalid service ID")
+		return
+	}
+
+	var input domain.VersionInput
+	if !decodeJSON(w, r, &input) {
+		return
+	}
+
+	version, err := h.service.CreateVersion(r.Context(), id, input)
+	if err != nil {
+		h.writeError(w, r, "create version", err)
+		return
+	}
+
+	h.writeJSON(w, r, http.StatusCreated, version)
+}
+
+// DeleteVersion handles DELETE /api/v1/services/{id}/versions/{versionId}
+func (h *ServiceHandler) DeleteVersion(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		problem.Error(w, r, http.StatusBadRequest, "Invalid service ID")
+		return
+	}
+	versionID, err := strconv.Atoi(vars["versionId"])
+	if err != nil {
+		problem.Error(w, r, http.StatusBadRequest, "Invalid version ID")
+		return
+	}
+
+	if err := h.service.DeleteVersion(r.Context(), id, versionID); err != nil {
+		h.writeError(w, r, "delete version", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// DeployVersion handles PUT /api/v1/services/{id}/environments/{environment}
+func (h *ServiceHandler) DeployVersion(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		problem.Error(w, r, http.StatusBadRequest, "Invalid service ID")
+		return
+	}
+
+	var input domain.DeploymentInput
+	if !decodeJSON(w, r, &input) {
+		return
+	}
+
+	version, err := h.service.DeployVersion(r.Context(), id, vars["environment"], input)
+	if err != nil {
+		h.writeError(w, r, "deploy version", err)
+		return
+	}
+
+	h.writeJSON(w, r, http.StatusOK, version)
+}
+
+// UndeployEnvironment handles DELETE /api/v1/services/{id}/environments/{environment}
+func (h *ServiceHandler) UndeployEnvironment(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		problem.Error(w, r, http.StatusBadRequest, "Invalid service ID")
+		return
+	}
+
+	if err := h.service.UndeployEnvironment(r.Context(), id, vars["environment"]); err != nil {
+		h.writeError(w, r, "undeploy environment", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,44 @@
+package health
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+)
+
+// DrainResponse is the payload of the drain endpoint
+type DrainResponse struct {
+	Status string `json:"status"`
+	// DrainedAt is when the grace period ends and the instance shuts down
+	DrainedAt time.Time `json:"drained_at"`
+}
+
+// Drain fails readiness from now on, while the instance keeps serving, and
+// closes Drained once grace has passed. Only the first call starts the grace
+// period; it returns when the period ends.
+func (c *Checker) Drain(grace time.Duration) time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.drainDeadline.IsZero() {
+		c.drainDeadline = time.Now().Add(grace)
+		time.AfterFunc(grace, func() { close(c.drained) })
+	}
+	return c.drainDeadline
+}
+
+// Drained is closed when the grace period started by Drain has passed
+func (c *Checker) Drained() <-chan struct{} {
+	return c.drained
+}
+
+// DrainHandler starts draining with grace on every request and answers 202
+// with the end of the grace period
+func (c *Checker) DrainHandler(grace time.Duration) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		response := DrainResponse{Status: StatusDraining, DrainedAt: c.Drain(grace).UTC()}
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "no-store")
+		w.WriteHeader(http.StatusAccepted)
+		json.NewEncoder(w).Encode(response)
+	})
+}
@@ -0,0 +1,178 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// DefaultTimeout bounds a check that sets no timeout of its own
+const DefaultTimeout = 2 * time.Second
+
+// Statuses of the report and of its dependencies
+const (
+	StatusOK          = "ok"
+	StatusDegraded    = "degraded"
+	StatusUnavailable = "unavailable"
+	StatusUp          = "up"
+	StatusDown        = "down"
+	// StatusDraining reports an instance taken out of load balancing by Drain
+	StatusDraining = "draining"
+)
+
+// Check probes one dependency
+type Check struct {
+	Name string
+	// Critical dependencies make the instance unready when they fail; the
+	// instance keeps serving when other dependencies fail, e.g. the rate limit
+	// store, which fails open
+	Critical bool
+	Timeout  time.Duration
+	Probe    func(ctx context.Context) error
+}
+
+// Report is the readiness payload
+type Report struct {
+	Status       string             `json:"status"`
+	Dependencies []DependencyStatus `json:"dependencies"`
+}
+
+// DependencyStatus is the outcome of one check
+type DependencyStatus struct {
+	Name      string  `json:"name"`
+	Status    string  `json:"status"`
+	Critical  bool    `json:"critical"`
+	LatencyMS float64 `json:"latency_ms"`
+	Error     string  `json:"error,omitempty"`
+}
+
+// Checker runs the registered checks on every readiness probe
+type Checker struct {
+	mu     sync.RWMutex
+	checks []Check
+
+	// Set once by Drain
+	drainDeadline time.Time
+	drained       chan struct{}
+}
+
+// NewChecker creates a checker with the given checks
+func NewChecker(checks ...Check) *Checker {
+	return &Checker{checks: checks, drained: make(chan struct{})}
+}
+
+// Register adds a check; dependencies are reported in registration order
+func (c *Checker) Register(check Check) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.checks = append(c.checks, check)
+}
+
+// Check runs every check concurrently, each bounded by its timeout
+func (c *Checker) Check(ctx context.Context) Report {
+	c.mu.RLock()
+	checks := append([]Check(nil), c.checks...)
+	draining := !c.drainDeadline.IsZero()
+	c.mu.RUnlock()
+
+	report := Report{Status: StatusOK, Dependencies: make([]DependencyStatus, len(checks))}
+	var wg sync.WaitGroup
+	for i, check := range checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			report.Dependencies[i] = run(ctx, check)
+		}()
+	}
+	wg.Wait()
+
+	for _, dependency := range report.Dependencies {
+		switch {
+		case dependency.Status == StatusUp:
+		case dependency.Critical:
+			report.Status = StatusUnavailable
+		case report.Status == StatusOK:
+			report.Status = StatusDegraded
+		}
+	}
+	if draining {
+		report.Status = StatusDraining
+	}
+	return report
+}
+
+func run(ctx context.Context, check Check) DependencyStatus {
+	timeout := check.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	status := DependencyStatus{Name: check.Name, Status: StatusUp, Critical: check.Critical}
+	start := time.Now()
+	// A probe that ignores its context must not hold up the report
+	done := make(chan error, 1)
+	go func() {
+		defer func() {
+			if v := recover(); v != nil {
+				done <- fmt.Errorf("panic: %v", v)
+			}
+		}()
+		done <- check.Probe(ctx)
+	}()
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+	status.LatencyMS = float64(time.Since(start).Microseconds()) / 1000
+	if err != nil {
+		status.Status, status.Error = StatusDown, err.Error()
+	}
+	return status
+}
+
+// Handler serves the report as JSON: 200 unless a critical dependency is
+// down or the instance is draining, 503 otherwise so that load balancers stop
+// routing to the instance
+func (c *Checker) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		report := c.Check(r.Context())
+		code := http.StatusOK
+		if report.Status == StatusUnavailable || report.Status == StatusDraining {
+			code = http.StatusServiceUnavailable
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "no-store")
+		w.WriteHeader(code)
+		json.NewEncoder(w).Encode(report)
+	})
+}
+
+// HTTP probes that url is reachable: any response below 500 counts as up, as
+// unauthenticated requests to a webhook target or an OIDC issuer may be refused
+func HTTP(client *http.Client, url string) func(ctx context.Context) error {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return func(ctx context.Context) error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return err
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			return err
+		}
+		resp.Body.Close()
+		if resp.StatusCode >= http.StatusInternalServerError {
+			return fmt.Errorf("%s answered %s", url, resp.Status)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,120 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func up(context.Context) error { return nil }
+
+func down(context.Context) error { return errors.New("connection refused") }
+
+func serve(t *testing.T, checker *Checker) (int, Report) {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	checker.Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/readyz", nil))
+	var report Report
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &report))
+	return rec.Code, report
+}
+
+func TestCheckerReportsEveryDependency(t *testing.T) {
+	checker := NewChecker(Check{Name: "database", Critical: true, Probe: up})
+	checker.Register(Check{Name: "redis", Probe: up})
+
+	code, report := serve(t, checker)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, StatusOK, report.Status)
+	require.Len(t, report.Dependencies, 2)
+	assert.Equal(t, DependencyStatus{Name: "database", Status: StatusUp, Critical: true, LatencyMS: report.Dependencies[0].LatencyMS}, report.Dependencies[0])
+	assert.Equal(t, "redis", report.Dependencies[1].Name)
+}
+
+func TestCheckerDegradesOnOptionalFailure(t *testing.T) {
+	checker := NewChecker(Check{Name: "database", Critical: true, Probe: up}, Check{Name: "redis", Probe: down})
+
+	code, report := serve(t, checker)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, StatusDegraded, report.Status)
+	assert.Equal(t, StatusDown, report.Dependencies[1].Status)
+	assert.Equal(t, "connection refused", report.Dependencies[1].Error)
+}
+
+func TestCheckerUnavailableOnCriticalFailure(t *testing.T) {
+	checker := NewChecker(
+		Check{Name: "database", Critical: true, Probe: down},
+		Check{Name: "panics", Probe: func(context.Context) error { panic("boom") }},
+	)
+
+	code, report := serve(t, checker)
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, StatusUnavailable, report.Status)
+	assert.Equal(t, "panic: boom", report.Dependencies[1].Error)
+}
+
+func TestCheckerTimesOutSlowProbes(t *testing.T) {
+	block := make(chan struct{})
+	defer close(block)
+	checker := NewChecker(Check{Name: "stuck", Critical: true, Timeout: 20 * time.Millisecond, Probe: func(context.Context) error {
+		<-block // ignores its context
+		return nil
+	}})
+
+	start := time.Now()
+	report := checker.Check(context.Background())
+	assert.Less(t, time.Since(start), time.Second)
+	assert.Equal(t, StatusUnavailable, report.Status)
+	assert.Equal(t, context.DeadlineExceeded.Error(), report.Dependencies[0].Error)
+	assert.GreaterOrEqual(t, report.Dependencies[0].LatencyMS, 20.0)
+}
+
+func TestHTTPProbe(t *testing.T) {
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/protected":
+			w.WriteHeader(http.StatusUnauthorized)
+		case "/broken":
+			w.WriteHeader(http.StatusBadGateway)
+		}
+	}))
+	defer target.Close()
+
+	ctx := context.Background()
+	assert.NoError(t, HTTP(nil, target.URL+"/")(ctx))
+	assert.NoError(t, HTTP(target.Client(), target.URL+"/protected")(ctx))
+	assert.ErrorContains(t, HTTP(nil, target.URL+"/broken")(ctx), "502 Bad Gateway")
+	assert.Error(t, HTTP(nil, "http://127.0.0.1:1/")(ctx))
+}
+
+func TestDrainFailsReadinessUntilGracePeriodEnds(t *testing.T) {
+	checker := NewChecker(Check{Name: "database", Critical: true, Probe: up})
+
+	rec := httptest.NewRecorder()
+	checker.DrainHandler(50*time.Millisecond).ServeHTTP(rec, httptest.NewRequest("POST", "/drain", nil))
+	assert.Equal(t, http.StatusAccepted, rec.Code)
+	var response DrainResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(t, StatusDraining, response.Status)
+
+	code, report := serve(t, checker)
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, StatusDraining, report.Status)
+	assert.Equal(t, StatusUp, report.Dependencies[0].Status)
+
+	// Draining again keeps the original deadline
+	assert.Equal(t, response.DrainedAt, checker.Drain(time.Hour).UTC())
+
+	select {
+	case <-checker.Drained():
+	case <-time.After(5 * time.Second):
+		t.Fatal("grace period did not end")
+	}
+}
@@ -0,0 +1,130 @@
+// Package i18n translates the messages of the API into the languages of its
+// clients. Messages are identified by their English text, or format, and
+// fall back to it in languages without a translation.
+package i18n
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Default is the language of the messages and descriptions as written
+const Default = "en"
+
+// Languages are the languages the API speaks, Default first
+var Languages = []string{Default, "de", "fr"}
+
+// catalogs holds the translations of every language but Default by English
+// message
+var catalogs = map[string]map[string]string{
+	"de": german,
+	"fr": french,
+}
+
+// Supported reports whether lang is one of Languages
+func Supported(lang string) bool {
+	return slices.Contains(Languages, lang)
+}
+
+// Negotiate picks the language of a response from an Accept-Language header,
+// e.g. "de-CH, fr;q=0.8", by quality then by order. Regional variants match
+// their language; Default is picked when no language is acceptable.
+func Negotiate(acceptLanguage string) string {
+	type candidate struct {
+		lang    string
+		quality float64
+	}
+	var candidates []candidate
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		tag, params, _ := strings.Cut(part, ";")
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		lang, _, _ := strings.Cut(tag, "-")
+		if tag == "*" {
+			lang = Default
+		}
+		quality := 1.0
+		if name, value, ok := strings.Cut(strings.TrimSpace(params), "="); ok && strings.TrimSpace(name) == "q" {
+			parsed, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+			if err != nil {
+				continue
+			}
+			quality = parsed
+		}
+		if quality > 0 && Supported(lang) {
+			candidates = append(candidates, candidate{lang, quality})
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].quality > candidates[j].quality })
+	if len(candidates) == 0 {
+		return Default
+	}
+	return candidates[0].lang
+}
+
+type contextKey struct{}
+
+// NewContext returns a copy of ctx carrying the language of the client
+func NewContext(ctx context.Context, lang string) context.Context {
+	return context.WithValue(ctx, contextKey{}, lang)
+}
+
+// FromContext returns the language of the client stored in ctx, or Default
+// when there is none
+func FromContext(ctx context.Context) string {
+	if lang, ok := ctx.Value(contextKey{}).(string); ok {
+		return lang
+	}
+	return Default
+}
+
+// Translate returns the translation of message into lang, or message itself
+// when it has none
+func Translate(lang, message string) string {
+	if translated, ok := catalogs[lang][message]; ok {
+		return translated
+	}
+	return message
+}
+
+// Sprintf formats the translation of format into lang. Arguments that are
+// messages are translated too.
+func Sprintf(lang, format string, args ...interface{}) string {
+	return M(format, args...).In(lang)
+}
+
+// Message is a message with its arguments, formatted once the language of
+// the client is known
+type Message struct {
+	Format string
+	Args   []interface{}
+}
+
+// M returns the message of format with args
+func M(format string, args ...interface{}) Message {
+	return Message{Format: format, Args: args}
+}
+
+// In formats the message in lang
+func (m Message) In(lang string) string {
+	format := Translate(lang, m.Format)
+	if len(m.Args) == 0 {
+		return format
+	}
+	args := make([]interface{}, len(m.Args))
+	for i, arg := range m.Args {
+		if message, ok := arg.(Message); ok {
+			arg = message.In(lang)
+		}
+		args[i] = arg
+	}
+	return fmt.Sprintf(format, args...)
+}
+
+// String formats the message in Default
+func (m Message) String() string {
+	return m.In(Default)
+}
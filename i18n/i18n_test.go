@@ -0,0 +1,56 @@
+package i18n
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNegotiate(t *testing.T) {
+	for header, want := range map[string]string{
+		"":                        "en",
+		"de":                      "de",
+		"fr-CA":                   "fr",
+		"DE-at":                   "de",
+		"it, fr;q=0.5, de;q=0.7":  "de",
+		"fr;q=0.8, de;q=0.8":      "fr",
+		"de;q=0, fr;q=0.1":        "fr",
+		"it, ja":                  "en",
+		"*":                       "en",
+		"de;q=bad, fr":            "fr",
+		"en-US,en;q=0.9,de;q=0.8": "en",
+	} {
+		assert.Equal(t, want, Negotiate(header), header)
+	}
+}
+
+func TestMessagesAreTranslated(t *testing.T) {
+	assert.Equal(t, "Dienst nicht verfügbar", Translate("de", "Service Unavailable"))
+	assert.Equal(t, "Service Unavailable", Translate("en", "Service Unavailable"))
+	assert.Equal(t, "no translation", Translate("fr", "no translation"))
+
+	notFound := M("%s not found", M("Repository"))
+	assert.Equal(t, "Repository not found", notFound.String())
+	assert.Equal(t, "Dépôt introuvable", notFound.In("fr"))
+	assert.Equal(t, "ungültige Service-ID: 0", Sprintf("de", "invalid service ID: %d", 0))
+	assert.Equal(t, "100% sure", M("100% sure").In("de"), "messages without arguments are not formatted")
+
+	assert.Equal(t, "en", FromContext(context.Background()))
+	assert.Equal(t, "fr", FromContext(NewContext(context.Background(), "fr")))
+}
+
+// TestCatalogsAgree fails when a translation drops or reorders the verbs of
+// its message, or a language misses a message another has
+func TestCatalogsAgree(t *testing.T) {
+	verbs := regexp.MustCompile(`%[a-z]`)
+	for lang, catalog := range catalogs {
+		for message, translated := range catalog {
+			assert.Equal(t, verbs.FindAllString(message, -1), verbs.FindAllString(translated, -1), "%s: %s", lang, message)
+			for other, otherCatalog := range catalogs {
+				assert.Contains(t, otherCatalog, message, "%s has %q, %s does not", lang, message, other)
+			}
+		}
+	}
+}
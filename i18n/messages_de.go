@@ -0,0 +1,88 @@
+package i18n
+
+// german translates the messages of the API into German
+var german = map[string]string{
+	// Problem titles
+	"Bad Request":              "Ungültige Anfrage",
+	"Unauthorized":             "Nicht authentifiziert",
+	"Forbidden":                "Verboten",
+	"Not Found":                "Nicht gefunden",
+	"Method Not Allowed":       "Methode nicht erlaubt",
+	"Conflict":                 "Konflikt",
+	"Unprocessable Entity":     "Nicht verarbeitbare Anfrage",
+	"Request Entity Too Large": "Anfrage zu groß",
+	"Too Many Requests":        "Zu viele Anfragen",
+	"Internal Server Error":    "Interner Serverfehler",
+	"Service Unavailable":      "Dienst nicht verfügbar",
+	"Invalid request body":     "Ungültiger Anfragekörper",
+	"Invalid query parameters": "Ungültige Abfrageparameter",
+
+	// Details of problems
+	"Internal server error":                     "Interner Serverfehler",
+	"Invalid token":                             "Ungültiges Token",
+	"Rate limit exceeded":                       "Anfragelimit überschritten",
+	"Server is busy":                            "Der Server ist ausgelastet",
+	"Request timed out":                         "Zeitüberschreitung der Anfrage",
+	"Service ID is required":                    "Die Service-ID ist erforderlich",
+	"Invalid service ID":                        "Ungültige Service-ID",
+	"Invalid version ID":                        "Ungültige Versions-ID",
+	"Invalid webhook ID":                        "Ungültige Webhook-ID",
+	"Invalid token ID":                          "Ungültige Token-ID",
+	"Invalid comment ID":                        "Ungültige Kommentar-ID",
+	"Request body must not exceed %d bytes":     "Der Anfragekörper darf höchstens %d Bytes groß sein",
+	"Invalid %s header":                         "Ungültiger %s-Header",
+	"%s is not allowed on %s; use %s":           "%s ist für %s nicht erlaubt; erlaubt sind %s",
+	"Authentication is temporarily unavailable": "Die Authentifizierung ist vorübergehend nicht verfügbar",
+	"One or more query parameters are unknown or have invalid values":           "Ein oder mehrere Abfrageparameter sind unbekannt oder haben ungültige Werte",
+	"One or more fields of the request body are unknown or have invalid values": "Ein oder mehrere Felder des Anfragekörpers sind unbekannt oder haben ungültige Werte",
+
+	// Reasons of invalid parameters and fields
+	"unknown parameter":             "unbekannter Parameter",
+	"unknown field":                 "unbekanntes Feld",
+	"must not be repeated":          "darf nicht wiederholt werden",
+	"is required":                   "ist erforderlich",
+	"must be a positive integer":    "muss eine positive ganze Zahl sein",
+	"must be true or false":         "muss true oder false sein",
+	"must be an RFC 3339 timestamp": "muss ein Zeitstempel nach RFC 3339 sein",
+
+	// Errors of the service layer
+	"%s not found":                      "%s nicht gefunden",
+	"Service":                           "Service",
+	"Version":                           "Version",
+	"Spec":                              "Spezifikation",
+	"Webhook":                           "Webhook",
+	"User":                              "Benutzer",
+	"Transfer":                          "Übertragung",
+	"Environment":                       "Umgebung",
+	"Comment":                           "Kommentar",
+	"Token":                             "Token",
+	"Sunset":                            "Abkündigung",
+	"Subscription":                      "Abonnement",
+	"SLO":                               "SLO",
+	"Repository":                        "Repository",
+	"Health check":                      "Health-Check",
+	"Favorite":                          "Favorit",
+	"Translation":                       "Übersetzung",
+	"Service already exists":            "Der Service existiert bereits",
+	"Version already exists":            "Die Version existiert bereits",
+	"User already exists":               "Der Benutzer existiert bereits",
+	"invalid service ID: %d":            "ungültige Service-ID: %d",
+	"invalid version ID: %d":            "ungültige Versions-ID: %d",
+	"invalid webhook ID: %d":            "ungültige Webhook-ID: %d",
+	"invalid comment ID: %d":            "ungültige Kommentar-ID: %d",
+	"invalid token ID: %d":              "ungültige Token-ID: %d",
+	"invalid service: name is required": "ungültiger Service: der Name ist erforderlich",
+	"invalid service: name must be at most %d characters":                                                   "ungültiger Service: der Name darf höchstens %d Zeichen lang sein",
+	"invalid service: description must be at most %d characters":                                            "ungültiger Service: die Beschreibung darf höchstens %d Zeichen lang sein",
+	"invalid service: unknown status %q":                                                                    "ungültiger Service: unbekannter Status %q",
+	"invalid version: version is required":                                                                  "ungültige Version: die Version ist erforderlich",
+	"invalid translation: unknown language %q, expected one of %s":                                          "ungültige Übersetzung: unbekannte Sprache %q, erwartet wird eine von %s",
+	"invalid translation: %s is the language of the description itself":                                     "ungültige Übersetzung: %s ist die Sprache der Beschreibung selbst",
+	"invalid translation: description is required and must be at most %d characters":                        "ungültige Übersetzung: die Beschreibung ist erforderlich und darf höchstens %d Zeichen lang sein",
+	"only the owner of a service or an admin can transfer it":                                               "nur der Owner eines Service oder ein Admin kann ihn übertragen",
+	"only an admin can transfer a service without confirmation":                                             "nur ein Admin kann einen Service ohne Bestätigung übertragen",
+	"only the receiving owner or an admin can confirm a transfer":                                           "nur der empfangende Owner oder ein Admin kann eine Übertragung bestätigen",
+	"only the parties to a transfer or an admin can decline it":                                             "nur die Beteiligten einer Übertragung oder ein Admin können sie ablehnen",
+	"only the author of a comment or an admin can delete it":                                                "nur der Autor eines Kommentars oder ein Admin kann ihn löschen",
+	"%s is not a user who can confirm the transfer; an admin can transfer the service without confirmation": "%s ist kein Benutzer, der die Übertragung bestätigen kann; ein Admin kann den Service ohne Bestätigung übertragen",
+}
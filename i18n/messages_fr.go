@@ -0,0 +1,88 @@
+package i18n
+
+// french translates the messages of the API into French
+var french = map[string]string{
+	// Problem titles
+	"Bad Request":              "Requête invalide",
+	"Unauthorized":             "Non authentifié",
+	"Forbidden":                "Interdit",
+	"Not Found":                "Introuvable",
+	"Method Not Allowed":       "Méthode non autorisée",
+	"Conflict":                 "Conflit",
+	"Unprocessable Entity":     "Entité non traitable",
+	"Request Entity Too Large": "Requête trop volumineuse",
+	"Too Many Requests":        "Trop de requêtes",
+	"Internal Server Error":    "Erreur interne du serveur",
+	"Service Unavailable":      "Service indisponible",
+	"Invalid request body":     "Corps de requête invalide",
+	"Invalid query parameters": "Paramètres de requête invalides",
+
+	// Details of problems
+	"Internal server error":                     "Erreur interne du serveur",
+	"Invalid token":                             "Jeton invalide",
+	"Rate limit exceeded":                       "Limite de requêtes dépassée",
+	"Server is busy":                            "Le serveur est occupé",
+	"Request timed out":                         "La requête a expiré",
+	"Service ID is required":                    "L'identifiant du service est requis",
+	"Invalid service ID":                        "Identifiant de service invalide",
+	"Invalid version ID":                        "Identifiant de version invalide",
+	"Invalid webhook ID":                        "Identifiant de webhook invalide",
+	"Invalid token ID":                          "Identifiant de jeton invalide",
+	"Invalid comment ID":                        "Identifiant de commentaire invalide",
+	"Request body must not exceed %d bytes":     "Le corps de la requête ne doit pas dépasser %d octets",
+	"Invalid %s header":                         "En-tête %s invalide",
+	"%s is not allowed on %s; use %s":           "%s n'est pas autorisé sur %s ; utilisez %s",
+	"Authentication is temporarily unavailable": "L'authentification est temporairement indisponible",
+	"One or more query parameters are unknown or have invalid values":           "Un ou plusieurs paramètres de requête sont inconnus ou ont des valeurs invalides",
+	"One or more fields of the request body are unknown or have invalid values": "Un ou plusieurs champs du corps de la requête sont inconnus ou ont des valeurs invalides",
+
+	// Reasons of invalid parameters and fields
+	"unknown parameter":             "paramètre inconnu",
+	"unknown field":                 "champ inconnu",
+	"must not be repeated":          "ne doit pas être répété",
+	"is required":                   "est requis",
+	"must be a positive integer":    "doit être un entier positif",
+	"must be true or false":         "doit valoir true ou false",
+	"must be an RFC 3339 timestamp": "doit être un horodatage RFC 3339",
+
+	// Errors of the service layer
+	"%s not found":                      "%s introuvable",
+	"Service":                           "Service",
+	"Version":                           "Version",
+	"Spec":                              "Spécification",
+	"Webhook":                           "Webhook",
+	"User":                              "Utilisateur",
+	"Transfer":                          "Transfert",
+	"Environment":                       "Environnement",
+	"Comment":                           "Commentaire",
+	"Token":                             "Jeton",
+	"Sunset":                            "Retrait",
+	"Subscription":                      "Abonnement",
+	"SLO":                               "SLO",
+	"Repository":                        "Dépôt",
+	"Health check":                      "Contrôle de santé",
+	"Favorite":                          "Favori",
+	"Translation":                       "Traduction",
+	"Service already exists":            "Le service existe déjà",
+	"Version already exists":            "La version existe déjà",
+	"User already exists":               "L'utilisateur existe déjà",
+	"invalid service ID: %d":            "identifiant de service invalide : %d",
+	"invalid version ID: %d":            "identifiant de version invalide : %d",
+	"invalid webhook ID: %d":            "identifiant de webhook invalide : %d",
+	"invalid comment ID: %d":            "identifiant de commentaire invalide : %d",
+	"invalid token ID: %d":              "identifiant de jeton invalide : %d",
+	"invalid service: name is required": "service invalide : le nom est requis",
+	"invalid service: name must be at most %d characters":                                                   "service invalide : le nom ne doit pas dépasser %d caractères",
+	"invalid service: description must be at most %d characters":                                            "service invalide : la description ne doit pas dépasser %d caractères",
+	"invalid service: unknown status %q":                                                                    "service invalide : statut inconnu %q",
+	"invalid version: version is required":                                                                  "version invalide : la version est requise",
+	"invalid translation: unknown language %q, expected one of %s":                                          "traduction invalide : langue inconnue %q, attendu l'une de %s",
+	"invalid translation: %s is the language of the description itself":                                     "traduction invalide : %s est la langue de la description elle-même",
+	"invalid translation: description is required and must be at most %d characters":                        "traduction invalide : la description est requise et ne doit pas dépasser %d caractères",
+	"only the owner of a service or an admin can transfer it":                                               "seul le propriétaire d'un service ou un administrateur peut le transférer",
+	"only an admin can transfer a service without confirmation":                                             "seul un administrateur peut transférer un service sans confirmation",
+	"only the receiving owner or an admin can confirm a transfer":                                           "seul le nouveau propriétaire ou un administrateur peut confirmer un transfert",
+	"only the parties to a transfer or an admin can decline it":                                             "seules les parties d'un transfert ou un administrateur peuvent le refuser",
+	"only the author of a comment or an admin can delete it":                                                "seul l'auteur d'un commentaire ou un administrateur peut le supprimer",
+	"%s is not a user who can confirm the transfer; an admin can transfer the service without confirmation": "%s n'est pas un utilisateur pouvant confirmer le transfert ; un administrateur peut transférer le service sans confirmation",
+}
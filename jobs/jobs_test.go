@@ -0,0 +1,127 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSchedule(t *testing.T) {
+	after := time.Date(2026, 3, 14, 10, 7, 30, 0, time.UTC)
+	tests := map[string]time.Time{
+		"15m":                    after.Add(15 * time.Minute),
+		"@every 90s":             after.Add(90 * time.Second),
+		"@hourly":                time.Date(2026, 3, 14, 11, 0, 0, 0, time.UTC),
+		"*/15 * * * *":           time.Date(2026, 3, 14, 10, 15, 0, 0, time.UTC),
+		"CRON_TZ=UTC 30 3 * * *": time.Date(2026, 3, 15, 3, 30, 0, 0, time.UTC),
+	}
+	for spec, want := range tests {
+		schedule, err := ParseSchedule(spec)
+		require.NoError(t, err, spec)
+		assert.Equal(t, want, schedule.Next(after).UTC(), spec)
+	}
+
+	for _, spec := range []string{"", "-5m", "0s", "* * *", "@sometimes", "61 * * * *"} {
+		_, err := ParseSchedule(spec)
+		assert.Error(t, err, spec)
+	}
+}
+
+func TestRunnerRunsJobsAndRecordsMetrics(t *testing.T) {
+	runner := NewRunner(nil)
+	var succeeded, failed atomic.Int32
+	require.NoError(t, runner.Register(Job{Name: "ok", Schedule: Every(5 * time.Millisecond), RunAtStart: true, Run: func(context.Context) error {
+		succeeded.Add(1)
+		return nil
+	}}))
+	require.NoError(t, runner.Register(Job{Name: "broken", Schedule: Every(time.Hour), RunAtStart: true, Run: func(context.Context) error {
+		failed.Add(1)
+		panic("boom")
+	}}))
+	assert.Error(t, runner.Register(Job{Name: "ok", Schedule: Every(time.Hour), Run: func(context.Context) error { return nil }}))
+	assert.Error(t, runner.Register(Job{Name: "incomplete"}))
+
+	runner.Start(context.Background())
+	assert.Error(t, runner.Register(Job{Name: "late", Schedule: Every(time.Hour), Run: func(context.Context) error { return nil }}))
+	require.Eventually(t, func() bool { return succeeded.Load() >= 3 && failed.Load() == 1 }, 5*time.Second, time.Millisecond)
+	require.NoError(t, runner.Shutdown(context.Background()))
+
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(runner.Collectors()...)
+	assert.Equal(t, float64(succeeded.Load()), testutil.ToFloat64(runner.metrics.runs.WithLabelValues("ok", "success")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(runner.metrics.runs.WithLabelValues("broken", "failure")))
+	assert.Zero(t, testutil.ToFloat64(runner.metrics.lastSuccess.WithLabelValues("broken")))
+	assert.NotZero(t, testutil.ToFloat64(runner.metrics.lastSuccess.WithLabelValues("ok")))
+
+	// No runs are scheduled after Shutdown
+	runs := succeeded.Load()
+	time.Sleep(20 * time.Millisecond)
+	assert.Equal(t, runs, succeeded.Load())
+}
+
+func TestShutdownWaitsForRunsThenCancelsThem(t *testing.T) {
+	runner := NewRunner(nil)
+	started := make(chan struct{})
+	var finished atomic.Bool
+	require.NoError(t, runner.Register(Job{Name: "slow", Schedule: Every(time.Hour), RunAtStart: true, Run: func(ctx context.Context) error {
+		close(started)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(50 * time.Millisecond):
+			finished.Store(true)
+			return nil
+		}
+	}}))
+
+	// Cancelling the start context does not interrupt the run
+	ctx, cancel := context.WithCancel(context.Background())
+	runner.Start(ctx)
+	<-started
+	cancel()
+	require.NoError(t, runner.Shutdown(context.Background()))
+	assert.True(t, finished.Load())
+
+	runner = NewRunner(nil)
+	var runErr atomic.Value
+	stuck := make(chan struct{})
+	require.NoError(t, runner.Register(Job{Name: "stuck", Schedule: Every(time.Hour), RunAtStart: true, Run: func(ctx context.Context) error {
+		close(stuck)
+		<-ctx.Done()
+		runErr.Store(ctx.Err())
+		return ctx.Err()
+	}}))
+	runner.Start(context.Background())
+	<-stuck
+
+	shutdownCtx, cancelShutdown := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancelShutdown()
+	assert.ErrorIs(t, runner.Shutdown(shutdownCtx), context.DeadlineExceeded)
+	assert.True(t, errors.Is(runErr.Load().(error), context.Canceled))
+}
+
+func TestRunTimeout(t *testing.T) {
+	runner := NewRunner(nil)
+	done := make(chan error, 1)
+	require.NoError(t, runner.Register(Job{Name: "bounded", Schedule: Every(time.Hour), RunAtStart: true, Timeout: 10 * time.Millisecond, Run: func(ctx context.Context) error {
+		<-ctx.Done()
+		done <- ctx.Err()
+		return ctx.Err()
+	}}))
+	runner.Start(context.Background())
+	defer runner.Shutdown(context.Background())
+
+	select {
+	case err := <-done:
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	case <-time.After(5 * time.Second):
+		t.Fatal("run was not cancelled at its timeout")
+	}
+}
@@ -0,0 +1,118 @@
+package jobs
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+
+	"com.kong.connect/logging"
+)
+
+// Leadership elects the instance running the scheduled jobs
+type Leadership interface {
+	// IsLeader reports whether this instance is the leader, taking over the
+	// leadership when no other instance holds it
+	IsLeader(ctx context.Context) (bool, error)
+}
+
+// DefaultLeaseTTL is how long a leader keeps the lease without renewing it,
+// i.e. how long jobs pause when the leader dies without releasing it
+const DefaultLeaseTTL = 30 * time.Second
+
+// Lease elects a leader through a row of the job_leases table: the instance
+// holding an unexpired lease is the leader. Replicas must share the database.
+type Lease struct {
+	db     *sql.DB
+	name   string
+	holder string
+	ttl    time.Duration
+	logger *slog.Logger
+
+	mu     sync.Mutex
+	leader bool
+}
+
+// NewLease creates a lease named name, held for ttl after each renewal. The
+// holder identifies this instance by host name and a random suffix.
+func NewLease(db *sql.DB, name string, ttl time.Duration, logger *slog.Logger) *Lease {
+	host, _ := os.Hostname()
+	suffix := make([]byte, 4)
+	rand.Read(suffix)
+	return &Lease{
+		db:     db,
+		name:   name,
+		holder: host + "-" + hex.EncodeToString(suffix),
+		ttl:    ttl,
+		logger: logging.Component(logger, "jobs"),
+	}
+}
+
+// Holder identifies this instance in the lease table
+func (l *Lease) Holder() string {
+	return l.holder
+}
+
+// IsLeader acquires or renews the lease and reports whether this instance
+// holds it
+func (l *Lease) IsLeader(ctx context.Context) (bool, error) {
+	now := time.Now()
+	// The update only applies when this instance already holds the lease or
+	// the previous holder let it expire
+	result, err := l.db.ExecContext(ctx, `
+		INSERT INTO job_leases (name, holder, expires_at) VALUES (?, ?, ?)
+		ON CONFLICT (name) DO UPDATE SET holder = excluded.holder, expires_at = excluded.expires_at
+		WHERE job_leases.holder = excluded.holder OR job_leases.expires_at <= ?`,
+		l.name, l.holder, now.Add(l.ttl).UnixMilli(), now.UnixMilli(),
+	)
+	if err != nil {
+		l.setLeader(false)
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		l.setLeader(false)
+		return false, err
+	}
+	l.setLeader(affected > 0)
+	return affected > 0, nil
+}
+
+// Keep renews the lease while this instance is the leader, and tries to take
+// it over otherwise, until ctx is done
+func (l *Lease) Keep(ctx context.Context) {
+	ticker := time.NewTicker(l.ttl / 3)
+	defer ticker.Stop()
+	for {
+		if _, err := l.IsLeader(ctx); err != nil && ctx.Err() == nil {
+			l.logger.Warn("failed to renew job lease", "lease", l.name, "error", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Release gives up the lease so that another instance takes over without
+// waiting for it to expire
+func (l *Lease) Release(ctx context.Context) error {
+	l.setLeader(false)
+	_, err := l.db.ExecContext(ctx, "DELETE FROM job_leases WHERE name = ? AND holder = ?", l.name, l.holder)
+	return err
+}
+
+// setLeader logs leadership changes
+func (l *Lease) setLeader(leader bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if leader != l.leader {
+		l.logger.Info("job leadership changed", "lease", l.name, "holder", l.holder, "leader", leader)
+	}
+	l.leader = leader
+}
@@ -0,0 +1,70 @@
+package jobs
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"com.kong.connect/testsupport"
+)
+
+func TestLeaseElectsOneHolder(t *testing.T) {
+	db := testsupport.NewEmptyDB(t)
+	ctx := context.Background()
+
+	a := NewLease(db, "jobs", 100*time.Millisecond, nil)
+	b := NewLease(db, "jobs", 100*time.Millisecond, nil)
+	assert.NotEqual(t, a.Holder(), b.Holder())
+
+	leader, err := a.IsLeader(ctx)
+	require.NoError(t, err)
+	assert.True(t, leader)
+	leader, err = b.IsLeader(ctx)
+	require.NoError(t, err)
+	assert.False(t, leader)
+	// Renewing keeps the lease
+	leader, err = a.IsLeader(ctx)
+	require.NoError(t, err)
+	assert.True(t, leader)
+
+	// b takes over once a stops renewing
+	require.Eventually(t, func() bool {
+		leader, err := b.IsLeader(ctx)
+		return err == nil && leader
+	}, 5*time.Second, 10*time.Millisecond)
+	leader, err = a.IsLeader(ctx)
+	require.NoError(t, err)
+	assert.False(t, leader)
+
+	// and hands it over at once when releasing it
+	require.NoError(t, b.Release(ctx))
+	leader, err = a.IsLeader(ctx)
+	require.NoError(t, err)
+	assert.True(t, leader)
+}
+
+// follower never wins the election
+type follower struct{}
+
+func (follower) IsLeader(context.Context) (bool, error) { return false, nil }
+
+func TestRunnerSkipsJobsWithoutLeadership(t *testing.T) {
+	runner := NewRunner(nil, WithLeadership(follower{}))
+	var runs atomic.Int32
+	require.NoError(t, runner.Register(Job{Name: "retention", Schedule: Every(5 * time.Millisecond), RunAtStart: true, Run: func(context.Context) error {
+		runs.Add(1)
+		return nil
+	}}))
+
+	runner.Start(context.Background())
+	require.Eventually(t, func() bool {
+		return testutil.ToFloat64(runner.metrics.runs.WithLabelValues("retention", "skipped")) >= 2
+	}, 5*time.Second, time.Millisecond)
+	require.NoError(t, runner.Shutdown(context.Background()))
+	assert.Zero(t, runs.Load())
+}
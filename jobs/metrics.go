@@ -0,0 +1,52 @@
+package jobs
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// runnerMetrics counts runs, their duration and the last success of every job
+type runnerMetrics struct {
+	runs        *prometheus.CounterVec
+	duration    *prometheus.HistogramVec
+	lastSuccess *prometheus.GaugeVec
+	running     *prometheus.GaugeVec
+}
+
+func newRunnerMetrics() *runnerMetrics {
+	return &runnerMetrics{
+		runs: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "job_runs_total",
+			Help: "Runs of background jobs by result.",
+		}, []string{"job", "result"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "job_run_duration_seconds",
+			Help:    "Duration of background job runs.",
+			Buckets: []float64{.01, .05, .1, .5, 1, 5, 10, 30, 60, 300},
+		}, []string{"job"}),
+		lastSuccess: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "job_last_success_timestamp_seconds",
+			Help: "Unix time the last successful run of a background job started.",
+		}, []string{"job"}),
+		running: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "job_running",
+			Help: "Whether a background job is running.",
+		}, []string{"job"}),
+	}
+}
+
+func (m *runnerMetrics) observe(job string, start time.Time, duration time.Duration, err error) {
+	m.duration.WithLabelValues(job).Observe(duration.Seconds())
+	if err != nil {
+		m.runs.WithLabelValues(job, "failure").Inc()
+		return
+	}
+	m.runs.WithLabelValues(job, "success").Inc()
+	m.lastSuccess.WithLabelValues(job).Set(float64(start.Unix()))
+}
+
+// Collectors returns the job metrics for registration with metrics.Registry
+func (r *Runner) Collectors() []prometheus.Collector {
+	return []prometheus.Collector{r.metrics.runs, r.metrics.duration, r.metrics.lastSuccess, r.metrics.running}
+}
@@ -0,0 +1,200 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"com.kong.connect/logging"
+)
+
+// Job is a task run on a schedule. Runs of the same job never overlap: when a
+// run takes longer than the interval, the missed runs are skipped.
+type Job struct {
+	Name     string
+	Schedule Schedule
+	// RunAtStart runs the job once when the runner starts, before its schedule
+	RunAtStart bool
+	// Timeout bounds a single run; 0 leaves it unbounded
+	Timeout time.Duration
+	Run     func(ctx context.Context) error
+}
+
+// Runner runs registered jobs on their schedules until it is shut down
+type Runner struct {
+	logger     *slog.Logger
+	metrics    *runnerMetrics
+	leadership Leadership
+
+	mu      sync.Mutex
+	jobs    []Job
+	started bool
+	stop    chan struct{}
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+}
+
+// RunnerOption configures a Runner
+type RunnerOption func(*Runner)
+
+// WithLeadership runs jobs only while leadership elects this instance, so
+// that replicas sharing a database do not all run the same jobs
+func WithLeadership(leadership Leadership) RunnerOption {
+	return func(r *Runner) {
+		r.leadership = leadership
+	}
+}
+
+// NewRunner creates a runner logging through logger
+func NewRunner(logger *slog.Logger, opts ...RunnerOption) *Runner {
+	r := &Runner{
+		logger:  logging.Component(logger, "jobs"),
+		metrics: newRunnerMetrics(),
+		stop:    make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// Register adds a job; jobs must be registered before Start
+func (r *Runner) Register(job Job) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	switch {
+	case r.started:
+		return errors.New("jobs must be registered before the runner starts")
+	case job.Name == "" || job.Schedule == nil || job.Run == nil:
+		return errors.New("a job needs a name, a schedule and a function")
+	}
+	for _, registered := range r.jobs {
+		if registered.Name == job.Name {
+			return fmt.Errorf("job %q is already registered", job.Name)
+		}
+	}
+	r.jobs = append(r.jobs, job)
+	return nil
+}
+
+// Start schedules the registered jobs until ctx is done or Shutdown is called.
+// Runs in progress when ctx is done are not interrupted; Shutdown waits for them.
+func (r *Runner) Start(ctx context.Context) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.started {
+		return
+	}
+	r.started = true
+
+	// Runs keep the values of ctx but outlive its cancellation until Shutdown
+	jobCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	r.cancel = cancel
+
+	for _, job := range r.jobs {
+		r.wg.Add(1)
+		go r.loop(ctx, jobCtx, job)
+	}
+	r.logger.Info("job runner started", "jobs", len(r.jobs))
+}
+
+// Shutdown stops scheduling and waits for runs in progress to finish. When ctx
+// is done first, the runs are cancelled and Shutdown returns ctx.Err() once
+// they have returned.
+func (r *Runner) Shutdown(ctx context.Context) error {
+	r.mu.Lock()
+	select {
+	case <-r.stop:
+	default:
+		close(r.stop)
+	}
+	cancel := r.cancel
+	r.mu.Unlock()
+	if cancel == nil {
+		return nil
+	}
+
+	done := make(chan struct{})
+	go func() {
+		r.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		cancel()
+		return nil
+	case <-ctx.Done():
+		r.logger.Warn("cancelling jobs still running at shutdown")
+		cancel()
+		<-done
+		return ctx.Err()
+	}
+}
+
+// loop runs job whenever its schedule is due
+func (r *Runner) loop(ctx, jobCtx context.Context, job Job) {
+	defer r.wg.Done()
+
+	if job.RunAtStart {
+		r.runOnce(jobCtx, job)
+	}
+	for {
+		timer := time.NewTimer(time.Until(job.Schedule.Next(time.Now())))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-r.stop:
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+		r.runOnce(jobCtx, job)
+	}
+}
+
+// runOnce runs job, recording its outcome; a panic counts as a failure. Runs
+// are skipped while another instance is the leader.
+func (r *Runner) runOnce(ctx context.Context, job Job) {
+	if r.leadership != nil {
+		leader, err := r.leadership.IsLeader(ctx)
+		if err != nil {
+			r.logger.Warn("skipping job, leader election failed", "job", job.Name, "error", err)
+		}
+		if !leader {
+			r.metrics.runs.WithLabelValues(job.Name, "skipped").Inc()
+			return
+		}
+	}
+
+	if job.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, job.Timeout)
+		defer cancel()
+	}
+
+	r.metrics.running.WithLabelValues(job.Name).Inc()
+	start := time.Now()
+	err := func() (err error) {
+		defer func() {
+			if p := recover(); p != nil {
+				err = fmt.Errorf("panic: %v", p)
+			}
+		}()
+		return job.Run(ctx)
+	}()
+	duration := time.Since(start)
+	r.metrics.running.WithLabelValues(job.Name).Dec()
+	r.metrics.observe(job.Name, start, duration, err)
+
+	if err != nil {
+		r.logger.Error("job failed", "job", job.Name, "duration", duration, "error", err)
+		return
+	}
+	r.logger.Debug("job finished", "job", job.Name, "duration", duration)
+}
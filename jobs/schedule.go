@@ -0,0 +1,50 @@
+package jobs
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+// Schedule decides when a job runs next
+type Schedule interface {
+	// Next returns the first run time after after
+	Next(after time.Time) time.Time
+}
+
+// interval runs a job a fixed duration after the previous run was scheduled
+type interval time.Duration
+
+func (i interval) Next(after time.Time) time.Time {
+	return after.Add(time.Duration(i))
+}
+
+// Every returns a schedule running a job every d
+func Every(d time.Duration) Schedule {
+	return interval(d)
+}
+
+// ParseSchedule parses an interval such as 15m or @every 15m, a descriptor such
+// as @hourly or @daily, or a standard five field cron expression such as
+// "30 3 * * *" (minute, hour, day of month, month, day of week). Cron
+// expressions use the local time zone unless prefixed with CRON_TZ=<zone>.
+func ParseSchedule(spec string) (Schedule, error) {
+	spec = strings.TrimSpace(spec)
+	if d, err := time.ParseDuration(spec); err == nil {
+		if d <= 0 {
+			return nil, fmt.Errorf("interval must be positive, got %s", spec)
+		}
+		return Every(d), nil
+	}
+	if rest, ok := strings.CutPrefix(spec, "@every "); ok {
+		return ParseSchedule(rest)
+	}
+
+	schedule, err := cron.ParseStandard(spec)
+	if err != nil {
+		return nil, fmt.Errorf("invalid schedule %q: %v", spec, err)
+	}
+	return schedule, nil
+}
@@ -0,0 +1,282 @@
+// Package jsonenc appends the JSON of the hottest responses, service listings
+// and details, without going through reflection. The output is byte for byte
+// what encoding/json produces for the same values, HTML escaping included.
+package jsonenc
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"strconv"
+	"time"
+	"unicode/utf8"
+
+	"com.kong.connect/domain"
+)
+
+// errTime is returned for times encoding/json refuses as well, so that callers
+// can fall back to it and answer with the same error
+var errTime = errors.New("time cannot be encoded as RFC 3339")
+
+// AppendServiceList appends the JSON object of a listing to buf
+func AppendServiceList(buf []byte, response *domain.ServiceListResponse) ([]byte, error) {
+	buf = append(buf, `{"services":`...)
+	if response.Services == nil {
+		buf = append(buf, "null"...)
+	} else {
+		buf = append(buf, '[')
+		for i := range response.Services {
+			if i > 0 {
+				buf = append(buf, ',')
+			}
+			var err error
+			if buf, err = AppendService(buf, &response.Services[i]); err != nil {
+				return nil, err
+			}
+		}
+		buf = append(buf, ']')
+	}
+	buf = append(buf, `,"total":`...)
+	buf = strconv.AppendInt(buf, int64(response.Total), 10)
+	buf = append(buf, `,"page":`...)
+	buf = strconv.AppendInt(buf, int64(response.Page), 10)
+	buf = append(buf, `,"page_size":`...)
+	buf = strconv.AppendInt(buf, int64(response.PageSize), 10)
+	buf = append(buf, `,"total_pages":`...)
+	buf = strconv.AppendInt(buf, int64(response.TotalPages), 10)
+	return append(buf, '}'), nil
+}
+
+// AppendService appends the JSON object of a service and its versions to buf
+func AppendService(buf []byte, service *domain.ServiceWithVersions) ([]byte, error) {
+	var err error
+	buf = append(buf, `{"id":`...)
+	buf = strconv.AppendInt(buf, int64(service.ID), 10)
+	buf = append(buf, `,"name":`...)
+	buf = appendString(buf, service.Name)
+	buf = append(buf, `,"description":`...)
+	buf = appendString(buf, service.Description)
+	buf = append(buf, `,"status":`...)
+	buf = appendString(buf, service.Status)
+	buf = append(buf, `,"owner":`...)
+	buf = appendString(buf, service.Owner)
+	buf = append(buf, `,"tags":`...)
+	if service.Tags == nil {
+		buf = append(buf, "null"...)
+	} else {
+		buf = append(buf, '[')
+		for i, tag := range service.Tags {
+			if i > 0 {
+				buf = append(buf, ',')
+			}
+			buf = appendString(buf, tag)
+		}
+		buf = append(buf, ']')
+	}
+	buf = append(buf, `,"created_at":`...)
+	if buf, err = appendTime(buf, service.CreatedAt); err != nil {
+		return nil, err
+	}
+	buf = append(buf, `,"updated_at":`...)
+	if buf, err = appendTime(buf, service.UpdatedAt); err != nil {
+		return nil, err
+	}
+	if health := service.Health; health != nil {
+		buf = append(buf, `,"health":{"url":`...)
+		buf = appendString(buf, health.URL)
+		buf = append(buf, `,"status":`...)
+		buf = appendString(buf, health.Status)
+		buf = append(buf, `,"latency_ms":`...)
+		buf = strconv.AppendInt(buf, health.LatencyMS, 10)
+		if health.Error != "" {
+			buf = append(buf, `,"error":`...)
+			buf = appendString(buf, health.Error)
+		}
+		if health.CheckedAt != nil {
+			buf = append(buf, `,"checked_at":`...)
+			if buf, err = appendTime(buf, *health.CheckedAt); err != nil {
+				return nil, err
+			}
+		}
+		buf = append(buf, '}')
+	}
+	if slo := service.SLO; slo != nil {
+		buf = append(buf, `,"slo":{"availability_target":`...)
+		buf = appendFloat(buf, slo.AvailabilityTarget)
+		if slo.LatencyTargetMS != 0 {
+			buf = append(buf, `,"latency_target_ms":`...)
+			buf = strconv.AppendInt(buf, slo.LatencyTargetMS, 10)
+		}
+		buf = append(buf, '}')
+	}
+	if service.Sunset != nil {
+		if buf, err = appendMember(buf, "sunset", service.Sunset); err != nil {
+			return nil, err
+		}
+	}
+	if service.Repository != nil {
+		if buf, err = appendMember(buf, "repository", service.Repository); err != nil {
+			return nil, err
+		}
+	}
+	if len(service.Translations) > 0 {
+		if buf, err = appendMember(buf, "translations", service.Translations); err != nil {
+			return nil, err
+		}
+	}
+	buf = append(buf, `,"versions":`...)
+	if service.Versions == nil {
+		buf = append(buf, "null"...)
+	} else {
+		buf = append(buf, '[')
+		for i, version := range service.Versions {
+			if i > 0 {
+				buf = append(buf, ',')
+			}
+			buf = append(buf, `{"id":`...)
+			buf = strconv.AppendInt(buf, int64(version.ID), 10)
+			buf = append(buf, `,"service_id":`...)
+			buf = strconv.AppendInt(buf, int64(version.ServiceID), 10)
+			buf = append(buf, `,"version":`...)
+			buf = appendString(buf, version.Version)
+			if version.OriginalVersion != "" {
+				buf = append(buf, `,"original_version":`...)
+				buf = appendString(buf, version.OriginalVersion)
+			}
+			buf = append(buf, `,"created_at":`...)
+			if buf, err = appendTime(buf, version.CreatedAt); err != nil {
+				return nil, err
+			}
+			if len(version.Environments) > 0 {
+				buf = append(buf, `,"environments":[`...)
+				for i, environment := range version.Environments {
+					if i > 0 {
+						buf = append(buf, ',')
+					}
+					buf = appendString(buf, environment)
+				}
+				buf = append(buf, ']')
+			}
+			if version.Spec != nil {
+				// Rarely attached; encoding/json handles its map
+				spec, err := json.Marshal(version.Spec)
+				if err != nil {
+					return nil, err
+				}
+				buf = append(buf, `,"spec":`...)
+				buf = append(buf, spec...)
+			}
+			if version.Sunset != nil {
+				if buf, err = appendMember(buf, "sunset", version.Sunset); err != nil {
+					return nil, err
+				}
+			}
+			buf = append(buf, '}')
+		}
+		buf = append(buf, ']')
+	}
+	return append(buf, '}'), nil
+}
+
+// appendTime appends t as time.Time.MarshalJSON does
+func appendTime(buf []byte, t time.Time) ([]byte, error) {
+	if year := t.Year(); year < 0 || year > 9999 {
+		return nil, errTime
+	}
+	if _, offset := t.Zone(); offset%60 != 0 {
+		return nil, errTime
+	}
+	buf = append(buf, '"')
+	buf = t.AppendFormat(buf, time.RFC3339Nano)
+	return append(buf, '"'), nil
+}
+
+// appendFloat appends the finite f as encoding/json does: in exponent
+// notation only when very small or large, with a one digit exponent
+func appendFloat(buf []byte, f float64) []byte {
+	format := byte('f')
+	if abs := math.Abs(f); abs != 0 && (abs < 1e-6 || abs >= 1e21) {
+		format = 'e'
+	}
+	buf = strconv.AppendFloat(buf, f, format, -1, 64)
+	if n := len(buf); format == 'e' && n >= 4 && buf[n-4] == 'e' && buf[n-3] == '-' && buf[n-2] == '0' {
+		buf[n-2] = buf[n-1]
+		buf = buf[:n-1]
+	}
+	return buf
+}
+
+const hex = "0123456789abcdef"
+
+// invalidUTF8 replaces invalid UTF-8 the way encoding/json of this toolchain
+// does: an escaped \ufffd, or the raw character when it is built on json/v2
+var invalidUTF8 = func() []byte {
+	encoded, _ := json.Marshal("\xff")
+	return encoded[1 : len(encoded)-1]
+}()
+
+// appendString appends s as a JSON string, escaping like encoding/json with
+// HTML escaping on
+func appendString(buf []byte, s string) []byte {
+	buf = append(buf, '"')
+	start := 0
+	for i := 0; i < len(s); {
+		if b := s[i]; b < utf8.RuneSelf {
+			if b >= 0x20 && b != '"' && b != '\\' && b != '<' && b != '>' && b != '&' {
+				i++
+				continue
+			}
+			buf = append(buf, s[start:i]...)
+			switch b {
+			case '"', '\\':
+				buf = append(buf, '\\', b)
+			case '\b':
+				buf = append(buf, '\\', 'b')
+			case '\f':
+				buf = append(buf, '\\', 'f')
+			case '\n':
+				buf = append(buf, '\\', 'n')
+			case '\r':
+				buf = append(buf, '\\', 'r')
+			case '\t':
+				buf = append(buf, '\\', 't')
+			default:
+				buf = append(buf, '\\', 'u', '0', '0', hex[b>>4], hex[b&0xF])
+			}
+			i++
+			start = i
+			continue
+		}
+
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if r == utf8.RuneError && size == 1 {
+			buf = append(buf, s[start:i]...)
+			buf = append(buf, invalidUTF8...)
+			i += size
+			start = i
+			continue
+		}
+		// U+2028 and U+2029 end lines in JavaScript
+		if r == '\u2028' || r == '\u2029' {
+			buf = append(buf, s[start:i]...)
+			buf = append(buf, '\\', 'u', '2', '0', '2', hex[r&0xF])
+			i += size
+			start = i
+			continue
+		}
+		i += size
+	}
+	buf = append(buf, s[start:]...)
+	return append(buf, '"')
+}
+
+// appendMember appends a member few services or versions have, such as
+// their sunset, encoded by encoding/json
+func appendMember(buf []byte, name string, value interface{}) ([]byte, error) {
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	buf = append(buf, `,"`+name+`":`...)
+	return append(buf, encoded...), nil
+}
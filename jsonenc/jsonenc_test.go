@@ -0,0 +1,133 @@
+package jsonenc
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"com.kong.connect/domain"
+)
+
+// listing is a page of services as returned by GET /api/v1/services
+func listing(size int) *domain.ServiceListResponse {
+	created := time.Date(2024, 3, 1, 12, 30, 0, 123456789, time.UTC)
+	response := &domain.ServiceListResponse{Total: 1000, Page: 1, PageSize: size, TotalPages: 1000 / size}
+	for i := range size {
+		service := domain.ServiceWithVersions{
+			Service: domain.Service{
+				ID:          i + 1,
+				Name:        fmt.Sprintf("Payments Gateway %d", i),
+				Description: "Settles card and wallet payments for the payments domain.",
+				Status:      domain.StatusActive,
+				Owner:       "payments-team",
+				Tags:        []string{"payments", "pii"},
+				CreatedAt:   created,
+				UpdatedAt:   created.Add(time.Hour),
+			},
+		}
+		for v := range 3 {
+			service.Versions = append(service.Versions, domain.ServiceVersion{ID: i*3 + v + 1, ServiceID: i + 1, Version: fmt.Sprintf("1.%d.0", v), CreatedAt: created})
+		}
+		service.Versions[0].Environments = []string{"prod", "staging"}
+		service.Versions[0].OriginalVersion = "v1.0"
+		service.Versions[1].Spec = &domain.SpecMetadata{
+			OpenAPI: "3.0.3", Title: "Payments <v1>", APIVersion: "1.1.0", Paths: 2, Operations: 3,
+			OperationsByMethod: map[string]int{"post": 1, "get": 2}, UploadedAt: created,
+		}
+		switch i % 3 {
+		case 1:
+			service.Health = &domain.ServiceHealth{URL: "https://payments.example.com/healthz", Status: domain.HealthUp, LatencyMS: 42, CheckedAt: &created}
+			service.SLO = &domain.ServiceSLO{AvailabilityTarget: 99.95, LatencyTargetMS: 300}
+		case 2:
+			service.Health = &domain.ServiceHealth{URL: "https://payments.example.com/healthz?probe=<1>", Status: domain.HealthDown, Error: "unexpected status 503"}
+			service.SLO = &domain.ServiceSLO{AvailabilityTarget: 99}
+			service.Sunset = &domain.Sunset{DeprecateAt: &created, ArchiveAt: created.AddDate(0, 6, 0), Status: domain.StatusDeprecated}
+			service.Versions[2].Sunset = &domain.Sunset{ArchiveAt: created.AddDate(0, 1, 0), Status: domain.StatusActive}
+			service.Repository = &domain.SourceRepository{URL: "https://github.com/kong/locate", Provider: domain.ProviderGitHub, Path: "kong/locate",
+				DefaultBranch: "main", LatestRelease: &domain.Release{Tag: "v1.2.0", PublishedAt: &created}, RefreshedAt: &created}
+			service.Translations = map[string]string{"fr": "Règle les paiements <cartes>", "de": "Wickelt Zahlungen ab"}
+		}
+		response.Services = append(response.Services, service)
+	}
+	return response
+}
+
+func TestAppendMatchesEncodingJSON(t *testing.T) {
+	tricky := domain.ServiceWithVersions{Service: domain.Service{
+		Name:        "<script>\"quoted\" & \\ \x00\x1f\b\f\n\r\t",
+		Description: "caf\u00e9 \u2028 \u2029 \xff invalid \u2713",
+		CreatedAt:   time.Date(2024, 3, 1, 12, 30, 0, 0, time.FixedZone("CET", 3600)),
+		UpdatedAt:   time.Date(1, 1, 1, 0, 0, 0, 0, time.UTC),
+		Tags:        []string{},
+		SLO:         &domain.ServiceSLO{AvailabilityTarget: 1e-7},
+	}, Versions: []domain.ServiceVersion{}}
+
+	responses := []*domain.ServiceListResponse{
+		listing(12),
+		{},
+		{Services: []domain.ServiceWithVersions{tricky, {}}},
+	}
+	for _, response := range responses {
+		expected, err := json.Marshal(response)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := AppendServiceList(nil, response)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != string(expected) {
+			t.Errorf("Output differs from encoding/json:\ngot:      %s\nexpected: %s", got, expected)
+		}
+	}
+}
+
+func TestAppendRejectsTimesEncodingJSONRejects(t *testing.T) {
+	service := &domain.ServiceWithVersions{Service: domain.Service{CreatedAt: time.Date(10000, 1, 1, 0, 0, 0, 0, time.UTC)}}
+	if _, err := json.Marshal(service); err == nil {
+		t.Fatal("Expected encoding/json to reject the year 10000")
+	}
+	if _, err := AppendService(nil, service); err == nil {
+		t.Error("Expected an error for the year 10000")
+	}
+}
+
+// TestFieldsAreCovered fails when a field is added to a type encoded here, so
+// that the new field gets encoded too
+func TestFieldsAreCovered(t *testing.T) {
+	fields := map[reflect.Type]int{
+		reflect.TypeOf(domain.ServiceListResponse{}): 5,
+		reflect.TypeOf(domain.ServiceWithVersions{}): 2,
+		reflect.TypeOf(domain.Service{}):             13,
+		reflect.TypeOf(domain.ServiceVersion{}):      8,
+	}
+	for typ, count := range fields {
+		if typ.NumField() != count {
+			t.Errorf("%s has %d fields, jsonenc encodes %d", typ, typ.NumField(), count)
+		}
+	}
+}
+
+func BenchmarkEncodeListing(b *testing.B) {
+	for _, size := range []int{12, 100} {
+		response := listing(size)
+		b.Run(fmt.Sprintf("encoding/json/page_size=%d", size), func(b *testing.B) {
+			b.ReportAllocs()
+			for b.Loop() {
+				if _, err := json.Marshal(response); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+		b.Run(fmt.Sprintf("jsonenc/page_size=%d", size), func(b *testing.B) {
+			b.ReportAllocs()
+			for b.Loop() {
+				if _, err := AppendServiceList(nil, response); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,284 @@
+// Package kube registers the Services and Ingresses of a Kubernetes cluster
+// in the catalog of the default organization. Resources opt in with the
+// annotation catalog.kong.connect/register: "true"; further annotations set
+// the catalog fields:
+//
+//	catalog.kong.connect/name: payments      # default: <namespace>/<name>
+//	catalog.kong.connect/description: Card payments
+//	catalog.kong.connect/owner: payments-team
+//	catalog.kong.connect/status: deprecated
+//	catalog.kong.connect/tags: http,public
+//	catalog.kong.connect/version: 2.1.0      # default: app.kubernetes.io/version label
+//
+// A Service and an Ingress with the same catalog name make one catalog
+// service. The controller watches the resources and a job reconciles the
+// catalog with them once they changed, so that only the leader writes.
+package kube
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	networkinglisters "k8s.io/client-go/listers/networking/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/clientcmd"
+
+	"com.kong.connect/audit"
+	"com.kong.connect/domain"
+	"com.kong.connect/logging"
+)
+
+// Source identifies the services created by the controller
+const Source = "kubernetes"
+
+// Annotations of the registered resources
+const (
+	AnnotationPrefix      = "catalog.kong.connect/"
+	RegisterAnnotation    = AnnotationPrefix + "register"
+	NameAnnotation        = AnnotationPrefix + "name"
+	DescriptionAnnotation = AnnotationPrefix + "description"
+	OwnerAnnotation       = AnnotationPrefix + "owner"
+	StatusAnnotation      = AnnotationPrefix + "status"
+	TagsAnnotation        = AnnotationPrefix + "tags"
+	VersionAnnotation     = AnnotationPrefix + "version"
+	// VersionLabel is the recommended label read without a version annotation
+	VersionLabel = "app.kubernetes.io/version"
+)
+
+const (
+	// SyncInterval is how often the job checks for changed resources
+	SyncInterval = 5 * time.Second
+	// resyncPeriod replays every resource to the controller, which then
+	// reconciles the catalog again
+	resyncPeriod = 10 * time.Minute
+)
+
+// Config configures the controller
+type Config struct {
+	Enabled bool
+	// Kubeconfig is the path of a kubeconfig file; empty uses the in-cluster
+	// service account
+	Kubeconfig string
+	// Namespace limits the watch to one namespace; empty watches all of them
+	Namespace string
+	// ConflictPolicy is a domain conflict policy applied to catalog services
+	// of the same name the controller did not create
+	ConflictPolicy string
+}
+
+// Catalog creates and updates the registered services
+type Catalog interface {
+	ImportServices(ctx context.Context, source string, services []domain.CatalogService, policy string) (*domain.ImportResult, error)
+}
+
+// Controller watches the annotated Services and Ingresses of a cluster
+type Controller struct {
+	policy    string
+	catalog   Catalog
+	logger    *slog.Logger
+	factory   informers.SharedInformerFactory
+	services  corelisters.ServiceLister
+	ingresses networkinglisters.IngressLister
+	synced    []cache.InformerSynced
+	// dirty is set by every change of a watched resource
+	dirty atomic.Bool
+}
+
+// NewController creates a controller for the cluster of cfg
+func NewController(cfg Config, catalog Catalog, logger *slog.Logger) (*Controller, error) {
+	restConfig, err := clientcmd.BuildConfigFromFlags("", cfg.Kubeconfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to configure the Kubernetes client: %v", err)
+	}
+	client, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the Kubernetes client: %v", err)
+	}
+	return newController(client, cfg, catalog, logger)
+}
+
+func newController(client kubernetes.Interface, cfg Config, catalog Catalog, logger *slog.Logger) (*Controller, error) {
+	c := &Controller{
+		policy:  cfg.ConflictPolicy,
+		catalog: catalog,
+		logger:  logging.Component(logger, "kube"),
+		factory: informers.NewSharedInformerFactoryWithOptions(client, resyncPeriod, informers.WithNamespace(cfg.Namespace)),
+	}
+	c.dirty.Store(true)
+
+	changed := cache.ResourceEventHandlerFuncs{
+		AddFunc:    func(interface{}) { c.dirty.Store(true) },
+		UpdateFunc: func(interface{}, interface{}) { c.dirty.Store(true) },
+		DeleteFunc: func(interface{}) { c.dirty.Store(true) },
+	}
+	services := c.factory.Core().V1().Services()
+	ingresses := c.factory.Networking().V1().Ingresses()
+	for _, informer := range []cache.SharedIndexInformer{services.Informer(), ingresses.Informer()} {
+		if _, err := informer.AddEventHandler(changed); err != nil {
+			return nil, err
+		}
+		c.synced = append(c.synced, informer.HasSynced)
+	}
+	c.services = services.Lister()
+	c.ingresses = ingresses.Lister()
+	return c, nil
+}
+
+// Start watches the resources until ctx is done
+func (c *Controller) Start(ctx context.Context) {
+	c.factory.Start(ctx.Done())
+}
+
+// Shutdown waits for the watches to stop once the context of Start is done
+func (c *Controller) Shutdown() {
+	c.factory.Shutdown()
+}
+
+// Sync reconciles the catalog with the registered resources when they
+// changed since the last sync; it is run by a job every SyncInterval. Resources
+// no longer registered leave their catalog service as it is.
+func (c *Controller) Sync(ctx context.Context) error {
+	for _, synced := range c.synced {
+		if !synced() {
+			c.logger.DebugContext(ctx, "waiting for the initial list of resources")
+			return nil
+		}
+	}
+	if !c.dirty.Swap(false) {
+		return nil
+	}
+
+	services, err := c.registered()
+	if err != nil {
+		c.dirty.Store(true)
+		return err
+	}
+	ctx = audit.NewContext(ctx, audit.Actor{Principal: Source})
+	result, err := c.catalog.ImportServices(ctx, Source, services, c.policy)
+	if err != nil {
+		// Retried by the next run
+		c.dirty.Store(true)
+		return err
+	}
+
+	c.logger.InfoContext(ctx, "synced Kubernetes services", "services", len(services),
+		"created", result.Created, "updated", result.Updated, "unchanged", result.Unchanged,
+		"conflicts", len(result.Conflicts), "invalid", len(result.Invalid))
+	if len(result.Conflicts) > 0 {
+		c.logger.WarnContext(ctx, "skipped Kubernetes services already in the catalog",
+			"services", result.Conflicts, "conflict_policy", c.policy)
+	}
+	return nil
+}
+
+// registered returns the catalog services of the registered resources,
+// sorted by name
+func (c *Controller) registered() ([]domain.CatalogService, error) {
+	services, err := c.services.List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	ingresses, err := c.ingresses.List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+
+	objects := make([]metav1.ObjectMeta, 0, len(services)+len(ingresses))
+	for _, service := range services {
+		objects = append(objects, service.ObjectMeta)
+	}
+	for _, ingress := range ingresses {
+		objects = append(objects, ingress.ObjectMeta)
+	}
+
+	byName := make(map[string]*domain.CatalogService)
+	for _, object := range objects {
+		entry, ok := catalogService(object)
+		if !ok {
+			continue
+		}
+		if existing, ok := byName[entry.Name]; ok {
+			merge(existing, entry)
+			continue
+		}
+		byName[entry.Name] = &entry
+	}
+
+	result := make([]domain.CatalogService, 0, len(byName))
+	for _, entry := range byName {
+		result = append(result, *entry)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result, nil
+}
+
+// catalogService maps the metadata of a resource to a catalog service,
+// returning false when it is not registered
+func catalogService(object metav1.ObjectMeta) (domain.CatalogService, bool) {
+	annotations := object.Annotations
+	if strings.TrimSpace(annotations[RegisterAnnotation]) != "true" {
+		return domain.CatalogService{}, false
+	}
+
+	service := domain.CatalogService{
+		Name:        strings.TrimSpace(annotations[NameAnnotation]),
+		Description: annotations[DescriptionAnnotation],
+		Owner:       annotations[OwnerAnnotation],
+		Status:      annotations[StatusAnnotation],
+		Tags:        splitList(annotations[TagsAnnotation]),
+		Versions:    splitList(annotations[VersionAnnotation]),
+	}
+	if service.Name == "" {
+		service.Name = object.Namespace + "/" + object.Name
+	}
+	if _, ok := annotations[VersionAnnotation]; !ok {
+		service.Versions = splitList(object.Labels[VersionLabel])
+	}
+	return service, true
+}
+
+// merge adds the tags and versions of other to service and fills its empty
+// fields, for resources registered under the same name
+func merge(service *domain.CatalogService, other domain.CatalogService) {
+	if service.Description == "" {
+		service.Description = other.Description
+	}
+	if service.Owner == "" {
+		service.Owner = other.Owner
+	}
+	if service.Status == "" {
+		service.Status = other.Status
+	}
+	for _, tag := range other.Tags {
+		if !slices.Contains(service.Tags, tag) {
+			service.Tags = append(service.Tags, tag)
+		}
+	}
+	for _, version := range other.Versions {
+		if !slices.Contains(service.Versions, version) {
+			service.Versions = append(service.Versions, version)
+		}
+	}
+}
+
+// splitList splits a comma separated annotation, dropping empty entries
+func splitList(value string) []string {
+	list := []string{}
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
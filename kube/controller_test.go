@@ -0,0 +1,126 @@
+package kube
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"com.kong.connect/audit"
+	"com.kong.connect/domain"
+)
+
+// recordingCatalog keeps the services of every import
+type recordingCatalog struct {
+	mu      sync.Mutex
+	imports [][]domain.CatalogService
+	actor   audit.Actor
+}
+
+func (c *recordingCatalog) ImportServices(ctx context.Context, source string, services []domain.CatalogService, policy string) (*domain.ImportResult, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.imports = append(c.imports, services)
+	c.actor = audit.FromContext(ctx)
+	return &domain.ImportResult{}, nil
+}
+
+func (c *recordingCatalog) count() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.imports)
+}
+
+func (c *recordingCatalog) last() []domain.CatalogService {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.imports[len(c.imports)-1]
+}
+
+func meta(namespace, name string, annotations, labels map[string]string) metav1.ObjectMeta {
+	return metav1.ObjectMeta{Namespace: namespace, Name: name, Annotations: annotations, Labels: labels}
+}
+
+func TestAnnotationsMapToCatalogFields(t *testing.T) {
+	_, ok := catalogService(meta("shop", "cart", map[string]string{NameAnnotation: "cart"}, nil))
+	assert.False(t, ok, "resources must opt in")
+
+	service, ok := catalogService(meta("shop", "cart", map[string]string{
+		RegisterAnnotation:    "true",
+		DescriptionAnnotation: "Shopping cart",
+		OwnerAnnotation:       "shop-team",
+		StatusAnnotation:      "deprecated",
+		TagsAnnotation:        "http, public,",
+	}, map[string]string{VersionLabel: "1.4.0"}))
+	require.True(t, ok)
+	assert.Equal(t, domain.CatalogService{
+		Name:        "shop/cart",
+		Description: "Shopping cart",
+		Owner:       "shop-team",
+		Status:      "deprecated",
+		Tags:        []string{"http", "public"},
+		Versions:    []string{"1.4.0"},
+	}, service)
+
+	// The annotation wins over the label
+	service, _ = catalogService(meta("shop", "cart", map[string]string{
+		RegisterAnnotation: "true", NameAnnotation: "Cart", VersionAnnotation: "2.0.0,2.1.0",
+	}, map[string]string{VersionLabel: "1.4.0"}))
+	assert.Equal(t, "Cart", service.Name)
+	assert.Equal(t, []string{"2.0.0", "2.1.0"}, service.Versions)
+}
+
+func TestControllerSyncsChangedResources(t *testing.T) {
+	registered := map[string]string{RegisterAnnotation: "true", NameAnnotation: "payments", TagsAnnotation: "http"}
+	client := fake.NewClientset(
+		&corev1.Service{ObjectMeta: meta("pay", "payments", registered, map[string]string{VersionLabel: "1.0.0"})},
+		&networkingv1.Ingress{ObjectMeta: meta("pay", "payments", map[string]string{
+			RegisterAnnotation: "true", NameAnnotation: "payments", OwnerAnnotation: "pay-team", TagsAnnotation: "public",
+		}, nil)},
+		&corev1.Service{ObjectMeta: meta("kube-system", "kube-dns", nil, nil)},
+	)
+	catalog := &recordingCatalog{}
+	controller, err := newController(client, Config{ConflictPolicy: domain.ConflictSkip}, catalog, nil)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer func() {
+		cancel()
+		controller.Shutdown()
+	}()
+	controller.Start(ctx)
+
+	require.Eventually(t, func() bool {
+		require.NoError(t, controller.Sync(ctx))
+		return catalog.count() == 1
+	}, 5*time.Second, 10*time.Millisecond)
+	// The Service and the Ingress make one catalog service
+	assert.Equal(t, []domain.CatalogService{{
+		Name:     "payments",
+		Owner:    "pay-team",
+		Tags:     []string{"http", "public"},
+		Versions: []string{"1.0.0"},
+	}}, catalog.last())
+	assert.Equal(t, Source, catalog.actor.Principal)
+
+	// Nothing changed, nothing to sync
+	require.NoError(t, controller.Sync(ctx))
+	assert.Equal(t, 1, catalog.count())
+
+	// A new version of the manifest is synced by the next run
+	_, err = client.CoreV1().Services("pay").Update(ctx,
+		&corev1.Service{ObjectMeta: meta("pay", "payments", registered, map[string]string{VersionLabel: "1.1.0"})}, metav1.UpdateOptions{})
+	require.NoError(t, err)
+	require.Eventually(t, func() bool {
+		require.NoError(t, controller.Sync(ctx))
+		return catalog.count() == 2
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, []string{"1.1.0"}, catalog.last()[0].Versions)
+}
@@ -0,0 +1,61 @@
+package logging
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+)
+
+// Config selects the output format and minimum level of the logger,
+// defaulting to text output at info level
+type Config struct {
+	Format string // json or text
+	Level  string // debug, info, warn or error
+}
+
+// New builds a logger writing to w. The returned LevelVar controls the
+// minimum level and can be changed while the program runs.
+func New(cfg Config, w io.Writer) (*slog.Logger, *slog.LevelVar, error) {
+	level := new(slog.LevelVar)
+	if err := SetLevel(level, cfg.Level); err != nil {
+		return nil, nil, err
+	}
+
+	opts := &slog.HandlerOptions{Level: level}
+	var handler slog.Handler
+	switch strings.ToLower(cfg.Format) {
+	case "", "text":
+		handler = slog.NewTextHandler(w, opts)
+	case "json":
+		handler = slog.NewJSONHandler(w, opts)
+	default:
+		return nil, nil, fmt.Errorf("unknown log format %q", cfg.Format)
+	}
+
+	return slog.New(contextHandler{handler}), level, nil
+}
+
+// SetLevel parses a level name into level; an empty name means info
+func SetLevel(level *slog.LevelVar, name string) error {
+	if name == "" {
+		level.Set(slog.LevelInfo)
+		return nil
+	}
+
+	var parsed slog.Level
+	if err := parsed.UnmarshalText([]byte(name)); err != nil {
+		return fmt.Errorf("unknown log level %q", name)
+	}
+	level.Set(parsed)
+	return nil
+}
+
+// Component returns a child of logger tagged with the name of the package using it.
+// A nil logger falls back to the process default.
+func Component(logger *slog.Logger, name string) *slog.Logger {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return logger.With("component", name)
+}
@@ -0,0 +1,71 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	"com.kong.connect/requestid"
+)
+
+func TestNewJSONRespectsLevel(t *testing.T) {
+	var buf bytes.Buffer
+	logger, level, err := New(Config{Format: "json", Level: "warn"}, &buf)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	logger.Info("dropped")
+	Component(logger, "handler").Warn("kept", "id", 7)
+
+	var entry map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("expected a single JSON line, got %q: %v", buf.String(), err)
+	}
+	if entry["msg"] != "kept" || entry["component"] != "handler" || entry["id"] != float64(7) {
+		t.Errorf("unexpected entry %v", entry)
+	}
+
+	// The level can be lowered at runtime
+	buf.Reset()
+	if err := SetLevel(level, "debug"); err != nil {
+		t.Fatalf("SetLevel() error = %v", err)
+	}
+	logger.Debug("now visible")
+	if buf.Len() == 0 {
+		t.Error("expected debug entry after lowering the level")
+	}
+	if level.Level() != slog.LevelDebug {
+		t.Errorf("level = %v, want debug", level.Level())
+	}
+}
+
+func TestNewRejectsUnknownSettings(t *testing.T) {
+	if _, _, err := New(Config{Format: "xml"}, &bytes.Buffer{}); err == nil {
+		t.Error("expected error for unknown format")
+	}
+	if _, _, err := New(Config{Level: "loud"}, &bytes.Buffer{}); err == nil {
+		t.Error("expected error for unknown level")
+	}
+}
+
+func TestLoggerIncludesRequestID(t *testing.T) {
+	var buf bytes.Buffer
+	logger, _, err := New(Config{Format: "json"}, &buf)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	ctx := requestid.NewContext(context.Background(), "abc123")
+	Component(logger, "handler").InfoContext(ctx, "handled")
+
+	var entry map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("invalid JSON %q: %v", buf.String(), err)
+	}
+	if entry["request_id"] != "abc123" {
+		t.Errorf("request_id = %v, want abc123", entry["request_id"])
+	}
+}
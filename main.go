@@ -1,42 +1,171 @@
 package main
 
 import (
-	"log"
-	"net/http"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
 	"os"
+	"sort"
+	"strings"
 
-	"com.kong.connect/database"
-	"com.kong.connect/handler"
-	"com.kong.connect/repository"
-	"com.kong.connect/service"
+	"com.kong.connect/config"
+	"com.kong.connect/logging"
 )
 
+// version is the release of the binary, set at build time with
+// -ldflags "-X main.version=v1.2.3"
+var version = "dev"
+
+// command is a subcommand of the CLI
+type command struct {
+	summary string
+	// needsConfig loads the configuration and sets up logging before run
+	needsConfig bool
+	run         func(a *app, args []string) error
+}
+
+var commands map[string]command
+
+func init() {
+	commands = map[string]command{
+		"serve":   {"Run the HTTP server (default)", true, runServe},
+		"migrate": {"Create missing tables, indexes and columns", true, runMigrate},
+		"seed":    {"Insert a seed profile, an export file or a generated catalog into an empty database", true, runSeed},
+		"export":  {"Write the catalog as JSON", true, runExport},
+		"version": {"Print the version", false, runVersion},
+	}
+}
+
+// errUsage reports invalid command flags, which the flag set already printed
+var errUsage = errors.New("invalid usage")
+
+// app carries the global flags, the configuration and the outputs to commands
+type app struct {
+	configPath string
+	overrides  map[string]string
+	stdout     io.Writer
+	stderr     io.Writer
+
+	cfg      *config.Config
+	logger   *slog.Logger
+	logLevel *slog.LevelVar
+}
+
 func main() {
-	// Get database path from environment or use default
-	dbPath := os.Getenv("DB_PATH")
-	if dbPath == "" {
-		dbPath = "./services.db"
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// run executes the command line and returns the exit code: 0 on success, 1
+// when the command fails and 2 on usage errors
+func run(args []string, stdout, stderr io.Writer) int {
+	a := &app{overrides: map[string]string{}, stdout: stdout, stderr: stderr}
+
+	global := flag.NewFlagSet("kong-connect", flag.ContinueOnError)
+	global.SetOutput(stderr)
+	global.StringVar(&a.configPath, "config", os.Getenv("CONFIG_FILE"), "configuration `file` (YAML, TOML or KEY=VALUE); defaults to $CONFIG_FILE")
+	global.Func("set", "override a setting, e.g. -set server.port=9090 or -set LOG_LEVEL=debug; repeatable", func(value string) error {
+		key, setting, ok := strings.Cut(value, "=")
+		if !ok || key == "" {
+			return errors.New("want key=value")
+		}
+		a.overrides[key] = setting
+		return nil
+	})
+	global.Usage = func() { a.usage(global) }
+	if err := global.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 2
+	}
+
+	name, args := "serve", global.Args()
+	if len(args) > 0 {
+		name, args = args[0], args[1:]
+	}
+	cmd, ok := commands[name]
+	if !ok {
+		fmt.Fprintf(stderr, "unknown command %q\n\n", name)
+		a.usage(global)
+		return 2
 	}
 
-	// Initialize database
-	if err := database.InitDB(dbPath); err != nil {
-		log.Fatal("Failed to initialize database:", err)
+	if cmd.needsConfig {
+		if err := a.load(); err != nil {
+			fmt.Fprintf(stderr, "invalid configuration: %v\n", err)
+			return 1
+		}
 	}
 
-	// Initialize layers
-	serviceRepo := repository.NewServiceRepository(database.DB)
-	serviceService := service.NewServiceService(serviceRepo)
-	serviceHandler := handler.NewServiceHandler(serviceService)
+	if err := cmd.run(a, args); err != nil {
+		switch {
+		case errors.Is(err, flag.ErrHelp):
+			return 0
+		case errors.Is(err, errUsage):
+			return 2
+		case a.logger != nil:
+			a.logger.Error(name+" failed", "error", err)
+		default:
+			fmt.Fprintf(stderr, "%s failed: %v\n", name, err)
+		}
+		return 1
+	}
+	return 0
+}
 
-	// Setup router
-	router := handler.SetupRouter(serviceHandler)
+// load reads the configuration and sets up logging; settings come from the
+// config file, overridden by the environment and then by -set flags
+func (a *app) load() error {
+	cfg, err := config.Load(a.configPath, a.overrides)
+	if err != nil {
+		return err
+	}
 
-	// Get port from environment or use default
-	port := os.Getenv("PORT")
-	if port == "" {
-		port = "8080"
+	// LOG_FORMAT selects json or text and LOG_LEVEL the minimum level
+	logger, logLevel, err := logging.New(cfg.Logging, a.stderr)
+	if err != nil {
+		return err
 	}
+	slog.SetDefault(logger)
+
+	a.cfg, a.logger, a.logLevel = cfg, logger, logLevel
+	return nil
+}
 
-	log.Printf("Server starting on port %s", port)
-	log.Fatal(http.ListenAndServe(":"+port, router))
+// parseFlags parses the flags of a command, which takes no arguments
+func (a *app) parseFlags(flags *flag.FlagSet, args []string) error {
+	flags.SetOutput(a.stderr)
+	flags.Usage = func() {
+		fmt.Fprintf(a.stderr, "Usage: kong-connect [global flags] %s [flags]\n\n%s\n", flags.Name(), commands[flags.Name()].summary)
+		flags.PrintDefaults()
+	}
+
+	if err := flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return err
+		}
+		return errUsage
+	}
+	if flags.NArg() > 0 {
+		fmt.Fprintf(a.stderr, "unexpected arguments: %s\n", strings.Join(flags.Args(), " "))
+		flags.Usage()
+		return errUsage
+	}
+	return nil
+}
+
+func (a *app) usage(global *flag.FlagSet) {
+	fmt.Fprintf(a.stderr, "Usage: kong-connect [global flags] [command] [flags]\n\nCommands:\n")
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(a.stderr, "  %-8s %s\n", name, commands[name].summary)
+	}
+	fmt.Fprintf(a.stderr, "\nGlobal flags:\n")
+	global.PrintDefaults()
 }
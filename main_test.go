@@ -0,0 +1,101 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"com.kong.connect/domain"
+)
+
+func TestVersionCommand(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	assert.Equal(t, 0, run([]string{"version"}, &stdout, &stderr))
+	assert.Contains(t, stdout.String(), "kong-connect dev")
+}
+
+func TestUnknownCommandAndFlags(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	assert.Equal(t, 2, run([]string{"deploy"}, &stdout, &stderr))
+	assert.Contains(t, stderr.String(), `unknown command "deploy"`)
+	assert.Contains(t, stderr.String(), "migrate")
+
+	assert.Equal(t, 2, run([]string{"-set", "novalue", "version"}, &stdout, &stderr))
+	assert.Equal(t, 2, run([]string{"version", "extra"}, &stdout, &stderr))
+	assert.Equal(t, 1, run([]string{"-set", "server.prot=1", "migrate"}, &stdout, &stderr))
+}
+
+func TestMigrateSeedAndExport(t *testing.T) {
+	dir := t.TempDir()
+	dbPath := filepath.Join(dir, "catalog.db")
+	exportPath := filepath.Join(dir, "catalog.json")
+	set := []string{"-set", "database.dsn=" + dbPath, "-set", "LOG_LEVEL=error"}
+
+	var stdout, stderr bytes.Buffer
+	require.Equal(t, 0, run(append(set, "migrate"), &stdout, &stderr), stderr.String())
+
+	// A migrated database is empty
+	require.Equal(t, 0, run(append(set, "export"), &stdout, &stderr), stderr.String())
+	assert.JSONEq(t, "[]", stdout.String())
+
+	require.Equal(t, 0, run(append(set, "seed"), &stdout, &stderr), stderr.String())
+	require.Equal(t, 0, run(append(set, "seed"), &stdout, &stderr), stderr.String())
+	require.Equal(t, 0, run(append(set, "export", "-o", exportPath), &stdout, &stderr), stderr.String())
+
+	content, err := os.ReadFile(exportPath)
+	require.NoError(t, err)
+	var services []domain.ServiceWithVersions
+	require.NoError(t, json.Unmarshal(content, &services))
+	require.Len(t, services, 8)
+	assert.Equal(t, "Collect Monday", services[0].Name)
+	assert.NotEmpty(t, services[0].Versions)
+	assert.Equal(t, []string{"payments"}, services[0].Tags)
+
+	// The export seeds another database as it is
+	set = []string{"-set", "database.dsn=" + filepath.Join(dir, "copy.db"), "-set", "LOG_LEVEL=error"}
+	require.Equal(t, 0, run(append(set, "seed", "-file", exportPath), &stdout, &stderr), stderr.String())
+	stdout.Reset()
+	require.Equal(t, 0, run(append(set, "export"), &stdout, &stderr), stderr.String())
+	var copied []domain.ServiceWithVersions
+	require.NoError(t, json.Unmarshal(stdout.Bytes(), &copied))
+	require.Len(t, copied, 8)
+	assert.Equal(t, services[0].Tags, copied[0].Tags)
+	assert.Len(t, copied[0].Versions, len(services[0].Versions))
+}
+
+func TestSeedGeneratesCatalog(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	set := []string{"-set", "database.dsn=" + filepath.Join(t.TempDir(), "catalog.db"), "-set", "LOG_LEVEL=error"}
+	require.Equal(t, 0, run(append(set, "seed", "-generate", "300", "-max-versions", "3"), &stdout, &stderr), stderr.String())
+
+	stdout.Reset()
+	require.Equal(t, 0, run(append(set, "export"), &stdout, &stderr), stderr.String())
+	var services []domain.ServiceWithVersions
+	require.NoError(t, json.Unmarshal(stdout.Bytes(), &services))
+	require.Len(t, services, 300)
+
+	names := make(map[string]bool)
+	for _, service := range services {
+		assert.False(t, names[service.Name], "duplicate name %s", service.Name)
+		names[service.Name] = true
+		assert.NotEmpty(t, service.Versions)
+		assert.LessOrEqual(t, len(service.Versions), 3)
+	}
+
+	assert.Equal(t, 1, run(append(set, "seed", "-generate", "10", "-file", "catalog.json"), &stdout, &stderr))
+	assert.Contains(t, stderr.String(), "cannot be combined")
+	assert.Equal(t, 1, run(append(set, "seed", "-generate", "-5"), &stdout, &stderr))
+}
+
+func TestSeedRejectsUnknownProfile(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	set := []string{"-set", "database.dsn=" + filepath.Join(t.TempDir(), "catalog.db")}
+	assert.Equal(t, 1, run(append(set, "seed", "-profile", "production"), &stdout, &stderr))
+	assert.Contains(t, stderr.String(), "available profiles: sample")
+	assert.Equal(t, 1, run(append(set, "seed", "-file", "missing.json"), &stdout, &stderr))
+}
@@ -0,0 +1,58 @@
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Registry holds the Prometheus collectors of the application
+type Registry struct {
+	registry        *prometheus.Registry
+	requestDuration *prometheus.HistogramVec
+	slos            *sloCollector
+}
+
+// New creates a registry with the Go runtime, process and HTTP request
+// collectors, tracking the given latency SLOs
+func New(slos []LatencySLO) *Registry {
+	r := &Registry{
+		registry: prometheus.NewRegistry(),
+		requestDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "http_request_duration_seconds",
+			Help:    "Latency of HTTP requests by route template.",
+			Buckets: []float64{.005, .01, .025, .05, .1, .2, .3, .5, 1, 2.5, 5, 10},
+		}, []string{"method", "route", "status"}),
+		slos: newSLOCollector(slos, time.Now),
+	}
+	r.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		r.requestDuration,
+		r.slos,
+	)
+	return r
+}
+
+// MustRegister adds collectors of other subsystems, panicking on conflicts
+func (r *Registry) MustRegister(cs ...prometheus.Collector) {
+	r.registry.MustRegister(cs...)
+}
+
+// Handler serves the metrics in the Prometheus exposition format
+func (r *Registry) Handler() http.Handler {
+	return promhttp.HandlerFor(r.registry, promhttp.HandlerOpts{Registry: r.registry})
+}
+
+// ObserveRequest records a served request; route is the route template, not the raw path
+func (r *Registry) ObserveRequest(method, route string, status int, duration time.Duration) {
+	r.requestDuration.WithLabelValues(method, route, strconv.Itoa(status)).Observe(duration.Seconds())
+	// Server errors count against the availability rather than the latency objective
+	if status < http.StatusInternalServerError {
+		r.slos.observe(method, route, duration)
+	}
+}
@@ -0,0 +1,94 @@
+package metrics
+
+import (
+	"io"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseLatencySLOs(t *testing.T) {
+	slos, err := ParseLatencySLOs("GET /api/v1/services=300ms@99, get /api/v1/search=1s@99.9")
+	require.NoError(t, err)
+	require.Len(t, slos, 2)
+	assert.Equal(t, "GET", slos[0].Method)
+	assert.Equal(t, "/api/v1/services", slos[0].Route)
+	assert.Equal(t, 300*time.Millisecond, slos[0].Threshold)
+	assert.InDelta(t, 0.99, slos[0].Objective, 1e-9)
+	assert.Equal(t, "GET", slos[1].Method)
+	assert.Equal(t, "/api/v1/search", slos[1].Route)
+	assert.Equal(t, time.Second, slos[1].Threshold)
+	assert.InDelta(t, 0.999, slos[1].Objective, 1e-9)
+
+	slos, err = ParseLatencySLOs("off")
+	require.NoError(t, err)
+	assert.Empty(t, slos)
+
+	_, err = ParseLatencySLOs(DefaultLatencySLOs)
+	require.NoError(t, err)
+
+	for _, spec := range []string{
+		"/api/v1/services=300ms@99",
+		"GET /api/v1/services=300ms",
+		"GET /api/v1/services=fast@99",
+		"GET /api/v1/services=300ms@100",
+		"GET api/v1/services=300ms@99",
+		"GET /api/v1/services=300ms@99,GET /api/v1/services=1s@95",
+	} {
+		_, err := ParseLatencySLOs(spec)
+		assert.Error(t, err, spec)
+	}
+}
+
+func TestBurnRate(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	c := newSLOCollector([]LatencySLO{
+		{Method: "GET", Route: "/api/v1/services", Threshold: 300 * time.Millisecond, Objective: 0.99},
+	}, func() time.Time { return now })
+	s := c.slos["GET /api/v1/services"]
+
+	// An hour ago: 100 fast requests
+	now = now.Add(-time.Hour + time.Minute)
+	for i := 0; i < 100; i++ {
+		c.observe("GET", "/api/v1/services", 10*time.Millisecond)
+	}
+	// Now: 2 slow out of 100, twice the allowed 1%
+	now = now.Add(time.Hour - time.Minute)
+	for i := 0; i < 100; i++ {
+		d := 10 * time.Millisecond
+		if i < 2 {
+			d = 500 * time.Millisecond
+		}
+		c.observe("GET", "/api/v1/services", d)
+	}
+	c.observe("GET", "/api/v1/services/{id}", time.Second)
+
+	assert.InDelta(t, 2.0, s.burnRate(now, 5), 1e-9)
+	assert.InDelta(t, 1.0, s.burnRate(now, 60), 1e-9)
+	assert.Equal(t, uint64(200), s.total.Load())
+	assert.Equal(t, uint64(2), s.slow.Load())
+
+	// Buckets older than the window are ignored
+	assert.Zero(t, s.burnRate(now.Add(10*time.Minute), 5))
+}
+
+func TestHandlerExportsRequestAndSLOMetrics(t *testing.T) {
+	registry := New([]LatencySLO{
+		{Method: "GET", Route: "/api/v1/services", Threshold: 300 * time.Millisecond, Objective: 0.99},
+	})
+	registry.ObserveRequest("GET", "/api/v1/services", 200, 400*time.Millisecond)
+	registry.ObserveRequest("GET", "/api/v1/services", 500, 400*time.Millisecond)
+
+	rec := httptest.NewRecorder()
+	registry.Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	body, _ := io.ReadAll(rec.Body)
+
+	assert.Contains(t, string(body), `http_request_duration_seconds_count{method="GET",route="/api/v1/services",status="200"} 1`)
+	assert.Contains(t, string(body), `http_slo_requests_total{method="GET",route="/api/v1/services"} 1`)
+	assert.Contains(t, string(body), `http_slo_requests_over_threshold_total{method="GET",route="/api/v1/services"} 1`)
+	assert.Contains(t, string(body), `http_slo_burn_rate{method="GET",route="/api/v1/services",window="5m"}`)
+	assert.Contains(t, string(body), "go_goroutines")
+}
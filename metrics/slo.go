@@ -0,0 +1,190 @@
+package metrics
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// DefaultLatencySLOs are the latency objectives of the hottest read endpoints
+const DefaultLatencySLOs = "GET /api/v1/services=300ms@99,GET /api/v1/services/{id}=100ms@99,GET /api/v1/search=500ms@99"
+
+// burnWindows are the windows the burn rate is exported for, in minutes
+var burnWindows = []struct {
+	label   string
+	minutes int
+}{
+	{"5m", 5},
+	{"1h", 60},
+}
+
+// LatencySLO requires Objective of the requests to a route to finish within Threshold
+type LatencySLO struct {
+	Method    string
+	Route     string
+	Threshold time.Duration
+	// Objective is the target ratio of fast requests, e.g. 0.99 for p99
+	Objective float64
+}
+
+// ParseLatencySLOs parses objectives written as "METHOD route=threshold@percentile"
+// separated by commas, e.g. "GET /api/v1/services=300ms@99". "off" means none.
+func ParseLatencySLOs(spec string) ([]LatencySLO, error) {
+	var slos []LatencySLO
+	if strings.TrimSpace(spec) == "off" {
+		return slos, nil
+	}
+	seen := make(map[string]struct{})
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		target, value, ok := strings.Cut(entry, "=")
+		method, route, ok2 := strings.Cut(strings.TrimSpace(target), " ")
+		threshold, percentile, ok3 := strings.Cut(value, "@")
+		route = strings.TrimSpace(route)
+		if !ok || !ok2 || !ok3 || method == "" || !strings.HasPrefix(route, "/") {
+			return nil, fmt.Errorf("invalid latency SLO %q: want METHOD route=threshold@percentile", entry)
+		}
+
+		d, err := time.ParseDuration(strings.TrimSpace(threshold))
+		if err != nil || d <= 0 {
+			return nil, fmt.Errorf("invalid latency SLO %q: threshold must be a positive duration", entry)
+		}
+		p, err := strconv.ParseFloat(strings.TrimSpace(percentile), 64)
+		if err != nil || p <= 0 || p >= 100 {
+			return nil, fmt.Errorf("invalid latency SLO %q: percentile must be between 0 and 100", entry)
+		}
+
+		method = strings.ToUpper(method)
+		if _, ok := seen[method+" "+route]; ok {
+			return nil, fmt.Errorf("invalid latency SLO %q: route configured twice", entry)
+		}
+		seen[method+" "+route] = struct{}{}
+		slos = append(slos, LatencySLO{Method: method, Route: route, Threshold: d, Objective: p / 100})
+	}
+	return slos, nil
+}
+
+// sloState counts the requests of one objective, in total and per minute for the burn rate
+type sloState struct {
+	LatencySLO
+	total atomic.Uint64
+	slow  atomic.Uint64
+
+	mu      sync.Mutex
+	minutes [60]int64
+	counts  [60]uint64
+	slows   [60]uint64
+}
+
+func (s *sloState) observe(now time.Time, slow bool) {
+	s.total.Add(1)
+	if slow {
+		s.slow.Add(1)
+	}
+
+	minute := now.Unix() / 60
+	i := minute % int64(len(s.minutes))
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.minutes[i] != minute {
+		s.minutes[i], s.counts[i], s.slows[i] = minute, 0, 0
+	}
+	s.counts[i]++
+	if slow {
+		s.slows[i]++
+	}
+}
+
+// burnRate is the ratio of slow requests over the last minutes divided by the
+// allowed ratio: 1 spends the error budget exactly over the SLO period, 14.4
+// spends a 30 day budget in two days.
+func (s *sloState) burnRate(now time.Time, minutes int) float64 {
+	current := now.Unix() / 60
+	var total, slow uint64
+
+	s.mu.Lock()
+	for m := current - int64(minutes) + 1; m <= current; m++ {
+		i := m % int64(len(s.minutes))
+		if s.minutes[i] == m {
+			total += s.counts[i]
+			slow += s.slows[i]
+		}
+	}
+	s.mu.Unlock()
+
+	if total == 0 {
+		return 0
+	}
+	return float64(slow) / float64(total) / (1 - s.Objective)
+}
+
+// sloCollector exports request counts and burn rates of the latency SLOs
+type sloCollector struct {
+	slos map[string]*sloState
+	now  func() time.Time
+
+	requests  *prometheus.Desc
+	slow      *prometheus.Desc
+	threshold *prometheus.Desc
+	objective *prometheus.Desc
+	burnRate  *prometheus.Desc
+}
+
+func newSLOCollector(slos []LatencySLO, now func() time.Time) *sloCollector {
+	labels := []string{"method", "route"}
+	c := &sloCollector{
+		slos: make(map[string]*sloState, len(slos)),
+		now:  now,
+		requests: prometheus.NewDesc("http_slo_requests_total",
+			"Requests to routes with a latency SLO.", labels, nil),
+		slow: prometheus.NewDesc("http_slo_requests_over_threshold_total",
+			"Requests slower than the latency threshold of their route.", labels, nil),
+		threshold: prometheus.NewDesc("http_slo_latency_threshold_seconds",
+			"Latency threshold of the SLO.", labels, nil),
+		objective: prometheus.NewDesc("http_slo_objective_ratio",
+			"Target ratio of requests within the latency threshold.", labels, nil),
+		burnRate: prometheus.NewDesc("http_slo_burn_rate",
+			"Error budget burn rate over the window; above 1 the SLO is being violated.", append(labels, "window"), nil),
+	}
+	for _, slo := range slos {
+		c.slos[slo.Method+" "+slo.Route] = &sloState{LatencySLO: slo}
+	}
+	return c
+}
+
+func (c *sloCollector) observe(method, route string, duration time.Duration) {
+	if s, ok := c.slos[method+" "+route]; ok {
+		s.observe(c.now(), duration > s.Threshold)
+	}
+}
+
+func (c *sloCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.requests
+	ch <- c.slow
+	ch <- c.threshold
+	ch <- c.objective
+	ch <- c.burnRate
+}
+
+func (c *sloCollector) Collect(ch chan<- prometheus.Metric) {
+	now := c.now()
+	for _, s := range c.slos {
+		ch <- prometheus.MustNewConstMetric(c.requests, prometheus.CounterValue, float64(s.total.Load()), s.Method, s.Route)
+		ch <- prometheus.MustNewConstMetric(c.slow, prometheus.CounterValue, float64(s.slow.Load()), s.Method, s.Route)
+		ch <- prometheus.MustNewConstMetric(c.threshold, prometheus.GaugeValue, s.Threshold.Seconds(), s.Method, s.Route)
+		ch <- prometheus.MustNewConstMetric(c.objective, prometheus.GaugeValue, s.Objective, s.Method, s.Route)
+		for _, w := range burnWindows {
+			ch <- prometheus.MustNewConstMetric(c.burnRate, prometheus.GaugeValue, s.burnRate(now, w.minutes), s.Method, s.Route, w.label)
+		}
+	}
+}
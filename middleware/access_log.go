@@ -0,0 +1,135 @@
+package middleware
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"com.kong.connect/clientip"
+	"com.kong.connect/requestid"
+)
+
+// Access log formats
+const (
+	AccessLogStructured = "structured" // through the application logger
+	AccessLogJSON       = "json"       // one JSON object per line
+	AccessLogCombined   = "combined"   // Apache combined log format
+	AccessLogOff        = "off"
+)
+
+// ValidAccessLogFormat reports whether format names a supported access log format
+func ValidAccessLogFormat(format string) bool {
+	switch format {
+	case AccessLogStructured, AccessLogJSON, AccessLogCombined, AccessLogOff:
+		return true
+	}
+	return false
+}
+
+// AccessLog logs one line per request with its status, size, latency, user,
+// client IP and request ID. The structured format goes through logger; the json and
+// combined formats are written to out.
+func AccessLog(format string, logger *slog.Logger, out io.Writer) func(http.Handler) http.Handler {
+	var mu sync.Mutex
+	write := func(line []byte) {
+		mu.Lock()
+		defer mu.Unlock()
+		out.Write(line)
+	}
+
+	return func(next http.Handler) http.Handler {
+		if format == AccessLogOff {
+			return next
+		}
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			ctx, state := withRequestState(r.Context())
+			rec := newStatusRecorder(w)
+
+			// A panic is logged on its way out too, as the 500 that the
+			// recovery outside the log answers
+			panicked := true
+			defer func() {
+				if panicked && !rec.wroteHeader {
+					rec.status = http.StatusInternalServerError
+				}
+
+				user, _ := state.snapshot()
+				latency := time.Since(start)
+
+				switch format {
+				case AccessLogJSON:
+					line, _ := json.Marshal(map[string]interface{}{
+						"time":        start.UTC().Format(time.RFC3339Nano),
+						"method":      r.Method,
+						"uri":         r.RequestURI,
+						"proto":       r.Proto,
+						"status":      rec.status,
+						"bytes":       rec.bytes,
+						"latency_ms":  float64(latency.Microseconds()) / 1000,
+						"user":        user,
+						"request_id":  requestid.FromContext(r.Context()),
+						"remote_addr": clientip.FromRequest(r),
+						"referer":     r.Referer(),
+						"user_agent":  r.UserAgent(),
+					})
+					write(append(line, '\n'))
+				case AccessLogCombined:
+					write([]byte(combinedLine(r, start, rec.status, rec.bytes, user)))
+				default:
+					logger.InfoContext(r.Context(), "request",
+						"method", r.Method,
+						"uri", r.RequestURI,
+						"status", rec.status,
+						"bytes", rec.bytes,
+						"latency", latency,
+						"user", user,
+						"remote_addr", clientip.FromRequest(r),
+					)
+				}
+			}()
+
+			next.ServeHTTP(rec, r.WithContext(ctx))
+			panicked = false
+		})
+	}
+}
+
+// combinedLine renders a request in the Apache combined log format:
+// %h %l %u %t "%r" %>s %b "%{Referer}i" "%{User-agent}i"
+func combinedLine(r *http.Request, start time.Time, status, bytes int, user string) string {
+	host := clientip.FromRequest(r)
+
+	size := "-"
+	if bytes > 0 {
+		size = fmt.Sprint(bytes)
+	}
+
+	return fmt.Sprintf("%s - %s [%s] \"%s %s %s\" %d %s \"%s\" \"%s\"\n",
+		host,
+		dashIfEmpty(user),
+		start.Format("02/Jan/2006:15:04:05 -0700"),
+		r.Method, escapeQuotes(r.RequestURI), r.Proto,
+		status,
+		size,
+		escapeQuotes(dashIfEmpty(r.Referer())),
+		escapeQuotes(dashIfEmpty(r.UserAgent())),
+	)
+}
+
+func dashIfEmpty(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+func escapeQuotes(s string) string {
+	return strings.ReplaceAll(s, `"`, `\"`)
+}
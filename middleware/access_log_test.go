@@ -0,0 +1,66 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+
+	"com.kong.connect/requestid"
+)
+
+func TestAccessLogJSON(t *testing.T) {
+	var out bytes.Buffer
+	handler := RequestID(AccessLog(AccessLogJSON, slog.Default(), &out)(
+		AuthMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusTeapot)
+			w.Write([]byte("short and stout"))
+		})),
+	))
+
+	req := httptest.NewRequest("GET", "/api/v1/services?page=2", nil)
+	req.Header.Set("Authorization", "Bearer viewer-token")
+	req.Header.Set(requestid.Header, "abc-123")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	var entry map[string]interface{}
+	if err := json.Unmarshal(out.Bytes(), &entry); err != nil {
+		t.Fatalf("invalid JSON access log %q: %v", out.String(), err)
+	}
+	want := map[string]interface{}{
+		"method":     "GET",
+		"uri":        "/api/v1/services?page=2",
+		"status":     float64(http.StatusTeapot),
+		"bytes":      float64(len("short and stout")),
+		"user":       "viewer",
+		"request_id": "abc-123",
+	}
+	for key, value := range want {
+		if entry[key] != value {
+			t.Errorf("%s = %v, want %v", key, entry[key], value)
+		}
+	}
+	if _, ok := entry["latency_ms"]; !ok {
+		t.Error("latency_ms missing")
+	}
+}
+
+func TestAccessLogCombined(t *testing.T) {
+	var out bytes.Buffer
+	handler := AccessLog(AccessLogCombined, slog.Default(), &out)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	req := httptest.NewRequest("DELETE", "/api/v1/services/1", nil)
+	req.RemoteAddr = "10.0.0.7:52311"
+	req.Header.Set("User-Agent", `curl/8.0 "quoted"`)
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	pattern := `^10\.0\.0\.7 - - \[\d{2}/\w{3}/\d{4}:\d{2}:\d{2}:\d{2} [+-]\d{4}\] "DELETE /api/v1/services/1 HTTP/1\.1" 204 - "-" "curl/8\.0 \\"quoted\\""\n$`
+	if !regexp.MustCompile(pattern).MatchString(out.String()) {
+		t.Errorf("combined log line %q does not match %s", out.String(), pattern)
+	}
+}
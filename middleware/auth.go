@@ -2,8 +2,15 @@ package middleware
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"net/http"
 	"strings"
+	"sync/atomic"
+
+	"com.kong.connect/audit"
+	"com.kong.connect/problem"
+	"com.kong.connect/tenant"
 )
 
 // UserContextKey is used to store user info in request context
@@ -14,37 +21,154 @@ const UserContextKey = contextKey("user")
 type UserClaims struct {
 	Username string
 	Roles    []string
+	// OrgID is the organization a token issued through the API was issued
+	// in; static tokens have none and may select any organization
+	OrgID int
+}
+
+// tokens maps static bearer tokens to their users; it can be replaced at runtime
+var tokens atomic.Pointer[map[string]UserClaims]
+
+func init() {
+	SetTokens(map[string]UserClaims{
+		"admin-token":  {Username: "admin", Roles: []string{"admin"}},
+		"viewer-token": {Username: "viewer", Roles: []string{"viewer"}},
+	})
+}
+
+// SetTokens replaces the accepted bearer tokens. Requests already
+// authenticated keep their claims.
+func SetTokens(t map[string]UserClaims) {
+	copied := make(map[string]UserClaims, len(t))
+	for token, claims := range t {
+		copied[token] = claims
+	}
+	tokens.Store(&copied)
+}
+
+// IsStaticUser reports whether username is the user of a static token
+func IsStaticUser(username string) bool {
+	for _, claims := range *tokens.Load() {
+		if claims.Username == username {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseTokens parses bearer tokens written as "token=username:role|role"
+// pairs separated by commas, e.g. "s3cr3t=admin:admin,r3ad=dashboard:viewer"
+func ParseTokens(spec string) (map[string]UserClaims, error) {
+	parsed := make(map[string]UserClaims)
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		token, user, ok := strings.Cut(entry, "=")
+		username, roles, ok2 := strings.Cut(user, ":")
+		if !ok || !ok2 || token == "" || username == "" || roles == "" {
+			return nil, fmt.Errorf("invalid token entry: want token=username:role|role")
+		}
+		parsed[token] = UserClaims{Username: username, Roles: strings.Split(roles, "|")}
+	}
+	if len(parsed) == 0 {
+		return nil, errors.New("at least one token is required")
+	}
+	return parsed, nil
 }
 
-// Dummy token validation — replace with real JWT validation
-func validateToken(token string) (*UserClaims, error) {
-	// This is where you'd parse and validate a JWT or token
-	if token == "admin-token" {
-		return &UserClaims{Username: "admin", Roles: []string{"admin"}}, nil
+// ValidateToken resolves a bearer token to its user claims.
+// Static token lookup — replace with real JWT validation
+func ValidateToken(token string) (*UserClaims, error) {
+	claims, ok := (*tokens.Load())[token]
+	if !ok {
+		return nil, http.ErrNoCookie
 	}
-	if token == "viewer-token" {
-		return &UserClaims{Username: "viewer", Roles: []string{"viewer"}}, nil
+	return &claims, nil
+}
+
+// TokenLookup resolves bearer tokens that are not static tokens, such as
+// tokens issued through the API. It returns nil claims for unknown tokens.
+type TokenLookup func(ctx context.Context, token string) (*UserClaims, error)
+
+var tokenLookup atomic.Pointer[TokenLookup]
+
+// SetTokenLookup consults lookup for bearer tokens that are not static
+// tokens; nil accepts static tokens only
+func SetTokenLookup(lookup TokenLookup) {
+	if lookup == nil {
+		tokenLookup.Store(nil)
+		return
 	}
-	return nil, http.ErrNoCookie
+	tokenLookup.Store(&lookup)
+}
+
+// resolvedTokenKey is the context key of a bearer token resolved before
+// authentication, by the rate limit
+type resolvedTokenKey struct{}
+
+type resolvedToken struct {
+	token  string
+	claims *UserClaims
+	err    error
 }
 
-// AuthMiddleware authenticates requests and injects user info into context
+// resolveToken resolves a bearer token to its user claims, or nil for an
+// unknown token: static tokens first, then the token lookup. A token resolved
+// earlier in the request is not looked up again.
+func resolveToken(ctx context.Context, token string) (*UserClaims, error) {
+	if resolved, ok := ctx.Value(resolvedTokenKey{}).(*resolvedToken); ok && resolved.token == token {
+		return resolved.claims, resolved.err
+	}
+	if user, err := ValidateToken(token); err == nil {
+		return user, nil
+	}
+	if lookup := tokenLookup.Load(); lookup != nil {
+		return (*lookup)(ctx, token)
+	}
+	return nil, nil
+}
+
+// withResolvedToken resolves a bearer token and returns ctx carrying the
+// result, for AuthMiddleware to reuse
+func withResolvedToken(ctx context.Context, token string) (context.Context, *UserClaims) {
+	claims, err := resolveToken(ctx, token)
+	return context.WithValue(ctx, resolvedTokenKey{}, &resolvedToken{token: token, claims: claims, err: err}), claims
+}
+
+// AuthMiddleware authenticates requests and injects user info and the
+// organization the request acts for into context
 func AuthMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		authHeader := r.Header.Get("Authorization")
 		if !strings.HasPrefix(authHeader, "Bearer ") {
-			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			problem.Error(w, r, http.StatusUnauthorized, "Unauthorized")
 			return
 		}
 
 		token := strings.TrimPrefix(authHeader, "Bearer ")
-		user, err := validateToken(token)
+		user, err := resolveToken(r.Context(), token)
 		if err != nil {
-			http.Error(w, "Invalid token", http.StatusUnauthorized)
+			problem.Error(w, r, http.StatusServiceUnavailable, "Authentication is temporarily unavailable")
+			return
+		}
+		if user == nil {
+			problem.Error(w, r, http.StatusUnauthorized, "Invalid token")
+			return
+		}
+
+		orgID, status, message := ResolveOrg(r.Context(), user, r.Header.Get(OrgHeader))
+		if status != 0 {
+			problem.Error(w, r, status, message)
 			return
 		}
 
+		setRequestUser(r.Context(), user.Username)
 		ctx := context.WithValue(r.Context(), UserContextKey, user)
+		ctx = tenant.NewContext(ctx, orgID)
+		ctx = audit.NewContext(ctx, audit.Actor{Principal: user.Username, IP: audit.ClientIP(r)})
 		next.ServeHTTP(w, r.WithContext(ctx))
 	})
 }
@@ -60,7 +184,7 @@ func RoleAuthorization(allowedRoles ...string) func(http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			user, ok := r.Context().Value(UserContextKey).(*UserClaims)
 			if !ok || user == nil {
-				http.Error(w, "Forbidden", http.StatusForbidden)
+				problem.Error(w, r, http.StatusForbidden, "Forbidden")
 				return
 			}
 
@@ -71,12 +195,15 @@ func RoleAuthorization(allowedRoles ...string) func(http.Handler) http.Handler {
 				}
 			}
 
-			http.Error(w, "Forbidden", http.StatusForbidden)
+			problem.Error(w, r, http.StatusForbidden, "Forbidden")
 		})
 	}
 }
 
 // AuthorizeRoles wraps a handler with authentication and role authorization
 func AuthorizeRoles(handler http.HandlerFunc, allowedRoles ...string) http.HandlerFunc {
-	return AuthMiddleware(RoleAuthorization(allowedRoles...)(handler)).ServeHTTP
+	stack := &Stack{}
+	stack.Use(LayerAuth, AuthMiddleware)
+	stack.Use(LayerRBAC, RoleAuthorization(allowedRoles...))
+	return stack.ThenFunc(handler)
 }
@@ -0,0 +1,26 @@
+package middleware
+
+import (
+	"net/http"
+
+	"com.kong.connect/i18n"
+	"com.kong.connect/problem"
+)
+
+// MaxBodySize caps request bodies at limit bytes. Requests declaring a larger
+// Content-Length are rejected with 413 upfront; for others the body is
+// wrapped in http.MaxBytesReader, so reading past the limit fails with
+// *http.MaxBytesError for the handler to report.
+func MaxBodySize(limit int64) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.ContentLength > limit {
+				problem.Error(w, r, http.StatusRequestEntityTooLarge, i18n.Sprintf(i18n.FromContext(r.Context()), "Request body must not exceed %d bytes", limit))
+				return
+			}
+
+			r.Body = http.MaxBytesReader(w, r.Body, limit)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
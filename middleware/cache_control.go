@@ -0,0 +1,75 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// HTTPCache is the caching policy of catalog reads for browsers, CDNs and
+// proxies. A zero MaxAge leaves responses without caching headers.
+type HTTPCache struct {
+	MaxAge time.Duration
+	// StaleWhileRevalidate lets caches serve a stale response for this long
+	// while they fetch a fresh one in the background
+	StaleWhileRevalidate time.Duration
+}
+
+// CacheControl marks successful responses as cacheable under policy and tags
+// them with the surrogate keys returned by keys, so that they can be purged
+// when the catalog changes. Responses vary by Authorization and X-Org, so a
+// shared cache never serves a response to a client that did not authenticate
+// or one of another organization's catalog.
+func CacheControl(policy HTTPCache, keys func(r *http.Request) []string) func(http.Handler) http.Handler {
+	value := fmt.Sprintf("public, max-age=%d", int(policy.MaxAge.Seconds()))
+	if policy.StaleWhileRevalidate > 0 {
+		value += fmt.Sprintf(", stale-while-revalidate=%d", int(policy.StaleWhileRevalidate.Seconds()))
+	}
+
+	return func(next http.Handler) http.Handler {
+		if policy.MaxAge <= 0 {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(&cacheHeaderWriter{ResponseWriter: w, set: func(h http.Header) {
+				h.Set("Cache-Control", value)
+				h.Add("Vary", "Authorization")
+				h.Add("Vary", OrgHeader)
+				if surrogateKeys := keys(r); len(surrogateKeys) > 0 {
+					h.Set("Surrogate-Key", strings.Join(surrogateKeys, " "))
+				}
+			}}, r)
+		})
+	}
+}
+
+// cacheHeaderWriter adds the caching headers to 200 responses only, since
+// errors must not be cached
+type cacheHeaderWriter struct {
+	http.ResponseWriter
+	set         func(http.Header)
+	wroteHeader bool
+}
+
+func (w *cacheHeaderWriter) WriteHeader(status int) {
+	if !w.wroteHeader {
+		w.wroteHeader = true
+		if status == http.StatusOK && w.Header().Get("Cache-Control") == "" {
+			w.set(w.Header())
+		}
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *cacheHeaderWriter) Write(b []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+// Unwrap exposes the underlying writer to http.ResponseController
+func (w *cacheHeaderWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -0,0 +1,41 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCacheControlMarksSuccessfulResponses(t *testing.T) {
+	status := http.StatusOK
+	handler := CacheControl(HTTPCache{MaxAge: time.Minute, StaleWhileRevalidate: 5 * time.Minute},
+		func(*http.Request) []string { return []string{"services"} },
+	)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+	}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("GET", "/api/v1/services", nil))
+	assert.Equal(t, "public, max-age=60, stale-while-revalidate=300", rec.Header().Get("Cache-Control"))
+	assert.Equal(t, "Authorization", rec.Header().Get("Vary"))
+	assert.Equal(t, "services", rec.Header().Get("Surrogate-Key"))
+
+	status = http.StatusNotFound
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("GET", "/api/v1/services", nil))
+	assert.Empty(t, rec.Header().Get("Cache-Control"))
+	assert.Empty(t, rec.Header().Get("Surrogate-Key"))
+}
+
+func TestCacheControlDisabledWithoutMaxAge(t *testing.T) {
+	handler := CacheControl(HTTPCache{}, nil)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("[]"))
+	}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("GET", "/api/v1/services", nil))
+	assert.Empty(t, rec.Header().Get("Cache-Control"))
+}
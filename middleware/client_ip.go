@@ -0,0 +1,19 @@
+package middleware
+
+import (
+	"net/http"
+	"net/netip"
+
+	"com.kong.connect/clientip"
+)
+
+// ClientIP resolves the IP of the client behind the trusted proxies and
+// stores it in the request context, for the access log, rate limits and
+// audit records. Without trusted proxies the peer is the client.
+func ClientIP(trusted []netip.Prefix) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(w, r.WithContext(clientip.NewContext(r.Context(), clientip.Resolve(r, trusted))))
+		})
+	}
+}
@@ -0,0 +1,40 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"com.kong.connect/problem"
+)
+
+// ConcurrencyLimit bounds the requests handled at once across every handler it
+// wraps, so that a burst of expensive requests queues instead of growing
+// memory without bound. A request finding every slot taken waits for one for
+// up to queueTimeout, and is then answered with 503 and Retry-After; a client
+// giving up while queued leaves the queue.
+func ConcurrencyLimit(limit int, queueTimeout time.Duration) func(http.Handler) http.Handler {
+	slots := make(chan struct{}, limit)
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			select {
+			case slots <- struct{}{}:
+			default:
+				timer := time.NewTimer(queueTimeout)
+				defer timer.Stop()
+				select {
+				case slots <- struct{}{}:
+				case <-timer.C:
+					w.Header().Set("Retry-After", strconv.Itoa(max(1, ceilSeconds(queueTimeout))))
+					problem.Error(w, r, http.StatusServiceUnavailable, "Server is busy")
+					return
+				case <-r.Context().Done():
+					return
+				}
+			}
+			defer func() { <-slots }()
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// blockingHandler answers once release is closed, signalling started when it
+// begins handling a request
+func blockingHandler(started chan<- struct{}, release <-chan struct{}) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		started <- struct{}{}
+		<-release
+		w.WriteHeader(http.StatusOK)
+	})
+}
+
+func TestConcurrencyLimitRejectsAfterQueueTimeout(t *testing.T) {
+	started, release := make(chan struct{}, 1), make(chan struct{})
+	handler := ConcurrencyLimit(1, 20*time.Millisecond)(blockingHandler(started, release))
+
+	first := httptest.NewRecorder()
+	done := make(chan struct{})
+	go func() {
+		handler.ServeHTTP(first, httptest.NewRequest("GET", "/api/v1/services", nil))
+		close(done)
+	}()
+	<-started
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("GET", "/api/v1/services", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	assert.Equal(t, "1", rec.Header().Get("Retry-After"))
+	assert.Equal(t, "application/problem+json", rec.Header().Get("Content-Type"))
+
+	close(release)
+	<-done
+	assert.Equal(t, http.StatusOK, first.Code)
+}
+
+func TestConcurrencyLimitQueuesUntilASlotFrees(t *testing.T) {
+	started, release := make(chan struct{}, 2), make(chan struct{})
+	handler := ConcurrencyLimit(1, time.Second)(blockingHandler(started, release))
+
+	recorders := []*httptest.ResponseRecorder{httptest.NewRecorder(), httptest.NewRecorder()}
+	done := make(chan struct{}, len(recorders))
+	for _, rec := range recorders {
+		go func() {
+			handler.ServeHTTP(rec, httptest.NewRequest("GET", "/api/v1/services", nil))
+			done <- struct{}{}
+		}()
+	}
+
+	<-started
+	select {
+	case <-started:
+		t.Fatal("Expected the second request to wait for the first")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	close(release)
+	<-done
+	<-done
+	for _, rec := range recorders {
+		assert.Equal(t, http.StatusOK, rec.Code)
+	}
+}
@@ -0,0 +1,52 @@
+package middleware
+
+import (
+	"net/http"
+	"slices"
+	"sync/atomic"
+)
+
+// CORS adds cross-origin headers for a set of allowed origins, which can be
+// replaced while serving. "*" allows every origin.
+type CORS struct {
+	origins atomic.Pointer[[]string]
+}
+
+// NewCORS creates a CORS policy; no origins means every origin is allowed
+func NewCORS(origins []string) *CORS {
+	c := &CORS{}
+	c.SetOrigins(origins)
+	return c
+}
+
+// SetOrigins replaces the allowed origins
+func (c *CORS) SetOrigins(origins []string) {
+	if len(origins) == 0 {
+		origins = []string{"*"}
+	}
+	origins = slices.Clone(origins)
+	c.origins.Store(&origins)
+}
+
+// Middleware adds the CORS headers. Preflight requests are answered by the
+// router, with the methods of the route in Access-Control-Allow-Methods.
+func (c *CORS) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		origins := *c.origins.Load()
+		origin := r.Header.Get("Origin")
+
+		switch {
+		case slices.Contains(origins, "*"):
+			w.Header().Set("Access-Control-Allow-Origin", "*")
+		case origin != "" && slices.Contains(origins, origin):
+			w.Header().Set("Access-Control-Allow-Origin", origin)
+			w.Header().Add("Vary", "Origin")
+		default:
+			w.Header().Add("Vary", "Origin")
+		}
+		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-Request-ID, X-Request-Timeout")
+		w.Header().Set("Access-Control-Expose-Headers", "X-Total-Count, Link, X-Request-ID, API-Version, Server-Timing, X-RateLimit-Limit, X-RateLimit-Remaining, X-RateLimit-Reset, Retry-After")
+
+		next.ServeHTTP(w, r)
+	})
+}
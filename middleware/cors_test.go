@@ -0,0 +1,30 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCORSAllowedOrigins(t *testing.T) {
+	cors := NewCORS(nil)
+	handler := cors.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	request := func(origin string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", "/api/v1/services", nil)
+		req.Header.Set("Origin", origin)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	assert.Equal(t, "*", request("https://example.com").Header().Get("Access-Control-Allow-Origin"))
+
+	cors.SetOrigins([]string{"https://portal.example.com"})
+	rec := request("https://portal.example.com")
+	assert.Equal(t, "https://portal.example.com", rec.Header().Get("Access-Control-Allow-Origin"))
+	assert.Equal(t, "Origin", rec.Header().Get("Vary"))
+	assert.Empty(t, request("https://evil.example.com").Header().Get("Access-Control-Allow-Origin"))
+}
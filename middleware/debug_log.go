@@ -0,0 +1,172 @@
+package middleware
+
+import (
+	"bytes"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// redacted replaces the values of secrets in debug logs
+const redacted = "[REDACTED]"
+
+// secretHeaders are the headers whose values are never logged
+var secretHeaders = []string{"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie", "X-Api-Key", "X-Hub-Signature-256", "X-Signature"}
+
+// secretName matches the names of JSON fields and query parameters holding
+// secrets, such as the token of an issued API token or the secret of a webhook
+var secretName = regexp.MustCompile(`(?i)(password|passwd|secret|token|api_?key|authorization|credential|private_?key|signature)`)
+
+// secretJSONField matches a JSON field and its string, number, boolean or
+// null value; objects and arrays are not matched. It also works on bodies
+// cut short by the size cap.
+var secretJSONField = regexp.MustCompile(`"((?:[^"\\]|\\.)*)"(\s*:\s*)("(?:[^"\\]|\\.)*"?|[^\s,}\]]+)`)
+
+// DebugLog logs the headers and bodies of every request and response at
+// debug level, for troubleshooting integrations. Bodies are cut at maxBytes,
+// and secrets are redacted: the values of credential headers, and of JSON
+// fields and query parameters named like secrets. Requests are only captured
+// while logger is enabled for debug, so the log level can be raised without
+// a restart.
+func DebugLog(logger *slog.Logger, maxBytes int) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !logger.Enabled(r.Context(), slog.LevelDebug) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			requestBody := &cappedBuffer{max: maxBytes}
+			if r.Body != nil && r.Body != http.NoBody {
+				r.Body = struct {
+					io.Reader
+					io.Closer
+				}{io.TeeReader(r.Body, requestBody), r.Body}
+			}
+			rec := &bodyRecorder{statusRecorder: newStatusRecorder(w), body: cappedBuffer{max: maxBytes}}
+
+			next.ServeHTTP(rec, r)
+
+			logger.DebugContext(r.Context(), "http exchange",
+				slog.Group("request",
+					"method", r.Method,
+					"uri", redactURI(r.URL),
+					"headers", redactHeaders(r.Header),
+					"body", requestBody.String(r.Header.Get("Content-Type")),
+				),
+				slog.Group("response",
+					"status", rec.status,
+					"headers", redactHeaders(rec.Header()),
+					"body", rec.body.String(rec.Header().Get("Content-Type")),
+				),
+			)
+		})
+	}
+}
+
+// bodyRecorder keeps the start of the response body
+type bodyRecorder struct {
+	*statusRecorder
+	body cappedBuffer
+}
+
+func (rec *bodyRecorder) Write(b []byte) (int, error) {
+	rec.body.Write(b)
+	return rec.statusRecorder.Write(b)
+}
+
+// cappedBuffer keeps the first max bytes written to it and counts the rest
+type cappedBuffer struct {
+	max   int
+	buf   bytes.Buffer
+	total int
+}
+
+func (b *cappedBuffer) Write(p []byte) (int, error) {
+	b.total += len(p)
+	if room := b.max - b.buf.Len(); room > 0 {
+		b.buf.Write(p[:min(room, len(p))])
+	}
+	return len(p), nil
+}
+
+// String renders the body for the log: redacted text, cut at the cap, or
+// the size of binary content
+func (b *cappedBuffer) String(contentType string) string {
+	if b.total == 0 {
+		return ""
+	}
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	var text string
+	switch {
+	case strings.Contains(mediaType, "json"):
+		text = redactJSON(b.buf.String())
+	case mediaType == "application/x-www-form-urlencoded":
+		text = redactQuery(b.buf.String())
+	case strings.HasPrefix(mediaType, "text/"), strings.Contains(mediaType, "yaml"), strings.Contains(mediaType, "xml"):
+		text = b.buf.String()
+	default:
+		return "[" + strconv.Itoa(b.total) + " bytes of " + dashIfEmpty(mediaType) + "]"
+	}
+	if b.total > b.buf.Len() {
+		text += "… [" + strconv.Itoa(b.total-b.buf.Len()) + " more bytes]"
+	}
+	return text
+}
+
+// redactHeaders flattens headers, hiding the values of credential headers
+func redactHeaders(header http.Header) map[string]string {
+	flat := make(map[string]string, len(header))
+	for name, values := range header {
+		flat[name] = strings.Join(values, ", ")
+	}
+	for _, name := range secretHeaders {
+		if _, ok := flat[name]; ok {
+			flat[name] = redacted
+		}
+	}
+	return flat
+}
+
+// redactJSON hides the values of the fields of a JSON document named like
+// secrets
+func redactJSON(text string) string {
+	return secretJSONField.ReplaceAllStringFunc(text, func(field string) string {
+		match := secretJSONField.FindStringSubmatch(field)
+		if !secretName.MatchString(match[1]) {
+			return field
+		}
+		return `"` + match[1] + `"` + match[2] + `"` + redacted + `"`
+	})
+}
+
+// redactURI renders the request URI, hiding the values of query parameters
+// named like secrets
+func redactURI(u *url.URL) string {
+	uri := u.EscapedPath()
+	if u.RawQuery != "" {
+		uri += "?" + redactQuery(u.RawQuery)
+	}
+	return uri
+}
+
+// redactQuery hides the values of the parameters of a query string or form
+// body named like secrets
+func redactQuery(query string) string {
+	pairs := strings.Split(query, "&")
+	for i, pair := range pairs {
+		name, _, hasValue := strings.Cut(pair, "=")
+		if unescaped, err := url.QueryUnescape(name); err == nil {
+			name = unescaped
+		}
+		if hasValue && secretName.MatchString(name) {
+			pairs[i] = pair[:strings.Index(pair, "=")+1] + url.QueryEscape(redacted)
+		}
+	}
+	return strings.Join(pairs, "&")
+}
@@ -0,0 +1,81 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func debugLogEntry(t *testing.T, level slog.Level, maxBytes int, req *http.Request, handler http.HandlerFunc) map[string]interface{} {
+	t.Helper()
+	var out bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&out, &slog.HandlerOptions{Level: level}))
+	DebugLog(logger, maxBytes)(handler).ServeHTTP(httptest.NewRecorder(), req)
+	if out.Len() == 0 {
+		return nil
+	}
+	var entry map[string]interface{}
+	require.NoError(t, json.Unmarshal(out.Bytes(), &entry))
+	return entry
+}
+
+func TestDebugLogRedactsSecrets(t *testing.T) {
+	req := httptest.NewRequest("POST", "/api/v1/webhooks?access_token=abc&page=2", strings.NewReader(`{"url": "https://hooks.example.com", "secret": "s3cr3t", "retries": 3}`))
+	req.Header.Set("Authorization", "Bearer admin-token")
+	req.Header.Set("Content-Type", "application/json")
+
+	entry := debugLogEntry(t, slog.LevelDebug, 1024, req, func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		assert.Contains(t, string(body), "s3cr3t", "handlers read the body unchanged")
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Set-Cookie", "session=abc")
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"id": 1, "token": "kc_0123456789", "api_key": null}`))
+	})
+	require.NotNil(t, entry)
+
+	request := entry["request"].(map[string]interface{})
+	assert.Equal(t, "/api/v1/webhooks?access_token=%5BREDACTED%5D&page=2", request["uri"])
+	assert.Equal(t, "[REDACTED]", request["headers"].(map[string]interface{})["Authorization"])
+	assert.Equal(t, `{"url": "https://hooks.example.com", "secret": "[REDACTED]", "retries": 3}`, request["body"])
+
+	response := entry["response"].(map[string]interface{})
+	assert.Equal(t, float64(http.StatusCreated), response["status"])
+	assert.Equal(t, "[REDACTED]", response["headers"].(map[string]interface{})["Set-Cookie"])
+	assert.Equal(t, `{"id": 1, "token": "[REDACTED]", "api_key": "[REDACTED]"}`, response["body"])
+}
+
+func TestDebugLogCapsBodies(t *testing.T) {
+	req := httptest.NewRequest("POST", "/api/v1/services", strings.NewReader(`{"name": "Billing", "password": "hunter2hunter2"}`))
+	req.Header.Set("Content-Type", "application/json")
+
+	entry := debugLogEntry(t, slog.LevelDebug, 36, req, func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body)
+		w.Header().Set("Content-Type", "application/octet-stream")
+		w.Write(make([]byte, 100))
+	})
+	require.NotNil(t, entry)
+
+	// The value is redacted although the cap cuts it
+	assert.Equal(t, `{"name": "Billing", "password": "[REDACTED]"… [13 more bytes]`, entry["request"].(map[string]interface{})["body"])
+	assert.Equal(t, "[100 bytes of application/octet-stream]", entry["response"].(map[string]interface{})["body"])
+}
+
+func TestDebugLogOnlyAtDebugLevel(t *testing.T) {
+	req := httptest.NewRequest("GET", "/api/v1/services", nil)
+	entry := debugLogEntry(t, slog.LevelInfo, 1024, req, func(w http.ResponseWriter, r *http.Request) {
+		_, captured := w.(*bodyRecorder)
+		assert.False(t, captured)
+	})
+	assert.Nil(t, entry)
+}
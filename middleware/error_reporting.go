@@ -0,0 +1,80 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"runtime/debug"
+
+	"com.kong.connect/errreport"
+	"com.kong.connect/problem"
+	"com.kong.connect/requestid"
+)
+
+// ErrorReporting recovers panics, answering them with a 500 problem response,
+// and sends panics and 5xx responses to the reporter together with the
+// request, request ID, user and the error handlers recorded with RecordError.
+// Errors injected by FaultInjection are not reported. It runs outside
+// RequestID, which records the ID for it.
+func ErrorReporting(reporter errreport.Reporter, logger *slog.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx, state := withRequestState(r.Context())
+			r = r.WithContext(ctx)
+			rec := newStatusRecorder(w)
+
+			report := func(err error, panicked bool, status int) {
+				user, _ := state.snapshot()
+				ctx := withRecordedRequestID(ctx, state)
+				reporter.Report(ctx, errreport.Event{
+					Err:       err,
+					Panic:     panicked,
+					Status:    status,
+					Request:   r,
+					RequestID: requestid.FromContext(ctx),
+					User:      user,
+				})
+			}
+
+			defer func() {
+				recovered := recover()
+				if recovered == nil {
+					return
+				}
+				// ErrAbortHandler is the sanctioned way to abort a response
+				if recovered == http.ErrAbortHandler {
+					panic(recovered)
+				}
+
+				ctx := withRecordedRequestID(ctx, state)
+				err := fmt.Errorf("panic: %v", recovered)
+				logger.ErrorContext(ctx, "recovered from panic", "error", err, "stack", string(debug.Stack()))
+				report(err, true, http.StatusInternalServerError)
+				if !rec.wroteHeader {
+					problem.Error(rec, r.WithContext(ctx), http.StatusInternalServerError, "Internal server error")
+				}
+			}()
+
+			next.ServeHTTP(rec, r)
+
+			// Injected errors are expected, not failures of the server
+			if rec.status >= http.StatusInternalServerError && !state.faultInjected() {
+				_, err := state.snapshot()
+				if err == nil {
+					err = fmt.Errorf("%s %s returned %d", r.Method, r.URL.Path, rec.status)
+				}
+				report(err, false, rec.status)
+			}
+		})
+	}
+}
+
+// withRecordedRequestID returns ctx carrying the request ID recorded in state
+// by RequestID, which runs inside error reporting
+func withRecordedRequestID(ctx context.Context, state *requestState) context.Context {
+	if id := state.id(); id != "" {
+		return requestid.NewContext(ctx, id)
+	}
+	return ctx
+}
@@ -0,0 +1,90 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"com.kong.connect/errreport"
+)
+
+type recordingReporter struct {
+	mu     sync.Mutex
+	events []errreport.Event
+}
+
+func (r *recordingReporter) Report(_ context.Context, event errreport.Event) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, event)
+}
+
+func (r *recordingReporter) Flush(time.Duration) bool { return true }
+
+func TestErrorReportingRecoversPanics(t *testing.T) {
+	reporter := &recordingReporter{}
+	handler := ErrorReporting(reporter, slog.Default())(AuthMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	})))
+
+	req := httptest.NewRequest("GET", "/api/v1/services", nil)
+	req.Header.Set("Authorization", "Bearer admin-token")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	assert.Equal(t, "application/problem+json", rec.Header().Get("Content-Type"))
+	require.Len(t, reporter.events, 1)
+	assert.True(t, reporter.events[0].Panic)
+	assert.EqualError(t, reporter.events[0].Err, "panic: boom")
+	assert.Equal(t, "admin", reporter.events[0].User)
+}
+
+func TestErrorReportingReportsServerErrors(t *testing.T) {
+	reporter := &recordingReporter{}
+	handler := ErrorReporting(reporter, slog.Default())(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/fail":
+			RecordError(r.Context(), errors.New("database is locked"))
+			w.WriteHeader(http.StatusInternalServerError)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/missing", nil))
+	assert.Empty(t, reporter.events)
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/fail", nil))
+	require.Len(t, reporter.events, 1)
+	assert.False(t, reporter.events[0].Panic)
+	assert.Equal(t, http.StatusInternalServerError, reporter.events[0].Status)
+	assert.EqualError(t, reporter.events[0].Err, "database is locked")
+}
+
+func TestErrorReportingSkipsInjectedFaults(t *testing.T) {
+	reporter := &recordingReporter{}
+	faults := Faults{ErrorPercent: 100, ErrorStatuses: []int{http.StatusBadGateway}}
+	handler := ErrorReporting(reporter, slog.Default())(FaultInjection(faults)(okHandler()))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("GET", "/api/v1/services", nil))
+	assert.Equal(t, http.StatusBadGateway, rec.Code)
+	assert.Empty(t, reporter.events)
+
+	// Failures of the handler behind fault injection are still reported
+	handler = ErrorReporting(reporter, slog.Default())(FaultInjection(Faults{})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/api/v1/services", nil))
+	require.Len(t, reporter.events, 1)
+	assert.Equal(t, http.StatusInternalServerError, reporter.events[0].Status)
+}
@@ -0,0 +1,102 @@
+package middleware
+
+import (
+	"errors"
+	"math/rand/v2"
+	"net/http"
+	"time"
+
+	"com.kong.connect/problem"
+)
+
+// FaultHeader names the faults injected into a response, so that clients
+// can tell them from genuine failures
+const FaultHeader = "X-Fault-Injected"
+
+// Faults are the failures injected into a share of the requests, each given
+// in percent. They are meant for test deployments, where client teams check
+// their timeouts and retries against them.
+type Faults struct {
+	// LatencyPercent of the requests are delayed by Latency before anything
+	// else happens to them
+	LatencyPercent float64
+	Latency        time.Duration
+	// ErrorPercent of the requests are answered with one of ErrorStatuses,
+	// picked at random, without being handled
+	ErrorPercent  float64
+	ErrorStatuses []int
+	// DropPercent of the requests have their connection closed without a
+	// response
+	DropPercent float64
+}
+
+// Enabled reports whether any fault is injected
+func (f Faults) Enabled() bool {
+	return (f.LatencyPercent > 0 && f.Latency > 0) || f.ErrorPercent > 0 || f.DropPercent > 0
+}
+
+// Validate checks that the percentages are between 0 and 100, errors and
+// drops together at most 100, and that errors have 5xx statuses to use
+func (f Faults) Validate() error {
+	for _, percent := range []float64{f.LatencyPercent, f.ErrorPercent, f.DropPercent} {
+		if percent < 0 || percent > 100 {
+			return errors.New("percentages must be between 0 and 100")
+		}
+	}
+	if f.ErrorPercent+f.DropPercent > 100 {
+		return errors.New("errors and dropped connections together exceed 100 percent")
+	}
+	if f.ErrorPercent > 0 && len(f.ErrorStatuses) == 0 {
+		return errors.New("errors need a status to answer with")
+	}
+	for _, status := range f.ErrorStatuses {
+		if status < 500 || status > 599 {
+			return errors.New("error statuses must be 5xx")
+		}
+	}
+	return nil
+}
+
+// FaultInjection injects faults into a share of the requests. A request is
+// delayed with a chance of LatencyPercent, and then either dropped, answered
+// with an error or handled; a client giving up while delayed is not answered.
+// Dropped connections abort the handler with http.ErrAbortHandler, which the
+// server answers by closing the connection.
+func FaultInjection(faults Faults) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if faults.Latency > 0 && chance(faults.LatencyPercent) {
+				timer := time.NewTimer(faults.Latency)
+				defer timer.Stop()
+				select {
+				case <-timer.C:
+				case <-r.Context().Done():
+					return
+				}
+				w.Header().Add(FaultHeader, "latency")
+			}
+
+			roll := rand.Float64() * 100
+			switch {
+			case roll < faults.DropPercent:
+				panic(http.ErrAbortHandler)
+			case roll < faults.DropPercent+faults.ErrorPercent:
+				status := faults.ErrorStatuses[rand.N(len(faults.ErrorStatuses))]
+				w.Header().Add(FaultHeader, "error")
+				setFaultInjected(r.Context())
+				if status == http.StatusServiceUnavailable {
+					w.Header().Set("Retry-After", "1")
+				}
+				problem.Error(w, r, status, "Fault injected")
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// chance reports true with a probability of percent
+func chance(percent float64) bool {
+	return rand.Float64()*100 < percent
+}
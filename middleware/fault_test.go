@@ -0,0 +1,80 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func okHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+}
+
+func TestFaultInjectionAnswersWithErrors(t *testing.T) {
+	handler := FaultInjection(Faults{ErrorPercent: 100, ErrorStatuses: []int{http.StatusServiceUnavailable}})(okHandler())
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("GET", "/api/v1/services", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	assert.Equal(t, "1", rec.Header().Get("Retry-After"))
+	assert.Equal(t, "application/problem+json", rec.Header().Get("Content-Type"))
+	assert.Equal(t, []string{"error"}, rec.Header().Values(FaultHeader))
+}
+
+func TestFaultInjectionDropsConnections(t *testing.T) {
+	handler := FaultInjection(Faults{DropPercent: 100})(okHandler())
+
+	assert.PanicsWithValue(t, http.ErrAbortHandler, func() {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/api/v1/services", nil))
+	})
+}
+
+func TestFaultInjectionDelaysRequests(t *testing.T) {
+	handler := FaultInjection(Faults{LatencyPercent: 100, Latency: 20 * time.Millisecond})(okHandler())
+
+	rec := httptest.NewRecorder()
+	start := time.Now()
+	handler.ServeHTTP(rec, httptest.NewRequest("GET", "/api/v1/services", nil))
+	assert.GreaterOrEqual(t, time.Since(start), 20*time.Millisecond)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, []string{"latency"}, rec.Header().Values(FaultHeader))
+
+	// A client giving up while delayed is not answered
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("GET", "/api/v1/services", nil).WithContext(ctx))
+	assert.False(t, rec.Flushed)
+	assert.Empty(t, rec.Header().Values(FaultHeader))
+}
+
+func TestFaultInjectionHandlesTheOtherRequests(t *testing.T) {
+	handler := FaultInjection(Faults{LatencyPercent: 0, Latency: time.Hour, ErrorPercent: 0, ErrorStatuses: []int{http.StatusInternalServerError}})(okHandler())
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("GET", "/api/v1/services", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Empty(t, rec.Header().Values(FaultHeader))
+}
+
+func TestFaultsValidate(t *testing.T) {
+	assert.NoError(t, Faults{}.Validate())
+	assert.False(t, Faults{ErrorStatuses: []int{http.StatusInternalServerError}}.Enabled())
+	assert.True(t, Faults{DropPercent: 0.5}.Enabled())
+
+	for name, faults := range map[string]Faults{
+		"negative":      {LatencyPercent: -1},
+		"above 100":     {DropPercent: 101},
+		"sum above 100": {ErrorPercent: 60, DropPercent: 60, ErrorStatuses: []int{http.StatusBadGateway}},
+		"no statuses":   {ErrorPercent: 10},
+		"not 5xx":       {ErrorPercent: 10, ErrorStatuses: []int{http.StatusTooManyRequests}},
+	} {
+		assert.Error(t, faults.Validate(), name)
+	}
+}
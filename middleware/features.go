@@ -0,0 +1,16 @@
+package middleware
+
+import (
+	"net/http"
+
+	"com.kong.connect/features"
+)
+
+// Features attaches the current feature flags to the request context
+func Features(store *features.Store) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(w, r.WithContext(features.NewContext(r.Context(), store.Load())))
+		})
+	}
+}
@@ -0,0 +1,20 @@
+package middleware
+
+import (
+	"net/http"
+
+	"com.kong.connect/i18n"
+)
+
+// Locale negotiates the language of the response from the Accept-Language
+// header and stores it in the request context, for the messages of problems
+// and the descriptions of services. The response names it in
+// Content-Language, and varies by Accept-Language for caches.
+func Locale(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lang := i18n.Negotiate(r.Header.Get("Accept-Language"))
+		w.Header().Set("Content-Language", lang)
+		w.Header().Add("Vary", "Accept-Language")
+		next.ServeHTTP(w, r.WithContext(i18n.NewContext(r.Context(), lang)))
+	})
+}
@@ -0,0 +1,21 @@
+package middleware
+
+import (
+	"net/http"
+	"time"
+
+	"com.kong.connect/metrics"
+)
+
+// Metrics records the latency and status of every request by route template,
+// feeding the request duration histogram and the latency SLOs
+func Metrics(registry *metrics.Registry) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			rec := newStatusRecorder(w)
+			next.ServeHTTP(rec, r)
+			registry.ObserveRequest(r.Method, routeTemplate(r), rec.status, time.Since(start))
+		})
+	}
+}
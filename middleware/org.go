@@ -0,0 +1,72 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+
+	"com.kong.connect/problem"
+	"com.kong.connect/tenant"
+)
+
+// OrgHeader selects the organization a request acts for by its slug
+const OrgHeader = "X-Org"
+
+// OrgLookup resolves an organization slug to its ID. It returns 0 for
+// unknown slugs.
+type OrgLookup func(ctx context.Context, slug string) (int, error)
+
+var orgLookup atomic.Pointer[OrgLookup]
+
+// SetOrgLookup consults lookup for the organizations selected with the X-Org
+// header; nil accepts the default organization only
+func SetOrgLookup(lookup OrgLookup) {
+	if lookup == nil {
+		orgLookup.Store(nil)
+		return
+	}
+	orgLookup.Store(&lookup)
+}
+
+// ResolveOrg returns the organization a user acts for. Tokens issued in an
+// organization act for it alone; other tokens act for the organization named
+// by slug, or the default organization when slug is empty. On failure it
+// returns the HTTP status and message to answer with.
+func ResolveOrg(ctx context.Context, user *UserClaims, slug string) (int, int, string) {
+	if slug == "" {
+		if user.OrgID != 0 {
+			return user.OrgID, 0, ""
+		}
+		return tenant.DefaultOrg, 0, ""
+	}
+
+	orgID := 0
+	if lookup := orgLookup.Load(); lookup != nil {
+		var err error
+		if orgID, err = (*lookup)(ctx, slug); err != nil {
+			return 0, http.StatusServiceUnavailable, "Authentication is temporarily unavailable"
+		}
+	} else if slug == tenant.DefaultSlug {
+		orgID = tenant.DefaultOrg
+	}
+	if orgID == 0 {
+		return 0, http.StatusNotFound, "Organization not found"
+	}
+	if user.OrgID != 0 && user.OrgID != orgID {
+		return 0, http.StatusForbidden, "Token is not valid for this organization"
+	}
+	return orgID, 0, ""
+}
+
+// DeploymentWide refuses tokens issued in an organization, for the routes
+// that manage the whole deployment such as users and organizations
+func DeploymentWide(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, ok := r.Context().Value(UserContextKey).(*UserClaims)
+		if !ok || user == nil || user.OrgID != 0 {
+			problem.Error(w, r, http.StatusForbidden, "Forbidden")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
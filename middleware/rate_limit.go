@@ -0,0 +1,73 @@
+package middleware
+
+import (
+	"context"
+	"log/slog"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"com.kong.connect/audit"
+	"com.kong.connect/problem"
+	"com.kong.connect/ratelimit"
+)
+
+// RateLimit limits requests of a route group per client with a token bucket.
+// The limit is looked up in the policy on every request so that it can be
+// reloaded; groups without a limit pass through. Responses carry
+// X-RateLimit-* headers, and requests over the limit are answered with 429
+// and Retry-After. When the store fails, requests are let through rather than
+// turning a store outage into an API outage.
+func RateLimit(store ratelimit.Store, policy *ratelimit.Policy, group string, logger *slog.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			limit, ok := policy.Lookup(group)
+			if !ok {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			ctx, key := rateLimitKey(r)
+			r = r.WithContext(ctx)
+			result, err := store.Allow(r.Context(), group+":"+key, limit)
+			if err != nil {
+				logger.WarnContext(r.Context(), "rate limit store unavailable", "group", group, "error", err)
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			w.Header().Set("X-RateLimit-Limit", strconv.Itoa(result.Limit))
+			w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(result.Remaining))
+			w.Header().Set("X-RateLimit-Reset", strconv.Itoa(ceilSeconds(result.ResetAfter)))
+
+			if !result.Allowed {
+				w.Header().Set("Retry-After", strconv.Itoa(max(1, ceilSeconds(result.RetryAfter))))
+				problem.Error(w, r, http.StatusTooManyRequests, "Rate limit exceeded")
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// rateLimitKey identifies the client a request counts against: the user of a
+// valid bearer token, so that clients behind a shared NAT don't starve each
+// other, and the client IP otherwise. The token is resolved once per request:
+// the returned context carries it for authentication.
+func rateLimitKey(r *http.Request) (context.Context, string) {
+	ctx := r.Context()
+	if token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok {
+		var user *UserClaims
+		if ctx, user = withResolvedToken(ctx, token); user != nil {
+			return ctx, "user:" + user.Username
+		}
+	}
+	return ctx, "ip:" + audit.ClientIP(r)
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
@@ -0,0 +1,97 @@
+package middleware
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"com.kong.connect/ratelimit"
+)
+
+func TestRateLimit(t *testing.T) {
+	limit := ratelimit.Limit{Rate: 0.5, Burst: 2}
+	policy := ratelimit.NewPolicy(map[string]ratelimit.Limit{"read": limit})
+	handler := RateLimit(ratelimit.NewMemoryStore(), policy, "read", slog.Default())(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}),
+	)
+
+	request := func(token, addr string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", "/api/v1/services", nil)
+		req.RemoteAddr = addr
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := request("viewer-token", "10.0.0.1:1234")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "2", rec.Header().Get("X-RateLimit-Limit"))
+	assert.Equal(t, "1", rec.Header().Get("X-RateLimit-Remaining"))
+
+	// The same user from another address shares the bucket
+	rec = request("viewer-token", "10.0.0.2:1234")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "0", rec.Header().Get("X-RateLimit-Remaining"))
+
+	rec = request("viewer-token", "10.0.0.1:1234")
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assert.Equal(t, "2", rec.Header().Get("Retry-After"))
+	assert.Equal(t, "application/problem+json", rec.Header().Get("Content-Type"))
+
+	// Other users and anonymous clients have their own buckets
+	assert.Equal(t, http.StatusOK, request("admin-token", "10.0.0.1:1234").Code)
+	assert.Equal(t, http.StatusOK, request("", "10.0.0.1:1234").Code)
+
+	// Lifting the limit takes effect immediately
+	policy.Set(nil)
+	rec = request("viewer-token", "10.0.0.1:1234")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Empty(t, rec.Header().Get("X-RateLimit-Limit"))
+}
+
+func TestRateLimitResolvesTokensOnce(t *testing.T) {
+	lookups := 0
+	SetTokenLookup(func(ctx context.Context, token string) (*UserClaims, error) {
+		lookups++
+		if token != "issued-token" {
+			return nil, nil
+		}
+		return &UserClaims{Username: "alice", Roles: []string{"viewer"}}, nil
+	})
+	t.Cleanup(func() { SetTokenLookup(nil) })
+
+	policy := ratelimit.NewPolicy(map[string]ratelimit.Limit{"read": {Rate: 1, Burst: 1}})
+	var user *UserClaims
+	handler := RateLimit(ratelimit.NewMemoryStore(), policy, "read", slog.Default())(
+		AuthMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			user, _ = r.Context().Value(UserContextKey).(*UserClaims)
+		})),
+	)
+
+	request := func(token, addr string) int {
+		req := httptest.NewRequest("GET", "/api/v1/services", nil)
+		req.RemoteAddr = addr
+		req.Header.Set("Authorization", "Bearer "+token)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	assert.Equal(t, http.StatusOK, request("issued-token", "10.0.0.1:1234"))
+	assert.Equal(t, 1, lookups)
+	assert.Equal(t, "alice", user.Username)
+
+	// Issued tokens count against their user too
+	assert.Equal(t, http.StatusTooManyRequests, request("issued-token", "10.0.0.2:1234"))
+
+	// Unknown tokens are looked up once and count against the client IP
+	assert.Equal(t, http.StatusUnauthorized, request("unknown-token", "10.0.0.3:1234"))
+	assert.Equal(t, 3, lookups)
+}
@@ -0,0 +1,87 @@
+package middleware
+
+import (
+	"context"
+	"sync"
+)
+
+// requestState collects details of a request that only handlers deeper in the
+// chain know, such as the authenticated user or the cause of a 5xx response,
+// for outer middleware like the access log and error reporting
+type requestState struct {
+	mu        sync.Mutex
+	requestID string
+	user      string
+	err       error
+	// faulted is set for responses with an injected error
+	faulted bool
+}
+
+type requestStateKey struct{}
+
+// withRequestState returns ctx carrying a request state, reusing one installed
+// by an outer middleware
+func withRequestState(ctx context.Context) (context.Context, *requestState) {
+	if state, ok := ctx.Value(requestStateKey{}).(*requestState); ok {
+		return ctx, state
+	}
+	state := &requestState{}
+	return context.WithValue(ctx, requestStateKey{}, state), state
+}
+
+// setRequestID records the ID of the request, for the middleware outside
+// RequestID
+func setRequestID(ctx context.Context, id string) {
+	if state, ok := ctx.Value(requestStateKey{}).(*requestState); ok {
+		state.mu.Lock()
+		state.requestID = id
+		state.mu.Unlock()
+	}
+}
+
+// setRequestUser records the authenticated user of the request
+func setRequestUser(ctx context.Context, user string) {
+	if state, ok := ctx.Value(requestStateKey{}).(*requestState); ok {
+		state.mu.Lock()
+		state.user = user
+		state.mu.Unlock()
+	}
+}
+
+// RecordError records the error that caused a 5xx response, so that error
+// reporting can include it
+func RecordError(ctx context.Context, err error) {
+	if state, ok := ctx.Value(requestStateKey{}).(*requestState); ok {
+		state.mu.Lock()
+		state.err = err
+		state.mu.Unlock()
+	}
+}
+
+// setFaultInjected records that the response is an injected error, which
+// error reporting leaves out
+func setFaultInjected(ctx context.Context) {
+	if state, ok := ctx.Value(requestStateKey{}).(*requestState); ok {
+		state.mu.Lock()
+		state.faulted = true
+		state.mu.Unlock()
+	}
+}
+
+func (s *requestState) snapshot() (user string, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.user, s.err
+}
+
+func (s *requestState) id() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.requestID
+}
+
+func (s *requestState) faultInjected() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.faulted
+}
@@ -0,0 +1,75 @@
+package middleware
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+)
+
+// APIVersionHeader names the version of the API that answered a request
+const APIVersionHeader = "API-Version"
+
+// ResponseMeta adds to every response, next to its X-Request-ID, what support
+// tooling needs to correlate a user report with the logs and traces of the
+// request: the API version and, in Server-Timing, the milliseconds the server
+// spent before it started answering.
+func ResponseMeta(apiVersion string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(&metaWriter{ResponseWriter: w, apiVersion: apiVersion, start: time.Now()}, r)
+		})
+	}
+}
+
+// metaWriter sets the headers of ResponseMeta when the response starts
+type metaWriter struct {
+	http.ResponseWriter
+	apiVersion  string
+	start       time.Time
+	wroteHeader bool
+}
+
+func (mw *metaWriter) setHeaders() {
+	if mw.wroteHeader {
+		return
+	}
+	mw.wroteHeader = true
+	elapsed := float64(time.Since(mw.start).Microseconds()) / 1000
+	mw.Header().Set(APIVersionHeader, mw.apiVersion)
+	mw.Header().Add("Server-Timing", fmt.Sprintf("app;dur=%.3f", elapsed))
+}
+
+func (mw *metaWriter) WriteHeader(status int) {
+	mw.setHeaders()
+	mw.ResponseWriter.WriteHeader(status)
+}
+
+func (mw *metaWriter) Write(b []byte) (int, error) {
+	mw.setHeaders()
+	return mw.ResponseWriter.Write(b)
+}
+
+// Flush supports streaming responses through the writer
+func (mw *metaWriter) Flush() {
+	mw.setHeaders()
+	if flusher, ok := mw.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Hijack supports WebSocket upgrades through the writer
+func (mw *metaWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := mw.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, errors.New("response writer does not support hijacking")
+	}
+	return hijacker.Hijack()
+}
+
+// Unwrap exposes the underlying writer to http.ResponseController
+func (mw *metaWriter) Unwrap() http.ResponseWriter {
+	return mw.ResponseWriter
+}
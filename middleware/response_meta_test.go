@@ -0,0 +1,40 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResponseMetaSetsVersionAndTiming(t *testing.T) {
+	handler := ResponseMeta("v1")(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(2 * time.Millisecond)
+		w.WriteHeader(http.StatusTeapot)
+		w.Write([]byte("short and stout"))
+	}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("GET", "/api/v1/services", nil))
+	assert.Equal(t, http.StatusTeapot, rec.Code)
+	assert.Equal(t, "v1", rec.Header().Get(APIVersionHeader))
+	timing := rec.Header().Values("Server-Timing")
+	if assert.Len(t, timing, 1) {
+		assert.Regexp(t, regexp.MustCompile(`^app;dur=\d+\.\d{3}$`), timing[0])
+		assert.NotEqual(t, "app;dur=0.000", timing[0])
+	}
+}
+
+func TestResponseMetaWithoutExplicitStatus(t *testing.T) {
+	handler := ResponseMeta("v1")(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("{}"))
+	}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("GET", "/api/v1/services/1", nil))
+	assert.Equal(t, "v1", rec.Header().Get(APIVersionHeader))
+	assert.Len(t, rec.Header().Values("Server-Timing"), 1)
+}
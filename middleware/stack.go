@@ -0,0 +1,62 @@
+package middleware
+
+import "net/http"
+
+// Middleware wraps a handler with behaviour of its own
+type Middleware func(http.Handler) http.Handler
+
+// Layer is the position of middleware in a Stack. Layers wrap each other in
+// the order they are declared, the first outermost; the handler sits inside
+// the last.
+type Layer int
+
+const (
+	// LayerInstrumentation measures the whole request: response metadata,
+	// tracing and metrics
+	LayerInstrumentation Layer = iota
+	// LayerRecovery recovers panics and reports errors
+	LayerRecovery
+	// LayerRequestID identifies the request and attaches request-scoped
+	// context, such as the feature flags and the language of the client
+	LayerRequestID
+	// LayerLogging writes the access log
+	LayerLogging
+	// LayerCORS adds cross-origin headers
+	LayerCORS
+	// LayerAuth authenticates the request
+	LayerAuth
+	// LayerRBAC authorizes the authenticated user
+	LayerRBAC
+
+	layerCount
+)
+
+// Stack composes middleware by layer, so that the order in which they run is
+// fixed by the layers rather than by the order in which they are added:
+// recovery → request ID → logging → CORS → auth → RBAC → handler. Within a
+// layer, middleware run in the order they were added.
+type Stack struct {
+	layers [layerCount][]Middleware
+}
+
+// Use adds middleware to a layer of the stack
+func (s *Stack) Use(layer Layer, middleware ...Middleware) *Stack {
+	s.layers[layer] = append(s.layers[layer], middleware...)
+	return s
+}
+
+// Then wraps handler with the middleware of the stack
+func (s *Stack) Then(handler http.Handler) http.Handler {
+	for layer := layerCount - 1; layer >= 0; layer-- {
+		middleware := s.layers[layer]
+		for i := len(middleware) - 1; i >= 0; i-- {
+			handler = middleware[i](handler)
+		}
+	}
+	return handler
+}
+
+// ThenFunc wraps handler with the middleware of the stack
+func (s *Stack) ThenFunc(handler http.HandlerFunc) http.HandlerFunc {
+	return s.Then(handler).ServeHTTP
+}
@@ -0,0 +1,74 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"com.kong.connect/requestid"
+)
+
+func TestStackOrdersMiddlewareByLayer(t *testing.T) {
+	var order []string
+	trace := func(name string) Middleware {
+		return func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				order = append(order, name)
+				next.ServeHTTP(w, r)
+			})
+		}
+	}
+
+	// Added out of order on purpose
+	stack := &Stack{}
+	stack.Use(LayerRBAC, trace("rbac"))
+	stack.Use(LayerCORS, trace("cors"))
+	stack.Use(LayerAuth, trace("auth"))
+	stack.Use(LayerRecovery, trace("recovery"))
+	stack.Use(LayerLogging, trace("logging"))
+	stack.Use(LayerRequestID, trace("request id"), trace("features"))
+	stack.Use(LayerInstrumentation, trace("tracing"))
+
+	stack.ThenFunc(func(w http.ResponseWriter, r *http.Request) {
+		order = append(order, "handler")
+	}).ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+
+	assert.Equal(t, []string{"tracing", "recovery", "request id", "features", "logging", "cors", "auth", "rbac", "handler"}, order)
+}
+
+func TestStackRecoversPanicsOfTheInnerLayers(t *testing.T) {
+	reporter := &recordingReporter{}
+	var log bytes.Buffer
+	stack := &Stack{}
+	stack.Use(LayerRecovery, ErrorReporting(reporter, slog.Default()))
+	stack.Use(LayerRequestID, RequestID)
+	stack.Use(LayerLogging, AccessLog(AccessLogJSON, slog.Default(), &log))
+	stack.Use(LayerAuth, AuthMiddleware)
+	handler := stack.ThenFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	})
+
+	req := httptest.NewRequest("GET", "/api/v1/services", nil)
+	req.Header.Set("Authorization", "Bearer admin-token")
+	req.Header.Set(requestid.Header, "abc-123")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	assert.Equal(t, "abc-123", rec.Header().Get(requestid.Header))
+	assert.Contains(t, rec.Body.String(), `"request_id":"abc-123"`)
+	require.Len(t, reporter.events, 1)
+	assert.Equal(t, "abc-123", reporter.events[0].RequestID)
+	assert.Equal(t, "admin", reporter.events[0].User)
+
+	var entry map[string]interface{}
+	require.NoError(t, json.Unmarshal(log.Bytes(), &entry))
+	assert.Equal(t, float64(http.StatusInternalServerError), entry["status"])
+	assert.Equal(t, "abc-123", entry["request_id"])
+}
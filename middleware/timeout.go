@@ -0,0 +1,142 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net/http"
+	"regexp"
+	"strconv"
+	"time"
+
+	"com.kong.connect/i18n"
+	"com.kong.connect/problem"
+)
+
+// Headers in which clients send how long they will wait for the response:
+// a duration such as 500ms, or seconds, in X-Request-Timeout, or the gRPC
+// form, such as 500m, in Grpc-Timeout
+const (
+	TimeoutHeader     = "X-Request-Timeout"
+	GRPCTimeoutHeader = "Grpc-Timeout"
+)
+
+// grpcTimeout matches a Grpc-Timeout value: at most 8 digits and a unit
+var grpcTimeout = regexp.MustCompile(`^([0-9]{1,8})([HMSmun])$`)
+
+// grpcTimeoutUnits are the units of Grpc-Timeout values
+var grpcTimeoutUnits = map[string]time.Duration{
+	"H": time.Hour, "M": time.Minute, "S": time.Second,
+	"m": time.Millisecond, "u": time.Microsecond, "n": time.Nanosecond,
+}
+
+// Timeout bounds the time a handler may spend on a request to timeout, or to
+// the shorter deadline the client asks for in TimeoutHeader or
+// GRPCTimeoutHeader, so that no work is spent on responses the client no
+// longer waits for. A timeout of 0 only honors the deadlines of clients. The
+// request context is cancelled at the deadline so in-flight database calls
+// abort, and a 503 problem response replaces whatever the handler would have
+// written afterwards. Handlers run on the request goroutine, so the response
+// is sent once the handler notices the cancellation and returns.
+func Timeout(timeout time.Duration) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			limit := timeout
+			requested, invalid := requestedTimeout(r.Header)
+			if invalid != "" {
+				problem.Error(w, r, http.StatusBadRequest, i18n.Sprintf(i18n.FromContext(r.Context()), "Invalid %s header", invalid))
+				return
+			}
+			if requested > 0 && (limit == 0 || requested < limit) {
+				limit = requested
+			}
+			if limit == 0 {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			ctx, cancel := context.WithTimeout(r.Context(), limit)
+			defer cancel()
+
+			tw := &timeoutWriter{ResponseWriter: w, ctx: ctx}
+			next.ServeHTTP(tw, r.WithContext(ctx))
+
+			if tw.timedOut && !tw.wroteHeader {
+				problem.Error(w, r, http.StatusServiceUnavailable, "Request timed out")
+			}
+		})
+	}
+}
+
+// requestedTimeout returns the timeout the client asks for, preferring
+// TimeoutHeader, or 0 when it asks for none. It also returns the header
+// holding a value that is not a positive timeout.
+func requestedTimeout(header http.Header) (time.Duration, string) {
+	if value := header.Get(TimeoutHeader); value != "" {
+		if seconds, err := strconv.ParseFloat(value, 64); err == nil {
+			// Also rejects NaN and timeouts overflowing a Duration
+			if !(seconds > 0 && seconds < math.MaxInt64/float64(time.Second)) {
+				return 0, TimeoutHeader
+			}
+			return time.Duration(seconds * float64(time.Second)), ""
+		}
+		timeout, err := time.ParseDuration(value)
+		if err != nil || timeout <= 0 {
+			return 0, TimeoutHeader
+		}
+		return timeout, ""
+	}
+	if value := header.Get(GRPCTimeoutHeader); value != "" {
+		match := grpcTimeout.FindStringSubmatch(value)
+		if match == nil {
+			return 0, GRPCTimeoutHeader
+		}
+		amount, _ := strconv.Atoi(match[1])
+		if amount == 0 {
+			return 0, GRPCTimeoutHeader
+		}
+		return time.Duration(amount) * grpcTimeoutUnits[match[2]], ""
+	}
+	return 0, ""
+}
+
+// timeoutWriter discards a handler's response once the deadline has passed,
+// unless the handler had already started writing it
+type timeoutWriter struct {
+	http.ResponseWriter
+	ctx         context.Context
+	wroteHeader bool
+	timedOut    bool
+}
+
+func (tw *timeoutWriter) WriteHeader(status int) {
+	if tw.expired() {
+		return
+	}
+	tw.wroteHeader = true
+	tw.ResponseWriter.WriteHeader(status)
+}
+
+func (tw *timeoutWriter) Write(b []byte) (int, error) {
+	if tw.expired() {
+		return 0, http.ErrHandlerTimeout
+	}
+	tw.wroteHeader = true
+	return tw.ResponseWriter.Write(b)
+}
+
+// expired reports whether writes must be dropped because the deadline passed first
+func (tw *timeoutWriter) expired() bool {
+	if tw.wroteHeader {
+		return false
+	}
+	if errors.Is(tw.ctx.Err(), context.DeadlineExceeded) {
+		tw.timedOut = true
+	}
+	return tw.timedOut
+}
+
+// Unwrap exposes the underlying writer to http.ResponseController
+func (tw *timeoutWriter) Unwrap() http.ResponseWriter {
+	return tw.ResponseWriter
+}
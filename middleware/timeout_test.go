@@ -0,0 +1,100 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTimeoutReturnsServiceUnavailable(t *testing.T) {
+	handler := Timeout(10 * time.Millisecond)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+		http.Error(w, "context cancelled", http.StatusInternalServerError)
+	}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("GET", "/api/v1/services", nil))
+
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	assert.Equal(t, "application/problem+json", rec.Header().Get("Content-Type"))
+	assert.NotContains(t, rec.Body.String(), "context cancelled")
+}
+
+func TestTimeoutPassesFastResponses(t *testing.T) {
+	handler := Timeout(time.Second)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, ok := r.Context().Deadline()
+		assert.True(t, ok)
+		w.WriteHeader(http.StatusCreated)
+	}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("POST", "/api/v1/services", nil))
+
+	assert.Equal(t, http.StatusCreated, rec.Code)
+}
+
+func TestTimeoutHonorsTheDeadlineOfTheClient(t *testing.T) {
+	var remaining time.Duration
+	handler := Timeout(time.Minute)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		remaining = 0
+		if deadline, ok := r.Context().Deadline(); ok {
+			remaining = time.Until(deadline)
+		}
+	}))
+	serve := func(header, value string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", "/api/v1/services", nil)
+		if header != "" {
+			req.Header.Set(header, value)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	for _, c := range []struct {
+		header, value string
+		want          time.Duration
+	}{
+		{"", "", time.Minute},
+		{TimeoutHeader, "2s", 2 * time.Second},
+		{TimeoutHeader, "1.5", 1500 * time.Millisecond},
+		{TimeoutHeader, "1h", time.Minute},
+		{GRPCTimeoutHeader, "250m", 250 * time.Millisecond},
+		{GRPCTimeoutHeader, "3S", 3 * time.Second},
+		{GRPCTimeoutHeader, "99999999H", time.Minute},
+	} {
+		rec := serve(c.header, c.value)
+		assert.Equal(t, http.StatusOK, rec.Code, c.value)
+		assert.InDelta(t, c.want, remaining, float64(100*time.Millisecond), "%s: %s", c.header, c.value)
+	}
+
+	for _, c := range [][2]string{
+		{TimeoutHeader, "soon"}, {TimeoutHeader, "0"}, {TimeoutHeader, "-1s"}, {TimeoutHeader, "NaN"}, {TimeoutHeader, "1e300"},
+		{GRPCTimeoutHeader, "0m"}, {GRPCTimeoutHeader, "100"}, {GRPCTimeoutHeader, "123456789m"}, {GRPCTimeoutHeader, "1s"},
+	} {
+		rec := serve(c[0], c[1])
+		assert.Equal(t, http.StatusBadRequest, rec.Code, "%s: %s", c[0], c[1])
+		assert.Contains(t, rec.Body.String(), "Invalid "+c[0]+" header")
+	}
+}
+
+func TestTimeoutWithoutLimitOnlyHonorsClients(t *testing.T) {
+	handler := Timeout(0)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+		http.Error(w, "context cancelled", http.StatusInternalServerError)
+	}))
+	req := httptest.NewRequest("GET", "/api/v1/services", nil)
+	req.Header.Set(TimeoutHeader, "10ms")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+
+	handler = Timeout(0)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, ok := r.Context().Deadline()
+		assert.False(t, ok)
+	}))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/api/v1/services", nil))
+}
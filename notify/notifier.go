@@ -0,0 +1,244 @@
+// Package notify posts a message to Slack and Microsoft Teams channels when a
+// service is created, deprecated or deleted. Channels are incoming webhooks
+// configured for the deployment, each with the notifications it receives.
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"com.kong.connect/domain"
+	"com.kong.connect/logging"
+	"com.kong.connect/tenant"
+)
+
+// Channel kinds
+const (
+	KindSlack = "slack"
+	KindTeams = "teams"
+)
+
+// Notifications a channel can receive
+const (
+	ServiceCreated    = "created"
+	ServiceDeprecated = "deprecated"
+	ServiceDeleted    = "deleted"
+)
+
+// Notifications lists every notification, which channels receive by default
+var Notifications = []string{ServiceCreated, ServiceDeprecated, ServiceDeleted}
+
+// postTimeout bounds a message post
+const postTimeout = 10 * time.Second
+
+// Channel is an incoming webhook of Slack or Teams receiving Notifications
+type Channel struct {
+	Kind          string
+	URL           string
+	Notifications []string
+}
+
+// ParseChannels parses a comma separated list of channels of the form
+// kind[:notification+notification]=url, e.g.
+// "slack=https://hooks.slack.com/services/T0/B0/x,teams:deprecated+deleted=https://example.webhook.office.com/...".
+// An empty spec configures no channel.
+func ParseChannels(spec string) ([]Channel, error) {
+	var channels []Channel
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		target, rawURL, ok := strings.Cut(entry, "=")
+		if !ok {
+			return nil, fmt.Errorf("channel %q: expected kind=url", entry)
+		}
+
+		kind, filter, filtered := strings.Cut(strings.TrimSpace(target), ":")
+		channel := Channel{Kind: strings.ToLower(kind), URL: strings.TrimSpace(rawURL), Notifications: Notifications}
+		if channel.Kind != KindSlack && channel.Kind != KindTeams {
+			return nil, fmt.Errorf("channel %q: unknown kind %q, expected slack or teams", target, kind)
+		}
+		if u, err := url.Parse(channel.URL); err != nil || u.Scheme != "https" || u.Host == "" {
+			return nil, fmt.Errorf("channel %q: the URL must be an https URL", target)
+		}
+		if filtered {
+			channel.Notifications = nil
+			for _, notification := range strings.Split(filter, "+") {
+				notification = strings.ToLower(strings.TrimSpace(notification))
+				if !slices.Contains(Notifications, notification) {
+					return nil, fmt.Errorf("channel %q: unknown notification %q, expected one of %s", target, notification, strings.Join(Notifications, ", "))
+				}
+				channel.Notifications = append(channel.Notifications, notification)
+			}
+		}
+		channels = append(channels, channel)
+	}
+	return channels, nil
+}
+
+// Notifier posts the notifications of the change events published to it
+type Notifier struct {
+	channels []Channel
+	client   *http.Client
+	logger   *slog.Logger
+}
+
+// NewNotifier creates a notifier posting to channels; a nil client uses
+// http.DefaultClient
+func NewNotifier(channels []Channel, client *http.Client, logger *slog.Logger) *Notifier {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &Notifier{channels: channels, client: client, logger: logging.Component(logger, "notify")}
+}
+
+// Publish posts the notification of event, if any, to the channels
+// receiving it. Posts are made in the background and not retried; failures
+// are logged.
+func (n *Notifier) Publish(event domain.ChangeEvent) {
+	notification := Notification(event)
+	if notification == "" {
+		return
+	}
+	for _, channel := range n.channels {
+		if !slices.Contains(channel.Notifications, notification) {
+			continue
+		}
+		go func(channel Channel) {
+			if err := n.post(channel, notification, event); err != nil {
+				n.logger.Error("failed to post notification", "kind", channel.Kind, "notification", notification,
+					"service_id", event.ServiceID, "error", err)
+			}
+		}(channel)
+	}
+}
+
+// Notification returns the notification of a change event, or "" when it
+// notifies nothing. An update notifies the deprecation of a service that
+// was not deprecated before.
+func Notification(event domain.ChangeEvent) string {
+	switch event.Type {
+	case domain.EventServiceCreated:
+		return ServiceCreated
+	case domain.EventServiceDeleted:
+		return ServiceDeleted
+	case domain.EventServiceUpdated:
+		if event.Service != nil && event.Previous != nil &&
+			event.Service.Status == domain.StatusDeprecated && event.Previous.Status != domain.StatusDeprecated {
+			return ServiceDeprecated
+		}
+	}
+	return ""
+}
+
+// post sends the message of a notification to a channel
+func (n *Notifier) post(channel Channel, notification string, event domain.ChangeEvent) error {
+	body, err := json.Marshal(message(channel.Kind, notification, event))
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), postTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, channel.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		answer, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("status %d: %s", resp.StatusCode, strings.TrimSpace(string(answer)))
+	}
+	return nil
+}
+
+// message returns the payload of a notification for a kind of channel: a
+// Slack message with a Block Kit section, or a Teams message card
+func message(kind, notification string, event domain.ChangeEvent) interface{} {
+	title, text := summary(notification, event)
+	if kind == KindTeams {
+		return map[string]interface{}{
+			"@type":      "MessageCard",
+			"@context":   "https://schema.org/extensions",
+			"summary":    title,
+			"themeColor": themeColors[notification],
+			"title":      title,
+			"text":       text,
+		}
+	}
+	return map[string]interface{}{
+		// Shown in notifications and by clients without blocks
+		"text": title,
+		"blocks": []interface{}{
+			map[string]interface{}{
+				"type": "section",
+				"text": map[string]string{"type": "mrkdwn", "text": "*" + title + "*\n" + text},
+			},
+		},
+	}
+}
+
+// themeColors color the Teams cards of each notification
+var themeColors = map[string]string{
+	ServiceCreated:    "2EB67D",
+	ServiceDeprecated: "ECB22E",
+	ServiceDeleted:    "E01E5A",
+}
+
+// summary returns the title and the details of a notification
+func summary(notification string, event domain.ChangeEvent) (string, string) {
+	service := event.Service
+	if service == nil {
+		service = &domain.Service{ID: event.ServiceID}
+	}
+
+	var title string
+	switch notification {
+	case ServiceCreated:
+		title = fmt.Sprintf("Service %s was created", service.Name)
+	case ServiceDeprecated:
+		title = fmt.Sprintf("Service %s was deprecated", service.Name)
+	case ServiceDeleted:
+		title = fmt.Sprintf("Service %s was deleted", service.Name)
+	}
+
+	details := []string{fmt.Sprintf("ID: %d", service.ID)}
+	if service.Owner != "" {
+		details = append(details, "Owner: "+service.Owner)
+	}
+	if len(service.Tags) > 0 {
+		details = append(details, "Tags: "+strings.Join(service.Tags, ", "))
+	}
+	if event.OrgID != tenant.DefaultOrg {
+		details = append(details, fmt.Sprintf("Organization: %d", event.OrgID))
+	}
+	if service.Description != "" && notification != ServiceDeleted {
+		details = append(details, truncate(service.Description, 300))
+	}
+	return title, strings.Join(details, "\n")
+}
+
+// truncate shortens s to at most max runes
+func truncate(s string, max int) string {
+	runes := []rune(s)
+	if len(runes) <= max {
+		return s
+	}
+	return string(runes[:max-1]) + "…"
+}
@@ -0,0 +1,102 @@
+package notify
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"com.kong.connect/domain"
+)
+
+func TestParseChannels(t *testing.T) {
+	channels, err := ParseChannels(" slack=https://hooks.slack.com/services/T0/B0/x?a=b , Teams:deprecated+DELETED=https://example.webhook.office.com/hook,")
+	require.NoError(t, err)
+	assert.Equal(t, []Channel{
+		{Kind: KindSlack, URL: "https://hooks.slack.com/services/T0/B0/x?a=b", Notifications: Notifications},
+		{Kind: KindTeams, URL: "https://example.webhook.office.com/hook", Notifications: []string{ServiceDeprecated, ServiceDeleted}},
+	}, channels)
+
+	channels, err = ParseChannels("")
+	require.NoError(t, err)
+	assert.Empty(t, channels)
+
+	for _, spec := range []string{
+		"slack",
+		"email=https://example.com",
+		"slack=http://hooks.slack.com/x",
+		"slack:updated=https://hooks.slack.com/x",
+	} {
+		_, err := ParseChannels(spec)
+		assert.Error(t, err, spec)
+	}
+}
+
+func TestNotificationOfChangeEvents(t *testing.T) {
+	active := &domain.Service{Status: domain.StatusActive}
+	deprecated := &domain.Service{Status: domain.StatusDeprecated}
+	for _, test := range []struct {
+		event domain.ChangeEvent
+		want  string
+	}{
+		{domain.ChangeEvent{Type: domain.EventServiceCreated, Service: active}, ServiceCreated},
+		{domain.ChangeEvent{Type: domain.EventServiceDeleted, Service: active}, ServiceDeleted},
+		{domain.ChangeEvent{Type: domain.EventServiceUpdated, Service: deprecated, Previous: active}, ServiceDeprecated},
+		{domain.ChangeEvent{Type: domain.EventServiceUpdated, Service: deprecated, Previous: deprecated}, ""},
+		{domain.ChangeEvent{Type: domain.EventServiceUpdated, Service: active, Previous: deprecated}, ""},
+		{domain.ChangeEvent{Type: domain.EventVersionCreated, Service: active}, ""},
+	} {
+		assert.Equal(t, test.want, Notification(test.event), test.event.Type)
+	}
+}
+
+func TestChannelsReceiveTheirFormat(t *testing.T) {
+	received := make(chan map[string]interface{}, 4)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		var payload map[string]interface{}
+		require.NoError(t, json.Unmarshal(body, &payload))
+		payload["path"] = r.URL.Path
+		received <- payload
+	}))
+	defer server.Close()
+
+	notifier := NewNotifier([]Channel{
+		{Kind: KindSlack, URL: server.URL + "/slack", Notifications: Notifications},
+		{Kind: KindTeams, URL: server.URL + "/teams", Notifications: []string{ServiceDeleted}},
+	}, server.Client(), nil)
+	service := &domain.Service{ID: 7, Name: "Payments", Owner: "payments-team", Tags: []string{"public"}, Description: "Card payments"}
+
+	notifier.Publish(domain.ChangeEvent{Type: domain.EventServiceCreated, OrgID: 1, ServiceID: 7, Service: service})
+	select {
+	case payload := <-received:
+		assert.Equal(t, "/slack", payload["path"])
+		assert.Equal(t, "Service Payments was created", payload["text"])
+		blocks := payload["blocks"].([]interface{})
+		text := blocks[0].(map[string]interface{})["text"].(map[string]interface{})["text"]
+		assert.Equal(t, "*Service Payments was created*\nID: 7\nOwner: payments-team\nTags: public\nCard payments", text)
+	case <-time.After(5 * time.Second):
+		t.Fatal("no Slack message")
+	}
+
+	notifier.Publish(domain.ChangeEvent{Type: domain.EventServiceDeleted, OrgID: 2, ServiceID: 7, Service: service})
+	byPath := map[string]map[string]interface{}{}
+	for range 2 {
+		select {
+		case payload := <-received:
+			byPath[payload["path"].(string)] = payload
+		case <-time.After(5 * time.Second):
+			t.Fatal("missing message")
+		}
+	}
+	teams := byPath["/teams"]
+	assert.Equal(t, "MessageCard", teams["@type"])
+	assert.Equal(t, "Service Payments was deleted", teams["title"])
+	assert.Equal(t, "ID: 7\nOwner: payments-team\nTags: public\nOrganization: 2", teams["text"])
+	assert.Contains(t, byPath, "/slack")
+}
@@ -0,0 +1,441 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi2"
+	"github.com/getkin/kin-openapi/openapi2conv"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/oasdiff/yaml"
+)
+
+// Formats of the API definitions imported as services
+const (
+	FormatOpenAPI = "openapi"
+	FormatSwagger = "swagger"
+	FormatPostman = "postman"
+)
+
+// postmanSchema prefixes the schema URL of Postman collections
+const postmanSchema = "https://schema.getpostman.com/json/collection/v2."
+
+// defaultPostmanVersion is the version of collections without one
+const defaultPostmanVersion = "1.0.0"
+
+// postmanVariable matches the {{variables}} of Postman URLs
+var postmanVariable = regexp.MustCompile(`^\{\{([^{}]+)\}\}$`)
+
+// Definition is an API definition imported as a service: the metadata of
+// the service and its first version, and the OpenAPI 3 spec of the version
+type Definition struct {
+	Format      string
+	Name        string
+	Description string
+	Version     string
+	Tags        []string
+	// Spec is the OpenAPI 3 spec of the version: the document itself when it
+	// is one, the spec converted from Swagger or generated from a Postman
+	// collection, in JSON, otherwise
+	Spec []byte
+	Doc  *openapi3.T
+}
+
+// ParseDefinition recognizes and parses an OpenAPI 3 spec or a Swagger 2.0
+// file, in JSON or YAML, or a Postman collection v2.0 or v2.1. Swagger files
+// are converted to OpenAPI 3 and the requests of collections described by
+// one; either is then validated like an uploaded spec. The tags of a spec,
+// or the top-level folders of a collection, are the tags of the service.
+func ParseDefinition(content []byte) (*Definition, error) {
+	converted, err := yaml.YAMLToJSON(content)
+	if err != nil {
+		return nil, fmt.Errorf("not a JSON or YAML document: %v", err)
+	}
+	var kind struct {
+		OpenAPI interface{} `json:"openapi"`
+		Swagger interface{} `json:"swagger"`
+		Info    struct {
+			Schema interface{} `json:"schema"`
+		} `json:"info"`
+	}
+	if err := json.Unmarshal(converted, &kind); err != nil {
+		return nil, fmt.Errorf("not an OpenAPI, Swagger or Postman document")
+	}
+
+	switch {
+	case kind.OpenAPI != nil:
+		doc, err := Parse(content)
+		if err != nil {
+			return nil, err
+		}
+		return describeDefinition(FormatOpenAPI, content, doc), nil
+	case kind.Swagger != nil:
+		if kind.Swagger != "2.0" {
+			return nil, fmt.Errorf("swagger must be 2.0, got %v", kind.Swagger)
+		}
+		var swagger openapi2.T
+		if err := json.Unmarshal(converted, &swagger); err != nil {
+			return nil, fmt.Errorf("not a Swagger document: %v", err)
+		}
+		doc, err := openapi2conv.ToV3(&swagger)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert Swagger to OpenAPI 3: %v", err)
+		}
+		return reparse(FormatSwagger, doc)
+	case strings.HasPrefix(fmt.Sprint(kind.Info.Schema), postmanSchema):
+		var collection postmanCollection
+		if err := json.Unmarshal(converted, &collection); err != nil {
+			return nil, fmt.Errorf("not a Postman collection: %v", err)
+		}
+		definition, err := reparse(FormatPostman, collection.spec())
+		if err != nil {
+			return nil, err
+		}
+		// The description and tags of the collection are richer than the spec's
+		definition.Description = collection.Info.Description.String()
+		definition.Tags = collection.folders()
+		return definition, nil
+	default:
+		return nil, fmt.Errorf("not an OpenAPI, Swagger or Postman v2 document")
+	}
+}
+
+// reparse validates a converted or generated spec in its JSON form, the
+// one stored
+func reparse(format string, doc *openapi3.T) (*Definition, error) {
+	spec, err := json.Marshal(doc)
+	if err != nil {
+		return nil, err
+	}
+	parsed, err := Parse(spec)
+	if err != nil {
+		return nil, err
+	}
+	return describeDefinition(format, spec, parsed), nil
+}
+
+func describeDefinition(format string, spec []byte, doc *openapi3.T) *Definition {
+	definition := &Definition{Format: format, Spec: spec, Doc: doc, Tags: []string{}}
+	if doc.Info != nil {
+		definition.Name = doc.Info.Title
+		definition.Description = doc.Info.Description
+		definition.Version = doc.Info.Version
+	}
+	for _, tag := range doc.Tags {
+		definition.Tags = append(definition.Tags, tag.Name)
+	}
+	return definition
+}
+
+// postmanCollection is the part of a Postman collection v2 describing its API
+type postmanCollection struct {
+	Info struct {
+		Name        string      `json:"name"`
+		Description postmanText `json:"description"`
+		Version     postmanText `json:"version"`
+	} `json:"info"`
+	Item []postmanItem `json:"item"`
+}
+
+// postmanItem is a folder, with items, or a request
+type postmanItem struct {
+	Name     string            `json:"name"`
+	Item     []postmanItem     `json:"item"`
+	Request  *postmanRequest   `json:"request"`
+	Response []postmanResponse `json:"response"`
+}
+
+type postmanRequest struct {
+	Method      string          `json:"method"`
+	URL         postmanURL      `json:"url"`
+	Header      []postmanHeader `json:"header"`
+	Body        *postmanBody    `json:"body"`
+	Description postmanText     `json:"description"`
+}
+
+type postmanHeader struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+type postmanBody struct {
+	Mode    string `json:"mode"`
+	Options struct {
+		Raw struct {
+			Language string `json:"language"`
+		} `json:"raw"`
+	} `json:"options"`
+}
+
+type postmanResponse struct {
+	Name string `json:"name"`
+	Code int    `json:"code"`
+}
+
+// postmanURL is a URL given as a string or as an object
+type postmanURL struct {
+	Raw   string
+	Path  []string
+	Query []struct {
+		Key         string      `json:"key"`
+		Disabled    bool        `json:"disabled"`
+		Description postmanText `json:"description"`
+	}
+	Variable []struct {
+		Key         string      `json:"key"`
+		Description postmanText `json:"description"`
+	}
+}
+
+func (u *postmanURL) UnmarshalJSON(data []byte) error {
+	if err := json.Unmarshal(data, &u.Raw); err == nil {
+		return nil
+	}
+	var object struct {
+		Raw      string            `json:"raw"`
+		Path     []json.RawMessage `json:"path"`
+		Query    json.RawMessage   `json:"query"`
+		Variable json.RawMessage   `json:"variable"`
+	}
+	if err := json.Unmarshal(data, &object); err != nil {
+		return err
+	}
+	u.Raw = object.Raw
+	// Path segments are strings, or objects in v2.0
+	for _, segment := range object.Path {
+		var value string
+		if err := json.Unmarshal(segment, &value); err != nil {
+			var object struct {
+				Value string `json:"value"`
+			}
+			if err := json.Unmarshal(segment, &object); err != nil {
+				return err
+			}
+			value = object.Value
+		}
+		u.Path = append(u.Path, value)
+	}
+	if len(object.Query) > 0 {
+		if err := json.Unmarshal(object.Query, &u.Query); err != nil {
+			return err
+		}
+	}
+	if len(object.Variable) > 0 {
+		if err := json.Unmarshal(object.Variable, &u.Variable); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// path returns the path of the URL as an OpenAPI path template
+func (u postmanURL) path() string {
+	segments := u.Path
+	if segments == nil {
+		raw, _, _ := strings.Cut(u.Raw, "?")
+		raw, _, _ = strings.Cut(raw, "#")
+		if _, rest, ok := strings.Cut(raw, "://"); ok {
+			raw = rest
+		}
+		// The first segment is the host, or a variable holding the base URL
+		segments = strings.Split(raw, "/")[1:]
+	}
+	var path strings.Builder
+	for _, segment := range segments {
+		if segment == "" {
+			continue
+		}
+		if name, ok := strings.CutPrefix(segment, ":"); ok && name != "" {
+			segment = "{" + name + "}"
+		} else if match := postmanVariable.FindStringSubmatch(segment); match != nil {
+			segment = "{" + strings.TrimSpace(match[1]) + "}"
+		}
+		path.WriteString("/" + segment)
+	}
+	if path.Len() == 0 {
+		return "/"
+	}
+	return path.String()
+}
+
+// postmanText is a description or version, given as a string or as an
+// object
+type postmanText string
+
+func (t *postmanText) UnmarshalJSON(data []byte) error {
+	var value string
+	if err := json.Unmarshal(data, &value); err == nil {
+		*t = postmanText(value)
+		return nil
+	}
+	var object struct {
+		Content    string      `json:"content"`
+		Major      json.Number `json:"major"`
+		Minor      json.Number `json:"minor"`
+		Patch      json.Number `json:"patch"`
+		Identifier string      `json:"identifier"`
+	}
+	if err := json.Unmarshal(data, &object); err != nil {
+		return err
+	}
+	switch {
+	case object.Content != "":
+		*t = postmanText(object.Content)
+	case object.Major != "":
+		version := string(object.Major) + "." + string(object.Minor) + "." + string(object.Patch)
+		if object.Identifier != "" {
+			version += "-" + object.Identifier
+		}
+		*t = postmanText(version)
+	}
+	return nil
+}
+
+func (t postmanText) String() string {
+	return strings.TrimSpace(string(t))
+}
+
+// folders returns the names of the top-level folders
+func (c *postmanCollection) folders() []string {
+	folders := []string{}
+	for _, item := range c.Item {
+		if item.Request == nil && strings.TrimSpace(item.Name) != "" {
+			folders = append(folders, strings.TrimSpace(item.Name))
+		}
+	}
+	return folders
+}
+
+// spec describes the requests of the collection as an OpenAPI 3 spec; the
+// first request of a method and path describes their operation
+func (c *postmanCollection) spec() *openapi3.T {
+	version := c.Info.Version.String()
+	if version == "" {
+		version = defaultPostmanVersion
+	}
+	doc := &openapi3.T{
+		OpenAPI: "3.0.3",
+		Info:    &openapi3.Info{Title: c.Info.Name, Description: c.Info.Description.String(), Version: version},
+		Paths:   openapi3.NewPaths(),
+	}
+	var walk func(items []postmanItem, folder string)
+	walk = func(items []postmanItem, folder string) {
+		for _, item := range items {
+			if item.Request == nil {
+				// Nested folders are tagged with their top-level folder
+				if folder == "" {
+					walk(item.Item, strings.TrimSpace(item.Name))
+				} else {
+					walk(item.Item, folder)
+				}
+				continue
+			}
+			addOperation(doc, item, folder)
+		}
+	}
+	walk(c.Item, "")
+	for _, folder := range c.folders() {
+		doc.Tags = append(doc.Tags, &openapi3.Tag{Name: folder})
+	}
+	return doc
+}
+
+// addOperation describes a request of a collection as an operation
+func addOperation(doc *openapi3.T, item postmanItem, folder string) {
+	request := item.Request
+	method := strings.ToUpper(strings.TrimSpace(request.Method))
+	if method == "" {
+		method = http.MethodGet
+	}
+	path := request.URL.path()
+	pathItem := doc.Paths.Value(path)
+	if pathItem == nil {
+		pathItem = &openapi3.PathItem{}
+		doc.Paths.Set(path, pathItem)
+	}
+	if pathItem.GetOperation(method) != nil {
+		return
+	}
+
+	operation := openapi3.NewOperation()
+	operation.Summary = strings.TrimSpace(item.Name)
+	operation.Description = request.Description.String()
+	if folder != "" {
+		operation.Tags = []string{folder}
+	}
+	descriptions := map[string]string{}
+	for _, variable := range request.URL.Variable {
+		descriptions[variable.Key] = variable.Description.String()
+	}
+	for _, name := range pathParameter.FindAllString(path, -1) {
+		name = strings.Trim(name, "{}")
+		parameter := openapi3.NewPathParameter(name).WithSchema(openapi3.NewStringSchema())
+		parameter.Description = descriptions[name]
+		operation.AddParameter(parameter)
+	}
+	seen := map[string]bool{}
+	for _, query := range request.URL.Query {
+		if query.Disabled || query.Key == "" || seen[query.Key] {
+			continue
+		}
+		seen[query.Key] = true
+		parameter := openapi3.NewQueryParameter(query.Key).WithSchema(openapi3.NewStringSchema())
+		parameter.Description = query.Description.String()
+		operation.AddParameter(parameter)
+	}
+	if contentType := request.contentType(); contentType != "" {
+		operation.RequestBody = &openapi3.RequestBodyRef{Value: openapi3.NewRequestBody().WithContent(
+			openapi3.Content{contentType: openapi3.NewMediaType()})}
+	}
+
+	// The saved responses of the request describe its responses; requests
+	// without any keep the default response
+	operation.Responses = openapi3.NewResponses()
+	for _, response := range item.Response {
+		if response.Code < 100 || response.Code > 599 {
+			continue
+		}
+		description := strings.TrimSpace(response.Name)
+		if description == "" {
+			description = http.StatusText(response.Code)
+		}
+		operation.Responses.Delete("default")
+		operation.Responses.Set(strconv.Itoa(response.Code), &openapi3.ResponseRef{Value: openapi3.NewResponse().WithDescription(description)})
+	}
+	pathItem.SetOperation(method, operation)
+}
+
+// contentType returns the content type of the body of a request, or "" when
+// it has none
+func (r *postmanRequest) contentType() string {
+	if r.Body == nil || r.Body.Mode == "" {
+		return ""
+	}
+	for _, header := range r.Header {
+		if strings.EqualFold(header.Key, "Content-Type") && header.Value != "" {
+			contentType, _, _ := strings.Cut(header.Value, ";")
+			return strings.TrimSpace(contentType)
+		}
+	}
+	switch r.Body.Mode {
+	case "urlencoded":
+		return "application/x-www-form-urlencoded"
+	case "formdata":
+		return "multipart/form-data"
+	case "graphql":
+		return "application/json"
+	case "raw":
+		switch r.Body.Options.Raw.Language {
+		case "json":
+			return "application/json"
+		case "xml":
+			return "application/xml"
+		}
+		return "text/plain"
+	}
+	return "application/octet-stream"
+}
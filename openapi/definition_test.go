@@ -0,0 +1,175 @@
+package openapi
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const swaggerPetstore = `
+swagger: "2.0"
+info:
+  title: Swagger Petstore
+  description: Pets, the old way
+  version: 1.0.5
+host: pets.example.com
+basePath: /v1
+tags:
+  - name: pet
+paths:
+  /pets/{id}:
+    get:
+      tags: [pet]
+      parameters:
+        - name: id
+          in: path
+          required: true
+          type: string
+      responses:
+        "200": {description: The pet}
+`
+
+const ordersCollection = `{
+  "info": {
+    "_postman_id": "6d1f",
+    "name": "Orders API",
+    "description": {"content": "Places and tracks orders", "type": "text/markdown"},
+    "version": {"major": 2, "minor": 1, "patch": 0},
+    "schema": "https://schema.getpostman.com/json/collection/v2.1.0/collection.json"
+  },
+  "item": [
+    {
+      "name": "Order Management",
+      "item": [
+        {
+          "name": "Get order",
+          "request": {
+            "method": "GET",
+            "url": {
+              "raw": "{{baseUrl}}/orders/:orderId?expand=items",
+              "host": ["{{baseUrl}}"],
+              "path": ["orders", ":orderId"],
+              "query": [{"key": "expand", "value": "items"}, {"key": "debug", "disabled": true}],
+              "variable": [{"key": "orderId", "description": "The order"}]
+            }
+          },
+          "response": [{"name": "Found", "code": 200}, {"name": "Missing", "code": 404}]
+        },
+        {
+          "name": "Nested",
+          "item": [
+            {
+              "name": "Cancel order",
+              "request": {"method": "DELETE", "url": "https://api.example.com/orders/{{orderId}}"}
+            }
+          ]
+        }
+      ]
+    },
+    {
+      "name": "Place order",
+      "request": {
+        "method": "POST",
+        "url": "https://api.example.com/orders",
+        "body": {"mode": "raw", "raw": "{}", "options": {"raw": {"language": "json"}}}
+      }
+    }
+  ]
+}`
+
+func TestParseDefinitionOfOpenAPI3(t *testing.T) {
+	definition, err := ParseDefinition([]byte(petstore))
+	require.NoError(t, err)
+	assert.Equal(t, FormatOpenAPI, definition.Format)
+	assert.Equal(t, "Petstore", definition.Name)
+	assert.Equal(t, "1.2.0", definition.Version)
+	assert.Equal(t, []string{}, definition.Tags)
+	assert.Equal(t, petstore, string(definition.Spec), "stored as written")
+}
+
+func TestParseDefinitionConvertsSwagger(t *testing.T) {
+	definition, err := ParseDefinition([]byte(swaggerPetstore))
+	require.NoError(t, err)
+	assert.Equal(t, FormatSwagger, definition.Format)
+	assert.Equal(t, "Swagger Petstore", definition.Name)
+	assert.Equal(t, "Pets, the old way", definition.Description)
+	assert.Equal(t, "1.0.5", definition.Version)
+	assert.Equal(t, []string{"pet"}, definition.Tags)
+	assert.Equal(t, ContentTypeJSON, ContentType(definition.Spec))
+
+	doc, err := Parse(definition.Spec)
+	require.NoError(t, err)
+	assert.Equal(t, "3.0.3", doc.OpenAPI)
+	require.NotNil(t, doc.Paths.Value("/pets/{id}"))
+	assert.Equal(t, "https://pets.example.com/v1", doc.Servers[0].URL)
+}
+
+func TestParseDefinitionDescribesPostmanCollections(t *testing.T) {
+	definition, err := ParseDefinition([]byte(ordersCollection))
+	require.NoError(t, err)
+	assert.Equal(t, FormatPostman, definition.Format)
+	assert.Equal(t, "Orders API", definition.Name)
+	assert.Equal(t, "Places and tracks orders", definition.Description)
+	assert.Equal(t, "2.1.0", definition.Version)
+	assert.Equal(t, []string{"Order Management"}, definition.Tags)
+
+	doc, err := Parse(definition.Spec)
+	require.NoError(t, err)
+	assert.Equal(t, 2, doc.Paths.Len())
+
+	get := doc.Paths.Value("/orders/{orderId}").Get
+	require.NotNil(t, get)
+	assert.Equal(t, "Get order", get.Summary)
+	assert.Equal(t, []string{"Order Management"}, get.Tags)
+	require.Len(t, get.Parameters, 2, "disabled query parameters are left out")
+	assert.Equal(t, "path", get.Parameters[0].Value.In)
+	assert.Equal(t, "The order", get.Parameters[0].Value.Description)
+	assert.Equal(t, "expand", get.Parameters[1].Value.Name)
+	assert.NotNil(t, get.Responses.Value("404"))
+	assert.Nil(t, get.Responses.Default())
+
+	cancel := doc.Paths.Value("/orders/{orderId}").Delete
+	require.NotNil(t, cancel, "{{variables}} are path parameters")
+	assert.Equal(t, []string{"Order Management"}, cancel.Tags, "nested folders are tagged with their top-level folder")
+	assert.NotNil(t, cancel.Responses.Default())
+
+	place := doc.Paths.Value("/orders").Post
+	require.NotNil(t, place)
+	assert.Empty(t, place.Tags)
+	assert.NotNil(t, place.RequestBody.Value.Content.Get("application/json"))
+}
+
+func TestParseDefinitionDefaultsPostmanVersion(t *testing.T) {
+	collection := map[string]interface{}{
+		"info": map[string]interface{}{
+			"name":        "Bare",
+			"description": "Only a name",
+			"schema":      "https://schema.getpostman.com/json/collection/v2.0.0/collection.json",
+		},
+		"item": []interface{}{},
+	}
+	content, err := json.Marshal(collection)
+	require.NoError(t, err)
+
+	definition, err := ParseDefinition(content)
+	require.NoError(t, err)
+	assert.Equal(t, "1.0.0", definition.Version)
+	assert.Equal(t, "Only a name", definition.Description)
+	assert.Equal(t, []string{}, definition.Tags)
+}
+
+func TestParseDefinitionRefusesOtherDocuments(t *testing.T) {
+	for name, content := range map[string]string{
+		"not a document":    "just text",
+		"swagger 1.2":       `{"swagger": "1.2", "info": {"title": "Old", "version": "1"}}`,
+		"postman v1":        `{"id": "1", "name": "Old", "requests": []}`,
+		"unknown schema":    `{"info": {"name": "X", "schema": "https://example.com/schema.json"}, "item": []}`,
+		"invalid openapi":   `{"openapi": "3.0.3", "info": {"title": "No version"}, "paths": {}}`,
+		"unterminated yaml": "openapi: 3.0.3\ninfo: [",
+	} {
+		_, err := ParseDefinition([]byte(content))
+		assert.Error(t, err, name)
+	}
+}
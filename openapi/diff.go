@@ -0,0 +1,248 @@
+package openapi
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+
+	"com.kong.connect/domain"
+)
+
+// pathParameter matches the parameters of a path template, whose names do
+// not tell endpoints apart: /pets/{id} and /pets/{petId} are the same
+var pathParameter = regexp.MustCompile(`\{[^}]*\}`)
+
+// endpoint is an operation of a spec
+type endpoint struct {
+	method string
+	path   string
+	item   *openapi3.PathItem
+	op     *openapi3.Operation
+}
+
+// Compare lists the operations added to, removed from and changed between
+// two specs, ordered by path and method. Removing an operation is breaking,
+// as is any change that makes a request valid for from invalid for to or
+// drops a successful response clients of from may expect.
+func Compare(from, to *openapi3.T) domain.SpecComparison {
+	comparison := domain.SpecComparison{
+		Added:   []domain.EndpointChanges{},
+		Removed: []domain.EndpointChanges{},
+		Changed: []domain.EndpointChanges{},
+	}
+	before, after := endpoints(from), endpoints(to)
+
+	keys := make([]string, 0, len(before)+len(after))
+	for key := range before {
+		keys = append(keys, key)
+	}
+	for key := range after {
+		if _, ok := before[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		b, inBefore := before[key]
+		a, inAfter := after[key]
+		switch {
+		case !inBefore:
+			comparison.Added = append(comparison.Added, domain.EndpointChanges{Method: a.method, Path: a.path})
+		case !inAfter:
+			comparison.Removed = append(comparison.Removed, domain.EndpointChanges{Method: b.method, Path: b.path, Breaking: true})
+			comparison.Breaking = true
+		default:
+			changes := compareOperations(b, a)
+			if len(changes) == 0 {
+				continue
+			}
+			endpoint := domain.EndpointChanges{Method: a.method, Path: a.path, Changes: changes}
+			endpoint.Breaking = slices.ContainsFunc(changes, func(c domain.SpecChange) bool { return c.Breaking })
+			comparison.Breaking = comparison.Breaking || endpoint.Breaking
+			comparison.Changed = append(comparison.Changed, endpoint)
+		}
+	}
+	return comparison
+}
+
+// endpoints returns the operations of a spec keyed by normalized path, then
+// method in the order of methods
+func endpoints(doc *openapi3.T) map[string]endpoint {
+	result := make(map[string]endpoint)
+	if doc.Paths == nil {
+		return result
+	}
+	for path, item := range doc.Paths.Map() {
+		normalized := pathParameter.ReplaceAllString(path, "{}")
+		for i, method := range methods {
+			if op := item.GetOperation(method); op != nil {
+				key := fmt.Sprintf("%s %d", normalized, i)
+				result[key] = endpoint{method: method, path: path, item: item, op: op}
+			}
+		}
+	}
+	return result
+}
+
+// compareOperations lists the changes between two versions of an operation
+func compareOperations(before, after endpoint) []domain.SpecChange {
+	var changes []domain.SpecChange
+	change := func(breaking bool, format string, args ...interface{}) {
+		changes = append(changes, domain.SpecChange{Description: fmt.Sprintf(format, args...), Breaking: breaking})
+	}
+
+	if !before.op.Deprecated && after.op.Deprecated {
+		change(false, "operation deprecated")
+	}
+
+	// Path parameters are part of the path, compared above
+	oldParams, newParams := parameters(before), parameters(after)
+	for _, key := range sortedKeys(oldParams) {
+		old := oldParams[key]
+		current, ok := newParams[key]
+		if !ok {
+			change(true, "%s parameter %s removed", old.In, old.Name)
+			continue
+		}
+		if !old.Required && current.Required {
+			change(true, "%s parameter %s became required", old.In, old.Name)
+		}
+		if old.Required && !current.Required {
+			change(false, "%s parameter %s became optional", old.In, old.Name)
+		}
+		if oldType, newType := schemaType(old.Schema), schemaType(current.Schema); oldType != "" && newType != "" && oldType != newType {
+			change(true, "%s parameter %s type changed from %s to %s", old.In, old.Name, oldType, newType)
+		}
+	}
+	for _, key := range sortedKeys(newParams) {
+		if _, ok := oldParams[key]; ok {
+			continue
+		}
+		if current := newParams[key]; current.Required {
+			change(true, "required %s parameter %s added", current.In, current.Name)
+		} else {
+			change(false, "optional %s parameter %s added", current.In, current.Name)
+		}
+	}
+
+	oldBody, newBody := requestBody(before.op), requestBody(after.op)
+	switch {
+	case oldBody == nil && newBody != nil && newBody.Required:
+		change(true, "required request body added")
+	case oldBody == nil && newBody != nil:
+		change(false, "optional request body added")
+	case oldBody != nil && newBody == nil:
+		change(true, "request body removed")
+	case oldBody != nil && newBody != nil:
+		if !oldBody.Required && newBody.Required {
+			change(true, "request body became required")
+		}
+		removed, added := diffKeys(oldBody.Content, newBody.Content)
+		for _, contentType := range removed {
+			change(true, "request body no longer accepts %s", contentType)
+		}
+		for _, contentType := range added {
+			change(false, "request body accepts %s", contentType)
+		}
+	}
+
+	oldResponses, newResponses := responses(before.op), responses(after.op)
+	removed, added := diffKeys(oldResponses, newResponses)
+	for _, status := range removed {
+		change(strings.HasPrefix(status, "2"), "response %s removed", status)
+	}
+	for _, status := range added {
+		change(false, "response %s added", status)
+	}
+	for _, status := range sortedKeys(oldResponses) {
+		current, ok := newResponses[status]
+		if !ok {
+			continue
+		}
+		removed, added := diffKeys(content(oldResponses[status]), content(current))
+		for _, contentType := range removed {
+			change(true, "response %s no longer returns %s", status, contentType)
+		}
+		for _, contentType := range added {
+			change(false, "response %s returns %s", status, contentType)
+		}
+	}
+	return changes
+}
+
+// parameters returns the query, header and cookie parameters of an
+// operation, those of its path included, keyed by location and name
+func parameters(e endpoint) map[string]*openapi3.Parameter {
+	result := make(map[string]*openapi3.Parameter)
+	for _, refs := range []openapi3.Parameters{e.item.Parameters, e.op.Parameters} {
+		for _, ref := range refs {
+			if ref == nil || ref.Value == nil || ref.Value.In == openapi3.ParameterInPath {
+				continue
+			}
+			name := ref.Value.Name
+			// Header names are case insensitive
+			if ref.Value.In == openapi3.ParameterInHeader {
+				name = strings.ToLower(name)
+			}
+			result[ref.Value.In+" "+name] = ref.Value
+		}
+	}
+	return result
+}
+
+func requestBody(op *openapi3.Operation) *openapi3.RequestBody {
+	if op.RequestBody == nil {
+		return nil
+	}
+	return op.RequestBody.Value
+}
+
+func responses(op *openapi3.Operation) map[string]*openapi3.ResponseRef {
+	if op.Responses == nil {
+		return nil
+	}
+	return op.Responses.Map()
+}
+
+func content(ref *openapi3.ResponseRef) openapi3.Content {
+	if ref == nil || ref.Value == nil {
+		return nil
+	}
+	return ref.Value.Content
+}
+
+func schemaType(ref *openapi3.SchemaRef) string {
+	if ref == nil || ref.Value == nil || ref.Value.Type == nil {
+		return ""
+	}
+	return strings.Join(ref.Value.Type.Slice(), " | ")
+}
+
+// diffKeys returns the sorted keys only in before and only in after
+func diffKeys[V any](before, after map[string]V) (removed, added []string) {
+	for _, key := range sortedKeys(before) {
+		if _, ok := after[key]; !ok {
+			removed = append(removed, key)
+		}
+	}
+	for _, key := range sortedKeys(after) {
+		if _, ok := before[key]; !ok {
+			added = append(added, key)
+		}
+	}
+	return removed, added
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,113 @@
+package openapi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"com.kong.connect/domain"
+)
+
+const petstoreV2 = `
+openapi: 3.0.3
+info:
+  title: Petstore
+  version: 2.0.0
+paths:
+  /pets:
+    get:
+      summary: List pets
+      deprecated: true
+      parameters:
+        - name: limit
+          in: query
+          schema: {type: string}
+        - name: X-Tenant
+          in: header
+          required: true
+          schema: {type: string}
+      responses:
+        "200": {description: The pets}
+    post:
+      summary: Add a pet
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema: {type: object}
+          application/xml:
+            schema: {type: object}
+      responses:
+        "201": {description: Created}
+        "409": {description: Conflict}
+  /pets/{petId}:
+    parameters:
+      - name: petId
+        in: path
+        required: true
+        schema: {type: string}
+    get:
+      summary: Get a pet
+      responses:
+        "200": {description: The pet}
+        "404": {description: No such pet}
+  /owners:
+    get:
+      responses:
+        "200": {description: The owners}
+`
+
+func TestCompareFlagsBreakingChanges(t *testing.T) {
+	from, err := Parse([]byte(petstore))
+	require.NoError(t, err)
+	to, err := Parse([]byte(petstoreV2))
+	require.NoError(t, err)
+
+	comparison := Compare(from, to)
+	assert.True(t, comparison.Breaking)
+	assert.Equal(t, []domain.EndpointChanges{{Method: "GET", Path: "/owners"}}, comparison.Added)
+	assert.Empty(t, comparison.Removed, "renaming a path parameter keeps the endpoint")
+	assert.Equal(t, []domain.EndpointChanges{
+		{Method: "GET", Path: "/pets", Breaking: true, Changes: []domain.SpecChange{
+			{Description: "operation deprecated"},
+			{Description: "query parameter limit type changed from integer to string", Breaking: true},
+			{Description: "required header parameter X-Tenant added", Breaking: true},
+		}},
+		{Method: "POST", Path: "/pets", Breaking: true, Changes: []domain.SpecChange{
+			{Description: "request body became required", Breaking: true},
+			{Description: "request body accepts application/xml"},
+			{Description: "response 409 added"},
+		}},
+	}, comparison.Changed)
+}
+
+func TestCompareIdenticalSpecs(t *testing.T) {
+	doc, err := Parse([]byte(petstore))
+	require.NoError(t, err)
+
+	comparison := Compare(doc, doc)
+	assert.False(t, comparison.Breaking)
+	assert.Empty(t, comparison.Added)
+	assert.Empty(t, comparison.Removed)
+	assert.Empty(t, comparison.Changed)
+}
+
+func TestCompareRemovals(t *testing.T) {
+	from, err := Parse([]byte(petstoreV2))
+	require.NoError(t, err)
+	to, err := Parse([]byte(petstore))
+	require.NoError(t, err)
+
+	comparison := Compare(from, to)
+	assert.True(t, comparison.Breaking)
+	assert.Equal(t, []domain.EndpointChanges{{Method: "GET", Path: "/owners", Breaking: true}}, comparison.Removed)
+	require.Len(t, comparison.Changed, 3)
+	assert.Contains(t, comparison.Changed[0].Changes, domain.SpecChange{Description: "header parameter X-Tenant removed", Breaking: true})
+	// Clients handle fewer error responses fine
+	assert.Contains(t, comparison.Changed[1].Changes, domain.SpecChange{Description: "response 409 removed"})
+	assert.Contains(t, comparison.Changed[1].Changes, domain.SpecChange{Description: "request body no longer accepts application/xml", Breaking: true})
+	assert.Equal(t, domain.EndpointChanges{Method: "GET", Path: "/pets/{id}", Changes: []domain.SpecChange{
+		{Description: "operation deprecated"},
+	}}, comparison.Changed[2])
+}
@@ -0,0 +1,140 @@
+package openapi
+
+import (
+	_ "embed"
+	"html/template"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+//go:embed docs.html.tmpl
+var docsTemplate string
+
+var docsPage = template.Must(template.New("docs").Funcs(template.FuncMap{
+	"lower": strings.ToLower,
+}).Parse(docsTemplate))
+
+// DocsPage is the documentation page of the spec of a service version
+type DocsPage struct {
+	Service     string
+	Version     string
+	Title       string
+	APIVersion  string
+	OpenAPI     string
+	Description string
+	Servers     []string
+	Operations  []Operation
+}
+
+// Operation is an operation of a spec as documented
+type Operation struct {
+	Method      string
+	Path        string
+	Summary     string
+	Description string
+	OperationID string
+	Tags        []string
+	Deprecated  bool
+	Parameters  []Parameter
+	// RequestBody lists the content types of the request body, if any
+	RequestBody []string
+	Responses   []Response
+}
+
+// Parameter is a parameter of an operation
+type Parameter struct {
+	Name        string
+	In          string
+	Type        string
+	Required    bool
+	Description string
+}
+
+// Response is a documented response of an operation
+type Response struct {
+	Status      string
+	Description string
+}
+
+// NewDocsPage builds the documentation page of the spec of a version of a
+// service. Operations are ordered by path, then by method.
+func NewDocsPage(service, version string, doc *openapi3.T) DocsPage {
+	page := DocsPage{Service: service, Version: version, OpenAPI: doc.OpenAPI}
+	if doc.Info != nil {
+		page.Title = doc.Info.Title
+		page.APIVersion = doc.Info.Version
+		page.Description = doc.Info.Description
+	}
+	for _, server := range doc.Servers {
+		page.Servers = append(page.Servers, server.URL)
+	}
+	if doc.Paths == nil {
+		return page
+	}
+
+	items := doc.Paths.Map()
+	paths := make([]string, 0, len(items))
+	for path := range items {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		item := items[path]
+		for _, method := range methods {
+			if op := item.GetOperation(method); op != nil {
+				page.Operations = append(page.Operations, newOperation(method, path, item, op))
+			}
+		}
+	}
+	return page
+}
+
+func newOperation(method, path string, item *openapi3.PathItem, op *openapi3.Operation) Operation {
+	operation := Operation{
+		Method:      method,
+		Path:        path,
+		Summary:     op.Summary,
+		Description: op.Description,
+		OperationID: op.OperationID,
+		Tags:        op.Tags,
+		Deprecated:  op.Deprecated,
+	}
+	// Parameters of the path apply to each of its operations
+	for _, ref := range append(append(openapi3.Parameters{}, item.Parameters...), op.Parameters...) {
+		if ref == nil || ref.Value == nil {
+			continue
+		}
+		operation.Parameters = append(operation.Parameters, Parameter{
+			Name:        ref.Value.Name,
+			In:          ref.Value.In,
+			Type:        schemaType(ref.Value.Schema),
+			Required:    ref.Value.Required,
+			Description: ref.Value.Description,
+		})
+	}
+	if op.RequestBody != nil && op.RequestBody.Value != nil {
+		for contentType := range op.RequestBody.Value.Content {
+			operation.RequestBody = append(operation.RequestBody, contentType)
+		}
+		sort.Strings(operation.RequestBody)
+	}
+	if op.Responses != nil {
+		for status, ref := range op.Responses.Map() {
+			response := Response{Status: status}
+			if ref != nil && ref.Value != nil && ref.Value.Description != nil {
+				response.Description = *ref.Value.Description
+			}
+			operation.Responses = append(operation.Responses, response)
+		}
+		sort.Slice(operation.Responses, func(i, j int) bool { return operation.Responses[i].Status < operation.Responses[j].Status })
+	}
+	return operation
+}
+
+// Render writes the page as HTML. Every text of the spec is escaped.
+func (p DocsPage) Render(w io.Writer) error {
+	return docsPage.Execute(w, p)
+}
@@ -0,0 +1,346 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// PostmanSchema is the schema of the exported Postman collections
+const PostmanSchema = "https://schema.getpostman.com/json/collection/v2.1.0/collection.json"
+
+// baseURLVariable is the collection variable prefixing every request URL
+const baseURLVariable = "baseUrl"
+
+// PostmanCollection is a Postman collection v2.1 with a request per
+// operation of a spec
+type PostmanCollection struct {
+	Info     PostmanInfo       `json:"info"`
+	Item     []PostmanItem     `json:"item"`
+	Variable []PostmanVariable `json:"variable"`
+}
+
+// PostmanInfo describes a collection
+type PostmanInfo struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Version     string `json:"version,omitempty"`
+	Schema      string `json:"schema"`
+}
+
+// PostmanItem is a folder of requests, or a request
+type PostmanItem struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description,omitempty"`
+	Item        []PostmanItem   `json:"item,omitempty"`
+	Request     *PostmanRequest `json:"request,omitempty"`
+}
+
+// PostmanRequest is the request of an operation
+type PostmanRequest struct {
+	Method      string          `json:"method"`
+	Header      []PostmanHeader `json:"header"`
+	Body        *PostmanBody    `json:"body,omitempty"`
+	URL         PostmanURL      `json:"url"`
+	Description string          `json:"description,omitempty"`
+}
+
+// PostmanHeader is a header of a request
+type PostmanHeader struct {
+	Key         string `json:"key"`
+	Value       string `json:"value"`
+	Description string `json:"description,omitempty"`
+	Disabled    bool   `json:"disabled,omitempty"`
+}
+
+// PostmanBody is the body of a request: raw text, or form fields
+type PostmanBody struct {
+	Mode       string             `json:"mode"`
+	Raw        *string            `json:"raw,omitempty"`
+	URLEncoded []PostmanFormField `json:"urlencoded,omitempty"`
+	FormData   []PostmanFormField `json:"formdata,omitempty"`
+	Options    *PostmanBodyOption `json:"options,omitempty"`
+}
+
+// PostmanFormField is a field of a form body
+type PostmanFormField struct {
+	Key         string `json:"key"`
+	Value       string `json:"value"`
+	Type        string `json:"type,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// PostmanBodyOption sets the language Postman highlights a raw body in
+type PostmanBodyOption struct {
+	Raw struct {
+		Language string `json:"language"`
+	} `json:"raw"`
+}
+
+// PostmanURL is the URL of a request, under the {{baseUrl}} variable
+type PostmanURL struct {
+	Raw      string            `json:"raw"`
+	Host     []string          `json:"host"`
+	Path     []string          `json:"path"`
+	Query    []PostmanHeader   `json:"query,omitempty"`
+	Variable []PostmanVariable `json:"variable,omitempty"`
+}
+
+// PostmanVariable is a collection variable, or a path variable of a URL
+type PostmanVariable struct {
+	Key         string `json:"key"`
+	Value       string `json:"value"`
+	Description string `json:"description,omitempty"`
+}
+
+// NewPostmanCollection describes the operations of the spec of a version of
+// a service as a Postman collection. Operations are grouped in a folder per
+// first tag, in the order the spec declares its tags, and ordered by path,
+// then by method; untagged operations follow the folders. The {{baseUrl}}
+// variable is the first server of the spec. Optional query parameters and
+// headers are included disabled, and request bodies use the example of
+// their media type when the spec has one.
+func NewPostmanCollection(service, version string, doc *openapi3.T) PostmanCollection {
+	collection := PostmanCollection{
+		Info:     PostmanInfo{Name: service, Version: version, Schema: PostmanSchema},
+		Item:     []PostmanItem{},
+		Variable: []PostmanVariable{{Key: baseURLVariable, Value: baseURL(doc)}},
+	}
+	if doc.Info != nil {
+		collection.Info.Description = doc.Info.Description
+	}
+	if doc.Paths == nil {
+		return collection
+	}
+
+	var folders []*PostmanItem
+	byTag := map[string]*PostmanItem{}
+	folder := func(tag string) *PostmanItem {
+		if byTag[tag] == nil {
+			byTag[tag] = &PostmanItem{Name: tag}
+			folders = append(folders, byTag[tag])
+		}
+		return byTag[tag]
+	}
+	for _, tag := range doc.Tags {
+		folder(tag.Name).Description = tag.Description
+	}
+
+	var untagged []PostmanItem
+	items := doc.Paths.Map()
+	paths := make([]string, 0, len(items))
+	for path := range items {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		item := items[path]
+		for _, method := range methods {
+			op := item.GetOperation(method)
+			if op == nil {
+				continue
+			}
+			request := newPostmanItem(method, path, item, op)
+			if len(op.Tags) == 0 {
+				untagged = append(untagged, request)
+				continue
+			}
+			tagged := folder(op.Tags[0])
+			tagged.Item = append(tagged.Item, request)
+		}
+	}
+	// Declared tags without operations would be empty folders
+	for _, folder := range folders {
+		if len(folder.Item) > 0 {
+			collection.Item = append(collection.Item, *folder)
+		}
+	}
+	collection.Item = append(collection.Item, untagged...)
+	return collection
+}
+
+// baseURL returns the URL of the first server of a spec with the defaults of
+// its variables, or ""
+func baseURL(doc *openapi3.T) string {
+	if len(doc.Servers) == 0 || doc.Servers[0] == nil {
+		return ""
+	}
+	server := doc.Servers[0]
+	url := server.URL
+	for name, variable := range server.Variables {
+		if variable != nil {
+			url = strings.ReplaceAll(url, "{"+name+"}", variable.Default)
+		}
+	}
+	return strings.TrimSuffix(url, "/")
+}
+
+func newPostmanItem(method, path string, item *openapi3.PathItem, op *openapi3.Operation) PostmanItem {
+	name := op.Summary
+	if name == "" {
+		name = op.OperationID
+	}
+	if name == "" {
+		name = method + " " + path
+	}
+	request := &PostmanRequest{Method: method, Header: []PostmanHeader{}, Description: op.Description}
+
+	// Postman names path variables :name when they are a whole segment
+	url := PostmanURL{Host: []string{"{{" + baseURLVariable + "}}"}, Path: []string{}}
+	for _, segment := range strings.Split(strings.Trim(path, "/"), "/") {
+		if segment == "" {
+			continue
+		}
+		if match := pathParameter.FindString(segment); match == segment {
+			segment = ":" + strings.Trim(segment, "{}")
+		} else {
+			segment = pathParameter.ReplaceAllStringFunc(segment, func(name string) string { return "{" + name + "}" })
+		}
+		url.Path = append(url.Path, segment)
+	}
+
+	// Parameters of the operation override those of its path
+	parameters := map[string]*openapi3.Parameter{}
+	var order []string
+	for _, ref := range append(append(openapi3.Parameters{}, item.Parameters...), op.Parameters...) {
+		if ref == nil || ref.Value == nil {
+			continue
+		}
+		key := ref.Value.In + ":" + ref.Value.Name
+		if parameters[key] == nil {
+			order = append(order, key)
+		}
+		parameters[key] = ref.Value
+	}
+	for _, key := range order {
+		parameter := parameters[key]
+		value := exampleValue(parameter.Example)
+		switch parameter.In {
+		case openapi3.ParameterInPath:
+			url.Variable = append(url.Variable, PostmanVariable{Key: parameter.Name, Value: value, Description: parameter.Description})
+		case openapi3.ParameterInQuery:
+			url.Query = append(url.Query, PostmanHeader{Key: parameter.Name, Value: value, Description: parameter.Description, Disabled: !parameter.Required})
+		case openapi3.ParameterInHeader:
+			request.Header = append(request.Header, PostmanHeader{Key: parameter.Name, Value: value, Description: parameter.Description, Disabled: !parameter.Required})
+		}
+	}
+
+	if op.RequestBody != nil && op.RequestBody.Value != nil {
+		if contentType, body := exampleBody(op.RequestBody.Value.Content); body != nil {
+			request.Header = append(request.Header, PostmanHeader{Key: "Content-Type", Value: contentType})
+			request.Body = body
+		}
+	}
+
+	url.Raw = strings.Join(url.Host, "") + "/" + strings.Join(url.Path, "/")
+	var query []string
+	for _, parameter := range url.Query {
+		if !parameter.Disabled {
+			query = append(query, parameter.Key+"="+parameter.Value)
+		}
+	}
+	if len(query) > 0 {
+		url.Raw += "?" + strings.Join(query, "&")
+	}
+	request.URL = url
+	return PostmanItem{Name: name, Request: request}
+}
+
+// exampleBody returns the content type and body of a request, preferring
+// JSON when the operation accepts several content types
+func exampleBody(content openapi3.Content) (string, *PostmanBody) {
+	if len(content) == 0 {
+		return "", nil
+	}
+	contentTypes := make([]string, 0, len(content))
+	for contentType := range content {
+		contentTypes = append(contentTypes, contentType)
+	}
+	sort.Strings(contentTypes)
+	contentType := contentTypes[0]
+	for _, candidate := range contentTypes {
+		if strings.Contains(candidate, "json") {
+			contentType = candidate
+			break
+		}
+	}
+	media := content[contentType]
+
+	switch contentType {
+	case "application/x-www-form-urlencoded":
+		return contentType, &PostmanBody{Mode: "urlencoded", URLEncoded: formFields(media, "")}
+	case "multipart/form-data":
+		return contentType, &PostmanBody{Mode: "formdata", FormData: formFields(media, "text")}
+	}
+
+	raw := ""
+	if media != nil {
+		example := media.Example
+		if example == nil {
+			// Named examples are used in the order of their names
+			names := make([]string, 0, len(media.Examples))
+			for name, ref := range media.Examples {
+				if ref != nil && ref.Value != nil && ref.Value.Value != nil {
+					names = append(names, name)
+				}
+			}
+			sort.Strings(names)
+			if len(names) > 0 {
+				example = media.Examples[names[0]].Value.Value
+			}
+		}
+		if text, ok := example.(string); ok {
+			raw = text
+		} else if example != nil {
+			if encoded, err := json.MarshalIndent(example, "", "  "); err == nil {
+				raw = string(encoded)
+			}
+		}
+	}
+	body := &PostmanBody{Mode: "raw", Raw: &raw}
+	language := ""
+	switch {
+	case strings.Contains(contentType, "json"):
+		language = "json"
+	case strings.Contains(contentType, "xml"):
+		language = "xml"
+	}
+	if language != "" {
+		body.Options = &PostmanBodyOption{}
+		body.Options.Raw.Language = language
+	}
+	return contentType, body
+}
+
+// formFields lists the properties of the schema of a form body
+func formFields(media *openapi3.MediaType, fieldType string) []PostmanFormField {
+	if media == nil || media.Schema == nil || media.Schema.Value == nil {
+		return nil
+	}
+	names := make([]string, 0, len(media.Schema.Value.Properties))
+	for name := range media.Schema.Value.Properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	fields := make([]PostmanFormField, 0, len(names))
+	for _, name := range names {
+		field := PostmanFormField{Key: name, Type: fieldType}
+		if property := media.Schema.Value.Properties[name]; property != nil && property.Value != nil {
+			field.Description = property.Value.Description
+			field.Value = exampleValue(property.Value.Example)
+		}
+		fields = append(fields, field)
+	}
+	return fields
+}
+
+// exampleValue formats the example of a parameter, or returns "" without one
+func exampleValue(example interface{}) string {
+	if example == nil {
+		return ""
+	}
+	return fmt.Sprint(example)
+}
@@ -0,0 +1,129 @@
+package openapi
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const ordersSpec = `
+openapi: 3.0.3
+info:
+  title: Orders
+  description: Places and tracks orders
+  version: 2.0.0
+servers:
+  - url: "{scheme}://orders.example.com/"
+    variables:
+      scheme: {default: https}
+tags:
+  - name: orders
+    description: Order management
+  - name: unused
+paths:
+  /orders:
+    post:
+      tags: [orders]
+      summary: Place an order
+      parameters:
+        - {name: X-Request-Id, in: header, schema: {type: string}}
+      requestBody:
+        content:
+          application/xml: {}
+          application/json:
+            example: {item: book, quantity: 2}
+      responses:
+        "201": {description: Placed}
+  /orders/{id}:
+    parameters:
+      - {name: id, in: path, required: true, description: The order, schema: {type: string}}
+    get:
+      tags: [orders]
+      operationId: getOrder
+      parameters:
+        - {name: expand, in: query, required: true, example: items, schema: {type: string}}
+        - {name: fields, in: query, schema: {type: string}}
+      responses:
+        "200": {description: The order}
+  /health:
+    get:
+      responses:
+        "200": {description: Up}
+  /reports/{year}.csv:
+    parameters:
+      - {name: year, in: path, required: true, schema: {type: integer}}
+    post:
+      tags: [reports]
+      requestBody:
+        content:
+          application/x-www-form-urlencoded:
+            schema:
+              type: object
+              properties:
+                email: {type: string, description: Where to send it}
+      responses:
+        "202": {description: Queued}
+`
+
+func TestPostmanCollectionHasARequestPerOperation(t *testing.T) {
+	doc, err := Parse([]byte(ordersSpec))
+	require.NoError(t, err)
+	collection := NewPostmanCollection("Order Service", "2.0.0", doc)
+
+	assert.Equal(t, PostmanInfo{Name: "Order Service", Description: "Places and tracks orders", Version: "2.0.0", Schema: PostmanSchema}, collection.Info)
+	assert.Equal(t, []PostmanVariable{{Key: "baseUrl", Value: "https://orders.example.com"}}, collection.Variable)
+
+	// Declared tags first, then the others, then untagged operations
+	require.Len(t, collection.Item, 3)
+	orders, reports, health := collection.Item[0], collection.Item[1], collection.Item[2]
+	assert.Equal(t, "orders", orders.Name)
+	assert.Equal(t, "Order management", orders.Description)
+	assert.Equal(t, "reports", reports.Name)
+	assert.Equal(t, "GET /health", health.Name)
+	require.NotNil(t, health.Request)
+	assert.Equal(t, "{{baseUrl}}/health", health.Request.URL.Raw)
+
+	require.Len(t, orders.Item, 2)
+	place, get := orders.Item[0].Request, orders.Item[1].Request
+	assert.Equal(t, "Place an order", orders.Item[0].Name)
+	assert.Equal(t, "POST", place.Method)
+	assert.Equal(t, []PostmanHeader{
+		{Key: "X-Request-Id", Disabled: true},
+		{Key: "Content-Type", Value: "application/json"},
+	}, place.Header, "JSON is preferred")
+	require.NotNil(t, place.Body)
+	assert.Equal(t, "raw", place.Body.Mode)
+	assert.JSONEq(t, `{"item": "book", "quantity": 2}`, *place.Body.Raw)
+	assert.Equal(t, "json", place.Body.Options.Raw.Language)
+
+	assert.Equal(t, "getOrder", orders.Item[1].Name)
+	assert.Equal(t, "{{baseUrl}}/orders/:id?expand=items", get.URL.Raw)
+	assert.Equal(t, []string{"{{baseUrl}}"}, get.URL.Host)
+	assert.Equal(t, []string{"orders", ":id"}, get.URL.Path)
+	assert.Equal(t, []PostmanVariable{{Key: "id", Description: "The order"}}, get.URL.Variable)
+	assert.Equal(t, []PostmanHeader{{Key: "expand", Value: "items"}, {Key: "fields", Disabled: true}}, get.URL.Query)
+	assert.Nil(t, get.Body)
+
+	report := reports.Item[0].Request
+	assert.Equal(t, []string{"reports", "{{year}}.csv"}, report.URL.Path, "partial segments use variables")
+	require.NotNil(t, report.Body)
+	assert.Equal(t, "urlencoded", report.Body.Mode)
+	assert.Equal(t, []PostmanFormField{{Key: "email", Description: "Where to send it"}}, report.Body.URLEncoded)
+}
+
+func TestPostmanCollectionsAreImportedBack(t *testing.T) {
+	doc, err := Parse([]byte(ordersSpec))
+	require.NoError(t, err)
+	content, err := json.Marshal(NewPostmanCollection("Orders", "2.0.0", doc))
+	require.NoError(t, err)
+
+	definition, err := ParseDefinition(content)
+	require.NoError(t, err)
+	assert.Equal(t, FormatPostman, definition.Format)
+	assert.Equal(t, "2.0.0", definition.Version)
+	assert.Equal(t, []string{"orders", "reports"}, definition.Tags)
+	assert.Equal(t, doc.Paths.Len(), definition.Doc.Paths.Len())
+	assert.NotNil(t, definition.Doc.Paths.Value("/orders/{id}").Get)
+}
@@ -0,0 +1,80 @@
+// Package openapi validates the OpenAPI 3 specs attached to service
+// versions, extracts their metadata and renders them as documentation
+// pages. It also turns Swagger files and Postman collections into OpenAPI 3
+// specs for imports.
+package openapi
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+
+	"com.kong.connect/domain"
+)
+
+// Content types of the stored specs
+const (
+	ContentTypeJSON = "application/json"
+	ContentTypeYAML = "application/yaml"
+)
+
+// methods orders the operations of a path
+var methods = []string{
+	http.MethodGet, http.MethodPut, http.MethodPost, http.MethodDelete,
+	http.MethodOptions, http.MethodHead, http.MethodPatch, http.MethodTrace,
+}
+
+// Parse parses and validates a spec written in JSON or YAML against the
+// OpenAPI 3 specification. References to other files or URLs are refused.
+func Parse(content []byte) (*openapi3.T, error) {
+	loader := openapi3.NewLoader()
+	loader.IsExternalRefsAllowed = false
+	doc, err := loader.LoadFromData(content)
+	if err != nil {
+		return nil, fmt.Errorf("not an OpenAPI document: %v", err)
+	}
+	if !strings.HasPrefix(doc.OpenAPI, "3.") {
+		return nil, fmt.Errorf("openapi must be a 3.x version, got %q", doc.OpenAPI)
+	}
+	// Examples are documentation; a stale one does not make the spec invalid
+	if err := doc.Validate(context.Background(), openapi3.DisableExamplesValidation()); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+// ContentType returns the content type of a spec: JSON when it is an
+// object, YAML otherwise
+func ContentType(content []byte) string {
+	if bytes.HasPrefix(bytes.TrimSpace(content), []byte("{")) {
+		return ContentTypeJSON
+	}
+	return ContentTypeYAML
+}
+
+// Describe extracts the metadata of a spec
+func Describe(doc *openapi3.T) domain.SpecMetadata {
+	metadata := domain.SpecMetadata{
+		OpenAPI:            doc.OpenAPI,
+		OperationsByMethod: map[string]int{},
+	}
+	if doc.Info != nil {
+		metadata.Title = doc.Info.Title
+		metadata.APIVersion = doc.Info.Version
+	}
+	if doc.Paths == nil {
+		return metadata
+	}
+	metadata.Paths = doc.Paths.Len()
+	for _, item := range doc.Paths.Map() {
+		for method := range item.Operations() {
+			metadata.Operations++
+			metadata.OperationsByMethod[strings.ToLower(method)]++
+		}
+	}
+	return metadata
+}
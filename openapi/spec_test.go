@@ -0,0 +1,112 @@
+package openapi
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const petstore = `
+openapi: 3.0.3
+info:
+  title: Petstore
+  version: 1.2.0
+servers:
+  - url: https://pets.example.com/v1
+paths:
+  /pets:
+    get:
+      summary: List pets
+      parameters:
+        - name: limit
+          in: query
+          schema: {type: integer}
+      responses:
+        "200": {description: The pets}
+    post:
+      summary: Add a pet
+      requestBody:
+        content:
+          application/json:
+            schema: {type: object}
+      responses:
+        "201": {description: Created}
+  /pets/{id}:
+    parameters:
+      - name: id
+        in: path
+        required: true
+        schema: {type: string}
+    get:
+      summary: Get a <pet>
+      deprecated: true
+      responses:
+        "200": {description: The pet}
+        "404": {description: No such pet}
+`
+
+func TestParseValidatesOpenAPI3(t *testing.T) {
+	doc, err := Parse([]byte(petstore))
+	require.NoError(t, err)
+	assert.Equal(t, "Petstore", doc.Info.Title)
+
+	doc, err = Parse([]byte(`{"openapi": "3.1.0", "info": {"title": "Empty", "version": "1"}, "paths": {}}`))
+	require.NoError(t, err)
+	assert.Equal(t, "3.1.0", doc.OpenAPI)
+
+	for name, spec := range map[string]string{
+		"not a document": "just text",
+		"swagger 2":      `{"swagger": "2.0", "info": {"title": "Old", "version": "1"}, "paths": {}}`,
+		"no info":        `{"openapi": "3.0.3", "paths": {}}`,
+		"no responses":   "openapi: 3.0.3\ninfo: {title: T, version: '1'}\npaths:\n  /a:\n    get: {}\n",
+		"bad parameter":  "openapi: 3.0.3\ninfo: {title: T, version: '1'}\npaths:\n  /a/{id}:\n    get:\n      parameters: [{name: id, in: body}]\n      responses: {'200': {description: OK}}\n",
+		"external ref":   "openapi: 3.0.3\ninfo: {title: T, version: '1'}\npaths:\n  /a:\n    $ref: 'https://example.com/paths.yaml'\n",
+	} {
+		_, err := Parse([]byte(spec))
+		assert.Error(t, err, name)
+	}
+}
+
+func TestDescribeCountsOperations(t *testing.T) {
+	doc, err := Parse([]byte(petstore))
+	require.NoError(t, err)
+
+	metadata := Describe(doc)
+	assert.Equal(t, "3.0.3", metadata.OpenAPI)
+	assert.Equal(t, "Petstore", metadata.Title)
+	assert.Equal(t, "1.2.0", metadata.APIVersion)
+	assert.Equal(t, 2, metadata.Paths)
+	assert.Equal(t, 3, metadata.Operations)
+	assert.Equal(t, map[string]int{"get": 2, "post": 1}, metadata.OperationsByMethod)
+}
+
+func TestContentType(t *testing.T) {
+	assert.Equal(t, ContentTypeJSON, ContentType([]byte("\n {\"openapi\": \"3.0.3\"}")))
+	assert.Equal(t, ContentTypeYAML, ContentType([]byte(petstore)))
+}
+
+func TestDocsPageListsOperations(t *testing.T) {
+	doc, err := Parse([]byte(petstore))
+	require.NoError(t, err)
+
+	page := NewDocsPage("Pets", "2.0.0", doc)
+	require.Len(t, page.Operations, 3)
+	assert.Equal(t, []string{"GET /pets", "POST /pets", "GET /pets/{id}"}, []string{
+		page.Operations[0].Method + " " + page.Operations[0].Path,
+		page.Operations[1].Method + " " + page.Operations[1].Path,
+		page.Operations[2].Method + " " + page.Operations[2].Path,
+	})
+	// Path parameters apply to the operations of the path
+	assert.Equal(t, []Parameter{{Name: "id", In: "path", Type: "string", Required: true}}, page.Operations[2].Parameters)
+	assert.Equal(t, []string{"application/json"}, page.Operations[1].RequestBody)
+	assert.Equal(t, []Response{{Status: "200", Description: "The pet"}, {Status: "404", Description: "No such pet"}}, page.Operations[2].Responses)
+
+	var html strings.Builder
+	require.NoError(t, page.Render(&html))
+	assert.Contains(t, html.String(), "<title>Pets 2.0.0 · Petstore</title>")
+	assert.Contains(t, html.String(), "<code>https://pets.example.com/v1</code>")
+	assert.Contains(t, html.String(), "Get a &lt;pet&gt;")
+	assert.Contains(t, html.String(), `<section id="get-/pets/{id}" class="deprecated">`)
+}
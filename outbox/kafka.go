@@ -0,0 +1,67 @@
+package outbox
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+
+	"com.kong.connect/domain"
+)
+
+// Headers of the Kafka and NATS messages. Kafka messages are keyed by
+// "<org_id>:<service_id>" so that the events of a service keep their order
+// within a partition.
+const (
+	EventIDHeader   = "event-id"
+	EventTypeHeader = "event-type"
+)
+
+// KafkaSink produces the events of the outbox to a Kafka topic, one message
+// per event with the ChangeEvent JSON as value. Messages are acknowledged by
+// every in-sync replica.
+type KafkaSink struct {
+	writer *kafka.Writer
+}
+
+// NewKafkaSink creates a sink producing to topic on the brokers, given as
+// host:port addresses
+func NewKafkaSink(brokers []string, topic string) *KafkaSink {
+	return &KafkaSink{writer: &kafka.Writer{
+		Addr:         kafka.TCP(brokers...),
+		Topic:        topic,
+		Balancer:     &kafka.Hash{},
+		RequiredAcks: kafka.RequireAll,
+		// Send writes one batch and waits for it; there is nothing to linger for
+		BatchSize:    batchSize,
+		BatchTimeout: 10 * time.Millisecond,
+	}}
+}
+
+// Send produces events and returns once Kafka has acknowledged all of them
+func (k *KafkaSink) Send(ctx context.Context, events []domain.OutboxEvent) error {
+	messages := make([]kafka.Message, len(events))
+	for i, event := range events {
+		messages[i] = message(event)
+	}
+	return k.writer.WriteMessages(ctx, messages...)
+}
+
+// Close flushes and closes the connections to the brokers
+func (k *KafkaSink) Close() error {
+	return k.writer.Close()
+}
+
+// message returns the Kafka message of an outbox event
+func message(event domain.OutboxEvent) kafka.Message {
+	return kafka.Message{
+		Key:   []byte(strconv.Itoa(event.OrgID) + ":" + strconv.Itoa(event.ServiceID)),
+		Value: event.Payload,
+		Headers: []kafka.Header{
+			{Key: EventIDHeader, Value: []byte(strconv.Itoa(event.ID))},
+			{Key: EventTypeHeader, Value: []byte(event.Type)},
+		},
+		Time: event.CreatedAt,
+	}
+}
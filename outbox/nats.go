@@ -0,0 +1,82 @@
+package outbox
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+
+	"com.kong.connect/domain"
+)
+
+// NATSSink publishes the events of the outbox to NATS JetStream, one message
+// per event on the subject "<prefix>.<event type>", e.g.
+// kong-connect.catalog.version.created, with the ChangeEvent JSON as data. A
+// stream must capture the subjects, "<prefix>.>" for all of them. Messages
+// carry the event ID as Nats-Msg-Id, so the stream discards the events
+// shipped twice within its duplicate window.
+type NATSSink struct {
+	conn   *nats.Conn
+	js     jetstream.JetStream
+	prefix string
+}
+
+// NewNATSSink creates a sink publishing under prefix through the servers of
+// url, a comma separated list of nats:// URLs. The connection is established
+// and re-established in the background, so an unreachable server fails the
+// sends, not the startup.
+func NewNATSSink(url, prefix string) (*NATSSink, error) {
+	conn, err := nats.Connect(url,
+		nats.Name("kong-connect"),
+		nats.RetryOnFailedConnect(true),
+		nats.MaxReconnects(-1),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to NATS: %v", err)
+	}
+	js, err := jetstream.New(conn, jetstream.WithPublishAsyncMaxPending(batchSize))
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return &NATSSink{conn: conn, js: js, prefix: prefix}, nil
+}
+
+// Send publishes events and returns once JetStream has stored all of them
+func (n *NATSSink) Send(ctx context.Context, events []domain.OutboxEvent) error {
+	acks := make([]jetstream.PubAckFuture, 0, len(events))
+	for _, event := range events {
+		ack, err := n.js.PublishMsgAsync(natsMessage(n.prefix, event))
+		if err != nil {
+			return err
+		}
+		acks = append(acks, ack)
+	}
+	for _, ack := range acks {
+		select {
+		case <-ack.Ok():
+		case err := <-ack.Err():
+			return err
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+// Close drains the publishes in flight and closes the connection
+func (n *NATSSink) Close() error {
+	return n.conn.Drain()
+}
+
+// natsMessage returns the JetStream message of an outbox event
+func natsMessage(prefix string, event domain.OutboxEvent) *nats.Msg {
+	msg := nats.NewMsg(prefix + "." + event.Type)
+	msg.Data = event.Payload
+	msg.Header.Set(jetstream.MsgIDHeader, strconv.Itoa(event.ID))
+	msg.Header.Set(EventIDHeader, strconv.Itoa(event.ID))
+	msg.Header.Set(EventTypeHeader, event.Type)
+	return msg
+}
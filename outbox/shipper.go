@@ -0,0 +1,76 @@
+// Package outbox ships the change events that writes record in the
+// event_outbox table, in the transaction of the write, to the data platform.
+// An event is removed from the outbox only once the sink has acknowledged
+// it, so none is lost: a failed or interrupted run ships it again, and
+// consumers deduplicate by event ID.
+package outbox
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"com.kong.connect/domain"
+	"com.kong.connect/logging"
+)
+
+const (
+	// Interval is how often the outbox is shipped
+	Interval = time.Second
+	// batchSize bounds the events sent to the sink at once
+	batchSize = 500
+)
+
+// Store reads and removes the events of the outbox
+type Store interface {
+	// OutboxEvents returns up to limit events in the order they were recorded
+	OutboxEvents(ctx context.Context, limit int) ([]domain.OutboxEvent, error)
+	// DeleteOutboxEvents removes the events up to and including lastID
+	DeleteOutboxEvents(ctx context.Context, lastID int) error
+}
+
+// Sink receives the events of the outbox; Send returns once every event is
+// stored durably
+type Sink interface {
+	Send(ctx context.Context, events []domain.OutboxEvent) error
+}
+
+// Shipper moves the events of the outbox to a sink, in order
+type Shipper struct {
+	store  Store
+	sink   Sink
+	logger *slog.Logger
+}
+
+// NewShipper creates a shipper sending the events of store to sink
+func NewShipper(store Store, sink Sink, logger *slog.Logger) *Shipper {
+	return &Shipper{store: store, sink: sink, logger: logging.Component(logger, "outbox")}
+}
+
+// Ship sends the recorded events to the sink batch by batch until the outbox
+// is empty; it is run by a job every Interval. Runs must not overlap, which
+// the job runner and its leader election guarantee.
+func (s *Shipper) Ship(ctx context.Context) error {
+	for {
+		events, err := s.store.OutboxEvents(ctx, batchSize)
+		if err != nil {
+			return fmt.Errorf("failed to read outbox: %v", err)
+		}
+		if len(events) == 0 {
+			return nil
+		}
+		if err := s.sink.Send(ctx, events); err != nil {
+			return fmt.Errorf("failed to ship %d events: %v", len(events), err)
+		}
+		lastID := events[len(events)-1].ID
+		// Deleting fails rarely; the events are then shipped again
+		if err := s.store.DeleteOutboxEvents(ctx, lastID); err != nil {
+			return fmt.Errorf("failed to delete shipped events: %v", err)
+		}
+		s.logger.Debug("shipped outbox events", "count", len(events), "last_id", lastID)
+		if len(events) < batchSize {
+			return nil
+		}
+	}
+}
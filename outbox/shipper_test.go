@@ -0,0 +1,105 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"com.kong.connect/domain"
+)
+
+// memoryStore is an outbox of events with IDs 1 to n
+type memoryStore struct {
+	events []domain.OutboxEvent
+}
+
+func newMemoryStore(n int) *memoryStore {
+	store := &memoryStore{}
+	for id := 1; id <= n; id++ {
+		store.events = append(store.events, domain.OutboxEvent{ID: id, OrgID: 1, ServiceID: id % 3, Type: domain.EventServiceUpdated})
+	}
+	return store
+}
+
+func (s *memoryStore) OutboxEvents(ctx context.Context, limit int) ([]domain.OutboxEvent, error) {
+	return s.events[:min(limit, len(s.events))], nil
+}
+
+func (s *memoryStore) DeleteOutboxEvents(ctx context.Context, lastID int) error {
+	for len(s.events) > 0 && s.events[0].ID <= lastID {
+		s.events = s.events[1:]
+	}
+	return nil
+}
+
+// recordingSink keeps the IDs it receives, failing from the failAt-th send
+type recordingSink struct {
+	ids    []int
+	sends  int
+	failAt int
+}
+
+func (s *recordingSink) Send(ctx context.Context, events []domain.OutboxEvent) error {
+	s.sends++
+	if s.failAt > 0 && s.sends >= s.failAt {
+		return errors.New("broker unavailable")
+	}
+	for _, event := range events {
+		s.ids = append(s.ids, event.ID)
+	}
+	return nil
+}
+
+func TestShipEmptiesTheOutboxInOrder(t *testing.T) {
+	store := newMemoryStore(batchSize + 10)
+	sink := &recordingSink{}
+	require.NoError(t, NewShipper(store, sink, nil).Ship(context.Background()))
+
+	assert.Empty(t, store.events)
+	assert.Equal(t, 2, sink.sends)
+	require.Len(t, sink.ids, batchSize+10)
+	for i, id := range sink.ids {
+		assert.Equal(t, i+1, id)
+	}
+}
+
+func TestShipKeepsEventsTheSinkRefused(t *testing.T) {
+	store := newMemoryStore(batchSize + 10)
+	sink := &recordingSink{failAt: 2}
+	err := NewShipper(store, sink, nil).Ship(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "broker unavailable")
+
+	// The first batch was acknowledged and removed, the second is kept
+	require.Len(t, store.events, 10)
+	assert.Equal(t, batchSize+1, store.events[0].ID)
+
+	sink.failAt = 0
+	require.NoError(t, NewShipper(store, sink, nil).Ship(context.Background()))
+	assert.Empty(t, store.events)
+	assert.Len(t, sink.ids, batchSize+10)
+}
+
+func TestMessagesAreKeyedByService(t *testing.T) {
+	msg := message(domain.OutboxEvent{ID: 42, OrgID: 2, ServiceID: 7, Type: domain.EventVersionCreated, Payload: []byte(`{"type":"version.created"}`)})
+	assert.Equal(t, "2:7", string(msg.Key))
+	assert.JSONEq(t, `{"type":"version.created"}`, string(msg.Value))
+	headers := map[string]string{}
+	for _, header := range msg.Headers {
+		headers[header.Key] = string(header.Value)
+	}
+	assert.Equal(t, map[string]string{EventIDHeader: "42", EventTypeHeader: "version.created"}, headers)
+}
+
+func TestNATSMessagesHaveASubjectPerEventType(t *testing.T) {
+	msg := natsMessage("kong-connect.catalog", domain.OutboxEvent{ID: 42, OrgID: 2, ServiceID: 7, Type: domain.EventVersionCreated, Payload: []byte(`{"type":"version.created"}`)})
+	assert.Equal(t, "kong-connect.catalog.version.created", msg.Subject)
+	assert.JSONEq(t, `{"type":"version.created"}`, string(msg.Data))
+	// Nats-Msg-Id lets the stream discard the events shipped twice
+	assert.Equal(t, "42", msg.Header.Get("Nats-Msg-Id"))
+	assert.Equal(t, "42", msg.Header.Get(EventIDHeader))
+	assert.Equal(t, "version.created", msg.Header.Get(EventTypeHeader))
+}
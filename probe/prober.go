@@ -0,0 +1,139 @@
+// Package probe checks the health check URLs declared by services. A service
+// is up while its URL answers GET requests with a 2xx status, redirects
+// followed, within the timeout, and down otherwise.
+package probe
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+
+	"com.kong.connect/domain"
+	"com.kong.connect/logging"
+	"com.kong.connect/tenant"
+)
+
+// UserAgent identifies the probes to the services
+const UserAgent = "kong-connect-health-check"
+
+const (
+	// concurrency bounds the health checks probed at once
+	concurrency = 16
+	// maxDrainBytes of a response are read so that its connection is reused
+	maxDrainBytes = 64 << 10
+	// maxErrorLength truncates the errors recorded
+	maxErrorLength = 500
+)
+
+// Config configures the prober
+type Config struct {
+	// Interval is how often every health check is probed; 0 disables probing
+	Interval time.Duration
+	// Timeout bounds a probe; slower services are down
+	Timeout time.Duration
+}
+
+// Monitor lists the health checks to probe and records the outcome of their
+// probes
+type Monitor interface {
+	// HealthChecks returns the health checks of every organization
+	HealthChecks(ctx context.Context) ([]domain.HealthCheck, error)
+	// RecordHealthResult records a probe of a service of the organization of ctx
+	RecordHealthResult(ctx context.Context, result domain.HealthResult) error
+}
+
+// Prober probes the health checks of every organization
+type Prober struct {
+	cfg     Config
+	monitor Monitor
+	client  *http.Client
+	logger  *slog.Logger
+}
+
+// NewProber creates a prober; a nil client uses http.DefaultClient
+func NewProber(cfg Config, monitor Monitor, client *http.Client, logger *slog.Logger) *Prober {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &Prober{cfg: cfg, monitor: monitor, client: client, logger: logging.Component(logger, "probe")}
+}
+
+// Probe probes every health check once and records the outcomes; it is run
+// by a job every Config.Interval
+func (p *Prober) Probe(ctx context.Context) error {
+	checks, err := p.monitor.HealthChecks(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get health checks: %v", err)
+	}
+
+	var failed atomic.Int64
+	var group errgroup.Group
+	group.SetLimit(concurrency)
+	for _, check := range checks {
+		group.Go(func() error {
+			result := p.probe(ctx, check)
+			if err := p.monitor.RecordHealthResult(tenant.NewContext(ctx, check.OrgID), result); err != nil {
+				p.logger.Error("failed to record health result", "service_id", check.ServiceID, "org_id", check.OrgID, "error", err)
+				failed.Add(1)
+			}
+			return nil
+		})
+	}
+	group.Wait()
+
+	if n := failed.Load(); n > 0 {
+		return fmt.Errorf("failed to record %d of %d health results", n, len(checks))
+	}
+	return nil
+}
+
+// probe requests the URL of a health check, timing the response
+func (p *Prober) probe(ctx context.Context, check domain.HealthCheck) domain.HealthResult {
+	ctx, cancel := context.WithTimeout(ctx, p.cfg.Timeout)
+	defer cancel()
+
+	result := domain.HealthResult{ServiceID: check.ServiceID, URL: check.URL, CheckedAt: time.Now().UTC()}
+	start := time.Now()
+	err := p.get(ctx, check.URL)
+	result.LatencyMS = time.Since(start).Milliseconds()
+	if err != nil {
+		result.Status = domain.HealthDown
+		result.Error = truncate(err.Error())
+		p.logger.Debug("service is down", "service_id", check.ServiceID, "org_id", check.OrgID, "error", err)
+		return result
+	}
+	result.Status = domain.HealthUp
+	return result
+}
+
+func (p *Prober) get(ctx context.Context, url string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("User-Agent", UserAgent)
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, maxDrainBytes))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+func truncate(message string) string {
+	if len(message) > maxErrorLength {
+		return message[:maxErrorLength]
+	}
+	return message
+}
@@ -0,0 +1,97 @@
+package probe
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"com.kong.connect/domain"
+	"com.kong.connect/tenant"
+)
+
+// recordingMonitor serves checks and keeps the results recorded, with the
+// organization they were recorded in
+type recordingMonitor struct {
+	checks []domain.HealthCheck
+	fail   bool
+
+	mu      sync.Mutex
+	results []domain.HealthResult
+	orgs    map[int]int
+}
+
+func (m *recordingMonitor) HealthChecks(ctx context.Context) ([]domain.HealthCheck, error) {
+	return m.checks, nil
+}
+
+func (m *recordingMonitor) RecordHealthResult(ctx context.Context, result domain.HealthResult) error {
+	if m.fail {
+		return errors.New("database is locked")
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.results = append(m.results, result)
+	m.orgs[result.ServiceID] = tenant.FromContext(ctx)
+	return nil
+}
+
+func TestProbeRecordsUpAndDown(t *testing.T) {
+	var userAgent string
+	service := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/healthz":
+			userAgent = r.UserAgent()
+			w.Write([]byte("ok"))
+		case "/moved":
+			http.Redirect(w, r, "/healthz", http.StatusFound)
+		case "/slow":
+			time.Sleep(200 * time.Millisecond)
+		default:
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+		}
+	}))
+	defer service.Close()
+
+	monitor := &recordingMonitor{orgs: map[int]int{}, checks: []domain.HealthCheck{
+		{OrgID: 1, ServiceID: 1, URL: service.URL + "/healthz"},
+		{OrgID: 2, ServiceID: 2, URL: service.URL + "/moved"},
+		{OrgID: 1, ServiceID: 3, URL: service.URL + "/broken"},
+		{OrgID: 1, ServiceID: 4, URL: service.URL + "/slow"},
+		{OrgID: 1, ServiceID: 5, URL: "http://127.0.0.1:1/healthz"},
+	}}
+	prober := NewProber(Config{Interval: time.Minute, Timeout: 100 * time.Millisecond}, monitor, nil, nil)
+	require.NoError(t, prober.Probe(context.Background()))
+
+	require.Len(t, monitor.results, 5)
+	sort.Slice(monitor.results, func(i, j int) bool { return monitor.results[i].ServiceID < monitor.results[j].ServiceID })
+	statuses := make([]string, len(monitor.results))
+	for i, result := range monitor.results {
+		statuses[i] = result.Status
+		assert.Equal(t, monitor.checks[i].URL, result.URL)
+		assert.False(t, result.CheckedAt.IsZero())
+	}
+	assert.Equal(t, []string{domain.HealthUp, domain.HealthUp, domain.HealthDown, domain.HealthDown, domain.HealthDown}, statuses)
+	assert.Empty(t, monitor.results[0].Error)
+	assert.Equal(t, "unexpected status 503", monitor.results[2].Error)
+	assert.Contains(t, monitor.results[3].Error, "deadline exceeded")
+	assert.GreaterOrEqual(t, monitor.results[3].LatencyMS, int64(100))
+	assert.NotEmpty(t, monitor.results[4].Error)
+	assert.Equal(t, UserAgent, userAgent)
+
+	// Results are recorded in the organization of their service
+	assert.Equal(t, map[int]int{1: 1, 2: 2, 3: 1, 4: 1, 5: 1}, monitor.orgs)
+}
+
+func TestProbeFailsWhenResultsAreLost(t *testing.T) {
+	monitor := &recordingMonitor{fail: true, checks: []domain.HealthCheck{{OrgID: 1, ServiceID: 1, URL: "http://127.0.0.1:1/"}}}
+	prober := NewProber(Config{Interval: time.Minute, Timeout: time.Second}, monitor, nil, nil)
+	assert.EqualError(t, prober.Probe(context.Background()), "failed to record 1 of 1 health results")
+}
@@ -0,0 +1,78 @@
+package problem
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"com.kong.connect/i18n"
+	"com.kong.connect/requestid"
+)
+
+// Problem type URIs returned in the "type" member of problem details
+const (
+	TypeDefault       = "about:blank"
+	TypeInvalidQuery  = "/problems/invalid-query-parameters"
+	TypeInvalidBody   = "/problems/invalid-request-body"
+	TypeNotFound      = "/problems/not-found"
+	TypeValidation    = "/problems/validation-error"
+	TypeConflict      = "/problems/conflict"
+	TypeForbidden     = "/problems/forbidden"
+	TypeUnprocessable = "/problems/unprocessable"
+)
+
+// InvalidParam describes why a single request parameter was rejected
+type InvalidParam struct {
+	Name   string `json:"name"`
+	Reason string `json:"reason"`
+}
+
+// Details is an RFC 7807 problem details response body
+type Details struct {
+	Type          string         `json:"type"`
+	Title         string         `json:"title"`
+	Status        int            `json:"status"`
+	Detail        string         `json:"detail,omitempty"`
+	Instance      string         `json:"instance,omitempty"`
+	RequestID     string         `json:"request_id,omitempty"`
+	InvalidParams []InvalidParam `json:"invalid_params,omitempty"`
+}
+
+// Write writes a problem details response for the current request, filling
+// in the type, title, instance and request ID when they are not set. The
+// title, detail and reasons are translated into the language of the client
+// when the i18n catalog has them.
+func Write(w http.ResponseWriter, r *http.Request, details Details) {
+	if details.Type == "" {
+		details.Type = TypeDefault
+	}
+	if details.Title == "" {
+		details.Title = http.StatusText(details.Status)
+	}
+	if lang := i18n.FromContext(r.Context()); lang != i18n.Default {
+		details.Title = i18n.Translate(lang, details.Title)
+		details.Detail = i18n.Translate(lang, details.Detail)
+		if details.InvalidParams != nil {
+			// Translated in a copy, which the caller may keep
+			params := make([]InvalidParam, len(details.InvalidParams))
+			for i, param := range details.InvalidParams {
+				params[i] = InvalidParam{Name: param.Name, Reason: i18n.Translate(lang, param.Reason)}
+			}
+			details.InvalidParams = params
+		}
+	}
+	if details.Instance == "" {
+		details.Instance = r.URL.Path
+	}
+	if details.RequestID == "" {
+		details.RequestID = requestid.FromContext(r.Context())
+	}
+
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(details.Status)
+	json.NewEncoder(w).Encode(details)
+}
+
+// Error writes a problem details response with the given status and detail message
+func Error(w http.ResponseWriter, r *http.Request, status int, detail string) {
+	Write(w, r, Details{Status: status, Detail: detail})
+}
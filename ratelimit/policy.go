@@ -0,0 +1,36 @@
+package ratelimit
+
+import "sync/atomic"
+
+// Policy holds the limits per route group. It is safe for concurrent use and
+// can be replaced while requests are being served.
+type Policy struct {
+	limits atomic.Pointer[map[string]Limit]
+}
+
+// NewPolicy creates a policy with the given limits
+func NewPolicy(limits map[string]Limit) *Policy {
+	p := &Policy{}
+	p.Set(limits)
+	return p
+}
+
+// Set replaces all limits
+func (p *Policy) Set(limits map[string]Limit) {
+	copied := make(map[string]Limit, len(limits))
+	for group, limit := range limits {
+		copied[group] = limit
+	}
+	p.limits.Store(&copied)
+}
+
+// Lookup returns the limit of a group, falling back to the "default" group.
+// It returns false when neither is limited.
+func (p *Policy) Lookup(group string) (Limit, bool) {
+	limits := *p.limits.Load()
+	if limit, ok := limits[group]; ok {
+		return limit, true
+	}
+	limit, ok := limits["default"]
+	return limit, ok
+}
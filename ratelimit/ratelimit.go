@@ -0,0 +1,82 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Limit configures a token bucket: Burst tokens at most, refilled at Rate tokens per second
+type Limit struct {
+	Rate  float64
+	Burst int
+}
+
+// Result describes the outcome of taking a token from a bucket
+type Result struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	// RetryAfter is how long until the next token is available when the request was denied
+	RetryAfter time.Duration
+	// ResetAfter is how long until the bucket is full again
+	ResetAfter time.Duration
+}
+
+// Store keeps token buckets by key
+type Store interface {
+	Allow(ctx context.Context, key string, limit Limit) (Result, error)
+}
+
+// newResult derives the response metadata from the tokens left in a bucket
+func newResult(limit Limit, tokens float64, allowed bool) Result {
+	result := Result{
+		Allowed:    allowed,
+		Limit:      limit.Burst,
+		Remaining:  int(math.Floor(tokens)),
+		ResetAfter: seconds((float64(limit.Burst) - tokens) / limit.Rate),
+	}
+	if !allowed {
+		result.RetryAfter = seconds((1 - tokens) / limit.Rate)
+	}
+	return result
+}
+
+func seconds(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second))
+}
+
+// ParseLimits parses per group limits written as "group=rate:burst" pairs
+// separated by commas, e.g. "read=20:40,write=5:10". "off" means no limits.
+func ParseLimits(spec string) (map[string]Limit, error) {
+	limits := make(map[string]Limit)
+	if strings.TrimSpace(spec) == "off" {
+		return limits, nil
+	}
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		group, value, ok := strings.Cut(entry, "=")
+		rate, burst, ok2 := strings.Cut(value, ":")
+		if !ok || !ok2 || strings.TrimSpace(group) == "" {
+			return nil, fmt.Errorf("invalid rate limit %q: want group=rate:burst", entry)
+		}
+
+		r, err := strconv.ParseFloat(rate, 64)
+		if err != nil || r <= 0 {
+			return nil, fmt.Errorf("invalid rate limit %q: rate must be a positive number", entry)
+		}
+		b, err := strconv.Atoi(burst)
+		if err != nil || b <= 0 {
+			return nil, fmt.Errorf("invalid rate limit %q: burst must be a positive integer", entry)
+		}
+		limits[strings.TrimSpace(group)] = Limit{Rate: r, Burst: b}
+	}
+	return limits, nil
+}
@@ -0,0 +1,73 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryStoreRefills(t *testing.T) {
+	now := time.Unix(0, 0)
+	store := NewMemoryStore()
+	store.now = func() time.Time { return now }
+	limit := Limit{Rate: 1, Burst: 2}
+
+	for i := 0; i < 2; i++ {
+		result, err := store.Allow(context.Background(), "user:viewer", limit)
+		require.NoError(t, err)
+		assert.True(t, result.Allowed)
+	}
+
+	result, _ := store.Allow(context.Background(), "user:viewer", limit)
+	assert.False(t, result.Allowed)
+	assert.Equal(t, time.Second, result.RetryAfter)
+	assert.Equal(t, 2*time.Second, result.ResetAfter)
+
+	now = now.Add(1500 * time.Millisecond)
+	result, _ = store.Allow(context.Background(), "user:viewer", limit)
+	assert.True(t, result.Allowed)
+	assert.Equal(t, 0, result.Remaining)
+
+	// Refilled buckets are swept
+	now = now.Add(time.Hour)
+	store.Allow(context.Background(), "user:admin", limit)
+	assert.Len(t, store.buckets, 1)
+}
+
+func TestRedisStore(t *testing.T) {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	defer client.Close()
+
+	store := NewRedisStore(client, "ratelimit:")
+	limit := Limit{Rate: 1, Burst: 2}
+
+	for i := 0; i < 2; i++ {
+		result, err := store.Allow(context.Background(), "user:viewer", limit)
+		require.NoError(t, err)
+		assert.True(t, result.Allowed)
+		assert.Equal(t, 1-i, result.Remaining)
+	}
+
+	result, err := store.Allow(context.Background(), "user:viewer", limit)
+	require.NoError(t, err)
+	assert.False(t, result.Allowed)
+	assert.Positive(t, result.RetryAfter)
+	assert.True(t, server.Exists("ratelimit:user:viewer"))
+}
+
+func TestParseLimits(t *testing.T) {
+	limits, err := ParseLimits("read=20:40, write=0.5:5")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]Limit{"read": {Rate: 20, Burst: 40}, "write": {Rate: 0.5, Burst: 5}}, limits)
+
+	_, err = ParseLimits("read=20")
+	assert.Error(t, err)
+	_, err = ParseLimits("read=-1:5")
+	assert.Error(t, err)
+}
@@ -0,0 +1,181 @@
+package realtime
+
+import (
+	"log/slog"
+	"sync"
+
+	"com.kong.connect/domain"
+	"com.kong.connect/logging"
+)
+
+// clientBuffer is the number of events queued per client before it is considered too slow
+const clientBuffer = 64
+
+// Hub fans catalog change events out to subscribed WebSocket clients
+type Hub struct {
+	mu      sync.RWMutex
+	clients map[*client]struct{}
+	closed  bool
+	logger  *slog.Logger
+}
+
+// NewHub creates a new hub; a nil logger falls back to the default logger
+func NewHub(logger *slog.Logger) *Hub {
+	return &Hub{
+		clients: make(map[*client]struct{}),
+		logger:  logging.Component(logger, "realtime"),
+	}
+}
+
+// Publish delivers an event to every client of its organization subscribed
+// to its service ID or one of its tags. Clients whose queue is full are disconnected rather than
+// slowing down the publisher.
+func (h *Hub) Publish(event domain.ChangeEvent) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	for c := range h.clients {
+		if !c.matches(event) {
+			continue
+		}
+		select {
+		case c.send <- event:
+		default:
+			c.closeSlow()
+		}
+	}
+}
+
+// ClientCount returns the number of connected clients
+func (h *Hub) ClientCount() int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return len(h.clients)
+}
+
+// Close disconnects every client with a going-away close frame and rejects
+// new connections. It is meant to run when the server shuts down, since
+// http.Server.Shutdown does not track hijacked connections.
+func (h *Hub) Close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.closed = true
+	for c := range h.clients {
+		c.shutdown()
+	}
+}
+
+// register adds a client, or returns false once the hub is closed
+func (h *Hub) register(c *client) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.closed {
+		return false
+	}
+	h.clients[c] = struct{}{}
+	return true
+}
+
+func (h *Hub) unregister(c *client) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	delete(h.clients, c)
+}
+
+// client is a single WebSocket connection and its subscriptions
+type client struct {
+	// orgID is the organization whose events the client receives
+	orgID int
+
+	mu         sync.RWMutex
+	serviceIDs map[int]struct{}
+	tags       map[string]struct{}
+
+	send      chan domain.ChangeEvent
+	replies   chan Message
+	slow      chan struct{}
+	closeOnce sync.Once
+
+	stopping     chan struct{}
+	stoppingOnce sync.Once
+}
+
+func newClient(orgID int) *client {
+	return &client{
+		orgID:      orgID,
+		serviceIDs: make(map[int]struct{}),
+		tags:       make(map[string]struct{}),
+		send:       make(chan domain.ChangeEvent, clientBuffer),
+		replies:    make(chan Message, clientBuffer),
+		slow:       make(chan struct{}),
+		stopping:   make(chan struct{}),
+	}
+}
+
+// matches reports whether the client subscribed to the event's service or
+// any of its tags within its organization
+func (c *client) matches(event domain.ChangeEvent) bool {
+	if event.OrgID != c.orgID {
+		return false
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if _, ok := c.serviceIDs[event.ServiceID]; ok {
+		return true
+	}
+	for _, tag := range event.Tags {
+		if _, ok := c.tags[tag]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *client) subscribe(serviceIDs []int, tags []string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, id := range serviceIDs {
+		c.serviceIDs[id] = struct{}{}
+	}
+	for _, tag := range tags {
+		c.tags[tag] = struct{}{}
+	}
+}
+
+func (c *client) unsubscribe(serviceIDs []int, tags []string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, id := range serviceIDs {
+		delete(c.serviceIDs, id)
+	}
+	for _, tag := range tags {
+		delete(c.tags, tag)
+	}
+}
+
+// subscriptions returns the current subscriptions of the client
+func (c *client) subscriptions() ([]int, []string) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	ids := make([]int, 0, len(c.serviceIDs))
+	for id := range c.serviceIDs {
+		ids = append(ids, id)
+	}
+	tags := make([]string, 0, len(c.tags))
+	for tag := range c.tags {
+		tags = append(tags, tag)
+	}
+	return ids, tags
+}
+
+func (c *client) closeSlow() {
+	c.closeOnce.Do(func() { close(c.slow) })
+}
+
+func (c *client) shutdown() {
+	c.stoppingOnce.Do(func() { close(c.stopping) })
+}
@@ -0,0 +1,234 @@
+package realtime
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"com.kong.connect/domain"
+	"com.kong.connect/middleware"
+	"com.kong.connect/problem"
+)
+
+const (
+	authTimeout  = 10 * time.Second
+	writeTimeout = 10 * time.Second
+	pongTimeout  = 60 * time.Second
+	pingInterval = pongTimeout * 9 / 10
+	maxMessage   = 4096
+)
+
+// Message types exchanged over the socket
+const (
+	MessageAuth        = "auth"
+	MessageSubscribe   = "subscribe"
+	MessageUnsubscribe = "unsubscribe"
+	MessageAck         = "ack"
+	MessageEvent       = "event"
+	MessageError       = "error"
+)
+
+// Message is the JSON envelope of every frame sent or received on the socket
+type Message struct {
+	Type       string              `json:"type"`
+	Token      string              `json:"token,omitempty"`
+	Org        string              `json:"org,omitempty"`
+	ServiceIDs []int               `json:"service_ids,omitempty"`
+	Tags       []string            `json:"tags,omitempty"`
+	Event      *domain.ChangeEvent `json:"event,omitempty"`
+	Error      string              `json:"error,omitempty"`
+}
+
+var (
+	errAuthRequired = errors.New("authentication required")
+	errInvalidToken = errors.New("invalid token")
+	errForbidden    = errors.New("forbidden")
+)
+
+// allowedRoles are the roles permitted to receive change notifications
+var allowedRoles = []string{"admin", "viewer"}
+
+var upgrader = websocket.Upgrader{
+	ReadBufferSize:  1024,
+	WriteBufferSize: 1024,
+	// Authentication is token based rather than cookie based, so cross-origin
+	// connections can't ride on ambient credentials
+	CheckOrigin: func(r *http.Request) bool { return true },
+}
+
+// ServeWS handles GET /ws. Clients authenticate either with an Authorization
+// header on the upgrade request or with an auth message as their first frame,
+// and receive the events of the organization selected with the X-Org header
+// or the auth message's org.
+func (h *Hub) ServeWS(w http.ResponseWriter, r *http.Request) {
+	var user *middleware.UserClaims
+	orgID := 0
+	if authHeader := r.Header.Get("Authorization"); authHeader != "" {
+		claims, err := authenticate(strings.TrimPrefix(authHeader, "Bearer "))
+		if err != nil {
+			problem.Error(w, r, http.StatusUnauthorized, err.Error())
+			return
+		}
+		var status int
+		var message string
+		if orgID, status, message = middleware.ResolveOrg(r.Context(), claims, r.Header.Get(middleware.OrgHeader)); status != 0 {
+			problem.Error(w, r, status, message)
+			return
+		}
+		user = claims
+	}
+
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		h.logger.WarnContext(r.Context(), "failed to upgrade websocket connection", "error", err)
+		return
+	}
+	defer conn.Close()
+	conn.SetReadLimit(maxMessage)
+
+	if user == nil {
+		if orgID, err = awaitAuth(r.Context(), conn); err != nil {
+			writeMessage(conn, Message{Type: MessageError, Error: err.Error()})
+			return
+		}
+	}
+
+	c := newClient(orgID)
+	if !h.register(c) {
+		conn.WriteControl(websocket.CloseMessage,
+			websocket.FormatCloseMessage(websocket.CloseGoingAway, "server shutting down"), time.Now().Add(writeTimeout))
+		return
+	}
+	defer h.unregister(c)
+	writeMessage(conn, Message{Type: MessageAck})
+
+	done := make(chan struct{})
+	go c.readLoop(conn, done, h.logger)
+	c.writeLoop(conn, done)
+}
+
+// awaitAuth reads the first frame, validates the token it carries and
+// returns the organization it selects
+func awaitAuth(ctx context.Context, conn *websocket.Conn) (int, error) {
+	conn.SetReadDeadline(time.Now().Add(authTimeout))
+	defer conn.SetReadDeadline(time.Time{})
+
+	var msg Message
+	if err := conn.ReadJSON(&msg); err != nil {
+		return 0, errAuthRequired
+	}
+	if msg.Type != MessageAuth {
+		return 0, errAuthRequired
+	}
+	user, err := authenticate(msg.Token)
+	if err != nil {
+		return 0, err
+	}
+	orgID, status, message := middleware.ResolveOrg(ctx, user, msg.Org)
+	if status != 0 {
+		return 0, errors.New(strings.ToLower(message))
+	}
+	return orgID, nil
+}
+
+// authenticate validates a token with the same rules as the HTTP API
+func authenticate(token string) (*middleware.UserClaims, error) {
+	user, err := middleware.ValidateToken(token)
+	if err != nil {
+		return nil, errInvalidToken
+	}
+	for _, role := range user.Roles {
+		for _, allowed := range allowedRoles {
+			if role == allowed {
+				return user, nil
+			}
+		}
+	}
+	return nil, errForbidden
+}
+
+// readLoop processes subscription messages until the connection fails
+func (c *client) readLoop(conn *websocket.Conn, done chan<- struct{}, logger *slog.Logger) {
+	defer close(done)
+
+	conn.SetReadDeadline(time.Now().Add(pongTimeout))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(pongTimeout))
+	})
+
+	for {
+		var msg Message
+		if err := conn.ReadJSON(&msg); err != nil {
+			if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseNormalClosure) {
+				logger.Warn("failed to read websocket message", "error", err)
+			}
+			return
+		}
+
+		reply := Message{Type: MessageAck}
+		switch msg.Type {
+		case MessageSubscribe:
+			c.subscribe(msg.ServiceIDs, msg.Tags)
+		case MessageUnsubscribe:
+			c.unsubscribe(msg.ServiceIDs, msg.Tags)
+		default:
+			reply = Message{Type: MessageError, Error: "unknown message type"}
+		}
+
+		if reply.Type == MessageAck {
+			reply.ServiceIDs, reply.Tags = c.subscriptions()
+			sort.Ints(reply.ServiceIDs)
+			sort.Strings(reply.Tags)
+		}
+		// Replies travel through the same queue as events so that only the
+		// write loop writes to the connection
+		select {
+		case c.replies <- reply:
+		default:
+		}
+	}
+}
+
+// writeLoop sends queued events, replies and keepalive pings
+func (c *client) writeLoop(conn *websocket.Conn, done <-chan struct{}) {
+	ticker := time.NewTicker(pingInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case event := <-c.send:
+			if err := writeMessage(conn, Message{Type: MessageEvent, Event: &event}); err != nil {
+				return
+			}
+		case reply := <-c.replies:
+			if err := writeMessage(conn, reply); err != nil {
+				return
+			}
+		case <-ticker.C:
+			conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+			if err := conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				return
+			}
+		case <-c.slow:
+			writeMessage(conn, Message{Type: MessageError, Error: "client too slow, disconnecting"})
+			return
+		case <-c.stopping:
+			conn.WriteControl(websocket.CloseMessage,
+				websocket.FormatCloseMessage(websocket.CloseGoingAway, "server shutting down"), time.Now().Add(writeTimeout))
+			return
+		case <-done:
+			return
+		}
+	}
+}
+
+func writeMessage(conn *websocket.Conn, msg Message) error {
+	conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+	return conn.WriteJSON(msg)
+}
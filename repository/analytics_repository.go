@@ -0,0 +1,160 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"com.kong.connect/domain"
+	"com.kong.connect/tenant"
+	"com.kong.connect/tracing"
+)
+
+// AddUsage adds daily view and search counts of any organization to those
+// stored
+func (r *ServiceRepository) AddUsage(ctx context.Context, usage domain.Usage) (err error) {
+	ctx, span := tracing.StartDB(ctx, "ServiceRepository.AddUsage")
+	defer func() { tracing.End(span, err) }()
+
+	return retryBusyWrite(ctx, func() error {
+		tx, err := r.begin(ctx)
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+
+		for _, count := range usage.Views {
+			if _, err := tx.ExecContext(ctx, `
+				INSERT INTO usage_service_views (org_id, service_id, day, views) VALUES (?, ?, ?, ?)
+				ON CONFLICT (org_id, service_id, day) DO UPDATE SET views = views + excluded.views`,
+				count.OrgID, count.ServiceID, count.Day, count.Views,
+			); err != nil {
+				return err
+			}
+		}
+		for _, count := range usage.Searches {
+			if _, err := tx.ExecContext(ctx, `
+				INSERT INTO usage_search_terms (org_id, term, day, searches) VALUES (?, ?, ?, ?)
+				ON CONFLICT (org_id, term, day) DO UPDATE SET searches = searches + excluded.searches`,
+				count.OrgID, count.Term, count.Day, count.Searches,
+			); err != nil {
+				return err
+			}
+		}
+		return tx.Commit()
+	})
+}
+
+// GetTopViewedServices retrieves the services of the organization viewed
+// most between two days, both included, most viewed first
+func (r *ServiceRepository) GetTopViewedServices(ctx context.Context, since, until string, limit int) (_ []domain.ServiceViews, err error) {
+	ctx, span := tracing.StartDB(ctx, "ServiceRepository.GetTopViewedServices")
+	defer func() { tracing.End(span, err) }()
+
+	rows, err := r.conn(ctx).QueryContext(ctx, `
+		SELECT s.id, s.name, SUM(v.views) AS total
+		FROM usage_service_views v
+		JOIN services s ON s.id = v.service_id AND s.org_id = v.org_id
+		WHERE v.org_id = ? AND v.day >= ? AND v.day <= ?
+		GROUP BY s.id, s.name
+		ORDER BY total DESC, s.name
+		LIMIT ?`,
+		tenant.FromContext(ctx), since, until, limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	services := []domain.ServiceViews{}
+	for rows.Next() {
+		var service domain.ServiceViews
+		if err := rows.Scan(&service.ServiceID, &service.ServiceName, &service.Views); err != nil {
+			return nil, err
+		}
+		services = append(services, service)
+	}
+	return services, rows.Err()
+}
+
+// GetSearchTerms retrieves the terms searched in the organization at least
+// minSearches times between since and until, both included, with their
+// searches since previousSince before that. Trending terms are those
+// searched more than before, by growth; the others are by searches.
+func (r *ServiceRepository) GetSearchTerms(ctx context.Context, previousSince, since, until string, minSearches int, trending bool, limit int) (_ []domain.SearchTermStats, err error) {
+	ctx, span := tracing.StartDB(ctx, "ServiceRepository.GetSearchTerms")
+	defer func() { tracing.End(span, err) }()
+
+	having, orderBy := "current >= ?", "current DESC, term"
+	if trending {
+		having, orderBy = "current >= ? AND current > previous", "current - previous DESC, current DESC, term"
+	}
+	rows, err := r.conn(ctx).QueryContext(ctx, `
+		SELECT term,
+			SUM(CASE WHEN day >= ? THEN searches ELSE 0 END) AS current,
+			SUM(CASE WHEN day < ? THEN searches ELSE 0 END) AS previous
+		FROM usage_search_terms
+		WHERE org_id = ? AND day >= ? AND day <= ?
+		GROUP BY term
+		HAVING `+having+`
+		ORDER BY `+orderBy+`
+		LIMIT ?`,
+		since, since, tenant.FromContext(ctx), previousSince, until, minSearches, limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	terms := []domain.SearchTermStats{}
+	for rows.Next() {
+		var term domain.SearchTermStats
+		if err := rows.Scan(&term.Term, &term.Searches, &term.PreviousSearches); err != nil {
+			return nil, err
+		}
+		terms = append(terms, term)
+	}
+	return terms, rows.Err()
+}
+
+// PurgeUsage deletes the usage of every organization counted on days before
+// the cutoff and returns how many rows were removed
+func (r *ServiceRepository) PurgeUsage(ctx context.Context, before time.Time) (_ int64, err error) {
+	ctx, span := tracing.StartDB(ctx, "ServiceRepository.PurgeUsage")
+	defer func() { tracing.End(span, err) }()
+
+	return retryBusy(ctx, func() (int64, error) {
+		tx, err := r.begin(ctx)
+		if err != nil {
+			return 0, err
+		}
+		defer tx.Rollback()
+
+		day := before.UTC().Format(domain.AnalyticsDayFormat)
+		var purged int64
+		for _, table := range []string{"usage_service_views", "usage_search_terms"} {
+			result, err := tx.ExecContext(ctx, "DELETE FROM "+table+" WHERE day < ?", day)
+			if err != nil {
+				return 0, err
+			}
+			affected, err := result.RowsAffected()
+			if err != nil {
+				return 0, err
+			}
+			purged += affected
+		}
+		return purged, tx.Commit()
+	})
+}
+
+// CountUsageBefore returns how many rows PurgeUsage would delete
+func (r *ServiceRepository) CountUsageBefore(ctx context.Context, before time.Time) (_ int64, err error) {
+	ctx, span := tracing.StartDB(ctx, "ServiceRepository.CountUsageBefore")
+	defer func() { tracing.End(span, err) }()
+
+	day := before.UTC().Format(domain.AnalyticsDayFormat)
+	var count int64
+	err = r.conn(ctx).QueryRowContext(ctx, `
+		SELECT (SELECT COUNT(*) FROM usage_service_views WHERE day < ?) + (SELECT COUNT(*) FROM usage_search_terms WHERE day < ?)`,
+		day, day).Scan(&count)
+	return count, err
+}
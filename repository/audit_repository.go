@@ -0,0 +1,144 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"com.kong.connect/domain"
+	"com.kong.connect/tenant"
+	"com.kong.connect/tracing"
+)
+
+// auditTimeFormat matches the layout SQLite uses for CURRENT_TIMESTAMP, so
+// bounds compare correctly against stored timestamps
+const auditTimeFormat = "2006-01-02 15:04:05"
+
+// InsertAuditEntry stores an audit entry in the organization's log
+func (r *ServiceRepository) InsertAuditEntry(ctx context.Context, entry domain.AuditEntry) (err error) {
+	ctx, span := tracing.StartDB(ctx, "ServiceRepository.InsertAuditEntry")
+	defer func() { tracing.End(span, err) }()
+
+	changes, err := json.Marshal(entry.Changes)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.exec(ctx, `
+		INSERT INTO audit_logs (org_id, created_at, principal, action, resource_type, resource_id, ip, request_id, before, after, changes)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		tenant.FromContext(ctx), entry.Timestamp.UTC().Format(auditTimeFormat), entry.Principal, entry.Action, entry.ResourceType,
+		entry.ResourceID, entry.IP, entry.RequestID, nullJSON(entry.Before), nullJSON(entry.After), string(changes),
+	)
+	return err
+}
+
+// GetAuditEntries retrieves one page of the organization's audit entries
+// matching the query, newest first
+func (r *ServiceRepository) GetAuditEntries(ctx context.Context, query domain.AuditQuery) (_ []domain.AuditEntry, _ int, err error) {
+	ctx, span := tracing.StartDB(ctx, "ServiceRepository.GetAuditEntries")
+	defer func() { tracing.End(span, err) }()
+
+	conditions := []string{"org_id = ?"}
+	args := []interface{}{tenant.FromContext(ctx)}
+	if query.Principal != "" {
+		conditions = append(conditions, "principal = ?")
+		args = append(args, query.Principal)
+	}
+	if query.Action != "" {
+		conditions = append(conditions, "action = ?")
+		args = append(args, query.Action)
+	}
+	if query.ResourceType != "" {
+		conditions = append(conditions, "resource_type = ?")
+		args = append(args, query.ResourceType)
+	}
+	if query.ResourceID > 0 {
+		conditions = append(conditions, "resource_id = ?")
+		args = append(args, query.ResourceID)
+	}
+	if !query.Since.IsZero() {
+		conditions = append(conditions, "created_at >= ?")
+		args = append(args, query.Since.UTC().Format(auditTimeFormat))
+	}
+	if !query.Until.IsZero() {
+		conditions = append(conditions, "created_at < ?")
+		args = append(args, query.Until.UTC().Format(auditTimeFormat))
+	}
+
+	where := "WHERE " + strings.Join(conditions, " AND ")
+
+	var total int
+	if err := r.conn(ctx).QueryRowContext(ctx, "SELECT COUNT(*) FROM audit_logs "+where, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	rows, err := r.conn(ctx).QueryContext(ctx, `
+		SELECT id, created_at, principal, action, resource_type, resource_id, ip, request_id, before, after, changes
+		FROM audit_logs `+where+`
+		ORDER BY id DESC
+		LIMIT ? OFFSET ?`,
+		append(args, query.PageSize, (query.Page-1)*query.PageSize)...,
+	)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	entries := []domain.AuditEntry{}
+	for rows.Next() {
+		var entry domain.AuditEntry
+		var before, after sql.NullString
+		var changes string
+		if err := rows.Scan(&entry.ID, &entry.Timestamp, &entry.Principal, &entry.Action, &entry.ResourceType,
+			&entry.ResourceID, &entry.IP, &entry.RequestID, &before, &after, &changes); err != nil {
+			return nil, 0, err
+		}
+		if before.Valid {
+			entry.Before = json.RawMessage(before.String)
+		}
+		if after.Valid {
+			entry.After = json.RawMessage(after.String)
+		}
+		if err := json.Unmarshal([]byte(changes), &entry.Changes); err != nil {
+			return nil, 0, err
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, total, rows.Err()
+}
+
+// PurgeAuditEntries deletes the audit entries of every organization recorded
+// before the cutoff and returns how many were removed
+func (r *ServiceRepository) PurgeAuditEntries(ctx context.Context, before time.Time) (_ int64, err error) {
+	ctx, span := tracing.StartDB(ctx, "ServiceRepository.PurgeAuditEntries")
+	defer func() { tracing.End(span, err) }()
+
+	result, err := r.exec(ctx, "DELETE FROM audit_logs WHERE created_at < ?", before.UTC().Format(auditTimeFormat))
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// CountAuditEntriesBefore returns how many audit entries of every
+// organization were recorded before the cutoff
+func (r *ServiceRepository) CountAuditEntriesBefore(ctx context.Context, before time.Time) (_ int64, err error) {
+	ctx, span := tracing.StartDB(ctx, "ServiceRepository.CountAuditEntriesBefore")
+	defer func() { tracing.End(span, err) }()
+
+	var count int64
+	err = r.conn(ctx).QueryRowContext(ctx, "SELECT COUNT(*) FROM audit_logs WHERE created_at < ?", before.UTC().Format(auditTimeFormat)).Scan(&count)
+	return count, err
+}
+
+// nullJSON stores absent snapshots as NULL rather than an empty string
+func nullJSON(raw json.RawMessage) interface{} {
+	if len(raw) == 0 {
+		return nil
+	}
+	return string(raw)
+}
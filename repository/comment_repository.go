@@ -0,0 +1,166 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	"com.kong.connect/domain"
+	"com.kong.connect/tenant"
+	"com.kong.connect/tracing"
+)
+
+const commentColumns = "id, service_id, parent_id, author, body, mentions, created_at"
+
+// CreateComment stores a comment on a service of the organization and
+// returns it with its ID, or nil when the service does not exist
+func (r *ServiceRepository) CreateComment(ctx context.Context, comment domain.Comment) (_ *domain.Comment, err error) {
+	ctx, span := tracing.StartDB(ctx, "ServiceRepository.CreateComment")
+	defer func() { tracing.End(span, err) }()
+
+	orgID := tenant.FromContext(ctx)
+	var exists bool
+	if err := r.conn(ctx).QueryRowContext(ctx,
+		"SELECT EXISTS (SELECT 1 FROM services WHERE id = ? AND org_id = ?)", comment.ServiceID, orgID,
+	).Scan(&exists); err != nil || !exists {
+		return nil, err
+	}
+
+	result, err := r.exec(ctx, `
+		INSERT INTO service_comments (service_id, org_id, parent_id, author, body, mentions, created_at) VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		comment.ServiceID, orgID, comment.ParentID, comment.Author, comment.Body,
+		strings.Join(comment.Mentions, ","), comment.CreatedAt.UTC().Format(auditTimeFormat),
+	)
+	if err != nil {
+		return nil, err
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return nil, err
+	}
+	comment.ID = int(id)
+	return &comment, nil
+}
+
+// GetComment retrieves a comment on a service of the organization, or nil
+func (r *ServiceRepository) GetComment(ctx context.Context, serviceID, commentID int) (_ *domain.Comment, err error) {
+	ctx, span := tracing.StartDB(ctx, "ServiceRepository.GetComment")
+	defer func() { tracing.End(span, err) }()
+
+	comment, err := scanComment(r.conn(ctx).QueryRowContext(ctx,
+		"SELECT "+commentColumns+" FROM service_comments WHERE id = ? AND service_id = ? AND org_id = ?",
+		commentID, serviceID, tenant.FromContext(ctx)))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &comment, nil
+}
+
+// GetComments retrieves one page of the threads on a service of the
+// organization, the most recently started first, each with its replies
+func (r *ServiceRepository) GetComments(ctx context.Context, query domain.CommentQuery) (_ []domain.Comment, _ int, err error) {
+	ctx, span := tracing.StartDB(ctx, "ServiceRepository.GetComments")
+	defer func() { tracing.End(span, err) }()
+
+	orgID := tenant.FromContext(ctx)
+	var total int
+	if err := r.conn(ctx).QueryRowContext(ctx,
+		"SELECT COUNT(*) FROM service_comments WHERE service_id = ? AND org_id = ? AND parent_id IS NULL",
+		query.ServiceID, orgID,
+	).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	rows, err := r.conn(ctx).QueryContext(ctx, `
+		SELECT `+commentColumns+` FROM service_comments
+		WHERE service_id = ? AND org_id = ? AND parent_id IS NULL
+		ORDER BY id DESC
+		LIMIT ? OFFSET ?`,
+		query.ServiceID, orgID, query.PageSize, (query.Page-1)*query.PageSize,
+	)
+	if err != nil {
+		return nil, 0, err
+	}
+	threads, err := scanComments(rows)
+	if err != nil {
+		return nil, 0, err
+	}
+	if len(threads) == 0 {
+		return threads, total, nil
+	}
+
+	// The replies of the whole page at once
+	args := []interface{}{query.ServiceID, orgID}
+	index := make(map[int]int, len(threads))
+	for i, thread := range threads {
+		args = append(args, thread.ID)
+		index[thread.ID] = i
+	}
+	rows, err = r.conn(ctx).QueryContext(ctx, `
+		SELECT `+commentColumns+` FROM service_comments
+		WHERE service_id = ? AND org_id = ? AND parent_id IN (`+strings.TrimSuffix(strings.Repeat("?,", len(threads)), ",")+`)
+		ORDER BY id`, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	replies, err := scanComments(rows)
+	if err != nil {
+		return nil, 0, err
+	}
+	for _, reply := range replies {
+		thread := &threads[index[*reply.ParentID]]
+		thread.Replies = append(thread.Replies, reply)
+	}
+	return threads, total, nil
+}
+
+// DeleteComment removes a comment on a service of the organization with its
+// replies, returning false when there was none
+func (r *ServiceRepository) DeleteComment(ctx context.Context, serviceID, commentID int) (_ bool, err error) {
+	ctx, span := tracing.StartDB(ctx, "ServiceRepository.DeleteComment")
+	defer func() { tracing.End(span, err) }()
+
+	result, err := r.exec(ctx,
+		"DELETE FROM service_comments WHERE (id = ? OR parent_id = ?) AND service_id = ? AND org_id = ?",
+		commentID, commentID, serviceID, tenant.FromContext(ctx),
+	)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	return affected > 0, err
+}
+
+func scanComment(row scanner) (domain.Comment, error) {
+	var comment domain.Comment
+	var parentID sql.NullInt64
+	var mentions string
+	if err := row.Scan(&comment.ID, &comment.ServiceID, &parentID, &comment.Author, &comment.Body, &mentions, &comment.CreatedAt); err != nil {
+		return comment, err
+	}
+	if parentID.Valid {
+		id := int(parentID.Int64)
+		comment.ParentID = &id
+	}
+	comment.Mentions = []string{}
+	if mentions != "" {
+		comment.Mentions = strings.Split(mentions, ",")
+	}
+	return comment, nil
+}
+
+func scanComments(rows *sql.Rows) ([]domain.Comment, error) {
+	defer rows.Close()
+	comments := []domain.Comment{}
+	for rows.Next() {
+		comment, err := scanComment(rows)
+		if err != nil {
+			return nil, err
+		}
+		comments = append(comments, comment)
+	}
+	return comments, rows.Err()
+}
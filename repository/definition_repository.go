@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+
+	"com.kong.connect/domain"
+	"com.kong.connect/tenant"
+	"com.kong.connect/tracing"
+)
+
+// ImportDefinition inserts a service into the organization with its first
+// version and the spec of the version, in one transaction, and returns the
+// ID of the service and the version
+func (r *ServiceRepository) ImportDefinition(ctx context.Context, input domain.ServiceInput, versionInput domain.VersionInput, spec domain.VersionSpec) (_ int, _ *domain.ServiceVersion, err error) {
+	ctx, span := tracing.StartDB(ctx, "ServiceRepository.ImportDefinition")
+	defer func() { tracing.End(span, err) }()
+
+	metadata, err := json.Marshal(spec.Metadata)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	var id int
+	var version domain.ServiceVersion
+	err = retryBusyWrite(ctx, func() error {
+		tx, err := r.begin(ctx)
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+
+		id, err = insertService(ctx, tx, tenant.FromContext(ctx), input)
+		if err != nil {
+			return err
+		}
+		result, err := tx.ExecContext(ctx, "INSERT INTO service_versions (service_id, version, original_version) VALUES (?, ?, NULLIF(?, ''))", id, versionInput.Version, versionInput.Original)
+		if err != nil {
+			return translateError(err)
+		}
+		versionID, err := result.LastInsertId()
+		if err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx,
+			"INSERT INTO version_specs (version_id, service_id, content, content_type, metadata) VALUES (?, ?, ?, ?, ?)",
+			versionID, id, spec.Content, spec.ContentType, string(metadata),
+		); err != nil {
+			return err
+		}
+
+		version, err = scanVersion(tx.QueryRowContext(ctx, "SELECT "+versionColumns+" FROM service_versions v WHERE v.id = ?", versionID))
+		if err != nil {
+			return err
+		}
+		if err := r.recordEvent(ctx, tx, domain.EventServiceCreated, id, nil); err != nil {
+			return err
+		}
+		if err := r.recordEvent(ctx, tx, domain.EventVersionCreated, id, &version); err != nil {
+			return err
+		}
+		if err := r.recordEvent(ctx, tx, domain.EventVersionSpecUpdated, id, &version); err != nil {
+			return err
+		}
+
+		return tx.Commit()
+	})
+	if err != nil {
+		return 0, nil, err
+	}
+	return id, &version, nil
+}
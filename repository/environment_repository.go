@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"com.kong.connect/domain"
+	"com.kong.connect/tenant"
+	"com.kong.connect/tracing"
+)
+
+// DeployVersion records that an environment of a service runs one of its
+// versions, replacing the version it ran before. It returns false when the
+// service does not exist in the organization.
+func (r *ServiceRepository) DeployVersion(ctx context.Context, serviceID, versionID int, environment string) (_ bool, err error) {
+	ctx, span := tracing.StartDB(ctx, "ServiceRepository.DeployVersion")
+	defer func() { tracing.End(span, err) }()
+
+	return retryBusy(ctx, func() (bool, error) {
+		tx, err := r.begin(ctx)
+		if err != nil {
+			return false, err
+		}
+		defer tx.Rollback()
+
+		if found, err := touchService(ctx, tx, tenant.FromContext(ctx), serviceID); err != nil || !found {
+			return false, err
+		}
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO service_environments (service_id, environment, version_id) VALUES (?, ?, ?)
+			ON CONFLICT (service_id, environment) DO UPDATE SET version_id = excluded.version_id, deployed_at = CURRENT_TIMESTAMP`,
+			serviceID, environment, versionID,
+		); err != nil {
+			return false, err
+		}
+		if r.outbox {
+			deployed, err := scanVersion(tx.QueryRowContext(ctx, "SELECT "+versionColumns+" FROM service_versions v WHERE v.id = ?", versionID))
+			if err != nil {
+				return false, err
+			}
+			if err := r.recordEvent(ctx, tx, domain.EventVersionDeployed, serviceID, &deployed); err != nil {
+				return false, err
+			}
+		}
+
+		return true, tx.Commit()
+	})
+}
+
+// UndeployEnvironment records that an environment no longer runs a service.
+// It returns false when the service does not exist in the organization or
+// the environment runs none of its versions.
+func (r *ServiceRepository) UndeployEnvironment(ctx context.Context, serviceID int, environment string) (_ bool, err error) {
+	ctx, span := tracing.StartDB(ctx, "ServiceRepository.UndeployEnvironment")
+	defer func() { tracing.End(span, err) }()
+
+	return retryBusy(ctx, func() (bool, error) {
+		tx, err := r.begin(ctx)
+		if err != nil {
+			return false, err
+		}
+		defer tx.Rollback()
+
+		if found, err := touchService(ctx, tx, tenant.FromContext(ctx), serviceID); err != nil || !found {
+			return false, err
+		}
+		var versionID int
+		err = tx.QueryRowContext(ctx, "SELECT version_id FROM service_environments WHERE service_id = ? AND environment = ?", serviceID, environment).Scan(&versionID)
+		if err == sql.ErrNoRows {
+			return false, nil
+		}
+		if err != nil {
+			return false, err
+		}
+		if _, err := tx.ExecContext(ctx, "DELETE FROM service_environments WHERE service_id = ? AND environment = ?", serviceID, environment); err != nil {
+			return false, err
+		}
+		if r.outbox {
+			undeployed, err := scanVersion(tx.QueryRowContext(ctx, "SELECT "+versionColumns+" FROM service_versions v WHERE v.id = ?", versionID))
+			if err != nil {
+				return false, err
+			}
+			if err := r.recordEvent(ctx, tx, domain.EventVersionUndeployed, serviceID, &undeployed); err != nil {
+				return false, err
+			}
+		}
+
+		return true, tx.Commit()
+	})
+}
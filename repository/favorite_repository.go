@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"context"
+
+	"com.kong.connect/tenant"
+	"com.kong.connect/tracing"
+)
+
+// AddFavorite stars a service of the organization for a user, returning
+// false when the service does not exist. Starring twice is a no-op.
+func (r *ServiceRepository) AddFavorite(ctx context.Context, serviceID int, username string) (_ bool, err error) {
+	ctx, span := tracing.StartDB(ctx, "ServiceRepository.AddFavorite")
+	defer func() { tracing.End(span, err) }()
+
+	orgID := tenant.FromContext(ctx)
+	var exists bool
+	if err := r.conn(ctx).QueryRowContext(ctx,
+		"SELECT EXISTS (SELECT 1 FROM services WHERE id = ? AND org_id = ?)", serviceID, orgID,
+	).Scan(&exists); err != nil || !exists {
+		return false, err
+	}
+
+	_, err = r.exec(ctx,
+		"INSERT INTO service_favorites (service_id, username, org_id) VALUES (?, ?, ?) ON CONFLICT DO NOTHING",
+		serviceID, username, orgID,
+	)
+	return err == nil, err
+}
+
+// RemoveFavorite unstars a service of the organization for a user,
+// returning false when it was not starred
+func (r *ServiceRepository) RemoveFavorite(ctx context.Context, serviceID int, username string) (_ bool, err error) {
+	ctx, span := tracing.StartDB(ctx, "ServiceRepository.RemoveFavorite")
+	defer func() { tracing.End(span, err) }()
+
+	result, err := r.exec(ctx,
+		"DELETE FROM service_favorites WHERE service_id = ? AND username = ? AND org_id = ?",
+		serviceID, username, tenant.FromContext(ctx),
+	)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	return affected > 0, err
+}
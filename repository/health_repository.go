@@ -0,0 +1,257 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	"com.kong.connect/domain"
+	"com.kong.connect/tenant"
+	"com.kong.connect/tracing"
+)
+
+// healthColumns selects the health check of the service s from
+// service_health_checks h, joined by healthJoin, for healthFields
+const (
+	healthColumns = "h.url, h.status, h.latency_ms, h.error, h.checked_at"
+	healthJoin    = "LEFT JOIN service_health_checks h ON h.service_id = s.id"
+)
+
+// healthFields scans healthColumns; every column is NULL for services
+// without a health check
+type healthFields struct {
+	url, status, error sql.NullString
+	latency            sql.NullInt64
+	checkedAt          sql.NullTime
+}
+
+func (f *healthFields) dest() []interface{} {
+	return []interface{}{&f.url, &f.status, &f.latency, &f.error, &f.checkedAt}
+}
+
+// health returns the scanned health check, or nil
+func (f *healthFields) health() *domain.ServiceHealth {
+	if !f.url.Valid {
+		return nil
+	}
+	health := &domain.ServiceHealth{URL: f.url.String, Status: f.status.String, LatencyMS: f.latency.Int64, Error: f.error.String}
+	if f.checkedAt.Valid {
+		checkedAt := f.checkedAt.Time
+		health.CheckedAt = &checkedAt
+	}
+	return health
+}
+
+// SetHealthCheck declares the URL probing a service; its status is unknown
+// until the next probe. It returns false when the service does not exist in
+// the organization.
+func (r *ServiceRepository) SetHealthCheck(ctx context.Context, serviceID int, url string) (_ bool, err error) {
+	ctx, span := tracing.StartDB(ctx, "ServiceRepository.SetHealthCheck")
+	defer func() { tracing.End(span, err) }()
+
+	return retryBusy(ctx, func() (bool, error) {
+		tx, err := r.begin(ctx)
+		if err != nil {
+			return false, err
+		}
+		defer tx.Rollback()
+
+		orgID := tenant.FromContext(ctx)
+		if found, err := touchService(ctx, tx, orgID, serviceID); err != nil || !found {
+			return false, err
+		}
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO service_health_checks (service_id, org_id, url) VALUES (?, ?, ?)
+			ON CONFLICT (service_id) DO UPDATE SET url = excluded.url, status = ?, latency_ms = 0, error = '', checked_at = NULL`,
+			serviceID, orgID, url, domain.HealthUnknown,
+		); err != nil {
+			return false, err
+		}
+		if err := r.recordEvent(ctx, tx, domain.EventServiceUpdated, serviceID, nil); err != nil {
+			return false, err
+		}
+
+		return true, tx.Commit()
+	})
+}
+
+// DeleteHealthCheck stops probing a service and removes its probe results.
+// It returns false when the service does not exist in the organization or
+// has no health check.
+func (r *ServiceRepository) DeleteHealthCheck(ctx context.Context, serviceID int) (_ bool, err error) {
+	ctx, span := tracing.StartDB(ctx, "ServiceRepository.DeleteHealthCheck")
+	defer func() { tracing.End(span, err) }()
+
+	return retryBusy(ctx, func() (bool, error) {
+		tx, err := r.begin(ctx)
+		if err != nil {
+			return false, err
+		}
+		defer tx.Rollback()
+
+		orgID := tenant.FromContext(ctx)
+		if found, err := touchService(ctx, tx, orgID, serviceID); err != nil || !found {
+			return false, err
+		}
+		result, err := tx.ExecContext(ctx, "DELETE FROM service_health_checks WHERE service_id = ? AND org_id = ?", serviceID, orgID)
+		if err != nil {
+			return false, err
+		}
+		if affected, err := result.RowsAffected(); err != nil || affected == 0 {
+			return false, err
+		}
+		if _, err := tx.ExecContext(ctx, "DELETE FROM service_health_results WHERE service_id = ?", serviceID); err != nil {
+			return false, err
+		}
+		if err := r.recordEvent(ctx, tx, domain.EventServiceUpdated, serviceID, nil); err != nil {
+			return false, err
+		}
+
+		return true, tx.Commit()
+	})
+}
+
+// HealthChecks retrieves the health checks of every organization
+func (r *ServiceRepository) HealthChecks(ctx context.Context) (_ []domain.HealthCheck, err error) {
+	ctx, span := tracing.StartDB(ctx, "ServiceRepository.HealthChecks")
+	defer func() { tracing.End(span, err) }()
+
+	rows, err := r.conn(ctx).QueryContext(ctx, "SELECT org_id, service_id, url FROM service_health_checks ORDER BY service_id")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var checks []domain.HealthCheck
+	for rows.Next() {
+		var check domain.HealthCheck
+		if err := rows.Scan(&check.OrgID, &check.ServiceID, &check.URL); err != nil {
+			return nil, err
+		}
+		checks = append(checks, check)
+	}
+	return checks, rows.Err()
+}
+
+// RecordHealthResult stores the outcome of a probe as the current health of
+// its service and in its history, and returns the status the service had.
+// Probes of a health check removed or replaced since they started are not
+// recorded, and return false.
+func (r *ServiceRepository) RecordHealthResult(ctx context.Context, result domain.HealthResult) (_ string, _ bool, err error) {
+	ctx, span := tracing.StartDB(ctx, "ServiceRepository.RecordHealthResult")
+	defer func() { tracing.End(span, err) }()
+
+	var previous string
+	var found bool
+	err = retryBusyWrite(ctx, func() error {
+		tx, err := r.begin(ctx)
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+
+		err = tx.QueryRowContext(ctx, "SELECT status FROM service_health_checks WHERE service_id = ? AND org_id = ? AND url = ?",
+			result.ServiceID, tenant.FromContext(ctx), result.URL).Scan(&previous)
+		if err == sql.ErrNoRows {
+			found = false
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		checkedAt := result.CheckedAt.UTC().Format(auditTimeFormat)
+		if _, err := tx.ExecContext(ctx, `
+			UPDATE service_health_checks SET status = ?, latency_ms = ?, error = ?, checked_at = ? WHERE service_id = ?`,
+			result.Status, result.LatencyMS, result.Error, checkedAt, result.ServiceID,
+		); err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO service_health_results (service_id, url, status, latency_ms, error, checked_at) VALUES (?, ?, ?, ?, ?, ?)`,
+			result.ServiceID, result.URL, result.Status, result.LatencyMS, result.Error, checkedAt,
+		); err != nil {
+			return err
+		}
+		if previous != result.Status {
+			if err := r.recordEvent(ctx, tx, domain.EventServiceHealthChanged, result.ServiceID, nil); err != nil {
+				return err
+			}
+		}
+
+		found = true
+		return tx.Commit()
+	})
+	if err != nil || !found {
+		return "", false, err
+	}
+	return previous, true, nil
+}
+
+// GetHealthResults retrieves one page of the probe results of a service of
+// the organization matching the query, newest first
+func (r *ServiceRepository) GetHealthResults(ctx context.Context, query domain.HealthHistoryQuery) (_ []domain.HealthResult, _ int, err error) {
+	ctx, span := tracing.StartDB(ctx, "ServiceRepository.GetHealthResults")
+	defer func() { tracing.End(span, err) }()
+
+	conditions := []string{"service_id = ?", "service_id IN (SELECT id FROM services WHERE org_id = ?)"}
+	args := []interface{}{query.ServiceID, tenant.FromContext(ctx)}
+	if !query.Since.IsZero() {
+		conditions = append(conditions, "checked_at >= ?")
+		args = append(args, query.Since.UTC().Format(auditTimeFormat))
+	}
+	where := "WHERE " + strings.Join(conditions, " AND ")
+
+	var total int
+	if err := r.conn(ctx).QueryRowContext(ctx, "SELECT COUNT(*) FROM service_health_results "+where, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	rows, err := r.conn(ctx).QueryContext(ctx, `
+		SELECT service_id, url, status, latency_ms, error, checked_at
+		FROM service_health_results `+where+`
+		ORDER BY id DESC
+		LIMIT ? OFFSET ?`,
+		append(args, query.PageSize, (query.Page-1)*query.PageSize)...,
+	)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	results := []domain.HealthResult{}
+	for rows.Next() {
+		var result domain.HealthResult
+		if err := rows.Scan(&result.ServiceID, &result.URL, &result.Status, &result.LatencyMS, &result.Error, &result.CheckedAt); err != nil {
+			return nil, 0, err
+		}
+		results = append(results, result)
+	}
+	return results, total, rows.Err()
+}
+
+// PurgeHealthResults deletes the probe results of every organization
+// recorded before the cutoff and returns how many were removed
+func (r *ServiceRepository) PurgeHealthResults(ctx context.Context, before time.Time) (_ int64, err error) {
+	ctx, span := tracing.StartDB(ctx, "ServiceRepository.PurgeHealthResults")
+	defer func() { tracing.End(span, err) }()
+
+	result, err := r.exec(ctx, "DELETE FROM service_health_results WHERE checked_at < ?", before.UTC().Format(auditTimeFormat))
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// CountHealthResultsBefore returns how many probe results
+// PurgeHealthResults would delete
+func (r *ServiceRepository) CountHealthResultsBefore(ctx context.Context, before time.Time) (_ int64, err error) {
+	ctx, span := tracing.StartDB(ctx, "ServiceRepository.CountHealthResultsBefore")
+	defer func() { tracing.End(span, err) }()
+
+	var count int64
+	err = r.conn(ctx).QueryRowContext(ctx, "SELECT COUNT(*) FROM service_health_results WHERE checked_at < ?",
+		before.UTC().Format(auditTimeFormat)).Scan(&count)
+	return count, err
+}
@@ -0,0 +1,45 @@
+package repository
+
+import (
+	"context"
+
+	"com.kong.connect/tenant"
+	"com.kong.connect/tracing"
+)
+
+// ImportedServiceIDs retrieves the IDs of the services of the organization
+// created or taken over by the importer of source
+func (r *ServiceRepository) ImportedServiceIDs(ctx context.Context, source string) (_ map[int]bool, err error) {
+	ctx, span := tracing.StartDB(ctx, "ServiceRepository.ImportedServiceIDs")
+	defer func() { tracing.End(span, err) }()
+
+	rows, err := r.conn(ctx).QueryContext(ctx,
+		"SELECT service_id FROM service_imports WHERE org_id = ? AND source = ?", tenant.FromContext(ctx), source)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ids := make(map[int]bool)
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids[id] = true
+	}
+	return ids, rows.Err()
+}
+
+// MarkImported records that the importer of source manages a service
+func (r *ServiceRepository) MarkImported(ctx context.Context, serviceID int, source string) (err error) {
+	ctx, span := tracing.StartDB(ctx, "ServiceRepository.MarkImported")
+	defer func() { tracing.End(span, err) }()
+
+	_, err = r.exec(ctx, `
+		INSERT INTO service_imports (service_id, org_id, source) VALUES (?, ?, ?)
+		ON CONFLICT (service_id) DO UPDATE SET source = excluded.source, imported_at = CURRENT_TIMESTAMP`,
+		serviceID, tenant.FromContext(ctx), source,
+	)
+	return err
+}
@@ -0,0 +1,182 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	"com.kong.connect/domain"
+	"com.kong.connect/tenant"
+	"com.kong.connect/tracing"
+)
+
+// GetNotificationPreferences retrieves the preferences of a user; users who
+// never saved theirs get every notification but have no email address
+func (r *ServiceRepository) GetNotificationPreferences(ctx context.Context, username string) (_ *domain.NotificationPreferences, err error) {
+	ctx, span := tracing.StartDB(ctx, "ServiceRepository.GetNotificationPreferences")
+	defer func() { tracing.End(span, err) }()
+
+	preferences := domain.NotificationPreferences{Deprecations: true, OwnershipChanges: true, Mentions: true}
+	err = r.conn(ctx).QueryRowContext(ctx,
+		"SELECT email, deprecations, ownership_changes, mentions FROM notification_preferences WHERE username = ?", username,
+	).Scan(&preferences.Email, &preferences.Deprecations, &preferences.OwnershipChanges, &preferences.Mentions)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, err
+	}
+	return &preferences, nil
+}
+
+// SaveNotificationPreferences replaces the preferences of a user
+func (r *ServiceRepository) SaveNotificationPreferences(ctx context.Context, username string, preferences domain.NotificationPreferences) (err error) {
+	ctx, span := tracing.StartDB(ctx, "ServiceRepository.SaveNotificationPreferences")
+	defer func() { tracing.End(span, err) }()
+
+	_, err = r.exec(ctx, `
+		INSERT INTO notification_preferences (username, email, deprecations, ownership_changes, mentions) VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT (username) DO UPDATE SET email = excluded.email, deprecations = excluded.deprecations,
+			ownership_changes = excluded.ownership_changes, mentions = excluded.mentions, updated_at = CURRENT_TIMESTAMP`,
+		username, preferences.Email, preferences.Deprecations, preferences.OwnershipChanges, preferences.Mentions,
+	)
+	return err
+}
+
+// Subscribe subscribes a user to a service of the organization, returning
+// false when the service does not exist. Subscribing twice is a no-op.
+func (r *ServiceRepository) Subscribe(ctx context.Context, serviceID int, username string) (_ bool, err error) {
+	ctx, span := tracing.StartDB(ctx, "ServiceRepository.Subscribe")
+	defer func() { tracing.End(span, err) }()
+
+	orgID := tenant.FromContext(ctx)
+	var exists bool
+	if err := r.conn(ctx).QueryRowContext(ctx,
+		"SELECT EXISTS (SELECT 1 FROM services WHERE id = ? AND org_id = ?)", serviceID, orgID,
+	).Scan(&exists); err != nil || !exists {
+		return false, err
+	}
+
+	_, err = r.exec(ctx,
+		"INSERT INTO service_subscriptions (service_id, username, org_id) VALUES (?, ?, ?) ON CONFLICT DO NOTHING",
+		serviceID, username, orgID,
+	)
+	return err == nil, err
+}
+
+// Unsubscribe removes the subscription of a user to a service of the
+// organization, returning false when there was none
+func (r *ServiceRepository) Unsubscribe(ctx context.Context, serviceID int, username string) (_ bool, err error) {
+	ctx, span := tracing.StartDB(ctx, "ServiceRepository.Unsubscribe")
+	defer func() { tracing.End(span, err) }()
+
+	result, err := r.exec(ctx,
+		"DELETE FROM service_subscriptions WHERE service_id = ? AND username = ? AND org_id = ?",
+		serviceID, username, tenant.FromContext(ctx),
+	)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	return affected > 0, err
+}
+
+// ListSubscriptions retrieves the subscriptions of a user in the
+// organization, ordered by service name
+func (r *ServiceRepository) ListSubscriptions(ctx context.Context, username string) (_ []domain.Subscription, err error) {
+	ctx, span := tracing.StartDB(ctx, "ServiceRepository.ListSubscriptions")
+	defer func() { tracing.End(span, err) }()
+
+	rows, err := r.conn(ctx).QueryContext(ctx, `
+		SELECT s.id, s.name, sub.created_at
+		FROM service_subscriptions sub JOIN services s ON s.id = sub.service_id
+		WHERE sub.username = ? AND sub.org_id = ?
+		ORDER BY s.name`, username, tenant.FromContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	subscriptions := []domain.Subscription{}
+	for rows.Next() {
+		var subscription domain.Subscription
+		if err := rows.Scan(&subscription.ServiceID, &subscription.ServiceName, &subscription.CreatedAt); err != nil {
+			return nil, err
+		}
+		subscriptions = append(subscriptions, subscription)
+	}
+	return subscriptions, rows.Err()
+}
+
+// EmailRecipients retrieves the users to email a notification about a
+// service of the organization: its subscribers and the given owners, when
+// they have an email address and did not turn the notification off
+func (r *ServiceRepository) EmailRecipients(ctx context.Context, serviceID int, notification string, owners []string) (_ []domain.EmailRecipient, err error) {
+	ctx, span := tracing.StartDB(ctx, "ServiceRepository.EmailRecipients")
+	defer func() { tracing.End(span, err) }()
+
+	// Sunset reminders go along with deprecations
+	enabled := "p.deprecations"
+	if notification == domain.NotifyOwnershipChange {
+		enabled = "p.ownership_changes"
+	}
+	query := `
+		SELECT p.username, p.email FROM notification_preferences p
+		WHERE p.email != '' AND ` + enabled + ` = 1 AND (
+			p.username IN (SELECT username FROM service_subscriptions WHERE service_id = ? AND org_id = ?)`
+	args := []interface{}{serviceID, tenant.FromContext(ctx)}
+	if len(owners) > 0 {
+		query += " OR p.username IN (" + strings.TrimSuffix(strings.Repeat("?,", len(owners)), ",") + ")"
+		for _, owner := range owners {
+			args = append(args, owner)
+		}
+	}
+	query += ") ORDER BY p.username"
+
+	rows, err := r.conn(ctx).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var recipients []domain.EmailRecipient
+	for rows.Next() {
+		var recipient domain.EmailRecipient
+		if err := rows.Scan(&recipient.Username, &recipient.Email); err != nil {
+			return nil, err
+		}
+		recipients = append(recipients, recipient)
+	}
+	return recipients, rows.Err()
+}
+
+// MentionRecipients retrieves the users among usernames to email about a
+// comment mentioning them: those with an email address who did not turn
+// mentions off
+func (r *ServiceRepository) MentionRecipients(ctx context.Context, usernames []string) (_ []domain.EmailRecipient, err error) {
+	ctx, span := tracing.StartDB(ctx, "ServiceRepository.MentionRecipients")
+	defer func() { tracing.End(span, err) }()
+
+	if len(usernames) == 0 {
+		return nil, nil
+	}
+	args := make([]interface{}, len(usernames))
+	for i, username := range usernames {
+		args[i] = username
+	}
+	rows, err := r.conn(ctx).QueryContext(ctx, `
+		SELECT username, email FROM notification_preferences
+		WHERE email != '' AND mentions = 1 AND username IN (`+strings.TrimSuffix(strings.Repeat("?,", len(usernames)), ",")+`)
+		ORDER BY username`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var recipients []domain.EmailRecipient
+	for rows.Next() {
+		var recipient domain.EmailRecipient
+		if err := rows.Scan(&recipient.Username, &recipient.Email); err != nil {
+			return nil, err
+		}
+		recipients = append(recipients, recipient)
+	}
+	return recipients, rows.Err()
+}
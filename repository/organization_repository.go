@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"com.kong.connect/domain"
+	"com.kong.connect/tracing"
+)
+
+const organizationColumns = "id, slug, name, created_at"
+
+// CreateOrganization inserts an organization and returns it
+func (r *ServiceRepository) CreateOrganization(ctx context.Context, input domain.OrganizationInput) (_ *domain.Organization, err error) {
+	ctx, span := tracing.StartDB(ctx, "ServiceRepository.CreateOrganization")
+	defer func() { tracing.End(span, err) }()
+
+	result, err := r.exec(ctx, "INSERT INTO organizations (slug, name) VALUES (?, ?)", input.Slug, input.Name)
+	if err != nil {
+		return nil, translateError(err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return nil, err
+	}
+	return scanOrganization(r.conn(ctx).QueryRowContext(ctx, "SELECT "+organizationColumns+" FROM organizations WHERE id = ?", id))
+}
+
+// GetOrganizationBySlug retrieves an organization, or nil when it does not exist
+func (r *ServiceRepository) GetOrganizationBySlug(ctx context.Context, slug string) (_ *domain.Organization, err error) {
+	ctx, span := tracing.StartDB(ctx, "ServiceRepository.GetOrganizationBySlug")
+	defer func() { tracing.End(span, err) }()
+
+	organization, err := scanOrganization(r.conn(ctx).QueryRowContext(ctx, "SELECT "+organizationColumns+" FROM organizations WHERE slug = ?", slug))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return organization, err
+}
+
+// ListOrganizations retrieves every organization ordered by slug
+func (r *ServiceRepository) ListOrganizations(ctx context.Context) (_ []domain.Organization, err error) {
+	ctx, span := tracing.StartDB(ctx, "ServiceRepository.ListOrganizations")
+	defer func() { tracing.End(span, err) }()
+
+	rows, err := r.conn(ctx).QueryContext(ctx, "SELECT "+organizationColumns+" FROM organizations ORDER BY slug")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	organizations := []domain.Organization{}
+	for rows.Next() {
+		organization, err := scanOrganization(rows)
+		if err != nil {
+			return nil, err
+		}
+		organizations = append(organizations, *organization)
+	}
+	return organizations, rows.Err()
+}
+
+func scanOrganization(row scanner) (*domain.Organization, error) {
+	var organization domain.Organization
+	if err := row.Scan(&organization.ID, &organization.Slug, &organization.Name, &organization.CreatedAt); err != nil {
+		return nil, err
+	}
+	return &organization, nil
+}
@@ -0,0 +1,119 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"sort"
+	"strings"
+	"time"
+
+	"com.kong.connect/domain"
+	"com.kong.connect/tenant"
+	"com.kong.connect/tracing"
+)
+
+// recordEvent records a change event of a service in the outbox within tx,
+// so that the event is shipped if and only if the write commits. The service
+// is read within tx: record events after writes and before deletes. Nothing
+// is recorded when the service does not exist in the organization, since
+// the write then fails.
+func (r *ServiceRepository) recordEvent(ctx context.Context, tx dbtx, eventType string, serviceID int, version *domain.ServiceVersion) error {
+	if !r.outbox {
+		return nil
+	}
+
+	orgID := tenant.FromContext(ctx)
+	var service domain.Service
+	var tags sql.NullString
+	var health healthFields
+	var slo sloFields
+	var sunset sql.NullString
+	var source sourceFields
+	err := tx.QueryRowContext(ctx, `
+		SELECT s.id, s.name, s.description, s.status, s.owner, s.created_at, s.updated_at,
+			(SELECT group_concat(tag) FROM service_tags WHERE service_id = s.id), `+healthColumns+`, `+sloColumns+`, `+serviceSunsetColumn+`, `+sourceColumns+`
+		FROM services s `+healthJoin+` `+sloJoin+` `+sourceJoin+` WHERE s.id = ? AND s.org_id = ?`, serviceID, orgID,
+	).Scan(append(append(append(append([]interface{}{&service.ID, &service.Name, &service.Description, &service.Status, &service.Owner,
+		&service.CreatedAt, &service.UpdatedAt, &tags}, health.dest()...), slo.dest()...), &sunset), source.dest()...)...)
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	service.Health = health.health()
+	service.SLO = slo.slo()
+	service.Repository = source.repository()
+	if service.Sunset, err = parseSunset(sunset); err != nil {
+		return err
+	}
+	service.Tags = []string{}
+	if tags.Valid {
+		service.Tags = strings.Split(tags.String, ",")
+		sort.Strings(service.Tags)
+	}
+
+	payload, err := json.Marshal(domain.ChangeEvent{
+		Type:      eventType,
+		OrgID:     orgID,
+		ServiceID: serviceID,
+		Tags:      service.Tags,
+		Service:   &service,
+		Version:   version,
+		Timestamp: time.Now().UTC(),
+	})
+	if err != nil {
+		return err
+	}
+	_, err = tx.ExecContext(ctx,
+		"INSERT INTO event_outbox (org_id, service_id, type, payload) VALUES (?, ?, ?, ?)",
+		orgID, serviceID, eventType, string(payload),
+	)
+	return err
+}
+
+// versionInTx reads a version of a service with its environments within tx
+func versionInTx(ctx context.Context, tx dbtx, serviceID int, version string) (*domain.ServiceVersion, error) {
+	v, err := scanVersion(tx.QueryRowContext(ctx,
+		"SELECT "+versionColumns+" FROM service_versions v WHERE v.service_id = ? AND v.version = ?", serviceID, version))
+	if err != nil {
+		return nil, err
+	}
+	return &v, nil
+}
+
+// OutboxEvents retrieves up to limit recorded events of every organization
+// in the order they were recorded
+func (r *ServiceRepository) OutboxEvents(ctx context.Context, limit int) (_ []domain.OutboxEvent, err error) {
+	ctx, span := tracing.StartDB(ctx, "ServiceRepository.OutboxEvents")
+	defer func() { tracing.End(span, err) }()
+
+	rows, err := r.conn(ctx).QueryContext(ctx,
+		"SELECT id, org_id, service_id, type, payload, created_at FROM event_outbox ORDER BY id LIMIT ?", limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []domain.OutboxEvent
+	for rows.Next() {
+		var event domain.OutboxEvent
+		var payload string
+		if err := rows.Scan(&event.ID, &event.OrgID, &event.ServiceID, &event.Type, &payload, &event.CreatedAt); err != nil {
+			return nil, err
+		}
+		event.Payload = []byte(payload)
+		events = append(events, event)
+	}
+	return events, rows.Err()
+}
+
+// DeleteOutboxEvents removes the shipped events, up to and including lastID
+func (r *ServiceRepository) DeleteOutboxEvents(ctx context.Context, lastID int) (err error) {
+	ctx, span := tracing.StartDB(ctx, "ServiceRepository.DeleteOutboxEvents")
+	defer func() { tracing.End(span, err) }()
+
+	_, err = r.exec(ctx, "DELETE FROM event_outbox WHERE id <= ?", lastID)
+	return err
+}
@@ -0,0 +1,75 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"math/rand/v2"
+	"time"
+
+	"github.com/mattn/go-sqlite3"
+)
+
+const (
+	// maxBusyAttempts bounds how often a write runs while the database is busy
+	maxBusyAttempts = 5
+	// firstBusyDelay is the delay before the first retry of a write, doubling
+	// with every further one
+	firstBusyDelay = 10 * time.Millisecond
+)
+
+// isBusy reports whether err is SQLite refusing a write because another
+// connection holds a lock on the database, which passes once it is released.
+// Transactions that read before they write fail with it without waiting for
+// the busy timeout, so that two of them cannot deadlock.
+func isBusy(err error) bool {
+	var sqliteErr sqlite3.Error
+	if !errors.As(err, &sqliteErr) {
+		return false
+	}
+	return sqliteErr.Code == sqlite3.ErrBusy || sqliteErr.Code == sqlite3.ErrLocked
+}
+
+// retryBusy runs write, and runs it again while it fails because the database
+// is busy, waiting a jittered delay that doubles with every attempt. write
+// must be safe to repeat, such as a whole transaction or a single statement.
+// It gives up with the last error after maxBusyAttempts, or when ctx ends.
+// Within InTx, write runs once: only the whole transaction is safe to repeat,
+// which InTx does.
+func retryBusy[T any](ctx context.Context, write func() (T, error)) (T, error) {
+	if txFromContext(ctx) != nil {
+		return write()
+	}
+	delay := firstBusyDelay
+	for attempt := 1; ; attempt++ {
+		value, err := write()
+		if err == nil || !isBusy(err) || attempt == maxBusyAttempts {
+			return value, err
+		}
+
+		timer := time.NewTimer(delay + rand.N(delay))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return value, err
+		case <-timer.C:
+		}
+		delay *= 2
+	}
+}
+
+// retryBusyWrite is retryBusy for writes returning no result, or several
+// results they assign to variables of the caller
+func retryBusyWrite(ctx context.Context, write func() error) error {
+	_, err := retryBusy(ctx, func() (struct{}, error) {
+		return struct{}{}, write()
+	})
+	return err
+}
+
+// exec runs a single write statement, retrying it while the database is busy
+func (r *ServiceRepository) exec(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	return retryBusy(ctx, func() (sql.Result, error) {
+		return r.conn(ctx).ExecContext(ctx, query, args...)
+	})
+}
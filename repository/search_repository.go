@@ -0,0 +1,138 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"com.kong.connect/domain"
+	"com.kong.connect/fold"
+	"com.kong.connect/semver"
+	"com.kong.connect/tenant"
+	"com.kong.connect/tracing"
+)
+
+// Relevance weights used to rank search hits. A name match always outranks a
+// description-only match, and exact and prefix name matches rank highest. A
+// term naming one of the versions of a service, such as "2.1.0", ranks it
+// above a mention in its description.
+const (
+	scoreNameExact      = 100
+	scoreNamePrefix     = 60
+	scoreNameContains   = 40
+	scoreVersionHit     = 20
+	scoreDescriptionHit = 10
+)
+
+// versionMatch matches the services having the version given as argument
+const versionMatch = "EXISTS (SELECT 1 FROM service_versions v WHERE v.service_id = s.id AND v.version = ?)"
+
+// Search retrieves the services of the organization matching the given terms
+// ordered by relevance.
+// Each term contributes to the score independently, so services matching more
+// terms rank above services matching fewer.
+func (r *ServiceRepository) Search(ctx context.Context, terms []string, page, pageSize int) (_ []domain.SearchResult, _ int, err error) {
+	ctx, span := tracing.StartDB(ctx, "ServiceRepository.Search")
+	defer func() { tracing.End(span, err) }()
+
+	if len(terms) == 0 {
+		return nil, 0, nil
+	}
+
+	scoreParts := make([]string, 0, len(terms))
+	scoreArgs := []interface{}{}
+	whereParts := make([]string, 0, len(terms))
+	whereArgs := []interface{}{}
+	name, description := r.searchColumns()
+	for _, term := range terms {
+		// Versions are stored in canonical form
+		version := semver.Canonical(term)
+		term = r.SearchFold()(term)
+		escaped := escapeLike(term)
+		scoreParts = append(scoreParts, fmt.Sprintf(`(CASE
+			WHEN s.%[1]s = ? THEN %[3]d
+			WHEN s.%[1]s LIKE ? ESCAPE '\' THEN %[4]d
+			WHEN s.%[1]s LIKE ? ESCAPE '\' THEN %[5]d
+			ELSE 0 END +
+			CASE WHEN %[8]s THEN %[6]d ELSE 0 END +
+			CASE WHEN s.%[2]s LIKE ? ESCAPE '\' THEN %[7]d ELSE 0 END)`,
+			name, description, scoreNameExact, scoreNamePrefix, scoreNameContains, scoreVersionHit, scoreDescriptionHit, versionMatch))
+		scoreArgs = append(scoreArgs, term, escaped+"%", "%"+escaped+"%", version, "%"+escaped+"%")
+
+		whereParts = append(whereParts, fmt.Sprintf(`s.%s LIKE ? ESCAPE '\' OR s.%s LIKE ? ESCAPE '\' OR %s`, name, description, versionMatch))
+		whereArgs = append(whereArgs, "%"+escaped+"%", "%"+escaped+"%", version)
+	}
+	whereClause := "WHERE s.org_id = ? AND (" + strings.Join(whereParts, " OR ") + ")"
+	whereArgs = append([]interface{}{tenant.FromContext(ctx)}, whereArgs...)
+
+	// Get total count
+	var total int
+	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM services s %s", whereClause)
+	if err := r.conn(ctx).QueryRowContext(ctx, countQuery, whereArgs...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	args := append(scoreArgs, whereArgs...)
+	args = append(args, pageSize, (page-1)*pageSize)
+	searchQuery := fmt.Sprintf(`
+		SELECT s.id, s.name, s.description, s.status, s.owner, s.created_at, s.updated_at, %s AS score
+		FROM services s
+		%s
+		ORDER BY score DESC, s.name ASC
+		LIMIT ? OFFSET ?`, strings.Join(scoreParts, " + "), whereClause)
+
+	rows, err := r.conn(ctx).QueryContext(ctx, searchQuery, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var results []domain.SearchResult
+	for rows.Next() {
+		var result domain.SearchResult
+		err := rows.Scan(&result.Service.ID, &result.Service.Name, &result.Service.Description,
+			&result.Service.Status, &result.Service.Owner, &result.Service.CreatedAt,
+			&result.Service.UpdatedAt, &result.Score)
+		if err != nil {
+			return nil, 0, err
+		}
+		results = append(results, result)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+	rows.Close()
+
+	for i := range results {
+		results[i].Service.Tags, err = r.getTagsByServiceID(ctx, results[i].Service.ID)
+		if err != nil {
+			return nil, 0, err
+		}
+	}
+
+	return results, total, nil
+}
+
+// searchColumns returns the columns of services searched instead of their name
+// and description, which the database keeps folded for search
+func (r *ServiceRepository) searchColumns() (name, description string) {
+	if r.accentSensitive {
+		return "folded_name", "folded_description"
+	}
+	return "unaccented_name", "unaccented_description"
+}
+
+// SearchFold returns how search terms are folded to be compared with the
+// services, e.g. to highlight the matches
+func (r *ServiceRepository) SearchFold() func(string) string {
+	if r.accentSensitive {
+		return fold.Case
+	}
+	return fold.Accents
+}
+
+// escapeLike escapes the LIKE wildcards in a user supplied term
+func escapeLike(term string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+	return replacer.Replace(term)
+}
@@ -1,36 +1,101 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
+	"sort"
+	"strconv"
 	"strings"
 
 	"com.kong.connect/domain"
+	"com.kong.connect/semver"
+	"com.kong.connect/tenant"
+	"com.kong.connect/tracing"
 )
 
 // ServiceRepository handles database operations for services
 type ServiceRepository struct {
 	db *sql.DB
+	// outbox records the change events of writes in event_outbox
+	outbox bool
+	// accentSensitive searches the folded columns of services, which keep
+	// their accents, instead of the unaccented ones
+	accentSensitive bool
+}
+
+// Option configures a ServiceRepository
+type Option func(*ServiceRepository)
+
+// WithOutbox records the change event of every catalog write in the
+// event_outbox table, in the transaction of the write
+func WithOutbox() Option {
+	return func(r *ServiceRepository) {
+		r.outbox = true
+	}
+}
+
+// WithAccentSensitiveSearch makes searches tell accented letters apart, so
+// that "Securite" no longer finds "Sécurité". Case is ignored either way.
+func WithAccentSensitiveSearch() Option {
+	return func(r *ServiceRepository) {
+		r.accentSensitive = true
+	}
 }
 
 // NewServiceRepository creates a new service repository
-func NewServiceRepository(db *sql.DB) *ServiceRepository {
-	return &ServiceRepository{db: db}
+func NewServiceRepository(db *sql.DB, opts ...Option) *ServiceRepository {
+	r := &ServiceRepository{db: db}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
 }
 
 // GetAll retrieves all services with pagination, filtering, and sorting
-func (r *ServiceRepository) GetAll(query domain.ServiceQuery) ([]domain.ServiceWithVersions, int, error) {
-	// Build the WHERE clause for search
-	whereClause := ""
-	args := []interface{}{}
+func (r *ServiceRepository) GetAll(ctx context.Context, query domain.ServiceQuery) (_ []domain.ServiceWithVersions, _ int, err error) {
+	ctx, span := tracing.StartDB(ctx, "ServiceRepository.GetAll")
+	defer func() { tracing.End(span, err) }()
+
+	// Build the WHERE clause for the organization and search
+	orgID := tenant.FromContext(ctx)
+	whereClause := "WHERE s.org_id = ?"
+	args := []interface{}{orgID}
 	if query.Search != "" {
-		whereClause = "WHERE s.name LIKE ? OR s.description LIKE ?"
-		searchTerm := "%" + query.Search + "%"
-		args = append(args, searchTerm, searchTerm)
+		name, description := r.searchColumns()
+		// instr matches the search literally, unlike LIKE, to which % and _
+		// are wildcards and a NUL character ends the pattern
+		whereClause += fmt.Sprintf(" AND (instr(s.%s, ?) > 0 OR instr(s.%s, ?) > 0 OR %s)", name, description, versionMatch)
+		searchTerm := r.SearchFold()(query.Search)
+		args = append(args, searchTerm, searchTerm, semver.Canonical(query.Search))
+	}
+	if query.Environment != "" {
+		whereClause += " AND EXISTS (SELECT 1 FROM service_environments e WHERE e.service_id = s.id AND e.environment = ?)"
+		args = append(args, query.Environment)
+	}
+	minVersions := query.MinVersions
+	if query.HasVersions != nil {
+		if *query.HasVersions {
+			minVersions = max(minVersions, 1)
+		} else {
+			whereClause += " AND s.id NOT IN (SELECT service_id FROM service_versions)"
+		}
+	}
+	if minVersions > 0 {
+		whereClause += " AND s.id IN (SELECT service_id FROM service_versions GROUP BY service_id HAVING COUNT(*) >= ?)"
+		args = append(args, minVersions)
+	}
+	if query.Favorites {
+		whereClause += " AND EXISTS (SELECT 1 FROM service_favorites f WHERE f.service_id = s.id AND f.username = ?)"
+		args = append(args, query.User)
 	}
 
-	// Build ORDER BY clause
-	orderBy := "s.name ASC" // default
+	// Build ORDER BY clause. The ID breaks ties, such as services created in
+	// the same second, so that pages neither repeat nor skip services; it
+	// follows the sort direction for the indexes, which end with it, to serve
+	// the order.
+	orderBy := "s.name ASC, s.id ASC" // default
 	if query.SortBy != "" {
 		direction := "ASC"
 		if strings.ToUpper(query.SortDir) == "DESC" {
@@ -39,18 +104,26 @@ func (r *ServiceRepository) GetAll(query domain.ServiceQuery) ([]domain.ServiceW
 
 		switch query.SortBy {
 		case "name":
-			orderBy = fmt.Sprintf("s.name %s", direction)
+			orderBy = fmt.Sprintf("s.name %[1]s, s.id %[1]s", direction)
 		case "created_at":
-			orderBy = fmt.Sprintf("s.created_at %s", direction)
+			orderBy = fmt.Sprintf("s.created_at %[1]s, s.id %[1]s", direction)
 		case "updated_at":
-			orderBy = fmt.Sprintf("s.updated_at %s", direction)
+			orderBy = fmt.Sprintf("s.updated_at %[1]s, s.id %[1]s", direction)
 		}
 	}
 
-	// Get total count
-	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM services s %s", whereClause)
+	// Get total count; unfiltered listings read the count kept by triggers
+	// instead of counting every row
 	var total int
-	err := r.db.QueryRow(countQuery, args...).Scan(&total)
+	if query.Search == "" && query.Environment == "" && query.HasVersions == nil && query.MinVersions == 0 && !query.Favorites {
+		err = r.conn(ctx).QueryRowContext(ctx, "SELECT count FROM row_counts WHERE name = ?", "services:"+strconv.Itoa(orgID)).Scan(&total)
+		if err == sql.ErrNoRows {
+			// Organizations get a count with their first service
+			err = nil
+		}
+	} else {
+		err = r.conn(ctx).QueryRowContext(ctx, fmt.Sprintf("SELECT COUNT(*) FROM services s %s", whereClause), args...).Scan(&total)
+	}
 	if err != nil {
 		return nil, 0, err
 	}
@@ -62,13 +135,13 @@ func (r *ServiceRepository) GetAll(query domain.ServiceQuery) ([]domain.ServiceW
 
 	// Get services
 	servicesQuery := fmt.Sprintf(`
-		SELECT s.id, s.name, s.description, s.created_at, s.updated_at 
-		FROM services s 
+		SELECT s.id, s.name, s.description, s.status, s.owner, s.created_at, s.updated_at, %s, %s, %s, %s, %s
+		FROM services s %s %s %s
 		%s 
 		ORDER BY %s 
-		%s`, whereClause, orderBy, limitOffset)
+		%s`, healthColumns, sloColumns, serviceSunsetColumn, sourceColumns, translationsColumn, healthJoin, sloJoin, sourceJoin, whereClause, orderBy, limitOffset)
 
-	rows, err := r.db.Query(servicesQuery, args...)
+	rows, err := r.conn(ctx).QueryContext(ctx, servicesQuery, args...)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -77,49 +150,100 @@ func (r *ServiceRepository) GetAll(query domain.ServiceQuery) ([]domain.ServiceW
 	var services []domain.ServiceWithVersions
 	for rows.Next() {
 		var service domain.Service
-		err := rows.Scan(&service.ID, &service.Name, &service.Description,
-			&service.CreatedAt, &service.UpdatedAt)
+		var health healthFields
+		var slo sloFields
+		var sunset sql.NullString
+		var source sourceFields
+		var translations sql.NullString
+		err := rows.Scan(append(append(append(append(append([]interface{}{&service.ID, &service.Name, &service.Description,
+			&service.Status, &service.Owner, &service.CreatedAt, &service.UpdatedAt}, health.dest()...), slo.dest()...), &sunset), source.dest()...), &translations)...)
 		if err != nil {
 			return nil, 0, err
 		}
-
-		// Get versions for this service
-		versions, err := r.getVersionsByServiceID(service.ID)
-		if err != nil {
+		service.Health = health.health()
+		service.SLO = slo.slo()
+		service.Repository = source.repository()
+		if service.Sunset, err = parseSunset(sunset); err != nil {
+			return nil, 0, err
+		}
+		if service.Translations, err = parseTranslations(translations); err != nil {
 			return nil, 0, err
 		}
 
-		serviceWithVersions := domain.ServiceWithVersions{
-			Service:  service,
-			Versions: versions,
+		services = append(services, domain.ServiceWithVersions{Service: service})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	// Get the tags and versions of the whole page at once
+	ids := make([]int, len(services))
+	for i := range services {
+		ids[i] = services[i].ID
+	}
+	tags, err := r.getTagsByServiceIDs(ctx, ids)
+	if err != nil {
+		return nil, 0, err
+	}
+	versions, err := r.getVersionsByServiceIDs(ctx, ids)
+	if err != nil {
+		return nil, 0, err
+	}
+	for i := range services {
+		services[i].Tags = tags[services[i].ID]
+		if services[i].Tags == nil {
+			services[i].Tags = []string{}
 		}
-		services = append(services, serviceWithVersions)
+		services[i].Versions = versions[services[i].ID]
 	}
 
 	return services, total, nil
 }
 
-// GetByID retrieves a service by ID with its versions
-func (r *ServiceRepository) GetByID(id int) (*domain.ServiceWithVersions, error) {
+// GetByID retrieves a service by ID with its versions; services of other
+// organizations are not found
+func (r *ServiceRepository) GetByID(ctx context.Context, id int) (_ *domain.ServiceWithVersions, err error) {
+	ctx, span := tracing.StartDB(ctx, "ServiceRepository.GetByID")
+	defer func() { tracing.End(span, err) }()
+
 	query := `
-		SELECT id, name, description, created_at, updated_at 
-		FROM services 
-		WHERE id = ?`
+		SELECT s.id, s.name, s.description, s.status, s.owner, s.created_at, s.updated_at, ` + healthColumns + `, ` + sloColumns + `, ` + serviceSunsetColumn + `, ` + sourceColumns + `, ` + translationsColumn + `
+		FROM services s ` + healthJoin + ` ` + sloJoin + ` ` + sourceJoin + `
+		WHERE s.id = ? AND s.org_id = ?`
 
 	var service domain.Service
-	err := r.db.QueryRow(query, id).Scan(
+	var health healthFields
+	var slo sloFields
+	var sunset sql.NullString
+	var source sourceFields
+	var translations sql.NullString
+	err = r.conn(ctx).QueryRowContext(ctx, query, id, tenant.FromContext(ctx)).Scan(append(append(append(append(append([]interface{}{
 		&service.ID, &service.Name, &service.Description,
-		&service.CreatedAt, &service.UpdatedAt,
-	)
+		&service.Status, &service.Owner, &service.CreatedAt, &service.UpdatedAt,
+	}, health.dest()...), slo.dest()...), &sunset), source.dest()...), &translations)...)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil // Service not found
 		}
 		return nil, err
 	}
+	service.Health = health.health()
+	service.SLO = slo.slo()
+	service.Repository = source.repository()
+	if service.Sunset, err = parseSunset(sunset); err != nil {
+		return nil, err
+	}
+	if service.Translations, err = parseTranslations(translations); err != nil {
+		return nil, err
+	}
+
+	service.Tags, err = r.getTagsByServiceID(ctx, service.ID)
+	if err != nil {
+		return nil, err
+	}
 
 	// Get versions
-	versions, err := r.getVersionsByServiceID(service.ID)
+	versions, err := r.getVersionsByServiceID(ctx, service.ID)
 	if err != nil {
 		return nil, err
 	}
@@ -132,15 +256,46 @@ func (r *ServiceRepository) GetByID(id int) (*domain.ServiceWithVersions, error)
 	return result, nil
 }
 
-// getVersionsByServiceID retrieves all versions for a service
-func (r *ServiceRepository) getVersionsByServiceID(serviceID int) ([]domain.ServiceVersion, error) {
+// versionColumns selects a version of service_versions v with the
+// environments it is deployed to and the metadata of its spec, for
+// scanVersion
+const versionColumns = `v.id, v.service_id, v.version, v.original_version, v.created_at,
+	(SELECT group_concat(e.environment) FROM service_environments e WHERE e.version_id = v.id),
+	(SELECT sp.metadata FROM version_specs sp WHERE sp.version_id = v.id), ` + versionSunsetColumn
+
+// scanVersion scans a row of versionColumns
+func scanVersion(row scanner) (domain.ServiceVersion, error) {
+	var version domain.ServiceVersion
+	var original, environments, spec, sunset sql.NullString
+	if err := row.Scan(&version.ID, &version.ServiceID, &version.Version, &original, &version.CreatedAt, &environments, &spec, &sunset); err != nil {
+		return version, err
+	}
+	version.OriginalVersion = original.String
+	if environments.Valid {
+		version.Environments = strings.Split(environments.String, ",")
+		sort.Strings(version.Environments)
+	}
+	if spec.Valid {
+		version.Spec = &domain.SpecMetadata{}
+		if err := json.Unmarshal([]byte(spec.String), version.Spec); err != nil {
+			return version, err
+		}
+	}
+	var err error
+	version.Sunset, err = parseSunset(sunset)
+	return version, err
+}
+
+// getVersionsByServiceID retrieves all versions for a service, newest first;
+// versions created in the same second keep the order they were added in
+func (r *ServiceRepository) getVersionsByServiceID(ctx context.Context, serviceID int) ([]domain.ServiceVersion, error) {
 	query := `
-		SELECT id, service_id, version, created_at 
-		FROM service_versions 
-		WHERE service_id = ? 
-		ORDER BY created_at DESC`
+		SELECT ` + versionColumns + `
+		FROM service_versions v
+		WHERE v.service_id = ?
+		ORDER BY v.created_at DESC, v.id ASC`
 
-	rows, err := r.db.Query(query, serviceID)
+	rows, err := r.conn(ctx).QueryContext(ctx, query, serviceID)
 	if err != nil {
 		return nil, err
 	}
@@ -148,8 +303,7 @@ func (r *ServiceRepository) getVersionsByServiceID(serviceID int) ([]domain.Serv
 
 	var versions []domain.ServiceVersion
 	for rows.Next() {
-		var version domain.ServiceVersion
-		err := rows.Scan(&version.ID, &version.ServiceID, &version.Version, &version.CreatedAt)
+		version, err := scanVersion(rows)
 		if err != nil {
 			return nil, err
 		}
@@ -158,3 +312,91 @@ func (r *ServiceRepository) getVersionsByServiceID(serviceID int) ([]domain.Serv
 
 	return versions, nil
 }
+
+// getVersionsByServiceIDs retrieves the versions of several services in one
+// query, grouped by service and ordered as getVersionsByServiceID orders them
+func (r *ServiceRepository) getVersionsByServiceIDs(ctx context.Context, serviceIDs []int) (map[int][]domain.ServiceVersion, error) {
+	if len(serviceIDs) == 0 {
+		return nil, nil
+	}
+
+	args := make([]interface{}, len(serviceIDs))
+	for i, id := range serviceIDs {
+		args[i] = id
+	}
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM service_versions v
+		WHERE v.service_id IN (%s)
+		ORDER BY v.service_id, v.created_at DESC, v.id ASC`, versionColumns, strings.TrimSuffix(strings.Repeat("?,", len(serviceIDs)), ","))
+
+	rows, err := r.conn(ctx).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	versions := make(map[int][]domain.ServiceVersion, len(serviceIDs))
+	for rows.Next() {
+		version, err := scanVersion(rows)
+		if err != nil {
+			return nil, err
+		}
+		versions[version.ServiceID] = append(versions[version.ServiceID], version)
+	}
+
+	return versions, rows.Err()
+}
+
+// getTagsByServiceID retrieves the tags of a service in alphabetical order
+func (r *ServiceRepository) getTagsByServiceID(ctx context.Context, serviceID int) ([]string, error) {
+	rows, err := r.conn(ctx).QueryContext(ctx, "SELECT tag FROM service_tags WHERE service_id = ? ORDER BY tag", serviceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tags := []string{}
+	for rows.Next() {
+		var tag string
+		if err := rows.Scan(&tag); err != nil {
+			return nil, err
+		}
+		tags = append(tags, tag)
+	}
+
+	return tags, rows.Err()
+}
+
+// getTagsByServiceIDs retrieves the tags of several services in one query,
+// grouped by service in alphabetical order
+func (r *ServiceRepository) getTagsByServiceIDs(ctx context.Context, serviceIDs []int) (map[int][]string, error) {
+	if len(serviceIDs) == 0 {
+		return nil, nil
+	}
+
+	args := make([]interface{}, len(serviceIDs))
+	for i, id := range serviceIDs {
+		args[i] = id
+	}
+	query := fmt.Sprintf("SELECT service_id, tag FROM service_tags WHERE service_id IN (%s) ORDER BY service_id, tag",
+		strings.TrimSuffix(strings.Repeat("?,", len(serviceIDs)), ","))
+
+	rows, err := r.conn(ctx).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tags := make(map[int][]string, len(serviceIDs))
+	for rows.Next() {
+		var serviceID int
+		var tag string
+		if err := rows.Scan(&serviceID, &tag); err != nil {
+			return nil, err
+		}
+		tags[serviceID] = append(tags[serviceID], tag)
+	}
+
+	return tags, rows.Err()
+}
@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"com.kong.connect/database"
+	"com.kong.connect/domain"
+)
+
+// benchmarkSizes are the catalog sizes the benchmarks run against; -short
+// skips the large one, which takes a while to seed
+var benchmarkSizes = []int{1_000, 100_000}
+
+// newBenchmarkRepository seeds a generated catalog of size services
+func newBenchmarkRepository(b *testing.B, size int) *ServiceRepository {
+	b.Helper()
+	db, err := database.Open(filepath.Join(b.TempDir(), "bench.db"))
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.Cleanup(func() { db.Close() })
+	if err := database.Migrate(db); err != nil {
+		b.Fatal(err)
+	}
+
+	services, err := database.Generate(database.GenerateOptions{Services: size, MaxVersions: 5, Seed: 1})
+	if err != nil {
+		b.Fatal(err)
+	}
+	if _, err := database.SeedServices(db, services); err != nil {
+		b.Fatal(err)
+	}
+	return NewServiceRepository(db)
+}
+
+func BenchmarkServiceRepository(b *testing.B) {
+	ctx := context.Background()
+	for _, size := range benchmarkSizes {
+		b.Run(fmt.Sprintf("services=%d", size), func(b *testing.B) {
+			if size > 1_000 && testing.Short() {
+				b.Skip("large catalog skipped in short mode")
+			}
+			repo := newBenchmarkRepository(b, size)
+
+			listings := []struct {
+				name  string
+				query domain.ServiceQuery
+			}{
+				{"GetAll/first-page", domain.ServiceQuery{Page: 1, PageSize: 12, SortBy: "name", SortDir: "asc"}},
+				{"GetAll/last-page", domain.ServiceQuery{Page: size / 12, PageSize: 12, SortBy: "name", SortDir: "asc"}},
+				{"GetAll/search", domain.ServiceQuery{Search: "payments", Page: 1, PageSize: 12}},
+				{"GetAll/sort-updated", domain.ServiceQuery{Page: 1, PageSize: 100, SortBy: "updated_at", SortDir: "desc"}},
+			}
+			for _, listing := range listings {
+				b.Run(listing.name, func(b *testing.B) {
+					b.ReportAllocs()
+					for b.Loop() {
+						if _, _, err := repo.GetAll(ctx, listing.query); err != nil {
+							b.Fatal(err)
+						}
+					}
+				})
+			}
+
+			b.Run("GetByID", func(b *testing.B) {
+				b.ReportAllocs()
+				id := 0
+				for b.Loop() {
+					id = id%size + 1
+					service, err := repo.GetByID(ctx, id)
+					if err != nil || service == nil {
+						b.Fatalf("service %d: %v", id, err)
+					}
+				}
+			})
+		})
+	}
+}
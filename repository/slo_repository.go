@@ -0,0 +1,138 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"com.kong.connect/domain"
+	"com.kong.connect/tenant"
+	"com.kong.connect/tracing"
+)
+
+// sloColumns selects the SLOs of the service s from service_slos o, joined
+// by sloJoin, for sloFields
+const (
+	sloColumns = "o.availability_target, o.latency_target_ms"
+	sloJoin    = "LEFT JOIN service_slos o ON o.service_id = s.id"
+)
+
+// sloFields scans sloColumns; every column is NULL for services without SLOs
+type sloFields struct {
+	availability sql.NullFloat64
+	latency      sql.NullInt64
+}
+
+func (f *sloFields) dest() []interface{} {
+	return []interface{}{&f.availability, &f.latency}
+}
+
+// slo returns the scanned SLOs, or nil
+func (f *sloFields) slo() *domain.ServiceSLO {
+	if !f.availability.Valid {
+		return nil
+	}
+	return &domain.ServiceSLO{AvailabilityTarget: f.availability.Float64, LatencyTargetMS: f.latency.Int64}
+}
+
+// SetSLO sets the SLOs of a service, replacing those it had. It returns
+// false when the service does not exist in the organization.
+func (r *ServiceRepository) SetSLO(ctx context.Context, serviceID int, slo domain.ServiceSLO) (_ bool, err error) {
+	ctx, span := tracing.StartDB(ctx, "ServiceRepository.SetSLO")
+	defer func() { tracing.End(span, err) }()
+
+	return retryBusy(ctx, func() (bool, error) {
+		tx, err := r.begin(ctx)
+		if err != nil {
+			return false, err
+		}
+		defer tx.Rollback()
+
+		orgID := tenant.FromContext(ctx)
+		if found, err := touchService(ctx, tx, orgID, serviceID); err != nil || !found {
+			return false, err
+		}
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO service_slos (service_id, org_id, availability_target, latency_target_ms, updated_at) VALUES (?, ?, ?, ?, ?)
+			ON CONFLICT (service_id) DO UPDATE SET availability_target = excluded.availability_target,
+				latency_target_ms = excluded.latency_target_ms, updated_at = excluded.updated_at`,
+			serviceID, orgID, slo.AvailabilityTarget, slo.LatencyTargetMS, time.Now().UTC().Format(auditTimeFormat),
+		); err != nil {
+			return false, err
+		}
+		if err := r.recordEvent(ctx, tx, domain.EventServiceUpdated, serviceID, nil); err != nil {
+			return false, err
+		}
+
+		return true, tx.Commit()
+	})
+}
+
+// DeleteSLO removes the SLOs of a service. It returns false when the service
+// does not exist in the organization or has no SLOs.
+func (r *ServiceRepository) DeleteSLO(ctx context.Context, serviceID int) (_ bool, err error) {
+	ctx, span := tracing.StartDB(ctx, "ServiceRepository.DeleteSLO")
+	defer func() { tracing.End(span, err) }()
+
+	return retryBusy(ctx, func() (bool, error) {
+		tx, err := r.begin(ctx)
+		if err != nil {
+			return false, err
+		}
+		defer tx.Rollback()
+
+		orgID := tenant.FromContext(ctx)
+		if found, err := touchService(ctx, tx, orgID, serviceID); err != nil || !found {
+			return false, err
+		}
+		result, err := tx.ExecContext(ctx, "DELETE FROM service_slos WHERE service_id = ? AND org_id = ?", serviceID, orgID)
+		if err != nil {
+			return false, err
+		}
+		if affected, err := result.RowsAffected(); err != nil || affected == 0 {
+			return false, err
+		}
+		if err := r.recordEvent(ctx, tx, domain.EventServiceUpdated, serviceID, nil); err != nil {
+			return false, err
+		}
+
+		return true, tx.Commit()
+	})
+}
+
+// GetSLOProbeCounts counts the probes recorded during the window of the
+// query for every service of the organization with SLOs, by service name
+func (r *ServiceRepository) GetSLOProbeCounts(ctx context.Context, query domain.SLOReportQuery) (_ []domain.SLOProbeCounts, err error) {
+	ctx, span := tracing.StartDB(ctx, "ServiceRepository.GetSLOProbeCounts")
+	defer func() { tracing.End(span, err) }()
+
+	orgID := tenant.FromContext(ctx)
+	rows, err := r.conn(ctx).QueryContext(ctx, `
+		SELECT s.id, s.name, o.availability_target, o.latency_target_ms, COUNT(r.id),
+			COALESCE(SUM(r.status = ?), 0),
+			COALESCE(SUM(r.status = ? AND o.latency_target_ms > 0 AND r.latency_ms <= o.latency_target_ms), 0)
+		FROM service_slos o
+		JOIN services s ON s.id = o.service_id AND s.org_id = ?
+		LEFT JOIN service_health_results r ON r.service_id = s.id AND r.checked_at >= ? AND r.checked_at < ?
+		WHERE o.org_id = ?
+		GROUP BY s.id, s.name, o.availability_target, o.latency_target_ms
+		ORDER BY s.name, s.id`,
+		domain.HealthUp, domain.HealthUp, orgID,
+		query.Since.UTC().Format(auditTimeFormat), query.Until.UTC().Format(auditTimeFormat), orgID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := []domain.SLOProbeCounts{}
+	for rows.Next() {
+		var c domain.SLOProbeCounts
+		if err := rows.Scan(&c.ServiceID, &c.ServiceName, &c.SLO.AvailabilityTarget, &c.SLO.LatencyTargetMS,
+			&c.Probes, &c.UpProbes, &c.FastProbes); err != nil {
+			return nil, err
+		}
+		counts = append(counts, c)
+	}
+	return counts, rows.Err()
+}
@@ -0,0 +1,202 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"com.kong.connect/domain"
+	"com.kong.connect/tenant"
+	"com.kong.connect/tracing"
+)
+
+// sourceColumns selects the source repository of the service s from
+// service_repositories g, joined by sourceJoin, for sourceFields
+const (
+	sourceColumns = "g.url, g.provider, g.path, g.default_branch, g.release_tag, g.release_name, g.release_url, g.release_published_at, g.error, g.refreshed_at"
+	sourceJoin    = "LEFT JOIN service_repositories g ON g.service_id = s.id"
+)
+
+// sourceFields scans sourceColumns; every column is NULL for services
+// without a linked repository
+type sourceFields struct {
+	url, provider, path, defaultBranch  sql.NullString
+	releaseTag, releaseName, releaseURL sql.NullString
+	error                               sql.NullString
+	releasePublishedAt, refreshedAt     sql.NullTime
+}
+
+func (f *sourceFields) dest() []interface{} {
+	return []interface{}{&f.url, &f.provider, &f.path, &f.defaultBranch, &f.releaseTag, &f.releaseName, &f.releaseURL,
+		&f.releasePublishedAt, &f.error, &f.refreshedAt}
+}
+
+// repository returns the scanned source repository, or nil
+func (f *sourceFields) repository() *domain.SourceRepository {
+	if !f.url.Valid {
+		return nil
+	}
+	repository := &domain.SourceRepository{URL: f.url.String, Provider: f.provider.String, Path: f.path.String,
+		DefaultBranch: f.defaultBranch.String, Error: f.error.String}
+	if f.releaseTag.String != "" {
+		repository.LatestRelease = &domain.Release{Tag: f.releaseTag.String, Name: f.releaseName.String, URL: f.releaseURL.String}
+		if f.releasePublishedAt.Valid {
+			publishedAt := f.releasePublishedAt.Time
+			repository.LatestRelease.PublishedAt = &publishedAt
+		}
+	}
+	if f.refreshedAt.Valid {
+		refreshedAt := f.refreshedAt.Time
+		repository.RefreshedAt = &refreshedAt
+	}
+	return repository
+}
+
+// SetSourceRepository links a service to a source repository, replacing the
+// repository it was linked to; its details are empty until the next
+// refresh. It returns false when the service does not exist in the
+// organization.
+func (r *ServiceRepository) SetSourceRepository(ctx context.Context, serviceID int, repository domain.SourceRepository) (_ bool, err error) {
+	ctx, span := tracing.StartDB(ctx, "ServiceRepository.SetSourceRepository")
+	defer func() { tracing.End(span, err) }()
+
+	return retryBusy(ctx, func() (bool, error) {
+		tx, err := r.begin(ctx)
+		if err != nil {
+			return false, err
+		}
+		defer tx.Rollback()
+
+		orgID := tenant.FromContext(ctx)
+		if found, err := touchService(ctx, tx, orgID, serviceID); err != nil || !found {
+			return false, err
+		}
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO service_repositories (service_id, org_id, url, provider, path) VALUES (?, ?, ?, ?, ?)
+			ON CONFLICT (service_id) DO UPDATE SET url = excluded.url, provider = excluded.provider, path = excluded.path,
+				default_branch = '', release_tag = '', release_name = '', release_url = '', release_published_at = NULL,
+				error = '', refreshed_at = NULL`,
+			serviceID, orgID, repository.URL, repository.Provider, repository.Path,
+		); err != nil {
+			return false, err
+		}
+		if err := r.recordEvent(ctx, tx, domain.EventServiceUpdated, serviceID, nil); err != nil {
+			return false, err
+		}
+
+		return true, tx.Commit()
+	})
+}
+
+// DeleteSourceRepository unlinks the source repository of a service. It
+// returns false when the service does not exist in the organization or has
+// no linked repository.
+func (r *ServiceRepository) DeleteSourceRepository(ctx context.Context, serviceID int) (_ bool, err error) {
+	ctx, span := tracing.StartDB(ctx, "ServiceRepository.DeleteSourceRepository")
+	defer func() { tracing.End(span, err) }()
+
+	return retryBusy(ctx, func() (bool, error) {
+		tx, err := r.begin(ctx)
+		if err != nil {
+			return false, err
+		}
+		defer tx.Rollback()
+
+		orgID := tenant.FromContext(ctx)
+		if found, err := touchService(ctx, tx, orgID, serviceID); err != nil || !found {
+			return false, err
+		}
+		result, err := tx.ExecContext(ctx, "DELETE FROM service_repositories WHERE service_id = ? AND org_id = ?", serviceID, orgID)
+		if err != nil {
+			return false, err
+		}
+		if affected, err := result.RowsAffected(); err != nil || affected == 0 {
+			return false, err
+		}
+		if err := r.recordEvent(ctx, tx, domain.EventServiceUpdated, serviceID, nil); err != nil {
+			return false, err
+		}
+
+		return true, tx.Commit()
+	})
+}
+
+// LinkedRepositories retrieves the linked source repositories of every
+// organization
+func (r *ServiceRepository) LinkedRepositories(ctx context.Context) (_ []domain.LinkedRepository, err error) {
+	ctx, span := tracing.StartDB(ctx, "ServiceRepository.LinkedRepositories")
+	defer func() { tracing.End(span, err) }()
+
+	rows, err := r.conn(ctx).QueryContext(ctx, "SELECT org_id, service_id, url, provider, path FROM service_repositories ORDER BY service_id")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var repositories []domain.LinkedRepository
+	for rows.Next() {
+		var repository domain.LinkedRepository
+		if err := rows.Scan(&repository.OrgID, &repository.ServiceID, &repository.URL, &repository.Provider, &repository.Path); err != nil {
+			return nil, err
+		}
+		repositories = append(repositories, repository)
+	}
+	return repositories, rows.Err()
+}
+
+// RecordRepositoryDetails stores the outcome of a refresh of the source
+// repository of a service of the organization; a failed refresh keeps the
+// details of the refresh before. Refreshes of a repository unlinked or
+// replaced since they started are not recorded, and return false. The
+// service is updated when its details changed.
+func (r *ServiceRepository) RecordRepositoryDetails(ctx context.Context, details domain.RepositoryDetails, changed bool) (_ bool, err error) {
+	ctx, span := tracing.StartDB(ctx, "ServiceRepository.RecordRepositoryDetails")
+	defer func() { tracing.End(span, err) }()
+
+	return retryBusy(ctx, func() (bool, error) {
+		tx, err := r.begin(ctx)
+		if err != nil {
+			return false, err
+		}
+		defer tx.Rollback()
+
+		orgID := tenant.FromContext(ctx)
+		refreshedAt := details.RefreshedAt.UTC().Format(auditTimeFormat)
+		var result sql.Result
+		if details.Error != "" {
+			result, err = tx.ExecContext(ctx, `
+				UPDATE service_repositories SET error = ?, refreshed_at = ? WHERE service_id = ? AND org_id = ? AND url = ?`,
+				details.Error, refreshedAt, details.ServiceID, orgID, details.URL)
+		} else {
+			var release domain.Release
+			var publishedAt interface{}
+			if details.LatestRelease != nil {
+				release = *details.LatestRelease
+				if release.PublishedAt != nil {
+					publishedAt = release.PublishedAt.UTC().Format(auditTimeFormat)
+				}
+			}
+			result, err = tx.ExecContext(ctx, `
+				UPDATE service_repositories SET default_branch = ?, release_tag = ?, release_name = ?, release_url = ?,
+					release_published_at = ?, error = '', refreshed_at = ?
+				WHERE service_id = ? AND org_id = ? AND url = ?`,
+				details.DefaultBranch, release.Tag, release.Name, release.URL, publishedAt, refreshedAt,
+				details.ServiceID, orgID, details.URL)
+		}
+		if err != nil {
+			return false, err
+		}
+		if affected, err := result.RowsAffected(); err != nil || affected == 0 {
+			return false, err
+		}
+		if changed {
+			if found, err := touchService(ctx, tx, orgID, details.ServiceID); err != nil || !found {
+				return false, err
+			}
+			if err := r.recordEvent(ctx, tx, domain.EventServiceUpdated, details.ServiceID, nil); err != nil {
+				return false, err
+			}
+		}
+
+		return true, tx.Commit()
+	})
+}
@@ -0,0 +1,122 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+
+	"com.kong.connect/domain"
+	"com.kong.connect/tenant"
+	"com.kong.connect/tracing"
+)
+
+// SaveVersionSpec attaches an OpenAPI spec to a version, replacing the one
+// it had, and returns the version. It returns nil when the service does not
+// exist in the organization or the version does not belong to it.
+func (r *ServiceRepository) SaveVersionSpec(ctx context.Context, serviceID, versionID int, spec domain.VersionSpec) (_ *domain.ServiceVersion, err error) {
+	ctx, span := tracing.StartDB(ctx, "ServiceRepository.SaveVersionSpec")
+	defer func() { tracing.End(span, err) }()
+
+	metadata, err := json.Marshal(spec.Metadata)
+	if err != nil {
+		return nil, err
+	}
+
+	return retryBusy(ctx, func() (*domain.ServiceVersion, error) {
+		tx, err := r.begin(ctx)
+		if err != nil {
+			return nil, err
+		}
+		defer tx.Rollback()
+
+		if found, err := touchService(ctx, tx, tenant.FromContext(ctx), serviceID); err != nil || !found {
+			return nil, err
+		}
+		result, err := tx.ExecContext(ctx, `
+			INSERT INTO version_specs (version_id, service_id, content, content_type, metadata)
+			SELECT id, service_id, ?, ?, ? FROM service_versions WHERE id = ? AND service_id = ?
+			ON CONFLICT (version_id) DO UPDATE SET content = excluded.content, content_type = excluded.content_type,
+				metadata = excluded.metadata`,
+			spec.Content, spec.ContentType, string(metadata), versionID, serviceID,
+		)
+		if err != nil {
+			return nil, err
+		}
+		if affected, err := result.RowsAffected(); err != nil || affected == 0 {
+			return nil, err
+		}
+
+		version, err := scanVersion(tx.QueryRowContext(ctx, "SELECT "+versionColumns+" FROM service_versions v WHERE v.id = ?", versionID))
+		if err != nil {
+			return nil, err
+		}
+		if err := r.recordEvent(ctx, tx, domain.EventVersionSpecUpdated, serviceID, &version); err != nil {
+			return nil, err
+		}
+
+		return &version, tx.Commit()
+	})
+}
+
+// GetVersionSpec retrieves the OpenAPI spec of a version of a service of the
+// organization, or nil when it has none
+func (r *ServiceRepository) GetVersionSpec(ctx context.Context, serviceID, versionID int) (_ *domain.VersionSpec, err error) {
+	ctx, span := tracing.StartDB(ctx, "ServiceRepository.GetVersionSpec")
+	defer func() { tracing.End(span, err) }()
+
+	var spec domain.VersionSpec
+	var metadata string
+	err = r.conn(ctx).QueryRowContext(ctx, `
+		SELECT sp.content, sp.content_type, sp.metadata
+		FROM version_specs sp JOIN services s ON s.id = sp.service_id
+		WHERE sp.version_id = ? AND sp.service_id = ? AND s.org_id = ?`,
+		versionID, serviceID, tenant.FromContext(ctx),
+	).Scan(&spec.Content, &spec.ContentType, &metadata)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(metadata), &spec.Metadata); err != nil {
+		return nil, err
+	}
+	return &spec, nil
+}
+
+// DeleteVersionSpec removes the OpenAPI spec of a version and returns the
+// version, or nil when the service does not exist in the organization or
+// the version has no spec
+func (r *ServiceRepository) DeleteVersionSpec(ctx context.Context, serviceID, versionID int) (_ *domain.ServiceVersion, err error) {
+	ctx, span := tracing.StartDB(ctx, "ServiceRepository.DeleteVersionSpec")
+	defer func() { tracing.End(span, err) }()
+
+	return retryBusy(ctx, func() (*domain.ServiceVersion, error) {
+		tx, err := r.begin(ctx)
+		if err != nil {
+			return nil, err
+		}
+		defer tx.Rollback()
+
+		if found, err := touchService(ctx, tx, tenant.FromContext(ctx), serviceID); err != nil || !found {
+			return nil, err
+		}
+		result, err := tx.ExecContext(ctx, "DELETE FROM version_specs WHERE version_id = ? AND service_id = ?", versionID, serviceID)
+		if err != nil {
+			return nil, err
+		}
+		if affected, err := result.RowsAffected(); err != nil || affected == 0 {
+			return nil, err
+		}
+
+		version, err := scanVersion(tx.QueryRowContext(ctx, "SELECT "+versionColumns+" FROM service_versions v WHERE v.id = ?", versionID))
+		if err != nil {
+			return nil, err
+		}
+		if err := r.recordEvent(ctx, tx, domain.EventVersionSpecUpdated, serviceID, &version); err != nil {
+			return nil, err
+		}
+
+		return &version, tx.Commit()
+	})
+}
@@ -0,0 +1,101 @@
+package repository
+
+import (
+	"context"
+
+	"com.kong.connect/domain"
+	"com.kong.connect/tenant"
+	"com.kong.connect/tracing"
+)
+
+// GetStats computes aggregate statistics of the organization's catalog with
+// grouped queries
+func (r *ServiceRepository) GetStats(ctx context.Context, recentLimit int) (_ *domain.CatalogStats, err error) {
+	ctx, span := tracing.StartDB(ctx, "ServiceRepository.GetStats")
+	defer func() { tracing.End(span, err) }()
+
+	stats := &domain.CatalogStats{}
+	orgID := tenant.FromContext(ctx)
+
+	err = r.conn(ctx).QueryRowContext(ctx, `
+		SELECT
+			(SELECT COUNT(*) FROM services WHERE org_id = ?),
+			(SELECT COUNT(*) FROM service_versions v JOIN services s ON s.id = v.service_id WHERE s.org_id = ?)`,
+		orgID, orgID).Scan(&stats.TotalServices, &stats.TotalVersions)
+	if err != nil {
+		return nil, err
+	}
+
+	if stats.ByStatus, err = r.countBuckets(ctx, `
+		SELECT status, COUNT(*) FROM services WHERE org_id = ?
+		GROUP BY status ORDER BY COUNT(*) DESC, status ASC`, orgID); err != nil {
+		return nil, err
+	}
+
+	if stats.ByOwner, err = r.countBuckets(ctx, `
+		SELECT owner, COUNT(*) FROM services WHERE org_id = ?
+		GROUP BY owner ORDER BY COUNT(*) DESC, owner ASC`, orgID); err != nil {
+		return nil, err
+	}
+
+	if stats.ByTag, err = r.countBuckets(ctx, `
+		SELECT t.tag, COUNT(*) FROM service_tags t JOIN services s ON s.id = t.service_id WHERE s.org_id = ?
+		GROUP BY t.tag ORDER BY COUNT(*) DESC, t.tag ASC`, orgID); err != nil {
+		return nil, err
+	}
+
+	rows, err := r.conn(ctx).QueryContext(ctx, `
+		SELECT id, name, description, status, owner, created_at, updated_at
+		FROM services
+		WHERE org_id = ?
+		ORDER BY updated_at DESC, id DESC
+		LIMIT ?`, orgID, recentLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	stats.RecentlyUpdated = []domain.Service{}
+	for rows.Next() {
+		var service domain.Service
+		err := rows.Scan(&service.ID, &service.Name, &service.Description,
+			&service.Status, &service.Owner, &service.CreatedAt, &service.UpdatedAt)
+		if err != nil {
+			return nil, err
+		}
+		stats.RecentlyUpdated = append(stats.RecentlyUpdated, service)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	rows.Close()
+
+	for i := range stats.RecentlyUpdated {
+		stats.RecentlyUpdated[i].Tags, err = r.getTagsByServiceID(ctx, stats.RecentlyUpdated[i].ID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return stats, nil
+}
+
+// countBuckets runs a "value, count" grouping query
+func (r *ServiceRepository) countBuckets(ctx context.Context, query string, args ...interface{}) ([]domain.StatBucket, error) {
+	rows, err := r.conn(ctx).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	buckets := []domain.StatBucket{}
+	for rows.Next() {
+		var bucket domain.StatBucket
+		if err := rows.Scan(&bucket.Value, &bucket.Count); err != nil {
+			return nil, err
+		}
+		buckets = append(buckets, bucket)
+	}
+
+	return buckets, rows.Err()
+}
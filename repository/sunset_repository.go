@@ -0,0 +1,256 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"com.kong.connect/domain"
+	"com.kong.connect/tenant"
+	"com.kong.connect/tracing"
+)
+
+// serviceSunsetColumn and versionSunsetColumn select the sunset of the
+// service s and the version v as "status|archive_at|deprecate_at", for
+// parseSunset
+const (
+	serviceSunsetColumn = "(SELECT su.status || '|' || su.archive_at || '|' || su.deprecate_at FROM sunsets su WHERE su.service_id = s.id AND su.version_id = 0)"
+	versionSunsetColumn = "(SELECT su.status || '|' || su.archive_at || '|' || su.deprecate_at FROM sunsets su WHERE su.version_id = v.id)"
+)
+
+// parseSunset parses a sunset column, which is NULL without a sunset
+func parseSunset(column sql.NullString) (*domain.Sunset, error) {
+	if !column.Valid {
+		return nil, nil
+	}
+	parts := strings.Split(column.String, "|")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("malformed sunset %q", column.String)
+	}
+	sunset := &domain.Sunset{Status: parts[0]}
+	var err error
+	if sunset.ArchiveAt, err = time.Parse(auditTimeFormat, parts[1]); err != nil {
+		return nil, err
+	}
+	if parts[2] != "" {
+		deprecateAt, err := time.Parse(auditTimeFormat, parts[2])
+		if err != nil {
+			return nil, err
+		}
+		sunset.DeprecateAt = &deprecateAt
+	}
+	return sunset, nil
+}
+
+// SetSunset schedules the sunset of a service, or of one of its versions
+// when versionID is not 0, replacing the one it had; the schedule starts
+// over from active. It returns false when the service or the version does
+// not exist in the organization.
+func (r *ServiceRepository) SetSunset(ctx context.Context, serviceID, versionID int, input domain.SunsetInput) (_ bool, err error) {
+	ctx, span := tracing.StartDB(ctx, "ServiceRepository.SetSunset")
+	defer func() { tracing.End(span, err) }()
+
+	return retryBusy(ctx, func() (bool, error) {
+		tx, err := r.begin(ctx)
+		if err != nil {
+			return false, err
+		}
+		defer tx.Rollback()
+
+		orgID := tenant.FromContext(ctx)
+		if found, err := touchService(ctx, tx, orgID, serviceID); err != nil || !found {
+			return false, err
+		}
+		var deprecateAt string
+		if input.DeprecateAt != nil {
+			deprecateAt = input.DeprecateAt.UTC().Format(auditTimeFormat)
+		}
+		// The version must belong to the service
+		result, err := tx.ExecContext(ctx, `
+			INSERT INTO sunsets (service_id, version_id, org_id, deprecate_at, archive_at, status, reminded_days)
+			SELECT ?, ?, ?, ?, ?, ?, 0 WHERE ? = 0 OR EXISTS (SELECT 1 FROM service_versions WHERE id = ? AND service_id = ?)
+			ON CONFLICT (service_id, version_id) DO UPDATE SET deprecate_at = excluded.deprecate_at,
+				archive_at = excluded.archive_at, status = excluded.status, reminded_days = 0`,
+			serviceID, versionID, orgID, deprecateAt, input.ArchiveAt.UTC().Format(auditTimeFormat), domain.StatusActive,
+			versionID, versionID, serviceID,
+		)
+		if err != nil {
+			return false, err
+		}
+		if affected, err := result.RowsAffected(); err != nil || affected == 0 {
+			return false, err
+		}
+		if err := r.recordSunsetEvent(ctx, tx, serviceID, versionID); err != nil {
+			return false, err
+		}
+
+		return true, tx.Commit()
+	})
+}
+
+// DeleteSunset cancels the sunset of a service, or of one of its versions
+// when versionID is not 0; the statuses it applied stay. It returns false
+// when there is no such sunset in the organization.
+func (r *ServiceRepository) DeleteSunset(ctx context.Context, serviceID, versionID int) (_ bool, err error) {
+	ctx, span := tracing.StartDB(ctx, "ServiceRepository.DeleteSunset")
+	defer func() { tracing.End(span, err) }()
+
+	return retryBusy(ctx, func() (bool, error) {
+		tx, err := r.begin(ctx)
+		if err != nil {
+			return false, err
+		}
+		defer tx.Rollback()
+
+		orgID := tenant.FromContext(ctx)
+		if found, err := touchService(ctx, tx, orgID, serviceID); err != nil || !found {
+			return false, err
+		}
+		// The event is recorded with the version before its sunset is removed
+		if versionID != 0 {
+			if err := r.recordSunsetEvent(ctx, tx, serviceID, versionID); err != nil {
+				return false, err
+			}
+		}
+		result, err := tx.ExecContext(ctx, "DELETE FROM sunsets WHERE service_id = ? AND version_id = ? AND org_id = ?", serviceID, versionID, orgID)
+		if err != nil {
+			return false, err
+		}
+		if affected, err := result.RowsAffected(); err != nil || affected == 0 {
+			return false, err
+		}
+		if versionID == 0 {
+			if err := r.recordSunsetEvent(ctx, tx, serviceID, versionID); err != nil {
+				return false, err
+			}
+		}
+
+		return true, tx.Commit()
+	})
+}
+
+// DueSunsets retrieves the sunsets of every organization with a step due at
+// now: those not archived yet whose deprecation is due or whose archival is
+// at most the earliest reminder away
+func (r *ServiceRepository) DueSunsets(ctx context.Context, now time.Time) (_ []domain.DueSunset, err error) {
+	ctx, span := tracing.StartDB(ctx, "ServiceRepository.DueSunsets")
+	defer func() { tracing.End(span, err) }()
+
+	horizon := now.AddDate(0, 0, domain.SunsetReminderDays[0])
+	rows, err := r.conn(ctx).QueryContext(ctx, `
+		SELECT org_id, service_id, version_id, status || '|' || archive_at || '|' || deprecate_at, reminded_days
+		FROM sunsets
+		WHERE status != ? AND (archive_at <= ? OR (deprecate_at != '' AND deprecate_at <= ? AND status = ?))
+		ORDER BY org_id, service_id, version_id`,
+		domain.StatusArchived, horizon.UTC().Format(auditTimeFormat), now.UTC().Format(auditTimeFormat), domain.StatusActive,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var due []domain.DueSunset
+	for rows.Next() {
+		var sunset domain.DueSunset
+		var column sql.NullString
+		if err := rows.Scan(&sunset.OrgID, &sunset.ServiceID, &sunset.VersionID, &column, &sunset.RemindedDays); err != nil {
+			return nil, err
+		}
+		parsed, err := parseSunset(column)
+		if err != nil {
+			return nil, err
+		}
+		sunset.Sunset = *parsed
+		due = append(due, sunset)
+	}
+	return due, rows.Err()
+}
+
+// AdvanceSunset records the steps a due sunset of the organization took:
+// the status it reached and the reminder published. A service reaching
+// deprecated or archived gets that status unless it is archived already.
+// It returns false when the sunset changed since it was due.
+func (r *ServiceRepository) AdvanceSunset(ctx context.Context, due domain.DueSunset, status string, remindedDays int) (_ bool, err error) {
+	ctx, span := tracing.StartDB(ctx, "ServiceRepository.AdvanceSunset")
+	defer func() { tracing.End(span, err) }()
+
+	return retryBusy(ctx, func() (bool, error) {
+		tx, err := r.begin(ctx)
+		if err != nil {
+			return false, err
+		}
+		defer tx.Rollback()
+
+		orgID := tenant.FromContext(ctx)
+		result, err := tx.ExecContext(ctx, `
+			UPDATE sunsets SET status = ?, reminded_days = ?
+			WHERE service_id = ? AND version_id = ? AND org_id = ? AND status = ? AND reminded_days = ? AND archive_at = ?`,
+			status, remindedDays, due.ServiceID, due.VersionID, orgID, due.Status, due.RemindedDays,
+			due.ArchiveAt.UTC().Format(auditTimeFormat),
+		)
+		if err != nil {
+			return false, err
+		}
+		if affected, err := result.RowsAffected(); err != nil || affected == 0 {
+			return false, err
+		}
+
+		if status != due.Status {
+			if due.VersionID == 0 {
+				if _, err := tx.ExecContext(ctx, `
+					UPDATE services SET status = ?, updated_at = CURRENT_TIMESTAMP
+					WHERE id = ? AND org_id = ? AND status NOT IN (?, ?)`,
+					status, due.ServiceID, orgID, status, domain.StatusArchived,
+				); err != nil {
+					return false, err
+				}
+			} else if _, err := touchService(ctx, tx, orgID, due.ServiceID); err != nil {
+				return false, err
+			}
+			if err := r.recordSunsetEvent(ctx, tx, due.ServiceID, due.VersionID); err != nil {
+				return false, err
+			}
+		}
+		if remindedDays != due.RemindedDays {
+			eventType, version := domain.EventServiceSunsetReminder, (*domain.ServiceVersion)(nil)
+			if due.VersionID != 0 {
+				eventType = domain.EventVersionSunsetReminder
+				if version, err = r.sunsetVersion(ctx, tx, due.VersionID); err != nil {
+					return false, err
+				}
+			}
+			if err := r.recordEvent(ctx, tx, eventType, due.ServiceID, version); err != nil {
+				return false, err
+			}
+		}
+
+		return true, tx.Commit()
+	})
+}
+
+// recordSunsetEvent records the change of the sunset of a service, as
+// service.updated, or of one of its versions, as version.sunset_updated
+func (r *ServiceRepository) recordSunsetEvent(ctx context.Context, tx dbtx, serviceID, versionID int) error {
+	if versionID == 0 {
+		return r.recordEvent(ctx, tx, domain.EventServiceUpdated, serviceID, nil)
+	}
+	version, err := r.sunsetVersion(ctx, tx, versionID)
+	if err != nil {
+		return err
+	}
+	return r.recordEvent(ctx, tx, domain.EventVersionSunsetUpdated, serviceID, version)
+}
+
+// sunsetVersion reads a version within tx for the events of its sunset
+func (r *ServiceRepository) sunsetVersion(ctx context.Context, tx dbtx, versionID int) (*domain.ServiceVersion, error) {
+	if !r.outbox {
+		return nil, nil
+	}
+	version, err := scanVersion(tx.QueryRowContext(ctx, "SELECT "+versionColumns+" FROM service_versions v WHERE v.id = ?", versionID))
+	if err != nil {
+		return nil, err
+	}
+	return &version, nil
+}
@@ -0,0 +1,179 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"com.kong.connect/domain"
+	"com.kong.connect/tenant"
+	"com.kong.connect/tracing"
+)
+
+const transferColumns = "id, service_id, from_owner, to_owner, reason, requested_by, status, resolved_by, requested_at, resolved_at"
+
+// CreateTransfer stores an ownership transfer of a service of the
+// organization and returns it with its ID, or nil when the service does not
+// exist. A transfer created completed hands the service over at once. It
+// returns ErrDuplicate when the service has a pending transfer.
+func (r *ServiceRepository) CreateTransfer(ctx context.Context, transfer domain.OwnershipTransfer) (_ *domain.OwnershipTransfer, err error) {
+	ctx, span := tracing.StartDB(ctx, "ServiceRepository.CreateTransfer")
+	defer func() { tracing.End(span, err) }()
+
+	return retryBusy(ctx, func() (*domain.OwnershipTransfer, error) {
+		tx, err := r.begin(ctx)
+		if err != nil {
+			return nil, err
+		}
+		defer tx.Rollback()
+
+		orgID := tenant.FromContext(ctx)
+		var exists, pending bool
+		if err := tx.QueryRowContext(ctx, `
+			SELECT EXISTS (SELECT 1 FROM services WHERE id = ? AND org_id = ?),
+				EXISTS (SELECT 1 FROM ownership_transfers WHERE service_id = ? AND org_id = ? AND status = ?)`,
+			transfer.ServiceID, orgID, transfer.ServiceID, orgID, domain.TransferPending,
+		).Scan(&exists, &pending); err != nil || !exists {
+			return nil, err
+		}
+		if pending {
+			return nil, ErrDuplicate
+		}
+
+		var resolvedAt interface{}
+		if transfer.ResolvedAt != nil {
+			resolvedAt = transfer.ResolvedAt.UTC().Format(auditTimeFormat)
+		}
+		result, err := tx.ExecContext(ctx, `
+			INSERT INTO ownership_transfers (service_id, org_id, from_owner, to_owner, reason, requested_by, status, resolved_by, requested_at, resolved_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			transfer.ServiceID, orgID, transfer.FromOwner, transfer.ToOwner, transfer.Reason, transfer.RequestedBy,
+			transfer.Status, transfer.ResolvedBy, transfer.RequestedAt.UTC().Format(auditTimeFormat), resolvedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		id, err := result.LastInsertId()
+		if err != nil {
+			return nil, err
+		}
+		transfer.ID = int(id)
+
+		if transfer.Status == domain.TransferCompleted {
+			if handed, err := r.handOver(ctx, tx, orgID, transfer); err != nil || !handed {
+				return nil, err
+			}
+		}
+		return &transfer, tx.Commit()
+	})
+}
+
+// GetPendingTransfer retrieves the pending ownership transfer of a service
+// of the organization, or nil
+func (r *ServiceRepository) GetPendingTransfer(ctx context.Context, serviceID int) (_ *domain.OwnershipTransfer, err error) {
+	ctx, span := tracing.StartDB(ctx, "ServiceRepository.GetPendingTransfer")
+	defer func() { tracing.End(span, err) }()
+
+	transfer, err := scanTransfer(r.conn(ctx).QueryRowContext(ctx,
+		"SELECT "+transferColumns+" FROM ownership_transfers WHERE service_id = ? AND org_id = ? AND status = ?",
+		serviceID, tenant.FromContext(ctx), domain.TransferPending))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &transfer, nil
+}
+
+// GetTransfers retrieves the ownership transfers of a service of the
+// organization, newest first
+func (r *ServiceRepository) GetTransfers(ctx context.Context, serviceID int) (_ []domain.OwnershipTransfer, err error) {
+	ctx, span := tracing.StartDB(ctx, "ServiceRepository.GetTransfers")
+	defer func() { tracing.End(span, err) }()
+
+	rows, err := r.conn(ctx).QueryContext(ctx,
+		"SELECT "+transferColumns+" FROM ownership_transfers WHERE service_id = ? AND org_id = ? ORDER BY id DESC",
+		serviceID, tenant.FromContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	transfers := []domain.OwnershipTransfer{}
+	for rows.Next() {
+		transfer, err := scanTransfer(rows)
+		if err != nil {
+			return nil, err
+		}
+		transfers = append(transfers, transfer)
+	}
+	return transfers, rows.Err()
+}
+
+// ResolveTransfer completes or declines a pending ownership transfer;
+// completing it hands the service over to its new owner. It returns false
+// when the transfer is no longer pending or the service changed owner since
+// it was requested.
+func (r *ServiceRepository) ResolveTransfer(ctx context.Context, transfer domain.OwnershipTransfer) (_ bool, err error) {
+	ctx, span := tracing.StartDB(ctx, "ServiceRepository.ResolveTransfer")
+	defer func() { tracing.End(span, err) }()
+
+	return retryBusy(ctx, func() (bool, error) {
+		tx, err := r.begin(ctx)
+		if err != nil {
+			return false, err
+		}
+		defer tx.Rollback()
+
+		orgID := tenant.FromContext(ctx)
+		result, err := tx.ExecContext(ctx, `
+			UPDATE ownership_transfers SET status = ?, resolved_by = ?, resolved_at = ?
+			WHERE id = ? AND service_id = ? AND org_id = ? AND status = ?`,
+			transfer.Status, transfer.ResolvedBy, transfer.ResolvedAt.UTC().Format(auditTimeFormat),
+			transfer.ID, transfer.ServiceID, orgID, domain.TransferPending,
+		)
+		if err != nil {
+			return false, err
+		}
+		if affected, err := result.RowsAffected(); err != nil || affected == 0 {
+			return false, err
+		}
+		if transfer.Status == domain.TransferCompleted {
+			if handed, err := r.handOver(ctx, tx, orgID, transfer); err != nil || !handed {
+				return false, err
+			}
+		}
+
+		return true, tx.Commit()
+	})
+}
+
+// handOver sets the owner of the service of a completed transfer within a
+// transaction, returning false when the service no longer has the owner the
+// transfer is from
+func (r *ServiceRepository) handOver(ctx context.Context, tx dbtx, orgID int, transfer domain.OwnershipTransfer) (bool, error) {
+	result, err := tx.ExecContext(ctx,
+		"UPDATE services SET owner = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ? AND org_id = ? AND owner = ?",
+		transfer.ToOwner, transfer.ServiceID, orgID, transfer.FromOwner)
+	if err != nil {
+		return false, err
+	}
+	if affected, err := result.RowsAffected(); err != nil || affected == 0 {
+		return false, err
+	}
+	return true, r.recordEvent(ctx, tx, domain.EventServiceUpdated, transfer.ServiceID, nil)
+}
+
+func scanTransfer(row scanner) (domain.OwnershipTransfer, error) {
+	var transfer domain.OwnershipTransfer
+	var resolvedAt sql.NullTime
+	if err := row.Scan(&transfer.ID, &transfer.ServiceID, &transfer.FromOwner, &transfer.ToOwner, &transfer.Reason,
+		&transfer.RequestedBy, &transfer.Status, &transfer.ResolvedBy, &transfer.RequestedAt, &resolvedAt); err != nil {
+		return transfer, err
+	}
+	if resolvedAt.Valid {
+		at := resolvedAt.Time
+		transfer.ResolvedAt = &at
+	}
+	return transfer, nil
+}
@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+
+	"com.kong.connect/domain"
+	"com.kong.connect/tenant"
+	"com.kong.connect/tracing"
+)
+
+// translationsColumn selects the translations of the description of the
+// service s as a JSON object by language, for parseTranslations
+const translationsColumn = "(SELECT json_group_object(t.language, t.description) FROM service_translations t WHERE t.service_id = s.id)"
+
+// parseTranslations parses a translations column, which is an empty object
+// for services without translations
+func parseTranslations(column sql.NullString) (map[string]string, error) {
+	var translations map[string]string
+	if column.Valid {
+		if err := json.Unmarshal([]byte(column.String), &translations); err != nil {
+			return nil, err
+		}
+	}
+	if len(translations) == 0 {
+		return nil, nil
+	}
+	return translations, nil
+}
+
+// SetTranslation sets the description of a service in a language, replacing
+// the one it had. It returns false when the service does not exist in the
+// organization.
+func (r *ServiceRepository) SetTranslation(ctx context.Context, serviceID int, language, description string) (_ bool, err error) {
+	ctx, span := tracing.StartDB(ctx, "ServiceRepository.SetTranslation")
+	defer func() { tracing.End(span, err) }()
+
+	return retryBusy(ctx, func() (bool, error) {
+		tx, err := r.begin(ctx)
+		if err != nil {
+			return false, err
+		}
+		defer tx.Rollback()
+
+		if found, err := touchService(ctx, tx, tenant.FromContext(ctx), serviceID); err != nil || !found {
+			return false, err
+		}
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO service_translations (service_id, language, description) VALUES (?, ?, ?)
+			ON CONFLICT (service_id, language) DO UPDATE SET description = excluded.description`,
+			serviceID, language, description,
+		); err != nil {
+			return false, err
+		}
+		if err := r.recordEvent(ctx, tx, domain.EventServiceUpdated, serviceID, nil); err != nil {
+			return false, err
+		}
+
+		return true, tx.Commit()
+	})
+}
+
+// DeleteTranslation removes the description of a service in a language. It
+// returns false when the service does not exist in the organization or has
+// no description in the language.
+func (r *ServiceRepository) DeleteTranslation(ctx context.Context, serviceID int, language string) (_ bool, err error) {
+	ctx, span := tracing.StartDB(ctx, "ServiceRepository.DeleteTranslation")
+	defer func() { tracing.End(span, err) }()
+
+	return retryBusy(ctx, func() (bool, error) {
+		tx, err := r.begin(ctx)
+		if err != nil {
+			return false, err
+		}
+		defer tx.Rollback()
+
+		if found, err := touchService(ctx, tx, tenant.FromContext(ctx), serviceID); err != nil || !found {
+			return false, err
+		}
+		result, err := tx.ExecContext(ctx, "DELETE FROM service_translations WHERE service_id = ? AND language = ?", serviceID, language)
+		if err != nil {
+			return false, err
+		}
+		if affected, err := result.RowsAffected(); err != nil || affected == 0 {
+			return false, err
+		}
+		if err := r.recordEvent(ctx, tx, domain.EventServiceUpdated, serviceID, nil); err != nil {
+			return false, err
+		}
+
+		return true, tx.Commit()
+	})
+}
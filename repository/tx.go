@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+)
+
+// dbtx runs statements, on the database or in a transaction
+type dbtx interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// txKey is the context key of the transaction started by InTx
+type txKey struct{}
+
+// txFromContext returns the transaction started by InTx, or nil
+func txFromContext(ctx context.Context) *sql.Tx {
+	tx, _ := ctx.Value(txKey{}).(*sql.Tx)
+	return tx
+}
+
+// InTx runs fn in one transaction: the repository methods fn calls with the
+// context it is given read and write in it, and an error of fn rolls all
+// their writes back. The transaction runs again while the database is busy,
+// so fn must be safe to repeat and leave side effects, such as publishing
+// events, until InTx returns. Within a transaction, InTx runs fn in it.
+func (r *ServiceRepository) InTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if txFromContext(ctx) != nil {
+		return fn(ctx)
+	}
+	return retryBusyWrite(ctx, func() error {
+		tx, err := r.db.BeginTx(ctx, nil)
+		if err != nil {
+			return err
+		}
+		defer tx.Rollback()
+
+		if err := fn(context.WithValue(ctx, txKey{}, tx)); err != nil {
+			return err
+		}
+		return tx.Commit()
+	})
+}
+
+// conn returns what the statements of ctx run on: the transaction of InTx,
+// or else the database
+func (r *ServiceRepository) conn(ctx context.Context) dbtx {
+	if tx := txFromContext(ctx); tx != nil {
+		return tx
+	}
+	return r.db
+}
+
+// txn is the transaction of a write method: its own, or the one of InTx,
+// which it joins, leaving the commit or rollback to InTx
+type txn struct {
+	*sql.Tx
+	joined bool
+}
+
+// begin starts the transaction of a write method, or joins the one of InTx
+func (r *ServiceRepository) begin(ctx context.Context) (*txn, error) {
+	if tx := txFromContext(ctx); tx != nil {
+		return &txn{Tx: tx, joined: true}, nil
+	}
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &txn{Tx: tx}, nil
+}
+
+// Commit commits a transaction of its own
+func (t *txn) Commit() error {
+	if t.joined {
+		return nil
+	}
+	return t.Tx.Commit()
+}
+
+// Rollback rolls back a transaction of its own
+func (t *txn) Rollback() error {
+	if t.joined {
+		return nil
+	}
+	return t.Tx.Rollback()
+}
@@ -0,0 +1,204 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	"com.kong.connect/domain"
+	"com.kong.connect/tenant"
+	"com.kong.connect/tracing"
+)
+
+// rolesSeparator joins the roles of a user in the roles column
+const rolesSeparator = "|"
+
+const userColumns = "id, username, roles, disabled, created_at"
+
+const tokenColumns = "t.id, t.user_id, t.org_id, u.username, t.name, t.created_at, t.revoked_at"
+
+// CreateUser inserts a user and returns it
+func (r *ServiceRepository) CreateUser(ctx context.Context, input domain.UserInput) (_ *domain.User, err error) {
+	ctx, span := tracing.StartDB(ctx, "ServiceRepository.CreateUser")
+	defer func() { tracing.End(span, err) }()
+
+	result, err := r.exec(ctx,
+		"INSERT INTO users (username, roles) VALUES (?, ?)",
+		input.Username, strings.Join(input.Roles, rolesSeparator),
+	)
+	if err != nil {
+		return nil, translateError(err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return nil, err
+	}
+	return scanUser(r.conn(ctx).QueryRowContext(ctx, "SELECT "+userColumns+" FROM users WHERE id = ?", id))
+}
+
+// GetUserByName retrieves a user, or nil when it does not exist
+func (r *ServiceRepository) GetUserByName(ctx context.Context, username string) (_ *domain.User, err error) {
+	ctx, span := tracing.StartDB(ctx, "ServiceRepository.GetUserByName")
+	defer func() { tracing.End(span, err) }()
+
+	user, err := scanUser(r.conn(ctx).QueryRowContext(ctx, "SELECT "+userColumns+" FROM users WHERE username = ?", username))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return user, err
+}
+
+// ListUsers retrieves every user ordered by username
+func (r *ServiceRepository) ListUsers(ctx context.Context) (_ []domain.User, err error) {
+	ctx, span := tracing.StartDB(ctx, "ServiceRepository.ListUsers")
+	defer func() { tracing.End(span, err) }()
+
+	rows, err := r.conn(ctx).QueryContext(ctx, "SELECT "+userColumns+" FROM users ORDER BY username")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	users := []domain.User{}
+	for rows.Next() {
+		user, err := scanUser(rows)
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, *user)
+	}
+	return users, rows.Err()
+}
+
+// SetUserDisabled disables or enables a user
+func (r *ServiceRepository) SetUserDisabled(ctx context.Context, id int, disabled bool) (err error) {
+	ctx, span := tracing.StartDB(ctx, "ServiceRepository.SetUserDisabled")
+	defer func() { tracing.End(span, err) }()
+
+	_, err = r.exec(ctx, "UPDATE users SET disabled = ? WHERE id = ?", disabled, id)
+	return err
+}
+
+// CreateToken stores the hash of a token issued to a user in the
+// organization and returns the token's description
+func (r *ServiceRepository) CreateToken(ctx context.Context, userID int, name, hash string) (_ *domain.APIToken, err error) {
+	ctx, span := tracing.StartDB(ctx, "ServiceRepository.CreateToken")
+	defer func() { tracing.End(span, err) }()
+
+	result, err := r.exec(ctx,
+		"INSERT INTO api_tokens (user_id, org_id, name, token_hash) VALUES (?, ?, ?, ?)",
+		userID, tenant.FromContext(ctx), name, hash,
+	)
+	if err != nil {
+		return nil, translateError(err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return nil, err
+	}
+	return r.getToken(ctx, int(id))
+}
+
+// ListTokens retrieves every token of the organization, revoked ones
+// included, in the order they were issued
+func (r *ServiceRepository) ListTokens(ctx context.Context) (_ []domain.APIToken, err error) {
+	ctx, span := tracing.StartDB(ctx, "ServiceRepository.ListTokens")
+	defer func() { tracing.End(span, err) }()
+
+	rows, err := r.conn(ctx).QueryContext(ctx,
+		"SELECT "+tokenColumns+" FROM api_tokens t JOIN users u ON u.id = t.user_id WHERE t.org_id = ? ORDER BY t.id",
+		tenant.FromContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tokens := []domain.APIToken{}
+	for rows.Next() {
+		token, err := scanToken(rows)
+		if err != nil {
+			return nil, err
+		}
+		tokens = append(tokens, *token)
+	}
+	return tokens, rows.Err()
+}
+
+// RevokeToken marks a token as revoked and returns it, or nil when it does
+// not exist in the organization. Revoking a revoked token keeps the
+// original revocation time.
+func (r *ServiceRepository) RevokeToken(ctx context.Context, id int) (_ *domain.APIToken, err error) {
+	ctx, span := tracing.StartDB(ctx, "ServiceRepository.RevokeToken")
+	defer func() { tracing.End(span, err) }()
+
+	if _, err := r.exec(ctx,
+		"UPDATE api_tokens SET revoked_at = CURRENT_TIMESTAMP WHERE id = ? AND org_id = ? AND revoked_at IS NULL",
+		id, tenant.FromContext(ctx),
+	); err != nil {
+		return nil, err
+	}
+
+	token, err := r.getToken(ctx, id)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return token, err
+}
+
+// GetTokenUser retrieves the user and organization of the unrevoked token
+// with the given hash, or nil when there is none
+func (r *ServiceRepository) GetTokenUser(ctx context.Context, hash string) (_ *domain.TokenGrant, err error) {
+	ctx, span := tracing.StartDB(ctx, "ServiceRepository.GetTokenUser")
+	defer func() { tracing.End(span, err) }()
+
+	var grant domain.TokenGrant
+	var roles string
+	err = r.conn(ctx).QueryRowContext(ctx, `
+		SELECT u.id, u.username, u.roles, u.disabled, u.created_at, t.org_id
+		FROM api_tokens t JOIN users u ON u.id = t.user_id
+		WHERE t.token_hash = ? AND t.revoked_at IS NULL`, hash,
+	).Scan(&grant.User.ID, &grant.User.Username, &roles, &grant.User.Disabled, &grant.User.CreatedAt, &grant.OrgID)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	grant.User.Roles = strings.Split(roles, rolesSeparator)
+	return &grant, nil
+}
+
+func (r *ServiceRepository) getToken(ctx context.Context, id int) (*domain.APIToken, error) {
+	return scanToken(r.conn(ctx).QueryRowContext(ctx,
+		"SELECT "+tokenColumns+" FROM api_tokens t JOIN users u ON u.id = t.user_id WHERE t.id = ? AND t.org_id = ?",
+		id, tenant.FromContext(ctx)))
+}
+
+// scanner is implemented by *sql.Row and *sql.Rows
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanUser(row scanner) (*domain.User, error) {
+	var user domain.User
+	var roles string
+	if err := row.Scan(&user.ID, &user.Username, &roles, &user.Disabled, &user.CreatedAt); err != nil {
+		return nil, err
+	}
+	user.Roles = strings.Split(roles, rolesSeparator)
+	return &user, nil
+}
+
+func scanToken(row scanner) (*domain.APIToken, error) {
+	var token domain.APIToken
+	var revokedAt sql.NullTime
+	if err := row.Scan(&token.ID, &token.UserID, &token.OrgID, &token.Username, &token.Name, &token.CreatedAt, &revokedAt); err != nil {
+		return nil, err
+	}
+	if revokedAt.Valid {
+		token.RevokedAt = &revokedAt.Time
+	}
+	return &token, nil
+}
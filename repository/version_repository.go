@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"com.kong.connect/domain"
+	"com.kong.connect/tenant"
+	"com.kong.connect/tracing"
+)
+
+// ServiceExists reports whether a service with the given ID exists in the
+// organization. The version queries below trust the service ID they are given,
+// so check it with ServiceExists first.
+func (r *ServiceRepository) ServiceExists(ctx context.Context, id int) (_ bool, err error) {
+	ctx, span := tracing.StartDB(ctx, "ServiceRepository.ServiceExists")
+	defer func() { tracing.End(span, err) }()
+
+	var exists int
+	err = r.conn(ctx).QueryRowContext(ctx, "SELECT 1 FROM services WHERE id = ? AND org_id = ?", id, tenant.FromContext(ctx)).Scan(&exists)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// CountVersions returns the number of versions of a service
+func (r *ServiceRepository) CountVersions(ctx context.Context, serviceID int) (_ int, err error) {
+	ctx, span := tracing.StartDB(ctx, "ServiceRepository.CountVersions")
+	defer func() { tracing.End(span, err) }()
+
+	var total int
+	err = r.conn(ctx).QueryRowContext(ctx, "SELECT COUNT(*) FROM service_versions WHERE service_id = ?", serviceID).Scan(&total)
+	return total, err
+}
+
+// GetVersionsByCreatedAt retrieves one page of the versions of a service ordered by creation time
+func (r *ServiceRepository) GetVersionsByCreatedAt(ctx context.Context, serviceID int, desc bool, limit, offset int) (_ []domain.ServiceVersion, err error) {
+	ctx, span := tracing.StartDB(ctx, "ServiceRepository.GetVersionsByCreatedAt")
+	defer func() { tracing.End(span, err) }()
+
+	direction := "ASC"
+	if desc {
+		direction = "DESC"
+	}
+
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM service_versions v
+		WHERE v.service_id = ?
+		ORDER BY v.created_at %s, v.id %s
+		LIMIT ? OFFSET ?`, versionColumns, direction, direction)
+
+	rows, err := r.conn(ctx).QueryContext(ctx, query, serviceID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	versions := []domain.ServiceVersion{}
+	for rows.Next() {
+		version, err := scanVersion(rows)
+		if err != nil {
+			return nil, err
+		}
+		versions = append(versions, version)
+	}
+
+	return versions, rows.Err()
+}
+
+// GetAllVersions retrieves every version of a service
+func (r *ServiceRepository) GetAllVersions(ctx context.Context, serviceID int) (_ []domain.ServiceVersion, err error) {
+	ctx, span := tracing.StartDB(ctx, "ServiceRepository.GetAllVersions")
+	defer func() { tracing.End(span, err) }()
+
+	return r.getVersionsByServiceID(ctx, serviceID)
+}
@@ -0,0 +1,307 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	"com.kong.connect/domain"
+	"com.kong.connect/tenant"
+	"com.kong.connect/tracing"
+)
+
+const (
+	webhookColumns  = "id, url, events, created_at"
+	deliveryColumns = "d.id, d.webhook_id, d.event, d.payload, d.status, d.attempts, d.response_code, d.error, d.next_attempt_at, d.created_at, d.delivered_at"
+	// eventsSeparator joins the event types a webhook filters on
+	eventsSeparator = ","
+)
+
+// CreateWebhook stores a webhook of the organization and returns it
+func (r *ServiceRepository) CreateWebhook(ctx context.Context, url, secret string, events []string) (_ *domain.Webhook, err error) {
+	ctx, span := tracing.StartDB(ctx, "ServiceRepository.CreateWebhook")
+	defer func() { tracing.End(span, err) }()
+
+	result, err := r.exec(ctx,
+		"INSERT INTO webhooks (org_id, url, secret, events) VALUES (?, ?, ?, ?)",
+		tenant.FromContext(ctx), url, secret, strings.Join(events, eventsSeparator),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return nil, err
+	}
+	return r.GetWebhook(ctx, int(id))
+}
+
+// GetWebhook retrieves a webhook, or nil when it does not exist in the organization
+func (r *ServiceRepository) GetWebhook(ctx context.Context, id int) (_ *domain.Webhook, err error) {
+	ctx, span := tracing.StartDB(ctx, "ServiceRepository.GetWebhook")
+	defer func() { tracing.End(span, err) }()
+
+	webhook, err := scanWebhook(r.conn(ctx).QueryRowContext(ctx,
+		"SELECT "+webhookColumns+" FROM webhooks WHERE id = ? AND org_id = ?", id, tenant.FromContext(ctx)))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return webhook, err
+}
+
+// ListWebhooks retrieves every webhook of the organization in the order they were created
+func (r *ServiceRepository) ListWebhooks(ctx context.Context) (_ []domain.Webhook, err error) {
+	ctx, span := tracing.StartDB(ctx, "ServiceRepository.ListWebhooks")
+	defer func() { tracing.End(span, err) }()
+
+	rows, err := r.conn(ctx).QueryContext(ctx, "SELECT "+webhookColumns+" FROM webhooks WHERE org_id = ? ORDER BY id", tenant.FromContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	webhooks := []domain.Webhook{}
+	for rows.Next() {
+		webhook, err := scanWebhook(rows)
+		if err != nil {
+			return nil, err
+		}
+		webhooks = append(webhooks, *webhook)
+	}
+	return webhooks, rows.Err()
+}
+
+// DeleteWebhook removes a webhook of the organization together with its
+// delivery log. It returns false when the webhook does not exist.
+func (r *ServiceRepository) DeleteWebhook(ctx context.Context, id int) (_ bool, err error) {
+	ctx, span := tracing.StartDB(ctx, "ServiceRepository.DeleteWebhook")
+	defer func() { tracing.End(span, err) }()
+
+	return retryBusy(ctx, func() (bool, error) {
+		tx, err := r.begin(ctx)
+		if err != nil {
+			return false, err
+		}
+		defer tx.Rollback()
+
+		result, err := tx.ExecContext(ctx, "DELETE FROM webhooks WHERE id = ? AND org_id = ?", id, tenant.FromContext(ctx))
+		if err != nil {
+			return false, err
+		}
+		affected, err := result.RowsAffected()
+		if err != nil || affected == 0 {
+			return false, err
+		}
+		if _, err := tx.ExecContext(ctx, "DELETE FROM webhook_deliveries WHERE webhook_id = ?", id); err != nil {
+			return false, err
+		}
+
+		return true, tx.Commit()
+	})
+}
+
+// ListWebhookDeliveries retrieves the most recent deliveries of a webhook of
+// the organization, newest first
+func (r *ServiceRepository) ListWebhookDeliveries(ctx context.Context, webhookID, limit int) (_ []domain.WebhookDelivery, err error) {
+	ctx, span := tracing.StartDB(ctx, "ServiceRepository.ListWebhookDeliveries")
+	defer func() { tracing.End(span, err) }()
+
+	rows, err := r.conn(ctx).QueryContext(ctx, `
+		SELECT `+deliveryColumns+` FROM webhook_deliveries d JOIN webhooks w ON w.id = d.webhook_id
+		WHERE d.webhook_id = ? AND w.org_id = ? ORDER BY d.id DESC LIMIT ?`,
+		webhookID, tenant.FromContext(ctx), limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	deliveries := []domain.WebhookDelivery{}
+	for rows.Next() {
+		delivery, err := scanDelivery(rows)
+		if err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, *delivery)
+	}
+	return deliveries, rows.Err()
+}
+
+// QueueWebhookDeliveries logs a pending delivery of an event to every webhook
+// of the organization filtering on its type, due for a retry at retryAt, and
+// returns them for their first attempt
+func (r *ServiceRepository) QueueWebhookDeliveries(ctx context.Context, event string, payload []byte, retryAt time.Time) (_ []domain.PendingDelivery, err error) {
+	ctx, span := tracing.StartDB(ctx, "ServiceRepository.QueueWebhookDeliveries")
+	defer func() { tracing.End(span, err) }()
+
+	return retryBusy(ctx, func() ([]domain.PendingDelivery, error) {
+		tx, err := r.begin(ctx)
+		if err != nil {
+			return nil, err
+		}
+		defer tx.Rollback()
+
+		rows, err := tx.QueryContext(ctx, `
+			SELECT id, url, secret FROM webhooks
+			WHERE org_id = ? AND (events = '' OR ',' || events || ',' LIKE '%,' || ? || ',%')
+			ORDER BY id`,
+			tenant.FromContext(ctx), event)
+		if err != nil {
+			return nil, err
+		}
+		var deliveries []domain.PendingDelivery
+		for rows.Next() {
+			var delivery domain.PendingDelivery
+			if err := rows.Scan(&delivery.WebhookID, &delivery.URL, &delivery.Secret); err != nil {
+				rows.Close()
+				return nil, err
+			}
+			deliveries = append(deliveries, delivery)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return nil, err
+		}
+
+		for i := range deliveries {
+			delivery := &deliveries[i]
+			result, err := tx.ExecContext(ctx,
+				"INSERT INTO webhook_deliveries (webhook_id, event, payload, next_attempt_at) VALUES (?, ?, ?, ?)",
+				delivery.WebhookID, event, string(payload), retryAt.UnixMilli(),
+			)
+			if err != nil {
+				return nil, err
+			}
+			id, err := result.LastInsertId()
+			if err != nil {
+				return nil, err
+			}
+			next := time.UnixMilli(retryAt.UnixMilli())
+			delivery.ID = int(id)
+			delivery.Event = event
+			delivery.Payload = payload
+			delivery.Status = domain.DeliveryPending
+			delivery.NextAttemptAt = &next
+		}
+
+		return deliveries, tx.Commit()
+	})
+}
+
+// DueWebhookDeliveries retrieves up to limit pending deliveries of every
+// organization whose next attempt is due at now, oldest first
+func (r *ServiceRepository) DueWebhookDeliveries(ctx context.Context, now time.Time, limit int) (_ []domain.PendingDelivery, err error) {
+	ctx, span := tracing.StartDB(ctx, "ServiceRepository.DueWebhookDeliveries")
+	defer func() { tracing.End(span, err) }()
+
+	rows, err := r.conn(ctx).QueryContext(ctx, `
+		SELECT `+deliveryColumns+`, w.url, w.secret FROM webhook_deliveries d JOIN webhooks w ON w.id = d.webhook_id
+		WHERE d.status = ? AND d.next_attempt_at <= ? ORDER BY d.next_attempt_at LIMIT ?`,
+		domain.DeliveryPending, now.UnixMilli(), limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var deliveries []domain.PendingDelivery
+	for rows.Next() {
+		var delivery domain.PendingDelivery
+		if err := scanDeliveryInto(rows, &delivery.WebhookDelivery, &delivery.URL, &delivery.Secret); err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, delivery)
+	}
+	return deliveries, rows.Err()
+}
+
+// RecordWebhookAttempt stores the outcome of the last attempt of a delivery:
+// its status, attempt count, response and next attempt
+func (r *ServiceRepository) RecordWebhookAttempt(ctx context.Context, delivery domain.WebhookDelivery) (err error) {
+	ctx, span := tracing.StartDB(ctx, "ServiceRepository.RecordWebhookAttempt")
+	defer func() { tracing.End(span, err) }()
+
+	var next interface{}
+	if delivery.NextAttemptAt != nil {
+		next = delivery.NextAttemptAt.UnixMilli()
+	}
+	_, err = r.exec(ctx, `
+		UPDATE webhook_deliveries
+		SET status = ?, attempts = ?, response_code = ?, error = ?, next_attempt_at = ?,
+			delivered_at = CASE WHEN ? = ? THEN CURRENT_TIMESTAMP END
+		WHERE id = ?`,
+		delivery.Status, delivery.Attempts, delivery.ResponseCode, delivery.Error, next,
+		delivery.Status, domain.DeliveryDelivered, delivery.ID,
+	)
+	return err
+}
+
+// PurgeWebhookDeliveries deletes the deliveries of every organization logged
+// before the cutoff, except those still pending, and returns how many were removed
+func (r *ServiceRepository) PurgeWebhookDeliveries(ctx context.Context, before time.Time) (_ int64, err error) {
+	ctx, span := tracing.StartDB(ctx, "ServiceRepository.PurgeWebhookDeliveries")
+	defer func() { tracing.End(span, err) }()
+
+	result, err := r.exec(ctx, "DELETE FROM webhook_deliveries WHERE created_at < ? AND status != ?",
+		before.UTC().Format(auditTimeFormat), domain.DeliveryPending)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// CountWebhookDeliveriesBefore returns how many deliveries
+// PurgeWebhookDeliveries would delete
+func (r *ServiceRepository) CountWebhookDeliveriesBefore(ctx context.Context, before time.Time) (_ int64, err error) {
+	ctx, span := tracing.StartDB(ctx, "ServiceRepository.CountWebhookDeliveriesBefore")
+	defer func() { tracing.End(span, err) }()
+
+	var count int64
+	err = r.conn(ctx).QueryRowContext(ctx, "SELECT COUNT(*) FROM webhook_deliveries WHERE created_at < ? AND status != ?",
+		before.UTC().Format(auditTimeFormat), domain.DeliveryPending).Scan(&count)
+	return count, err
+}
+
+func scanWebhook(row scanner) (*domain.Webhook, error) {
+	var webhook domain.Webhook
+	var events string
+	if err := row.Scan(&webhook.ID, &webhook.URL, &events, &webhook.CreatedAt); err != nil {
+		return nil, err
+	}
+	webhook.Events = []string{}
+	if events != "" {
+		webhook.Events = strings.Split(events, eventsSeparator)
+	}
+	return &webhook, nil
+}
+
+func scanDelivery(row scanner) (*domain.WebhookDelivery, error) {
+	var delivery domain.WebhookDelivery
+	if err := scanDeliveryInto(row, &delivery); err != nil {
+		return nil, err
+	}
+	return &delivery, nil
+}
+
+// scanDeliveryInto scans the delivery columns followed by extra columns
+func scanDeliveryInto(row scanner, delivery *domain.WebhookDelivery, extra ...interface{}) error {
+	var payload string
+	var next sql.NullInt64
+	var deliveredAt sql.NullTime
+	dest := []interface{}{
+		&delivery.ID, &delivery.WebhookID, &delivery.Event, &payload, &delivery.Status, &delivery.Attempts,
+		&delivery.ResponseCode, &delivery.Error, &next, &delivery.CreatedAt, &deliveredAt,
+	}
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return err
+	}
+	delivery.Payload = []byte(payload)
+	if next.Valid {
+		t := time.UnixMilli(next.Int64)
+		delivery.NextAttemptAt = &t
+	}
+	if deliveredAt.Valid {
+		delivery.DeliveredAt = &deliveredAt.Time
+	}
+	return nil
+}
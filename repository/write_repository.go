@@ -0,0 +1,429 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/mattn/go-sqlite3"
+
+	"com.kong.connect/domain"
+	"com.kong.connect/tenant"
+	"com.kong.connect/tracing"
+)
+
+// ErrDuplicate is returned when a write violates a uniqueness constraint
+var ErrDuplicate = errors.New("duplicate record")
+
+// Create inserts a new service with its tags into the organization and returns its ID
+func (r *ServiceRepository) Create(ctx context.Context, input domain.ServiceInput) (_ int, err error) {
+	ctx, span := tracing.StartDB(ctx, "ServiceRepository.Create")
+	defer func() { tracing.End(span, err) }()
+
+	return retryBusy(ctx, func() (int, error) {
+		tx, err := r.begin(ctx)
+		if err != nil {
+			return 0, err
+		}
+		defer tx.Rollback()
+
+		id, err := insertService(ctx, tx, tenant.FromContext(ctx), input)
+		if err != nil {
+			return 0, err
+		}
+		if err := r.recordEvent(ctx, tx, domain.EventServiceCreated, id, nil); err != nil {
+			return 0, err
+		}
+
+		return id, tx.Commit()
+	})
+}
+
+// Update replaces the fields and tags of an existing service.
+// It returns false when the service does not exist in the organization.
+func (r *ServiceRepository) Update(ctx context.Context, id int, input domain.ServiceInput) (_ bool, err error) {
+	ctx, span := tracing.StartDB(ctx, "ServiceRepository.Update")
+	defer func() { tracing.End(span, err) }()
+
+	return retryBusy(ctx, func() (bool, error) {
+		tx, err := r.begin(ctx)
+		if err != nil {
+			return false, err
+		}
+		defer tx.Rollback()
+
+		found, err := updateService(ctx, tx, tenant.FromContext(ctx), id, input)
+		if err != nil || !found {
+			return false, err
+		}
+		if err := r.recordEvent(ctx, tx, domain.EventServiceUpdated, id, nil); err != nil {
+			return false, err
+		}
+
+		return true, tx.Commit()
+	})
+}
+
+// Delete removes a service together with its versions and tags.
+// It returns false when the service does not exist in the organization.
+func (r *ServiceRepository) Delete(ctx context.Context, id int) (_ bool, err error) {
+	ctx, span := tracing.StartDB(ctx, "ServiceRepository.Delete")
+	defer func() { tracing.End(span, err) }()
+
+	return retryBusy(ctx, func() (bool, error) {
+		tx, err := r.begin(ctx)
+		if err != nil {
+			return false, err
+		}
+		defer tx.Rollback()
+
+		if err := r.recordEvent(ctx, tx, domain.EventServiceDeleted, id, nil); err != nil {
+			return false, err
+		}
+		found, err := deleteService(ctx, tx, tenant.FromContext(ctx), id)
+		if err != nil || !found {
+			return false, err
+		}
+
+		return true, tx.Commit()
+	})
+}
+
+// CreateVersion adds a version to a service and bumps the service's
+// updated_at. It returns nil when the service does not exist in the
+// organization.
+func (r *ServiceRepository) CreateVersion(ctx context.Context, serviceID int, input domain.VersionInput) (_ *domain.ServiceVersion, err error) {
+	ctx, span := tracing.StartDB(ctx, "ServiceRepository.CreateVersion")
+	defer func() { tracing.End(span, err) }()
+
+	return retryBusy(ctx, func() (*domain.ServiceVersion, error) {
+		tx, err := r.begin(ctx)
+		if err != nil {
+			return nil, err
+		}
+		defer tx.Rollback()
+
+		if found, err := touchService(ctx, tx, tenant.FromContext(ctx), serviceID); err != nil || !found {
+			return nil, err
+		}
+
+		result, err := tx.ExecContext(ctx,
+			"INSERT INTO service_versions (service_id, version, original_version) VALUES (?, ?, NULLIF(?, ''))",
+			serviceID, input.Version, input.Original,
+		)
+		if err != nil {
+			return nil, translateError(err)
+		}
+
+		id, err := result.LastInsertId()
+		if err != nil {
+			return nil, err
+		}
+
+		var version domain.ServiceVersion
+		var original sql.NullString
+		err = tx.QueryRowContext(ctx,
+			"SELECT id, service_id, version, original_version, created_at FROM service_versions WHERE id = ?", id,
+		).Scan(&version.ID, &version.ServiceID, &version.Version, &original, &version.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		version.OriginalVersion = original.String
+		if err := r.recordEvent(ctx, tx, domain.EventVersionCreated, serviceID, &version); err != nil {
+			return nil, err
+		}
+
+		return &version, tx.Commit()
+	})
+}
+
+// DeleteVersion removes a version of a service and returns it, or nil when
+// it does not exist in the organization
+func (r *ServiceRepository) DeleteVersion(ctx context.Context, serviceID, versionID int) (_ *domain.ServiceVersion, err error) {
+	ctx, span := tracing.StartDB(ctx, "ServiceRepository.DeleteVersion")
+	defer func() { tracing.End(span, err) }()
+
+	return retryBusy(ctx, func() (*domain.ServiceVersion, error) {
+		tx, err := r.begin(ctx)
+		if err != nil {
+			return nil, err
+		}
+		defer tx.Rollback()
+
+		if found, err := touchService(ctx, tx, tenant.FromContext(ctx), serviceID); err != nil || !found {
+			return nil, err
+		}
+
+		version, err := scanVersion(tx.QueryRowContext(ctx,
+			"SELECT "+versionColumns+" FROM service_versions v WHERE v.id = ? AND v.service_id = ?",
+			versionID, serviceID,
+		))
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if err := r.recordEvent(ctx, tx, domain.EventVersionDeleted, serviceID, &version); err != nil {
+			return nil, err
+		}
+
+		// A deleted version no longer runs anywhere
+		if _, err := tx.ExecContext(ctx, "DELETE FROM service_environments WHERE version_id = ?", versionID); err != nil {
+			return nil, err
+		}
+		if _, err := tx.ExecContext(ctx, "DELETE FROM version_specs WHERE version_id = ?", versionID); err != nil {
+			return nil, err
+		}
+		if _, err := tx.ExecContext(ctx, "DELETE FROM sunsets WHERE version_id = ?", versionID); err != nil {
+			return nil, err
+		}
+		if _, err := tx.ExecContext(ctx, "DELETE FROM service_versions WHERE id = ?", versionID); err != nil {
+			return nil, err
+		}
+
+		return &version, tx.Commit()
+	})
+}
+
+// ApplyCatalog makes the changes of a catalog apply to the organization's
+// catalog in one transaction, so that a failed apply leaves it untouched. inputs holds the desired
+// fields of created and updated services by name. It returns the IDs of the
+// created services by name.
+func (r *ServiceRepository) ApplyCatalog(ctx context.Context, changes []domain.CatalogChange, inputs map[string]domain.ServiceInput) (_ map[string]int, err error) {
+	ctx, span := tracing.StartDB(ctx, "ServiceRepository.ApplyCatalog")
+	defer func() { tracing.End(span, err) }()
+
+	return retryBusy(ctx, func() (map[string]int, error) {
+		tx, err := r.begin(ctx)
+		if err != nil {
+			return nil, err
+		}
+		defer tx.Rollback()
+
+		orgID := tenant.FromContext(ctx)
+		created := make(map[string]int)
+		for _, change := range changes {
+			id := change.ServiceID
+			switch change.Action {
+			case domain.CatalogActionCreate:
+				if id, err = insertService(ctx, tx, orgID, inputs[change.Service]); err != nil {
+					return nil, err
+				}
+				created[change.Service] = id
+				if err := r.recordEvent(ctx, tx, domain.EventServiceCreated, id, nil); err != nil {
+					return nil, err
+				}
+			case domain.CatalogActionUpdate:
+				if len(change.Fields) > 0 {
+					if _, err := updateService(ctx, tx, orgID, id, inputs[change.Service]); err != nil {
+						return nil, err
+					}
+					if err := r.recordEvent(ctx, tx, domain.EventServiceUpdated, id, nil); err != nil {
+						return nil, err
+					}
+				} else if _, err := touchService(ctx, tx, orgID, id); err != nil {
+					return nil, err
+				}
+			case domain.CatalogActionDelete:
+				if err := r.recordEvent(ctx, tx, domain.EventServiceDeleted, id, nil); err != nil {
+					return nil, err
+				}
+				if _, err := deleteService(ctx, tx, orgID, id); err != nil {
+					return nil, err
+				}
+				continue
+			}
+
+			for _, version := range change.VersionsAdded {
+				if _, err := tx.ExecContext(ctx, "INSERT INTO service_versions (service_id, version) VALUES (?, ?)", id, version); err != nil {
+					return nil, translateError(err)
+				}
+				if r.outbox {
+					added, err := versionInTx(ctx, tx, id, version)
+					if err != nil {
+						return nil, err
+					}
+					if err := r.recordEvent(ctx, tx, domain.EventVersionCreated, id, added); err != nil {
+						return nil, err
+					}
+				}
+			}
+			for _, version := range change.VersionsRemoved {
+				if r.outbox {
+					removed, err := versionInTx(ctx, tx, id, version)
+					if err != nil {
+						return nil, err
+					}
+					if err := r.recordEvent(ctx, tx, domain.EventVersionDeleted, id, removed); err != nil {
+						return nil, err
+					}
+				}
+				if _, err := tx.ExecContext(ctx, `
+					DELETE FROM service_environments
+					WHERE version_id IN (SELECT id FROM service_versions WHERE service_id = ? AND version = ?)`, id, version); err != nil {
+					return nil, err
+				}
+				if _, err := tx.ExecContext(ctx, `
+					DELETE FROM version_specs
+					WHERE version_id IN (SELECT id FROM service_versions WHERE service_id = ? AND version = ?)`, id, version); err != nil {
+					return nil, err
+				}
+				if _, err := tx.ExecContext(ctx, `
+					DELETE FROM sunsets
+					WHERE version_id IN (SELECT id FROM service_versions WHERE service_id = ? AND version = ?)`, id, version); err != nil {
+					return nil, err
+				}
+				if _, err := tx.ExecContext(ctx, "DELETE FROM service_versions WHERE service_id = ? AND version = ?", id, version); err != nil {
+					return nil, err
+				}
+			}
+		}
+
+		return created, tx.Commit()
+	})
+}
+
+// insertService inserts a service with its tags into an organization within
+// a transaction and returns its ID
+func insertService(ctx context.Context, tx dbtx, orgID int, input domain.ServiceInput) (int, error) {
+	result, err := tx.ExecContext(ctx,
+		"INSERT INTO services (org_id, name, description, status, owner) VALUES (?, ?, ?, ?, ?)",
+		orgID, input.Name, input.Description, input.Status, input.Owner,
+	)
+	if err != nil {
+		return 0, translateError(err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+
+	if err := replaceTags(ctx, tx, int(id), input.Tags); err != nil {
+		return 0, err
+	}
+	return int(id), nil
+}
+
+// updateService replaces the fields and tags of a service within a
+// transaction, returning false when it does not exist in the organization
+func updateService(ctx context.Context, tx dbtx, orgID, id int, input domain.ServiceInput) (bool, error) {
+	result, err := tx.ExecContext(ctx, `
+		UPDATE services
+		SET name = ?, description = ?, status = ?, owner = ?, updated_at = CURRENT_TIMESTAMP
+		WHERE id = ? AND org_id = ?`,
+		input.Name, input.Description, input.Status, input.Owner, id, orgID,
+	)
+	if err != nil {
+		return false, translateError(err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	if affected == 0 {
+		return false, nil
+	}
+
+	return true, replaceTags(ctx, tx, id, input.Tags)
+}
+
+// deleteService removes a service with its versions and tags within a
+// transaction, returning false when it does not exist in the organization
+func deleteService(ctx context.Context, tx dbtx, orgID, id int) (bool, error) {
+	result, err := tx.ExecContext(ctx, "DELETE FROM services WHERE id = ? AND org_id = ?", id, orgID)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil || affected == 0 {
+		return false, err
+	}
+
+	// SQLite only enforces ON DELETE CASCADE with the foreign_keys pragma,
+	// so remove the children explicitly
+	if _, err := tx.ExecContext(ctx, "DELETE FROM service_versions WHERE service_id = ?", id); err != nil {
+		return false, err
+	}
+	if _, err := tx.ExecContext(ctx, "DELETE FROM service_tags WHERE service_id = ?", id); err != nil {
+		return false, err
+	}
+	if _, err := tx.ExecContext(ctx, "DELETE FROM service_environments WHERE service_id = ?", id); err != nil {
+		return false, err
+	}
+	if _, err := tx.ExecContext(ctx, "DELETE FROM version_specs WHERE service_id = ?", id); err != nil {
+		return false, err
+	}
+	if _, err := tx.ExecContext(ctx, "DELETE FROM service_imports WHERE service_id = ?", id); err != nil {
+		return false, err
+	}
+	if _, err := tx.ExecContext(ctx, "DELETE FROM service_subscriptions WHERE service_id = ?", id); err != nil {
+		return false, err
+	}
+	if _, err := tx.ExecContext(ctx, "DELETE FROM service_favorites WHERE service_id = ?", id); err != nil {
+		return false, err
+	}
+	if _, err := tx.ExecContext(ctx, "DELETE FROM service_comments WHERE service_id = ?", id); err != nil {
+		return false, err
+	}
+	if _, err := tx.ExecContext(ctx, "DELETE FROM ownership_transfers WHERE service_id = ?", id); err != nil {
+		return false, err
+	}
+	if _, err := tx.ExecContext(ctx, "DELETE FROM sunsets WHERE service_id = ?", id); err != nil {
+		return false, err
+	}
+	if _, err := tx.ExecContext(ctx, "DELETE FROM service_health_checks WHERE service_id = ?", id); err != nil {
+		return false, err
+	}
+	if _, err := tx.ExecContext(ctx, "DELETE FROM service_health_results WHERE service_id = ?", id); err != nil {
+		return false, err
+	}
+	if _, err := tx.ExecContext(ctx, "DELETE FROM service_slos WHERE service_id = ?", id); err != nil {
+		return false, err
+	}
+	if _, err := tx.ExecContext(ctx, "DELETE FROM service_repositories WHERE service_id = ?", id); err != nil {
+		return false, err
+	}
+	if _, err := tx.ExecContext(ctx, "DELETE FROM usage_service_views WHERE service_id = ?", id); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// touchService bumps the updated_at of a service within a transaction,
+// returning false when it does not exist in the organization
+func touchService(ctx context.Context, tx dbtx, orgID, id int) (bool, error) {
+	result, err := tx.ExecContext(ctx, "UPDATE services SET updated_at = CURRENT_TIMESTAMP WHERE id = ? AND org_id = ?", id, orgID)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	return affected > 0, err
+}
+
+// replaceTags overwrites the tags of a service within a transaction
+func replaceTags(ctx context.Context, tx dbtx, serviceID int, tags []string) error {
+	if _, err := tx.ExecContext(ctx, "DELETE FROM service_tags WHERE service_id = ?", serviceID); err != nil {
+		return err
+	}
+	for _, tag := range tags {
+		if _, err := tx.ExecContext(ctx, "INSERT OR IGNORE INTO service_tags (service_id, tag) VALUES (?, ?)", serviceID, tag); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// translateError maps driver specific constraint violations to repository
+// errors. Duplicate primary keys are reported by SQLite as a failed UNIQUE
+// constraint too.
+func translateError(err error) error {
+	var sqliteErr sqlite3.Error
+	if errors.As(err, &sqliteErr) &&
+		(sqliteErr.ExtendedCode == sqlite3.ErrConstraintUnique || sqliteErr.ExtendedCode == sqlite3.ErrConstraintPrimaryKey) {
+		return ErrDuplicate
+	}
+	return err
+}
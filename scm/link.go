@@ -0,0 +1,104 @@
+// Package scm links services to the repositories of their source code on
+// GitHub and GitLab, and refreshes the default branch and latest release of
+// the linked repositories from the providers' APIs.
+package scm
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"com.kong.connect/domain"
+)
+
+const (
+	maxURLLength = 2000
+	// maxGitLabDepth bounds the groups and subgroups of a GitLab project
+	maxGitLabDepth = 20
+)
+
+// hosts maps the hosts of the supported providers to their names
+var hosts = map[string]string{
+	"github.com": domain.ProviderGitHub,
+	"gitlab.com": domain.ProviderGitLab,
+}
+
+// segmentPattern matches the owner, group and repository names of both providers
+var segmentPattern = regexp.MustCompile(`^[A-Za-z0-9_.-]+$`)
+
+// Parse normalizes the URL of a repository on GitHub or GitLab. Web URLs,
+// with or without a scheme, clone URLs and scp-like SSH addresses such as
+// git@github.com:kong/connect.git are accepted; links to a page of the
+// repository, such as its tree, are reduced to the repository. The result is
+// the https web URL of the repository with its provider and path.
+func Parse(raw string) (domain.SourceRepository, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" || len(raw) > maxURLLength {
+		return domain.SourceRepository{}, fmt.Errorf("url must be a GitHub or GitLab repository URL of at most %d characters", maxURLLength)
+	}
+
+	// git@github.com:kong/connect.git
+	if user, rest, ok := strings.Cut(raw, "@"); ok && !strings.Contains(user, "/") && !strings.Contains(raw, "://") {
+		host, path, ok := strings.Cut(rest, ":")
+		if !ok {
+			return domain.SourceRepository{}, fmt.Errorf("url %q is not a repository URL", raw)
+		}
+		raw = "ssh://" + user + "@" + host + "/" + path
+	} else if !strings.Contains(raw, "://") {
+		raw = "https://" + raw
+	}
+	parsed, err := url.Parse(raw)
+	if err != nil {
+		return domain.SourceRepository{}, fmt.Errorf("url %q is not a repository URL", raw)
+	}
+	switch parsed.Scheme {
+	case "http", "https", "ssh", "git":
+	default:
+		return domain.SourceRepository{}, fmt.Errorf("url scheme %q is not supported", parsed.Scheme)
+	}
+
+	host := strings.TrimPrefix(strings.ToLower(parsed.Hostname()), "www.")
+	provider, ok := hosts[host]
+	if !ok {
+		return domain.SourceRepository{}, fmt.Errorf("url host %q is not github.com or gitlab.com", parsed.Hostname())
+	}
+	path, err := repositoryPath(provider, parsed.Path)
+	if err != nil {
+		return domain.SourceRepository{}, err
+	}
+	return domain.SourceRepository{URL: "https://" + host + "/" + path, Provider: provider, Path: path}, nil
+}
+
+// repositoryPath extracts the path of the repository from the path of a URL
+func repositoryPath(provider, path string) (string, error) {
+	// GitLab separates the project from its pages with /-/
+	if provider == domain.ProviderGitLab {
+		path, _, _ = strings.Cut(path, "/-/")
+	}
+	segments := strings.FieldsFunc(path, func(r rune) bool { return r == '/' })
+	if provider == domain.ProviderGitHub && len(segments) > 2 {
+		segments = segments[:2]
+	}
+	if len(segments) > 0 {
+		segments[len(segments)-1] = strings.TrimSuffix(segments[len(segments)-1], ".git")
+	}
+	if len(segments) < 2 || len(segments) > maxGitLabDepth {
+		return "", fmt.Errorf("url must name the owner and the repository, as in https://%s/owner/name", providerHost(provider))
+	}
+	for _, segment := range segments {
+		if !segmentPattern.MatchString(segment) || strings.Trim(segment, ".") == "" {
+			return "", fmt.Errorf("url has an invalid repository name %q", segment)
+		}
+	}
+	return strings.Join(segments, "/"), nil
+}
+
+func providerHost(provider string) string {
+	for host, name := range hosts {
+		if name == provider {
+			return host
+		}
+	}
+	return ""
+}
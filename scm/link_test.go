@@ -0,0 +1,46 @@
+package scm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"com.kong.connect/domain"
+)
+
+func TestParseNormalizesRepositoryURLs(t *testing.T) {
+	for _, test := range []struct {
+		raw  string
+		want domain.SourceRepository
+	}{
+		{"https://github.com/kong/connect", domain.SourceRepository{URL: "https://github.com/kong/connect", Provider: domain.ProviderGitHub, Path: "kong/connect"}},
+		{" http://www.GitHub.com/kong/connect.git/ ", domain.SourceRepository{URL: "https://github.com/kong/connect", Provider: domain.ProviderGitHub, Path: "kong/connect"}},
+		{"github.com/kong/connect/tree/main/docs", domain.SourceRepository{URL: "https://github.com/kong/connect", Provider: domain.ProviderGitHub, Path: "kong/connect"}},
+		{"git@github.com:kong/connect.git", domain.SourceRepository{URL: "https://github.com/kong/connect", Provider: domain.ProviderGitHub, Path: "kong/connect"}},
+		{"ssh://git@github.com/kong/connect.git", domain.SourceRepository{URL: "https://github.com/kong/connect", Provider: domain.ProviderGitHub, Path: "kong/connect"}},
+		{"https://gitlab.com/kong/platform/connect", domain.SourceRepository{URL: "https://gitlab.com/kong/platform/connect", Provider: domain.ProviderGitLab, Path: "kong/platform/connect"}},
+		{"https://gitlab.com/kong/platform/connect/-/tree/main", domain.SourceRepository{URL: "https://gitlab.com/kong/platform/connect", Provider: domain.ProviderGitLab, Path: "kong/platform/connect"}},
+		{"git@gitlab.com:kong/connect.git", domain.SourceRepository{URL: "https://gitlab.com/kong/connect", Provider: domain.ProviderGitLab, Path: "kong/connect"}},
+	} {
+		repository, err := Parse(test.raw)
+		require.NoError(t, err, test.raw)
+		assert.Equal(t, test.want, repository, test.raw)
+	}
+}
+
+func TestParseRejectsOtherURLs(t *testing.T) {
+	for _, raw := range []string{
+		"",
+		"https://bitbucket.org/kong/connect",
+		"https://github.com/kong",
+		"https://github.com/",
+		"ftp://github.com/kong/connect",
+		"https://github.com/kong/con nect",
+		"https://github.com/kong/..",
+		"git@github.com",
+	} {
+		_, err := Parse(raw)
+		assert.Error(t, err, raw)
+	}
+}
@@ -0,0 +1,259 @@
+package scm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+
+	"com.kong.connect/domain"
+	"com.kong.connect/logging"
+	"com.kong.connect/tenant"
+)
+
+// UserAgent identifies the refreshes to the providers
+const UserAgent = "kong-connect-repository-refresh"
+
+const (
+	// DefaultGitHubURL and DefaultGitLabURL are the APIs of github.com and
+	// gitlab.com
+	DefaultGitHubURL = "https://api.github.com"
+	DefaultGitLabURL = "https://gitlab.com/api/v4"
+
+	// concurrency bounds the repositories refreshed at once, to stay clear
+	// of the providers' rate limits
+	concurrency = 4
+	// requestTimeout bounds each request to a provider
+	requestTimeout = 10 * time.Second
+	// maxResponseBytes bounds the responses read from the providers
+	maxResponseBytes = 1 << 20
+	// maxErrorLength truncates the errors recorded
+	maxErrorLength = 500
+)
+
+// errNotFound is returned for resources the provider does not have, or does
+// not show to the token
+var errNotFound = errors.New("not found")
+
+// Config configures the refresher
+type Config struct {
+	// Interval is how often the linked repositories are refreshed; 0
+	// disables refreshing
+	Interval time.Duration
+	// GitHubURL and GitLabURL are the base URLs of the providers' APIs
+	GitHubURL string
+	GitLabURL string
+	// GitHubToken and GitLabToken, when set, authenticate the requests,
+	// which raises the rate limits and gives access to private repositories
+	GitHubToken string
+	GitLabToken string
+}
+
+// Catalog lists the linked repositories and records their details
+type Catalog interface {
+	// LinkedRepositories returns the linked repositories of every organization
+	LinkedRepositories(ctx context.Context) ([]domain.LinkedRepository, error)
+	// RecordRepositoryDetails records a refresh of a repository of the
+	// organization of ctx
+	RecordRepositoryDetails(ctx context.Context, details domain.RepositoryDetails) error
+}
+
+// Refresher refreshes the details of the linked repositories of every
+// organization
+type Refresher struct {
+	cfg     Config
+	catalog Catalog
+	client  *http.Client
+	logger  *slog.Logger
+}
+
+// NewRefresher creates a refresher; a nil client uses http.DefaultClient
+func NewRefresher(cfg Config, catalog Catalog, client *http.Client, logger *slog.Logger) *Refresher {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	if cfg.GitHubURL == "" {
+		cfg.GitHubURL = DefaultGitHubURL
+	}
+	if cfg.GitLabURL == "" {
+		cfg.GitLabURL = DefaultGitLabURL
+	}
+	cfg.GitHubURL = strings.TrimSuffix(cfg.GitHubURL, "/")
+	cfg.GitLabURL = strings.TrimSuffix(cfg.GitLabURL, "/")
+	return &Refresher{cfg: cfg, catalog: catalog, client: client, logger: logging.Component(logger, "scm")}
+}
+
+// Refresh refreshes every linked repository once and records their details;
+// it is run by a job every Config.Interval
+func (r *Refresher) Refresh(ctx context.Context) error {
+	repositories, err := r.catalog.LinkedRepositories(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get linked repositories: %v", err)
+	}
+
+	var failed atomic.Int64
+	var group errgroup.Group
+	group.SetLimit(concurrency)
+	for _, repository := range repositories {
+		group.Go(func() error {
+			details := r.refresh(ctx, repository)
+			if err := r.catalog.RecordRepositoryDetails(tenant.NewContext(ctx, repository.OrgID), details); err != nil {
+				r.logger.Error("failed to record repository details", "service_id", repository.ServiceID, "org_id", repository.OrgID, "error", err)
+				failed.Add(1)
+			}
+			return nil
+		})
+	}
+	group.Wait()
+
+	if n := failed.Load(); n > 0 {
+		return fmt.Errorf("failed to record %d of %d repository details", n, len(repositories))
+	}
+	return nil
+}
+
+// refresh asks the provider of a repository for its details
+func (r *Refresher) refresh(ctx context.Context, repository domain.LinkedRepository) domain.RepositoryDetails {
+	details := domain.RepositoryDetails{ServiceID: repository.ServiceID, URL: repository.URL}
+	var err error
+	switch repository.Provider {
+	case domain.ProviderGitHub:
+		err = r.github(ctx, repository.Path, &details)
+	case domain.ProviderGitLab:
+		err = r.gitlab(ctx, repository.Path, &details)
+	default:
+		err = fmt.Errorf("unknown provider %q", repository.Provider)
+	}
+	details.RefreshedAt = time.Now().UTC()
+	if err != nil {
+		details.Error = truncate(err.Error())
+		r.logger.Debug("failed to refresh repository", "service_id", repository.ServiceID, "org_id", repository.OrgID, "url", repository.URL, "error", err)
+	}
+	return details
+}
+
+// github reads the default branch and the latest release of a repository
+// from the GitHub REST API
+func (r *Refresher) github(ctx context.Context, path string, details *domain.RepositoryDetails) error {
+	var repo struct {
+		DefaultBranch string `json:"default_branch"`
+	}
+	if err := r.get(ctx, r.cfg.GitHubURL+"/repos/"+path, r.githubHeaders, &repo); err != nil {
+		if err == errNotFound {
+			return fmt.Errorf("repository %s not found", path)
+		}
+		return err
+	}
+	details.DefaultBranch = repo.DefaultBranch
+
+	var release struct {
+		TagName     string     `json:"tag_name"`
+		Name        string     `json:"name"`
+		HTMLURL     string     `json:"html_url"`
+		PublishedAt *time.Time `json:"published_at"`
+	}
+	switch err := r.get(ctx, r.cfg.GitHubURL+"/repos/"+path+"/releases/latest", r.githubHeaders, &release); err {
+	case nil:
+		details.LatestRelease = &domain.Release{Tag: release.TagName, Name: release.Name, URL: release.HTMLURL, PublishedAt: release.PublishedAt}
+	case errNotFound:
+		// No release published yet
+	default:
+		return err
+	}
+	return nil
+}
+
+func (r *Refresher) githubHeaders(header http.Header) {
+	header.Set("Accept", "application/vnd.github+json")
+	header.Set("X-GitHub-Api-Version", "2022-11-28")
+	if r.cfg.GitHubToken != "" {
+		header.Set("Authorization", "Bearer "+r.cfg.GitHubToken)
+	}
+}
+
+// gitlab reads the default branch and the latest release of a project from
+// the GitLab REST API
+func (r *Refresher) gitlab(ctx context.Context, path string, details *domain.RepositoryDetails) error {
+	project := r.cfg.GitLabURL + "/projects/" + url.PathEscape(path)
+	var repo struct {
+		DefaultBranch string `json:"default_branch"`
+	}
+	if err := r.get(ctx, project, r.gitlabHeaders, &repo); err != nil {
+		if err == errNotFound {
+			return fmt.Errorf("project %s not found", path)
+		}
+		return err
+	}
+	details.DefaultBranch = repo.DefaultBranch
+
+	// Releases are listed newest first
+	var releases []struct {
+		TagName    string     `json:"tag_name"`
+		Name       string     `json:"name"`
+		ReleasedAt *time.Time `json:"released_at"`
+		Links      struct {
+			Self string `json:"self"`
+		} `json:"_links"`
+	}
+	if err := r.get(ctx, project+"/releases?per_page=1", r.gitlabHeaders, &releases); err != nil && err != errNotFound {
+		return err
+	}
+	if len(releases) > 0 {
+		release := releases[0]
+		details.LatestRelease = &domain.Release{Tag: release.TagName, Name: release.Name, URL: release.Links.Self, PublishedAt: release.ReleasedAt}
+	}
+	return nil
+}
+
+func (r *Refresher) gitlabHeaders(header http.Header) {
+	if r.cfg.GitLabToken != "" {
+		header.Set("PRIVATE-TOKEN", r.cfg.GitLabToken)
+	}
+}
+
+// get decodes the JSON answer of a provider to a GET request into v
+func (r *Refresher) get(ctx context.Context, url string, headers func(http.Header), v interface{}) error {
+	ctx, cancel := context.WithTimeout(ctx, requestTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("User-Agent", UserAgent)
+	headers(req.Header)
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		io.Copy(io.Discard, io.LimitReader(resp.Body, maxResponseBytes))
+		return errNotFound
+	case resp.StatusCode < 200 || resp.StatusCode > 299:
+		io.Copy(io.Discard, io.LimitReader(resp.Body, maxResponseBytes))
+		return fmt.Errorf("%s answered %s", req.URL.Host, resp.Status)
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxResponseBytes)).Decode(v); err != nil {
+		return fmt.Errorf("invalid answer from %s: %v", req.URL.Host, err)
+	}
+	return nil
+}
+
+func truncate(message string) string {
+	if len(message) > maxErrorLength {
+		return message[:maxErrorLength]
+	}
+	return message
+}
@@ -0,0 +1,112 @@
+package scm
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"com.kong.connect/domain"
+	"com.kong.connect/tenant"
+)
+
+// recordingCatalog serves repositories and keeps the details recorded, with
+// the organization they were recorded in
+type recordingCatalog struct {
+	repositories []domain.LinkedRepository
+
+	mu      sync.Mutex
+	details []domain.RepositoryDetails
+	orgs    map[int]int
+}
+
+func (c *recordingCatalog) LinkedRepositories(ctx context.Context) ([]domain.LinkedRepository, error) {
+	return c.repositories, nil
+}
+
+func (c *recordingCatalog) RecordRepositoryDetails(ctx context.Context, details domain.RepositoryDetails) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.details = append(c.details, details)
+	c.orgs[details.ServiceID] = tenant.FromContext(ctx)
+	return nil
+}
+
+func TestRefreshReadsGitHubAndGitLab(t *testing.T) {
+	var mu sync.Mutex
+	headers := map[string]http.Header{}
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		headers[r.URL.EscapedPath()] = r.Header
+		mu.Unlock()
+		switch r.URL.EscapedPath() {
+		case "/github/repos/kong/connect":
+			w.Write([]byte(`{"full_name": "kong/connect", "default_branch": "main"}`))
+		case "/github/repos/kong/connect/releases/latest":
+			w.Write([]byte(`{"tag_name": "v1.2.0", "name": "Spring", "html_url": "https://github.com/kong/connect/releases/tag/v1.2.0", "published_at": "2026-03-01T12:00:00Z"}`))
+		case "/github/repos/kong/fresh":
+			w.Write([]byte(`{"default_branch": "trunk"}`))
+		case "/gitlab/projects/kong%2Fplatform%2Fconnect":
+			w.Write([]byte(`{"id": 7, "default_branch": "develop"}`))
+		case "/gitlab/projects/kong%2Fplatform%2Fconnect/releases":
+			w.Write([]byte(`[{"tag_name": "2.0.0", "name": "2.0", "released_at": "2026-04-01T08:30:00.123Z", "_links": {"self": "https://gitlab.com/kong/platform/connect/-/releases/2.0.0"}}]`))
+		case "/github/repos/kong/limited":
+			http.Error(w, `{"message": "API rate limit exceeded"}`, http.StatusForbidden)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer api.Close()
+
+	catalog := &recordingCatalog{orgs: map[int]int{}, repositories: []domain.LinkedRepository{
+		{OrgID: 1, ServiceID: 1, URL: "https://github.com/kong/connect", Provider: domain.ProviderGitHub, Path: "kong/connect"},
+		{OrgID: 1, ServiceID: 2, URL: "https://github.com/kong/fresh", Provider: domain.ProviderGitHub, Path: "kong/fresh"},
+		{OrgID: 2, ServiceID: 3, URL: "https://gitlab.com/kong/platform/connect", Provider: domain.ProviderGitLab, Path: "kong/platform/connect"},
+		{OrgID: 2, ServiceID: 4, URL: "https://github.com/kong/limited", Provider: domain.ProviderGitHub, Path: "kong/limited"},
+		{OrgID: 2, ServiceID: 5, URL: "https://github.com/kong/gone", Provider: domain.ProviderGitHub, Path: "kong/gone"},
+	}}
+	refresher := NewRefresher(Config{GitHubURL: api.URL + "/github/", GitLabURL: api.URL + "/gitlab", GitHubToken: "ghp_secret", GitLabToken: "glpat-secret"},
+		catalog, api.Client(), nil)
+	require.NoError(t, refresher.Refresh(context.Background()))
+
+	require.Len(t, catalog.details, 5)
+	sort.Slice(catalog.details, func(i, j int) bool { return catalog.details[i].ServiceID < catalog.details[j].ServiceID })
+	for _, details := range catalog.details {
+		assert.WithinDuration(t, time.Now(), details.RefreshedAt, time.Minute)
+	}
+	published := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	assert.Equal(t, "main", catalog.details[0].DefaultBranch)
+	require.NotNil(t, catalog.details[0].LatestRelease)
+	assert.Equal(t, "v1.2.0", catalog.details[0].LatestRelease.Tag)
+	assert.Equal(t, "Spring", catalog.details[0].LatestRelease.Name)
+	assert.Equal(t, "https://github.com/kong/connect/releases/tag/v1.2.0", catalog.details[0].LatestRelease.URL)
+	assert.True(t, published.Equal(*catalog.details[0].LatestRelease.PublishedAt))
+	assert.Empty(t, catalog.details[0].Error)
+
+	// Repositories without releases
+	assert.Equal(t, "trunk", catalog.details[1].DefaultBranch)
+	assert.Nil(t, catalog.details[1].LatestRelease)
+	assert.Empty(t, catalog.details[1].Error)
+
+	assert.Equal(t, "develop", catalog.details[2].DefaultBranch)
+	require.NotNil(t, catalog.details[2].LatestRelease)
+	assert.Equal(t, "2.0.0", catalog.details[2].LatestRelease.Tag)
+	assert.Equal(t, "https://gitlab.com/kong/platform/connect/-/releases/2.0.0", catalog.details[2].LatestRelease.URL)
+
+	assert.Contains(t, catalog.details[3].Error, "403 Forbidden")
+	assert.Equal(t, "repository kong/gone not found", catalog.details[4].Error)
+	assert.Equal(t, map[int]int{1: 1, 2: 1, 3: 2, 4: 2, 5: 2}, catalog.orgs)
+
+	// Tokens authenticate the requests of their provider
+	assert.Equal(t, "Bearer ghp_secret", headers["/github/repos/kong/connect"].Get("Authorization"))
+	assert.Equal(t, "application/vnd.github+json", headers["/github/repos/kong/connect"].Get("Accept"))
+	assert.Equal(t, UserAgent, headers["/github/repos/kong/connect"].Get("User-Agent"))
+	assert.Equal(t, "glpat-secret", headers["/gitlab/projects/kong%2Fplatform%2Fconnect"].Get("PRIVATE-TOKEN"))
+	assert.Empty(t, headers["/gitlab/projects/kong%2Fplatform%2Fconnect"].Get("Authorization"))
+}
@@ -0,0 +1,110 @@
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// Provider reads secrets from a secrets manager
+type Provider interface {
+	// Secret returns the secret named by ref, the part of a reference between
+	// the scheme and the optional #field
+	Secret(ctx context.Context, ref string) ([]byte, error)
+}
+
+// Factory creates a provider the first time one of its references is resolved,
+// so that credentials are only needed for the secrets managers in use
+type Factory func(ctx context.Context) (Provider, error)
+
+// Resolver replaces secret references such as vault://secret/data/app#dsn
+// with the secrets they name
+type Resolver struct {
+	mu        sync.Mutex
+	factories map[string]Factory
+	providers map[string]Provider
+}
+
+// NewResolver creates a resolver for the vault://, awssm:// and gcpsm:// schemes
+func NewResolver() *Resolver {
+	return &Resolver{
+		factories: map[string]Factory{
+			"vault": func(ctx context.Context) (Provider, error) { return NewVault() },
+			"awssm": func(ctx context.Context) (Provider, error) { return NewAWS(ctx) },
+			"gcpsm": func(ctx context.Context) (Provider, error) { return NewGCP(ctx) },
+		},
+		providers: map[string]Provider{},
+	}
+}
+
+// Register serves references of scheme from provider, replacing the built-in one
+func (r *Resolver) Register(scheme string, provider Provider) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.providers[scheme] = provider
+}
+
+// Resolve returns the secret named by value when it is a reference and value
+// itself otherwise. A reference is scheme://ref with an optional #field that
+// selects a key of a secret holding a JSON object.
+func (r *Resolver) Resolve(ctx context.Context, value string) (string, error) {
+	scheme, rest, ok := strings.Cut(value, "://")
+	if !ok || !r.known(scheme) {
+		return value, nil
+	}
+	ref, field, _ := strings.Cut(rest, "#")
+	if ref == "" {
+		return "", fmt.Errorf("secret reference %q names no secret", value)
+	}
+
+	provider, err := r.provider(ctx, scheme)
+	if err != nil {
+		return "", err
+	}
+	secret, err := provider.Secret(ctx, ref)
+	if err != nil {
+		return "", fmt.Errorf("failed to read secret %s://%s: %v", scheme, ref, err)
+	}
+	if field == "" {
+		return string(secret), nil
+	}
+
+	fields := map[string]interface{}{}
+	if err := json.Unmarshal(secret, &fields); err != nil {
+		return "", fmt.Errorf("secret %s://%s is not a JSON object, cannot select %q", scheme, ref, field)
+	}
+	selected, ok := fields[field]
+	if !ok {
+		return "", fmt.Errorf("secret %s://%s has no field %q", scheme, ref, field)
+	}
+	if s, ok := selected.(string); ok {
+		return s, nil
+	}
+	return fmt.Sprint(selected), nil
+}
+
+// known reports whether scheme is served by a registered or built-in provider
+func (r *Resolver) known(scheme string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	_, registered := r.providers[scheme]
+	_, builtIn := r.factories[scheme]
+	return registered || builtIn
+}
+
+// provider returns the provider of scheme, creating it on first use
+func (r *Resolver) provider(ctx context.Context, scheme string) (Provider, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if provider, ok := r.providers[scheme]; ok {
+		return provider, nil
+	}
+	provider, err := r.factories[scheme](ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s secrets provider: %v", scheme, err)
+	}
+	r.providers[scheme] = provider
+	return provider, nil
+}
@@ -0,0 +1,134 @@
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	vault "github.com/hashicorp/vault/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// staticProvider serves secrets from a map and counts reads
+type staticProvider struct {
+	secrets map[string]string
+	reads   int
+}
+
+func (p *staticProvider) Secret(_ context.Context, ref string) ([]byte, error) {
+	p.reads++
+	secret, ok := p.secrets[ref]
+	if !ok {
+		return nil, errors.New("not found")
+	}
+	return []byte(secret), nil
+}
+
+func TestResolverResolvesReferences(t *testing.T) {
+	provider := &staticProvider{secrets: map[string]string{
+		"app":   `{"dsn": "/data/services.db", "port": 5432}`,
+		"plain": "admin-token=admin:admin",
+	}}
+	resolver := NewResolver()
+	resolver.Register("vault", provider)
+	ctx := context.Background()
+
+	for value, want := range map[string]string{
+		"vault://app#dsn":        "/data/services.db",
+		"vault://app#port":       "5432",
+		"vault://plain":          "admin-token=admin:admin",
+		"./services.db":          "./services.db",
+		"redis://localhost:6379": "redis://localhost:6379",
+		"":                       "",
+	} {
+		got, err := resolver.Resolve(ctx, value)
+		require.NoError(t, err, value)
+		assert.Equal(t, want, got, value)
+	}
+	assert.Equal(t, 3, provider.reads)
+
+	for _, value := range []string{"vault://app#user", "vault://plain#dsn", "vault://missing", "vault://#dsn"} {
+		_, err := resolver.Resolve(ctx, value)
+		assert.Error(t, err, value)
+	}
+}
+
+func TestVaultUnwrapsKVVersion2(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "vault-token", r.Header.Get("X-Vault-Token"))
+		switch r.URL.Path {
+		case "/v1/secret/data/kong-connect":
+			w.Write([]byte(`{"data": {"data": {"dsn": "/data/v2.db"}, "metadata": {"version": 3}}}`))
+		case "/v1/kv/kong-connect":
+			w.Write([]byte(`{"data": {"dsn": "/data/v1.db"}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"errors": []}`))
+		}
+	}))
+	defer server.Close()
+
+	client, err := vault.NewClient(&vault.Config{Address: server.URL})
+	require.NoError(t, err)
+	client.SetToken("vault-token")
+	resolver := NewResolver()
+	resolver.Register("vault", &Vault{client: client})
+
+	dsn, err := resolver.Resolve(context.Background(), "vault://secret/data/kong-connect#dsn")
+	require.NoError(t, err)
+	assert.Equal(t, "/data/v2.db", dsn)
+
+	dsn, err = resolver.Resolve(context.Background(), "vault://kv/kong-connect#dsn")
+	require.NoError(t, err)
+	assert.Equal(t, "/data/v1.db", dsn)
+
+	_, err = resolver.Resolve(context.Background(), "vault://secret/data/missing#dsn")
+	assert.Error(t, err)
+}
+
+func TestAWSReadsSecretString(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "secretsmanager.GetSecretValue", r.Header.Get("X-Amz-Target"))
+		body, _ := io.ReadAll(r.Body)
+		var input struct{ SecretId string }
+		require.NoError(t, json.Unmarshal(body, &input))
+		assert.Equal(t, "kong-connect/prod", input.SecretId)
+		w.Header().Set("Content-Type", "application/x-amz-json-1.1")
+		w.Write([]byte(`{"Name": "kong-connect/prod", "SecretString": "{\"admin_token\": \"s3cr3t=admin:admin\"}"}`))
+	}))
+	defer server.Close()
+
+	provider := newAWS(aws.Config{
+		Region:      "eu-west-1",
+		Credentials: credentials.NewStaticCredentialsProvider("key", "secret", ""),
+	}, func(o *secretsmanager.Options) { o.BaseEndpoint = aws.String(server.URL) })
+	resolver := NewResolver()
+	resolver.Register("awssm", provider)
+
+	token, err := resolver.Resolve(context.Background(), "awssm://kong-connect/prod#admin_token")
+	require.NoError(t, err)
+	assert.Equal(t, "s3cr3t=admin:admin", token)
+}
+
+func TestGCPReadsLatestVersion(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/projects/acme/secrets/dsn/versions/latest:access", r.URL.Path)
+		w.Write([]byte(`{"name": "projects/acme/secrets/dsn/versions/4", "payload": {"data": "L2RhdGEvZ2NwLmRi"}}`))
+	}))
+	defer server.Close()
+
+	resolver := NewResolver()
+	resolver.Register("gcpsm", &GCP{client: server.Client(), endpoint: server.URL + "/"})
+
+	dsn, err := resolver.Resolve(context.Background(), "gcpsm://projects/acme/secrets/dsn")
+	require.NoError(t, err)
+	assert.Equal(t, "/data/gcp.db", dsn)
+}
@@ -0,0 +1,46 @@
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	vault "github.com/hashicorp/vault/api"
+)
+
+// Vault reads secrets from HashiCorp Vault. The address, token and TLS
+// settings come from the standard VAULT_ADDR, VAULT_TOKEN and VAULT_CACERT
+// environment variables. References are logical paths such as
+// vault://secret/data/kong-connect#dsn; the data of KV version 2 secrets is
+// unwrapped, so fields are selected the same way for both KV versions.
+type Vault struct {
+	client *vault.Client
+}
+
+// NewVault creates a Vault provider configured from the environment
+func NewVault() (*Vault, error) {
+	client, err := vault.NewClient(vault.DefaultConfig())
+	if err != nil {
+		return nil, err
+	}
+	return &Vault{client: client}, nil
+}
+
+// Secret returns the data of the secret at path as a JSON object
+func (v *Vault) Secret(ctx context.Context, path string) ([]byte, error) {
+	secret, err := v.client.Logical().ReadWithContext(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	if secret == nil || secret.Data == nil {
+		return nil, fmt.Errorf("no secret at %s", path)
+	}
+
+	data := secret.Data
+	if nested, ok := data["data"].(map[string]interface{}); ok {
+		if _, versioned := data["metadata"]; versioned {
+			data = nested
+		}
+	}
+	return json.Marshal(data)
+}
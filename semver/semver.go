@@ -0,0 +1,140 @@
+// Package semver orders and normalizes the version strings of services,
+// which teams write in conventions such as "1.2.3", "v1.2" or "2.0.0-rc.1".
+package semver
+
+import (
+	"strconv"
+	"strings"
+)
+
+// version is a parsed semantic version; strings that don't parse keep only raw
+type version struct {
+	raw        string
+	valid      bool
+	prefixed   bool
+	parts      int
+	numbers    [3]int
+	prerelease string
+	build      string
+}
+
+// parse parses versions such as "1.2.3", "v1.2" and "2.0.0-rc.1"
+func parse(raw string) version {
+	v := version{raw: raw}
+	s := strings.TrimSpace(raw)
+	s, v.prefixed = strings.CutPrefix(s, "v")
+	if i := strings.IndexByte(s, '+'); i >= 0 {
+		v.build = s[i+1:]
+		s = s[:i]
+	}
+	if i := strings.IndexByte(s, '-'); i >= 0 {
+		v.prerelease = s[i+1:]
+		s = s[:i]
+	}
+
+	parts := strings.Split(s, ".")
+	if len(parts) == 0 || len(parts) > 3 {
+		return v
+	}
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return v
+		}
+		v.numbers[i] = n
+	}
+	v.parts = len(parts)
+	v.valid = true
+	return v
+}
+
+// Canonical returns a version as major.minor.patch, followed by its
+// pre-release and build metadata: "v1.2" becomes "1.2.0". Versions that are
+// not semantic are returned trimmed, and so are bare numbers such as "42" or
+// "2024-01", which are more likely counters or dates than major versions.
+func Canonical(raw string) string {
+	v := parse(raw)
+	if !v.valid || (v.parts == 1 && !v.prefixed) {
+		return strings.TrimSpace(raw)
+	}
+
+	canonical := strconv.Itoa(v.numbers[0]) + "." + strconv.Itoa(v.numbers[1]) + "." + strconv.Itoa(v.numbers[2])
+	if v.prerelease != "" {
+		canonical += "-" + v.prerelease
+	}
+	if v.build != "" {
+		canonical += "+" + v.build
+	}
+	return canonical
+}
+
+// Compare orders versions by semver precedence. Unparseable versions sort
+// before valid ones and compare lexically among themselves. Build metadata is
+// ignored as it has no bearing on precedence.
+func Compare(a, b string) int {
+	va, vb := parse(a), parse(b)
+	switch {
+	case !va.valid && !vb.valid:
+		return strings.Compare(va.raw, vb.raw)
+	case !va.valid:
+		return -1
+	case !vb.valid:
+		return 1
+	}
+
+	for i := range va.numbers {
+		if va.numbers[i] != vb.numbers[i] {
+			if va.numbers[i] < vb.numbers[i] {
+				return -1
+			}
+			return 1
+		}
+	}
+
+	return comparePrerelease(va.prerelease, vb.prerelease)
+}
+
+// comparePrerelease applies the semver rules for pre-release identifiers:
+// a release outranks any pre-release, numeric identifiers compare numerically
+// and rank below alphanumeric ones.
+func comparePrerelease(a, b string) int {
+	switch {
+	case a == b:
+		return 0
+	case a == "":
+		return 1
+	case b == "":
+		return -1
+	}
+
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(as) && i < len(bs); i++ {
+		an, aErr := strconv.Atoi(as[i])
+		bn, bErr := strconv.Atoi(bs[i])
+		switch {
+		case aErr == nil && bErr == nil:
+			if an != bn {
+				if an < bn {
+					return -1
+				}
+				return 1
+			}
+		case aErr == nil:
+			return -1
+		case bErr == nil:
+			return 1
+		default:
+			if c := strings.Compare(as[i], bs[i]); c != 0 {
+				return c
+			}
+		}
+	}
+
+	switch {
+	case len(as) < len(bs):
+		return -1
+	case len(as) > len(bs):
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,49 @@
+package semver
+
+import "testing"
+
+func TestCompareSemver(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"1.0.0", "1.0.0", 0},
+		{"1.2.0", "1.10.0", -1},
+		{"2.0.0", "1.99.99", 1},
+		{"v1.2.0", "1.2.0", 0},
+		{"1.2", "1.2.0", 0},
+		{"1.0.0-rc.1", "1.0.0", -1},
+		{"1.0.0-alpha", "1.0.0-alpha.1", -1},
+		{"1.0.0-alpha.2", "1.0.0-alpha.10", -1},
+		{"1.0.0-1", "1.0.0-alpha", -1},
+		{"1.0.0+build.5", "1.0.0", 0},
+		{"latest", "0.0.1", -1},
+		{"abc", "abd", -1},
+	}
+
+	for _, tt := range tests {
+		if got := Compare(tt.a, tt.b); got != tt.want {
+			t.Errorf("Compare(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestCanonical(t *testing.T) {
+	tests := map[string]string{
+		"1.2.0":             "1.2.0",
+		" v1.2.0 ":          "1.2.0",
+		"1.2":               "1.2.0",
+		"v2":                "2.0.0",
+		"01.02.03":          "1.2.3",
+		"v1.0-rc.1+exp.sha": "1.0.0-rc.1+exp.sha",
+		"42":                "42",
+		"2024-01":           "2024-01",
+		"latest":            "latest",
+		"1.2.3.4":           "1.2.3.4",
+	}
+	for raw, want := range tests {
+		if got := Canonical(raw); got != want {
+			t.Errorf("Canonical(%q) = %q, want %q", raw, got, want)
+		}
+	}
+}
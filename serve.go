@@ -0,0 +1,70 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"com.kong.connect/config"
+	"com.kong.connect/server"
+)
+
+// runServe starts the HTTP server and blocks until SIGINT or SIGTERM, then
+// drains in-flight requests and releases resources
+func runServe(a *app, args []string) error {
+	if err := a.parseFlags(flag.NewFlagSet("serve", flag.ContinueOnError), args); err != nil {
+		return err
+	}
+
+	// SIGINT and SIGTERM start a graceful shutdown
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	// A second signal terminates immediately
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
+
+	srv := server.New(a.cfg, server.WithLogger(a.logger, a.logLevel))
+
+	// Log level, CORS origins, rate limits and tokens are reloaded from the config file on SIGHUP
+	go reloadOnSIGHUP(ctx, srv, a.configPath, a.overrides, a.logLevel, a.logger)
+
+	err := srv.Run(ctx)
+	if err == nil {
+		a.logger.Info("server stopped")
+	}
+
+	// Log records are written unbuffered; sync in case the outputs are files
+	os.Stdout.Sync()
+	os.Stderr.Sync()
+	return err
+}
+
+// reloadOnSIGHUP reloads the runtime settings whenever the process receives
+// SIGHUP. Invalid settings are logged and the current ones stay in effect.
+func reloadOnSIGHUP(ctx context.Context, srv *server.Server, path string, overrides map[string]string, logLevel *slog.LevelVar, logger *slog.Logger) {
+	hangups := make(chan os.Signal, 1)
+	signal.Notify(hangups, syscall.SIGHUP)
+	defer signal.Stop(hangups)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-hangups:
+		}
+
+		cfg, err := config.Load(path, overrides)
+		if err != nil {
+			logger.Error("failed to reload configuration, keeping current settings", "error", err)
+			continue
+		}
+		srv.Reload(cfg.Runtime)
+		logger.Info("runtime configuration reloaded", "path", path, "log_level", logLevel.Level(),
+			"cors_origins", cfg.Runtime.CORSOrigins, "rate_limit_groups", len(cfg.Runtime.RateLimits), "tokens", len(cfg.Runtime.Tokens), "feature_flags", cfg.Runtime.Features)
+	}
+}
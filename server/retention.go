@@ -0,0 +1,98 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"com.kong.connect/service"
+)
+
+// retentionRule permanently deletes the rows of one kind once they are older
+// than period
+type retentionRule struct {
+	name   string
+	period time.Duration
+	// count returns how many rows purge would delete
+	count func(ctx context.Context, before time.Time) (int64, error)
+	purge func(ctx context.Context, before time.Time) (int64, error)
+}
+
+// retentionRules lists the data kept for a limited time: audit entries,
+// finished webhook deliveries, health check results and catalog usage.
+// Services and
+// versions are deleted immediately and there are no idempotency keys.
+func retentionRules(svc service.ServiceServiceInterface, auditRetentionDays, webhookRetentionDays, healthCheckRetentionDays, analyticsRetentionDays int) []retentionRule {
+	var rules []retentionRule
+	// AUDIT_RETENTION_DAYS=0 keeps audit entries forever
+	if auditRetentionDays > 0 {
+		rules = append(rules, retentionRule{
+			name:   "audit_logs",
+			period: time.Duration(auditRetentionDays) * 24 * time.Hour,
+			count:  svc.CountAuditLogsBefore,
+			purge:  svc.PurgeAuditLogs,
+		})
+	}
+	// WEBHOOK_RETENTION_DAYS=0 keeps the delivery log forever
+	if webhookRetentionDays > 0 {
+		rules = append(rules, retentionRule{
+			name:   "webhook_deliveries",
+			period: time.Duration(webhookRetentionDays) * 24 * time.Hour,
+			count:  svc.CountWebhookDeliveriesBefore,
+			purge:  svc.PurgeWebhookDeliveries,
+		})
+	}
+	// HEALTH_CHECK_RETENTION_DAYS=0 keeps the probe results forever
+	if healthCheckRetentionDays > 0 {
+		rules = append(rules, retentionRule{
+			name:   "service_health_results",
+			period: time.Duration(healthCheckRetentionDays) * 24 * time.Hour,
+			count:  svc.CountHealthResultsBefore,
+			purge:  svc.PurgeHealthResults,
+		})
+	}
+	// ANALYTICS_RETENTION_DAYS=0 keeps the usage counts forever
+	if analyticsRetentionDays > 0 {
+		rules = append(rules, retentionRule{
+			name:   "usage",
+			period: time.Duration(analyticsRetentionDays) * 24 * time.Hour,
+			count:  svc.CountUsageBefore,
+			purge:  svc.PurgeUsage,
+		})
+	}
+	return rules
+}
+
+// runRetention applies every rule; in a dry run it only logs how many rows
+// would be deleted. All rules are attempted even when one fails.
+func runRetention(ctx context.Context, rules []retentionRule, dryRun bool, now time.Time, logger *slog.Logger) error {
+	var failed []string
+	for _, rule := range rules {
+		before := now.Add(-rule.period)
+		if dryRun {
+			count, err := rule.count(ctx, before)
+			if err != nil {
+				logger.Error("retention dry run failed", "kind", rule.name, "error", err)
+				failed = append(failed, rule.name)
+				continue
+			}
+			logger.Info("retention dry run, nothing deleted", "kind", rule.name, "would_delete", count, "before", before)
+			continue
+		}
+
+		purged, err := rule.purge(ctx, before)
+		if err != nil {
+			logger.Error("retention purge failed", "kind", rule.name, "error", err)
+			failed = append(failed, rule.name)
+			continue
+		}
+		if purged > 0 {
+			logger.Info("retention purge deleted expired rows", "kind", rule.name, "deleted", purged, "before", before)
+		}
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("retention failed for %v", failed)
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// expiringRows is a retention target holding rows created at the given times
+type expiringRows struct {
+	created []time.Time
+	err     error
+}
+
+func (e *expiringRows) count(_ context.Context, before time.Time) (int64, error) {
+	var n int64
+	for _, created := range e.created {
+		if created.Before(before) {
+			n++
+		}
+	}
+	return n, e.err
+}
+
+func (e *expiringRows) purge(ctx context.Context, before time.Time) (int64, error) {
+	if e.err != nil {
+		return 0, e.err
+	}
+	var kept []time.Time
+	for _, created := range e.created {
+		if !created.Before(before) {
+			kept = append(kept, created)
+		}
+	}
+	n := int64(len(e.created) - len(kept))
+	e.created = kept
+	return n, nil
+}
+
+func TestRunRetention(t *testing.T) {
+	now := time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)
+	audit := &expiringRows{created: []time.Time{now.AddDate(0, 0, -400), now.AddDate(0, 0, -100), now}}
+	broken := &expiringRows{err: errors.New("database is locked")}
+	rules := []retentionRule{
+		{name: "broken", period: time.Hour, count: broken.count, purge: broken.purge},
+		{name: "audit_logs", period: 365 * 24 * time.Hour, count: audit.count, purge: audit.purge},
+	}
+
+	var logs bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&logs, nil))
+
+	// A dry run reports what would go and keeps everything
+	err := runRetention(context.Background(), rules[1:], true, now, logger)
+	require.NoError(t, err)
+	assert.Len(t, audit.created, 3)
+	assert.Contains(t, logs.String(), "kind=audit_logs would_delete=1")
+
+	// A failing rule does not stop the others
+	err = runRetention(context.Background(), rules, false, now, logger)
+	assert.ErrorContains(t, err, "broken")
+	assert.Len(t, audit.created, 2)
+	assert.Contains(t, logs.String(), "kind=audit_logs deleted=1")
+}
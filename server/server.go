@@ -0,0 +1,672 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"com.kong.connect/analytics"
+	"com.kong.connect/cache"
+	"com.kong.connect/cdn"
+	"com.kong.connect/config"
+	"com.kong.connect/consul"
+	"com.kong.connect/database"
+	"com.kong.connect/domain"
+	"com.kong.connect/email"
+	"com.kong.connect/errreport"
+	"com.kong.connect/events"
+	"com.kong.connect/features"
+	"com.kong.connect/handler"
+	"com.kong.connect/health"
+	"com.kong.connect/jobs"
+	"com.kong.connect/kube"
+	"com.kong.connect/logging"
+	"com.kong.connect/metrics"
+	"com.kong.connect/middleware"
+	"com.kong.connect/notify"
+	"com.kong.connect/outbox"
+	"com.kong.connect/probe"
+	"com.kong.connect/ratelimit"
+	"com.kong.connect/realtime"
+	"com.kong.connect/repository"
+	"com.kong.connect/scm"
+	"com.kong.connect/service"
+	"com.kong.connect/tracing"
+	"com.kong.connect/web"
+	"com.kong.connect/webhook"
+)
+
+// errorFlushTimeout bounds waiting for queued error reports at shutdown
+const errorFlushTimeout = 5 * time.Second
+
+// Server is the complete catalog server: database, background jobs, router and
+// listeners. Bearer tokens are process wide, so a process runs at most one
+// Server at a time.
+type Server struct {
+	cfg      *config.Config
+	logger   *slog.Logger
+	logLevel *slog.LevelVar
+	listener net.Listener
+
+	// Settings reloaded while running
+	cors       *middleware.CORS
+	rateLimits *ratelimit.Policy
+	features   *features.Store
+}
+
+// Option configures a Server
+type Option func(*Server)
+
+// WithLogger logs through logger instead of the default logger. LOG_LEVEL
+// changes are applied to level, which may be nil when the embedding program
+// manages the level itself.
+func WithLogger(logger *slog.Logger, level *slog.LevelVar) Option {
+	return func(s *Server) {
+		s.logger = logger
+		if level != nil {
+			s.logLevel = level
+		}
+	}
+}
+
+// WithListener serves on ln instead of listening on the configured port, e.g.
+// on 127.0.0.1:0 in tests. TLS settings still apply.
+func WithListener(ln net.Listener) Option {
+	return func(s *Server) {
+		s.listener = ln
+	}
+}
+
+// New creates a server from cfg, as returned by config.Load. Nothing is
+// opened until Run.
+func New(cfg *config.Config, opts ...Option) *Server {
+	s := &Server{
+		cfg:        cfg,
+		logger:     slog.Default(),
+		logLevel:   new(slog.LevelVar),
+		cors:       middleware.NewCORS(cfg.Runtime.CORSOrigins),
+		rateLimits: ratelimit.NewPolicy(cfg.Runtime.RateLimits),
+		features:   features.NewStore(cfg.Runtime.Features),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Reload switches the running server to new runtime settings: log level, CORS
+// origins, rate limits, bearer tokens and feature flags
+func (s *Server) Reload(runtime config.Runtime) {
+	// Validated by config.Load
+	logging.SetLevel(s.logLevel, runtime.LogLevel)
+	s.cors.SetOrigins(runtime.CORSOrigins)
+	s.rateLimits.Set(runtime.RateLimits)
+	middleware.SetTokens(runtime.Tokens)
+	s.features.Set(runtime.Features)
+}
+
+// Run opens the database, starts serving and blocks until ctx is done, then
+// drains in-flight requests for up to the shutdown timeout and releases
+// resources. It returns early with an error when a listener fails.
+func (s *Server) Run(ctx context.Context) error {
+	cfg, logger := s.cfg, s.logger
+	// Background jobs stop when Run returns, also when a listener failed
+	ctx, cancelJobs := context.WithCancel(ctx)
+	defer cancelJobs()
+
+	// Initialize tracing; exporting is configured through the standard OTEL_* variables
+	shutdownTracing, err := tracing.Init(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to initialize tracing: %v", err)
+	}
+
+	// Initialize database
+	db, err := database.Open(cfg.Database.DSN)
+	if err != nil {
+		return fmt.Errorf("failed to initialize database: %v", err)
+	}
+	defer func() {
+		if err := database.Close(db); err != nil {
+			logger.Error("failed to close database", "error", err)
+		}
+	}()
+	if err := database.Migrate(db); err != nil {
+		return fmt.Errorf("failed to initialize database: %v", err)
+	}
+	// SEED_ON_START inserts the sample catalog into an empty database, for development only
+	if cfg.Database.SeedOnStart {
+		if _, err := database.Seed(db); err != nil {
+			return fmt.Errorf("failed to initialize database: %v", err)
+		}
+	}
+	logger.Info("database initialized", "path", cfg.Database.DSN)
+
+	// Change notifications flow from the service layer to WebSocket subscribers
+	bus := events.NewBus()
+	hub := realtime.NewHub(logger)
+	bus.Subscribe(hub.Publish)
+	var publisher events.Publisher = bus
+
+	// /readyz reports the dependencies; the instance is unready without its database
+	readiness := health.NewChecker(health.Check{Name: "database", Critical: true, Probe: db.PingContext})
+
+	// REDIS_URL coordinates replicas: change events made on one instance reach
+	// the WebSocket clients of every instance
+	var sharedRedis *redis.Client
+	if cfg.RedisURL != "" {
+		if sharedRedis, err = newRedisClient("REDIS_URL", cfg.RedisURL); err != nil {
+			return err
+		}
+		defer sharedRedis.Close()
+		// Stopped before the client closes
+		relayCtx, stopRelay := context.WithCancel(ctx)
+		defer stopRelay()
+		relay := events.NewRelay(sharedRedis, "kong-connect:events", bus, logger)
+		go relay.Run(relayCtx)
+		publisher = relay
+		// Instances keep serving without Redis, only their clients miss remote changes
+		readiness.Register(redisCheck("redis", sharedRedis))
+	}
+
+	// HTTP_CACHE_PURGE_URL purges the responses cached by CDNs on every change
+	// made through this instance
+	if cfg.HTTPCachePurgeURL != "" {
+		publisher = events.Publishers{publisher, cdn.NewPurger(cfg.HTTPCachePurgeURL, cfg.HTTPCachePurgeToken, nil, logger)}
+	}
+
+	// KAFKA_BROKERS or NATS_URL records every change in the outbox with its
+	// write; a job below ships the outbox to them
+	sink, err := outboxSink(cfg)
+	if err != nil {
+		return err
+	}
+	var repoOpts []repository.Option
+	if sink != nil {
+		// Closed once the runner has stopped shipping
+		defer sink.Close()
+		repoOpts = append(repoOpts, repository.WithOutbox())
+	}
+	if cfg.SearchAccentSensitive {
+		repoOpts = append(repoOpts, repository.WithAccentSensitiveSearch())
+	}
+	serviceRepo := repository.NewServiceRepository(db, repoOpts...)
+
+	// Webhooks registered through /api/v1/webhooks receive the changes made
+	// through this instance; failed deliveries are retried by a job below
+	dispatcher := webhook.NewDispatcher(serviceRepo, cfg.WebhookMaxAttempts, nil, logger)
+	publisher = events.Publishers{publisher, dispatcher}
+	// NOTIFY_CHANNELS are told about the services created, deprecated and
+	// deleted through this instance
+	if len(cfg.NotifyChannels) > 0 {
+		publisher = events.Publishers{publisher, notify.NewNotifier(cfg.NotifyChannels, nil, logger)}
+	}
+	// SMTP_ADDR emails owners and subscribers about the services deprecated
+	// and the ownership changes made through this instance, and users about
+	// the comments mentioning them
+	serviceOpts := []service.Option{}
+	if cfg.Email.Addr != "" {
+		mailer, err := email.NewNotifier(cfg.Email, nil, serviceRepo, logger)
+		if err != nil {
+			return err
+		}
+		publisher = events.Publishers{publisher, mailer}
+		serviceOpts = append(serviceOpts, service.WithMentionNotifier(mailer))
+	}
+
+	// SERVICE_CACHE_SIZE caches service details; the change events, relayed
+	// ones included, invalidate them
+	serviceOpts = append(serviceOpts, service.WithPublisher(publisher))
+	var details *cache.LRU[service.DetailKey, *domain.ServiceWithVersions]
+	if cfg.ServiceCacheSize > 0 {
+		details = cache.NewLRU[service.DetailKey, *domain.ServiceWithVersions]("service", cfg.ServiceCacheSize, cfg.ServiceCacheTTL)
+		bus.Subscribe(func(event domain.ChangeEvent) {
+			details.Remove(service.DetailKey{OrgID: event.OrgID, ServiceID: event.ServiceID})
+		})
+		serviceOpts = append(serviceOpts, service.WithDetailCache(details))
+	}
+
+	// SHARED_CACHE_TTL caches listings and details in REDIS_URL for every instance
+	var shared *cache.Shared
+	if cfg.SharedCacheTTL > 0 {
+		shared = cache.NewShared(sharedRedis, "shared", "kong-connect:cache:", cfg.SharedCacheTTL)
+		serviceOpts = append(serviceOpts, service.WithSharedCache(shared))
+	}
+
+	// CACHE_WARM_SERVICES warms the services viewed most, tallied in REDIS_URL
+	// across instances and deploys
+	var views *cache.Views
+	if cfg.CacheWarmServices > 0 && sharedRedis != nil {
+		views = cache.NewViews(sharedRedis, viewsKey)
+		serviceOpts = append(serviceOpts, service.WithViews(views))
+		viewsCtx, stopViews := context.WithCancel(ctx)
+		flushed := make(chan struct{})
+		go func() {
+			defer close(flushed)
+			views.Run(viewsCtx, viewsFlushInterval, logger)
+		}()
+		// Flushed before the client closes
+		defer func() {
+			stopViews()
+			<-flushed
+		}()
+	}
+
+	// ANALYTICS_FLUSH_INTERVAL counts the views and searches of the catalog
+	// for GET /api/v1/analytics
+	if cfg.AnalyticsFlushInterval > 0 {
+		usage := analytics.NewTracker(serviceRepo)
+		serviceOpts = append(serviceOpts, service.WithUsage(usage))
+		usageCtx, stopUsage := context.WithCancel(ctx)
+		flushed := make(chan struct{})
+		go func() {
+			defer close(flushed)
+			usage.Run(usageCtx, cfg.AnalyticsFlushInterval, logger)
+		}()
+		// Flushed before the database closes
+		defer func() {
+			stopUsage()
+			<-flushed
+		}()
+	}
+
+	// Initialize layers; the users of AUTH_TOKENS have no row in the users table
+	serviceOpts = append(serviceOpts, service.WithStaticUsers(middleware.IsStaticUser))
+	serviceService := service.NewServiceService(serviceRepo, serviceOpts...)
+
+	// Tokens issued through /api/v1/tokens authenticate besides the static AUTH_TOKENS
+	middleware.SetTokenLookup(handler.TokenLookup(serviceService))
+	defer middleware.SetTokenLookup(nil)
+
+	// X-Org selects among the organizations created through /api/v1/organizations
+	middleware.SetOrgLookup(handler.OrgLookup(serviceService))
+	defer middleware.SetOrgLookup(nil)
+
+	// JOBS_LEADER_ELECTION runs background jobs only on the replica holding
+	// the job lease in the shared database
+	var runnerOpts []jobs.RunnerOption
+	if cfg.JobsLeaderElection {
+		lease := jobs.NewLease(db, "jobs", jobs.DefaultLeaseTTL, logger)
+		runnerOpts = append(runnerOpts, jobs.WithLeadership(lease))
+		keepCtx, stopKeeping := context.WithCancel(ctx)
+		kept := make(chan struct{})
+		go func() {
+			defer close(kept)
+			lease.Keep(keepCtx)
+		}()
+		// Released before the database closes, so another replica takes over at once
+		defer func() {
+			stopKeeping()
+			<-kept
+			if err := lease.Release(context.Background()); err != nil {
+				logger.Error("failed to release job lease", "error", err)
+			}
+		}()
+	}
+
+	// Background jobs run until shutdown, which waits for runs in progress
+	runner := jobs.NewRunner(logger, runnerOpts...)
+
+	// RETENTION_SCHEDULE deletes expired rows, such as audit entries older than
+	// AUDIT_RETENTION_DAYS; RETENTION_DRY_RUN only logs what would be deleted
+	if rules := retentionRules(serviceService, cfg.AuditRetentionDays, cfg.WebhookRetentionDays, cfg.HealthCheckRetentionDays, cfg.AnalyticsRetentionDays); len(rules) > 0 {
+		if err := runner.Register(jobs.Job{
+			Name:       "retention",
+			Schedule:   cfg.RetentionSchedule,
+			RunAtStart: true,
+			Run: func(ctx context.Context) error {
+				return runRetention(ctx, rules, cfg.RetentionDryRun, time.Now(), logger)
+			},
+		}); err != nil {
+			return err
+		}
+	}
+
+	if sink != nil {
+		shipper := outbox.NewShipper(serviceRepo, sink, logger)
+		if err := runner.Register(jobs.Job{
+			Name:       "outbox",
+			Schedule:   jobs.Every(outbox.Interval),
+			RunAtStart: true,
+			Run:        shipper.Ship,
+		}); err != nil {
+			return err
+		}
+	}
+
+	// KUBE_DISCOVERY watches the cluster; the leader registers the annotated
+	// Services and Ingresses once they changed
+	if cfg.Kubernetes.Enabled {
+		controller, err := kube.NewController(cfg.Kubernetes, serviceService, logger)
+		if err != nil {
+			return err
+		}
+		watchCtx, stopWatching := context.WithCancel(ctx)
+		controller.Start(watchCtx)
+		defer func() {
+			stopWatching()
+			controller.Shutdown()
+		}()
+		if err := runner.Register(jobs.Job{
+			Name:       "kubernetes-sync",
+			Schedule:   jobs.Every(kube.SyncInterval),
+			RunAtStart: true,
+			Run:        controller.Sync,
+		}); err != nil {
+			return err
+		}
+	}
+
+	// CONSUL_ADDR imports the services registered in Consul into the
+	// default organization, resolving name conflicts by CONSUL_CONFLICT_POLICY
+	if cfg.Consul.Address != "" {
+		importer := consul.NewImporter(cfg.Consul, serviceService, nil, logger)
+		if err := runner.Register(jobs.Job{
+			Name:       "consul-import",
+			Schedule:   jobs.Every(cfg.Consul.Interval),
+			RunAtStart: true,
+			Run:        importer.Import,
+		}); err != nil {
+			return err
+		}
+	}
+
+	// HEALTH_CHECK_INTERVAL probes the health checks of the services of every
+	// organization, recording whether each is up and how fast it answered
+	if cfg.Probe.Interval > 0 {
+		prober := probe.NewProber(cfg.Probe, serviceService, nil, logger)
+		if err := runner.Register(jobs.Job{
+			Name:       "health-checks",
+			Schedule:   jobs.Every(cfg.Probe.Interval),
+			RunAtStart: true,
+			Run:        prober.Probe,
+		}); err != nil {
+			return err
+		}
+	}
+
+	// REPOSITORY_REFRESH_INTERVAL refreshes the default branch and latest
+	// release of the source repositories linked to services from GitHub and
+	// GitLab
+	if cfg.Repositories.Interval > 0 {
+		refresher := scm.NewRefresher(cfg.Repositories, serviceService, nil, logger)
+		if err := runner.Register(jobs.Job{
+			Name:       "repository-refresh",
+			Schedule:   jobs.Every(cfg.Repositories.Interval),
+			RunAtStart: true,
+			Run:        refresher.Refresh,
+		}); err != nil {
+			return err
+		}
+	}
+
+	// Sunsets deprecate and archive services and versions on schedule, and
+	// publish reminders 30, 7 and 1 days ahead of their archival
+	if err := runner.Register(jobs.Job{
+		Name:       "sunsets",
+		Schedule:   jobs.Every(service.SunsetInterval),
+		RunAtStart: true,
+		Run:        serviceService.ApplySunsets,
+	}); err != nil {
+		return err
+	}
+
+	// WEBHOOK_MAX_ATTEMPTS bounds the retries of failed webhook deliveries,
+	// backing off from 30 seconds to an hour between attempts
+	if err := runner.Register(jobs.Job{
+		Name:     "webhook-retries",
+		Schedule: jobs.Every(webhook.RetryInterval),
+		Run:      dispatcher.Retry,
+	}); err != nil {
+		return err
+	}
+
+	// LENIENT_QUERY_PARAMS keeps the legacy behaviour of ignoring bad query parameters
+	var handlerOpts []handler.HandlerOption
+	if cfg.LenientQueryParams {
+		handlerOpts = append(handlerOpts, handler.WithLenientQueryParams())
+	}
+	if cfg.FastJSON {
+		handlerOpts = append(handlerOpts, handler.WithFastJSON())
+	}
+	handlerOpts = append(handlerOpts, handler.WithMaxResponseSize(cfg.MaxResponseBytes), handler.WithLogger(logger))
+	serviceHandler := handler.NewServiceHandler(serviceService, handlerOpts...)
+
+	// Log level, CORS origins, rate limits and tokens can change later through Reload
+	s.Reload(cfg.Runtime)
+
+	// RATE_LIMIT_REDIS_URL, or else REDIS_URL, shares the rate limit buckets
+	// between instances
+	var rateLimitStore ratelimit.Store = ratelimit.NewMemoryStore()
+	switch {
+	case cfg.RateLimitRedisURL != "":
+		redisClient, err := newRedisClient("RATE_LIMIT_REDIS_URL", cfg.RateLimitRedisURL)
+		if err != nil {
+			return err
+		}
+		defer redisClient.Close()
+		rateLimitStore = ratelimit.NewRedisStore(redisClient, "kong-connect:ratelimit:")
+		// Rate limiting fails open, so the instance stays ready without Redis
+		name := "redis"
+		if sharedRedis != nil {
+			name = "redis-ratelimit"
+		}
+		readiness.Register(redisCheck(name, redisClient))
+	case sharedRedis != nil:
+		rateLimitStore = ratelimit.NewRedisStore(sharedRedis, "kong-connect:ratelimit:")
+	}
+
+	// SENTRY_DSN reports panics and 5xx responses to Sentry
+	var errorReporter errreport.Reporter = errreport.Nop{}
+	if cfg.Sentry.DSN != "" {
+		errorReporter, err = errreport.NewSentry(cfg.Sentry)
+		if err != nil {
+			return fmt.Errorf("invalid error reporting configuration: %v", err)
+		}
+	}
+
+	// REQUEST_TIMEOUT=0 disables the per-request limit
+	routerOpts := []handler.RouterOption{
+		handler.WithRequestTimeout(cfg.RequestTimeout),
+		handler.WithWebSocket(hub),
+		handler.WithRequestLogger(logger),
+		handler.WithAccessLog(cfg.AccessLogFormat, os.Stdout),
+		handler.WithCORS(s.cors),
+		handler.WithTrustedProxies(cfg.TrustedProxies),
+		handler.WithRateLimit(rateLimitStore, s.rateLimits),
+		handler.WithErrorReporter(errorReporter),
+		handler.WithMaxBodySize(cfg.MaxBodyBytes),
+		handler.WithConcurrencyLimit(cfg.MaxInFlight, cfg.QueueTimeout),
+		handler.WithFeatures(s.features),
+		handler.WithReadiness(readiness),
+		handler.WithDrain(cfg.DrainGracePeriod),
+		handler.WithHTTPCache(cfg.HTTPCache),
+	}
+
+	// METRICS_ENABLED=false removes the /metrics endpoint
+	if cfg.MetricsEnabled {
+		registry := metrics.New(cfg.LatencySLOs)
+		registry.MustRegister(runner.Collectors()...)
+		if details != nil {
+			registry.MustRegister(details.Collectors()...)
+		}
+		if shared != nil {
+			registry.MustRegister(shared.Collectors()...)
+		}
+		routerOpts = append(routerOpts, handler.WithMetrics(registry))
+	}
+
+	// DEBUG_BODY_BYTES logs request and response bodies at debug level
+	if cfg.DebugBodyBytes > 0 {
+		logger.Warn("request and response bodies are logged at debug level", "max_bytes", cfg.DebugBodyBytes)
+		routerOpts = append(routerOpts, handler.WithDebugLog(cfg.DebugBodyBytes))
+	}
+
+	// DEBUG_ENDPOINTS mounts the admin-only pprof and expvar endpoints
+	if cfg.DebugEndpoints {
+		logger.Warn("debug endpoints enabled under /debug")
+		routerOpts = append(routerOpts, handler.WithDebugEndpoints())
+	}
+
+	// FAULT_* settings make a share of the API requests slow or fail
+	if cfg.Faults.Enabled() {
+		logger.Warn("fault injection enabled; not for production",
+			"latency_percent", cfg.Faults.LatencyPercent, "latency", cfg.Faults.Latency,
+			"error_percent", cfg.Faults.ErrorPercent, "drop_percent", cfg.Faults.DropPercent)
+		routerOpts = append(routerOpts, handler.WithFaultInjection(cfg.Faults))
+	}
+
+	// UI_ENABLED=false serves the API only
+	if cfg.UIEnabled {
+		ui, err := web.NewHandler(web.Assets())
+		if err != nil {
+			return fmt.Errorf("failed to load web UI: %v", err)
+		}
+		routerOpts = append(routerOpts, handler.WithUI(ui))
+	}
+
+	// Setup router
+	router := handler.SetupRouter(serviceHandler, routerOpts...)
+
+	port := cfg.Port
+	httpServer := &http.Server{
+		Addr:              ":" + port,
+		Handler:           router,
+		ReadHeaderTimeout: cfg.ReadHeaderTimeout,
+		ReadTimeout:       cfg.ReadTimeout,
+		WriteTimeout:      cfg.WriteTimeout,
+		IdleTimeout:       cfg.IdleTimeout,
+	}
+
+	// HTTP/2 is negotiated over TLS by default; HTTP2_CLEARTEXT=true also accepts h2c
+	cfg.HTTP2.Apply(httpServer)
+
+	// Shutdown does not track hijacked connections, so close WebSocket clients explicitly
+	httpServer.RegisterOnShutdown(hub.Close)
+
+	// TLS_CERT_FILE/TLS_KEY_FILE or TLS_AUTOCERT_DOMAINS switch the server to HTTPS
+	tlsConfig := cfg.TLS
+	var redirectServer *http.Server
+	if tlsConfig.Enabled() {
+		redirectHandler, err := tlsConfig.Apply(httpServer)
+		if err != nil {
+			return fmt.Errorf("invalid TLS configuration: %v", err)
+		}
+		if tlsConfig.RedirectAddr != "" {
+			redirectServer = &http.Server{
+				Addr:              tlsConfig.RedirectAddr,
+				Handler:           redirectHandler,
+				ReadHeaderTimeout: httpServer.ReadHeaderTimeout,
+				ReadTimeout:       httpServer.ReadTimeout,
+				WriteTimeout:      httpServer.WriteTimeout,
+				IdleTimeout:       httpServer.IdleTimeout,
+			}
+		}
+	}
+
+	listener := s.listener
+	if listener == nil {
+		if listener, err = net.Listen("tcp", httpServer.Addr); err != nil {
+			return fmt.Errorf("failed to listen: %v", err)
+		}
+	}
+
+	// CACHE_WARM_PAGES and CACHE_WARM_SERVICES fill the caches before serving
+	if cfg.CacheWarmPages > 0 || cfg.CacheWarmServices > 0 {
+		warmCache(ctx, serviceService, views, cfg.CacheWarmPages, cfg.CacheWarmServices, logger)
+	}
+
+	runner.Start(ctx)
+
+	serverErr := make(chan error, 1)
+	go func() {
+		if !tlsConfig.Enabled() {
+			logger.Info("server starting", "addr", listener.Addr().String())
+			serverErr <- httpServer.Serve(listener)
+			return
+		}
+		logger.Info("server starting with TLS", "addr", listener.Addr().String(), "autocert", len(tlsConfig.AutocertDomains) > 0)
+		serverErr <- httpServer.ServeTLS(listener, tlsConfig.CertFile, tlsConfig.KeyFile)
+	}()
+	if redirectServer != nil {
+		go func() {
+			logger.Info("redirecting HTTP to HTTPS", "addr", redirectServer.Addr)
+			serverErr <- redirectServer.ListenAndServe()
+		}()
+	}
+
+	var runErr error
+	select {
+	case err := <-serverErr:
+		runErr = fmt.Errorf("server stopped: %v", err)
+	case <-ctx.Done():
+	case <-readiness.Drained():
+		logger.Info("drain grace period over")
+	}
+
+	shutdownTimeout := cfg.ShutdownTimeout
+	logger.Info("shutting down, draining in-flight requests", "timeout", shutdownTimeout)
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+
+	if redirectServer != nil {
+		redirectServer.Shutdown(shutdownCtx)
+	}
+	if err := httpServer.Shutdown(shutdownCtx); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		logger.Error("failed to drain in-flight requests", "error", err)
+	}
+	if err := runner.Shutdown(shutdownCtx); err != nil {
+		logger.Error("failed to finish running jobs", "error", err)
+	}
+	if err := shutdownTracing(shutdownCtx); err != nil {
+		logger.Error("failed to flush traces", "error", err)
+	}
+	if !errorReporter.Flush(errorFlushTimeout) {
+		logger.Error("failed to flush error reports")
+	}
+
+	return runErr
+}
+
+// newRedisClient connects to the Redis server of url, configured by env
+func newRedisClient(env, url string) (*redis.Client, error) {
+	opts, err := redis.ParseURL(url)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %v", env, err)
+	}
+	return redis.NewClient(opts), nil
+}
+
+// outboxSink returns the sink of the change events, KAFKA_BROKERS or
+// NATS_URL, or nil without either
+func outboxSink(cfg *config.Config) (interface {
+	outbox.Sink
+	Close() error
+}, error) {
+	switch {
+	case len(cfg.KafkaBrokers) > 0:
+		return outbox.NewKafkaSink(cfg.KafkaBrokers, cfg.KafkaTopic), nil
+	case cfg.NATSURL != "":
+		sink, err := outbox.NewNATSSink(cfg.NATSURL, cfg.NATSSubjectPrefix)
+		if err != nil {
+			return nil, fmt.Errorf("invalid NATS_URL: %v", err)
+		}
+		return sink, nil
+	}
+	return nil, nil
+}
+
+// redisCheck is a non-critical readiness check pinging client
+func redisCheck(name string, client *redis.Client) health.Check {
+	return health.Check{
+		Name:  name,
+		Probe: func(ctx context.Context) error { return client.Ping(ctx).Err() },
+	}
+}
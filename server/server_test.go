@@ -0,0 +1,170 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"com.kong.connect/config"
+	"com.kong.connect/database"
+	"com.kong.connect/health"
+	"com.kong.connect/middleware"
+)
+
+// get requests path from the server with a bearer token and returns the status
+func get(t *testing.T, addr, path, token string) int {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodGet, "http://"+addr+path, nil)
+	require.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer "+token)
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	return resp.StatusCode
+}
+
+func TestRunServesUntilContextIsDone(t *testing.T) {
+	cfg, err := config.Load("", map[string]string{
+		"database.dsn":              database.MemoryPath,
+		"logging.access_log_format": "off",
+		"server.shutdown_timeout":   "5s",
+	})
+	require.NoError(t, err)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := listener.Addr().String()
+
+	srv := New(cfg, WithListener(listener))
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- srv.Run(ctx) }()
+
+	require.Eventually(t, func() bool {
+		resp, err := http.Get("http://" + addr + "/health")
+		if err != nil {
+			return false
+		}
+		resp.Body.Close()
+		return resp.StatusCode == http.StatusOK
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, http.StatusOK, get(t, addr, "/api/v1/services", "viewer-token"))
+	// SEED_ON_START is off by default
+	assert.Equal(t, http.StatusNotFound, get(t, addr, "/api/v1/services/1", "viewer-token"))
+
+	// Reloaded tokens apply to the next request
+	tokens, err := middleware.ParseTokens("rotated=viewer:viewer")
+	require.NoError(t, err)
+	srv.Reload(config.Runtime{RateLimits: cfg.Runtime.RateLimits, Tokens: tokens})
+	t.Cleanup(func() { middleware.SetTokens(cfg.Runtime.Tokens) })
+	assert.Equal(t, http.StatusUnauthorized, get(t, addr, "/api/v1/services", "viewer-token"))
+	assert.Equal(t, http.StatusOK, get(t, addr, "/api/v1/services", "rotated"))
+
+	cancel()
+	select {
+	case err := <-done:
+		assert.NoError(t, err)
+	case <-time.After(10 * time.Second):
+		t.Fatal("Run did not return after the context was cancelled")
+	}
+	_, err = http.Get("http://" + addr + "/health")
+	assert.Error(t, err)
+}
+
+func TestReadyzReportsDependencies(t *testing.T) {
+	cfg, err := config.Load("", map[string]string{
+		"database.dsn":              database.MemoryPath,
+		"logging.access_log_format": "off",
+		"rate_limit.redis_url":      "redis://127.0.0.1:1",
+	})
+	require.NoError(t, err)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- New(cfg, WithListener(listener)).Run(ctx) }()
+	defer func() {
+		cancel()
+		<-done
+	}()
+
+	// Redis is down, but rate limiting fails open so the instance stays ready
+	var report health.Report
+	require.Eventually(t, func() bool {
+		resp, err := http.Get("http://" + listener.Addr().String() + "/readyz")
+		if err != nil {
+			return false
+		}
+		defer resp.Body.Close()
+		return resp.StatusCode == http.StatusOK && json.NewDecoder(resp.Body).Decode(&report) == nil
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, health.StatusDegraded, report.Status)
+	require.Len(t, report.Dependencies, 2)
+	assert.Equal(t, "database", report.Dependencies[0].Name)
+	assert.Equal(t, health.StatusUp, report.Dependencies[0].Status)
+	assert.Equal(t, "redis", report.Dependencies[1].Name)
+	assert.Equal(t, health.StatusDown, report.Dependencies[1].Status)
+}
+
+func TestDrainStopsServerAfterGracePeriod(t *testing.T) {
+	cfg, err := config.Load("", map[string]string{
+		"database.dsn":              database.MemoryPath,
+		"logging.access_log_format": "off",
+		"server.drain_grace_period": "300ms",
+	})
+	require.NoError(t, err)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := listener.Addr().String()
+	done := make(chan error, 1)
+	go func() { done <- New(cfg, WithListener(listener)).Run(context.Background()) }()
+	require.Eventually(t, func() bool {
+		resp, err := http.Get("http://" + addr + "/readyz")
+		if err != nil {
+			return false
+		}
+		resp.Body.Close()
+		return resp.StatusCode == http.StatusOK
+	}, 5*time.Second, 10*time.Millisecond)
+
+	drain := func(token string) int {
+		req, err := http.NewRequest(http.MethodPost, "http://"+addr+"/drain", nil)
+		require.NoError(t, err)
+		req.Header.Set("Authorization", "Bearer "+token)
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+	assert.Equal(t, http.StatusForbidden, drain("viewer-token"))
+	assert.Equal(t, http.StatusOK, get(t, addr, "/readyz", ""))
+	require.Equal(t, http.StatusAccepted, drain("admin-token"))
+
+	// Traffic is still served while readiness fails
+	assert.Equal(t, http.StatusServiceUnavailable, get(t, addr, "/readyz", ""))
+	assert.Equal(t, http.StatusOK, get(t, addr, "/api/v1/services", "viewer-token"))
+
+	select {
+	case err := <-done:
+		assert.NoError(t, err)
+	case <-time.After(10 * time.Second):
+		t.Fatal("Run did not return after the grace period")
+	}
+}
+
+func TestRunFailsOnInvalidDatabase(t *testing.T) {
+	cfg, err := config.Load("", map[string]string{"database.dsn": filepath.Join(t.TempDir(), "missing", "catalog.db")})
+	require.NoError(t, err)
+
+	err = New(cfg).Run(context.Background())
+	assert.ErrorContains(t, err, "failed to initialize database")
+}
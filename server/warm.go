@@ -0,0 +1,44 @@
+package server
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"com.kong.connect/cache"
+	"com.kong.connect/service"
+)
+
+// Views are tallied in REDIS_URL under viewsKey, flushed every viewsFlushInterval
+const (
+	viewsKey           = "kong-connect:views"
+	viewsFlushInterval = time.Minute
+)
+
+// cacheWarmTimeout bounds warming, which delays serving
+const cacheWarmTimeout = 10 * time.Second
+
+// warmCache reads the first pages of the default listing and the services
+// viewed most, as tallied by views, or else the services on those pages.
+// Warming is best effort: failures are logged and the server starts anyway.
+func warmCache(ctx context.Context, svc service.ServiceServiceInterface, views *cache.Views, pages, services int, logger *slog.Logger) {
+	ctx, cancel := context.WithTimeout(ctx, cacheWarmTimeout)
+	defer cancel()
+
+	var ids []int
+	if views != nil && services > 0 {
+		top, err := views.Top(ctx, services)
+		if err != nil {
+			logger.Warn("failed to read the services viewed most", "error", err)
+		}
+		ids = top
+	}
+
+	start := time.Now()
+	reads, err := service.WarmCache(ctx, svc, pages, ids)
+	if err != nil {
+		logger.Warn("cache warming stopped early", "reads", reads, "error", err)
+		return
+	}
+	logger.Info("cache warmed", "reads", reads, "duration", time.Since(start))
+}
@@ -0,0 +1,99 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"com.kong.connect/domain"
+	"com.kong.connect/tracing"
+)
+
+const (
+	// analyticsMaxDays bounds the days an analytics report covers
+	analyticsMaxDays = 365
+	// analyticsMaxLimit bounds the entries of each list of a report
+	analyticsMaxLimit = 100
+	// minReportedSearches hides the terms searched fewer times during the
+	// days of a report, which may identify who searched them
+	minReportedSearches = 3
+)
+
+// GetAnalytics reports the services of the organization viewed most and the
+// terms searched most and trending during the last days, by default 30.
+// Usage counted by other instances since their last flush is not included.
+func (s *ServiceService) GetAnalytics(ctx context.Context, query domain.AnalyticsQuery) (_ *domain.AnalyticsReport, err error) {
+	ctx, span := tracing.Start(ctx, "ServiceService.GetAnalytics")
+	defer func() { tracing.End(span, err) }()
+
+	if query.Days <= 0 {
+		query.Days = 30
+	}
+	if query.Days > analyticsMaxDays {
+		query.Days = analyticsMaxDays
+	}
+	if query.Limit <= 0 {
+		query.Limit = 10
+	}
+	if query.Limit > analyticsMaxLimit {
+		query.Limit = analyticsMaxLimit
+	}
+
+	today := time.Now().UTC()
+	until := today.Format(domain.AnalyticsDayFormat)
+	since := today.AddDate(0, 0, 1-query.Days).Format(domain.AnalyticsDayFormat)
+	previousSince := today.AddDate(0, 0, 1-2*query.Days).Format(domain.AnalyticsDayFormat)
+
+	services, err := s.repo.GetTopViewedServices(ctx, since, until, query.Limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get viewed services: %v", err)
+	}
+	top, err := s.repo.GetSearchTerms(ctx, previousSince, since, until, minReportedSearches, false, query.Limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get search terms: %v", err)
+	}
+	trending, err := s.repo.GetSearchTerms(ctx, previousSince, since, until, minReportedSearches, true, query.Limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get search terms: %v", err)
+	}
+
+	return &domain.AnalyticsReport{
+		Since:            since,
+		Until:            until,
+		TopServices:      services,
+		TopSearches:      top,
+		TrendingSearches: trending,
+	}, nil
+}
+
+// recordSearch counts a search for term when usage is tracked. Only first
+// pages count, so that paging through results is a single search.
+func (s *ServiceService) recordSearch(ctx context.Context, term string, page int) {
+	if s.usage != nil && page <= 1 && !isWarming(ctx) {
+		s.usage.RecordSearch(ctx, term)
+	}
+}
+
+// PurgeUsage deletes the usage counted on days before the cutoff
+func (s *ServiceService) PurgeUsage(ctx context.Context, before time.Time) (_ int64, err error) {
+	ctx, span := tracing.Start(ctx, "ServiceService.PurgeUsage")
+	defer func() { tracing.End(span, err) }()
+
+	purged, err := s.repo.PurgeUsage(ctx, before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge usage: %v", err)
+	}
+	return purged, nil
+}
+
+// CountUsageBefore returns how many rows PurgeUsage would delete
+func (s *ServiceService) CountUsageBefore(ctx context.Context, before time.Time) (_ int64, err error) {
+	ctx, span := tracing.Start(ctx, "ServiceService.CountUsageBefore")
+	defer func() { tracing.End(span, err) }()
+
+	count, err := s.repo.CountUsageBefore(ctx, before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count usage: %v", err)
+	}
+	return count, nil
+}
@@ -0,0 +1,170 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"time"
+
+	"com.kong.connect/audit"
+	"com.kong.connect/domain"
+	"com.kong.connect/requestid"
+	"com.kong.connect/tracing"
+)
+
+// GetAuditLogs retrieves one page of audit entries, newest first
+func (s *ServiceService) GetAuditLogs(ctx context.Context, query domain.AuditQuery) (_ *domain.AuditListResponse, err error) {
+	ctx, span := tracing.Start(ctx, "ServiceService.GetAuditLogs")
+	defer func() { tracing.End(span, err) }()
+
+	if query.Page <= 0 {
+		query.Page = 1
+	}
+	if query.PageSize <= 0 {
+		query.PageSize = 50
+	}
+	if query.PageSize > 100 {
+		query.PageSize = 100
+	}
+
+	entries, total, err := s.repo.GetAuditEntries(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get audit logs: %v", err)
+	}
+
+	return &domain.AuditListResponse{
+		Entries:    entries,
+		Total:      total,
+		Page:       query.Page,
+		PageSize:   query.PageSize,
+		TotalPages: int(math.Ceil(float64(total) / float64(query.PageSize))),
+	}, nil
+}
+
+// PurgeAuditLogs deletes audit entries recorded before the cutoff
+func (s *ServiceService) PurgeAuditLogs(ctx context.Context, before time.Time) (_ int64, err error) {
+	ctx, span := tracing.Start(ctx, "ServiceService.PurgeAuditLogs")
+	defer func() { tracing.End(span, err) }()
+
+	purged, err := s.repo.PurgeAuditEntries(ctx, before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge audit logs: %v", err)
+	}
+	return purged, nil
+}
+
+// CountAuditLogsBefore returns how many audit entries PurgeAuditLogs would delete
+func (s *ServiceService) CountAuditLogsBefore(ctx context.Context, before time.Time) (_ int64, err error) {
+	ctx, span := tracing.Start(ctx, "ServiceService.CountAuditLogsBefore")
+	defer func() { tracing.End(span, err) }()
+
+	count, err := s.repo.CountAuditEntriesBefore(ctx, before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count audit logs: %v", err)
+	}
+	return count, nil
+}
+
+// recordAudit stores who changed a resource and how. It is called within
+// repo.InTx, in the transaction of the change, so that a change is never
+// committed without its audit entry.
+func (s *ServiceService) recordAudit(ctx context.Context, action, resourceType string, resourceID int, before, after interface{}) error {
+	actor := audit.FromContext(ctx)
+	entry := domain.AuditEntry{
+		Timestamp:    time.Now().UTC(),
+		Principal:    actor.Principal,
+		Action:       action,
+		ResourceType: resourceType,
+		ResourceID:   resourceID,
+		IP:           actor.IP,
+		RequestID:    requestid.FromContext(ctx),
+	}
+
+	var err error
+	entry.Before, entry.Changes, err = auditDiff(before, after)
+	if err == nil {
+		entry.After, err = snapshot(after)
+	}
+	if err == nil {
+		err = s.repo.InsertAuditEntry(ctx, entry)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to record audit entry: %v", err)
+	}
+	return nil
+}
+
+// auditedWrite runs write in a transaction and, unless write reports that
+// what it changes was not found, records the audit entry of the change in
+// it too
+func (s *ServiceService) auditedWrite(ctx context.Context, write func(ctx context.Context) (bool, error), action, resourceType string, resourceID int, before, after interface{}) (bool, error) {
+	var found bool
+	err := s.repo.InTx(ctx, func(ctx context.Context) error {
+		var err error
+		if found, err = write(ctx); err != nil || !found {
+			return err
+		}
+		return s.recordAudit(ctx, action, resourceType, resourceID, before, after)
+	})
+	return found, err
+}
+
+// auditDiff snapshots before and compares the top-level fields of both
+// values, returning the fields whose value changed
+func auditDiff(before, after interface{}) (json.RawMessage, map[string]domain.AuditChange, error) {
+	beforeJSON, err := snapshot(before)
+	if err != nil {
+		return nil, nil, err
+	}
+	afterJSON, err := snapshot(after)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	beforeFields, err := fields(beforeJSON)
+	if err != nil {
+		return nil, nil, err
+	}
+	afterFields, err := fields(afterJSON)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	changes := make(map[string]domain.AuditChange)
+	for name, value := range beforeFields {
+		if other, ok := afterFields[name]; !ok || !reflect.DeepEqual(value, other) {
+			changes[name] = domain.AuditChange{Before: value, After: afterFields[name]}
+		}
+	}
+	for name, value := range afterFields {
+		if _, ok := beforeFields[name]; !ok {
+			changes[name] = domain.AuditChange{Before: nil, After: value}
+		}
+	}
+	return beforeJSON, changes, nil
+}
+
+// snapshot encodes a resource as JSON, or returns nil when there is no resource
+func snapshot(value interface{}) (json.RawMessage, error) {
+	if value == nil {
+		return nil, nil
+	}
+	if v := reflect.ValueOf(value); v.Kind() == reflect.Ptr && v.IsNil() {
+		return nil, nil
+	}
+	return json.Marshal(value)
+}
+
+// fields decodes a JSON object snapshot into its top-level fields
+func fields(raw json.RawMessage) (map[string]interface{}, error) {
+	values := make(map[string]interface{})
+	if len(raw) == 0 {
+		return values, nil
+	}
+	if err := json.Unmarshal(raw, &values); err != nil {
+		return nil, err
+	}
+	return values, nil
+}
@@ -0,0 +1,283 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"com.kong.connect/domain"
+	"com.kong.connect/repository"
+	"com.kong.connect/semver"
+	"com.kong.connect/tracing"
+)
+
+// catalogPageSize is the number of services read per query while planning an apply
+const catalogPageSize = 100
+
+// ApplyCatalog reconciles the catalog with the desired state: services are
+// matched by name, missing ones are created, changed ones updated, and
+// services absent from the document deleted, together with their versions.
+// With dryRun the changes are only computed. The changes are made in one
+// transaction and audited one by one.
+func (s *ServiceService) ApplyCatalog(ctx context.Context, document domain.CatalogDocument, dryRun bool) (_ *domain.CatalogApplyResult, err error) {
+	ctx, span := tracing.Start(ctx, "ServiceService.ApplyCatalog")
+	defer func() { tracing.End(span, err) }()
+
+	inputs, versions, err := normalizeCatalog(document)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := s.allServices(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get services: %v", err)
+	}
+
+	result := &domain.CatalogApplyResult{DryRun: dryRun, Changes: []domain.CatalogChange{}}
+	current := make(map[string]*domain.ServiceWithVersions, len(existing))
+	for i := range existing {
+		current[existing[i].Name] = &existing[i]
+	}
+
+	for _, entry := range document.Services {
+		name := strings.TrimSpace(entry.Name)
+		input := inputs[name]
+		service, ok := current[name]
+		if !ok {
+			result.Changes = append(result.Changes, domain.CatalogChange{
+				Action:        domain.CatalogActionCreate,
+				Service:       name,
+				VersionsAdded: versions[name],
+			})
+			result.Created++
+			continue
+		}
+
+		change := domain.CatalogChange{Action: domain.CatalogActionUpdate, Service: name, ServiceID: service.ID}
+		change.Fields = serviceFieldChanges(service.Service, input)
+		have := make([]string, len(service.Versions))
+		for i, version := range service.Versions {
+			have[i] = version.Version
+		}
+		for _, version := range versions[name] {
+			if !slices.Contains(have, version) {
+				change.VersionsAdded = append(change.VersionsAdded, version)
+			}
+		}
+		for _, version := range have {
+			if !slices.Contains(versions[name], version) {
+				change.VersionsRemoved = append(change.VersionsRemoved, version)
+			}
+		}
+		if len(change.Fields) == 0 && len(change.VersionsAdded) == 0 && len(change.VersionsRemoved) == 0 {
+			result.Unchanged++
+			continue
+		}
+		result.Changes = append(result.Changes, change)
+		result.Updated++
+	}
+
+	for _, service := range existing {
+		if _, ok := inputs[service.Name]; !ok {
+			result.Changes = append(result.Changes, domain.CatalogChange{
+				Action:    domain.CatalogActionDelete,
+				Service:   service.Name,
+				ServiceID: service.ID,
+			})
+			result.Deleted++
+		}
+	}
+
+	if dryRun || len(result.Changes) == 0 {
+		return result, nil
+	}
+
+	applied := make([]*domain.ServiceWithVersions, len(result.Changes))
+	err = s.repo.InTx(ctx, func(ctx context.Context) error {
+		created, err := s.repo.ApplyCatalog(ctx, result.Changes, inputs)
+		if err != nil {
+			return err
+		}
+		for i := range result.Changes {
+			change := &result.Changes[i]
+			if id, ok := created[change.Service]; ok {
+				change.ServiceID = id
+			}
+			if applied[i], err = s.auditCatalogChange(ctx, *change, current[change.Service]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, repository.ErrDuplicate) {
+			return nil, conflict("catalog changed during apply", "The catalog changed during the apply, retry it")
+		}
+		return nil, fmt.Errorf("failed to apply catalog: %v", err)
+	}
+
+	for i, change := range result.Changes {
+		s.publishCatalogChange(ctx, change, current[change.Service], applied[i])
+	}
+	return result, nil
+}
+
+// auditCatalogChange records an applied change like the equivalent single
+// write and returns the service after it, nil for deleted services; before
+// is nil for created services
+func (s *ServiceService) auditCatalogChange(ctx context.Context, change domain.CatalogChange, before *domain.ServiceWithVersions) (*domain.ServiceWithVersions, error) {
+	if change.Action == domain.CatalogActionDelete {
+		return nil, s.recordAudit(ctx, domain.AuditActionDelete, domain.AuditResourceService, change.ServiceID, &before.Service, nil)
+	}
+
+	after, err := s.repo.GetByID(ctx, change.ServiceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get service: %v", err)
+	}
+	if after == nil {
+		return nil, fmt.Errorf("applied service %d not found", change.ServiceID)
+	}
+
+	switch {
+	case change.Action == domain.CatalogActionCreate:
+		err = s.recordAudit(ctx, domain.AuditActionCreate, domain.AuditResourceService, after.ID, nil, &after.Service)
+	case len(change.Fields) > 0:
+		err = s.recordAudit(ctx, domain.AuditActionUpdate, domain.AuditResourceService, after.ID, &before.Service, &after.Service)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range after.Versions {
+		version := &after.Versions[i]
+		if slices.Contains(change.VersionsAdded, version.Version) {
+			if err := s.recordAudit(ctx, domain.AuditActionCreate, domain.AuditResourceVersion, version.ID, nil, version); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if before == nil {
+		return after, nil
+	}
+	for i := range before.Versions {
+		version := &before.Versions[i]
+		if slices.Contains(change.VersionsRemoved, version.Version) {
+			if err := s.recordAudit(ctx, domain.AuditActionDelete, domain.AuditResourceVersion, version.ID, version, nil); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return after, nil
+}
+
+// publishCatalogChange publishes a committed change like the equivalent
+// single write; after is nil for deleted services
+func (s *ServiceService) publishCatalogChange(ctx context.Context, change domain.CatalogChange, before, after *domain.ServiceWithVersions) {
+	if change.Action == domain.CatalogActionDelete {
+		s.publish(ctx, domain.EventServiceDeleted, &before.Service, nil)
+		return
+	}
+
+	switch {
+	case change.Action == domain.CatalogActionCreate:
+		s.publish(ctx, domain.EventServiceCreated, &after.Service, nil)
+	case len(change.Fields) > 0:
+		s.publishUpdate(ctx, &before.Service, &after.Service)
+	}
+
+	for i := range after.Versions {
+		version := &after.Versions[i]
+		if slices.Contains(change.VersionsAdded, version.Version) {
+			s.publish(ctx, domain.EventVersionCreated, &after.Service, version)
+		}
+	}
+	if before == nil {
+		return
+	}
+	for i := range before.Versions {
+		version := &before.Versions[i]
+		if slices.Contains(change.VersionsRemoved, version.Version) {
+			s.publish(ctx, domain.EventVersionDeleted, &after.Service, version)
+		}
+	}
+}
+
+// allServices reads every service with its versions, page by page
+func (s *ServiceService) allServices(ctx context.Context) ([]domain.ServiceWithVersions, error) {
+	services := []domain.ServiceWithVersions{}
+	for page := 1; ; page++ {
+		batch, total, err := s.repo.GetAll(ctx, domain.ServiceQuery{SortBy: "name", SortDir: "asc", Page: page, PageSize: catalogPageSize})
+		if err != nil {
+			return nil, err
+		}
+		services = append(services, batch...)
+		if len(batch) < catalogPageSize || len(services) >= total {
+			return services, nil
+		}
+	}
+}
+
+// normalizeCatalog validates every service of the document like a single
+// write and returns the service inputs and versions by name
+func normalizeCatalog(document domain.CatalogDocument) (map[string]domain.ServiceInput, map[string][]string, error) {
+	inputs := make(map[string]domain.ServiceInput, len(document.Services))
+	versions := make(map[string][]string, len(document.Services))
+	for i, entry := range document.Services {
+		input, err := normalizeServiceInput(domain.ServiceInput{
+			Name:        entry.Name,
+			Description: entry.Description,
+			Status:      entry.Status,
+			Owner:       entry.Owner,
+			Tags:        entry.Tags,
+		})
+		if err != nil {
+			return nil, nil, invalidf("invalid catalog: services[%d]: %v", i, strings.TrimPrefix(err.Error(), "invalid service: "))
+		}
+		if _, ok := inputs[input.Name]; ok {
+			return nil, nil, invalidf("invalid catalog: service %q is listed twice", input.Name)
+		}
+
+		list := []string{}
+		for _, version := range entry.Versions {
+			// Declared versions are compared with the stored ones in
+			// canonical form, which is what is stored
+			version = semver.Canonical(version)
+			switch {
+			case version == "":
+				return nil, nil, invalidf("invalid catalog: service %q: empty version", input.Name)
+			case len(version) > maxVersionLength:
+				return nil, nil, invalidf("invalid catalog: service %q: version must be at most %d characters", input.Name, maxVersionLength)
+			case slices.Contains(list, version):
+				return nil, nil, invalidf("invalid catalog: service %q: version %s is listed twice", input.Name, version)
+			}
+			list = append(list, version)
+		}
+
+		inputs[input.Name] = input
+		versions[input.Name] = list
+	}
+	return inputs, versions, nil
+}
+
+// serviceFieldChanges lists the fields of service that differ from input
+func serviceFieldChanges(service domain.Service, input domain.ServiceInput) map[string]domain.AuditChange {
+	changes := make(map[string]domain.AuditChange)
+	if service.Description != input.Description {
+		changes["description"] = domain.AuditChange{Before: service.Description, After: input.Description}
+	}
+	if service.Status != input.Status {
+		changes["status"] = domain.AuditChange{Before: service.Status, After: input.Status}
+	}
+	if service.Owner != input.Owner {
+		changes["owner"] = domain.AuditChange{Before: service.Owner, After: input.Owner}
+	}
+	if !slices.Equal(service.Tags, input.Tags) {
+		changes["tags"] = domain.AuditChange{Before: service.Tags, After: input.Tags}
+	}
+	if len(changes) == 0 {
+		return nil
+	}
+	return changes
+}
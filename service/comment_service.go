@@ -0,0 +1,196 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"com.kong.connect/domain"
+	"com.kong.connect/tracing"
+)
+
+const (
+	maxCommentLength = 10000
+	// maxMentions bounds the users a comment notifies
+	maxMentions = 20
+	// commentsMaxPageSize bounds a page of threads
+	commentsMaxPageSize = 100
+)
+
+// mention matches @username, but not the domain of an email address
+var mention = regexp.MustCompile(`(?:^|[^\w@.])@([A-Za-z0-9][A-Za-z0-9._-]{0,63})`)
+
+// MentionNotifier tells the users mentioned in a comment on a service about
+// it, e.g. by email
+type MentionNotifier interface {
+	NotifyMentions(ctx context.Context, service *domain.Service, comment *domain.Comment)
+}
+
+// WithMentionNotifier notifies the users mentioned in new comments
+func WithMentionNotifier(notifier MentionNotifier) Option {
+	return func(s *ServiceService) {
+		s.mentions = notifier
+	}
+}
+
+// CreateComment adds a comment by author to a service, starting a thread or
+// replying in the thread of input.ParentID, and notifies the users it
+// mentions
+func (s *ServiceService) CreateComment(ctx context.Context, serviceID int, author string, input domain.CommentInput) (_ *domain.Comment, err error) {
+	ctx, span := tracing.Start(ctx, "ServiceService.CreateComment")
+	defer func() { tracing.End(span, err) }()
+
+	if serviceID <= 0 {
+		return nil, invalidf("invalid service ID: %d", serviceID)
+	}
+	input.Body = strings.TrimSpace(input.Body)
+	if input.Body == "" || utf8.RuneCountInString(input.Body) > maxCommentLength {
+		return nil, invalidf("invalid comment: body is required and must be at most %d characters", maxCommentLength)
+	}
+
+	existing, err := s.repo.GetByID(ctx, serviceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get service: %v", err)
+	}
+	if existing == nil {
+		return nil, notFound("Service")
+	}
+	comment := domain.Comment{
+		ServiceID: serviceID,
+		Author:    author,
+		Body:      input.Body,
+		Mentions:  mentions(input.Body, author),
+		CreatedAt: time.Now().UTC(),
+	}
+	if input.ParentID != nil {
+		parent, err := s.repo.GetComment(ctx, serviceID, *input.ParentID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get comment: %v", err)
+		}
+		if parent == nil {
+			return nil, invalidf("invalid comment: parent_id is not a comment on the service")
+		}
+		// Replies to replies join the thread
+		comment.ParentID = &parent.ID
+		if parent.ParentID != nil {
+			comment.ParentID = parent.ParentID
+		}
+	}
+
+	var created *domain.Comment
+	err = s.repo.InTx(ctx, func(ctx context.Context) error {
+		var err error
+		if created, err = s.repo.CreateComment(ctx, comment); err != nil || created == nil {
+			return err
+		}
+		return s.recordAudit(ctx, domain.AuditActionCreate, domain.AuditResourceComment, created.ID, nil, created)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create comment: %v", err)
+	}
+	if created == nil {
+		return nil, notFound("Service")
+	}
+	if s.mentions != nil && len(created.Mentions) > 0 {
+		s.mentions.NotifyMentions(ctx, &existing.Service, created)
+	}
+	return created, nil
+}
+
+// GetComments retrieves one page of the threads on a service, the most
+// recently started first
+func (s *ServiceService) GetComments(ctx context.Context, query domain.CommentQuery) (_ *domain.CommentListResponse, err error) {
+	ctx, span := tracing.Start(ctx, "ServiceService.GetComments")
+	defer func() { tracing.End(span, err) }()
+
+	if query.ServiceID <= 0 {
+		return nil, invalidf("invalid service ID: %d", query.ServiceID)
+	}
+	if query.Page <= 0 {
+		query.Page = 1
+	}
+	if query.PageSize <= 0 {
+		query.PageSize = 20
+	}
+	if query.PageSize > commentsMaxPageSize {
+		query.PageSize = commentsMaxPageSize
+	}
+
+	existing, err := s.repo.GetByID(ctx, query.ServiceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get service: %v", err)
+	}
+	if existing == nil {
+		return nil, notFound("Service")
+	}
+	comments, total, err := s.repo.GetComments(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get comments: %v", err)
+	}
+
+	return &domain.CommentListResponse{
+		Comments:   comments,
+		Total:      total,
+		Page:       query.Page,
+		PageSize:   query.PageSize,
+		TotalPages: int(math.Ceil(float64(total) / float64(query.PageSize))),
+	}, nil
+}
+
+// DeleteComment removes a comment on a service with its replies. Only its
+// author and admins may delete a comment.
+func (s *ServiceService) DeleteComment(ctx context.Context, serviceID, commentID int, username string, admin bool) (err error) {
+	ctx, span := tracing.Start(ctx, "ServiceService.DeleteComment")
+	defer func() { tracing.End(span, err) }()
+
+	if serviceID <= 0 {
+		return invalidf("invalid service ID: %d", serviceID)
+	}
+	if commentID <= 0 {
+		return invalidf("invalid comment ID: %d", commentID)
+	}
+	comment, err := s.repo.GetComment(ctx, serviceID, commentID)
+	if err != nil {
+		return fmt.Errorf("failed to get comment: %v", err)
+	}
+	if comment == nil {
+		return notFound("Comment")
+	}
+	if comment.Author != username && !admin {
+		return forbiddenf("only the author of a comment or an admin can delete it")
+	}
+
+	deleted, err := s.auditedWrite(ctx, func(ctx context.Context) (bool, error) {
+		return s.repo.DeleteComment(ctx, serviceID, commentID)
+	}, domain.AuditActionDelete, domain.AuditResourceComment, commentID, comment, nil)
+	if err != nil {
+		return fmt.Errorf("failed to delete comment: %v", err)
+	}
+	if !deleted {
+		return notFound("Comment")
+	}
+	return nil
+}
+
+// mentions returns the users mentioned in a comment body, in order and
+// without its author; a trailing period ends a sentence, not a username
+func mentions(body, author string) []string {
+	users := []string{}
+	seen := map[string]bool{author: true}
+	for _, match := range mention.FindAllStringSubmatch(body, -1) {
+		username := strings.TrimRight(match[1], ".")
+		if username == "" || seen[username] {
+			continue
+		}
+		seen[username] = true
+		users = append(users, username)
+		if len(users) == maxMentions {
+			break
+		}
+	}
+	return users
+}
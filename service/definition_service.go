@@ -0,0 +1,122 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"com.kong.connect/domain"
+	"com.kong.connect/openapi"
+	"com.kong.connect/repository"
+	"com.kong.connect/tracing"
+)
+
+// ImportDefinition creates a service from an OpenAPI 3 spec, a Swagger 2.0
+// file or a Postman collection, with a first version holding the spec, or
+// the spec converted to OpenAPI 3. The title, description and version of
+// the definition name the service and its version unless overridden; its
+// tags, or the top-level folders of a collection, tag the service. Tags the
+// catalog cannot hold are dropped and long descriptions are cut rather than
+// refusing the definition.
+func (s *ServiceService) ImportDefinition(ctx context.Context, content []byte, overrides domain.DefinitionImport) (_ *domain.ServiceWithVersions, err error) {
+	ctx, span := tracing.Start(ctx, "ServiceService.ImportDefinition")
+	defer func() { tracing.End(span, err) }()
+
+	if len(content) == 0 {
+		return nil, invalidf("invalid definition: the request body is empty")
+	}
+	definition, err := openapi.ParseDefinition(content)
+	if err != nil {
+		return nil, invalidf("invalid definition: %v", err)
+	}
+
+	input := domain.ServiceInput{
+		Name:        definition.Name,
+		Description: truncate(strings.TrimSpace(definition.Description), maxDescriptionLength),
+		Owner:       overrides.Owner,
+		Tags:        definitionTags(definition.Tags),
+	}
+	if name := strings.TrimSpace(overrides.Name); name != "" {
+		input.Name = name
+	}
+	input, err = normalizeServiceInput(input)
+	if err != nil {
+		return nil, err
+	}
+	version := strings.TrimSpace(definition.Version)
+	if override := strings.TrimSpace(overrides.Version); override != "" {
+		version = override
+	}
+	versionInput, err := normalizeVersionInput(domain.VersionInput{Version: version})
+	if err != nil {
+		return nil, err
+	}
+
+	metadata := openapi.Describe(definition.Doc)
+	metadata.UploadedAt = time.Now().UTC()
+	var service *domain.ServiceWithVersions
+	var created *domain.ServiceVersion
+	err = s.repo.InTx(ctx, func(ctx context.Context) error {
+		var id int
+		var err error
+		if id, created, err = s.repo.ImportDefinition(ctx, input, versionInput, domain.VersionSpec{
+			Content:     definition.Spec,
+			ContentType: openapi.ContentType(definition.Spec),
+			Metadata:    metadata,
+		}); err != nil {
+			return err
+		}
+		if service, err = s.repo.GetByID(ctx, id); err != nil {
+			return fmt.Errorf("failed to get service: %v", err)
+		}
+		if err := s.recordAudit(ctx, domain.AuditActionCreate, domain.AuditResourceService, id, nil, &service.Service); err != nil {
+			return err
+		}
+		return s.recordAudit(ctx, domain.AuditActionCreate, domain.AuditResourceVersion, created.ID, nil, created)
+	})
+	if err != nil {
+		if errors.Is(err, repository.ErrDuplicate) {
+			return nil, conflict("service already exists", "Service already exists")
+		}
+		return nil, fmt.Errorf("failed to import definition: %v", err)
+	}
+
+	s.publish(ctx, domain.EventServiceCreated, &service.Service, nil)
+	s.publish(ctx, domain.EventVersionCreated, &service.Service, created)
+	s.publish(ctx, domain.EventVersionSpecUpdated, &service.Service, created)
+	return service, nil
+}
+
+// definitionTags turns the tags of a definition into catalog tags: spaces
+// become dashes, and tags too long or beyond the limit are dropped
+func definitionTags(names []string) []string {
+	seen := make(map[string]bool)
+	tags := []string{}
+	for _, name := range names {
+		tag := strings.Join(strings.Fields(strings.ToLower(name)), "-")
+		if tag == "" || len(tag) > maxTagLength || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		tags = append(tags, tag)
+		if len(tags) == maxTags {
+			break
+		}
+	}
+	return tags
+}
+
+// truncate cuts s to at most max bytes without splitting a character
+func truncate(s string, max int) string {
+	if len(s) <= max {
+		return s
+	}
+	s = s[:max]
+	for !utf8.ValidString(s) {
+		s = s[:len(s)-1]
+	}
+	return s
+}
@@ -0,0 +1,115 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"com.kong.connect/domain"
+	"com.kong.connect/semver"
+	"com.kong.connect/tracing"
+)
+
+// maxEnvironmentLength bounds environment names such as dev, staging or prod
+const maxEnvironmentLength = 50
+
+// DeployVersion records that an environment of a service runs one of its
+// versions, replacing the version it ran before, and returns the deployed
+// version
+func (s *ServiceService) DeployVersion(ctx context.Context, serviceID int, environment string, input domain.DeploymentInput) (_ *domain.ServiceVersion, err error) {
+	ctx, span := tracing.Start(ctx, "ServiceService.DeployVersion")
+	defer func() { tracing.End(span, err) }()
+
+	if serviceID <= 0 {
+		return nil, invalidf("invalid service ID: %d", serviceID)
+	}
+	environment, err = normalizeEnvironment(environment)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := s.repo.GetByID(ctx, serviceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get service: %v", err)
+	}
+	if existing == nil {
+		return nil, notFound("Service")
+	}
+	i := slices.IndexFunc(existing.Versions, func(v domain.ServiceVersion) bool { return v.Version == semver.Canonical(input.Version) })
+	if i < 0 {
+		return nil, notFound("Version")
+	}
+	before := existing.Versions[i]
+	if slices.Contains(before.Environments, environment) {
+		return &before, nil
+	}
+
+	deployed := before
+	deployed.Environments = append(slices.Clone(before.Environments), environment)
+	slices.Sort(deployed.Environments)
+	found, err := s.auditedWrite(ctx, func(ctx context.Context) (bool, error) {
+		return s.repo.DeployVersion(ctx, serviceID, before.ID, environment)
+	}, domain.AuditActionUpdate, domain.AuditResourceVersion, deployed.ID, &before, &deployed)
+	if err != nil {
+		return nil, fmt.Errorf("failed to deploy version: %v", err)
+	}
+	if !found {
+		return nil, notFound("Service")
+	}
+
+	s.publish(ctx, domain.EventVersionDeployed, &existing.Service, &deployed)
+	return &deployed, nil
+}
+
+// UndeployEnvironment records that an environment no longer runs a service
+func (s *ServiceService) UndeployEnvironment(ctx context.Context, serviceID int, environment string) (err error) {
+	ctx, span := tracing.Start(ctx, "ServiceService.UndeployEnvironment")
+	defer func() { tracing.End(span, err) }()
+
+	if serviceID <= 0 {
+		return invalidf("invalid service ID: %d", serviceID)
+	}
+	environment, err = normalizeEnvironment(environment)
+	if err != nil {
+		return err
+	}
+
+	existing, err := s.repo.GetByID(ctx, serviceID)
+	if err != nil {
+		return fmt.Errorf("failed to get service: %v", err)
+	}
+	if existing == nil {
+		return notFound("Service")
+	}
+	i := slices.IndexFunc(existing.Versions, func(v domain.ServiceVersion) bool { return slices.Contains(v.Environments, environment) })
+	if i < 0 {
+		return notFound("Environment")
+	}
+
+	before := existing.Versions[i]
+	undeployed := before
+	undeployed.Environments = slices.DeleteFunc(slices.Clone(before.Environments), func(e string) bool { return e == environment })
+	found, err := s.auditedWrite(ctx, func(ctx context.Context) (bool, error) {
+		return s.repo.UndeployEnvironment(ctx, serviceID, environment)
+	}, domain.AuditActionUpdate, domain.AuditResourceVersion, undeployed.ID, &before, &undeployed)
+	if err != nil {
+		return fmt.Errorf("failed to undeploy environment: %v", err)
+	}
+	if !found {
+		return notFound("Environment")
+	}
+
+	s.publish(ctx, domain.EventVersionUndeployed, &existing.Service, &undeployed)
+	return nil
+}
+
+// normalizeEnvironment lowercases an environment name and checks that it is
+// usable in URLs and query parameters, like organization slugs
+func normalizeEnvironment(environment string) (string, error) {
+	environment = strings.ToLower(strings.TrimSpace(environment))
+	if !slugPattern.MatchString(environment) || len(environment) > maxEnvironmentLength {
+		return "", invalidf("invalid environment: must be 1 to %d lowercase letters, digits or hyphens", maxEnvironmentLength)
+	}
+	return environment, nil
+}
@@ -0,0 +1,79 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"com.kong.connect/i18n"
+)
+
+// Kinds of the errors returned by the service layer, matched with errors.Is.
+// The handlers map each kind to an HTTP status and a problem type; errors of
+// no kind are internal.
+var (
+	// ErrNotFound is returned when a resource does not exist in the
+	// organization
+	ErrNotFound = errors.New("not found")
+	// ErrValidation is returned for invalid input
+	ErrValidation = errors.New("invalid")
+	// ErrConflict is returned when a write conflicts with the state of the
+	// catalog, such as a duplicate name
+	ErrConflict = errors.New("conflict")
+	// ErrForbidden is returned when the principal may not make a change
+	ErrForbidden = errors.New("forbidden")
+	// ErrUnprocessable is returned for valid input naming something that
+	// cannot take part in a change, such as an unknown user
+	ErrUnprocessable = errors.New("unprocessable")
+)
+
+// Error is a service error of a kind. Detail is the message shown to
+// clients, Error the one logged.
+type Error struct {
+	Kind    error
+	Message string
+	Detail  string
+	// Localized is Detail before formatting, which handlers translate into
+	// the language of the client; Detail is shown when it is empty
+	Localized i18n.Message
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// Unwrap returns the kind of the error
+func (e *Error) Unwrap() error {
+	return e.Kind
+}
+
+// notFound returns the ErrNotFound error of a resource, named as shown to
+// clients, e.g. "Health check"
+func notFound(resource string) error {
+	return &Error{Kind: ErrNotFound, Message: strings.ToLower(resource) + " not found", Detail: resource + " not found",
+		Localized: i18n.M("%s not found", i18n.M(resource))}
+}
+
+// invalidf returns an ErrValidation error; its message, shown to clients,
+// starts with "invalid" and names what is
+func invalidf(format string, args ...interface{}) error {
+	message := fmt.Sprintf(format, args...)
+	return &Error{Kind: ErrValidation, Message: message, Detail: message, Localized: i18n.M(format, args...)}
+}
+
+// conflict returns an ErrConflict error with the detail shown to clients
+func conflict(message, detail string) error {
+	return &Error{Kind: ErrConflict, Message: message, Detail: detail, Localized: i18n.M(detail)}
+}
+
+// forbiddenf returns an ErrForbidden error explaining why to clients
+func forbiddenf(format string, args ...interface{}) error {
+	detail := fmt.Sprintf(format, args...)
+	return &Error{Kind: ErrForbidden, Message: "forbidden: " + detail, Detail: detail, Localized: i18n.M(format, args...)}
+}
+
+// unprocessablef returns an ErrUnprocessable error explaining why to clients
+func unprocessablef(format string, args ...interface{}) error {
+	message := fmt.Sprintf(format, args...)
+	return &Error{Kind: ErrUnprocessable, Message: message, Detail: message, Localized: i18n.M(format, args...)}
+}
@@ -0,0 +1,45 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"com.kong.connect/tracing"
+)
+
+// FavoriteService stars a service for a user, so that listings with
+// Favorites set can be limited to it
+func (s *ServiceService) FavoriteService(ctx context.Context, id int, username string) (err error) {
+	ctx, span := tracing.Start(ctx, "ServiceService.FavoriteService")
+	defer func() { tracing.End(span, err) }()
+
+	if id <= 0 {
+		return invalidf("invalid service ID: %d", id)
+	}
+	found, err := s.repo.AddFavorite(ctx, id, username)
+	if err != nil {
+		return fmt.Errorf("failed to favorite service: %v", err)
+	}
+	if !found {
+		return notFound("Service")
+	}
+	return nil
+}
+
+// UnfavoriteService unstars a service for a user
+func (s *ServiceService) UnfavoriteService(ctx context.Context, id int, username string) (err error) {
+	ctx, span := tracing.Start(ctx, "ServiceService.UnfavoriteService")
+	defer func() { tracing.End(span, err) }()
+
+	if id <= 0 {
+		return invalidf("invalid service ID: %d", id)
+	}
+	found, err := s.repo.RemoveFavorite(ctx, id, username)
+	if err != nil {
+		return fmt.Errorf("failed to unfavorite service: %v", err)
+	}
+	if !found {
+		return notFound("Favorite")
+	}
+	return nil
+}
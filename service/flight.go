@@ -0,0 +1,30 @@
+package service
+
+import (
+	"context"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// coalesce runs read once for all the concurrent callers asking for the same
+// key, so that a burst of identical requests makes a single database query.
+// The read is detached from the cancellation of the caller that started it,
+// which would otherwise fail every caller waiting on it; each caller still
+// stops waiting when its own context is done.
+func coalesce[T any](ctx context.Context, flights *singleflight.Group, key string, read func(context.Context) (T, error)) (T, error) {
+	results := flights.DoChan(key, func() (interface{}, error) {
+		return read(context.WithoutCancel(ctx))
+	})
+
+	select {
+	case result := <-results:
+		if result.Err != nil {
+			var zero T
+			return zero, result.Err
+		}
+		return result.Val.(T), nil
+	case <-ctx.Done():
+		var zero T
+		return zero, ctx.Err()
+	}
+}
@@ -0,0 +1,86 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+func TestCoalesceSharesOneRead(t *testing.T) {
+	var flights singleflight.Group
+	var reads atomic.Int32
+	release := make(chan struct{})
+
+	read := func(ctx context.Context) (int, error) {
+		reads.Add(1)
+		<-release
+		return 42, nil
+	}
+
+	var wg sync.WaitGroup
+	results := make([]int, 20)
+	for i := range results {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i], _ = coalesce(context.Background(), &flights, "services", read)
+		}()
+	}
+
+	// Let the callers join the first read before it completes
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if got := reads.Load(); got != 1 {
+		t.Errorf("Expected 1 read, got %d", got)
+	}
+	for i, result := range results {
+		if result != 42 {
+			t.Errorf("Caller %d: expected 42, got %d", i, result)
+		}
+	}
+}
+
+func TestCoalesceOutlivesTheFirstCaller(t *testing.T) {
+	var flights singleflight.Group
+	release := make(chan struct{})
+
+	read := func(ctx context.Context) (int, error) {
+		<-release
+		return 42, ctx.Err()
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	first := make(chan error, 1)
+	go func() {
+		_, err := coalesce(ctx, &flights, "service:1", read)
+		first <- err
+	}()
+	time.Sleep(20 * time.Millisecond)
+
+	second := make(chan int, 1)
+	go func() {
+		result, err := coalesce(context.Background(), &flights, "service:1", read)
+		if err != nil {
+			t.Errorf("Expected no error, got %v", err)
+		}
+		second <- result
+	}()
+	time.Sleep(20 * time.Millisecond)
+
+	cancel()
+	if err := <-first; !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected the first caller to be cancelled, got %v", err)
+	}
+
+	close(release)
+	if result := <-second; result != 42 {
+		t.Errorf("Expected 42, got %d", result)
+	}
+}
@@ -0,0 +1,198 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/url"
+	"strings"
+	"time"
+
+	"com.kong.connect/domain"
+	"com.kong.connect/tracing"
+)
+
+const (
+	maxHealthCheckURLLength = 2000
+	// healthHistoryMaxPageSize bounds a page of probe results
+	healthHistoryMaxPageSize = 500
+)
+
+// SetHealthCheck declares the URL probing a service, replacing the one it
+// had, and returns its health check. The status is unknown until the next
+// probe.
+func (s *ServiceService) SetHealthCheck(ctx context.Context, serviceID int, input domain.HealthCheckInput) (_ *domain.ServiceHealth, err error) {
+	ctx, span := tracing.Start(ctx, "ServiceService.SetHealthCheck")
+	defer func() { tracing.End(span, err) }()
+
+	if serviceID <= 0 {
+		return nil, invalidf("invalid service ID: %d", serviceID)
+	}
+	input.URL = strings.TrimSpace(input.URL)
+	target, err := url.Parse(input.URL)
+	if err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.Host == "" || len(input.URL) > maxHealthCheckURLLength {
+		return nil, invalidf("invalid health check: url must be an absolute http or https URL of at most %d characters", maxHealthCheckURLLength)
+	}
+
+	existing, err := s.repo.GetByID(ctx, serviceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get service: %v", err)
+	}
+	if existing == nil {
+		return nil, notFound("Service")
+	}
+	if existing.Health != nil && existing.Health.URL == input.URL {
+		return existing.Health, nil
+	}
+
+	after := existing.Service
+	after.Health = &domain.ServiceHealth{URL: input.URL, Status: domain.HealthUnknown}
+	found, err := s.auditedWrite(ctx, func(ctx context.Context) (bool, error) {
+		return s.repo.SetHealthCheck(ctx, serviceID, input.URL)
+	}, domain.AuditActionUpdate, domain.AuditResourceService, serviceID, &existing.Service, &after)
+	if err != nil {
+		return nil, fmt.Errorf("failed to set health check: %v", err)
+	}
+	if !found {
+		return nil, notFound("Service")
+	}
+
+	s.publishUpdate(ctx, &existing.Service, &after)
+	return after.Health, nil
+}
+
+// DeleteHealthCheck stops probing a service and removes its probe results
+func (s *ServiceService) DeleteHealthCheck(ctx context.Context, serviceID int) (err error) {
+	ctx, span := tracing.Start(ctx, "ServiceService.DeleteHealthCheck")
+	defer func() { tracing.End(span, err) }()
+
+	if serviceID <= 0 {
+		return invalidf("invalid service ID: %d", serviceID)
+	}
+	existing, err := s.repo.GetByID(ctx, serviceID)
+	if err != nil {
+		return fmt.Errorf("failed to get service: %v", err)
+	}
+	if existing == nil {
+		return notFound("Service")
+	}
+
+	after := existing.Service
+	after.Health = nil
+	deleted, err := s.auditedWrite(ctx, func(ctx context.Context) (bool, error) {
+		return s.repo.DeleteHealthCheck(ctx, serviceID)
+	}, domain.AuditActionUpdate, domain.AuditResourceService, serviceID, &existing.Service, &after)
+	if err != nil {
+		return fmt.Errorf("failed to delete health check: %v", err)
+	}
+	if !deleted {
+		return notFound("Health check")
+	}
+
+	s.publishUpdate(ctx, &existing.Service, &after)
+	return nil
+}
+
+// GetHealthHistory retrieves the current health of a service and one page
+// of its probe results, newest first
+func (s *ServiceService) GetHealthHistory(ctx context.Context, query domain.HealthHistoryQuery) (_ *domain.HealthHistoryResponse, err error) {
+	ctx, span := tracing.Start(ctx, "ServiceService.GetHealthHistory")
+	defer func() { tracing.End(span, err) }()
+
+	if query.ServiceID <= 0 {
+		return nil, invalidf("invalid service ID: %d", query.ServiceID)
+	}
+	if query.Page <= 0 {
+		query.Page = 1
+	}
+	if query.PageSize <= 0 {
+		query.PageSize = 100
+	}
+	if query.PageSize > healthHistoryMaxPageSize {
+		query.PageSize = healthHistoryMaxPageSize
+	}
+
+	existing, err := s.repo.GetByID(ctx, query.ServiceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get service: %v", err)
+	}
+	if existing == nil {
+		return nil, notFound("Service")
+	}
+	results, total, err := s.repo.GetHealthResults(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get health results: %v", err)
+	}
+
+	return &domain.HealthHistoryResponse{
+		Health:     existing.Health,
+		Results:    results,
+		Total:      total,
+		Page:       query.Page,
+		PageSize:   query.PageSize,
+		TotalPages: int(math.Ceil(float64(total) / float64(query.PageSize))),
+	}, nil
+}
+
+// HealthChecks retrieves the health checks of every organization, for the
+// prober
+func (s *ServiceService) HealthChecks(ctx context.Context) (_ []domain.HealthCheck, err error) {
+	ctx, span := tracing.Start(ctx, "ServiceService.HealthChecks")
+	defer func() { tracing.End(span, err) }()
+
+	checks, err := s.repo.HealthChecks(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get health checks: %v", err)
+	}
+	return checks, nil
+}
+
+// RecordHealthResult stores the outcome of a probe of a service of the
+// organization of ctx, publishing service.health_changed when its status
+// changed. Listings and details cached before show the previous probe until
+// the status changes or they expire.
+func (s *ServiceService) RecordHealthResult(ctx context.Context, result domain.HealthResult) (err error) {
+	ctx, span := tracing.Start(ctx, "ServiceService.RecordHealthResult")
+	defer func() { tracing.End(span, err) }()
+
+	previous, recorded, err := s.repo.RecordHealthResult(ctx, result)
+	if err != nil {
+		return fmt.Errorf("failed to record health result: %v", err)
+	}
+	if !recorded || previous == result.Status {
+		return nil
+	}
+
+	existing, err := s.repo.GetByID(ctx, result.ServiceID)
+	if err != nil {
+		return fmt.Errorf("failed to get service: %v", err)
+	}
+	if existing != nil {
+		s.publish(ctx, domain.EventServiceHealthChanged, &existing.Service, nil)
+	}
+	return nil
+}
+
+// PurgeHealthResults deletes the probe results recorded before the cutoff
+func (s *ServiceService) PurgeHealthResults(ctx context.Context, before time.Time) (_ int64, err error) {
+	ctx, span := tracing.Start(ctx, "ServiceService.PurgeHealthResults")
+	defer func() { tracing.End(span, err) }()
+
+	purged, err := s.repo.PurgeHealthResults(ctx, before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge health results: %v", err)
+	}
+	return purged, nil
+}
+
+// CountHealthResultsBefore returns how many probe results PurgeHealthResults would delete
+func (s *ServiceService) CountHealthResultsBefore(ctx context.Context, before time.Time) (_ int64, err error) {
+	ctx, span := tracing.Start(ctx, "ServiceService.CountHealthResultsBefore")
+	defer func() { tracing.End(span, err) }()
+
+	count, err := s.repo.CountHealthResultsBefore(ctx, before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count health results: %v", err)
+	}
+	return count, nil
+}
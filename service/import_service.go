@@ -0,0 +1,169 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"com.kong.connect/domain"
+	"com.kong.connect/logging"
+	"com.kong.connect/tracing"
+)
+
+// ImportServices creates and updates catalog services from an external
+// source, such as Consul, matching them by name. Empty fields of an imported
+// service are left as they are; its tags replace those of the catalog
+// service and its versions are added, none removed. Services of the same
+// name the importer of source did not create are handled by policy. Every
+// change is made, audited and published like the equivalent single write;
+// invalid services are reported and skipped.
+func (s *ServiceService) ImportServices(ctx context.Context, source string, services []domain.CatalogService, policy string) (_ *domain.ImportResult, err error) {
+	ctx, span := tracing.Start(ctx, "ServiceService.ImportServices")
+	defer func() { tracing.End(span, err) }()
+
+	if !slices.Contains(domain.ConflictPolicies, policy) {
+		return nil, invalidf("invalid conflict policy %q", policy)
+	}
+
+	existing, err := s.allServices(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get services: %v", err)
+	}
+	current := make(map[string]*domain.ServiceWithVersions, len(existing))
+	for i := range existing {
+		current[existing[i].Name] = &existing[i]
+	}
+	imported, err := s.repo.ImportedServiceIDs(ctx, source)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get imported services: %v", err)
+	}
+
+	result := &domain.ImportResult{Conflicts: []string{}, Invalid: []string{}}
+	for _, entry := range services {
+		name := strings.TrimSpace(entry.Name)
+		service, ok := current[name]
+		if !ok {
+			if err := s.importNew(ctx, source, entry, result); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		owned := imported[service.ID]
+		if !owned && policy == domain.ConflictSkip {
+			result.Conflicts = append(result.Conflicts, name)
+			continue
+		}
+		input, versions, err := importedInput(service, entry, owned || policy == domain.ConflictOverwrite)
+		if err != nil {
+			s.reportInvalid(ctx, source, name, err, result)
+			continue
+		}
+
+		changed := false
+		if len(serviceFieldChanges(service.Service, input)) > 0 {
+			if _, err := s.UpdateService(ctx, service.ID, input); err != nil {
+				return nil, fmt.Errorf("failed to import service %q: %v", name, err)
+			}
+			changed = true
+		}
+		for _, version := range versions {
+			if slices.ContainsFunc(service.Versions, func(v domain.ServiceVersion) bool { return v.Version == version }) {
+				continue
+			}
+			if _, err := s.CreateVersion(ctx, service.ID, domain.VersionInput{Version: version}); err != nil {
+				return nil, fmt.Errorf("failed to import version %s of service %q: %v", version, name, err)
+			}
+			changed = true
+		}
+		if !owned && policy == domain.ConflictOverwrite {
+			if err := s.repo.MarkImported(ctx, service.ID, source); err != nil {
+				return nil, fmt.Errorf("failed to import service %q: %v", name, err)
+			}
+		}
+
+		if changed {
+			result.Updated++
+		} else {
+			result.Unchanged++
+		}
+	}
+	return result, nil
+}
+
+// importNew creates an imported service that is not in the catalog yet
+func (s *ServiceService) importNew(ctx context.Context, source string, entry domain.CatalogService, result *domain.ImportResult) error {
+	input, versions, err := importedInput(nil, entry, true)
+	if err != nil {
+		s.reportInvalid(ctx, source, entry.Name, err, result)
+		return nil
+	}
+	created, err := s.CreateService(ctx, input)
+	if err != nil {
+		return fmt.Errorf("failed to import service %q: %v", input.Name, err)
+	}
+	if err := s.repo.MarkImported(ctx, created.ID, source); err != nil {
+		return fmt.Errorf("failed to import service %q: %v", input.Name, err)
+	}
+	for _, version := range versions {
+		if _, err := s.CreateVersion(ctx, created.ID, domain.VersionInput{Version: version}); err != nil {
+			return fmt.Errorf("failed to import version %s of service %q: %v", version, input.Name, err)
+		}
+	}
+	result.Created++
+	return nil
+}
+
+// reportInvalid skips an imported service that is not a valid catalog service
+func (s *ServiceService) reportInvalid(ctx context.Context, source, name string, err error, result *domain.ImportResult) {
+	logging.Component(nil, "service").WarnContext(ctx, "skipping invalid imported service",
+		"source", source, "service", name, "error", err)
+	result.Invalid = append(result.Invalid, name)
+}
+
+// importedInput returns the validated fields and versions an imported entry
+// gives service, nil for a new one. With sync the fields the entry sets and
+// its tags replace those of service; otherwise its tags are only added.
+func importedInput(service *domain.ServiceWithVersions, entry domain.CatalogService, sync bool) (domain.ServiceInput, []string, error) {
+	input := domain.ServiceInput{
+		Name:        entry.Name,
+		Description: entry.Description,
+		Status:      entry.Status,
+		Owner:       entry.Owner,
+		Tags:        entry.Tags,
+	}
+	if service != nil {
+		keep := domain.ServiceInput{
+			Name:        service.Name,
+			Description: service.Description,
+			Status:      service.Status,
+			Owner:       service.Owner,
+			Tags:        append(slices.Clone(service.Tags), entry.Tags...),
+		}
+		if sync {
+			keep.Tags = entry.Tags
+			if strings.TrimSpace(entry.Description) != "" {
+				keep.Description = entry.Description
+			}
+			if strings.TrimSpace(entry.Status) != "" {
+				keep.Status = entry.Status
+			}
+			if strings.TrimSpace(entry.Owner) != "" {
+				keep.Owner = entry.Owner
+			}
+		}
+		input = keep
+	}
+
+	input, err := normalizeServiceInput(input)
+	if err != nil {
+		return input, nil, err
+	}
+	// The catalog document rules apply to the versions too
+	_, versions, err := normalizeCatalog(domain.CatalogDocument{Services: []domain.CatalogService{{Name: input.Name, Versions: entry.Versions}}})
+	if err != nil {
+		return input, nil, err
+	}
+	return input, versions[input.Name], nil
+}
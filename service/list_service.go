@@ -1,31 +1,195 @@
 package service
 
 import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"math"
+	"strconv"
+	"strings"
+	"time"
 
+	"golang.org/x/sync/singleflight"
+
+	"com.kong.connect/analytics"
+	"com.kong.connect/cache"
 	"com.kong.connect/domain"
+	"com.kong.connect/events"
+	"com.kong.connect/openapi"
 	"com.kong.connect/repository"
+	"com.kong.connect/tenant"
+	"com.kong.connect/tracing"
 )
 
+//go:generate go tool mockgen -typed -destination=mocks/service.go -package=mocks . ServiceServiceInterface,MentionNotifier
+
 // ServiceServiceInterface defines the contract for service operations
 type ServiceServiceInterface interface {
-	GetServices(query domain.ServiceQuery) (*domain.ServiceListResponse, error)
-	GetServiceByID(id int) (*domain.ServiceWithVersions, error)
+	GetServices(ctx context.Context, query domain.ServiceQuery) (*domain.ServiceListResponse, error)
+	GetServiceByID(ctx context.Context, id int) (*domain.ServiceWithVersions, error)
+	SearchServices(ctx context.Context, query domain.SearchQuery) (*domain.SearchResponse, error)
+	GetStats(ctx context.Context, recentLimit int) (*domain.CatalogStats, error)
+	GetServiceVersions(ctx context.Context, query domain.VersionQuery) (*domain.VersionListResponse, error)
+	CreateService(ctx context.Context, input domain.ServiceInput) (*domain.ServiceWithVersions, error)
+	UpdateService(ctx context.Context, id int, input domain.ServiceInput) (*domain.ServiceWithVersions, error)
+	DeleteService(ctx context.Context, id int) error
+	CreateVersion(ctx context.Context, serviceID int, input domain.VersionInput) (*domain.ServiceVersion, error)
+	DeleteVersion(ctx context.Context, serviceID, versionID int) error
+	DeployVersion(ctx context.Context, serviceID int, environment string, input domain.DeploymentInput) (*domain.ServiceVersion, error)
+	UndeployEnvironment(ctx context.Context, serviceID int, environment string) error
+	GetAuditLogs(ctx context.Context, query domain.AuditQuery) (*domain.AuditListResponse, error)
+	PurgeAuditLogs(ctx context.Context, before time.Time) (int64, error)
+	CountAuditLogsBefore(ctx context.Context, before time.Time) (int64, error)
+	ApplyCatalog(ctx context.Context, document domain.CatalogDocument, dryRun bool) (*domain.CatalogApplyResult, error)
+	ImportServices(ctx context.Context, source string, services []domain.CatalogService, policy string) (*domain.ImportResult, error)
+	CreateUser(ctx context.Context, input domain.UserInput) (*domain.User, error)
+	ListUsers(ctx context.Context) (*domain.UserListResponse, error)
+	DisableUser(ctx context.Context, username string) (*domain.User, error)
+	IssueToken(ctx context.Context, input domain.TokenInput) (*domain.IssuedToken, error)
+	ListTokens(ctx context.Context) (*domain.TokenListResponse, error)
+	RevokeToken(ctx context.Context, id int) error
+	AuthenticateToken(ctx context.Context, token string) (*domain.TokenGrant, error)
+	CreateOrganization(ctx context.Context, input domain.OrganizationInput) (*domain.Organization, error)
+	ListOrganizations(ctx context.Context) (*domain.OrganizationListResponse, error)
+	GetOrganizationBySlug(ctx context.Context, slug string) (*domain.Organization, error)
+	CreateWebhook(ctx context.Context, input domain.WebhookInput) (*domain.CreatedWebhook, error)
+	ListWebhooks(ctx context.Context) (*domain.WebhookListResponse, error)
+	DeleteWebhook(ctx context.Context, id int) error
+	ListWebhookDeliveries(ctx context.Context, id int) (*domain.WebhookDeliveryListResponse, error)
+	PurgeWebhookDeliveries(ctx context.Context, before time.Time) (int64, error)
+	CountWebhookDeliveriesBefore(ctx context.Context, before time.Time) (int64, error)
+	GetNotificationPreferences(ctx context.Context, username string) (*domain.NotificationPreferences, error)
+	UpdateNotificationPreferences(ctx context.Context, username string, preferences domain.NotificationPreferences) (*domain.NotificationPreferences, error)
+	SubscribeService(ctx context.Context, id int, username string) error
+	UnsubscribeService(ctx context.Context, id int, username string) error
+	ListSubscriptions(ctx context.Context, username string) (*domain.SubscriptionListResponse, error)
+	UploadVersionSpec(ctx context.Context, serviceID, versionID int, content []byte) (*domain.ServiceVersion, error)
+	GetVersionSpec(ctx context.Context, serviceID, versionID int) (*domain.VersionSpec, error)
+	DeleteVersionSpec(ctx context.Context, serviceID, versionID int) error
+	GetVersionDocs(ctx context.Context, serviceID, versionID int) (*openapi.DocsPage, error)
+	GetVersionPostman(ctx context.Context, serviceID, versionID int) (*openapi.PostmanCollection, error)
+	CompareVersions(ctx context.Context, serviceID int, from, to string) (*domain.SpecComparison, error)
+	ImportDefinition(ctx context.Context, content []byte, overrides domain.DefinitionImport) (*domain.ServiceWithVersions, error)
+	SetHealthCheck(ctx context.Context, serviceID int, input domain.HealthCheckInput) (*domain.ServiceHealth, error)
+	DeleteHealthCheck(ctx context.Context, serviceID int) error
+	GetHealthHistory(ctx context.Context, query domain.HealthHistoryQuery) (*domain.HealthHistoryResponse, error)
+	HealthChecks(ctx context.Context) ([]domain.HealthCheck, error)
+	RecordHealthResult(ctx context.Context, result domain.HealthResult) error
+	PurgeHealthResults(ctx context.Context, before time.Time) (int64, error)
+	CountHealthResultsBefore(ctx context.Context, before time.Time) (int64, error)
+	SetSLO(ctx context.Context, serviceID int, slo domain.ServiceSLO) (*domain.ServiceSLO, error)
+	DeleteSLO(ctx context.Context, serviceID int) error
+	GetSLOReport(ctx context.Context, query domain.SLOReportQuery) (*domain.SLOReport, error)
+	GetAnalytics(ctx context.Context, query domain.AnalyticsQuery) (*domain.AnalyticsReport, error)
+	PurgeUsage(ctx context.Context, before time.Time) (int64, error)
+	CountUsageBefore(ctx context.Context, before time.Time) (int64, error)
+	FavoriteService(ctx context.Context, id int, username string) error
+	UnfavoriteService(ctx context.Context, id int, username string) error
+	CreateComment(ctx context.Context, serviceID int, author string, input domain.CommentInput) (*domain.Comment, error)
+	GetComments(ctx context.Context, query domain.CommentQuery) (*domain.CommentListResponse, error)
+	DeleteComment(ctx context.Context, serviceID, commentID int, username string, admin bool) error
+	TransferOwnership(ctx context.Context, serviceID int, username string, admin bool, input domain.TransferInput) (*domain.OwnershipTransfer, error)
+	ConfirmTransfer(ctx context.Context, serviceID int, username string, admin bool) (*domain.OwnershipTransfer, error)
+	DeclineTransfer(ctx context.Context, serviceID int, username string, admin bool) (*domain.OwnershipTransfer, error)
+	GetTransfers(ctx context.Context, serviceID int) (*domain.TransferListResponse, error)
+	SetSunset(ctx context.Context, serviceID, versionID int, input domain.SunsetInput) (*domain.Sunset, error)
+	DeleteSunset(ctx context.Context, serviceID, versionID int) error
+	ApplySunsets(ctx context.Context) error
+	SetSourceRepository(ctx context.Context, serviceID int, input domain.SourceRepositoryInput) (*domain.SourceRepository, error)
+	DeleteSourceRepository(ctx context.Context, serviceID int) error
+	SetTranslation(ctx context.Context, serviceID int, language string, input domain.TranslationInput) (*domain.Translation, error)
+	DeleteTranslation(ctx context.Context, serviceID int, language string) error
+	LinkedRepositories(ctx context.Context) ([]domain.LinkedRepository, error)
+	RecordRepositoryDetails(ctx context.Context, details domain.RepositoryDetails) error
 }
 
 // ServiceService handles business logic for services
 type ServiceService struct {
-	repo *repository.ServiceRepository
+	repo      *repository.ServiceRepository
+	publisher events.Publisher
+	details   *cache.LRU[DetailKey, *domain.ServiceWithVersions]
+	shared    *cache.Shared
+	views     *cache.Views
+	usage     *analytics.Tracker
+	mentions  MentionNotifier
+	// staticUser reports whether a username belongs to a static token
+	staticUser func(username string) bool
+
+	// flights coalesces identical concurrent reads of GetServices and
+	// GetServiceByID
+	flights singleflight.Group
+}
+
+// Option configures optional dependencies of the service
+type Option func(*ServiceService)
+
+// WithPublisher publishes a change event after every successful write
+func WithPublisher(publisher events.Publisher) Option {
+	return func(s *ServiceService) {
+		s.publisher = publisher
+	}
+}
+
+// DetailKey identifies a service in the detail cache. The organization is
+// part of the key so that a service cached for one organization is never
+// served to another.
+type DetailKey struct {
+	OrgID     int
+	ServiceID int
+}
+
+// WithDetailCache serves GetServiceByID from details, which the service
+// invalidates on its own writes. Writes made through other instances must be
+// invalidated by the caller, e.g. from the relayed change events.
+func WithDetailCache(details *cache.LRU[DetailKey, *domain.ServiceWithVersions]) Option {
+	return func(s *ServiceService) {
+		s.details = details
+	}
+}
+
+// WithSharedCache serves GetServices and GetServiceByID from shared, e.g. a
+// cache in Redis shared by the instances of a deployment. A write invalidates
+// the listings and the service it changed; the details of other services stay
+// cached.
+func WithSharedCache(shared *cache.Shared) Option {
+	return func(s *ServiceService) {
+		s.shared = shared
+	}
+}
+
+// WithViews counts the views of every service read by GetServiceByID, so
+// that the services viewed most can be warmed at startup
+func WithViews(views *cache.Views) Option {
+	return func(s *ServiceService) {
+		s.views = views
+	}
+}
+
+// WithUsage counts the detail views of services and the searches, for
+// GetAnalytics
+func WithUsage(usage *analytics.Tracker) Option {
+	return func(s *ServiceService) {
+		s.usage = usage
+	}
 }
 
 // NewServiceService creates a new service service
-func NewServiceService(repo *repository.ServiceRepository) ServiceServiceInterface {
-	return &ServiceService{repo: repo}
+func NewServiceService(repo *repository.ServiceRepository, opts ...Option) ServiceServiceInterface {
+	s := &ServiceService{repo: repo}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
 }
 
-// GetServices retrieves services with pagination, filtering, and sorting
-func (s *ServiceService) GetServices(query domain.ServiceQuery) (*domain.ServiceListResponse, error) {
+// GetServices retrieves services with pagination, filtering, and sorting,
+// with their descriptions in the language of the client when translated
+func (s *ServiceService) GetServices(ctx context.Context, query domain.ServiceQuery) (_ *domain.ServiceListResponse, err error) {
+	ctx, span := tracing.Start(ctx, "ServiceService.GetServices")
+	defer func() { tracing.End(span, err) }()
+
 	// Validate and set defaults for pagination
 	if query.Page <= 0 {
 		query.Page = 1
@@ -42,14 +206,39 @@ func (s *ServiceService) GetServices(query domain.ServiceQuery) (*domain.Service
 		query.SortDir = "asc"
 	}
 
-	services, total, err := s.repo.GetAll(query)
+	query.Environment = strings.ToLower(strings.TrimSpace(query.Environment))
+	if !query.Favorites {
+		query.User = ""
+	}
+	s.recordSearch(ctx, query.Search, query.Page)
+
+	// The query is keyed as normalized above
+	scope := listingsScope(tenant.FromContext(ctx))
+	key := scope + ":" + listingKey(query)
+	var response domain.ServiceListResponse
+	generation, cached := s.sharedGeneration(ctx, scope)
+	// Starring a service does not invalidate listings, so the listings of
+	// favorites are not cached
+	cached = cached && !query.Favorites
+	if cached && s.shared.Get(ctx, generation, key, &response) {
+		return localizeServices(ctx, &response), nil
+	}
+
+	page, err := coalesce(ctx, &s.flights, key, func(ctx context.Context) (servicePage, error) {
+		services, total, err := s.repo.GetAll(ctx, query)
+		return servicePage{services, total}, err
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to get services: %v", err)
 	}
+	services, total := page.services, page.total
+	if services == nil {
+		services = []domain.ServiceWithVersions{}
+	}
 
 	totalPages := int(math.Ceil(float64(total) / float64(query.PageSize)))
 
-	response := &domain.ServiceListResponse{
+	response = domain.ServiceListResponse{
 		Services:   services,
 		Total:      total,
 		Page:       query.Page,
@@ -57,23 +246,106 @@ func (s *ServiceService) GetServices(query domain.ServiceQuery) (*domain.Service
 		TotalPages: totalPages,
 	}
 
-	return response, nil
+	if cached {
+		s.shared.Set(ctx, generation, key, response)
+	}
+	return localizeServices(ctx, &response), nil
 }
 
-// GetServiceByID retrieves a service by ID
-func (s *ServiceService) GetServiceByID(id int) (*domain.ServiceWithVersions, error) {
+// GetServiceByID retrieves a service by ID, with its description in the
+// language of the client when translated
+func (s *ServiceService) GetServiceByID(ctx context.Context, id int) (_ *domain.ServiceWithVersions, err error) {
+	ctx, span := tracing.Start(ctx, "ServiceService.GetServiceByID")
+	defer func() { tracing.End(span, err) }()
+
 	if id <= 0 {
-		return nil, fmt.Errorf("invalid service ID: %d", id)
+		return nil, invalidf("invalid service ID: %d", id)
 	}
 
-	service, err := s.repo.GetByID(id)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get service: %v", err)
+	orgID := tenant.FromContext(ctx)
+	detailKey := DetailKey{OrgID: orgID, ServiceID: id}
+	var generation uint64
+	if s.details != nil {
+		if service, ok := s.details.Get(detailKey); ok {
+			s.recordView(ctx, id)
+			return localizeService(ctx, service), nil
+		}
+		generation = s.details.Generation()
 	}
 
-	if service == nil {
-		return nil, fmt.Errorf("service not found")
+	key := serviceScope(orgID, id)
+	sharedGeneration, cached := s.sharedGeneration(ctx, key)
+	service := new(domain.ServiceWithVersions)
+	if !cached || !s.shared.Get(ctx, sharedGeneration, key, service) {
+		service, err = coalesce(ctx, &s.flights, key, func(ctx context.Context) (*domain.ServiceWithVersions, error) {
+			return s.repo.GetByID(ctx, id)
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get service: %v", err)
+		}
+
+		if service == nil {
+			return nil, notFound("Service")
+		}
+
+		if cached {
+			s.shared.Set(ctx, sharedGeneration, key, service)
+		}
+	}
+
+	if s.details != nil {
+		s.details.Add(detailKey, service, generation)
+	}
+	s.recordView(ctx, id)
+	return localizeService(ctx, service), nil
+}
+
+// recordView counts a view of service id when views or usage are counted
+func (s *ServiceService) recordView(ctx context.Context, id int) {
+	if isWarming(ctx) {
+		return
 	}
+	if s.views != nil {
+		s.views.Record(id)
+	}
+	if s.usage != nil {
+		s.usage.RecordView(ctx, id)
+	}
+}
+
+// servicePage is a page of GetAll shared by coalesced listings
+type servicePage struct {
+	services []domain.ServiceWithVersions
+	total    int
+}
+
+// listingsScope holds the listings of an organization in the shared cache;
+// any write may change any page, so they are invalidated together
+func listingsScope(orgID int) string {
+	return "org:" + strconv.Itoa(orgID) + ":services"
+}
+
+// serviceScope holds the details of a service in the shared cache, under a
+// key of the same name. Keys and singleflight groups include the
+// organization, so that no read is shared across organizations.
+func serviceScope(orgID, id int) string {
+	return "org:" + strconv.Itoa(orgID) + ":service:" + strconv.Itoa(id)
+}
+
+// sharedGeneration returns the generation of scope in the shared cache, and
+// false when there is no shared cache or it is unreachable
+func (s *ServiceService) sharedGeneration(ctx context.Context, scope string) (int64, bool) {
+	if s.shared == nil {
+		return 0, false
+	}
+	generation, err := s.shared.Generation(ctx, scope)
+	return generation, err == nil
+}
 
-	return service, nil
+// listingKey identifies a listing query in the shared cache
+func listingKey(query domain.ServiceQuery) string {
+	// Marshalling a struct of plain fields cannot fail
+	encoded, _ := json.Marshal(query)
+	sum := sha256.Sum256(encoded)
+	return hex.EncodeToString(sum[:16])
 }
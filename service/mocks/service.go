@@ -0,0 +1,3049 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: com.kong.connect/service (interfaces: ServiceServiceInterface,MentionNotifier)
+//
+// Generated by this command:
+//
+//	mockgen -typed -destination=mocks/service.go -package=mocks . ServiceServiceInterface,MentionNotifier
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	domain "com.kong.connect/domain"
+	openapi "com.kong.connect/openapi"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockServiceServiceInterface is a mock of ServiceServiceInterface interface.
+type MockServiceServiceInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockServiceServiceInterfaceMockRecorder
+	isgomock struct{}
+}
+
+// MockServiceServiceInterfaceMockRecorder is the mock recorder for MockServiceServiceInterface.
+type MockServiceServiceInterfaceMockRecorder struct {
+	mock *MockServiceServiceInterface
+}
+
+// NewMockServiceServiceInterface creates a new mock instance.
+func NewMockServiceServiceInterface(ctrl *gomock.Controller) *MockServiceServiceInterface {
+	mock := &MockServiceServiceInterface{ctrl: ctrl}
+	mock.recorder = &MockServiceServiceInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockServiceServiceInterface) EXPECT() *MockServiceServiceInterfaceMockRecorder {
+	return m.recorder
+}
+
+// ApplyCatalog mocks base method.
+func (m *MockServiceServiceInterface) ApplyCatalog(ctx context.Context, document domain.CatalogDocument, dryRun bool) (*domain.CatalogApplyResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ApplyCatalog", ctx, document, dryRun)
+	ret0, _ := ret[0].(*domain.CatalogApplyResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ApplyCatalog indicates an expected call of ApplyCatalog.
+func (mr *MockServiceServiceInterfaceMockRecorder) ApplyCatalog(ctx, document, dryRun any) *MockServiceServiceInterfaceApplyCatalogCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ApplyCatalog", reflect.TypeOf((*MockServiceServiceInterface)(nil).ApplyCatalog), ctx, document, dryRun)
+	return &MockServiceServiceInterfaceApplyCatalogCall{Call: call}
+}
+
+// MockServiceServiceInterfaceApplyCatalogCall wrap *gomock.Call
+type MockServiceServiceInterfaceApplyCatalogCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockServiceServiceInterfaceApplyCatalogCall) Return(arg0 *domain.CatalogApplyResult, arg1 error) *MockServiceServiceInterfaceApplyCatalogCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockServiceServiceInterfaceApplyCatalogCall) Do(f func(context.Context, domain.CatalogDocument, bool) (*domain.CatalogApplyResult, error)) *MockServiceServiceInterfaceApplyCatalogCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockServiceServiceInterfaceApplyCatalogCall) DoAndReturn(f func(context.Context, domain.CatalogDocument, bool) (*domain.CatalogApplyResult, error)) *MockServiceServiceInterfaceApplyCatalogCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// ApplySunsets mocks base method.
+func (m *MockServiceServiceInterface) ApplySunsets(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ApplySunsets", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ApplySunsets indicates an expected call of ApplySunsets.
+func (mr *MockServiceServiceInterfaceMockRecorder) ApplySunsets(ctx any) *MockServiceServiceInterfaceApplySunsetsCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ApplySunsets", reflect.TypeOf((*MockServiceServiceInterface)(nil).ApplySunsets), ctx)
+	return &MockServiceServiceInterfaceApplySunsetsCall{Call: call}
+}
+
+// MockServiceServiceInterfaceApplySunsetsCall wrap *gomock.Call
+type MockServiceServiceInterfaceApplySunsetsCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockServiceServiceInterfaceApplySunsetsCall) Return(arg0 error) *MockServiceServiceInterfaceApplySunsetsCall {
+	c.Call = c.Call.Return(arg0)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockServiceServiceInterfaceApplySunsetsCall) Do(f func(context.Context) error) *MockServiceServiceInterfaceApplySunsetsCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockServiceServiceInterfaceApplySunsetsCall) DoAndReturn(f func(context.Context) error) *MockServiceServiceInterfaceApplySunsetsCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// AuthenticateToken mocks base method.
+func (m *MockServiceServiceInterface) AuthenticateToken(ctx context.Context, token string) (*domain.TokenGrant, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AuthenticateToken", ctx, token)
+	ret0, _ := ret[0].(*domain.TokenGrant)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AuthenticateToken indicates an expected call of AuthenticateToken.
+func (mr *MockServiceServiceInterfaceMockRecorder) AuthenticateToken(ctx, token any) *MockServiceServiceInterfaceAuthenticateTokenCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AuthenticateToken", reflect.TypeOf((*MockServiceServiceInterface)(nil).AuthenticateToken), ctx, token)
+	return &MockServiceServiceInterfaceAuthenticateTokenCall{Call: call}
+}
+
+// MockServiceServiceInterfaceAuthenticateTokenCall wrap *gomock.Call
+type MockServiceServiceInterfaceAuthenticateTokenCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockServiceServiceInterfaceAuthenticateTokenCall) Return(arg0 *domain.TokenGrant, arg1 error) *MockServiceServiceInterfaceAuthenticateTokenCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockServiceServiceInterfaceAuthenticateTokenCall) Do(f func(context.Context, string) (*domain.TokenGrant, error)) *MockServiceServiceInterfaceAuthenticateTokenCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockServiceServiceInterfaceAuthenticateTokenCall) DoAndReturn(f func(context.Context, string) (*domain.TokenGrant, error)) *MockServiceServiceInterfaceAuthenticateTokenCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// CompareVersions mocks base method.
+func (m *MockServiceServiceInterface) CompareVersions(ctx context.Context, serviceID int, from, to string) (*domain.SpecComparison, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompareVersions", ctx, serviceID, from, to)
+	ret0, _ := ret[0].(*domain.SpecComparison)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CompareVersions indicates an expected call of CompareVersions.
+func (mr *MockServiceServiceInterfaceMockRecorder) CompareVersions(ctx, serviceID, from, to any) *MockServiceServiceInterfaceCompareVersionsCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompareVersions", reflect.TypeOf((*MockServiceServiceInterface)(nil).CompareVersions), ctx, serviceID, from, to)
+	return &MockServiceServiceInterfaceCompareVersionsCall{Call: call}
+}
+
+// MockServiceServiceInterfaceCompareVersionsCall wrap *gomock.Call
+type MockServiceServiceInterfaceCompareVersionsCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockServiceServiceInterfaceCompareVersionsCall) Return(arg0 *domain.SpecComparison, arg1 error) *MockServiceServiceInterfaceCompareVersionsCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockServiceServiceInterfaceCompareVersionsCall) Do(f func(context.Context, int, string, string) (*domain.SpecComparison, error)) *MockServiceServiceInterfaceCompareVersionsCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockServiceServiceInterfaceCompareVersionsCall) DoAndReturn(f func(context.Context, int, string, string) (*domain.SpecComparison, error)) *MockServiceServiceInterfaceCompareVersionsCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// ConfirmTransfer mocks base method.
+func (m *MockServiceServiceInterface) ConfirmTransfer(ctx context.Context, serviceID int, username string, admin bool) (*domain.OwnershipTransfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConfirmTransfer", ctx, serviceID, username, admin)
+	ret0, _ := ret[0].(*domain.OwnershipTransfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ConfirmTransfer indicates an expected call of ConfirmTransfer.
+func (mr *MockServiceServiceInterfaceMockRecorder) ConfirmTransfer(ctx, serviceID, username, admin any) *MockServiceServiceInterfaceConfirmTransferCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConfirmTransfer", reflect.TypeOf((*MockServiceServiceInterface)(nil).ConfirmTransfer), ctx, serviceID, username, admin)
+	return &MockServiceServiceInterfaceConfirmTransferCall{Call: call}
+}
+
+// MockServiceServiceInterfaceConfirmTransferCall wrap *gomock.Call
+type MockServiceServiceInterfaceConfirmTransferCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockServiceServiceInterfaceConfirmTransferCall) Return(arg0 *domain.OwnershipTransfer, arg1 error) *MockServiceServiceInterfaceConfirmTransferCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockServiceServiceInterfaceConfirmTransferCall) Do(f func(context.Context, int, string, bool) (*domain.OwnershipTransfer, error)) *MockServiceServiceInterfaceConfirmTransferCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockServiceServiceInterfaceConfirmTransferCall) DoAndReturn(f func(context.Context, int, string, bool) (*domain.OwnershipTransfer, error)) *MockServiceServiceInterfaceConfirmTransferCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// CountAuditLogsBefore mocks base method.
+func (m *MockServiceServiceInterface) CountAuditLogsBefore(ctx context.Context, before time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountAuditLogsBefore", ctx, before)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountAuditLogsBefore indicates an expected call of CountAuditLogsBefore.
+func (mr *MockServiceServiceInterfaceMockRecorder) CountAuditLogsBefore(ctx, before any) *MockServiceServiceInterfaceCountAuditLogsBeforeCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountAuditLogsBefore", reflect.TypeOf((*MockServiceServiceInterface)(nil).CountAuditLogsBefore), ctx, before)
+	return &MockServiceServiceInterfaceCountAuditLogsBeforeCall{Call: call}
+}
+
+// MockServiceServiceInterfaceCountAuditLogsBeforeCall wrap *gomock.Call
+type MockServiceServiceInterfaceCountAuditLogsBeforeCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockServiceServiceInterfaceCountAuditLogsBeforeCall) Return(arg0 int64, arg1 error) *MockServiceServiceInterfaceCountAuditLogsBeforeCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockServiceServiceInterfaceCountAuditLogsBeforeCall) Do(f func(context.Context, time.Time) (int64, error)) *MockServiceServiceInterfaceCountAuditLogsBeforeCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockServiceServiceInterfaceCountAuditLogsBeforeCall) DoAndReturn(f func(context.Context, time.Time) (int64, error)) *MockServiceServiceInterfaceCountAuditLogsBeforeCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// CountHealthResultsBefore mocks base method.
+func (m *MockServiceServiceInterface) CountHealthResultsBefore(ctx context.Context, before time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountHealthResultsBefore", ctx, before)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountHealthResultsBefore indicates an expected call of CountHealthResultsBefore.
+func (mr *MockServiceServiceInterfaceMockRecorder) CountHealthResultsBefore(ctx, before any) *MockServiceServiceInterfaceCountHealthResultsBeforeCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountHealthResultsBefore", reflect.TypeOf((*MockServiceServiceInterface)(nil).CountHealthResultsBefore), ctx, before)
+	return &MockServiceServiceInterfaceCountHealthResultsBeforeCall{Call: call}
+}
+
+// MockServiceServiceInterfaceCountHealthResultsBeforeCall wrap *gomock.Call
+type MockServiceServiceInterfaceCountHealthResultsBeforeCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockServiceServiceInterfaceCountHealthResultsBeforeCall) Return(arg0 int64, arg1 error) *MockServiceServiceInterfaceCountHealthResultsBeforeCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockServiceServiceInterfaceCountHealthResultsBeforeCall) Do(f func(context.Context, time.Time) (int64, error)) *MockServiceServiceInterfaceCountHealthResultsBeforeCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockServiceServiceInterfaceCountHealthResultsBeforeCall) DoAndReturn(f func(context.Context, time.Time) (int64, error)) *MockServiceServiceInterfaceCountHealthResultsBeforeCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// CountUsageBefore mocks base method.
+func (m *MockServiceServiceInterface) CountUsageBefore(ctx context.Context, before time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountUsageBefore", ctx, before)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountUsageBefore indicates an expected call of CountUsageBefore.
+func (mr *MockServiceServiceInterfaceMockRecorder) CountUsageBefore(ctx, before any) *MockServiceServiceInterfaceCountUsageBeforeCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountUsageBefore", reflect.TypeOf((*MockServiceServiceInterface)(nil).CountUsageBefore), ctx, before)
+	return &MockServiceServiceInterfaceCountUsageBeforeCall{Call: call}
+}
+
+// MockServiceServiceInterfaceCountUsageBeforeCall wrap *gomock.Call
+type MockServiceServiceInterfaceCountUsageBeforeCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockServiceServiceInterfaceCountUsageBeforeCall) Return(arg0 int64, arg1 error) *MockServiceServiceInterfaceCountUsageBeforeCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockServiceServiceInterfaceCountUsageBeforeCall) Do(f func(context.Context, time.Time) (int64, error)) *MockServiceServiceInterfaceCountUsageBeforeCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockServiceServiceInterfaceCountUsageBeforeCall) DoAndReturn(f func(context.Context, time.Time) (int64, error)) *MockServiceServiceInterfaceCountUsageBeforeCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// CountWebhookDeliveriesBefore mocks base method.
+func (m *MockServiceServiceInterface) CountWebhookDeliveriesBefore(ctx context.Context, before time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountWebhookDeliveriesBefore", ctx, before)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountWebhookDeliveriesBefore indicates an expected call of CountWebhookDeliveriesBefore.
+func (mr *MockServiceServiceInterfaceMockRecorder) CountWebhookDeliveriesBefore(ctx, before any) *MockServiceServiceInterfaceCountWebhookDeliveriesBeforeCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountWebhookDeliveriesBefore", reflect.TypeOf((*MockServiceServiceInterface)(nil).CountWebhookDeliveriesBefore), ctx, before)
+	return &MockServiceServiceInterfaceCountWebhookDeliveriesBeforeCall{Call: call}
+}
+
+// MockServiceServiceInterfaceCountWebhookDeliveriesBeforeCall wrap *gomock.Call
+type MockServiceServiceInterfaceCountWebhookDeliveriesBeforeCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockServiceServiceInterfaceCountWebhookDeliveriesBeforeCall) Return(arg0 int64, arg1 error) *MockServiceServiceInterfaceCountWebhookDeliveriesBeforeCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockServiceServiceInterfaceCountWebhookDeliveriesBeforeCall) Do(f func(context.Context, time.Time) (int64, error)) *MockServiceServiceInterfaceCountWebhookDeliveriesBeforeCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockServiceServiceInterfaceCountWebhookDeliveriesBeforeCall) DoAndReturn(f func(context.Context, time.Time) (int64, error)) *MockServiceServiceInterfaceCountWebhookDeliveriesBeforeCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// CreateComment mocks base method.
+func (m *MockServiceServiceInterface) CreateComment(ctx context.Context, serviceID int, author string, input domain.CommentInput) (*domain.Comment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateComment", ctx, serviceID, author, input)
+	ret0, _ := ret[0].(*domain.Comment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateComment indicates an expected call of CreateComment.
+func (mr *MockServiceServiceInterfaceMockRecorder) CreateComment(ctx, serviceID, author, input any) *MockServiceServiceInterfaceCreateCommentCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateComment", reflect.TypeOf((*MockServiceServiceInterface)(nil).CreateComment), ctx, serviceID, author, input)
+	return &MockServiceServiceInterfaceCreateCommentCall{Call: call}
+}
+
+// MockServiceServiceInterfaceCreateCommentCall wrap *gomock.Call
+type MockServiceServiceInterfaceCreateCommentCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockServiceServiceInterfaceCreateCommentCall) Return(arg0 *domain.Comment, arg1 error) *MockServiceServiceInterfaceCreateCommentCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockServiceServiceInterfaceCreateCommentCall) Do(f func(context.Context, int, string, domain.CommentInput) (*domain.Comment, error)) *MockServiceServiceInterfaceCreateCommentCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockServiceServiceInterfaceCreateCommentCall) DoAndReturn(f func(context.Context, int, string, domain.CommentInput) (*domain.Comment, error)) *MockServiceServiceInterfaceCreateCommentCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// CreateOrganization mocks base method.
+func (m *MockServiceServiceInterface) CreateOrganization(ctx context.Context, input domain.OrganizationInput) (*domain.Organization, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateOrganization", ctx, input)
+	ret0, _ := ret[0].(*domain.Organization)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateOrganization indicates an expected call of CreateOrganization.
+func (mr *MockServiceServiceInterfaceMockRecorder) CreateOrganization(ctx, input any) *MockServiceServiceInterfaceCreateOrganizationCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOrganization", reflect.TypeOf((*MockServiceServiceInterface)(nil).CreateOrganization), ctx, input)
+	return &MockServiceServiceInterfaceCreateOrganizationCall{Call: call}
+}
+
+// MockServiceServiceInterfaceCreateOrganizationCall wrap *gomock.Call
+type MockServiceServiceInterfaceCreateOrganizationCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockServiceServiceInterfaceCreateOrganizationCall) Return(arg0 *domain.Organization, arg1 error) *MockServiceServiceInterfaceCreateOrganizationCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockServiceServiceInterfaceCreateOrganizationCall) Do(f func(context.Context, domain.OrganizationInput) (*domain.Organization, error)) *MockServiceServiceInterfaceCreateOrganizationCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockServiceServiceInterfaceCreateOrganizationCall) DoAndReturn(f func(context.Context, domain.OrganizationInput) (*domain.Organization, error)) *MockServiceServiceInterfaceCreateOrganizationCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// CreateService mocks base method.
+func (m *MockServiceServiceInterface) CreateService(ctx context.Context, input domain.ServiceInput) (*domain.ServiceWithVersions, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateService", ctx, input)
+	ret0, _ := ret[0].(*domain.ServiceWithVersions)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateService indicates an expected call of CreateService.
+func (mr *MockServiceServiceInterfaceMockRecorder) CreateService(ctx, input any) *MockServiceServiceInterfaceCreateServiceCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateService", reflect.TypeOf((*MockServiceServiceInterface)(nil).CreateService), ctx, input)
+	return &MockServiceServiceInterfaceCreateServiceCall{Call: call}
+}
+
+// MockServiceServiceInterfaceCreateServiceCall wrap *gomock.Call
+type MockServiceServiceInterfaceCreateServiceCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockServiceServiceInterfaceCreateServiceCall) Return(arg0 *domain.ServiceWithVersions, arg1 error) *MockServiceServiceInterfaceCreateServiceCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockServiceServiceInterfaceCreateServiceCall) Do(f func(context.Context, domain.ServiceInput) (*domain.ServiceWithVersions, error)) *MockServiceServiceInterfaceCreateServiceCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockServiceServiceInterfaceCreateServiceCall) DoAndReturn(f func(context.Context, domain.ServiceInput) (*domain.ServiceWithVersions, error)) *MockServiceServiceInterfaceCreateServiceCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// CreateUser mocks base method.
+func (m *MockServiceServiceInterface) CreateUser(ctx context.Context, input domain.UserInput) (*domain.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateUser", ctx, input)
+	ret0, _ := ret[0].(*domain.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateUser indicates an expected call of CreateUser.
+func (mr *MockServiceServiceInterfaceMockRecorder) CreateUser(ctx, input any) *MockServiceServiceInterfaceCreateUserCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUser", reflect.TypeOf((*MockServiceServiceInterface)(nil).CreateUser), ctx, input)
+	return &MockServiceServiceInterfaceCreateUserCall{Call: call}
+}
+
+// MockServiceServiceInterfaceCreateUserCall wrap *gomock.Call
+type MockServiceServiceInterfaceCreateUserCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockServiceServiceInterfaceCreateUserCall) Return(arg0 *domain.User, arg1 error) *MockServiceServiceInterfaceCreateUserCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockServiceServiceInterfaceCreateUserCall) Do(f func(context.Context, domain.UserInput) (*domain.User, error)) *MockServiceServiceInterfaceCreateUserCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockServiceServiceInterfaceCreateUserCall) DoAndReturn(f func(context.Context, domain.UserInput) (*domain.User, error)) *MockServiceServiceInterfaceCreateUserCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// CreateVersion mocks base method.
+func (m *MockServiceServiceInterface) CreateVersion(ctx context.Context, serviceID int, input domain.VersionInput) (*domain.ServiceVersion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateVersion", ctx, serviceID, input)
+	ret0, _ := ret[0].(*domain.ServiceVersion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateVersion indicates an expected call of CreateVersion.
+func (mr *MockServiceServiceInterfaceMockRecorder) CreateVersion(ctx, serviceID, input any) *MockServiceServiceInterfaceCreateVersionCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateVersion", reflect.TypeOf((*MockServiceServiceInterface)(nil).CreateVersion), ctx, serviceID, input)
+	return &MockServiceServiceInterfaceCreateVersionCall{Call: call}
+}
+
+// MockServiceServiceInterfaceCreateVersionCall wrap *gomock.Call
+type MockServiceServiceInterfaceCreateVersionCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockServiceServiceInterfaceCreateVersionCall) Return(arg0 *domain.ServiceVersion, arg1 error) *MockServiceServiceInterfaceCreateVersionCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockServiceServiceInterfaceCreateVersionCall) Do(f func(context.Context, int, domain.VersionInput) (*domain.ServiceVersion, error)) *MockServiceServiceInterfaceCreateVersionCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockServiceServiceInterfaceCreateVersionCall) DoAndReturn(f func(context.Context, int, domain.VersionInput) (*domain.ServiceVersion, error)) *MockServiceServiceInterfaceCreateVersionCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// CreateWebhook mocks base method.
+func (m *MockServiceServiceInterface) CreateWebhook(ctx context.Context, input domain.WebhookInput) (*domain.CreatedWebhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateWebhook", ctx, input)
+	ret0, _ := ret[0].(*domain.CreatedWebhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateWebhook indicates an expected call of CreateWebhook.
+func (mr *MockServiceServiceInterfaceMockRecorder) CreateWebhook(ctx, input any) *MockServiceServiceInterfaceCreateWebhookCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWebhook", reflect.TypeOf((*MockServiceServiceInterface)(nil).CreateWebhook), ctx, input)
+	return &MockServiceServiceInterfaceCreateWebhookCall{Call: call}
+}
+
+// MockServiceServiceInterfaceCreateWebhookCall wrap *gomock.Call
+type MockServiceServiceInterfaceCreateWebhookCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockServiceServiceInterfaceCreateWebhookCall) Return(arg0 *domain.CreatedWebhook, arg1 error) *MockServiceServiceInterfaceCreateWebhookCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockServiceServiceInterfaceCreateWebhookCall) Do(f func(context.Context, domain.WebhookInput) (*domain.CreatedWebhook, error)) *MockServiceServiceInterfaceCreateWebhookCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockServiceServiceInterfaceCreateWebhookCall) DoAndReturn(f func(context.Context, domain.WebhookInput) (*domain.CreatedWebhook, error)) *MockServiceServiceInterfaceCreateWebhookCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// DeclineTransfer mocks base method.
+func (m *MockServiceServiceInterface) DeclineTransfer(ctx context.Context, serviceID int, username string, admin bool) (*domain.OwnershipTransfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeclineTransfer", ctx, serviceID, username, admin)
+	ret0, _ := ret[0].(*domain.OwnershipTransfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeclineTransfer indicates an expected call of DeclineTransfer.
+func (mr *MockServiceServiceInterfaceMockRecorder) DeclineTransfer(ctx, serviceID, username, admin any) *MockServiceServiceInterfaceDeclineTransferCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeclineTransfer", reflect.TypeOf((*MockServiceServiceInterface)(nil).DeclineTransfer), ctx, serviceID, username, admin)
+	return &MockServiceServiceInterfaceDeclineTransferCall{Call: call}
+}
+
+// MockServiceServiceInterfaceDeclineTransferCall wrap *gomock.Call
+type MockServiceServiceInterfaceDeclineTransferCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockServiceServiceInterfaceDeclineTransferCall) Return(arg0 *domain.OwnershipTransfer, arg1 error) *MockServiceServiceInterfaceDeclineTransferCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockServiceServiceInterfaceDeclineTransferCall) Do(f func(context.Context, int, string, bool) (*domain.OwnershipTransfer, error)) *MockServiceServiceInterfaceDeclineTransferCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockServiceServiceInterfaceDeclineTransferCall) DoAndReturn(f func(context.Context, int, string, bool) (*domain.OwnershipTransfer, error)) *MockServiceServiceInterfaceDeclineTransferCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// DeleteComment mocks base method.
+func (m *MockServiceServiceInterface) DeleteComment(ctx context.Context, serviceID, commentID int, username string, admin bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteComment", ctx, serviceID, commentID, username, admin)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteComment indicates an expected call of DeleteComment.
+func (mr *MockServiceServiceInterfaceMockRecorder) DeleteComment(ctx, serviceID, commentID, username, admin any) *MockServiceServiceInterfaceDeleteCommentCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteComment", reflect.TypeOf((*MockServiceServiceInterface)(nil).DeleteComment), ctx, serviceID, commentID, username, admin)
+	return &MockServiceServiceInterfaceDeleteCommentCall{Call: call}
+}
+
+// MockServiceServiceInterfaceDeleteCommentCall wrap *gomock.Call
+type MockServiceServiceInterfaceDeleteCommentCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockServiceServiceInterfaceDeleteCommentCall) Return(arg0 error) *MockServiceServiceInterfaceDeleteCommentCall {
+	c.Call = c.Call.Return(arg0)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockServiceServiceInterfaceDeleteCommentCall) Do(f func(context.Context, int, int, string, bool) error) *MockServiceServiceInterfaceDeleteCommentCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockServiceServiceInterfaceDeleteCommentCall) DoAndReturn(f func(context.Context, int, int, string, bool) error) *MockServiceServiceInterfaceDeleteCommentCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// DeleteHealthCheck mocks base method.
+func (m *MockServiceServiceInterface) DeleteHealthCheck(ctx context.Context, serviceID int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteHealthCheck", ctx, serviceID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteHealthCheck indicates an expected call of DeleteHealthCheck.
+func (mr *MockServiceServiceInterfaceMockRecorder) DeleteHealthCheck(ctx, serviceID any) *MockServiceServiceInterfaceDeleteHealthCheckCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteHealthCheck", reflect.TypeOf((*MockServiceServiceInterface)(nil).DeleteHealthCheck), ctx, serviceID)
+	return &MockServiceServiceInterfaceDeleteHealthCheckCall{Call: call}
+}
+
+// MockServiceServiceInterfaceDeleteHealthCheckCall wrap *gomock.Call
+type MockServiceServiceInterfaceDeleteHealthCheckCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockServiceServiceInterfaceDeleteHealthCheckCall) Return(arg0 error) *MockServiceServiceInterfaceDeleteHealthCheckCall {
+	c.Call = c.Call.Return(arg0)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockServiceServiceInterfaceDeleteHealthCheckCall) Do(f func(context.Context, int) error) *MockServiceServiceInterfaceDeleteHealthCheckCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockServiceServiceInterfaceDeleteHealthCheckCall) DoAndReturn(f func(context.Context, int) error) *MockServiceServiceInterfaceDeleteHealthCheckCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// DeleteSLO mocks base method.
+func (m *MockServiceServiceInterface) DeleteSLO(ctx context.Context, serviceID int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteSLO", ctx, serviceID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteSLO indicates an expected call of DeleteSLO.
+func (mr *MockServiceServiceInterfaceMockRecorder) DeleteSLO(ctx, serviceID any) *MockServiceServiceInterfaceDeleteSLOCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSLO", reflect.TypeOf((*MockServiceServiceInterface)(nil).DeleteSLO), ctx, serviceID)
+	return &MockServiceServiceInterfaceDeleteSLOCall{Call: call}
+}
+
+// MockServiceServiceInterfaceDeleteSLOCall wrap *gomock.Call
+type MockServiceServiceInterfaceDeleteSLOCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockServiceServiceInterfaceDeleteSLOCall) Return(arg0 error) *MockServiceServiceInterfaceDeleteSLOCall {
+	c.Call = c.Call.Return(arg0)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockServiceServiceInterfaceDeleteSLOCall) Do(f func(context.Context, int) error) *MockServiceServiceInterfaceDeleteSLOCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockServiceServiceInterfaceDeleteSLOCall) DoAndReturn(f func(context.Context, int) error) *MockServiceServiceInterfaceDeleteSLOCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// DeleteService mocks base method.
+func (m *MockServiceServiceInterface) DeleteService(ctx context.Context, id int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteService", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteService indicates an expected call of DeleteService.
+func (mr *MockServiceServiceInterfaceMockRecorder) DeleteService(ctx, id any) *MockServiceServiceInterfaceDeleteServiceCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteService", reflect.TypeOf((*MockServiceServiceInterface)(nil).DeleteService), ctx, id)
+	return &MockServiceServiceInterfaceDeleteServiceCall{Call: call}
+}
+
+// MockServiceServiceInterfaceDeleteServiceCall wrap *gomock.Call
+type MockServiceServiceInterfaceDeleteServiceCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockServiceServiceInterfaceDeleteServiceCall) Return(arg0 error) *MockServiceServiceInterfaceDeleteServiceCall {
+	c.Call = c.Call.Return(arg0)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockServiceServiceInterfaceDeleteServiceCall) Do(f func(context.Context, int) error) *MockServiceServiceInterfaceDeleteServiceCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockServiceServiceInterfaceDeleteServiceCall) DoAndReturn(f func(context.Context, int) error) *MockServiceServiceInterfaceDeleteServiceCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// DeleteSourceRepository mocks base method.
+func (m *MockServiceServiceInterface) DeleteSourceRepository(ctx context.Context, serviceID int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteSourceRepository", ctx, serviceID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteSourceRepository indicates an expected call of DeleteSourceRepository.
+func (mr *MockServiceServiceInterfaceMockRecorder) DeleteSourceRepository(ctx, serviceID any) *MockServiceServiceInterfaceDeleteSourceRepositoryCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSourceRepository", reflect.TypeOf((*MockServiceServiceInterface)(nil).DeleteSourceRepository), ctx, serviceID)
+	return &MockServiceServiceInterfaceDeleteSourceRepositoryCall{Call: call}
+}
+
+// MockServiceServiceInterfaceDeleteSourceRepositoryCall wrap *gomock.Call
+type MockServiceServiceInterfaceDeleteSourceRepositoryCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockServiceServiceInterfaceDeleteSourceRepositoryCall) Return(arg0 error) *MockServiceServiceInterfaceDeleteSourceRepositoryCall {
+	c.Call = c.Call.Return(arg0)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockServiceServiceInterfaceDeleteSourceRepositoryCall) Do(f func(context.Context, int) error) *MockServiceServiceInterfaceDeleteSourceRepositoryCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockServiceServiceInterfaceDeleteSourceRepositoryCall) DoAndReturn(f func(context.Context, int) error) *MockServiceServiceInterfaceDeleteSourceRepositoryCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// DeleteSunset mocks base method.
+func (m *MockServiceServiceInterface) DeleteSunset(ctx context.Context, serviceID, versionID int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteSunset", ctx, serviceID, versionID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteSunset indicates an expected call of DeleteSunset.
+func (mr *MockServiceServiceInterfaceMockRecorder) DeleteSunset(ctx, serviceID, versionID any) *MockServiceServiceInterfaceDeleteSunsetCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSunset", reflect.TypeOf((*MockServiceServiceInterface)(nil).DeleteSunset), ctx, serviceID, versionID)
+	return &MockServiceServiceInterfaceDeleteSunsetCall{Call: call}
+}
+
+// MockServiceServiceInterfaceDeleteSunsetCall wrap *gomock.Call
+type MockServiceServiceInterfaceDeleteSunsetCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockServiceServiceInterfaceDeleteSunsetCall) Return(arg0 error) *MockServiceServiceInterfaceDeleteSunsetCall {
+	c.Call = c.Call.Return(arg0)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockServiceServiceInterfaceDeleteSunsetCall) Do(f func(context.Context, int, int) error) *MockServiceServiceInterfaceDeleteSunsetCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockServiceServiceInterfaceDeleteSunsetCall) DoAndReturn(f func(context.Context, int, int) error) *MockServiceServiceInterfaceDeleteSunsetCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// DeleteTranslation mocks base method.
+func (m *MockServiceServiceInterface) DeleteTranslation(ctx context.Context, serviceID int, language string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteTranslation", ctx, serviceID, language)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteTranslation indicates an expected call of DeleteTranslation.
+func (mr *MockServiceServiceInterfaceMockRecorder) DeleteTranslation(ctx, serviceID, language any) *MockServiceServiceInterfaceDeleteTranslationCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTranslation", reflect.TypeOf((*MockServiceServiceInterface)(nil).DeleteTranslation), ctx, serviceID, language)
+	return &MockServiceServiceInterfaceDeleteTranslationCall{Call: call}
+}
+
+// MockServiceServiceInterfaceDeleteTranslationCall wrap *gomock.Call
+type MockServiceServiceInterfaceDeleteTranslationCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockServiceServiceInterfaceDeleteTranslationCall) Return(arg0 error) *MockServiceServiceInterfaceDeleteTranslationCall {
+	c.Call = c.Call.Return(arg0)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockServiceServiceInterfaceDeleteTranslationCall) Do(f func(context.Context, int, string) error) *MockServiceServiceInterfaceDeleteTranslationCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockServiceServiceInterfaceDeleteTranslationCall) DoAndReturn(f func(context.Context, int, string) error) *MockServiceServiceInterfaceDeleteTranslationCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// DeleteVersion mocks base method.
+func (m *MockServiceServiceInterface) DeleteVersion(ctx context.Context, serviceID, versionID int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteVersion", ctx, serviceID, versionID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteVersion indicates an expected call of DeleteVersion.
+func (mr *MockServiceServiceInterfaceMockRecorder) DeleteVersion(ctx, serviceID, versionID any) *MockServiceServiceInterfaceDeleteVersionCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteVersion", reflect.TypeOf((*MockServiceServiceInterface)(nil).DeleteVersion), ctx, serviceID, versionID)
+	return &MockServiceServiceInterfaceDeleteVersionCall{Call: call}
+}
+
+// MockServiceServiceInterfaceDeleteVersionCall wrap *gomock.Call
+type MockServiceServiceInterfaceDeleteVersionCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockServiceServiceInterfaceDeleteVersionCall) Return(arg0 error) *MockServiceServiceInterfaceDeleteVersionCall {
+	c.Call = c.Call.Return(arg0)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockServiceServiceInterfaceDeleteVersionCall) Do(f func(context.Context, int, int) error) *MockServiceServiceInterfaceDeleteVersionCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockServiceServiceInterfaceDeleteVersionCall) DoAndReturn(f func(context.Context, int, int) error) *MockServiceServiceInterfaceDeleteVersionCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// DeleteVersionSpec mocks base method.
+func (m *MockServiceServiceInterface) DeleteVersionSpec(ctx context.Context, serviceID, versionID int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteVersionSpec", ctx, serviceID, versionID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteVersionSpec indicates an expected call of DeleteVersionSpec.
+func (mr *MockServiceServiceInterfaceMockRecorder) DeleteVersionSpec(ctx, serviceID, versionID any) *MockServiceServiceInterfaceDeleteVersionSpecCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteVersionSpec", reflect.TypeOf((*MockServiceServiceInterface)(nil).DeleteVersionSpec), ctx, serviceID, versionID)
+	return &MockServiceServiceInterfaceDeleteVersionSpecCall{Call: call}
+}
+
+// MockServiceServiceInterfaceDeleteVersionSpecCall wrap *gomock.Call
+type MockServiceServiceInterfaceDeleteVersionSpecCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockServiceServiceInterfaceDeleteVersionSpecCall) Return(arg0 error) *MockServiceServiceInterfaceDeleteVersionSpecCall {
+	c.Call = c.Call.Return(arg0)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockServiceServiceInterfaceDeleteVersionSpecCall) Do(f func(context.Context, int, int) error) *MockServiceServiceInterfaceDeleteVersionSpecCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockServiceServiceInterfaceDeleteVersionSpecCall) DoAndReturn(f func(context.Context, int, int) error) *MockServiceServiceInterfaceDeleteVersionSpecCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// DeleteWebhook mocks base method.
+func (m *MockServiceServiceInterface) DeleteWebhook(ctx context.Context, id int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWebhook", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteWebhook indicates an expected call of DeleteWebhook.
+func (mr *MockServiceServiceInterfaceMockRecorder) DeleteWebhook(ctx, id any) *MockServiceServiceInterfaceDeleteWebhookCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWebhook", reflect.TypeOf((*MockServiceServiceInterface)(nil).DeleteWebhook), ctx, id)
+	return &MockServiceServiceInterfaceDeleteWebhookCall{Call: call}
+}
+
+// MockServiceServiceInterfaceDeleteWebhookCall wrap *gomock.Call
+type MockServiceServiceInterfaceDeleteWebhookCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockServiceServiceInterfaceDeleteWebhookCall) Return(arg0 error) *MockServiceServiceInterfaceDeleteWebhookCall {
+	c.Call = c.Call.Return(arg0)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockServiceServiceInterfaceDeleteWebhookCall) Do(f func(context.Context, int) error) *MockServiceServiceInterfaceDeleteWebhookCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockServiceServiceInterfaceDeleteWebhookCall) DoAndReturn(f func(context.Context, int) error) *MockServiceServiceInterfaceDeleteWebhookCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// DeployVersion mocks base method.
+func (m *MockServiceServiceInterface) DeployVersion(ctx context.Context, serviceID int, environment string, input domain.DeploymentInput) (*domain.ServiceVersion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeployVersion", ctx, serviceID, environment, input)
+	ret0, _ := ret[0].(*domain.ServiceVersion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeployVersion indicates an expected call of DeployVersion.
+func (mr *MockServiceServiceInterfaceMockRecorder) DeployVersion(ctx, serviceID, environment, input any) *MockServiceServiceInterfaceDeployVersionCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeployVersion", reflect.TypeOf((*MockServiceServiceInterface)(nil).DeployVersion), ctx, serviceID, environment, input)
+	return &MockServiceServiceInterfaceDeployVersionCall{Call: call}
+}
+
+// MockServiceServiceInterfaceDeployVersionCall wrap *gomock.Call
+type MockServiceServiceInterfaceDeployVersionCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockServiceServiceInterfaceDeployVersionCall) Return(arg0 *domain.ServiceVersion, arg1 error) *MockServiceServiceInterfaceDeployVersionCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockServiceServiceInterfaceDeployVersionCall) Do(f func(context.Context, int, string, domain.DeploymentInput) (*domain.ServiceVersion, error)) *MockServiceServiceInterfaceDeployVersionCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockServiceServiceInterfaceDeployVersionCall) DoAndReturn(f func(context.Context, int, string, domain.DeploymentInput) (*domain.ServiceVersion, error)) *MockServiceServiceInterfaceDeployVersionCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// DisableUser mocks base method.
+func (m *MockServiceServiceInterface) DisableUser(ctx context.Context, username string) (*domain.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DisableUser", ctx, username)
+	ret0, _ := ret[0].(*domain.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DisableUser indicates an expected call of DisableUser.
+func (mr *MockServiceServiceInterfaceMockRecorder) DisableUser(ctx, username any) *MockServiceServiceInterfaceDisableUserCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DisableUser", reflect.TypeOf((*MockServiceServiceInterface)(nil).DisableUser), ctx, username)
+	return &MockServiceServiceInterfaceDisableUserCall{Call: call}
+}
+
+// MockServiceServiceInterfaceDisableUserCall wrap *gomock.Call
+type MockServiceServiceInterfaceDisableUserCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockServiceServiceInterfaceDisableUserCall) Return(arg0 *domain.User, arg1 error) *MockServiceServiceInterfaceDisableUserCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockServiceServiceInterfaceDisableUserCall) Do(f func(context.Context, string) (*domain.User, error)) *MockServiceServiceInterfaceDisableUserCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockServiceServiceInterfaceDisableUserCall) DoAndReturn(f func(context.Context, string) (*domain.User, error)) *MockServiceServiceInterfaceDisableUserCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// FavoriteService mocks base method.
+func (m *MockServiceServiceInterface) FavoriteService(ctx context.Context, id int, username string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FavoriteService", ctx, id, username)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// FavoriteService indicates an expected call of FavoriteService.
+func (mr *MockServiceServiceInterfaceMockRecorder) FavoriteService(ctx, id, username any) *MockServiceServiceInterfaceFavoriteServiceCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FavoriteService", reflect.TypeOf((*MockServiceServiceInterface)(nil).FavoriteService), ctx, id, username)
+	return &MockServiceServiceInterfaceFavoriteServiceCall{Call: call}
+}
+
+// MockServiceServiceInterfaceFavoriteServiceCall wrap *gomock.Call
+type MockServiceServiceInterfaceFavoriteServiceCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockServiceServiceInterfaceFavoriteServiceCall) Return(arg0 error) *MockServiceServiceInterfaceFavoriteServiceCall {
+	c.Call = c.Call.Return(arg0)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockServiceServiceInterfaceFavoriteServiceCall) Do(f func(context.Context, int, string) error) *MockServiceServiceInterfaceFavoriteServiceCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockServiceServiceInterfaceFavoriteServiceCall) DoAndReturn(f func(context.Context, int, string) error) *MockServiceServiceInterfaceFavoriteServiceCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// GetAnalytics mocks base method.
+func (m *MockServiceServiceInterface) GetAnalytics(ctx context.Context, query domain.AnalyticsQuery) (*domain.AnalyticsReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAnalytics", ctx, query)
+	ret0, _ := ret[0].(*domain.AnalyticsReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAnalytics indicates an expected call of GetAnalytics.
+func (mr *MockServiceServiceInterfaceMockRecorder) GetAnalytics(ctx, query any) *MockServiceServiceInterfaceGetAnalyticsCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAnalytics", reflect.TypeOf((*MockServiceServiceInterface)(nil).GetAnalytics), ctx, query)
+	return &MockServiceServiceInterfaceGetAnalyticsCall{Call: call}
+}
+
+// MockServiceServiceInterfaceGetAnalyticsCall wrap *gomock.Call
+type MockServiceServiceInterfaceGetAnalyticsCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockServiceServiceInterfaceGetAnalyticsCall) Return(arg0 *domain.AnalyticsReport, arg1 error) *MockServiceServiceInterfaceGetAnalyticsCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockServiceServiceInterfaceGetAnalyticsCall) Do(f func(context.Context, domain.AnalyticsQuery) (*domain.AnalyticsReport, error)) *MockServiceServiceInterfaceGetAnalyticsCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockServiceServiceInterfaceGetAnalyticsCall) DoAndReturn(f func(context.Context, domain.AnalyticsQuery) (*domain.AnalyticsReport, error)) *MockServiceServiceInterfaceGetAnalyticsCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// GetAuditLogs mocks base method.
+func (m *MockServiceServiceInterface) GetAuditLogs(ctx context.Context, query domain.AuditQuery) (*domain.AuditListResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAuditLogs", ctx, query)
+	ret0, _ := ret[0].(*domain.AuditListResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAuditLogs indicates an expected call of GetAuditLogs.
+func (mr *MockServiceServiceInterfaceMockRecorder) GetAuditLogs(ctx, query any) *MockServiceServiceInterfaceGetAuditLogsCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAuditLogs", reflect.TypeOf((*MockServiceServiceInterface)(nil).GetAuditLogs), ctx, query)
+	return &MockServiceServiceInterfaceGetAuditLogsCall{Call: call}
+}
+
+// MockServiceServiceInterfaceGetAuditLogsCall wrap *gomock.Call
+type MockServiceServiceInterfaceGetAuditLogsCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockServiceServiceInterfaceGetAuditLogsCall) Return(arg0 *domain.AuditListResponse, arg1 error) *MockServiceServiceInterfaceGetAuditLogsCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockServiceServiceInterfaceGetAuditLogsCall) Do(f func(context.Context, domain.AuditQuery) (*domain.AuditListResponse, error)) *MockServiceServiceInterfaceGetAuditLogsCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockServiceServiceInterfaceGetAuditLogsCall) DoAndReturn(f func(context.Context, domain.AuditQuery) (*domain.AuditListResponse, error)) *MockServiceServiceInterfaceGetAuditLogsCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// GetComments mocks base method.
+func (m *MockServiceServiceInterface) GetComments(ctx context.Context, query domain.CommentQuery) (*domain.CommentListResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetComments", ctx, query)
+	ret0, _ := ret[0].(*domain.CommentListResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetComments indicates an expected call of GetComments.
+func (mr *MockServiceServiceInterfaceMockRecorder) GetComments(ctx, query any) *MockServiceServiceInterfaceGetCommentsCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetComments", reflect.TypeOf((*MockServiceServiceInterface)(nil).GetComments), ctx, query)
+	return &MockServiceServiceInterfaceGetCommentsCall{Call: call}
+}
+
+// MockServiceServiceInterfaceGetCommentsCall wrap *gomock.Call
+type MockServiceServiceInterfaceGetCommentsCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockServiceServiceInterfaceGetCommentsCall) Return(arg0 *domain.CommentListResponse, arg1 error) *MockServiceServiceInterfaceGetCommentsCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockServiceServiceInterfaceGetCommentsCall) Do(f func(context.Context, domain.CommentQuery) (*domain.CommentListResponse, error)) *MockServiceServiceInterfaceGetCommentsCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockServiceServiceInterfaceGetCommentsCall) DoAndReturn(f func(context.Context, domain.CommentQuery) (*domain.CommentListResponse, error)) *MockServiceServiceInterfaceGetCommentsCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// GetHealthHistory mocks base method.
+func (m *MockServiceServiceInterface) GetHealthHistory(ctx context.Context, query domain.HealthHistoryQuery) (*domain.HealthHistoryResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetHealthHistory", ctx, query)
+	ret0, _ := ret[0].(*domain.HealthHistoryResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetHealthHistory indicates an expected call of GetHealthHistory.
+func (mr *MockServiceServiceInterfaceMockRecorder) GetHealthHistory(ctx, query any) *MockServiceServiceInterfaceGetHealthHistoryCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHealthHistory", reflect.TypeOf((*MockServiceServiceInterface)(nil).GetHealthHistory), ctx, query)
+	return &MockServiceServiceInterfaceGetHealthHistoryCall{Call: call}
+}
+
+// MockServiceServiceInterfaceGetHealthHistoryCall wrap *gomock.Call
+type MockServiceServiceInterfaceGetHealthHistoryCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockServiceServiceInterfaceGetHealthHistoryCall) Return(arg0 *domain.HealthHistoryResponse, arg1 error) *MockServiceServiceInterfaceGetHealthHistoryCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockServiceServiceInterfaceGetHealthHistoryCall) Do(f func(context.Context, domain.HealthHistoryQuery) (*domain.HealthHistoryResponse, error)) *MockServiceServiceInterfaceGetHealthHistoryCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockServiceServiceInterfaceGetHealthHistoryCall) DoAndReturn(f func(context.Context, domain.HealthHistoryQuery) (*domain.HealthHistoryResponse, error)) *MockServiceServiceInterfaceGetHealthHistoryCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// GetNotificationPreferences mocks base method.
+func (m *MockServiceServiceInterface) GetNotificationPreferences(ctx context.Context, username string) (*domain.NotificationPreferences, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetNotificationPreferences", ctx, username)
+	ret0, _ := ret[0].(*domain.NotificationPreferences)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetNotificationPreferences indicates an expected call of GetNotificationPreferences.
+func (mr *MockServiceServiceInterfaceMockRecorder) GetNotificationPreferences(ctx, username any) *MockServiceServiceInterfaceGetNotificationPreferencesCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNotificationPreferences", reflect.TypeOf((*MockServiceServiceInterface)(nil).GetNotificationPreferences), ctx, username)
+	return &MockServiceServiceInterfaceGetNotificationPreferencesCall{Call: call}
+}
+
+// MockServiceServiceInterfaceGetNotificationPreferencesCall wrap *gomock.Call
+type MockServiceServiceInterfaceGetNotificationPreferencesCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockServiceServiceInterfaceGetNotificationPreferencesCall) Return(arg0 *domain.NotificationPreferences, arg1 error) *MockServiceServiceInterfaceGetNotificationPreferencesCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockServiceServiceInterfaceGetNotificationPreferencesCall) Do(f func(context.Context, string) (*domain.NotificationPreferences, error)) *MockServiceServiceInterfaceGetNotificationPreferencesCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockServiceServiceInterfaceGetNotificationPreferencesCall) DoAndReturn(f func(context.Context, string) (*domain.NotificationPreferences, error)) *MockServiceServiceInterfaceGetNotificationPreferencesCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// GetOrganizationBySlug mocks base method.
+func (m *MockServiceServiceInterface) GetOrganizationBySlug(ctx context.Context, slug string) (*domain.Organization, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOrganizationBySlug", ctx, slug)
+	ret0, _ := ret[0].(*domain.Organization)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOrganizationBySlug indicates an expected call of GetOrganizationBySlug.
+func (mr *MockServiceServiceInterfaceMockRecorder) GetOrganizationBySlug(ctx, slug any) *MockServiceServiceInterfaceGetOrganizationBySlugCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrganizationBySlug", reflect.TypeOf((*MockServiceServiceInterface)(nil).GetOrganizationBySlug), ctx, slug)
+	return &MockServiceServiceInterfaceGetOrganizationBySlugCall{Call: call}
+}
+
+// MockServiceServiceInterfaceGetOrganizationBySlugCall wrap *gomock.Call
+type MockServiceServiceInterfaceGetOrganizationBySlugCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockServiceServiceInterfaceGetOrganizationBySlugCall) Return(arg0 *domain.Organization, arg1 error) *MockServiceServiceInterfaceGetOrganizationBySlugCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockServiceServiceInterfaceGetOrganizationBySlugCall) Do(f func(context.Context, string) (*domain.Organization, error)) *MockServiceServiceInterfaceGetOrganizationBySlugCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockServiceServiceInterfaceGetOrganizationBySlugCall) DoAndReturn(f func(context.Context, string) (*domain.Organization, error)) *MockServiceServiceInterfaceGetOrganizationBySlugCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// GetSLOReport mocks base method.
+func (m *MockServiceServiceInterface) GetSLOReport(ctx context.Context, query domain.SLOReportQuery) (*domain.SLOReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSLOReport", ctx, query)
+	ret0, _ := ret[0].(*domain.SLOReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSLOReport indicates an expected call of GetSLOReport.
+func (mr *MockServiceServiceInterfaceMockRecorder) GetSLOReport(ctx, query any) *MockServiceServiceInterfaceGetSLOReportCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSLOReport", reflect.TypeOf((*MockServiceServiceInterface)(nil).GetSLOReport), ctx, query)
+	return &MockServiceServiceInterfaceGetSLOReportCall{Call: call}
+}
+
+// MockServiceServiceInterfaceGetSLOReportCall wrap *gomock.Call
+type MockServiceServiceInterfaceGetSLOReportCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockServiceServiceInterfaceGetSLOReportCall) Return(arg0 *domain.SLOReport, arg1 error) *MockServiceServiceInterfaceGetSLOReportCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockServiceServiceInterfaceGetSLOReportCall) Do(f func(context.Context, domain.SLOReportQuery) (*domain.SLOReport, error)) *MockServiceServiceInterfaceGetSLOReportCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockServiceServiceInterfaceGetSLOReportCall) DoAndReturn(f func(context.Context, domain.SLOReportQuery) (*domain.SLOReport, error)) *MockServiceServiceInterfaceGetSLOReportCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// GetServiceByID mocks base method.
+func (m *MockServiceServiceInterface) GetServiceByID(ctx context.Context, id int) (*domain.ServiceWithVersions, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetServiceByID", ctx, id)
+	ret0, _ := ret[0].(*domain.ServiceWithVersions)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetServiceByID indicates an expected call of GetServiceByID.
+func (mr *MockServiceServiceInterfaceMockRecorder) GetServiceByID(ctx, id any) *MockServiceServiceInterfaceGetServiceByIDCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetServiceByID", reflect.TypeOf((*MockServiceServiceInterface)(nil).GetServiceByID), ctx, id)
+	return &MockServiceServiceInterfaceGetServiceByIDCall{Call: call}
+}
+
+// MockServiceServiceInterfaceGetServiceByIDCall wrap *gomock.Call
+type MockServiceServiceInterfaceGetServiceByIDCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockServiceServiceInterfaceGetServiceByIDCall) Return(arg0 *domain.ServiceWithVersions, arg1 error) *MockServiceServiceInterfaceGetServiceByIDCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockServiceServiceInterfaceGetServiceByIDCall) Do(f func(context.Context, int) (*domain.ServiceWithVersions, error)) *MockServiceServiceInterfaceGetServiceByIDCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockServiceServiceInterfaceGetServiceByIDCall) DoAndReturn(f func(context.Context, int) (*domain.ServiceWithVersions, error)) *MockServiceServiceInterfaceGetServiceByIDCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// GetServiceVersions mocks base method.
+func (m *MockServiceServiceInterface) GetServiceVersions(ctx context.Context, query domain.VersionQuery) (*domain.VersionListResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetServiceVersions", ctx, query)
+	ret0, _ := ret[0].(*domain.VersionListResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetServiceVersions indicates an expected call of GetServiceVersions.
+func (mr *MockServiceServiceInterfaceMockRecorder) GetServiceVersions(ctx, query any) *MockServiceServiceInterfaceGetServiceVersionsCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetServiceVersions", reflect.TypeOf((*MockServiceServiceInterface)(nil).GetServiceVersions), ctx, query)
+	return &MockServiceServiceInterfaceGetServiceVersionsCall{Call: call}
+}
+
+// MockServiceServiceInterfaceGetServiceVersionsCall wrap *gomock.Call
+type MockServiceServiceInterfaceGetServiceVersionsCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockServiceServiceInterfaceGetServiceVersionsCall) Return(arg0 *domain.VersionListResponse, arg1 error) *MockServiceServiceInterfaceGetServiceVersionsCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockServiceServiceInterfaceGetServiceVersionsCall) Do(f func(context.Context, domain.VersionQuery) (*domain.VersionListResponse, error)) *MockServiceServiceInterfaceGetServiceVersionsCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockServiceServiceInterfaceGetServiceVersionsCall) DoAndReturn(f func(context.Context, domain.VersionQuery) (*domain.VersionListResponse, error)) *MockServiceServiceInterfaceGetServiceVersionsCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// GetServices mocks base method.
+func (m *MockServiceServiceInterface) GetServices(ctx context.Context, query domain.ServiceQuery) (*domain.ServiceListResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetServices", ctx, query)
+	ret0, _ := ret[0].(*domain.ServiceListResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetServices indicates an expected call of GetServices.
+func (mr *MockServiceServiceInterfaceMockRecorder) GetServices(ctx, query any) *MockServiceServiceInterfaceGetServicesCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetServices", reflect.TypeOf((*MockServiceServiceInterface)(nil).GetServices), ctx, query)
+	return &MockServiceServiceInterfaceGetServicesCall{Call: call}
+}
+
+// MockServiceServiceInterfaceGetServicesCall wrap *gomock.Call
+type MockServiceServiceInterfaceGetServicesCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockServiceServiceInterfaceGetServicesCall) Return(arg0 *domain.ServiceListResponse, arg1 error) *MockServiceServiceInterfaceGetServicesCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockServiceServiceInterfaceGetServicesCall) Do(f func(context.Context, domain.ServiceQuery) (*domain.ServiceListResponse, error)) *MockServiceServiceInterfaceGetServicesCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockServiceServiceInterfaceGetServicesCall) DoAndReturn(f func(context.Context, domain.ServiceQuery) (*domain.ServiceListResponse, error)) *MockServiceServiceInterfaceGetServicesCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// GetStats mocks base method.
+func (m *MockServiceServiceInterface) GetStats(ctx context.Context, recentLimit int) (*domain.CatalogStats, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStats", ctx, recentLimit)
+	ret0, _ := ret[0].(*domain.CatalogStats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetStats indicates an expected call of GetStats.
+func (mr *MockServiceServiceInterfaceMockRecorder) GetStats(ctx, recentLimit any) *MockServiceServiceInterfaceGetStatsCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStats", reflect.TypeOf((*MockServiceServiceInterface)(nil).GetStats), ctx, recentLimit)
+	return &MockServiceServiceInterfaceGetStatsCall{Call: call}
+}
+
+// MockServiceServiceInterfaceGetStatsCall wrap *gomock.Call
+type MockServiceServiceInterfaceGetStatsCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockServiceServiceInterfaceGetStatsCall) Return(arg0 *domain.CatalogStats, arg1 error) *MockServiceServiceInterfaceGetStatsCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockServiceServiceInterfaceGetStatsCall) Do(f func(context.Context, int) (*domain.CatalogStats, error)) *MockServiceServiceInterfaceGetStatsCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockServiceServiceInterfaceGetStatsCall) DoAndReturn(f func(context.Context, int) (*domain.CatalogStats, error)) *MockServiceServiceInterfaceGetStatsCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// GetTransfers mocks base method.
+func (m *MockServiceServiceInterface) GetTransfers(ctx context.Context, serviceID int) (*domain.TransferListResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTransfers", ctx, serviceID)
+	ret0, _ := ret[0].(*domain.TransferListResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTransfers indicates an expected call of GetTransfers.
+func (mr *MockServiceServiceInterfaceMockRecorder) GetTransfers(ctx, serviceID any) *MockServiceServiceInterfaceGetTransfersCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransfers", reflect.TypeOf((*MockServiceServiceInterface)(nil).GetTransfers), ctx, serviceID)
+	return &MockServiceServiceInterfaceGetTransfersCall{Call: call}
+}
+
+// MockServiceServiceInterfaceGetTransfersCall wrap *gomock.Call
+type MockServiceServiceInterfaceGetTransfersCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockServiceServiceInterfaceGetTransfersCall) Return(arg0 *domain.TransferListResponse, arg1 error) *MockServiceServiceInterfaceGetTransfersCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockServiceServiceInterfaceGetTransfersCall) Do(f func(context.Context, int) (*domain.TransferListResponse, error)) *MockServiceServiceInterfaceGetTransfersCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockServiceServiceInterfaceGetTransfersCall) DoAndReturn(f func(context.Context, int) (*domain.TransferListResponse, error)) *MockServiceServiceInterfaceGetTransfersCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// GetVersionDocs mocks base method.
+func (m *MockServiceServiceInterface) GetVersionDocs(ctx context.Context, serviceID, versionID int) (*openapi.DocsPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetVersionDocs", ctx, serviceID, versionID)
+	ret0, _ := ret[0].(*openapi.DocsPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetVersionDocs indicates an expected call of GetVersionDocs.
+func (mr *MockServiceServiceInterfaceMockRecorder) GetVersionDocs(ctx, serviceID, versionID any) *MockServiceServiceInterfaceGetVersionDocsCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVersionDocs", reflect.TypeOf((*MockServiceServiceInterface)(nil).GetVersionDocs), ctx, serviceID, versionID)
+	return &MockServiceServiceInterfaceGetVersionDocsCall{Call: call}
+}
+
+// MockServiceServiceInterfaceGetVersionDocsCall wrap *gomock.Call
+type MockServiceServiceInterfaceGetVersionDocsCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockServiceServiceInterfaceGetVersionDocsCall) Return(arg0 *openapi.DocsPage, arg1 error) *MockServiceServiceInterfaceGetVersionDocsCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockServiceServiceInterfaceGetVersionDocsCall) Do(f func(context.Context, int, int) (*openapi.DocsPage, error)) *MockServiceServiceInterfaceGetVersionDocsCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockServiceServiceInterfaceGetVersionDocsCall) DoAndReturn(f func(context.Context, int, int) (*openapi.DocsPage, error)) *MockServiceServiceInterfaceGetVersionDocsCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// GetVersionPostman mocks base method.
+func (m *MockServiceServiceInterface) GetVersionPostman(ctx context.Context, serviceID, versionID int) (*openapi.PostmanCollection, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetVersionPostman", ctx, serviceID, versionID)
+	ret0, _ := ret[0].(*openapi.PostmanCollection)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetVersionPostman indicates an expected call of GetVersionPostman.
+func (mr *MockServiceServiceInterfaceMockRecorder) GetVersionPostman(ctx, serviceID, versionID any) *MockServiceServiceInterfaceGetVersionPostmanCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVersionPostman", reflect.TypeOf((*MockServiceServiceInterface)(nil).GetVersionPostman), ctx, serviceID, versionID)
+	return &MockServiceServiceInterfaceGetVersionPostmanCall{Call: call}
+}
+
+// MockServiceServiceInterfaceGetVersionPostmanCall wrap *gomock.Call
+type MockServiceServiceInterfaceGetVersionPostmanCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockServiceServiceInterfaceGetVersionPostmanCall) Return(arg0 *openapi.PostmanCollection, arg1 error) *MockServiceServiceInterfaceGetVersionPostmanCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockServiceServiceInterfaceGetVersionPostmanCall) Do(f func(context.Context, int, int) (*openapi.PostmanCollection, error)) *MockServiceServiceInterfaceGetVersionPostmanCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockServiceServiceInterfaceGetVersionPostmanCall) DoAndReturn(f func(context.Context, int, int) (*openapi.PostmanCollection, error)) *MockServiceServiceInterfaceGetVersionPostmanCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// GetVersionSpec mocks base method.
+func (m *MockServiceServiceInterface) GetVersionSpec(ctx context.Context, serviceID, versionID int) (*domain.VersionSpec, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetVersionSpec", ctx, serviceID, versionID)
+	ret0, _ := ret[0].(*domain.VersionSpec)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetVersionSpec indicates an expected call of GetVersionSpec.
+func (mr *MockServiceServiceInterfaceMockRecorder) GetVersionSpec(ctx, serviceID, versionID any) *MockServiceServiceInterfaceGetVersionSpecCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVersionSpec", reflect.TypeOf((*MockServiceServiceInterface)(nil).GetVersionSpec), ctx, serviceID, versionID)
+	return &MockServiceServiceInterfaceGetVersionSpecCall{Call: call}
+}
+
+// MockServiceServiceInterfaceGetVersionSpecCall wrap *gomock.Call
+type MockServiceServiceInterfaceGetVersionSpecCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockServiceServiceInterfaceGetVersionSpecCall) Return(arg0 *domain.VersionSpec, arg1 error) *MockServiceServiceInterfaceGetVersionSpecCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockServiceServiceInterfaceGetVersionSpecCall) Do(f func(context.Context, int, int) (*domain.VersionSpec, error)) *MockServiceServiceInterfaceGetVersionSpecCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockServiceServiceInterfaceGetVersionSpecCall) DoAndReturn(f func(context.Context, int, int) (*domain.VersionSpec, error)) *MockServiceServiceInterfaceGetVersionSpecCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// HealthChecks mocks base method.
+func (m *MockServiceServiceInterface) HealthChecks(ctx context.Context) ([]domain.HealthCheck, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HealthChecks", ctx)
+	ret0, _ := ret[0].([]domain.HealthCheck)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// HealthChecks indicates an expected call of HealthChecks.
+func (mr *MockServiceServiceInterfaceMockRecorder) HealthChecks(ctx any) *MockServiceServiceInterfaceHealthChecksCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HealthChecks", reflect.TypeOf((*MockServiceServiceInterface)(nil).HealthChecks), ctx)
+	return &MockServiceServiceInterfaceHealthChecksCall{Call: call}
+}
+
+// MockServiceServiceInterfaceHealthChecksCall wrap *gomock.Call
+type MockServiceServiceInterfaceHealthChecksCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockServiceServiceInterfaceHealthChecksCall) Return(arg0 []domain.HealthCheck, arg1 error) *MockServiceServiceInterfaceHealthChecksCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockServiceServiceInterfaceHealthChecksCall) Do(f func(context.Context) ([]domain.HealthCheck, error)) *MockServiceServiceInterfaceHealthChecksCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockServiceServiceInterfaceHealthChecksCall) DoAndReturn(f func(context.Context) ([]domain.HealthCheck, error)) *MockServiceServiceInterfaceHealthChecksCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// ImportDefinition mocks base method.
+func (m *MockServiceServiceInterface) ImportDefinition(ctx context.Context, content []byte, overrides domain.DefinitionImport) (*domain.ServiceWithVersions, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ImportDefinition", ctx, content, overrides)
+	ret0, _ := ret[0].(*domain.ServiceWithVersions)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ImportDefinition indicates an expected call of ImportDefinition.
+func (mr *MockServiceServiceInterfaceMockRecorder) ImportDefinition(ctx, content, overrides any) *MockServiceServiceInterfaceImportDefinitionCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportDefinition", reflect.TypeOf((*MockServiceServiceInterface)(nil).ImportDefinition), ctx, content, overrides)
+	return &MockServiceServiceInterfaceImportDefinitionCall{Call: call}
+}
+
+// MockServiceServiceInterfaceImportDefinitionCall wrap *gomock.Call
+type MockServiceServiceInterfaceImportDefinitionCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockServiceServiceInterfaceImportDefinitionCall) Return(arg0 *domain.ServiceWithVersions, arg1 error) *MockServiceServiceInterfaceImportDefinitionCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockServiceServiceInterfaceImportDefinitionCall) Do(f func(context.Context, []byte, domain.DefinitionImport) (*domain.ServiceWithVersions, error)) *MockServiceServiceInterfaceImportDefinitionCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockServiceServiceInterfaceImportDefinitionCall) DoAndReturn(f func(context.Context, []byte, domain.DefinitionImport) (*domain.ServiceWithVersions, error)) *MockServiceServiceInterfaceImportDefinitionCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// ImportServices mocks base method.
+func (m *MockServiceServiceInterface) ImportServices(ctx context.Context, source string, services []domain.CatalogService, policy string) (*domain.ImportResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ImportServices", ctx, source, services, policy)
+	ret0, _ := ret[0].(*domain.ImportResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ImportServices indicates an expected call of ImportServices.
+func (mr *MockServiceServiceInterfaceMockRecorder) ImportServices(ctx, source, services, policy any) *MockServiceServiceInterfaceImportServicesCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportServices", reflect.TypeOf((*MockServiceServiceInterface)(nil).ImportServices), ctx, source, services, policy)
+	return &MockServiceServiceInterfaceImportServicesCall{Call: call}
+}
+
+// MockServiceServiceInterfaceImportServicesCall wrap *gomock.Call
+type MockServiceServiceInterfaceImportServicesCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockServiceServiceInterfaceImportServicesCall) Return(arg0 *domain.ImportResult, arg1 error) *MockServiceServiceInterfaceImportServicesCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockServiceServiceInterfaceImportServicesCall) Do(f func(context.Context, string, []domain.CatalogService, string) (*domain.ImportResult, error)) *MockServiceServiceInterfaceImportServicesCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockServiceServiceInterfaceImportServicesCall) DoAndReturn(f func(context.Context, string, []domain.CatalogService, string) (*domain.ImportResult, error)) *MockServiceServiceInterfaceImportServicesCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// IssueToken mocks base method.
+func (m *MockServiceServiceInterface) IssueToken(ctx context.Context, input domain.TokenInput) (*domain.IssuedToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IssueToken", ctx, input)
+	ret0, _ := ret[0].(*domain.IssuedToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IssueToken indicates an expected call of IssueToken.
+func (mr *MockServiceServiceInterfaceMockRecorder) IssueToken(ctx, input any) *MockServiceServiceInterfaceIssueTokenCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IssueToken", reflect.TypeOf((*MockServiceServiceInterface)(nil).IssueToken), ctx, input)
+	return &MockServiceServiceInterfaceIssueTokenCall{Call: call}
+}
+
+// MockServiceServiceInterfaceIssueTokenCall wrap *gomock.Call
+type MockServiceServiceInterfaceIssueTokenCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockServiceServiceInterfaceIssueTokenCall) Return(arg0 *domain.IssuedToken, arg1 error) *MockServiceServiceInterfaceIssueTokenCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockServiceServiceInterfaceIssueTokenCall) Do(f func(context.Context, domain.TokenInput) (*domain.IssuedToken, error)) *MockServiceServiceInterfaceIssueTokenCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockServiceServiceInterfaceIssueTokenCall) DoAndReturn(f func(context.Context, domain.TokenInput) (*domain.IssuedToken, error)) *MockServiceServiceInterfaceIssueTokenCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// LinkedRepositories mocks base method.
+func (m *MockServiceServiceInterface) LinkedRepositories(ctx context.Context) ([]domain.LinkedRepository, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LinkedRepositories", ctx)
+	ret0, _ := ret[0].([]domain.LinkedRepository)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LinkedRepositories indicates an expected call of LinkedRepositories.
+func (mr *MockServiceServiceInterfaceMockRecorder) LinkedRepositories(ctx any) *MockServiceServiceInterfaceLinkedRepositoriesCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LinkedRepositories", reflect.TypeOf((*MockServiceServiceInterface)(nil).LinkedRepositories), ctx)
+	return &MockServiceServiceInterfaceLinkedRepositoriesCall{Call: call}
+}
+
+// MockServiceServiceInterfaceLinkedRepositoriesCall wrap *gomock.Call
+type MockServiceServiceInterfaceLinkedRepositoriesCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockServiceServiceInterfaceLinkedRepositoriesCall) Return(arg0 []domain.LinkedRepository, arg1 error) *MockServiceServiceInterfaceLinkedRepositoriesCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockServiceServiceInterfaceLinkedRepositoriesCall) Do(f func(context.Context) ([]domain.LinkedRepository, error)) *MockServiceServiceInterfaceLinkedRepositoriesCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockServiceServiceInterfaceLinkedRepositoriesCall) DoAndReturn(f func(context.Context) ([]domain.LinkedRepository, error)) *MockServiceServiceInterfaceLinkedRepositoriesCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// ListOrganizations mocks base method.
+func (m *MockServiceServiceInterface) ListOrganizations(ctx context.Context) (*domain.OrganizationListResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListOrganizations", ctx)
+	ret0, _ := ret[0].(*domain.OrganizationListResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListOrganizations indicates an expected call of ListOrganizations.
+func (mr *MockServiceServiceInterfaceMockRecorder) ListOrganizations(ctx any) *MockServiceServiceInterfaceListOrganizationsCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListOrganizations", reflect.TypeOf((*MockServiceServiceInterface)(nil).ListOrganizations), ctx)
+	return &MockServiceServiceInterfaceListOrganizationsCall{Call: call}
+}
+
+// MockServiceServiceInterfaceListOrganizationsCall wrap *gomock.Call
+type MockServiceServiceInterfaceListOrganizationsCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockServiceServiceInterfaceListOrganizationsCall) Return(arg0 *domain.OrganizationListResponse, arg1 error) *MockServiceServiceInterfaceListOrganizationsCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockServiceServiceInterfaceListOrganizationsCall) Do(f func(context.Context) (*domain.OrganizationListResponse, error)) *MockServiceServiceInterfaceListOrganizationsCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockServiceServiceInterfaceListOrganizationsCall) DoAndReturn(f func(context.Context) (*domain.OrganizationListResponse, error)) *MockServiceServiceInterfaceListOrganizationsCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// ListSubscriptions mocks base method.
+func (m *MockServiceServiceInterface) ListSubscriptions(ctx context.Context, username string) (*domain.SubscriptionListResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSubscriptions", ctx, username)
+	ret0, _ := ret[0].(*domain.SubscriptionListResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSubscriptions indicates an expected call of ListSubscriptions.
+func (mr *MockServiceServiceInterfaceMockRecorder) ListSubscriptions(ctx, username any) *MockServiceServiceInterfaceListSubscriptionsCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSubscriptions", reflect.TypeOf((*MockServiceServiceInterface)(nil).ListSubscriptions), ctx, username)
+	return &MockServiceServiceInterfaceListSubscriptionsCall{Call: call}
+}
+
+// MockServiceServiceInterfaceListSubscriptionsCall wrap *gomock.Call
+type MockServiceServiceInterfaceListSubscriptionsCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockServiceServiceInterfaceListSubscriptionsCall) Return(arg0 *domain.SubscriptionListResponse, arg1 error) *MockServiceServiceInterfaceListSubscriptionsCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockServiceServiceInterfaceListSubscriptionsCall) Do(f func(context.Context, string) (*domain.SubscriptionListResponse, error)) *MockServiceServiceInterfaceListSubscriptionsCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockServiceServiceInterfaceListSubscriptionsCall) DoAndReturn(f func(context.Context, string) (*domain.SubscriptionListResponse, error)) *MockServiceServiceInterfaceListSubscriptionsCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// ListTokens mocks base method.
+func (m *MockServiceServiceInterface) ListTokens(ctx context.Context) (*domain.TokenListResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTokens", ctx)
+	ret0, _ := ret[0].(*domain.TokenListResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTokens indicates an expected call of ListTokens.
+func (mr *MockServiceServiceInterfaceMockRecorder) ListTokens(ctx any) *MockServiceServiceInterfaceListTokensCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTokens", reflect.TypeOf((*MockServiceServiceInterface)(nil).ListTokens), ctx)
+	return &MockServiceServiceInterfaceListTokensCall{Call: call}
+}
+
+// MockServiceServiceInterfaceListTokensCall wrap *gomock.Call
+type MockServiceServiceInterfaceListTokensCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockServiceServiceInterfaceListTokensCall) Return(arg0 *domain.TokenListResponse, arg1 error) *MockServiceServiceInterfaceListTokensCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockServiceServiceInterfaceListTokensCall) Do(f func(context.Context) (*domain.TokenListResponse, error)) *MockServiceServiceInterfaceListTokensCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockServiceServiceInterfaceListTokensCall) DoAndReturn(f func(context.Context) (*domain.TokenListResponse, error)) *MockServiceServiceInterfaceListTokensCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// ListUsers mocks base method.
+func (m *MockServiceServiceInterface) ListUsers(ctx context.Context) (*domain.UserListResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUsers", ctx)
+	ret0, _ := ret[0].(*domain.UserListResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListUsers indicates an expected call of ListUsers.
+func (mr *MockServiceServiceInterfaceMockRecorder) ListUsers(ctx any) *MockServiceServiceInterfaceListUsersCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUsers", reflect.TypeOf((*MockServiceServiceInterface)(nil).ListUsers), ctx)
+	return &MockServiceServiceInterfaceListUsersCall{Call: call}
+}
+
+// MockServiceServiceInterfaceListUsersCall wrap *gomock.Call
+type MockServiceServiceInterfaceListUsersCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockServiceServiceInterfaceListUsersCall) Return(arg0 *domain.UserListResponse, arg1 error) *MockServiceServiceInterfaceListUsersCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockServiceServiceInterfaceListUsersCall) Do(f func(context.Context) (*domain.UserListResponse, error)) *MockServiceServiceInterfaceListUsersCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockServiceServiceInterfaceListUsersCall) DoAndReturn(f func(context.Context) (*domain.UserListResponse, error)) *MockServiceServiceInterfaceListUsersCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// ListWebhookDeliveries mocks base method.
+func (m *MockServiceServiceInterface) ListWebhookDeliveries(ctx context.Context, id int) (*domain.WebhookDeliveryListResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListWebhookDeliveries", ctx, id)
+	ret0, _ := ret[0].(*domain.WebhookDeliveryListResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListWebhookDeliveries indicates an expected call of ListWebhookDeliveries.
+func (mr *MockServiceServiceInterfaceMockRecorder) ListWebhookDeliveries(ctx, id any) *MockServiceServiceInterfaceListWebhookDeliveriesCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListWebhookDeliveries", reflect.TypeOf((*MockServiceServiceInterface)(nil).ListWebhookDeliveries), ctx, id)
+	return &MockServiceServiceInterfaceListWebhookDeliveriesCall{Call: call}
+}
+
+// MockServiceServiceInterfaceListWebhookDeliveriesCall wrap *gomock.Call
+type MockServiceServiceInterfaceListWebhookDeliveriesCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockServiceServiceInterfaceListWebhookDeliveriesCall) Return(arg0 *domain.WebhookDeliveryListResponse, arg1 error) *MockServiceServiceInterfaceListWebhookDeliveriesCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockServiceServiceInterfaceListWebhookDeliveriesCall) Do(f func(context.Context, int) (*domain.WebhookDeliveryListResponse, error)) *MockServiceServiceInterfaceListWebhookDeliveriesCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockServiceServiceInterfaceListWebhookDeliveriesCall) DoAndReturn(f func(context.Context, int) (*domain.WebhookDeliveryListResponse, error)) *MockServiceServiceInterfaceListWebhookDeliveriesCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// ListWebhooks mocks base method.
+func (m *MockServiceServiceInterface) ListWebhooks(ctx context.Context) (*domain.WebhookListResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListWebhooks", ctx)
+	ret0, _ := ret[0].(*domain.WebhookListResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListWebhooks indicates an expected call of ListWebhooks.
+func (mr *MockServiceServiceInterfaceMockRecorder) ListWebhooks(ctx any) *MockServiceServiceInterfaceListWebhooksCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListWebhooks", reflect.TypeOf((*MockServiceServiceInterface)(nil).ListWebhooks), ctx)
+	return &MockServiceServiceInterfaceListWebhooksCall{Call: call}
+}
+
+// MockServiceServiceInterfaceListWebhooksCall wrap *gomock.Call
+type MockServiceServiceInterfaceListWebhooksCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockServiceServiceInterfaceListWebhooksCall) Return(arg0 *domain.WebhookListResponse, arg1 error) *MockServiceServiceInterfaceListWebhooksCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockServiceServiceInterfaceListWebhooksCall) Do(f func(context.Context) (*domain.WebhookListResponse, error)) *MockServiceServiceInterfaceListWebhooksCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockServiceServiceInterfaceListWebhooksCall) DoAndReturn(f func(context.Context) (*domain.WebhookListResponse, error)) *MockServiceServiceInterfaceListWebhooksCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// PurgeAuditLogs mocks base method.
+func (m *MockServiceServiceInterface) PurgeAuditLogs(ctx context.Context, before time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeAuditLogs", ctx, before)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeAuditLogs indicates an expected call of PurgeAuditLogs.
+func (mr *MockServiceServiceInterfaceMockRecorder) PurgeAuditLogs(ctx, before any) *MockServiceServiceInterfacePurgeAuditLogsCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeAuditLogs", reflect.TypeOf((*MockServiceServiceInterface)(nil).PurgeAuditLogs), ctx, before)
+	return &MockServiceServiceInterfacePurgeAuditLogsCall{Call: call}
+}
+
+// MockServiceServiceInterfacePurgeAuditLogsCall wrap *gomock.Call
+type MockServiceServiceInterfacePurgeAuditLogsCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockServiceServiceInterfacePurgeAuditLogsCall) Return(arg0 int64, arg1 error) *MockServiceServiceInterfacePurgeAuditLogsCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockServiceServiceInterfacePurgeAuditLogsCall) Do(f func(context.Context, time.Time) (int64, error)) *MockServiceServiceInterfacePurgeAuditLogsCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockServiceServiceInterfacePurgeAuditLogsCall) DoAndReturn(f func(context.Context, time.Time) (int64, error)) *MockServiceServiceInterfacePurgeAuditLogsCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// PurgeHealthResults mocks base method.
+func (m *MockServiceServiceInterface) PurgeHealthResults(ctx context.Context, before time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeHealthResults", ctx, before)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeHealthResults indicates an expected call of PurgeHealthResults.
+func (mr *MockServiceServiceInterfaceMockRecorder) PurgeHealthResults(ctx, before any) *MockServiceServiceInterfacePurgeHealthResultsCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeHealthResults", reflect.TypeOf((*MockServiceServiceInterface)(nil).PurgeHealthResults), ctx, before)
+	return &MockServiceServiceInterfacePurgeHealthResultsCall{Call: call}
+}
+
+// MockServiceServiceInterfacePurgeHealthResultsCall wrap *gomock.Call
+type MockServiceServiceInterfacePurgeHealthResultsCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockServiceServiceInterfacePurgeHealthResultsCall) Return(arg0 int64, arg1 error) *MockServiceServiceInterfacePurgeHealthResultsCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockServiceServiceInterfacePurgeHealthResultsCall) Do(f func(context.Context, time.Time) (int64, error)) *MockServiceServiceInterfacePurgeHealthResultsCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockServiceServiceInterfacePurgeHealthResultsCall) DoAndReturn(f func(context.Context, time.Time) (int64, error)) *MockServiceServiceInterfacePurgeHealthResultsCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// PurgeUsage mocks base method.
+func (m *MockServiceServiceInterface) PurgeUsage(ctx context.Context, before time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeUsage", ctx, before)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeUsage indicates an expected call of PurgeUsage.
+func (mr *MockServiceServiceInterfaceMockRecorder) PurgeUsage(ctx, before any) *MockServiceServiceInterfacePurgeUsageCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeUsage", reflect.TypeOf((*MockServiceServiceInterface)(nil).PurgeUsage), ctx, before)
+	return &MockServiceServiceInterfacePurgeUsageCall{Call: call}
+}
+
+// MockServiceServiceInterfacePurgeUsageCall wrap *gomock.Call
+type MockServiceServiceInterfacePurgeUsageCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockServiceServiceInterfacePurgeUsageCall) Return(arg0 int64, arg1 error) *MockServiceServiceInterfacePurgeUsageCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockServiceServiceInterfacePurgeUsageCall) Do(f func(context.Context, time.Time) (int64, error)) *MockServiceServiceInterfacePurgeUsageCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockServiceServiceInterfacePurgeUsageCall) DoAndReturn(f func(context.Context, time.Time) (int64, error)) *MockServiceServiceInterfacePurgeUsageCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// PurgeWebhookDeliveries mocks base method.
+func (m *MockServiceServiceInterface) PurgeWebhookDeliveries(ctx context.Context, before time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeWebhookDeliveries", ctx, before)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeWebhookDeliveries indicates an expected call of PurgeWebhookDeliveries.
+func (mr *MockServiceServiceInterfaceMockRecorder) PurgeWebhookDeliveries(ctx, before any) *MockServiceServiceInterfacePurgeWebhookDeliveriesCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeWebhookDeliveries", reflect.TypeOf((*MockServiceServiceInterface)(nil).PurgeWebhookDeliveries), ctx, before)
+	return &MockServiceServiceInterfacePurgeWebhookDeliveriesCall{Call: call}
+}
+
+// MockServiceServiceInterfacePurgeWebhookDeliveriesCall wrap *gomock.Call
+type MockServiceServiceInterfacePurgeWebhookDeliveriesCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockServiceServiceInterfacePurgeWebhookDeliveriesCall) Return(arg0 int64, arg1 error) *MockServiceServiceInterfacePurgeWebhookDeliveriesCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockServiceServiceInterfacePurgeWebhookDeliveriesCall) Do(f func(context.Context, time.Time) (int64, error)) *MockServiceServiceInterfacePurgeWebhookDeliveriesCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockServiceServiceInterfacePurgeWebhookDeliveriesCall) DoAndReturn(f func(context.Context, time.Time) (int64, error)) *MockServiceServiceInterfacePurgeWebhookDeliveriesCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// RecordHealthResult mocks base method.
+func (m *MockServiceServiceInterface) RecordHealthResult(ctx context.Context, result domain.HealthResult) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordHealthResult", ctx, result)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecordHealthResult indicates an expected call of RecordHealthResult.
+func (mr *MockServiceServiceInterfaceMockRecorder) RecordHealthResult(ctx, result any) *MockServiceServiceInterfaceRecordHealthResultCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordHealthResult", reflect.TypeOf((*MockServiceServiceInterface)(nil).RecordHealthResult), ctx, result)
+	return &MockServiceServiceInterfaceRecordHealthResultCall{Call: call}
+}
+
+// MockServiceServiceInterfaceRecordHealthResultCall wrap *gomock.Call
+type MockServiceServiceInterfaceRecordHealthResultCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockServiceServiceInterfaceRecordHealthResultCall) Return(arg0 error) *MockServiceServiceInterfaceRecordHealthResultCall {
+	c.Call = c.Call.Return(arg0)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockServiceServiceInterfaceRecordHealthResultCall) Do(f func(context.Context, domain.HealthResult) error) *MockServiceServiceInterfaceRecordHealthResultCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockServiceServiceInterfaceRecordHealthResultCall) DoAndReturn(f func(context.Context, domain.HealthResult) error) *MockServiceServiceInterfaceRecordHealthResultCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// RecordRepositoryDetails mocks base method.
+func (m *MockServiceServiceInterface) RecordRepositoryDetails(ctx context.Context, details domain.RepositoryDetails) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordRepositoryDetails", ctx, details)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecordRepositoryDetails indicates an expected call of RecordRepositoryDetails.
+func (mr *MockServiceServiceInterfaceMockRecorder) RecordRepositoryDetails(ctx, details any) *MockServiceServiceInterfaceRecordRepositoryDetailsCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordRepositoryDetails", reflect.TypeOf((*MockServiceServiceInterface)(nil).RecordRepositoryDetails), ctx, details)
+	return &MockServiceServiceInterfaceRecordRepositoryDetailsCall{Call: call}
+}
+
+// MockServiceServiceInterfaceRecordRepositoryDetailsCall wrap *gomock.Call
+type MockServiceServiceInterfaceRecordRepositoryDetailsCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockServiceServiceInterfaceRecordRepositoryDetailsCall) Return(arg0 error) *MockServiceServiceInterfaceRecordRepositoryDetailsCall {
+	c.Call = c.Call.Return(arg0)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockServiceServiceInterfaceRecordRepositoryDetailsCall) Do(f func(context.Context, domain.RepositoryDetails) error) *MockServiceServiceInterfaceRecordRepositoryDetailsCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockServiceServiceInterfaceRecordRepositoryDetailsCall) DoAndReturn(f func(context.Context, domain.RepositoryDetails) error) *MockServiceServiceInterfaceRecordRepositoryDetailsCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// RevokeToken mocks base method.
+func (m *MockServiceServiceInterface) RevokeToken(ctx context.Context, id int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeToken", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeToken indicates an expected call of RevokeToken.
+func (mr *MockServiceServiceInterfaceMockRecorder) RevokeToken(ctx, id any) *MockServiceServiceInterfaceRevokeTokenCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeToken", reflect.TypeOf((*MockServiceServiceInterface)(nil).RevokeToken), ctx, id)
+	return &MockServiceServiceInterfaceRevokeTokenCall{Call: call}
+}
+
+// MockServiceServiceInterfaceRevokeTokenCall wrap *gomock.Call
+type MockServiceServiceInterfaceRevokeTokenCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockServiceServiceInterfaceRevokeTokenCall) Return(arg0 error) *MockServiceServiceInterfaceRevokeTokenCall {
+	c.Call = c.Call.Return(arg0)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockServiceServiceInterfaceRevokeTokenCall) Do(f func(context.Context, int) error) *MockServiceServiceInterfaceRevokeTokenCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockServiceServiceInterfaceRevokeTokenCall) DoAndReturn(f func(context.Context, int) error) *MockServiceServiceInterfaceRevokeTokenCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// SearchServices mocks base method.
+func (m *MockServiceServiceInterface) SearchServices(ctx context.Context, query domain.SearchQuery) (*domain.SearchResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchServices", ctx, query)
+	ret0, _ := ret[0].(*domain.SearchResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchServices indicates an expected call of SearchServices.
+func (mr *MockServiceServiceInterfaceMockRecorder) SearchServices(ctx, query any) *MockServiceServiceInterfaceSearchServicesCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchServices", reflect.TypeOf((*MockServiceServiceInterface)(nil).SearchServices), ctx, query)
+	return &MockServiceServiceInterfaceSearchServicesCall{Call: call}
+}
+
+// MockServiceServiceInterfaceSearchServicesCall wrap *gomock.Call
+type MockServiceServiceInterfaceSearchServicesCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockServiceServiceInterfaceSearchServicesCall) Return(arg0 *domain.SearchResponse, arg1 error) *MockServiceServiceInterfaceSearchServicesCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockServiceServiceInterfaceSearchServicesCall) Do(f func(context.Context, domain.SearchQuery) (*domain.SearchResponse, error)) *MockServiceServiceInterfaceSearchServicesCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockServiceServiceInterfaceSearchServicesCall) DoAndReturn(f func(context.Context, domain.SearchQuery) (*domain.SearchResponse, error)) *MockServiceServiceInterfaceSearchServicesCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// SetHealthCheck mocks base method.
+func (m *MockServiceServiceInterface) SetHealthCheck(ctx context.Context, serviceID int, input domain.HealthCheckInput) (*domain.ServiceHealth, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetHealthCheck", ctx, serviceID, input)
+	ret0, _ := ret[0].(*domain.ServiceHealth)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetHealthCheck indicates an expected call of SetHealthCheck.
+func (mr *MockServiceServiceInterfaceMockRecorder) SetHealthCheck(ctx, serviceID, input any) *MockServiceServiceInterfaceSetHealthCheckCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetHealthCheck", reflect.TypeOf((*MockServiceServiceInterface)(nil).SetHealthCheck), ctx, serviceID, input)
+	return &MockServiceServiceInterfaceSetHealthCheckCall{Call: call}
+}
+
+// MockServiceServiceInterfaceSetHealthCheckCall wrap *gomock.Call
+type MockServiceServiceInterfaceSetHealthCheckCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockServiceServiceInterfaceSetHealthCheckCall) Return(arg0 *domain.ServiceHealth, arg1 error) *MockServiceServiceInterfaceSetHealthCheckCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockServiceServiceInterfaceSetHealthCheckCall) Do(f func(context.Context, int, domain.HealthCheckInput) (*domain.ServiceHealth, error)) *MockServiceServiceInterfaceSetHealthCheckCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockServiceServiceInterfaceSetHealthCheckCall) DoAndReturn(f func(context.Context, int, domain.HealthCheckInput) (*domain.ServiceHealth, error)) *MockServiceServiceInterfaceSetHealthCheckCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// SetSLO mocks base method.
+func (m *MockServiceServiceInterface) SetSLO(ctx context.Context, serviceID int, slo domain.ServiceSLO) (*domain.ServiceSLO, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetSLO", ctx, serviceID, slo)
+	ret0, _ := ret[0].(*domain.ServiceSLO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetSLO indicates an expected call of SetSLO.
+func (mr *MockServiceServiceInterfaceMockRecorder) SetSLO(ctx, serviceID, slo any) *MockServiceServiceInterfaceSetSLOCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetSLO", reflect.TypeOf((*MockServiceServiceInterface)(nil).SetSLO), ctx, serviceID, slo)
+	return &MockServiceServiceInterfaceSetSLOCall{Call: call}
+}
+
+// MockServiceServiceInterfaceSetSLOCall wrap *gomock.Call
+type MockServiceServiceInterfaceSetSLOCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockServiceServiceInterfaceSetSLOCall) Return(arg0 *domain.ServiceSLO, arg1 error) *MockServiceServiceInterfaceSetSLOCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockServiceServiceInterfaceSetSLOCall) Do(f func(context.Context, int, domain.ServiceSLO) (*domain.ServiceSLO, error)) *MockServiceServiceInterfaceSetSLOCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockServiceServiceInterfaceSetSLOCall) DoAndReturn(f func(context.Context, int, domain.ServiceSLO) (*domain.ServiceSLO, error)) *MockServiceServiceInterfaceSetSLOCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// SetSourceRepository mocks base method.
+func (m *MockServiceServiceInterface) SetSourceRepository(ctx context.Context, serviceID int, input domain.SourceRepositoryInput) (*domain.SourceRepository, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetSourceRepository", ctx, serviceID, input)
+	ret0, _ := ret[0].(*domain.SourceRepository)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetSourceRepository indicates an expected call of SetSourceRepository.
+func (mr *MockServiceServiceInterfaceMockRecorder) SetSourceRepository(ctx, serviceID, input any) *MockServiceServiceInterfaceSetSourceRepositoryCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetSourceRepository", reflect.TypeOf((*MockServiceServiceInterface)(nil).SetSourceRepository), ctx, serviceID, input)
+	return &MockServiceServiceInterfaceSetSourceRepositoryCall{Call: call}
+}
+
+// MockServiceServiceInterfaceSetSourceRepositoryCall wrap *gomock.Call
+type MockServiceServiceInterfaceSetSourceRepositoryCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockServiceServiceInterfaceSetSourceRepositoryCall) Return(arg0 *domain.SourceRepository, arg1 error) *MockServiceServiceInterfaceSetSourceRepositoryCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockServiceServiceInterfaceSetSourceRepositoryCall) Do(f func(context.Context, int, domain.SourceRepositoryInput) (*domain.SourceRepository, error)) *MockServiceServiceInterfaceSetSourceRepositoryCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockServiceServiceInterfaceSetSourceRepositoryCall) DoAndReturn(f func(context.Context, int, domain.SourceRepositoryInput) (*domain.SourceRepository, error)) *MockServiceServiceInterfaceSetSourceRepositoryCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// SetSunset mocks base method.
+func (m *MockServiceServiceInterface) SetSunset(ctx context.Context, serviceID, versionID int, input domain.SunsetInput) (*domain.Sunset, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetSunset", ctx, serviceID, versionID, input)
+	ret0, _ := ret[0].(*domain.Sunset)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetSunset indicates an expected call of SetSunset.
+func (mr *MockServiceServiceInterfaceMockRecorder) SetSunset(ctx, serviceID, versionID, input any) *MockServiceServiceInterfaceSetSunsetCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetSunset", reflect.TypeOf((*MockServiceServiceInterface)(nil).SetSunset), ctx, serviceID, versionID, input)
+	return &MockServiceServiceInterfaceSetSunsetCall{Call: call}
+}
+
+// MockServiceServiceInterfaceSetSunsetCall wrap *gomock.Call
+type MockServiceServiceInterfaceSetSunsetCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockServiceServiceInterfaceSetSunsetCall) Return(arg0 *domain.Sunset, arg1 error) *MockServiceServiceInterfaceSetSunsetCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockServiceServiceInterfaceSetSunsetCall) Do(f func(context.Context, int, int, domain.SunsetInput) (*domain.Sunset, error)) *MockServiceServiceInterfaceSetSunsetCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockServiceServiceInterfaceSetSunsetCall) DoAndReturn(f func(context.Context, int, int, domain.SunsetInput) (*domain.Sunset, error)) *MockServiceServiceInterfaceSetSunsetCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// SetTranslation mocks base method.
+func (m *MockServiceServiceInterface) SetTranslation(ctx context.Context, serviceID int, language string, input domain.TranslationInput) (*domain.Translation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetTranslation", ctx, serviceID, language, input)
+	ret0, _ := ret[0].(*domain.Translation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetTranslation indicates an expected call of SetTranslation.
+func (mr *MockServiceServiceInterfaceMockRecorder) SetTranslation(ctx, serviceID, language, input any) *MockServiceServiceInterfaceSetTranslationCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetTranslation", reflect.TypeOf((*MockServiceServiceInterface)(nil).SetTranslation), ctx, serviceID, language, input)
+	return &MockServiceServiceInterfaceSetTranslationCall{Call: call}
+}
+
+// MockServiceServiceInterfaceSetTranslationCall wrap *gomock.Call
+type MockServiceServiceInterfaceSetTranslationCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockServiceServiceInterfaceSetTranslationCall) Return(arg0 *domain.Translation, arg1 error) *MockServiceServiceInterfaceSetTranslationCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockServiceServiceInterfaceSetTranslationCall) Do(f func(context.Context, int, string, domain.TranslationInput) (*domain.Translation, error)) *MockServiceServiceInterfaceSetTranslationCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockServiceServiceInterfaceSetTranslationCall) DoAndReturn(f func(context.Context, int, string, domain.TranslationInput) (*domain.Translation, error)) *MockServiceServiceInterfaceSetTranslationCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// SubscribeService mocks base method.
+func (m *MockServiceServiceInterface) SubscribeService(ctx context.Context, id int, username string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SubscribeService", ctx, id, username)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SubscribeService indicates an expected call of SubscribeService.
+func (mr *MockServiceServiceInterfaceMockRecorder) SubscribeService(ctx, id, username any) *MockServiceServiceInterfaceSubscribeServiceCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SubscribeService", reflect.TypeOf((*MockServiceServiceInterface)(nil).SubscribeService), ctx, id, username)
+	return &MockServiceServiceInterfaceSubscribeServiceCall{Call: call}
+}
+
+// MockServiceServiceInterfaceSubscribeServiceCall wrap *gomock.Call
+type MockServiceServiceInterfaceSubscribeServiceCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockServiceServiceInterfaceSubscribeServiceCall) Return(arg0 error) *MockServiceServiceInterfaceSubscribeServiceCall {
+	c.Call = c.Call.Return(arg0)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockServiceServiceInterfaceSubscribeServiceCall) Do(f func(context.Context, int, string) error) *MockServiceServiceInterfaceSubscribeServiceCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockServiceServiceInterfaceSubscribeServiceCall) DoAndReturn(f func(context.Context, int, string) error) *MockServiceServiceInterfaceSubscribeServiceCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// TransferOwnership mocks base method.
+func (m *MockServiceServiceInterface) TransferOwnership(ctx context.Context, serviceID int, username string, admin bool, input domain.TransferInput) (*domain.OwnershipTransfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TransferOwnership", ctx, serviceID, username, admin, input)
+	ret0, _ := ret[0].(*domain.OwnershipTransfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TransferOwnership indicates an expected call of TransferOwnership.
+func (mr *MockServiceServiceInterfaceMockRecorder) TransferOwnership(ctx, serviceID, username, admin, input any) *MockServiceServiceInterfaceTransferOwnershipCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TransferOwnership", reflect.TypeOf((*MockServiceServiceInterface)(nil).TransferOwnership), ctx, serviceID, username, admin, input)
+	return &MockServiceServiceInterfaceTransferOwnershipCall{Call: call}
+}
+
+// MockServiceServiceInterfaceTransferOwnershipCall wrap *gomock.Call
+type MockServiceServiceInterfaceTransferOwnershipCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockServiceServiceInterfaceTransferOwnershipCall) Return(arg0 *domain.OwnershipTransfer, arg1 error) *MockServiceServiceInterfaceTransferOwnershipCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockServiceServiceInterfaceTransferOwnershipCall) Do(f func(context.Context, int, string, bool, domain.TransferInput) (*domain.OwnershipTransfer, error)) *MockServiceServiceInterfaceTransferOwnershipCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockServiceServiceInterfaceTransferOwnershipCall) DoAndReturn(f func(context.Context, int, string, bool, domain.TransferInput) (*domain.OwnershipTransfer, error)) *MockServiceServiceInterfaceTransferOwnershipCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// UndeployEnvironment mocks base method.
+func (m *MockServiceServiceInterface) UndeployEnvironment(ctx context.Context, serviceID int, environment string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UndeployEnvironment", ctx, serviceID, environment)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UndeployEnvironment indicates an expected call of UndeployEnvironment.
+func (mr *MockServiceServiceInterfaceMockRecorder) UndeployEnvironment(ctx, serviceID, environment any) *MockServiceServiceInterfaceUndeployEnvironmentCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UndeployEnvironment", reflect.TypeOf((*MockServiceServiceInterface)(nil).UndeployEnvironment), ctx, serviceID, environment)
+	return &MockServiceServiceInterfaceUndeployEnvironmentCall{Call: call}
+}
+
+// MockServiceServiceInterfaceUndeployEnvironmentCall wrap *gomock.Call
+type MockServiceServiceInterfaceUndeployEnvironmentCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockServiceServiceInterfaceUndeployEnvironmentCall) Return(arg0 error) *MockServiceServiceInterfaceUndeployEnvironmentCall {
+	c.Call = c.Call.Return(arg0)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockServiceServiceInterfaceUndeployEnvironmentCall) Do(f func(context.Context, int, string) error) *MockServiceServiceInterfaceUndeployEnvironmentCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockServiceServiceInterfaceUndeployEnvironmentCall) DoAndReturn(f func(context.Context, int, string) error) *MockServiceServiceInterfaceUndeployEnvironmentCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// UnfavoriteService mocks base method.
+func (m *MockServiceServiceInterface) UnfavoriteService(ctx context.Context, id int, username string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnfavoriteService", ctx, id, username)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UnfavoriteService indicates an expected call of UnfavoriteService.
+func (mr *MockServiceServiceInterfaceMockRecorder) UnfavoriteService(ctx, id, username any) *MockServiceServiceInterfaceUnfavoriteServiceCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnfavoriteService", reflect.TypeOf((*MockServiceServiceInterface)(nil).UnfavoriteService), ctx, id, username)
+	return &MockServiceServiceInterfaceUnfavoriteServiceCall{Call: call}
+}
+
+// MockServiceServiceInterfaceUnfavoriteServiceCall wrap *gomock.Call
+type MockServiceServiceInterfaceUnfavoriteServiceCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockServiceServiceInterfaceUnfavoriteServiceCall) Return(arg0 error) *MockServiceServiceInterfaceUnfavoriteServiceCall {
+	c.Call = c.Call.Return(arg0)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockServiceServiceInterfaceUnfavoriteServiceCall) Do(f func(context.Context, int, string) error) *MockServiceServiceInterfaceUnfavoriteServiceCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockServiceServiceInterfaceUnfavoriteServiceCall) DoAndReturn(f func(context.Context, int, string) error) *MockServiceServiceInterfaceUnfavoriteServiceCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// UnsubscribeService mocks base method.
+func (m *MockServiceServiceInterface) UnsubscribeService(ctx context.Context, id int, username string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnsubscribeService", ctx, id, username)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UnsubscribeService indicates an expected call of UnsubscribeService.
+func (mr *MockServiceServiceInterfaceMockRecorder) UnsubscribeService(ctx, id, username any) *MockServiceServiceInterfaceUnsubscribeServiceCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnsubscribeService", reflect.TypeOf((*MockServiceServiceInterface)(nil).UnsubscribeService), ctx, id, username)
+	return &MockServiceServiceInterfaceUnsubscribeServiceCall{Call: call}
+}
+
+// MockServiceServiceInterfaceUnsubscribeServiceCall wrap *gomock.Call
+type MockServiceServiceInterfaceUnsubscribeServiceCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockServiceServiceInterfaceUnsubscribeServiceCall) Return(arg0 error) *MockServiceServiceInterfaceUnsubscribeServiceCall {
+	c.Call = c.Call.Return(arg0)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockServiceServiceInterfaceUnsubscribeServiceCall) Do(f func(context.Context, int, string) error) *MockServiceServiceInterfaceUnsubscribeServiceCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockServiceServiceInterfaceUnsubscribeServiceCall) DoAndReturn(f func(context.Context, int, string) error) *MockServiceServiceInterfaceUnsubscribeServiceCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// UpdateNotificationPreferences mocks base method.
+func (m *MockServiceServiceInterface) UpdateNotificationPreferences(ctx context.Context, username string, preferences domain.NotificationPreferences) (*domain.NotificationPreferences, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateNotificationPreferences", ctx, username, preferences)
+	ret0, _ := ret[0].(*domain.NotificationPreferences)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateNotificationPreferences indicates an expected call of UpdateNotificationPreferences.
+func (mr *MockServiceServiceInterfaceMockRecorder) UpdateNotificationPreferences(ctx, username, preferences any) *MockServiceServiceInterfaceUpdateNotificationPreferencesCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateNotificationPreferences", reflect.TypeOf((*MockServiceServiceInterface)(nil).UpdateNotificationPreferences), ctx, username, preferences)
+	return &MockServiceServiceInterfaceUpdateNotificationPreferencesCall{Call: call}
+}
+
+// MockServiceServiceInterfaceUpdateNotificationPreferencesCall wrap *gomock.Call
+type MockServiceServiceInterfaceUpdateNotificationPreferencesCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockServiceServiceInterfaceUpdateNotificationPreferencesCall) Return(arg0 *domain.NotificationPreferences, arg1 error) *MockServiceServiceInterfaceUpdateNotificationPreferencesCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockServiceServiceInterfaceUpdateNotificationPreferencesCall) Do(f func(context.Context, string, domain.NotificationPreferences) (*domain.NotificationPreferences, error)) *MockServiceServiceInterfaceUpdateNotificationPreferencesCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockServiceServiceInterfaceUpdateNotificationPreferencesCall) DoAndReturn(f func(context.Context, string, domain.NotificationPreferences) (*domain.NotificationPreferences, error)) *MockServiceServiceInterfaceUpdateNotificationPreferencesCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// UpdateService mocks base method.
+func (m *MockServiceServiceInterface) UpdateService(ctx context.Context, id int, input domain.ServiceInput) (*domain.ServiceWithVersions, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateService", ctx, id, input)
+	ret0, _ := ret[0].(*domain.ServiceWithVersions)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateService indicates an expected call of UpdateService.
+func (mr *MockServiceServiceInterfaceMockRecorder) UpdateService(ctx, id, input any) *MockServiceServiceInterfaceUpdateServiceCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateService", reflect.TypeOf((*MockServiceServiceInterface)(nil).UpdateService), ctx, id, input)
+	return &MockServiceServiceInterfaceUpdateServiceCall{Call: call}
+}
+
+// MockServiceServiceInterfaceUpdateServiceCall wrap *gomock.Call
+type MockServiceServiceInterfaceUpdateServiceCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockServiceServiceInterfaceUpdateServiceCall) Return(arg0 *domain.ServiceWithVersions, arg1 error) *MockServiceServiceInterfaceUpdateServiceCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockServiceServiceInterfaceUpdateServiceCall) Do(f func(context.Context, int, domain.ServiceInput) (*domain.ServiceWithVersions, error)) *MockServiceServiceInterfaceUpdateServiceCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockServiceServiceInterfaceUpdateServiceCall) DoAndReturn(f func(context.Context, int, domain.ServiceInput) (*domain.ServiceWithVersions, error)) *MockServiceServiceInterfaceUpdateServiceCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// UploadVersionSpec mocks base method.
+func (m *MockServiceServiceInterface) UploadVersionSpec(ctx context.Context, serviceID, versionID int, content []byte) (*domain.ServiceVersion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UploadVersionSpec", ctx, serviceID, versionID, content)
+	ret0, _ := ret[0].(*domain.ServiceVersion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UploadVersionSpec indicates an expected call of UploadVersionSpec.
+func (mr *MockServiceServiceInterfaceMockRecorder) UploadVersionSpec(ctx, serviceID, versionID, content any) *MockServiceServiceInterfaceUploadVersionSpecCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UploadVersionSpec", reflect.TypeOf((*MockServiceServiceInterface)(nil).UploadVersionSpec), ctx, serviceID, versionID, content)
+	return &MockServiceServiceInterfaceUploadVersionSpecCall{Call: call}
+}
+
+// MockServiceServiceInterfaceUploadVersionSpecCall wrap *gomock.Call
+type MockServiceServiceInterfaceUploadVersionSpecCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockServiceServiceInterfaceUploadVersionSpecCall) Return(arg0 *domain.ServiceVersion, arg1 error) *MockServiceServiceInterfaceUploadVersionSpecCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockServiceServiceInterfaceUploadVersionSpecCall) Do(f func(context.Context, int, int, []byte) (*domain.ServiceVersion, error)) *MockServiceServiceInterfaceUploadVersionSpecCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockServiceServiceInterfaceUploadVersionSpecCall) DoAndReturn(f func(context.Context, int, int, []byte) (*domain.ServiceVersion, error)) *MockServiceServiceInterfaceUploadVersionSpecCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// MockMentionNotifier is a mock of MentionNotifier interface.
+type MockMentionNotifier struct {
+	ctrl     *gomock.Controller
+	recorder *MockMentionNotifierMockRecorder
+	isgomock struct{}
+}
+
+// MockMentionNotifierMockRecorder is the mock recorder for MockMentionNotifier.
+type MockMentionNotifierMockRecorder struct {
+	mock *MockMentionNotifier
+}
+
+// NewMockMentionNotifier creates a new mock instance.
+func NewMockMentionNotifier(ctrl *gomock.Controller) *MockMentionNotifier {
+	mock := &MockMentionNotifier{ctrl: ctrl}
+	mock.recorder = &MockMentionNotifierMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockMentionNotifier) EXPECT() *MockMentionNotifierMockRecorder {
+	return m.recorder
+}
+
+// NotifyMentions mocks base method.
+func (m *MockMentionNotifier) NotifyMentions(ctx context.Context, service *domain.Service, comment *domain.Comment) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "NotifyMentions", ctx, service, comment)
+}
+
+// NotifyMentions indicates an expected call of NotifyMentions.
+func (mr *MockMentionNotifierMockRecorder) NotifyMentions(ctx, service, comment any) *MockMentionNotifierNotifyMentionsCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NotifyMentions", reflect.TypeOf((*MockMentionNotifier)(nil).NotifyMentions), ctx, service, comment)
+	return &MockMentionNotifierNotifyMentionsCall{Call: call}
+}
+
+// MockMentionNotifierNotifyMentionsCall wrap *gomock.Call
+type MockMentionNotifierNotifyMentionsCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *MockMentionNotifierNotifyMentionsCall) Return() *MockMentionNotifierNotifyMentionsCall {
+	c.Call = c.Call.Return()
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *MockMentionNotifierNotifyMentionsCall) Do(f func(context.Context, *domain.Service, *domain.Comment)) *MockMentionNotifierNotifyMentionsCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *MockMentionNotifierNotifyMentionsCall) DoAndReturn(f func(context.Context, *domain.Service, *domain.Comment)) *MockMentionNotifierNotifyMentionsCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
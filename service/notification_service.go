@@ -0,0 +1,97 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/mail"
+	"strings"
+
+	"com.kong.connect/domain"
+	"com.kong.connect/tracing"
+)
+
+// maxEmailLength is the longest address accepted by SMTP servers
+const maxEmailLength = 254
+
+// GetNotificationPreferences retrieves the email notification preferences of
+// a user
+func (s *ServiceService) GetNotificationPreferences(ctx context.Context, username string) (_ *domain.NotificationPreferences, err error) {
+	ctx, span := tracing.Start(ctx, "ServiceService.GetNotificationPreferences")
+	defer func() { tracing.End(span, err) }()
+
+	preferences, err := s.repo.GetNotificationPreferences(ctx, username)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get notification preferences: %v", err)
+	}
+	return preferences, nil
+}
+
+// UpdateNotificationPreferences validates and replaces the email
+// notification preferences of a user. An empty email address turns every
+// notification off.
+func (s *ServiceService) UpdateNotificationPreferences(ctx context.Context, username string, preferences domain.NotificationPreferences) (_ *domain.NotificationPreferences, err error) {
+	ctx, span := tracing.Start(ctx, "ServiceService.UpdateNotificationPreferences")
+	defer func() { tracing.End(span, err) }()
+
+	preferences.Email = strings.TrimSpace(preferences.Email)
+	if preferences.Email != "" {
+		address, err := mail.ParseAddress(preferences.Email)
+		if err != nil || address.Address != preferences.Email || len(preferences.Email) > maxEmailLength {
+			return nil, invalidf("invalid notification preferences: email must be a plain email address of at most %d characters", maxEmailLength)
+		}
+	}
+
+	if err := s.repo.SaveNotificationPreferences(ctx, username, preferences); err != nil {
+		return nil, fmt.Errorf("failed to save notification preferences: %v", err)
+	}
+	return &preferences, nil
+}
+
+// SubscribeService subscribes a user to the email notifications of a service
+func (s *ServiceService) SubscribeService(ctx context.Context, id int, username string) (err error) {
+	ctx, span := tracing.Start(ctx, "ServiceService.SubscribeService")
+	defer func() { tracing.End(span, err) }()
+
+	if id <= 0 {
+		return invalidf("invalid service ID: %d", id)
+	}
+	found, err := s.repo.Subscribe(ctx, id, username)
+	if err != nil {
+		return fmt.Errorf("failed to subscribe to service: %v", err)
+	}
+	if !found {
+		return notFound("Service")
+	}
+	return nil
+}
+
+// UnsubscribeService ends the subscription of a user to a service
+func (s *ServiceService) UnsubscribeService(ctx context.Context, id int, username string) (err error) {
+	ctx, span := tracing.Start(ctx, "ServiceService.UnsubscribeService")
+	defer func() { tracing.End(span, err) }()
+
+	if id <= 0 {
+		return invalidf("invalid service ID: %d", id)
+	}
+	found, err := s.repo.Unsubscribe(ctx, id, username)
+	if err != nil {
+		return fmt.Errorf("failed to unsubscribe from service: %v", err)
+	}
+	if !found {
+		return notFound("Subscription")
+	}
+	return nil
+}
+
+// ListSubscriptions retrieves the services a user subscribed to in the
+// organization of ctx
+func (s *ServiceService) ListSubscriptions(ctx context.Context, username string) (_ *domain.SubscriptionListResponse, err error) {
+	ctx, span := tracing.Start(ctx, "ServiceService.ListSubscriptions")
+	defer func() { tracing.End(span, err) }()
+
+	subscriptions, err := s.repo.ListSubscriptions(ctx, username)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list subscriptions: %v", err)
+	}
+	return &domain.SubscriptionListResponse{Subscriptions: subscriptions}, nil
+}
@@ -0,0 +1,80 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"com.kong.connect/domain"
+	"com.kong.connect/repository"
+	"com.kong.connect/tracing"
+)
+
+const (
+	maxSlugLength             = 50
+	maxOrganizationNameLength = 200
+)
+
+// slugPattern keeps slugs usable in the X-Org header and in URLs
+var slugPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]*$`)
+
+// CreateOrganization validates and stores a new organization with an empty catalog
+func (s *ServiceService) CreateOrganization(ctx context.Context, input domain.OrganizationInput) (_ *domain.Organization, err error) {
+	ctx, span := tracing.Start(ctx, "ServiceService.CreateOrganization")
+	defer func() { tracing.End(span, err) }()
+
+	input.Slug = strings.ToLower(strings.TrimSpace(input.Slug))
+	input.Name = strings.TrimSpace(input.Name)
+	if !slugPattern.MatchString(input.Slug) || len(input.Slug) > maxSlugLength {
+		return nil, invalidf("invalid organization: slug must be 1 to %d lowercase letters, digits or hyphens", maxSlugLength)
+	}
+	if input.Name == "" {
+		input.Name = input.Slug
+	}
+	if len(input.Name) > maxOrganizationNameLength {
+		return nil, invalidf("invalid organization: name must be at most %d characters", maxOrganizationNameLength)
+	}
+
+	var organization *domain.Organization
+	err = s.repo.InTx(ctx, func(ctx context.Context) error {
+		var err error
+		if organization, err = s.repo.CreateOrganization(ctx, input); err != nil {
+			return err
+		}
+		return s.recordAudit(ctx, domain.AuditActionCreate, domain.AuditResourceOrg, organization.ID, nil, organization)
+	})
+	if err != nil {
+		if errors.Is(err, repository.ErrDuplicate) {
+			return nil, conflict("organization already exists", "Organization already exists")
+		}
+		return nil, fmt.Errorf("failed to create organization: %v", err)
+	}
+
+	return organization, nil
+}
+
+// ListOrganizations retrieves every organization of the deployment
+func (s *ServiceService) ListOrganizations(ctx context.Context) (_ *domain.OrganizationListResponse, err error) {
+	ctx, span := tracing.Start(ctx, "ServiceService.ListOrganizations")
+	defer func() { tracing.End(span, err) }()
+
+	organizations, err := s.repo.ListOrganizations(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list organizations: %v", err)
+	}
+	return &domain.OrganizationListResponse{Organizations: organizations}, nil
+}
+
+// GetOrganizationBySlug retrieves an organization, or nil when it does not exist
+func (s *ServiceService) GetOrganizationBySlug(ctx context.Context, slug string) (_ *domain.Organization, err error) {
+	ctx, span := tracing.Start(ctx, "ServiceService.GetOrganizationBySlug")
+	defer func() { tracing.End(span, err) }()
+
+	organization, err := s.repo.GetOrganizationBySlug(ctx, slug)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get organization: %v", err)
+	}
+	return organization, nil
+}
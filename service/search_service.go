@@ -0,0 +1,148 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"math"
+	"strings"
+	"unicode/utf8"
+
+	"com.kong.connect/domain"
+	"com.kong.connect/fold"
+	"com.kong.connect/tracing"
+)
+
+const (
+	// maxSearchTerms bounds the number of terms a single query may contain
+	maxSearchTerms = 10
+	// snippetLength is the approximate size of the highlighted description snippet
+	snippetLength = 160
+)
+
+// SearchServices ranks services by how well they match the query and highlights the matches
+func (s *ServiceService) SearchServices(ctx context.Context, query domain.SearchQuery) (_ *domain.SearchResponse, err error) {
+	ctx, span := tracing.Start(ctx, "ServiceService.SearchServices")
+	defer func() { tracing.End(span, err) }()
+
+	terms := searchTerms(query.Query)
+	if len(terms) == 0 {
+		return nil, invalidf("invalid search query: it has no words to search for")
+	}
+
+	if query.Page <= 0 {
+		query.Page = 1
+	}
+	if query.PageSize <= 0 {
+		query.PageSize = 12
+	}
+	if query.PageSize > 100 {
+		query.PageSize = 100
+	}
+
+	s.recordSearch(ctx, query.Query, query.Page)
+
+	results, total, err := s.repo.Search(ctx, terms, query.Page, query.PageSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search services: %v", err)
+	}
+
+	matcher := termMatcher(s.repo.SearchFold(), terms)
+	for i := range results {
+		results[i].Highlight = domain.SearchHighlight{
+			Name:        highlight(matcher, results[i].Service.Name),
+			Description: highlight(matcher, snippet(matcher, results[i].Service.Description, snippetLength)),
+		}
+	}
+	if results == nil {
+		results = []domain.SearchResult{}
+	}
+
+	return &domain.SearchResponse{
+		Query:      query.Query,
+		Results:    results,
+		Total:      total,
+		Page:       query.Page,
+		PageSize:   query.PageSize,
+		TotalPages: int(math.Ceil(float64(total) / float64(query.PageSize))),
+	}, nil
+}
+
+// searchTerms splits a raw query into unique, non-empty terms
+func searchTerms(q string) []string {
+	seen := make(map[string]struct{})
+	var terms []string
+	for _, field := range strings.Fields(q) {
+		key := strings.ToLower(field)
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		seen[key] = struct{}{}
+		terms = append(terms, field)
+		if len(terms) == maxSearchTerms {
+			break
+		}
+	}
+	return terms
+}
+
+// termMatcher finds the terms in text as the repository compares them,
+// ignoring case and, unless searches are accent sensitive, accents
+func termMatcher(foldTerm func(string) string, terms []string) *fold.Matcher {
+	return fold.NewMatcher(foldTerm, terms)
+}
+
+// highlight escapes text as HTML and wraps every match in <mark> tags.
+// Clients render highlights as HTML, so nothing of the text may pass as
+// markup.
+func highlight(matcher *fold.Matcher, text string) string {
+	var b strings.Builder
+	last := 0
+	for _, loc := range matcher.FindAllStringIndex(text, -1) {
+		b.WriteString(html.EscapeString(text[last:loc[0]]))
+		b.WriteString("<mark>" + html.EscapeString(text[loc[0]:loc[1]]) + "</mark>")
+		last = loc[1]
+	}
+	b.WriteString(html.EscapeString(text[last:]))
+	return b.String()
+}
+
+// snippet returns a window of roughly length bytes around the first match in
+// text, falling back to the start of text when nothing matches. It cuts the
+// raw text, which highlight escapes afterwards, so that no entity is cut.
+func snippet(matcher *fold.Matcher, text string, length int) string {
+	if len(text) <= length {
+		return text
+	}
+
+	start := 0
+	if loc := matcher.FindStringIndex(text); loc != nil {
+		start = loc[0] - length/4
+		if start < 0 {
+			start = 0
+		}
+	}
+	end := start + length
+	if end > len(text) {
+		end = len(text)
+		start = end - length
+	}
+
+	// Keep the window on rune boundaries
+	result := text[alignRune(text, start):alignRune(text, end)]
+	if start > 0 {
+		result = "..." + result
+	}
+	if end < len(text) {
+		result += "..."
+	}
+	return result
+}
+
+// alignRune moves i back to the start of the rune that contains it
+func alignRune(text string, i int) int {
+	for i > 0 && i < len(text) && !utf8.RuneStart(text[i]) {
+		i--
+	}
+	return i
+}
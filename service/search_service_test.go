@@ -0,0 +1,106 @@
+package service
+
+import (
+	"html"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"com.kong.connect/fold"
+)
+
+func TestSearchTerms(t *testing.T) {
+	got := searchTerms("  rates  FX rates  ")
+	if len(got) != 2 || got[0] != "rates" || got[1] != "FX" {
+		t.Errorf("searchTerms() = %v, want [rates FX]", got)
+	}
+
+	if got := searchTerms("   "); len(got) != 0 {
+		t.Errorf("searchTerms() = %v, want no terms", got)
+	}
+}
+
+func TestHighlight(t *testing.T) {
+	matcher := termMatcher(fold.Accents, []string{"rates", "fx"})
+	got := highlight(matcher, "FX Rates International")
+	want := "<mark>FX</mark> <mark>Rates</mark> International"
+	if got != want {
+		t.Errorf("highlight() = %q, want %q", got, want)
+	}
+
+	// Regex metacharacters in the query are matched literally
+	matcher = termMatcher(fold.Accents, []string{"a.b"})
+	if got := highlight(matcher, "axb a.b"); got != "axb <mark>a.b</mark>" {
+		t.Errorf("highlight() = %q, want literal match", got)
+	}
+
+	// Accents are ignored like in the search itself
+	matcher = termMatcher(fold.Accents, []string{"securite"})
+	if got := highlight(matcher, "Sécurité Cloud"); got != "<mark>Sécurité</mark> Cloud" {
+		t.Errorf("highlight() = %q, want the accented match", got)
+	}
+
+	// Markup in the text is escaped, inside matches too
+	matcher = termMatcher(fold.Accents, []string{"<b>", "alert"})
+	got = highlight(matcher, `Tom & Jerry<script>alert("x")</script> <b>`)
+	want = `Tom &amp; Jerry&lt;script&gt;<mark>alert</mark>(&#34;x&#34;)&lt;/script&gt; <mark>&lt;b&gt;</mark>`
+	if got != want {
+		t.Errorf("highlight() = %q, want %q", got, want)
+	}
+}
+
+func TestSnippet(t *testing.T) {
+	matcher := termMatcher(fold.Accents, []string{"needle"})
+	text := strings.Repeat("é", 200) + " needle " + strings.Repeat("z", 200)
+
+	got := snippet(matcher, text, 80)
+	if !strings.Contains(got, "needle") {
+		t.Errorf("snippet() = %q, want it to contain the match", got)
+	}
+	if !strings.HasPrefix(got, "...") || !strings.HasSuffix(got, "...") {
+		t.Errorf("snippet() = %q, want ellipses on both sides", got)
+	}
+	if !strings.Contains(got, "é") || strings.ContainsRune(got, '�') {
+		t.Errorf("snippet() = %q, want whole runes only", got)
+	}
+
+	short := "short text"
+	if got := snippet(matcher, short, 80); got != short {
+		t.Errorf("snippet() = %q, want %q", got, short)
+	}
+}
+
+// FuzzSearchQuery splits arbitrary queries into terms and highlights them in
+// arbitrary text, which must come out escaped as HTML once the marks are
+// removed
+func FuzzSearchQuery(f *testing.F) {
+	f.Add("rates FX rates", "FX Rates International")
+	f.Add("a.b [x] (y|z)* ^$", "axb a.b")
+	f.Add("securite", "Sécurité Cloud")
+	f.Add("<mark> </mark>", "a <mark> b")
+	f.Add("   x", "x​x")
+	f.Add("\xff", "\xff\xfe")
+
+	f.Fuzz(func(t *testing.T, query, text string) {
+		terms := searchTerms(query)
+		if len(terms) > maxSearchTerms {
+			t.Fatalf("searchTerms() = %d terms, want at most %d", len(terms), maxSearchTerms)
+		}
+		seen := make(map[string]bool)
+		for _, term := range terms {
+			if term == "" || strings.TrimSpace(term) != term || seen[strings.ToLower(term)] {
+				t.Fatalf("searchTerms(%q) = %q, want unique words", query, terms)
+			}
+			seen[strings.ToLower(term)] = true
+		}
+
+		matcher := termMatcher(fold.Accents, terms)
+		highlighted := highlight(matcher, text)
+		if unmarked := strings.NewReplacer("<mark>", "", "</mark>", "").Replace(highlighted); unmarked != html.EscapeString(text) {
+			t.Fatalf("highlight(%q) = %q, want the escaped text with marks", text, highlighted)
+		}
+		if got := snippet(matcher, text, 80); utf8.ValidString(text) && !utf8.ValidString(got) {
+			t.Fatalf("snippet(%q) = %q, want whole runes only", text, got)
+		}
+	})
+}
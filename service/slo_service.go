@@ -0,0 +1,171 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"com.kong.connect/domain"
+	"com.kong.connect/tracing"
+)
+
+const (
+	// maxLatencyTargetMS bounds latency targets to an hour
+	maxLatencyTargetMS = 3600000
+	// sloReportWindow is the window of SLO reports not given one
+	sloReportWindow = 7 * 24 * time.Hour
+)
+
+// SetSLO sets the SLOs of a service, replacing those it had, and returns
+// them. Attainment is measured by the probes of its health check.
+func (s *ServiceService) SetSLO(ctx context.Context, serviceID int, slo domain.ServiceSLO) (_ *domain.ServiceSLO, err error) {
+	ctx, span := tracing.Start(ctx, "ServiceService.SetSLO")
+	defer func() { tracing.End(span, err) }()
+
+	if serviceID <= 0 {
+		return nil, invalidf("invalid service ID: %d", serviceID)
+	}
+	// A target of 100 leaves no error budget
+	if !(slo.AvailabilityTarget > 0 && slo.AvailabilityTarget < 100) {
+		return nil, invalidf("invalid slo: availability_target must be a percent above 0 and below 100")
+	}
+	if slo.LatencyTargetMS < 0 || slo.LatencyTargetMS > maxLatencyTargetMS {
+		return nil, invalidf("invalid slo: latency_target_ms must be between 0 and %d", maxLatencyTargetMS)
+	}
+
+	existing, err := s.repo.GetByID(ctx, serviceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get service: %v", err)
+	}
+	if existing == nil {
+		return nil, notFound("Service")
+	}
+	if existing.SLO != nil && *existing.SLO == slo {
+		return existing.SLO, nil
+	}
+
+	after := existing.Service
+	after.SLO = &slo
+	found, err := s.auditedWrite(ctx, func(ctx context.Context) (bool, error) {
+		return s.repo.SetSLO(ctx, serviceID, slo)
+	}, domain.AuditActionUpdate, domain.AuditResourceService, serviceID, &existing.Service, &after)
+	if err != nil {
+		return nil, fmt.Errorf("failed to set slo: %v", err)
+	}
+	if !found {
+		return nil, notFound("Service")
+	}
+
+	s.publishUpdate(ctx, &existing.Service, &after)
+	return after.SLO, nil
+}
+
+// DeleteSLO removes the SLOs of a service
+func (s *ServiceService) DeleteSLO(ctx context.Context, serviceID int) (err error) {
+	ctx, span := tracing.Start(ctx, "ServiceService.DeleteSLO")
+	defer func() { tracing.End(span, err) }()
+
+	if serviceID <= 0 {
+		return invalidf("invalid service ID: %d", serviceID)
+	}
+	existing, err := s.repo.GetByID(ctx, serviceID)
+	if err != nil {
+		return fmt.Errorf("failed to get service: %v", err)
+	}
+	if existing == nil {
+		return notFound("Service")
+	}
+
+	after := existing.Service
+	after.SLO = nil
+	deleted, err := s.auditedWrite(ctx, func(ctx context.Context) (bool, error) {
+		return s.repo.DeleteSLO(ctx, serviceID)
+	}, domain.AuditActionUpdate, domain.AuditResourceService, serviceID, &existing.Service, &after)
+	if err != nil {
+		return fmt.Errorf("failed to delete slo: %v", err)
+	}
+	if !deleted {
+		return notFound("SLO")
+	}
+
+	s.publishUpdate(ctx, &existing.Service, &after)
+	return nil
+}
+
+// GetSLOReport reports the SLO attainment of every service of the
+// organization with SLOs during a window, by default the last 7 days. Only
+// the probe results within the health check retention count.
+func (s *ServiceService) GetSLOReport(ctx context.Context, query domain.SLOReportQuery) (_ *domain.SLOReport, err error) {
+	ctx, span := tracing.Start(ctx, "ServiceService.GetSLOReport")
+	defer func() { tracing.End(span, err) }()
+
+	if query.Until.IsZero() {
+		query.Until = time.Now().UTC()
+	}
+	if query.Since.IsZero() {
+		query.Since = query.Until.Add(-sloReportWindow)
+	}
+	if !query.Since.Before(query.Until) {
+		return nil, invalidf("invalid report window: since must be before until")
+	}
+
+	counts, err := s.repo.GetSLOProbeCounts(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get probe counts: %v", err)
+	}
+	report := &domain.SLOReport{Since: query.Since.UTC(), Until: query.Until.UTC(), Services: make([]domain.SLOAttainment, len(counts))}
+	for i, c := range counts {
+		report.Services[i] = sloAttainment(c)
+		switch report.Services[i].Status {
+		case domain.SLOMet:
+			report.Met++
+		case domain.SLOBreached:
+			report.Breached++
+		default:
+			report.NoData++
+		}
+	}
+	return report, nil
+}
+
+// sloAttainment measures the probes of a service against its SLOs.
+// Percentages are rounded to 3 decimals once compared.
+func sloAttainment(c domain.SLOProbeCounts) domain.SLOAttainment {
+	attainment := domain.SLOAttainment{
+		ServiceID:            c.ServiceID,
+		ServiceName:          c.ServiceName,
+		SLO:                  c.SLO,
+		Probes:               c.Probes,
+		UpProbes:             c.UpProbes,
+		ErrorBudgetRemaining: 100,
+		Status:               domain.SLONoData,
+	}
+	if c.Probes == 0 {
+		return attainment
+	}
+
+	availability := 100 * float64(c.UpProbes) / float64(c.Probes)
+	met := availability >= c.SLO.AvailabilityTarget
+	var latency float64
+	if c.SLO.LatencyTargetMS > 0 {
+		if c.UpProbes > 0 {
+			latency = 100 * float64(c.FastProbes) / float64(c.UpProbes)
+		}
+		met = met && latency >= domain.LatencyPercentile
+	}
+	budget := 100 * (1 - (100-availability)/(100-c.SLO.AvailabilityTarget))
+
+	attainment.Availability = roundPercent(availability)
+	attainment.LatencyAttainment = roundPercent(latency)
+	attainment.ErrorBudgetRemaining = roundPercent(budget)
+	attainment.Status = domain.SLOBreached
+	if met {
+		attainment.Status = domain.SLOMet
+	}
+	return attainment
+}
+
+func roundPercent(percent float64) float64 {
+	return math.Round(percent*1000) / 1000
+}
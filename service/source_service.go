@@ -0,0 +1,150 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"com.kong.connect/domain"
+	"com.kong.connect/scm"
+	"com.kong.connect/tracing"
+)
+
+// SetSourceRepository links a service to the repository of its source code
+// on GitHub or GitLab, replacing the repository it was linked to, and
+// returns the normalized link. Its default branch and latest release are
+// filled in by the next refresh.
+func (s *ServiceService) SetSourceRepository(ctx context.Context, serviceID int, input domain.SourceRepositoryInput) (_ *domain.SourceRepository, err error) {
+	ctx, span := tracing.Start(ctx, "ServiceService.SetSourceRepository")
+	defer func() { tracing.End(span, err) }()
+
+	if serviceID <= 0 {
+		return nil, invalidf("invalid service ID: %d", serviceID)
+	}
+	repository, err := scm.Parse(input.URL)
+	if err != nil {
+		return nil, invalidf("invalid repository: %v", err)
+	}
+
+	existing, err := s.repo.GetByID(ctx, serviceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get service: %v", err)
+	}
+	if existing == nil {
+		return nil, notFound("Service")
+	}
+	if existing.Repository != nil && existing.Repository.URL == repository.URL {
+		return existing.Repository, nil
+	}
+
+	after := existing.Service
+	after.Repository = &repository
+	found, err := s.auditedWrite(ctx, func(ctx context.Context) (bool, error) {
+		return s.repo.SetSourceRepository(ctx, serviceID, repository)
+	}, domain.AuditActionUpdate, domain.AuditResourceService, serviceID, &existing.Service, &after)
+	if err != nil {
+		return nil, fmt.Errorf("failed to set repository: %v", err)
+	}
+	if !found {
+		return nil, notFound("Service")
+	}
+
+	s.publishUpdate(ctx, &existing.Service, &after)
+	return after.Repository, nil
+}
+
+// DeleteSourceRepository unlinks the source repository of a service
+func (s *ServiceService) DeleteSourceRepository(ctx context.Context, serviceID int) (err error) {
+	ctx, span := tracing.Start(ctx, "ServiceService.DeleteSourceRepository")
+	defer func() { tracing.End(span, err) }()
+
+	if serviceID <= 0 {
+		return invalidf("invalid service ID: %d", serviceID)
+	}
+	existing, err := s.repo.GetByID(ctx, serviceID)
+	if err != nil {
+		return fmt.Errorf("failed to get service: %v", err)
+	}
+	if existing == nil {
+		return notFound("Service")
+	}
+
+	after := existing.Service
+	after.Repository = nil
+	deleted, err := s.auditedWrite(ctx, func(ctx context.Context) (bool, error) {
+		return s.repo.DeleteSourceRepository(ctx, serviceID)
+	}, domain.AuditActionUpdate, domain.AuditResourceService, serviceID, &existing.Service, &after)
+	if err != nil {
+		return fmt.Errorf("failed to delete repository: %v", err)
+	}
+	if !deleted {
+		return notFound("Repository")
+	}
+
+	s.publishUpdate(ctx, &existing.Service, &after)
+	return nil
+}
+
+// LinkedRepositories retrieves the linked source repositories of every
+// organization, for the refresher
+func (s *ServiceService) LinkedRepositories(ctx context.Context) (_ []domain.LinkedRepository, err error) {
+	ctx, span := tracing.Start(ctx, "ServiceService.LinkedRepositories")
+	defer func() { tracing.End(span, err) }()
+
+	repositories, err := s.repo.LinkedRepositories(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get linked repositories: %v", err)
+	}
+	return repositories, nil
+}
+
+// RecordRepositoryDetails stores the outcome of a refresh of the source
+// repository of a service of the organization of ctx, publishing
+// service.updated when its default branch or latest release changed.
+// Listings and details cached before show the previous refresh until then
+// or until they expire.
+func (s *ServiceService) RecordRepositoryDetails(ctx context.Context, details domain.RepositoryDetails) (err error) {
+	ctx, span := tracing.Start(ctx, "ServiceService.RecordRepositoryDetails")
+	defer func() { tracing.End(span, err) }()
+
+	existing, err := s.repo.GetByID(ctx, details.ServiceID)
+	if err != nil {
+		return fmt.Errorf("failed to get service: %v", err)
+	}
+	if existing == nil || existing.Repository == nil || existing.Repository.URL != details.URL {
+		return nil
+	}
+
+	refreshed := *existing.Repository
+	refreshedAt := details.RefreshedAt
+	refreshed.Error, refreshed.RefreshedAt = details.Error, &refreshedAt
+	if details.Error == "" {
+		refreshed.DefaultBranch, refreshed.LatestRelease = details.DefaultBranch, details.LatestRelease
+	}
+	changed := refreshed.DefaultBranch != existing.Repository.DefaultBranch ||
+		!sameRelease(refreshed.LatestRelease, existing.Repository.LatestRelease)
+
+	recorded, err := s.repo.RecordRepositoryDetails(ctx, details, changed)
+	if err != nil {
+		return fmt.Errorf("failed to record repository details: %v", err)
+	}
+	if !recorded || !changed {
+		return nil
+	}
+	after := existing.Service
+	after.Repository = &refreshed
+	s.publishUpdate(ctx, &existing.Service, &after)
+	return nil
+}
+
+// sameRelease reports whether two releases are the same to the second at
+// which their publication is stored
+func sameRelease(a, b *domain.Release) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	if a.Tag != b.Tag || a.Name != b.Name || a.URL != b.URL || (a.PublishedAt == nil) != (b.PublishedAt == nil) {
+		return false
+	}
+	return a.PublishedAt == nil || a.PublishedAt.Truncate(time.Second).Equal(b.PublishedAt.Truncate(time.Second))
+}
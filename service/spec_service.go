@@ -0,0 +1,220 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/getkin/kin-openapi/openapi3"
+
+	"com.kong.connect/domain"
+	"com.kong.connect/openapi"
+	"com.kong.connect/tracing"
+)
+
+// UploadVersionSpec validates an OpenAPI 3 spec, in JSON or YAML, and
+// attaches it to a version of a service with the metadata extracted from it,
+// replacing the spec the version had. It returns the version.
+func (s *ServiceService) UploadVersionSpec(ctx context.Context, serviceID, versionID int, content []byte) (_ *domain.ServiceVersion, err error) {
+	ctx, span := tracing.Start(ctx, "ServiceService.UploadVersionSpec")
+	defer func() { tracing.End(span, err) }()
+
+	existing, before, err := s.findVersion(ctx, serviceID, versionID)
+	if err != nil {
+		return nil, err
+	}
+	if len(content) == 0 {
+		return nil, invalidf("invalid spec: the request body is empty")
+	}
+	doc, err := openapi.Parse(content)
+	if err != nil {
+		return nil, invalidf("invalid spec: %v", err)
+	}
+	metadata := openapi.Describe(doc)
+	metadata.UploadedAt = time.Now().UTC()
+
+	var version *domain.ServiceVersion
+	err = s.repo.InTx(ctx, func(ctx context.Context) error {
+		var err error
+		if version, err = s.repo.SaveVersionSpec(ctx, serviceID, versionID, domain.VersionSpec{
+			Content:     content,
+			ContentType: openapi.ContentType(content),
+			Metadata:    metadata,
+		}); err != nil || version == nil {
+			return err
+		}
+		return s.recordAudit(ctx, domain.AuditActionUpdate, domain.AuditResourceVersion, versionID, before, version)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to save spec: %v", err)
+	}
+	if version == nil {
+		return nil, notFound("Version")
+	}
+
+	s.publish(ctx, domain.EventVersionSpecUpdated, &existing.Service, version)
+	return version, nil
+}
+
+// GetVersionSpec retrieves the OpenAPI spec of a version as uploaded
+func (s *ServiceService) GetVersionSpec(ctx context.Context, serviceID, versionID int) (_ *domain.VersionSpec, err error) {
+	ctx, span := tracing.Start(ctx, "ServiceService.GetVersionSpec")
+	defer func() { tracing.End(span, err) }()
+
+	if _, _, err := s.findVersion(ctx, serviceID, versionID); err != nil {
+		return nil, err
+	}
+	spec, err := s.repo.GetVersionSpec(ctx, serviceID, versionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get spec: %v", err)
+	}
+	if spec == nil {
+		return nil, notFound("Spec")
+	}
+	return spec, nil
+}
+
+// DeleteVersionSpec removes the OpenAPI spec of a version
+func (s *ServiceService) DeleteVersionSpec(ctx context.Context, serviceID, versionID int) (err error) {
+	ctx, span := tracing.Start(ctx, "ServiceService.DeleteVersionSpec")
+	defer func() { tracing.End(span, err) }()
+
+	existing, before, err := s.findVersion(ctx, serviceID, versionID)
+	if err != nil {
+		return err
+	}
+	var version *domain.ServiceVersion
+	err = s.repo.InTx(ctx, func(ctx context.Context) error {
+		var err error
+		if version, err = s.repo.DeleteVersionSpec(ctx, serviceID, versionID); err != nil || version == nil {
+			return err
+		}
+		return s.recordAudit(ctx, domain.AuditActionUpdate, domain.AuditResourceVersion, versionID, before, version)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete spec: %v", err)
+	}
+	if version == nil {
+		return notFound("Spec")
+	}
+
+	s.publish(ctx, domain.EventVersionSpecUpdated, &existing.Service, version)
+	return nil
+}
+
+// GetVersionDocs builds the documentation page of the OpenAPI spec of a
+// version
+func (s *ServiceService) GetVersionDocs(ctx context.Context, serviceID, versionID int) (_ *openapi.DocsPage, err error) {
+	ctx, span := tracing.Start(ctx, "ServiceService.GetVersionDocs")
+	defer func() { tracing.End(span, err) }()
+
+	existing, version, doc, err := s.versionDoc(ctx, serviceID, versionID)
+	if err != nil {
+		return nil, err
+	}
+	page := openapi.NewDocsPage(existing.Name, version.Version, doc)
+	return &page, nil
+}
+
+// GetVersionPostman converts the OpenAPI spec of a version into a Postman
+// collection
+func (s *ServiceService) GetVersionPostman(ctx context.Context, serviceID, versionID int) (_ *openapi.PostmanCollection, err error) {
+	ctx, span := tracing.Start(ctx, "ServiceService.GetVersionPostman")
+	defer func() { tracing.End(span, err) }()
+
+	existing, version, doc, err := s.versionDoc(ctx, serviceID, versionID)
+	if err != nil {
+		return nil, err
+	}
+	collection := openapi.NewPostmanCollection(existing.Name, version.Version, doc)
+	return &collection, nil
+}
+
+// versionDoc retrieves a version of a service of the organization and
+// parses its spec
+func (s *ServiceService) versionDoc(ctx context.Context, serviceID, versionID int) (*domain.ServiceWithVersions, *domain.ServiceVersion, *openapi3.T, error) {
+	existing, version, err := s.findVersion(ctx, serviceID, versionID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	spec, err := s.repo.GetVersionSpec(ctx, serviceID, versionID)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to get spec: %v", err)
+	}
+	if spec == nil {
+		return nil, nil, nil, notFound("Spec")
+	}
+	// Specs were validated on upload
+	doc, err := openapi.Parse(spec.Content)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to parse spec: %v", err)
+	}
+	return existing, version, doc, nil
+}
+
+// findVersion retrieves a service of the organization and one of its
+// versions
+func (s *ServiceService) findVersion(ctx context.Context, serviceID, versionID int) (*domain.ServiceWithVersions, *domain.ServiceVersion, error) {
+	if serviceID <= 0 {
+		return nil, nil, invalidf("invalid service ID: %d", serviceID)
+	}
+	if versionID <= 0 {
+		return nil, nil, invalidf("invalid version ID: %d", versionID)
+	}
+
+	existing, err := s.repo.GetByID(ctx, serviceID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get service: %v", err)
+	}
+	if existing == nil {
+		return nil, nil, notFound("Service")
+	}
+	i := slices.IndexFunc(existing.Versions, func(v domain.ServiceVersion) bool { return v.ID == versionID })
+	if i < 0 {
+		return nil, nil, notFound("Version")
+	}
+	return existing, &existing.Versions[i], nil
+}
+
+// CompareVersions diffs the OpenAPI specs of two versions of a service,
+// given by version string, listing the endpoints added, removed and changed
+// from one to the other and whether clients of from may break
+func (s *ServiceService) CompareVersions(ctx context.Context, serviceID int, from, to string) (_ *domain.SpecComparison, err error) {
+	ctx, span := tracing.Start(ctx, "ServiceService.CompareVersions")
+	defer func() { tracing.End(span, err) }()
+
+	if serviceID <= 0 {
+		return nil, invalidf("invalid service ID: %d", serviceID)
+	}
+	existing, err := s.repo.GetByID(ctx, serviceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get service: %v", err)
+	}
+	if existing == nil {
+		return nil, notFound("Service")
+	}
+
+	var docs [2]*openapi3.T
+	for i, name := range []string{from, to} {
+		j := slices.IndexFunc(existing.Versions, func(v domain.ServiceVersion) bool { return v.Version == strings.TrimSpace(name) })
+		if j < 0 {
+			return nil, notFound("Version")
+		}
+		spec, err := s.repo.GetVersionSpec(ctx, serviceID, existing.Versions[j].ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get spec: %v", err)
+		}
+		if spec == nil {
+			return nil, notFound("Spec")
+		}
+		if docs[i], err = openapi.Parse(spec.Content); err != nil {
+			return nil, fmt.Errorf("failed to parse spec: %v", err)
+		}
+	}
+
+	comparison := openapi.Compare(docs[0], docs[1])
+	comparison.From, comparison.To = strings.TrimSpace(from), strings.TrimSpace(to)
+	return &comparison, nil
+}
@@ -0,0 +1,34 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"com.kong.connect/domain"
+	"com.kong.connect/tracing"
+)
+
+const (
+	defaultRecentLimit = 5
+	maxRecentLimit     = 50
+)
+
+// GetStats returns aggregate catalog statistics with up to recentLimit recently updated services
+func (s *ServiceService) GetStats(ctx context.Context, recentLimit int) (_ *domain.CatalogStats, err error) {
+	ctx, span := tracing.Start(ctx, "ServiceService.GetStats")
+	defer func() { tracing.End(span, err) }()
+
+	if recentLimit <= 0 {
+		recentLimit = defaultRecentLimit
+	}
+	if recentLimit > maxRecentLimit {
+		recentLimit = maxRecentLimit
+	}
+
+	stats, err := s.repo.GetStats(ctx, recentLimit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get stats: %v", err)
+	}
+
+	return stats, nil
+}
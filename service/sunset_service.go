@@ -0,0 +1,257 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"slices"
+	"time"
+
+	"com.kong.connect/audit"
+	"com.kong.connect/domain"
+	"com.kong.connect/logging"
+	"com.kong.connect/tenant"
+	"com.kong.connect/tracing"
+)
+
+// SunsetInterval is how often the sunsets due are applied
+const SunsetInterval = time.Hour
+
+// sunsetPrincipal is the principal of the audit entries of the changes
+// sunsets make
+const sunsetPrincipal = "sunset"
+
+// SetSunset schedules the deprecation and archival of a service, or of one
+// of its versions when versionID is not 0, replacing the schedule it had.
+// The steps due are applied by ApplySunsets.
+func (s *ServiceService) SetSunset(ctx context.Context, serviceID, versionID int, input domain.SunsetInput) (_ *domain.Sunset, err error) {
+	ctx, span := tracing.Start(ctx, "ServiceService.SetSunset")
+	defer func() { tracing.End(span, err) }()
+
+	if input.ArchiveAt.IsZero() {
+		return nil, invalidf("invalid sunset: archive_at is required")
+	}
+	if !input.ArchiveAt.After(time.Now()) {
+		return nil, invalidf("invalid sunset: archive_at must be in the future")
+	}
+	if input.DeprecateAt != nil && !input.DeprecateAt.Before(input.ArchiveAt) {
+		return nil, invalidf("invalid sunset: deprecate_at must be before archive_at")
+	}
+	// Stored to the second
+	input.ArchiveAt = input.ArchiveAt.UTC().Truncate(time.Second)
+	if input.DeprecateAt != nil {
+		deprecateAt := input.DeprecateAt.UTC().Truncate(time.Second)
+		input.DeprecateAt = &deprecateAt
+	}
+
+	existing, version, err := s.findSunsetTarget(ctx, serviceID, versionID)
+	if err != nil {
+		return nil, err
+	}
+	sunset := &domain.Sunset{DeprecateAt: input.DeprecateAt, ArchiveAt: input.ArchiveAt, Status: domain.StatusActive}
+	var found bool
+	err = s.repo.InTx(ctx, func(ctx context.Context) error {
+		var err error
+		if found, err = s.repo.SetSunset(ctx, serviceID, versionID, input); err != nil || !found {
+			return err
+		}
+		return s.auditSunset(ctx, existing, version, sunset)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to set sunset: %v", err)
+	}
+	if !found {
+		return nil, notFound("Service")
+	}
+
+	s.publishSunset(ctx, existing, version, sunset)
+	return sunset, nil
+}
+
+// DeleteSunset cancels the sunset of a service, or of one of its versions
+// when versionID is not 0. The statuses it applied stay.
+func (s *ServiceService) DeleteSunset(ctx context.Context, serviceID, versionID int) (err error) {
+	ctx, span := tracing.Start(ctx, "ServiceService.DeleteSunset")
+	defer func() { tracing.End(span, err) }()
+
+	existing, version, err := s.findSunsetTarget(ctx, serviceID, versionID)
+	if err != nil {
+		return err
+	}
+	var deleted bool
+	err = s.repo.InTx(ctx, func(ctx context.Context) error {
+		var err error
+		if deleted, err = s.repo.DeleteSunset(ctx, serviceID, versionID); err != nil || !deleted {
+			return err
+		}
+		return s.auditSunset(ctx, existing, version, nil)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete sunset: %v", err)
+	}
+	if !deleted {
+		return notFound("Sunset")
+	}
+
+	s.publishSunset(ctx, existing, version, nil)
+	return nil
+}
+
+// ApplySunsets takes the steps of the sunsets of every organization due at
+// now: it deprecates and archives the services and versions whose time has
+// come and publishes a reminder when a sunset gets within 30, 7 and 1 days.
+// It is run by a job every SunsetInterval.
+func (s *ServiceService) ApplySunsets(ctx context.Context) (err error) {
+	ctx, span := tracing.Start(ctx, "ServiceService.ApplySunsets")
+	defer func() { tracing.End(span, err) }()
+
+	now := time.Now()
+	due, err := s.repo.DueSunsets(ctx, now)
+	if err != nil {
+		return fmt.Errorf("failed to get sunsets: %v", err)
+	}
+	ctx = audit.NewContext(ctx, audit.Actor{Principal: sunsetPrincipal})
+
+	failed := 0
+	for _, sunset := range due {
+		if err := s.applySunset(tenant.NewContext(ctx, sunset.OrgID), sunset, now); err != nil {
+			logging.Component(nil, "service").ErrorContext(ctx, "failed to apply sunset", "org_id", sunset.OrgID,
+				"service_id", sunset.ServiceID, "version_id", sunset.VersionID, "error", err)
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d sunsets failed", failed, len(due))
+	}
+	return nil
+}
+
+// applySunset takes the steps of a sunset due at now
+func (s *ServiceService) applySunset(ctx context.Context, due domain.DueSunset, now time.Time) error {
+	status, reminded := sunsetSteps(due, now)
+	if status == due.Status && reminded == due.RemindedDays {
+		return nil
+	}
+
+	existing, version, err := s.findSunsetTarget(ctx, due.ServiceID, due.VersionID)
+	if err != nil {
+		return err
+	}
+	sunset := due.Sunset
+	sunset.Status = status
+	var advanced bool
+	err = s.repo.InTx(ctx, func(ctx context.Context) error {
+		var err error
+		if advanced, err = s.repo.AdvanceSunset(ctx, due, status, reminded); err != nil || !advanced {
+			return err
+		}
+		if status == due.Status {
+			return nil
+		}
+		return s.auditSunset(ctx, existing, version, &sunset)
+	})
+	if err != nil {
+		return err
+	}
+	// Changed since it was due; the next run takes the new schedule
+	if !advanced {
+		return nil
+	}
+
+	if status != due.Status {
+		s.publishSunset(ctx, existing, version, &sunset)
+	}
+	if reminded != due.RemindedDays {
+		service := existing.Service
+		if version == nil {
+			service.Sunset = &sunset
+			s.publish(ctx, domain.EventServiceSunsetReminder, &service, nil)
+		} else {
+			after := *version
+			after.Sunset = &sunset
+			s.publish(ctx, domain.EventVersionSunsetReminder, &service, &after)
+		}
+	}
+	return nil
+}
+
+// sunsetSteps returns the status a sunset reaches at now and the reminder
+// due, in days ahead of it: the closest reminder not published yet
+func sunsetSteps(due domain.DueSunset, now time.Time) (string, int) {
+	status, reminded := due.Status, due.RemindedDays
+	switch {
+	case !due.ArchiveAt.After(now):
+		return domain.StatusArchived, reminded
+	case status == domain.StatusActive && due.DeprecateAt != nil && !due.DeprecateAt.After(now):
+		status = domain.StatusDeprecated
+	}
+
+	daysLeft := int(math.Ceil(due.ArchiveAt.Sub(now).Hours() / 24))
+	for _, days := range slices.Backward(domain.SunsetReminderDays) {
+		if daysLeft <= days {
+			if reminded == 0 || days < reminded {
+				reminded = days
+			}
+			break
+		}
+	}
+	return status, reminded
+}
+
+// findSunsetTarget retrieves a service of the organization and, when
+// versionID is not 0, one of its versions
+func (s *ServiceService) findSunsetTarget(ctx context.Context, serviceID, versionID int) (*domain.ServiceWithVersions, *domain.ServiceVersion, error) {
+	if versionID == 0 {
+		if serviceID <= 0 {
+			return nil, nil, invalidf("invalid service ID: %d", serviceID)
+		}
+		existing, err := s.repo.GetByID(ctx, serviceID)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get service: %v", err)
+		}
+		if existing == nil {
+			return nil, nil, notFound("Service")
+		}
+		return existing, nil, nil
+	}
+	return s.findVersion(ctx, serviceID, versionID)
+}
+
+// sunsetApplied returns the service, or the version when version is not nil,
+// as the change of its sunset to sunset leaves it. A service the sunset
+// deprecates or archives gets that status unless it is archived already.
+func sunsetApplied(existing *domain.ServiceWithVersions, version *domain.ServiceVersion, sunset *domain.Sunset) (*domain.Service, *domain.ServiceVersion) {
+	if version != nil {
+		after := *version
+		after.Sunset = sunset
+		return nil, &after
+	}
+
+	after := existing.Service
+	after.Sunset = sunset
+	if sunset != nil && sunset.Status != domain.StatusActive && after.Status != sunset.Status && after.Status != domain.StatusArchived {
+		after.Status = sunset.Status
+	}
+	return &after, nil
+}
+
+// auditSunset audits the change of the sunset of a service, or of a version
+// when version is not nil, to sunset
+func (s *ServiceService) auditSunset(ctx context.Context, existing *domain.ServiceWithVersions, version *domain.ServiceVersion, sunset *domain.Sunset) error {
+	service, afterVersion := sunsetApplied(existing, version, sunset)
+	if version != nil {
+		return s.recordAudit(ctx, domain.AuditActionUpdate, domain.AuditResourceVersion, version.ID, version, afterVersion)
+	}
+	return s.recordAudit(ctx, domain.AuditActionUpdate, domain.AuditResourceService, existing.ID, &existing.Service, service)
+}
+
+// publishSunset publishes the change of the sunset of a service, or of a
+// version when version is not nil, to sunset
+func (s *ServiceService) publishSunset(ctx context.Context, existing *domain.ServiceWithVersions, version *domain.ServiceVersion, sunset *domain.Sunset) {
+	service, afterVersion := sunsetApplied(existing, version, sunset)
+	if version != nil {
+		s.publish(ctx, domain.EventVersionSunsetUpdated, &existing.Service, afterVersion)
+		return
+	}
+	s.publishUpdate(ctx, &existing.Service, service)
+}
@@ -0,0 +1,244 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"com.kong.connect/domain"
+	"com.kong.connect/repository"
+	"com.kong.connect/tracing"
+)
+
+// TransferOwnership requests to hand a service over to another owner. The
+// owner of the service or an admin requests it, and the transfer stays
+// pending until the receiving owner confirms it, unless an admin overrides
+// the confirmation.
+func (s *ServiceService) TransferOwnership(ctx context.Context, serviceID int, username string, admin bool, input domain.TransferInput) (_ *domain.OwnershipTransfer, err error) {
+	ctx, span := tracing.Start(ctx, "ServiceService.TransferOwnership")
+	defer func() { tracing.End(span, err) }()
+
+	if serviceID <= 0 {
+		return nil, invalidf("invalid service ID: %d", serviceID)
+	}
+	input.Owner = strings.TrimSpace(input.Owner)
+	input.Reason = strings.TrimSpace(input.Reason)
+	if input.Owner == "" {
+		return nil, invalidf("invalid transfer: owner is required")
+	}
+	if len(input.Owner) > maxNameLength {
+		return nil, invalidf("invalid transfer: owner must be at most %d characters", maxNameLength)
+	}
+	if len(input.Reason) > maxDescriptionLength {
+		return nil, invalidf("invalid transfer: reason must be at most %d characters", maxDescriptionLength)
+	}
+
+	existing, err := s.repo.GetByID(ctx, serviceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get service: %v", err)
+	}
+	if existing == nil {
+		return nil, notFound("Service")
+	}
+	if existing.Owner != username && !admin {
+		return nil, forbiddenf("only the owner of a service or an admin can transfer it")
+	}
+	if input.Override && !admin {
+		return nil, forbiddenf("only an admin can transfer a service without confirmation")
+	}
+	if input.Owner == existing.Owner {
+		return nil, invalidf("invalid transfer: the service is already owned by %s", input.Owner)
+	}
+	// Only a user can confirm the transfer; admins may hand a service over
+	// to a team without confirmation
+	if !input.Override {
+		known, err := s.isEnabledUser(ctx, input.Owner)
+		if err != nil {
+			return nil, err
+		}
+		if !known {
+			return nil, unprocessablef("%s is not a user who can confirm the transfer; an admin can transfer the service without confirmation", input.Owner)
+		}
+	}
+
+	now := time.Now().UTC()
+	transfer := domain.OwnershipTransfer{
+		ServiceID:   serviceID,
+		FromOwner:   existing.Owner,
+		ToOwner:     input.Owner,
+		Reason:      input.Reason,
+		RequestedBy: username,
+		Status:      domain.TransferPending,
+		RequestedAt: now,
+	}
+	if input.Override {
+		transfer.Status = domain.TransferCompleted
+		transfer.ResolvedBy = username
+		transfer.ResolvedAt = &now
+	}
+
+	var created *domain.OwnershipTransfer
+	var after *domain.Service
+	err = s.repo.InTx(ctx, func(ctx context.Context) error {
+		var err error
+		if created, err = s.repo.CreateTransfer(ctx, transfer); err != nil || created == nil {
+			return err
+		}
+		if err := s.recordAudit(ctx, domain.AuditActionCreate, domain.AuditResourceTransfer, created.ID, nil, created); err != nil {
+			return err
+		}
+		if created.Status == domain.TransferCompleted {
+			after, err = s.handOver(ctx, &existing.Service, created)
+		}
+		return err
+	})
+	if err != nil {
+		if errors.Is(err, repository.ErrDuplicate) {
+			return nil, conflict("transfer already pending", "A transfer of the service is already pending")
+		}
+		return nil, fmt.Errorf("failed to create transfer: %v", err)
+	}
+	if created == nil {
+		return nil, notFound("Service")
+	}
+
+	if after != nil {
+		s.publishUpdate(ctx, &existing.Service, after)
+	}
+	return created, nil
+}
+
+// ConfirmTransfer completes the pending ownership transfer of a service,
+// confirmed by its receiving owner or an admin
+func (s *ServiceService) ConfirmTransfer(ctx context.Context, serviceID int, username string, admin bool) (_ *domain.OwnershipTransfer, err error) {
+	ctx, span := tracing.Start(ctx, "ServiceService.ConfirmTransfer")
+	defer func() { tracing.End(span, err) }()
+
+	existing, pending, err := s.findPendingTransfer(ctx, serviceID)
+	if err != nil {
+		return nil, err
+	}
+	if pending.ToOwner != username && !admin {
+		return nil, forbiddenf("only the receiving owner or an admin can confirm a transfer")
+	}
+	if existing.Owner != pending.FromOwner {
+		return nil, conflict("transfer outdated", "The service changed owner since the transfer was requested")
+	}
+
+	return s.resolveTransfer(ctx, existing, pending, domain.TransferCompleted, username)
+}
+
+// DeclineTransfer declines the pending ownership transfer of a service. Its
+// receiving owner declines it, or its requester or the owner of the service
+// withdraws it; admins may do either.
+func (s *ServiceService) DeclineTransfer(ctx context.Context, serviceID int, username string, admin bool) (_ *domain.OwnershipTransfer, err error) {
+	ctx, span := tracing.Start(ctx, "ServiceService.DeclineTransfer")
+	defer func() { tracing.End(span, err) }()
+
+	existing, pending, err := s.findPendingTransfer(ctx, serviceID)
+	if err != nil {
+		return nil, err
+	}
+	if username != pending.ToOwner && username != pending.RequestedBy && username != existing.Owner && !admin {
+		return nil, forbiddenf("only the parties to a transfer or an admin can decline it")
+	}
+
+	return s.resolveTransfer(ctx, existing, pending, domain.TransferDeclined, username)
+}
+
+// GetTransfers retrieves the ownership transfers of a service, newest first
+func (s *ServiceService) GetTransfers(ctx context.Context, serviceID int) (_ *domain.TransferListResponse, err error) {
+	ctx, span := tracing.Start(ctx, "ServiceService.GetTransfers")
+	defer func() { tracing.End(span, err) }()
+
+	if serviceID <= 0 {
+		return nil, invalidf("invalid service ID: %d", serviceID)
+	}
+	existing, err := s.repo.GetByID(ctx, serviceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get service: %v", err)
+	}
+	if existing == nil {
+		return nil, notFound("Service")
+	}
+	transfers, err := s.repo.GetTransfers(ctx, serviceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transfers: %v", err)
+	}
+	return &domain.TransferListResponse{Transfers: transfers}, nil
+}
+
+// findPendingTransfer retrieves a service of the organization and its
+// pending ownership transfer
+func (s *ServiceService) findPendingTransfer(ctx context.Context, serviceID int) (*domain.ServiceWithVersions, *domain.OwnershipTransfer, error) {
+	if serviceID <= 0 {
+		return nil, nil, invalidf("invalid service ID: %d", serviceID)
+	}
+	existing, err := s.repo.GetByID(ctx, serviceID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get service: %v", err)
+	}
+	if existing == nil {
+		return nil, nil, notFound("Service")
+	}
+	pending, err := s.repo.GetPendingTransfer(ctx, serviceID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get transfer: %v", err)
+	}
+	if pending == nil {
+		return nil, nil, notFound("Transfer")
+	}
+	return existing, pending, nil
+}
+
+// resolveTransfer completes or declines a pending transfer
+func (s *ServiceService) resolveTransfer(ctx context.Context, existing *domain.ServiceWithVersions, pending *domain.OwnershipTransfer, status, username string) (*domain.OwnershipTransfer, error) {
+	now := time.Now().UTC()
+	resolved := *pending
+	resolved.Status = status
+	resolved.ResolvedBy = username
+	resolved.ResolvedAt = &now
+
+	var found bool
+	var after *domain.Service
+	err := s.repo.InTx(ctx, func(ctx context.Context) error {
+		var err error
+		if found, err = s.repo.ResolveTransfer(ctx, resolved); err != nil || !found {
+			return err
+		}
+		if err := s.recordAudit(ctx, domain.AuditActionUpdate, domain.AuditResourceTransfer, resolved.ID, pending, &resolved); err != nil {
+			return err
+		}
+		if status == domain.TransferCompleted {
+			after, err = s.handOver(ctx, &existing.Service, &resolved)
+		}
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve transfer: %v", err)
+	}
+	// Resolved concurrently
+	if !found {
+		return nil, notFound("Transfer")
+	}
+
+	if after != nil {
+		s.publishUpdate(ctx, &existing.Service, after)
+	}
+	return &resolved, nil
+}
+
+// handOver audits the change of owner of a service by a completed transfer
+// and returns the service after it, published by the caller once committed,
+// which emails both owners like any change of owner
+func (s *ServiceService) handOver(ctx context.Context, before *domain.Service, transfer *domain.OwnershipTransfer) (*domain.Service, error) {
+	after := *before
+	after.Owner = transfer.ToOwner
+	after.UpdatedAt = *transfer.ResolvedAt
+	if err := s.recordAudit(ctx, domain.AuditActionUpdate, domain.AuditResourceService, before.ID, before, &after); err != nil {
+		return nil, err
+	}
+	return &after, nil
+}
@@ -0,0 +1,150 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"strings"
+
+	"com.kong.connect/domain"
+	"com.kong.connect/i18n"
+	"com.kong.connect/tracing"
+)
+
+// SetTranslation sets the description of a service in a language other than
+// the one it is written in, replacing the one it had
+func (s *ServiceService) SetTranslation(ctx context.Context, serviceID int, language string, input domain.TranslationInput) (_ *domain.Translation, err error) {
+	ctx, span := tracing.Start(ctx, "ServiceService.SetTranslation")
+	defer func() { tracing.End(span, err) }()
+
+	if serviceID <= 0 {
+		return nil, invalidf("invalid service ID: %d", serviceID)
+	}
+	if err := validateLanguage(language); err != nil {
+		return nil, err
+	}
+	description := strings.TrimSpace(input.Description)
+	if description == "" || len(description) > maxDescriptionLength {
+		return nil, invalidf("invalid translation: description is required and must be at most %d characters", maxDescriptionLength)
+	}
+
+	existing, err := s.repo.GetByID(ctx, serviceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get service: %v", err)
+	}
+	if existing == nil {
+		return nil, notFound("Service")
+	}
+	translation := &domain.Translation{Language: language, Description: description}
+	if existing.Translations[language] == description {
+		return translation, nil
+	}
+
+	after := existing.Service
+	after.Translations = maps.Clone(existing.Translations)
+	if after.Translations == nil {
+		after.Translations = map[string]string{}
+	}
+	after.Translations[language] = description
+	found, err := s.auditedWrite(ctx, func(ctx context.Context) (bool, error) {
+		return s.repo.SetTranslation(ctx, serviceID, language, description)
+	}, domain.AuditActionUpdate, domain.AuditResourceService, serviceID, &existing.Service, &after)
+	if err != nil {
+		return nil, fmt.Errorf("failed to set translation: %v", err)
+	}
+	if !found {
+		return nil, notFound("Service")
+	}
+
+	s.publishUpdate(ctx, &existing.Service, &after)
+	return translation, nil
+}
+
+// DeleteTranslation removes the description of a service in a language
+func (s *ServiceService) DeleteTranslation(ctx context.Context, serviceID int, language string) (err error) {
+	ctx, span := tracing.Start(ctx, "ServiceService.DeleteTranslation")
+	defer func() { tracing.End(span, err) }()
+
+	if serviceID <= 0 {
+		return invalidf("invalid service ID: %d", serviceID)
+	}
+	if err := validateLanguage(language); err != nil {
+		return err
+	}
+	existing, err := s.repo.GetByID(ctx, serviceID)
+	if err != nil {
+		return fmt.Errorf("failed to get service: %v", err)
+	}
+	if existing == nil {
+		return notFound("Service")
+	}
+
+	after := existing.Service
+	after.Translations = maps.Clone(existing.Translations)
+	delete(after.Translations, language)
+	if len(after.Translations) == 0 {
+		after.Translations = nil
+	}
+	deleted, err := s.auditedWrite(ctx, func(ctx context.Context) (bool, error) {
+		return s.repo.DeleteTranslation(ctx, serviceID, language)
+	}, domain.AuditActionUpdate, domain.AuditResourceService, serviceID, &existing.Service, &after)
+	if err != nil {
+		return fmt.Errorf("failed to delete translation: %v", err)
+	}
+	if !deleted {
+		return notFound("Translation")
+	}
+
+	s.publishUpdate(ctx, &existing.Service, &after)
+	return nil
+}
+
+// validateLanguage checks that descriptions can be translated into language
+func validateLanguage(language string) error {
+	if language == i18n.Default {
+		return invalidf("invalid translation: %s is the language of the description itself", language)
+	}
+	if !i18n.Supported(language) {
+		return invalidf("invalid translation: unknown language %q, expected one of %s", language, strings.Join(i18n.Languages[1:], ", "))
+	}
+	return nil
+}
+
+// localizeService returns service with its description in the language of
+// the client, when translated into it. Services may be shared by caches, so
+// a translated service is a copy.
+func localizeService(ctx context.Context, service *domain.ServiceWithVersions) *domain.ServiceWithVersions {
+	translated, ok := service.Translations[i18n.FromContext(ctx)]
+	if !ok {
+		return service
+	}
+	localized := *service
+	localized.Description = translated
+	return &localized
+}
+
+// localizeServices returns a listing with the descriptions of its services
+// in the language of the client, copying it when any is translated
+func localizeServices(ctx context.Context, response *domain.ServiceListResponse) *domain.ServiceListResponse {
+	lang := i18n.FromContext(ctx)
+	if lang == i18n.Default {
+		return response
+	}
+	var localized *domain.ServiceListResponse
+	for i := range response.Services {
+		translated, ok := response.Services[i].Translations[lang]
+		if !ok {
+			continue
+		}
+		if localized == nil {
+			copied := *response
+			copied.Services = append([]domain.ServiceWithVersions(nil), response.Services...)
+			localized = &copied
+		}
+		localized.Services[i].Description = translated
+	}
+	if localized == nil {
+		return response
+	}
+	return localized
+}
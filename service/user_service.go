@@ -0,0 +1,248 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"com.kong.connect/domain"
+	"com.kong.connect/repository"
+	"com.kong.connect/tracing"
+)
+
+const (
+	maxUsernameLength  = 100
+	maxTokenNameLength = 100
+	// tokenBytes is the entropy of issued tokens
+	tokenBytes = 32
+	// tokenPrefix makes issued tokens recognizable, e.g. by secret scanners
+	tokenPrefix = "kc_"
+)
+
+// usernamePattern keeps usernames printable in logs and audit entries
+var usernamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._@-]*$`)
+
+// WithStaticUsers makes the users of static tokens, which have no row in the
+// users table, known to the service, e.g. as the receiving owners of
+// ownership transfers
+func WithStaticUsers(known func(username string) bool) Option {
+	return func(s *ServiceService) {
+		s.staticUser = known
+	}
+}
+
+// CreateUser validates and stores a new user
+func (s *ServiceService) CreateUser(ctx context.Context, input domain.UserInput) (_ *domain.User, err error) {
+	ctx, span := tracing.Start(ctx, "ServiceService.CreateUser")
+	defer func() { tracing.End(span, err) }()
+
+	input.Username = strings.TrimSpace(input.Username)
+	if !usernamePattern.MatchString(input.Username) || len(input.Username) > maxUsernameLength {
+		return nil, invalidf("invalid user: username must be 1 to %d letters, digits or ._@- characters", maxUsernameLength)
+	}
+	roles := []string{}
+	for _, role := range input.Roles {
+		role = strings.ToLower(strings.TrimSpace(role))
+		if role != domain.RoleAdmin && role != domain.RoleViewer {
+			return nil, invalidf("invalid user: unknown role %q", role)
+		}
+		if !slices.Contains(roles, role) {
+			roles = append(roles, role)
+		}
+	}
+	if len(roles) == 0 {
+		return nil, invalidf("invalid user: at least one role is required")
+	}
+	slices.Sort(roles)
+	input.Roles = roles
+
+	var user *domain.User
+	err = s.repo.InTx(ctx, func(ctx context.Context) error {
+		var err error
+		if user, err = s.repo.CreateUser(ctx, input); err != nil {
+			return err
+		}
+		return s.recordAudit(ctx, domain.AuditActionCreate, domain.AuditResourceUser, user.ID, nil, user)
+	})
+	if err != nil {
+		if errors.Is(err, repository.ErrDuplicate) {
+			return nil, conflict("user already exists", "User already exists")
+		}
+		return nil, fmt.Errorf("failed to create user: %v", err)
+	}
+
+	return user, nil
+}
+
+// ListUsers retrieves every user
+func (s *ServiceService) ListUsers(ctx context.Context) (_ *domain.UserListResponse, err error) {
+	ctx, span := tracing.Start(ctx, "ServiceService.ListUsers")
+	defer func() { tracing.End(span, err) }()
+
+	users, err := s.repo.ListUsers(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list users: %v", err)
+	}
+	return &domain.UserListResponse{Users: users}, nil
+}
+
+// DisableUser stops every token of a user from authenticating
+func (s *ServiceService) DisableUser(ctx context.Context, username string) (_ *domain.User, err error) {
+	ctx, span := tracing.Start(ctx, "ServiceService.DisableUser")
+	defer func() { tracing.End(span, err) }()
+
+	existing, err := s.repo.GetUserByName(ctx, username)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %v", err)
+	}
+	if existing == nil {
+		return nil, notFound("User")
+	}
+	if existing.Disabled {
+		return existing, nil
+	}
+
+	disabled := *existing
+	disabled.Disabled = true
+	err = s.repo.InTx(ctx, func(ctx context.Context) error {
+		if err := s.repo.SetUserDisabled(ctx, existing.ID, true); err != nil {
+			return err
+		}
+		return s.recordAudit(ctx, domain.AuditActionUpdate, domain.AuditResourceUser, existing.ID, existing, &disabled)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to disable user: %v", err)
+	}
+
+	return &disabled, nil
+}
+
+// IssueToken creates a bearer token for an enabled user, acting for the
+// organization of ctx. Only a hash is stored, so the returned secret cannot
+// be retrieved again.
+func (s *ServiceService) IssueToken(ctx context.Context, input domain.TokenInput) (_ *domain.IssuedToken, err error) {
+	ctx, span := tracing.Start(ctx, "ServiceService.IssueToken")
+	defer func() { tracing.End(span, err) }()
+
+	input.Name = strings.TrimSpace(input.Name)
+	if len(input.Name) > maxTokenNameLength {
+		return nil, invalidf("invalid token: name must be at most %d characters", maxTokenNameLength)
+	}
+
+	user, err := s.repo.GetUserByName(ctx, strings.TrimSpace(input.Username))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %v", err)
+	}
+	if user == nil {
+		return nil, notFound("User")
+	}
+	if user.Disabled {
+		return nil, invalidf("invalid token: user %s is disabled", user.Username)
+	}
+
+	raw := make([]byte, tokenBytes)
+	if _, err := rand.Read(raw); err != nil {
+		return nil, fmt.Errorf("failed to generate token: %v", err)
+	}
+	secret := tokenPrefix + base64.RawURLEncoding.EncodeToString(raw)
+
+	var token *domain.APIToken
+	err = s.repo.InTx(ctx, func(ctx context.Context) error {
+		var err error
+		if token, err = s.repo.CreateToken(ctx, user.ID, input.Name, hashToken(secret)); err != nil {
+			return err
+		}
+		return s.recordAudit(ctx, domain.AuditActionCreate, domain.AuditResourceToken, token.ID, nil, token)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create token: %v", err)
+	}
+
+	return &domain.IssuedToken{APIToken: *token, Token: secret}, nil
+}
+
+// ListTokens retrieves every token issued in the organization without its secret
+func (s *ServiceService) ListTokens(ctx context.Context) (_ *domain.TokenListResponse, err error) {
+	ctx, span := tracing.Start(ctx, "ServiceService.ListTokens")
+	defer func() { tracing.End(span, err) }()
+
+	tokens, err := s.repo.ListTokens(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tokens: %v", err)
+	}
+	return &domain.TokenListResponse{Tokens: tokens}, nil
+}
+
+// RevokeToken stops a token from authenticating
+func (s *ServiceService) RevokeToken(ctx context.Context, id int) (err error) {
+	ctx, span := tracing.Start(ctx, "ServiceService.RevokeToken")
+	defer func() { tracing.End(span, err) }()
+
+	if id <= 0 {
+		return invalidf("invalid token ID: %d", id)
+	}
+
+	var token *domain.APIToken
+	err = s.repo.InTx(ctx, func(ctx context.Context) error {
+		var err error
+		if token, err = s.repo.RevokeToken(ctx, id); err != nil || token == nil {
+			return err
+		}
+		return s.recordAudit(ctx, domain.AuditActionDelete, domain.AuditResourceToken, id, token, nil)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to revoke token: %v", err)
+	}
+	if token == nil {
+		return notFound("Token")
+	}
+
+	return nil
+}
+
+// AuthenticateToken resolves an issued bearer token to its user and
+// organization, or nil when the token is unknown or revoked or the user is
+// disabled
+func (s *ServiceService) AuthenticateToken(ctx context.Context, token string) (_ *domain.TokenGrant, err error) {
+	ctx, span := tracing.Start(ctx, "ServiceService.AuthenticateToken")
+	defer func() { tracing.End(span, err) }()
+
+	if !strings.HasPrefix(token, tokenPrefix) {
+		return nil, nil
+	}
+	grant, err := s.repo.GetTokenUser(ctx, hashToken(token))
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up token: %v", err)
+	}
+	if grant == nil || grant.User.Disabled {
+		return nil, nil
+	}
+	return grant, nil
+}
+
+// hashToken returns the stored form of a token; tokens carry 256 random
+// bits, so a plain SHA-256 is enough
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// isEnabledUser reports whether username is a user who can authenticate:
+// a user of a static token or an enabled user of the users table
+func (s *ServiceService) isEnabledUser(ctx context.Context, username string) (bool, error) {
+	if s.staticUser != nil && s.staticUser(username) {
+		return true, nil
+	}
+	user, err := s.repo.GetUserByName(ctx, username)
+	if err != nil {
+		return false, fmt.Errorf("failed to get user: %v", err)
+	}
+	return user != nil && !user.Disabled, nil
+}
@@ -0,0 +1,95 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+
+	"com.kong.connect/domain"
+	"com.kong.connect/semver"
+	"com.kong.connect/tracing"
+)
+
+// GetServiceVersions retrieves one page of the versions of a service, sorted by
+// semver precedence or creation time
+func (s *ServiceService) GetServiceVersions(ctx context.Context, query domain.VersionQuery) (_ *domain.VersionListResponse, err error) {
+	ctx, span := tracing.Start(ctx, "ServiceService.GetServiceVersions")
+	defer func() { tracing.End(span, err) }()
+
+	if query.ServiceID <= 0 {
+		return nil, invalidf("invalid service ID: %d", query.ServiceID)
+	}
+
+	if query.Page <= 0 {
+		query.Page = 1
+	}
+	if query.PageSize <= 0 {
+		query.PageSize = 12
+	}
+	if query.PageSize > 100 {
+		query.PageSize = 100
+	}
+	if query.SortBy != "semver" && query.SortBy != "created_at" {
+		query.SortBy = "semver"
+	}
+	if query.SortDir != "asc" && query.SortDir != "desc" {
+		query.SortDir = "desc"
+	}
+
+	exists, err := s.repo.ServiceExists(ctx, query.ServiceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get service: %v", err)
+	}
+	if !exists {
+		return nil, notFound("Service")
+	}
+
+	offset := (query.Page - 1) * query.PageSize
+	var versions []domain.ServiceVersion
+	var total int
+
+	if query.SortBy == "created_at" {
+		total, err = s.repo.CountVersions(ctx, query.ServiceID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to count versions: %v", err)
+		}
+		versions, err = s.repo.GetVersionsByCreatedAt(ctx, query.ServiceID, query.SortDir == "desc", query.PageSize, offset)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get versions: %v", err)
+		}
+	} else {
+		// Semver precedence can't be expressed in SQL, so sort in memory
+		all, err := s.repo.GetAllVersions(ctx, query.ServiceID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get versions: %v", err)
+		}
+		sort.SliceStable(all, func(i, j int) bool {
+			c := semver.Compare(all[i].Version, all[j].Version)
+			if query.SortDir == "desc" {
+				return c > 0
+			}
+			return c < 0
+		})
+
+		total = len(all)
+		if offset < total {
+			end := offset + query.PageSize
+			if end > total {
+				end = total
+			}
+			versions = all[offset:end]
+		}
+	}
+	if versions == nil {
+		versions = []domain.ServiceVersion{}
+	}
+
+	return &domain.VersionListResponse{
+		Versions:   versions,
+		Total:      total,
+		Page:       query.Page,
+		PageSize:   query.PageSize,
+		TotalPages: int(math.Ceil(float64(total) / float64(query.PageSize))),
+	}, nil
+}
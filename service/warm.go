@@ -0,0 +1,58 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"com.kong.connect/domain"
+)
+
+// warmingKey marks the reads of WarmCache, which are not views
+type warmingKey struct{}
+
+// WarmCache reads the first pages of the default listing, then the services
+// ids, or else the services listed on those pages, through svc. The reads
+// fill the caches in front of the database, so that the first users after a
+// deploy do not wait on a cold cache. It stops at the first error and
+// returns how many reads were made.
+func WarmCache(ctx context.Context, svc ServiceServiceInterface, pages int, ids []int) (int, error) {
+	ctx = context.WithValue(ctx, warmingKey{}, true)
+	reads := 0
+	var listed []int
+	for page := 1; page <= pages; page++ {
+		// The query the UI and a bare GET /api/v1/services make
+		response, err := svc.GetServices(ctx, domain.ServiceQuery{Page: page})
+		if err != nil {
+			return reads, fmt.Errorf("failed to warm listing page %d: %v", page, err)
+		}
+		reads++
+		for _, service := range response.Services {
+			listed = append(listed, service.ID)
+		}
+		if page >= response.TotalPages {
+			break
+		}
+	}
+
+	if len(ids) == 0 {
+		ids = listed
+	}
+	for _, id := range ids {
+		if _, err := svc.GetServiceByID(ctx, id); err != nil {
+			// Services viewed before may have been deleted since
+			if errors.Is(err, ErrNotFound) {
+				continue
+			}
+			return reads, fmt.Errorf("failed to warm service %d: %v", id, err)
+		}
+		reads++
+	}
+	return reads, nil
+}
+
+// isWarming reports whether ctx belongs to a WarmCache read
+func isWarming(ctx context.Context) bool {
+	warming, _ := ctx.Value(warmingKey{}).(bool)
+	return warming
+}
@@ -0,0 +1,170 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"com.kong.connect/domain"
+	"com.kong.connect/tracing"
+)
+
+const (
+	maxWebhookURLLength = 2000
+	// Secrets sign deliveries with HMAC-SHA256; generated ones carry 256 random bits
+	minWebhookSecretLength = 16
+	maxWebhookSecretLength = 200
+	webhookSecretBytes     = 32
+	// deliveryListLimit bounds the deliveries listed for a webhook
+	deliveryListLimit = 100
+)
+
+// CreateWebhook validates and stores a webhook receiving the change events of
+// the organization of ctx. The secret is returned once and cannot be
+// retrieved again.
+func (s *ServiceService) CreateWebhook(ctx context.Context, input domain.WebhookInput) (_ *domain.CreatedWebhook, err error) {
+	ctx, span := tracing.Start(ctx, "ServiceService.CreateWebhook")
+	defer func() { tracing.End(span, err) }()
+
+	input.URL = strings.TrimSpace(input.URL)
+	target, err := url.Parse(input.URL)
+	if err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.Host == "" || len(input.URL) > maxWebhookURLLength {
+		return nil, invalidf("invalid webhook: url must be an absolute http or https URL of at most %d characters", maxWebhookURLLength)
+	}
+	events, err := normalizeEventTypes(input.Events)
+	if err != nil {
+		return nil, err
+	}
+	if input.Secret == "" {
+		raw := make([]byte, webhookSecretBytes)
+		if _, err := rand.Read(raw); err != nil {
+			return nil, fmt.Errorf("failed to generate webhook secret: %v", err)
+		}
+		input.Secret = hex.EncodeToString(raw)
+	}
+	if len(input.Secret) < minWebhookSecretLength || len(input.Secret) > maxWebhookSecretLength {
+		return nil, invalidf("invalid webhook: secret must be %d to %d characters", minWebhookSecretLength, maxWebhookSecretLength)
+	}
+
+	var webhook *domain.Webhook
+	err = s.repo.InTx(ctx, func(ctx context.Context) error {
+		var err error
+		if webhook, err = s.repo.CreateWebhook(ctx, input.URL, input.Secret, events); err != nil {
+			return err
+		}
+		return s.recordAudit(ctx, domain.AuditActionCreate, domain.AuditResourceWebhook, webhook.ID, nil, webhook)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create webhook: %v", err)
+	}
+
+	return &domain.CreatedWebhook{Webhook: *webhook, Secret: input.Secret}, nil
+}
+
+// ListWebhooks retrieves every webhook of the organization without its secret
+func (s *ServiceService) ListWebhooks(ctx context.Context) (_ *domain.WebhookListResponse, err error) {
+	ctx, span := tracing.Start(ctx, "ServiceService.ListWebhooks")
+	defer func() { tracing.End(span, err) }()
+
+	webhooks, err := s.repo.ListWebhooks(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list webhooks: %v", err)
+	}
+	return &domain.WebhookListResponse{Webhooks: webhooks}, nil
+}
+
+// DeleteWebhook stops deliveries to a webhook and removes its delivery log
+func (s *ServiceService) DeleteWebhook(ctx context.Context, id int) (err error) {
+	ctx, span := tracing.Start(ctx, "ServiceService.DeleteWebhook")
+	defer func() { tracing.End(span, err) }()
+
+	if id <= 0 {
+		return invalidf("invalid webhook ID: %d", id)
+	}
+
+	existing, err := s.repo.GetWebhook(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to get webhook: %v", err)
+	}
+	if existing == nil {
+		return notFound("Webhook")
+	}
+	found, err := s.auditedWrite(ctx, func(ctx context.Context) (bool, error) {
+		return s.repo.DeleteWebhook(ctx, id)
+	}, domain.AuditActionDelete, domain.AuditResourceWebhook, id, existing, nil)
+	if err != nil {
+		return fmt.Errorf("failed to delete webhook: %v", err)
+	}
+	if !found {
+		return notFound("Webhook")
+	}
+
+	return nil
+}
+
+// ListWebhookDeliveries retrieves the most recent deliveries of a webhook
+func (s *ServiceService) ListWebhookDeliveries(ctx context.Context, id int) (_ *domain.WebhookDeliveryListResponse, err error) {
+	ctx, span := tracing.Start(ctx, "ServiceService.ListWebhookDeliveries")
+	defer func() { tracing.End(span, err) }()
+
+	if id <= 0 {
+		return nil, invalidf("invalid webhook ID: %d", id)
+	}
+
+	webhook, err := s.repo.GetWebhook(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get webhook: %v", err)
+	}
+	if webhook == nil {
+		return nil, notFound("Webhook")
+	}
+	deliveries, err := s.repo.ListWebhookDeliveries(ctx, id, deliveryListLimit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list webhook deliveries: %v", err)
+	}
+	return &domain.WebhookDeliveryListResponse{Deliveries: deliveries}, nil
+}
+
+// PurgeWebhookDeliveries deletes the finished deliveries logged before the cutoff
+func (s *ServiceService) PurgeWebhookDeliveries(ctx context.Context, before time.Time) (_ int64, err error) {
+	ctx, span := tracing.Start(ctx, "ServiceService.PurgeWebhookDeliveries")
+	defer func() { tracing.End(span, err) }()
+
+	purged, err := s.repo.PurgeWebhookDeliveries(ctx, before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge webhook deliveries: %v", err)
+	}
+	return purged, nil
+}
+
+// CountWebhookDeliveriesBefore returns how many deliveries PurgeWebhookDeliveries would delete
+func (s *ServiceService) CountWebhookDeliveriesBefore(ctx context.Context, before time.Time) (_ int64, err error) {
+	ctx, span := tracing.Start(ctx, "ServiceService.CountWebhookDeliveriesBefore")
+	defer func() { tracing.End(span, err) }()
+
+	count, err := s.repo.CountWebhookDeliveriesBefore(ctx, before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count webhook deliveries: %v", err)
+	}
+	return count, nil
+}
+
+// normalizeEventTypes checks that every event type exists and returns them
+// sorted without duplicates
+func normalizeEventTypes(events []string) ([]string, error) {
+	normalized := make([]string, 0, len(events))
+	for _, event := range events {
+		event = strings.ToLower(strings.TrimSpace(event))
+		if !slices.Contains(domain.EventTypes, event) {
+			return nil, invalidf("invalid webhook: unknown event type %q, expected one of %s", event, strings.Join(domain.EventTypes, ", "))
+		}
+		normalized = append(normalized, event)
+	}
+	slices.Sort(normalized)
+	return slices.Compact(normalized), nil
+}
@@ -0,0 +1,326 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"com.kong.connect/domain"
+	"com.kong.connect/logging"
+	"com.kong.connect/repository"
+	"com.kong.connect/semver"
+	"com.kong.connect/tenant"
+	"com.kong.connect/tracing"
+)
+
+const (
+	maxNameLength        = 200
+	maxDescriptionLength = 2000
+	maxTags              = 20
+	maxTagLength         = 50
+	maxVersionLength     = 50
+)
+
+// CreateService validates and stores a new service
+func (s *ServiceService) CreateService(ctx context.Context, input domain.ServiceInput) (_ *domain.ServiceWithVersions, err error) {
+	ctx, span := tracing.Start(ctx, "ServiceService.CreateService")
+	defer func() { tracing.End(span, err) }()
+
+	input, err = normalizeServiceInput(input)
+	if err != nil {
+		return nil, err
+	}
+
+	var created *domain.ServiceWithVersions
+	err = s.repo.InTx(ctx, func(ctx context.Context) error {
+		id, err := s.repo.Create(ctx, input)
+		if err != nil {
+			return err
+		}
+		if created, err = s.repo.GetByID(ctx, id); err != nil {
+			return fmt.Errorf("failed to get service: %v", err)
+		}
+		return s.recordAudit(ctx, domain.AuditActionCreate, domain.AuditResourceService, id, nil, &created.Service)
+	})
+	if err != nil {
+		if errors.Is(err, repository.ErrDuplicate) {
+			return nil, conflict("service already exists", "Service already exists")
+		}
+		return nil, fmt.Errorf("failed to create service: %v", err)
+	}
+
+	s.publish(ctx, domain.EventServiceCreated, &created.Service, nil)
+	return created, nil
+}
+
+// UpdateService validates and replaces the fields of an existing service
+func (s *ServiceService) UpdateService(ctx context.Context, id int, input domain.ServiceInput) (_ *domain.ServiceWithVersions, err error) {
+	ctx, span := tracing.Start(ctx, "ServiceService.UpdateService")
+	defer func() { tracing.End(span, err) }()
+
+	if id <= 0 {
+		return nil, invalidf("invalid service ID: %d", id)
+	}
+
+	input, err = normalizeServiceInput(input)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get service: %v", err)
+	}
+	if existing == nil {
+		return nil, notFound("Service")
+	}
+
+	var found bool
+	var updated *domain.ServiceWithVersions
+	err = s.repo.InTx(ctx, func(ctx context.Context) error {
+		var err error
+		if found, err = s.repo.Update(ctx, id, input); err != nil || !found {
+			return err
+		}
+		if updated, err = s.repo.GetByID(ctx, id); err != nil {
+			return fmt.Errorf("failed to get service: %v", err)
+		}
+		return s.recordAudit(ctx, domain.AuditActionUpdate, domain.AuditResourceService, id, &existing.Service, &updated.Service)
+	})
+	if err != nil {
+		if errors.Is(err, repository.ErrDuplicate) {
+			return nil, conflict("service already exists", "Service already exists")
+		}
+		return nil, fmt.Errorf("failed to update service: %v", err)
+	}
+	if !found {
+		return nil, notFound("Service")
+	}
+
+	s.publishUpdate(ctx, &existing.Service, &updated.Service)
+	return updated, nil
+}
+
+// DeleteService removes a service and its versions
+func (s *ServiceService) DeleteService(ctx context.Context, id int) (err error) {
+	ctx, span := tracing.Start(ctx, "ServiceService.DeleteService")
+	defer func() { tracing.End(span, err) }()
+
+	if id <= 0 {
+		return invalidf("invalid service ID: %d", id)
+	}
+
+	existing, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to get service: %v", err)
+	}
+	if existing == nil {
+		return notFound("Service")
+	}
+
+	found, err := s.auditedWrite(ctx, func(ctx context.Context) (bool, error) {
+		return s.repo.Delete(ctx, id)
+	}, domain.AuditActionDelete, domain.AuditResourceService, id, &existing.Service, nil)
+	if err != nil {
+		return fmt.Errorf("failed to delete service: %v", err)
+	}
+	if !found {
+		return notFound("Service")
+	}
+
+	s.publish(ctx, domain.EventServiceDeleted, &existing.Service, nil)
+	return nil
+}
+
+// CreateVersion adds a version to an existing service
+func (s *ServiceService) CreateVersion(ctx context.Context, serviceID int, input domain.VersionInput) (_ *domain.ServiceVersion, err error) {
+	ctx, span := tracing.Start(ctx, "ServiceService.CreateVersion")
+	defer func() { tracing.End(span, err) }()
+
+	if serviceID <= 0 {
+		return nil, invalidf("invalid service ID: %d", serviceID)
+	}
+
+	input, err = normalizeVersionInput(input)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := s.repo.GetByID(ctx, serviceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get service: %v", err)
+	}
+	if existing == nil {
+		return nil, notFound("Service")
+	}
+
+	var version *domain.ServiceVersion
+	err = s.repo.InTx(ctx, func(ctx context.Context) error {
+		var err error
+		if version, err = s.repo.CreateVersion(ctx, serviceID, input); err != nil || version == nil {
+			return err
+		}
+		return s.recordAudit(ctx, domain.AuditActionCreate, domain.AuditResourceVersion, version.ID, nil, version)
+	})
+	if err != nil {
+		if errors.Is(err, repository.ErrDuplicate) {
+			return nil, conflict("version already exists", "Version already exists")
+		}
+		return nil, fmt.Errorf("failed to create version: %v", err)
+	}
+	if version == nil {
+		return nil, notFound("Service")
+	}
+
+	s.publish(ctx, domain.EventVersionCreated, &existing.Service, version)
+	return version, nil
+}
+
+// DeleteVersion removes a version from a service
+func (s *ServiceService) DeleteVersion(ctx context.Context, serviceID, versionID int) (err error) {
+	ctx, span := tracing.Start(ctx, "ServiceService.DeleteVersion")
+	defer func() { tracing.End(span, err) }()
+
+	if serviceID <= 0 {
+		return invalidf("invalid service ID: %d", serviceID)
+	}
+	if versionID <= 0 {
+		return invalidf("invalid version ID: %d", versionID)
+	}
+
+	existing, err := s.repo.GetByID(ctx, serviceID)
+	if err != nil {
+		return fmt.Errorf("failed to get service: %v", err)
+	}
+	if existing == nil {
+		return notFound("Service")
+	}
+
+	var version *domain.ServiceVersion
+	err = s.repo.InTx(ctx, func(ctx context.Context) error {
+		var err error
+		if version, err = s.repo.DeleteVersion(ctx, serviceID, versionID); err != nil || version == nil {
+			return err
+		}
+		return s.recordAudit(ctx, domain.AuditActionDelete, domain.AuditResourceVersion, versionID, version, nil)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete version: %v", err)
+	}
+	if version == nil {
+		return notFound("Version")
+	}
+
+	s.publish(ctx, domain.EventVersionDeleted, &existing.Service, version)
+	return nil
+}
+
+// publish invalidates the cached listings and service and emits a change
+// event when a publisher is configured
+func (s *ServiceService) publish(ctx context.Context, eventType string, service *domain.Service, version *domain.ServiceVersion) {
+	s.publishChange(ctx, eventType, service, version, nil)
+}
+
+// publishUpdate publishes service.updated along with the service before the update
+func (s *ServiceService) publishUpdate(ctx context.Context, before, after *domain.Service) {
+	s.publishChange(ctx, domain.EventServiceUpdated, after, nil, before)
+}
+
+func (s *ServiceService) publishChange(ctx context.Context, eventType string, service *domain.Service, version *domain.ServiceVersion, previous *domain.Service) {
+	orgID := tenant.FromContext(ctx)
+	if s.details != nil {
+		s.details.Remove(DetailKey{OrgID: orgID, ServiceID: service.ID})
+	}
+	if s.shared != nil {
+		// The write is committed; entries left behind expire with their TTL
+		if err := s.shared.Invalidate(ctx, listingsScope(orgID), serviceScope(orgID, service.ID)); err != nil {
+			logging.Component(nil, "service").WarnContext(ctx, "failed to invalidate shared cache", "error", err)
+		}
+	}
+	if s.publisher == nil {
+		return
+	}
+
+	s.publisher.Publish(domain.ChangeEvent{
+		Type:      eventType,
+		OrgID:     orgID,
+		ServiceID: service.ID,
+		Tags:      service.Tags,
+		Service:   service,
+		Version:   version,
+		Previous:  previous,
+		Timestamp: time.Now().UTC(),
+	})
+}
+
+// normalizeVersionInput validates a version and stores it in canonical form,
+// so that "v1.2" and "1.2.0" sort and match alike; Original keeps the version
+// as submitted when it differs
+func normalizeVersionInput(input domain.VersionInput) (domain.VersionInput, error) {
+	version := strings.TrimSpace(input.Version)
+	if version == "" {
+		return input, invalidf("invalid version: version is required")
+	}
+	canonical := semver.Canonical(version)
+	if len(canonical) > maxVersionLength {
+		return input, invalidf("invalid version: must be at most %d characters", maxVersionLength)
+	}
+	input.Version, input.Original = canonical, ""
+	if canonical != version {
+		input.Original = version
+	}
+	return input, nil
+}
+
+// normalizeServiceInput trims and validates service fields, applying defaults
+func normalizeServiceInput(input domain.ServiceInput) (domain.ServiceInput, error) {
+	input.Name = strings.TrimSpace(input.Name)
+	input.Description = strings.TrimSpace(input.Description)
+	input.Owner = strings.TrimSpace(input.Owner)
+	input.Status = strings.ToLower(strings.TrimSpace(input.Status))
+
+	if input.Name == "" {
+		return input, invalidf("invalid service: name is required")
+	}
+	if len(input.Name) > maxNameLength {
+		return input, invalidf("invalid service: name must be at most %d characters", maxNameLength)
+	}
+	if len(input.Description) > maxDescriptionLength {
+		return input, invalidf("invalid service: description must be at most %d characters", maxDescriptionLength)
+	}
+
+	switch input.Status {
+	case "":
+		input.Status = domain.StatusActive
+	case domain.StatusActive, domain.StatusDeprecated, domain.StatusArchived:
+	default:
+		return input, invalidf("invalid service: unknown status %q", input.Status)
+	}
+
+	seen := make(map[string]struct{})
+	tags := []string{}
+	for _, tag := range input.Tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" {
+			continue
+		}
+		if len(tag) > maxTagLength {
+			return input, invalidf("invalid service: tag %q must be at most %d characters", tag, maxTagLength)
+		}
+		if _, ok := seen[tag]; ok {
+			continue
+		}
+		seen[tag] = struct{}{}
+		tags = append(tags, tag)
+	}
+	if len(tags) > maxTags {
+		return input, invalidf("invalid service: at most %d tags are allowed", maxTags)
+	}
+	sort.Strings(tags)
+	input.Tags = tags
+
+	return input, nil
+}
@@ -0,0 +1,28 @@
+// Package tenant carries the organization a request acts for. The
+// repositories scope every catalog read and write to it, so that one
+// deployment can host the catalogs of several teams.
+package tenant
+
+import "context"
+
+// DefaultOrg is the organization of databases created before organizations
+// existed, and of work done outside a request, such as seeding and jobs
+const DefaultOrg = 1
+
+// DefaultSlug identifies DefaultOrg in the X-Org header
+const DefaultSlug = "default"
+
+type contextKey struct{}
+
+// NewContext returns a copy of ctx acting for organization orgID
+func NewContext(ctx context.Context, orgID int) context.Context {
+	return context.WithValue(ctx, contextKey{}, orgID)
+}
+
+// FromContext returns the organization ctx acts for, or DefaultOrg when none was set
+func FromContext(ctx context.Context) int {
+	if orgID, ok := ctx.Value(contextKey{}).(int); ok {
+		return orgID
+	}
+	return DefaultOrg
+}
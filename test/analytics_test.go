@@ -0,0 +1,83 @@
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"com.kong.connect/analytics"
+	"com.kong.connect/domain"
+	"com.kong.connect/handler"
+	"com.kong.connect/repository"
+	"com.kong.connect/service"
+	"com.kong.connect/testsupport"
+)
+
+func TestAnalyticsReportViewsAndSearches(t *testing.T) {
+	repo := repository.NewServiceRepository(testsupport.NewDB(t))
+	usage := analytics.NewTracker(repo)
+	svc := service.NewServiceService(repo, service.WithUsage(usage))
+	router := handler.SetupRouter(handler.NewServiceHandler(svc))
+	ctx := context.Background()
+
+	for range 3 {
+		doRequest(t, router, "GET", "/api/v1/services/2", "viewer-token", nil)
+	}
+	doRequest(t, router, "GET", "/api/v1/services/1", "viewer-token", nil)
+	for range 3 {
+		doRequest(t, router, "GET", "/api/v1/search?q=Locate", "viewer-token", nil)
+		doRequest(t, router, "GET", "/api/v1/services?search=monday", "viewer-token", nil)
+	}
+	// Further pages and rare terms are not reported
+	doRequest(t, router, "GET", "/api/v1/search?q=locate&page=2", "viewer-token", nil)
+	doRequest(t, router, "GET", "/api/v1/search?q=jane.doe@example.com", "viewer-token", nil)
+
+	// Monday was searched more often during the previous 30 days
+	previous := time.Now().UTC().AddDate(0, 0, -40).Format(domain.AnalyticsDayFormat)
+	require.NoError(t, repo.AddUsage(ctx, domain.Usage{Searches: []domain.SearchTermCount{{OrgID: 1, Term: "monday", Day: previous, Searches: 5}}}))
+
+	// Nothing is reported before the tracker flushes
+	response := doRequest(t, router, "GET", "/api/v1/analytics", "admin-token", nil)
+	require.Equal(t, http.StatusOK, response.Code, response.Body.String())
+	var report domain.AnalyticsReport
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &report))
+	assert.Empty(t, report.TopServices)
+
+	require.NoError(t, usage.Flush(ctx))
+	response = doRequest(t, router, "GET", "/api/v1/analytics", "admin-token", nil)
+	require.Equal(t, http.StatusOK, response.Code)
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &report))
+	assert.Equal(t, time.Now().UTC().Format(domain.AnalyticsDayFormat), report.Until)
+	assert.Equal(t, []domain.ServiceViews{
+		{ServiceID: 2, ServiceName: "Collect Monday", Views: 3},
+		{ServiceID: 1, ServiceName: "Locate Us", Views: 1},
+	}, report.TopServices)
+	assert.Equal(t, []domain.SearchTermStats{
+		{Term: "locate", Searches: 3},
+		{Term: "monday", Searches: 3, PreviousSearches: 5},
+	}, report.TopSearches)
+	assert.Equal(t, []domain.SearchTermStats{{Term: "locate", Searches: 3}}, report.TrendingSearches)
+
+	response = doRequest(t, router, "GET", "/api/v1/analytics?limit=1", "admin-token", nil)
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &report))
+	assert.Len(t, report.TopServices, 1)
+	assert.Len(t, report.TopSearches, 1)
+
+	response = doRequest(t, router, "GET", "/api/v1/analytics?days=0", "admin-token", nil)
+	assert.Equal(t, http.StatusBadRequest, response.Code)
+	response = doRequest(t, router, "GET", "/api/v1/analytics", "viewer-token", nil)
+	assert.Equal(t, http.StatusForbidden, response.Code)
+
+	// Deleted services leave the report
+	response = doRequest(t, router, "DELETE", "/api/v1/services/2", "admin-token", nil)
+	require.Equal(t, http.StatusNoContent, response.Code)
+	response = doRequest(t, router, "GET", "/api/v1/analytics", "admin-token", nil)
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &report))
+	require.Len(t, report.TopServices, 1)
+	assert.Equal(t, 1, report.TopServices[0].ServiceID)
+}
@@ -0,0 +1,169 @@
+package integration
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"com.kong.connect/clientip"
+	"com.kong.connect/domain"
+	"com.kong.connect/handler"
+	"com.kong.connect/repository"
+	"com.kong.connect/service"
+	"com.kong.connect/testsupport"
+)
+
+func TestAuditLogRecordsWrites(t *testing.T) {
+	router := newTestRouter(t)
+
+	input := domain.ServiceInput{Name: "Billing", Description: "Invoices", Owner: "payments-team"}
+	response := doRequest(t, router, "POST", "/api/v1/services", "admin-token", input)
+	require.Equal(t, http.StatusCreated, response.Code, response.Body.String())
+
+	var created domain.ServiceWithVersions
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &created))
+
+	input.Status = domain.StatusDeprecated
+	response = doRequest(t, router, "PUT", "/api/v1/services/"+itoa(created.ID), "admin-token", input)
+	require.Equal(t, http.StatusOK, response.Code, response.Body.String())
+
+	response = doRequest(t, router, "DELETE", "/api/v1/services/"+itoa(created.ID), "admin-token", nil)
+	require.Equal(t, http.StatusNoContent, response.Code)
+
+	response = doRequest(t, router, "GET", "/api/v1/audit-logs?resource_type=service&resource_id="+itoa(created.ID), "admin-token", nil)
+	require.Equal(t, http.StatusOK, response.Code, response.Body.String())
+
+	var logs domain.AuditListResponse
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &logs))
+	require.Equal(t, 3, logs.Total)
+	assert.Equal(t, domain.AuditActionDelete, logs.Entries[0].Action)
+	assert.Equal(t, domain.AuditActionCreate, logs.Entries[2].Action)
+
+	update := logs.Entries[1]
+	assert.Equal(t, domain.AuditActionUpdate, update.Action)
+	assert.Equal(t, "admin", update.Principal)
+	assert.NotEmpty(t, update.RequestID)
+	assert.NotEmpty(t, update.Before)
+	assert.NotEmpty(t, update.After)
+	assert.Equal(t, domain.AuditChange{Before: "active", After: "deprecated"}, update.Changes["status"])
+	assert.NotContains(t, update.Changes, "name")
+
+	response = doRequest(t, router, "GET", "/api/v1/audit-logs?action=delete", "admin-token", nil)
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &logs))
+	assert.Equal(t, 1, logs.Total)
+}
+
+func TestAuditLogRecordsTheClientBehindTrustedProxies(t *testing.T) {
+	trusted, err := clientip.ParseTrustedProxies([]string{"10.0.0.0/8"})
+	require.NoError(t, err)
+	svc := service.NewServiceService(repository.NewServiceRepository(testsupport.NewDB(t)))
+	router := handler.SetupRouter(handler.NewServiceHandler(svc), handler.WithTrustedProxies(trusted))
+
+	create := func(name, remoteAddr, forwardedFor string) {
+		payload, err := json.Marshal(domain.ServiceInput{Name: name})
+		require.NoError(t, err)
+		req := httptest.NewRequest("POST", "/api/v1/services", bytes.NewReader(payload))
+		req.RemoteAddr = remoteAddr
+		req.Header.Set("Authorization", "Bearer admin-token")
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set(clientip.ForwardedForHeader, forwardedFor)
+		response := httptest.NewRecorder()
+		router.ServeHTTP(response, req)
+		require.Equal(t, http.StatusCreated, response.Code, response.Body.String())
+	}
+	create("Proxied", "10.0.0.5:41000", "1.2.3.4, 198.51.100.7")
+	create("Spoofed", "203.0.113.9:41000", "198.51.100.7")
+
+	response := doRequest(t, router, "GET", "/api/v1/audit-logs?action=create", "admin-token", nil)
+	require.Equal(t, http.StatusOK, response.Code, response.Body.String())
+	var logs domain.AuditListResponse
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &logs))
+	require.Equal(t, 2, logs.Total)
+	assert.Equal(t, "203.0.113.9", logs.Entries[0].IP, "untrusted peers cannot forward an IP")
+	assert.Equal(t, "198.51.100.7", logs.Entries[1].IP)
+}
+
+func TestAuditLogRecordsComments(t *testing.T) {
+	router := newTestRouter(t)
+
+	response := doRequest(t, router, "POST", "/api/v1/services/1/comments", "viewer-token", domain.CommentInput{Body: "Is there a sandbox?"})
+	require.Equal(t, http.StatusCreated, response.Code, response.Body.String())
+	var comment domain.Comment
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &comment))
+	response = doRequest(t, router, "DELETE", "/api/v1/services/1/comments/"+itoa(comment.ID), "admin-token", nil)
+	require.Equal(t, http.StatusNoContent, response.Code, response.Body.String())
+
+	response = doRequest(t, router, "GET", "/api/v1/audit-logs?resource_type=comment&resource_id="+itoa(comment.ID), "admin-token", nil)
+	require.Equal(t, http.StatusOK, response.Code, response.Body.String())
+	var logs domain.AuditListResponse
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &logs))
+	require.Equal(t, 2, logs.Total)
+
+	deleted := logs.Entries[0]
+	assert.Equal(t, domain.AuditActionDelete, deleted.Action)
+	assert.Equal(t, "admin", deleted.Principal)
+	assert.NotEmpty(t, deleted.Before)
+	assert.Empty(t, deleted.After)
+
+	created := logs.Entries[1]
+	assert.Equal(t, domain.AuditActionCreate, created.Action)
+	assert.Equal(t, domain.AuditResourceComment, created.ResourceType)
+	assert.Equal(t, "viewer", created.Principal)
+	assert.Empty(t, created.Before)
+	assert.NotEmpty(t, created.After)
+}
+
+func TestAuditLogFailureRollsBackTheWrite(t *testing.T) {
+	db := testsupport.NewDB(t)
+	router := handler.SetupRouter(handler.NewServiceHandler(service.NewServiceService(repository.NewServiceRepository(db))))
+	_, err := db.Exec("CREATE TRIGGER audit_unavailable BEFORE INSERT ON audit_logs BEGIN SELECT RAISE(ABORT, 'audit unavailable'); END")
+	require.NoError(t, err)
+
+	response := doRequest(t, router, "POST", "/api/v1/services", "admin-token", domain.ServiceInput{Name: "Billing"})
+	assert.Equal(t, http.StatusInternalServerError, response.Code)
+	response = doRequest(t, router, "DELETE", "/api/v1/services/1", "admin-token", nil)
+	assert.Equal(t, http.StatusInternalServerError, response.Code)
+
+	var count int
+	require.NoError(t, db.QueryRow("SELECT COUNT(*) FROM services WHERE name = 'Billing'").Scan(&count))
+	assert.Zero(t, count, "the service is not created without its audit entry")
+	response = doRequest(t, router, "GET", "/api/v1/services/1", "viewer-token", nil)
+	assert.Equal(t, http.StatusOK, response.Code, "the service is not deleted without its audit entry")
+}
+
+func TestAuditLogRequiresAdmin(t *testing.T) {
+	router := newTestRouter(t)
+
+	response := doRequest(t, router, "GET", "/api/v1/audit-logs", "viewer-token", nil)
+	assert.Equal(t, http.StatusForbidden, response.Code)
+
+	response = doRequest(t, router, "GET", "/api/v1/audit-logs?since=yesterday", "admin-token", nil)
+	assert.Equal(t, http.StatusBadRequest, response.Code)
+}
+
+func TestAuditLogRetentionPurge(t *testing.T) {
+	svc := service.NewServiceService(repository.NewServiceRepository(testsupport.NewDB(t)))
+	router := handler.SetupRouter(handler.NewServiceHandler(svc))
+
+	response := doRequest(t, router, "POST", "/api/v1/services", "admin-token", domain.ServiceInput{Name: "Billing"})
+	require.Equal(t, http.StatusCreated, response.Code, response.Body.String())
+
+	expired, err := svc.CountAuditLogsBefore(context.Background(), time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), expired)
+
+	purged, err := svc.PurgeAuditLogs(context.Background(), time.Now().Add(-time.Hour))
+	require.NoError(t, err)
+	assert.Zero(t, purged)
+
+	purged, err = svc.PurgeAuditLogs(context.Background(), time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), purged)
+}
@@ -0,0 +1,189 @@
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"com.kong.connect/cache"
+	"com.kong.connect/domain"
+	"com.kong.connect/events"
+	"com.kong.connect/handler"
+	"com.kong.connect/repository"
+	"com.kong.connect/service"
+	"com.kong.connect/testsupport"
+)
+
+func TestServiceDetailCacheInvalidatesOnWrites(t *testing.T) {
+	details := cache.NewLRU[service.DetailKey, *domain.ServiceWithVersions]("service", 10, time.Minute)
+	registry := prometheus.NewPedanticRegistry()
+	registry.MustRegister(details.Collectors()...)
+	repo := repository.NewServiceRepository(testsupport.NewDB(t))
+	router := handler.SetupRouter(handler.NewServiceHandler(service.NewServiceService(repo, service.WithDetailCache(details))))
+
+	detail := func() domain.ServiceWithVersions {
+		response := doRequest(t, router, "GET", "/api/v1/services/1", "viewer-token", nil)
+		require.Equal(t, http.StatusOK, response.Code, response.Body.String())
+		var service domain.ServiceWithVersions
+		require.NoError(t, json.Unmarshal(response.Body.Bytes(), &service))
+		return service
+	}
+
+	before := detail()
+	detail()
+	assert.Equal(t, 1, details.Len())
+	assert.NoError(t, testutil.GatherAndCompare(registry, strings.NewReader(`
+# HELP cache_hits_total Lookups answered from the cache.
+# TYPE cache_hits_total counter
+cache_hits_total{cache="service"} 1
+# HELP cache_misses_total Lookups not found in the cache or expired.
+# TYPE cache_misses_total counter
+cache_misses_total{cache="service"} 1
+`), "cache_hits_total", "cache_misses_total"))
+
+	input := domain.ServiceInput{Name: before.Name, Description: "Updated through the API", Owner: before.Owner, Tags: before.Tags}
+	response := doRequest(t, router, "PUT", "/api/v1/services/1", "admin-token", input)
+	require.Equal(t, http.StatusOK, response.Code, response.Body.String())
+	assert.Equal(t, "Updated through the API", detail().Description)
+
+	response = doRequest(t, router, "POST", "/api/v1/services/1/versions", "admin-token", domain.VersionInput{Version: "9.0.0"})
+	require.Equal(t, http.StatusCreated, response.Code, response.Body.String())
+	assert.Len(t, detail().Versions, len(before.Versions)+1)
+
+	// Missing services are not cached
+	response = doRequest(t, router, "GET", "/api/v1/services/999", "viewer-token", nil)
+	assert.Equal(t, http.StatusNotFound, response.Code)
+	assert.Equal(t, 1, details.Len())
+}
+
+func TestSharedCacheIsInvalidatedAcrossInstances(t *testing.T) {
+	server := miniredis.RunT(t)
+	db := testsupport.NewDB(t)
+	routers := make([]http.Handler, 2)
+	for i := range routers {
+		client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+		t.Cleanup(func() { client.Close() })
+		shared := cache.NewShared(client, "shared", "catalog:", time.Minute)
+		svc := service.NewServiceService(repository.NewServiceRepository(db), service.WithSharedCache(shared))
+		routers[i] = handler.SetupRouter(handler.NewServiceHandler(svc))
+	}
+
+	list := func(router http.Handler) domain.ServiceListResponse {
+		response := doRequest(t, router, "GET", "/api/v1/services?sort_by=name", "viewer-token", nil)
+		require.Equal(t, http.StatusOK, response.Code, response.Body.String())
+		var listing domain.ServiceListResponse
+		require.NoError(t, json.Unmarshal(response.Body.Bytes(), &listing))
+		return listing
+	}
+
+	// The first instance fills the cache for the second
+	before := list(routers[0])
+	assert.Len(t, server.Keys(), 1)
+	assert.Equal(t, before, list(routers[1]))
+
+	response := doRequest(t, routers[0], "POST", "/api/v1/services", "admin-token", domain.ServiceInput{Name: "Billing", Description: "Invoices"})
+	require.Equal(t, http.StatusCreated, response.Code, response.Body.String())
+	assert.Equal(t, before.Total+1, list(routers[1]).Total)
+}
+
+func TestCachesAreInvalidatedByChangeEvents(t *testing.T) {
+	server := miniredis.RunT(t)
+	db := testsupport.NewDB(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Two instances wired as the server wires them: the relayed change events
+	// of one invalidate the detail cache of the other
+	routers := make([]http.Handler, 2)
+	for i := range routers {
+		client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+		t.Cleanup(func() { client.Close() })
+		bus := events.NewBus()
+		details := cache.NewLRU[service.DetailKey, *domain.ServiceWithVersions]("service", 10, time.Minute)
+		bus.Subscribe(func(event domain.ChangeEvent) {
+			details.Remove(service.DetailKey{OrgID: event.OrgID, ServiceID: event.ServiceID})
+		})
+		relay := events.NewRelay(client, "catalog-events", bus, nil)
+		go relay.Run(ctx)
+		shared := cache.NewShared(client, "shared", "catalog:", time.Minute)
+		svc := service.NewServiceService(repository.NewServiceRepository(db),
+			service.WithPublisher(relay), service.WithDetailCache(details), service.WithSharedCache(shared))
+		routers[i] = handler.SetupRouter(handler.NewServiceHandler(svc))
+	}
+	require.Eventually(t, func() bool { return server.PubSubNumSub("catalog-events")["catalog-events"] == 2 }, 5*time.Second, 10*time.Millisecond)
+
+	detail := func(router http.Handler, id string) domain.ServiceWithVersions {
+		response := doRequest(t, router, "GET", "/api/v1/services/"+id, "viewer-token", nil)
+		require.Equal(t, http.StatusOK, response.Code, response.Body.String())
+		var service domain.ServiceWithVersions
+		require.NoError(t, json.Unmarshal(response.Body.Bytes(), &service))
+		return service
+	}
+	for _, router := range routers {
+		detail(router, "1")
+		detail(router, "2")
+	}
+	require.True(t, server.Exists("catalog:0:org:1:service:2"))
+
+	before := detail(routers[0], "1")
+	input := domain.ServiceInput{Name: before.Name, Description: "Updated on the first instance", Owner: before.Owner, Tags: before.Tags}
+	response := doRequest(t, routers[0], "PUT", "/api/v1/services/1", "admin-token", input)
+	require.Equal(t, http.StatusOK, response.Code, response.Body.String())
+
+	assert.Eventually(t, func() bool {
+		return detail(routers[1], "1").Description == "Updated on the first instance"
+	}, 5*time.Second, 10*time.Millisecond)
+	// Only the changed service was invalidated in the shared cache
+	assert.False(t, server.Exists("catalog:org:1:service:2:generation"))
+	assert.True(t, server.Exists("catalog:0:org:1:service:2"))
+}
+
+func TestWarmCacheFillsTheDetailCache(t *testing.T) {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { client.Close() })
+	ctx := context.Background()
+
+	details := cache.NewLRU[service.DetailKey, *domain.ServiceWithVersions]("service", 100, time.Minute)
+	views := cache.NewViews(client, "views")
+	repo := repository.NewServiceRepository(testsupport.NewDB(t))
+	svc := service.NewServiceService(repo, service.WithDetailCache(details), service.WithViews(views))
+
+	// Without a tally, the services on the warmed pages are read
+	reads, err := service.WarmCache(ctx, svc, 2, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 9, reads)
+	assert.Equal(t, 8, details.Len())
+
+	// Warming is not counted as views
+	require.NoError(t, views.Flush(ctx))
+	top, err := views.Top(ctx, 10)
+	require.NoError(t, err)
+	assert.Empty(t, top)
+
+	for _, id := range []int{5, 5, 2} {
+		_, err := svc.GetServiceByID(ctx, id)
+		require.NoError(t, err)
+	}
+	require.NoError(t, views.Flush(ctx))
+	top, err = views.Top(ctx, 10)
+	require.NoError(t, err)
+	assert.Equal(t, []int{5, 2}, top)
+
+	// Deleted services are skipped
+	details.Purge()
+	reads, err = service.WarmCache(ctx, svc, 0, append(top, 999))
+	require.NoError(t, err)
+	assert.Equal(t, 2, reads)
+	assert.Equal(t, 2, details.Len())
+}
@@ -0,0 +1,145 @@
+package integration
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"com.kong.connect/domain"
+)
+
+// applyYAML posts a YAML catalog document as admin
+func applyYAML(t *testing.T, router http.Handler, query, document string) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest("POST", "/api/v1/catalog:apply"+query, strings.NewReader(document))
+	req.Header.Set("Authorization", "Bearer admin-token")
+	req.Header.Set("Content-Type", "application/yaml")
+	response := httptest.NewRecorder()
+	router.ServeHTTP(response, req)
+	return response
+}
+
+// desiredCatalog keeps two sample services, one of them changed, and adds one
+const desiredCatalog = `
+services:
+  - name: Locate Us
+    description: Branch and ATM finder
+    owner: web-team
+    tags: [public, maps]
+    versions: [1.0.0, 1.1.0, 2.0.0, 2.1.0]
+  - name: Security
+    description: "Lorem ipsum dolor sit amet, consectetur adipiscing elit. Turpis non a, pellentesque ipsum aliquet id..."
+    owner: platform-team
+    tags: [internal]
+    versions: [1.0.0, 1.1.0, 1.2.0]
+  - name: Billing
+    description: Invoices
+    status: deprecated
+    owner: payments-team
+    versions: [1.0.0]
+`
+
+func TestApplyCatalogDryRunThenApply(t *testing.T) {
+	router := newTestRouter(t)
+
+	response := applyYAML(t, router, "?dry_run=true", desiredCatalog)
+	require.Equal(t, http.StatusOK, response.Code, response.Body.String())
+	var plan domain.CatalogApplyResult
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &plan))
+	assert.True(t, plan.DryRun)
+	assert.Equal(t, 1, plan.Created)
+	assert.Equal(t, 1, plan.Updated)
+	assert.Equal(t, 6, plan.Deleted)
+	assert.Equal(t, 1, plan.Unchanged)
+
+	update := plan.Changes[0]
+	assert.Equal(t, domain.CatalogActionUpdate, update.Action)
+	assert.Equal(t, "Locate Us", update.Service)
+	assert.Equal(t, map[string]domain.AuditChange{"description": {Before: "Lorem ipsum dolor sit amet, consectetur adipiscing elit. Turpis non a, pellentesque ipsum aliquet id...", After: "Branch and ATM finder"}}, update.Fields)
+	assert.Equal(t, []string{"2.1.0"}, update.VersionsAdded)
+	assert.Equal(t, domain.CatalogChange{Action: domain.CatalogActionCreate, Service: "Billing", VersionsAdded: []string{"1.0.0"}}, plan.Changes[1])
+	assert.Equal(t, domain.CatalogActionDelete, plan.Changes[2].Action)
+
+	// A dry run changes nothing
+	list := doRequest(t, router, "GET", "/api/v1/services", "viewer-token", nil)
+	var before domain.ServiceListResponse
+	require.NoError(t, json.Unmarshal(list.Body.Bytes(), &before))
+	assert.Equal(t, 8, before.Total)
+
+	response = applyYAML(t, router, "", desiredCatalog)
+	require.Equal(t, http.StatusOK, response.Code, response.Body.String())
+	var applied domain.CatalogApplyResult
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &applied))
+	assert.False(t, applied.DryRun)
+	assert.NotZero(t, applied.Changes[1].ServiceID)
+
+	list = doRequest(t, router, "GET", "/api/v1/services?sort_by=name", "viewer-token", nil)
+	var after domain.ServiceListResponse
+	require.NoError(t, json.Unmarshal(list.Body.Bytes(), &after))
+	require.Equal(t, 3, after.Total)
+	assert.Equal(t, "Billing", after.Services[0].Name)
+	assert.Equal(t, domain.StatusDeprecated, after.Services[0].Status)
+	assert.Equal(t, "Branch and ATM finder", after.Services[1].Description)
+	assert.Len(t, after.Services[1].Versions, 4)
+
+	// Applying the same document again is a no-op
+	response = applyYAML(t, router, "", desiredCatalog)
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &applied))
+	assert.Empty(t, applied.Changes)
+	assert.Equal(t, 3, applied.Unchanged)
+
+	// Every change is audited
+	audit := doRequest(t, router, "GET", "/api/v1/audit-logs?action=delete&resource_type=service", "admin-token", nil)
+	var entries domain.AuditListResponse
+	require.NoError(t, json.Unmarshal(audit.Body.Bytes(), &entries))
+	assert.Equal(t, 6, entries.Total)
+}
+
+func TestApplyCatalogRemovesVersionsAndAcceptsJSON(t *testing.T) {
+	router := newTestRouter(t)
+
+	document := domain.CatalogDocument{Services: []domain.CatalogService{
+		{Name: "Reporting", Description: "Reports", Owner: "data-team", Tags: []string{"analytics"}, Versions: []string{"2.0.0"}},
+	}}
+	response := doRequest(t, router, "POST", "/api/v1/catalog:apply", "admin-token", document)
+	require.Equal(t, http.StatusOK, response.Code, response.Body.String())
+	var result domain.CatalogApplyResult
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &result))
+	assert.Equal(t, []string{"1.0.0", "1.1.0"}, result.Changes[0].VersionsRemoved)
+
+	response = doRequest(t, router, "GET", "/api/v1/services?search=Reporting", "viewer-token", nil)
+	var list domain.ServiceListResponse
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &list))
+	require.Len(t, list.Services, 1)
+	require.Len(t, list.Services[0].Versions, 1)
+	assert.Equal(t, "2.0.0", list.Services[0].Versions[0].Version)
+}
+
+func TestApplyCatalogRejectsInvalidDocuments(t *testing.T) {
+	router := newTestRouter(t)
+
+	for name, document := range map[string]string{
+		"empty":          "",
+		"unknown field":  "services:\n  - name: Billing\n    ownr: me\n",
+		"missing name":   "services:\n  - description: nameless\n",
+		"bad status":     "services:\n  - name: Billing\n    status: retired\n",
+		"duplicate":      "services:\n  - name: Billing\n  - name: Billing\n",
+		"twice versions": "services:\n  - name: Billing\n    versions: [1.0.0, 1.0.0]\n",
+	} {
+		response := applyYAML(t, router, "", document)
+		assert.Equal(t, http.StatusBadRequest, response.Code, name)
+	}
+	assert.Equal(t, http.StatusBadRequest, applyYAML(t, router, "?dry_run=maybe", "services: []").Code)
+	assert.Equal(t, http.StatusForbidden, doRequest(t, router, "POST", "/api/v1/catalog:apply", "viewer-token", domain.CatalogDocument{}).Code)
+
+	// Nothing was deleted by the rejected documents
+	response := doRequest(t, router, "GET", "/api/v1/services", "viewer-token", nil)
+	var list domain.ServiceListResponse
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &list))
+	assert.Equal(t, 8, list.Total)
+}
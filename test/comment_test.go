@@ -0,0 +1,146 @@
+package integration
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"com.kong.connect/domain"
+	"com.kong.connect/email"
+	"com.kong.connect/handler"
+	"com.kong.connect/repository"
+	"com.kong.connect/service"
+	"com.kong.connect/testsupport"
+)
+
+func TestCommentThreads(t *testing.T) {
+	svc := service.NewServiceService(repository.NewServiceRepository(testsupport.NewDB(t)))
+	router := handler.SetupRouter(handler.NewServiceHandler(svc))
+
+	comment := func(token string, input domain.CommentInput) domain.Comment {
+		t.Helper()
+		response := doRequest(t, router, "POST", "/api/v1/services/1/comments", token, input)
+		require.Equal(t, http.StatusCreated, response.Code, response.Body.String())
+		var created domain.Comment
+		require.NoError(t, json.Unmarshal(response.Body.Bytes(), &created))
+		return created
+	}
+
+	question := comment("viewer-token", domain.CommentInput{Body: "Is there a sandbox?"})
+	assert.Equal(t, "viewer", question.Author)
+	assert.Equal(t, 1, question.ServiceID)
+	assert.Nil(t, question.ParentID)
+	assert.Empty(t, question.Mentions)
+
+	answer := comment("admin-token", domain.CommentInput{Body: "Yes, @viewer: see the docs", ParentID: &question.ID})
+	require.NotNil(t, answer.ParentID)
+	assert.Equal(t, question.ID, *answer.ParentID)
+	assert.Equal(t, []string{"viewer"}, answer.Mentions)
+	// Replies to replies join the thread
+	thanks := comment("viewer-token", domain.CommentInput{Body: "Thanks @admin!", ParentID: &answer.ID})
+	require.NotNil(t, thanks.ParentID)
+	assert.Equal(t, question.ID, *thanks.ParentID)
+	announcement := comment("admin-token", domain.CommentInput{Body: "v2 ships next week"})
+
+	for _, test := range []struct {
+		path  string
+		input domain.CommentInput
+		want  int
+	}{
+		{"/api/v1/services/1/comments", domain.CommentInput{Body: "  "}, http.StatusBadRequest},
+		{"/api/v1/services/1/comments", domain.CommentInput{Body: strings.Repeat("a", 10001)}, http.StatusBadRequest},
+		{"/api/v1/services/2/comments", domain.CommentInput{Body: "Wrong service", ParentID: &question.ID}, http.StatusBadRequest},
+		{"/api/v1/services/999/comments", domain.CommentInput{Body: "Anyone?"}, http.StatusNotFound},
+	} {
+		response := doRequest(t, router, "POST", test.path, "viewer-token", test.input)
+		assert.Equal(t, test.want, response.Code, "%s %q", test.path, test.input.Body)
+	}
+
+	response := doRequest(t, router, "GET", "/api/v1/services/1/comments?page_size=1", "viewer-token", nil)
+	require.Equal(t, http.StatusOK, response.Code, response.Body.String())
+	var listing domain.CommentListResponse
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &listing))
+	assert.Equal(t, 2, listing.Total)
+	assert.Equal(t, 2, listing.TotalPages)
+	require.Len(t, listing.Comments, 1)
+	assert.Equal(t, announcement.ID, listing.Comments[0].ID)
+
+	response = doRequest(t, router, "GET", "/api/v1/services/1/comments?page=2&page_size=1", "viewer-token", nil)
+	require.Equal(t, http.StatusOK, response.Code)
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &listing))
+	require.Len(t, listing.Comments, 1)
+	assert.Equal(t, question.ID, listing.Comments[0].ID)
+	require.Len(t, listing.Comments[0].Replies, 2)
+	assert.Equal(t, answer.ID, listing.Comments[0].Replies[0].ID)
+	assert.Equal(t, thanks.ID, listing.Comments[0].Replies[1].ID)
+
+	response = doRequest(t, router, "GET", "/api/v1/services/999/comments", "viewer-token", nil)
+	assert.Equal(t, http.StatusNotFound, response.Code)
+
+	// Only their author or an admin deletes comments
+	response = doRequest(t, router, "DELETE", "/api/v1/services/1/comments/"+itoa(answer.ID), "viewer-token", nil)
+	assert.Equal(t, http.StatusForbidden, response.Code)
+	response = doRequest(t, router, "DELETE", "/api/v1/services/1/comments/"+itoa(thanks.ID), "viewer-token", nil)
+	assert.Equal(t, http.StatusNoContent, response.Code)
+	response = doRequest(t, router, "DELETE", "/api/v1/services/2/comments/"+itoa(answer.ID), "admin-token", nil)
+	assert.Equal(t, http.StatusNotFound, response.Code)
+	// Deleting the first comment of a thread deletes its replies
+	response = doRequest(t, router, "DELETE", "/api/v1/services/1/comments/"+itoa(question.ID), "admin-token", nil)
+	assert.Equal(t, http.StatusNoContent, response.Code)
+	response = doRequest(t, router, "DELETE", "/api/v1/services/1/comments/"+itoa(answer.ID), "admin-token", nil)
+	assert.Equal(t, http.StatusNotFound, response.Code)
+
+	response = doRequest(t, router, "GET", "/api/v1/services/1/comments", "viewer-token", nil)
+	require.Equal(t, http.StatusOK, response.Code)
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &listing))
+	assert.Equal(t, 1, listing.Total)
+
+	// Deleting a service deletes its comments
+	response = doRequest(t, router, "DELETE", "/api/v1/services/1", "admin-token", nil)
+	require.Equal(t, http.StatusNoContent, response.Code, response.Body.String())
+	response = doRequest(t, router, "DELETE", "/api/v1/services/1/comments/"+itoa(announcement.ID), "admin-token", nil)
+	assert.Equal(t, http.StatusNotFound, response.Code)
+}
+
+func TestMentionedUsersAreEmailed(t *testing.T) {
+	sent := make(fakeSender, 10)
+	repo := repository.NewServiceRepository(testsupport.NewDB(t))
+	notifier, err := email.NewNotifier(email.Config{From: "catalog@example.com"}, sent, repo, nil)
+	require.NoError(t, err)
+	router := handler.SetupRouter(handler.NewServiceHandler(service.NewServiceService(repo, service.WithMentionNotifier(notifier))))
+
+	response := doRequest(t, router, "PUT", "/api/v1/me/notification-preferences", "viewer-token",
+		domain.NotificationPreferences{Email: "viewer@example.com", Mentions: true})
+	require.Equal(t, http.StatusOK, response.Code, response.Body.String())
+	response = doRequest(t, router, "PUT", "/api/v1/me/notification-preferences", "admin-token",
+		domain.NotificationPreferences{Email: "admin@example.com"})
+	require.Equal(t, http.StatusOK, response.Code, response.Body.String())
+
+	// The admin turned mentions off; authors are not emailed about
+	// themselves, nor unknown users at all
+	response = doRequest(t, router, "POST", "/api/v1/services/1/comments", "viewer-token",
+		domain.CommentInput{Body: "@admin @viewer @nobody please review"})
+	require.Equal(t, http.StatusCreated, response.Code, response.Body.String())
+	response = doRequest(t, router, "POST", "/api/v1/services/1/comments", "admin-token",
+		domain.CommentInput{Body: "Ping @viewer and @viewer again"})
+	require.Equal(t, http.StatusCreated, response.Code, response.Body.String())
+
+	select {
+	case message := <-sent:
+		assert.Equal(t, "viewer@example.com", message.to)
+		assert.Contains(t, message.message, "Subject: [Kong Connect] admin mentioned you on Locate Us\r\n")
+		assert.Contains(t, message.message, "Ping @viewer and @viewer again")
+	case <-time.After(5 * time.Second):
+		t.Fatal("no email sent")
+	}
+	select {
+	case message := <-sent:
+		t.Fatalf("unexpected email to %s", message.to)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
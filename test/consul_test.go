@@ -0,0 +1,121 @@
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"com.kong.connect/consul"
+	"com.kong.connect/domain"
+	"com.kong.connect/repository"
+	"com.kong.connect/service"
+	"com.kong.connect/testsupport"
+)
+
+// fakeConsul serves a catalog of services and their tags
+type fakeConsul struct {
+	mu       sync.Mutex
+	services map[string][]string
+}
+
+func (f *fakeConsul) set(name string, tags ...string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.services[name] = tags
+}
+
+func (f *fakeConsul) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	json.NewEncoder(w).Encode(f.services)
+}
+
+func TestConsulImportResolvesConflictsByPolicy(t *testing.T) {
+	svc := service.NewServiceService(repository.NewServiceRepository(testsupport.NewDB(t)))
+	registry := &fakeConsul{services: map[string][]string{}}
+	server := httptest.NewServer(registry)
+	defer server.Close()
+	ctx := context.Background()
+
+	run := func(policy string) {
+		t.Helper()
+		cfg := consul.Config{Address: server.URL, ConflictPolicy: policy}
+		require.NoError(t, consul.NewImporter(cfg, svc, nil, nil).Import(ctx))
+	}
+	find := func(name string) *domain.ServiceWithVersions {
+		t.Helper()
+		list, err := svc.GetServices(ctx, domain.ServiceQuery{SortBy: "name", SortDir: "asc", Page: 1, PageSize: 100})
+		require.NoError(t, err)
+		for _, s := range list.Services {
+			if s.Name == name {
+				found, err := svc.GetServiceByID(ctx, s.ID)
+				require.NoError(t, err)
+				return found
+			}
+		}
+		return nil
+	}
+	versions := func(service *domain.ServiceWithVersions) []string {
+		list := []string{}
+		for _, v := range service.Versions {
+			list = append(list, v.Version)
+		}
+		return list
+	}
+
+	// "Locate Us" is seeded, owned by web-team and tagged maps and public
+	registry.set("orders", "owner=orders-team", "version=1.0.0", "http")
+	registry.set("Locate Us", "owner=maps-team", "version=3.0.0", "geo")
+	registry.set("broken", "version="+strings.Repeat("1", 60))
+	run(domain.ConflictSkip)
+
+	orders := find("orders")
+	require.NotNil(t, orders)
+	assert.Equal(t, "orders-team", orders.Owner)
+	assert.Equal(t, domain.StatusActive, orders.Status)
+	assert.Equal(t, []string{"http"}, orders.Tags)
+	assert.Equal(t, []string{"1.0.0"}, versions(orders))
+	assert.Nil(t, find("broken"))
+	locate := find("Locate Us")
+	assert.Equal(t, "web-team", locate.Owner)
+	assert.Len(t, locate.Versions, 3)
+
+	// Imported services follow Consul: set fields and tags are replaced,
+	// versions added
+	registry.set("orders", "status=deprecated", "version=1.0.0", "version=1.1.0", "grpc")
+	run(domain.ConflictSkip)
+	orders = find("orders")
+	assert.Equal(t, "orders-team", orders.Owner)
+	assert.Equal(t, domain.StatusDeprecated, orders.Status)
+	assert.Equal(t, []string{"grpc"}, orders.Tags)
+	assert.ElementsMatch(t, []string{"1.0.0", "1.1.0"}, versions(orders))
+
+	// Merging adds the tags and versions only
+	run(domain.ConflictMerge)
+	locate = find("Locate Us")
+	assert.Equal(t, "web-team", locate.Owner)
+	assert.Equal(t, []string{"geo", "maps", "public"}, locate.Tags)
+	assert.Contains(t, versions(locate), "3.0.0")
+
+	// Overwriting takes the service over for good
+	run(domain.ConflictOverwrite)
+	locate = find("Locate Us")
+	assert.Equal(t, "maps-team", locate.Owner)
+	assert.Equal(t, []string{"geo"}, locate.Tags)
+	registry.set("Locate Us", "owner=geo-team")
+	run(domain.ConflictSkip)
+	assert.Equal(t, "geo-team", find("Locate Us").Owner)
+
+	// Imports are audited as made by the importer
+	logs, err := svc.GetAuditLogs(ctx, domain.AuditQuery{ResourceType: domain.AuditResourceService, ResourceID: orders.ID, Page: 1, PageSize: 10})
+	require.NoError(t, err)
+	require.NotEmpty(t, logs.Entries)
+	assert.Equal(t, consul.Source, logs.Entries[0].Principal)
+}
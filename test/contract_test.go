@@ -0,0 +1,289 @@
+package integration
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/getkin/kin-openapi/routers/gorillamux"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func init() {
+	// Documentation pages are validated as the text they are
+	openapi3filter.RegisterBodyDecoder("text/html", openapi3filter.PlainBodyDecoder)
+}
+
+// contract replays requests through the router and validates every response
+// against the OpenAPI document the router publishes
+type contract struct {
+	t       *testing.T
+	handler http.Handler
+	doc     *openapi3.T
+	routes  routers.Router
+	// exercised holds the documented operations requested, as "METHOD path"
+	exercised map[string]bool
+}
+
+// newContract loads the OpenAPI document published by router
+func newContract(t *testing.T, router http.Handler) *contract {
+	t.Helper()
+
+	response := doRequest(t, router, http.MethodGet, "/api/v1/openapi.yaml", "", nil)
+	require.Equal(t, http.StatusOK, response.Code)
+	loader := openapi3.NewLoader()
+	doc, err := loader.LoadFromData(response.Body.Bytes())
+	require.NoError(t, err)
+	require.NoError(t, doc.Validate(loader.Context), "the OpenAPI document is invalid")
+	routes, err := gorillamux.NewRouter(doc)
+	require.NoError(t, err)
+
+	c := &contract{t: t, handler: router, doc: doc, routes: routes, exercised: map[string]bool{}}
+	c.validate(http.MethodGet, "/api/v1/openapi.yaml", response)
+	return c
+}
+
+// do performs a request as doRequest does, failing the test unless its
+// operation is documented and the response matches the document
+func (c *contract) do(method, path, token string, body interface{}) *httptest.ResponseRecorder {
+	c.t.Helper()
+	response := doRequest(c.t, c.handler, method, path, token, body)
+	c.validate(method, path, response)
+	return response
+}
+
+func (c *contract) validate(method, path string, response *httptest.ResponseRecorder) {
+	c.t.Helper()
+
+	req := httptest.NewRequest(method, path, nil)
+	route, pathParams, err := c.routes.FindRoute(req)
+	require.NoError(c.t, err, "%s %s is not documented", method, path)
+	c.exercised[method+" "+route.Path] = true
+
+	err = openapi3filter.ValidateResponse(context.Background(), &openapi3filter.ResponseValidationInput{
+		RequestValidationInput: &openapi3filter.RequestValidationInput{
+			Request:    req,
+			PathParams: pathParams,
+			Route:      route,
+		},
+		Status: response.Code,
+		Header: response.Header(),
+		Body:   io.NopCloser(bytes.NewReader(response.Body.Bytes())),
+		Options: &openapi3filter.Options{
+			IncludeResponseStatus: true,
+			MultiError:            true,
+		},
+	})
+	assert.NoError(c.t, err, "%s %s answered %d, not as documented: %s", method, path, response.Code, response.Body.String())
+}
+
+// operations returns the operations of the document, as "METHOD path"
+func (c *contract) operations() []string {
+	var operations []string
+	for path, item := range c.doc.Paths.Map() {
+		for method := range item.Operations() {
+			operations = append(operations, method+" "+path)
+		}
+	}
+	slices.Sort(operations)
+	return operations
+}
+
+func TestResponsesMatchOpenAPIDocument(t *testing.T) {
+	c := newContract(t, newTestRouter(t))
+
+	c.do(http.MethodGet, "/health", "", nil)
+
+	// Listings, including their errors
+	c.do(http.MethodGet, "/api/v1/services?page=2&page_size=3", "viewer-token", nil)
+	c.do(http.MethodGet, "/api/v1/services?has_versions=true&min_versions=2&sort_by=name&sort_dir=desc", "viewer-token", nil)
+	c.do(http.MethodGet, "/api/v1/services?search=contact&environment=production", "viewer-token", nil)
+	c.do(http.MethodGet, "/api/v1/services?page_size=1000&sort_by=colour", "viewer-token", nil)
+	c.do(http.MethodGet, "/api/v1/services", "", nil)
+	c.do(http.MethodGet, "/api/v1/search?q=contact", "viewer-token", nil)
+	c.do(http.MethodGet, "/api/v1/search", "viewer-token", nil)
+	c.do(http.MethodGet, "/api/v1/stats?recent=3", "viewer-token", nil)
+
+	// The lifecycle of a service
+	input := map[string]interface{}{
+		"name":        "Contract Testing",
+		"description": "Checks responses against the OpenAPI document",
+		"owner":       "platform",
+		"tags":        []string{"testing"},
+	}
+	assert.Equal(t, http.StatusForbidden, c.do(http.MethodPost, "/api/v1/services", "viewer-token", input).Code)
+	response := c.do(http.MethodPost, "/api/v1/services", "admin-token", input)
+	require.Equal(t, http.StatusCreated, response.Code)
+	var created struct{ ID int }
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &created))
+	servicePath := "/api/v1/services/" + itoa(created.ID)
+	assert.Equal(t, http.StatusConflict, c.do(http.MethodPost, "/api/v1/services", "admin-token", input).Code)
+	assert.Equal(t, http.StatusBadRequest, c.do(http.MethodPost, "/api/v1/services", "admin-token", map[string]string{"name": ""}).Code)
+
+	input["status"] = "deprecated"
+	c.do(http.MethodPut, servicePath, "admin-token", input)
+	c.do(http.MethodGet, servicePath+"/versions?sort_by=created_at", "viewer-token", nil)
+	response = c.do(http.MethodPost, servicePath+"/versions", "admin-token", map[string]string{"version": "v1.2"})
+	require.Equal(t, http.StatusCreated, response.Code)
+	var version struct{ ID int }
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &version))
+	c.do(http.MethodPost, servicePath+"/versions", "admin-token", map[string]string{"version": "not a version"})
+	c.do(http.MethodGet, servicePath+"/versions?sort_by=semver&sort_dir=desc", "viewer-token", nil)
+	c.do(http.MethodPut, servicePath+"/translations/de", "admin-token", map[string]string{"description": "Prüft Antworten"})
+	c.do(http.MethodPut, servicePath+"/translations/xx", "admin-token", map[string]string{"description": "?"})
+	c.do(http.MethodGet, servicePath, "viewer-token", nil)
+	c.do(http.MethodDelete, servicePath+"/translations/de", "admin-token", nil)
+
+	// Operations, sunsets and environments
+	c.do(http.MethodPut, servicePath+"/health-check", "admin-token", map[string]string{"url": "https://contract.example.com/healthz"})
+	c.do(http.MethodPut, servicePath+"/health-check", "admin-token", map[string]string{"url": "ftp://contract.example.com/"})
+	c.do(http.MethodGet, servicePath+"/health?page_size=10", "viewer-token", nil)
+	c.do(http.MethodDelete, servicePath+"/health-check", "admin-token", nil)
+	c.do(http.MethodPut, servicePath+"/slo", "admin-token", map[string]interface{}{"availability_target": 99.9, "latency_target_ms": 300})
+	c.do(http.MethodGet, "/api/v1/slo-report", "viewer-token", nil)
+	c.do(http.MethodGet, "/api/v1/slo-report?since=yesterday", "viewer-token", nil)
+	c.do(http.MethodDelete, servicePath+"/slo", "admin-token", nil)
+	c.do(http.MethodPut, servicePath+"/repository", "admin-token", map[string]string{"url": "https://github.com/kong/contract"})
+	c.do(http.MethodDelete, servicePath+"/repository", "admin-token", nil)
+	sunset := map[string]string{"archive_at": "2099-01-01T00:00:00Z"}
+	c.do(http.MethodPut, servicePath+"/sunset", "admin-token", sunset)
+	c.do(http.MethodDelete, servicePath+"/sunset", "admin-token", nil)
+	c.do(http.MethodPut, servicePath+"/versions/"+itoa(version.ID)+"/sunset", "admin-token", sunset)
+	c.do(http.MethodDelete, servicePath+"/versions/"+itoa(version.ID)+"/sunset", "admin-token", nil)
+	c.do(http.MethodPut, servicePath+"/environments/prod", "admin-token", map[string]string{"version": "v1.2"})
+	c.do(http.MethodPut, servicePath+"/environments/prod", "admin-token", map[string]string{"version": "9.9.9"})
+	c.do(http.MethodDelete, servicePath+"/environments/prod", "admin-token", nil)
+
+	// Specs, uploaded and imported
+	spec := map[string]interface{}{
+		"openapi": "3.0.3",
+		"info":    map[string]string{"title": "Contract Testing", "version": "1.2.0"},
+		"paths": map[string]interface{}{
+			"/checks": map[string]interface{}{"get": map[string]interface{}{"responses": map[string]interface{}{"200": map[string]string{"description": "The checks"}}}},
+		},
+	}
+	versionPath := servicePath + "/versions/" + itoa(version.ID)
+	c.do(http.MethodPut, versionPath+"/spec", "admin-token", spec)
+	c.do(http.MethodPut, versionPath+"/spec", "admin-token", map[string]string{"openapi": "3.0.3"})
+	c.do(http.MethodGet, versionPath+"/spec", "viewer-token", nil)
+	c.do(http.MethodGet, versionPath+"/docs", "viewer-token", nil)
+	c.do(http.MethodGet, versionPath+"/postman", "viewer-token", nil)
+	spec["info"] = map[string]string{"title": "Contract Import", "version": "2.0.0"}
+	response = c.do(http.MethodPost, "/api/v1/services:import", "admin-token", spec)
+	require.Equal(t, http.StatusCreated, response.Code, response.Body.String())
+	var imported struct{ ID int }
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &imported))
+	c.do(http.MethodPost, "/api/v1/services:import", "admin-token", spec)
+	c.do(http.MethodGet, servicePath+"/versions/compare?from=1.2.0&to=1.2.0", "viewer-token", nil)
+	c.do(http.MethodGet, servicePath+"/versions/compare?from=1.2.0", "viewer-token", nil)
+	c.do(http.MethodDelete, versionPath+"/spec", "admin-token", nil)
+	c.do(http.MethodDelete, "/api/v1/services/"+itoa(imported.ID), "admin-token", nil)
+
+	// Comments, ownership transfers and what users follow
+	response = c.do(http.MethodPost, servicePath+"/comments", "viewer-token", map[string]string{"body": "Is there a sandbox, @admin?"})
+	require.Equal(t, http.StatusCreated, response.Code)
+	var comment struct{ ID int }
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &comment))
+	c.do(http.MethodPost, servicePath+"/comments", "admin-token", map[string]interface{}{"body": "Yes", "parent_id": comment.ID})
+	c.do(http.MethodGet, servicePath+"/comments", "viewer-token", nil)
+	c.do(http.MethodDelete, servicePath+"/comments/"+itoa(comment.ID), "admin-token", nil)
+	c.do(http.MethodPost, servicePath+"/transfer-ownership", "admin-token", map[string]string{"owner": "viewer"})
+	c.do(http.MethodPost, servicePath+"/transfer-ownership/decline", "admin-token", nil)
+	c.do(http.MethodPost, servicePath+"/transfer-ownership", "admin-token", map[string]interface{}{"owner": "viewer", "override": true})
+	c.do(http.MethodPost, servicePath+"/transfer-ownership/confirm", "admin-token", nil)
+	c.do(http.MethodGet, servicePath+"/ownership-transfers", "viewer-token", nil)
+	c.do(http.MethodPut, servicePath+"/favorite", "viewer-token", nil)
+	c.do(http.MethodDelete, servicePath+"/favorite", "viewer-token", nil)
+	c.do(http.MethodPut, servicePath+"/subscription", "viewer-token", nil)
+	c.do(http.MethodGet, "/api/v1/me/subscriptions", "viewer-token", nil)
+	c.do(http.MethodDelete, servicePath+"/subscription", "viewer-token", nil)
+	c.do(http.MethodPut, "/api/v1/me/notification-preferences", "viewer-token", map[string]interface{}{"email": "viewer@example.com", "deprecations": true})
+	c.do(http.MethodGet, "/api/v1/me/notification-preferences", "viewer-token", nil)
+
+	c.do(http.MethodDelete, servicePath+"/versions/"+itoa(version.ID), "admin-token", nil)
+	c.do(http.MethodDelete, servicePath, "admin-token", nil)
+	assert.Equal(t, http.StatusNotFound, c.do(http.MethodGet, servicePath, "viewer-token", nil).Code)
+	c.do(http.MethodGet, "/api/v1/services/abc", "viewer-token", nil)
+
+	// The catalog as a whole
+	c.do(http.MethodPost, "/api/v1/catalog:apply?dry_run=true", "admin-token", map[string]interface{}{
+		"services": []map[string]interface{}{{"name": "Contract Testing", "owner": "platform", "versions": []string{"1.0.0"}}},
+	})
+	c.do(http.MethodPost, "/api/v1/catalog:apply?dry_run=maybe", "admin-token", map[string]interface{}{"services": []string{}})
+	c.do(http.MethodGet, "/api/v1/analytics?days=7&limit=5", "admin-token", nil)
+	c.do(http.MethodGet, "/api/v1/audit-logs?resource_type=service&page_size=5", "admin-token", nil)
+	c.do(http.MethodGet, "/api/v1/audit-logs", "viewer-token", nil)
+
+	// Administration
+	c.do(http.MethodPost, "/api/v1/users", "admin-token", map[string]interface{}{"username": "carol", "roles": []string{"viewer"}})
+	c.do(http.MethodPost, "/api/v1/users", "admin-token", map[string]interface{}{"username": "carol", "roles": []string{"viewer"}})
+	c.do(http.MethodGet, "/api/v1/users", "admin-token", nil)
+	response = c.do(http.MethodPost, "/api/v1/tokens", "admin-token", map[string]string{"username": "carol", "name": "ci"})
+	require.Equal(t, http.StatusCreated, response.Code, response.Body.String())
+	var token struct{ ID int }
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &token))
+	c.do(http.MethodDelete, "/api/v1/tokens/"+itoa(token.ID), "admin-token", nil)
+	c.do(http.MethodGet, "/api/v1/tokens", "admin-token", nil)
+	c.do(http.MethodPost, "/api/v1/users/carol/disable", "admin-token", nil)
+	c.do(http.MethodPost, "/api/v1/users/nobody/disable", "admin-token", nil)
+	c.do(http.MethodPost, "/api/v1/organizations", "admin-token", map[string]string{"slug": "contract"})
+	c.do(http.MethodPost, "/api/v1/organizations", "admin-token", map[string]string{"slug": "Not A Slug"})
+	c.do(http.MethodGet, "/api/v1/organizations", "admin-token", nil)
+	response = c.do(http.MethodPost, "/api/v1/webhooks", "admin-token", map[string]interface{}{"url": "https://hooks.example.com/catalog", "events": []string{"service.created"}})
+	require.Equal(t, http.StatusCreated, response.Code, response.Body.String())
+	var webhook struct{ ID int }
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &webhook))
+	c.do(http.MethodGet, "/api/v1/webhooks", "admin-token", nil)
+	c.do(http.MethodGet, "/api/v1/webhooks/"+itoa(webhook.ID)+"/deliveries", "admin-token", nil)
+	c.do(http.MethodDelete, "/api/v1/webhooks/"+itoa(webhook.ID), "admin-token", nil)
+
+	var missing []string
+	for _, operation := range c.operations() {
+		if !c.exercised[operation] {
+			missing = append(missing, operation)
+		}
+	}
+	assert.Empty(t, missing, "documented operations the contract test never requests")
+}
+
+func TestRoutesAreDocumented(t *testing.T) {
+	router := newTestRouter(t)
+	c := newContract(t, router)
+
+	var routes []string
+	err := router.(*mux.Router).Walk(func(route *mux.Route, _ *mux.Router, _ []*mux.Route) error {
+		path, err := route.GetPathTemplate()
+		if err != nil {
+			return nil
+		}
+		methods, err := route.GetMethods()
+		if err != nil {
+			return nil
+		}
+		for _, method := range methods {
+			routes = append(routes, method+" "+path)
+		}
+		return nil
+	})
+	require.NoError(t, err)
+
+	documented := c.operations()
+	for _, route := range routes {
+		if !slices.Contains(documented, route) {
+			t.Errorf("%s is not documented in handler/openapi.yaml", route)
+		}
+	}
+	for _, operation := range documented {
+		assert.Contains(t, routes, operation, "documented operation is not routed")
+	}
+}
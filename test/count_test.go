@@ -0,0 +1,53 @@
+package integration
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"com.kong.connect/database"
+	"com.kong.connect/domain"
+	"com.kong.connect/testsupport"
+)
+
+func TestUnfilteredListingsServeTheMaintainedCount(t *testing.T) {
+	router := newTestRouter(t)
+
+	total := func(query string) int {
+		response := doRequest(t, router, "GET", "/api/v1/services"+query, "viewer-token", nil)
+		require.Equal(t, http.StatusOK, response.Code, response.Body.String())
+		var listing domain.ServiceListResponse
+		require.NoError(t, json.Unmarshal(response.Body.Bytes(), &listing))
+		return listing.Total
+	}
+	require.Equal(t, 8, total(""))
+
+	response := doRequest(t, router, "POST", "/api/v1/services", "admin-token", domain.ServiceInput{Name: "Billing", Description: "Invoices"})
+	require.Equal(t, http.StatusCreated, response.Code, response.Body.String())
+	assert.Equal(t, 9, total(""))
+	assert.Equal(t, 1, total("?search=invoices"))
+
+	for _, id := range []string{"1", "2"} {
+		response = doRequest(t, router, "DELETE", "/api/v1/services/"+id, "admin-token", nil)
+		require.Equal(t, http.StatusNoContent, response.Code, response.Body.String())
+	}
+	assert.Equal(t, 7, total(""))
+}
+
+func TestMigrateRecountsServices(t *testing.T) {
+	db := testsupport.NewEmptyDB(t)
+
+	// Rows written while the triggers were missing, e.g. by an older release
+	_, err := db.Exec("DROP TRIGGER services_count_insert")
+	require.NoError(t, err)
+	_, err = db.Exec("INSERT INTO services (name, description) VALUES ('Billing', ''), ('Payments', '')")
+	require.NoError(t, err)
+
+	require.NoError(t, database.Migrate(db))
+	var count int
+	require.NoError(t, db.QueryRow("SELECT count FROM row_counts WHERE name = 'services:1'").Scan(&count))
+	assert.Equal(t, 2, count)
+}
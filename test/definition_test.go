@@ -0,0 +1,123 @@
+package integration
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"com.kong.connect/domain"
+	"com.kong.connect/events"
+	"com.kong.connect/handler"
+	"com.kong.connect/repository"
+	"com.kong.connect/service"
+	"com.kong.connect/testsupport"
+)
+
+const billingSwagger = `swagger: "2.0"
+info:
+  title: Billing
+  description: Invoices and payments
+  version: 3.2.0
+tags:
+  - name: Invoices
+  - name: A tag far too long to be a catalog tag, which is dropped
+paths:
+  /invoices:
+    get:
+      tags: [Invoices]
+      responses:
+        "200": {description: The invoices}
+`
+
+const shippingCollection = `{
+  "info": {
+    "name": "Shipping",
+    "description": "Ships parcels",
+    "schema": "https://schema.getpostman.com/json/collection/v2.1.0/collection.json"
+  },
+  "item": [
+    {"name": "Parcels", "item": [{"name": "Track", "request": {"method": "GET", "url": "{{host}}/parcels/:id"}}]},
+    {"name": "Rates", "item": [{"name": "Quote", "request": {"method": "POST", "url": "{{host}}/rates"}}]}
+  ]
+}`
+
+// importDefinition posts an API definition to the import endpoint
+func importDefinition(t *testing.T, router http.Handler, token, query, definition string) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest("POST", "/api/v1/services:import"+query, strings.NewReader(definition))
+	req.Header.Set("Authorization", "Bearer "+token)
+	response := httptest.NewRecorder()
+	router.ServeHTTP(response, req)
+	return response
+}
+
+func TestDefinitionsAreImportedAsServices(t *testing.T) {
+	bus := events.NewBus()
+	var published []string
+	bus.Subscribe(func(event domain.ChangeEvent) {
+		published = append(published, event.Type)
+	})
+	svc := service.NewServiceService(repository.NewServiceRepository(testsupport.NewDB(t)), service.WithPublisher(bus))
+	router := handler.SetupRouter(handler.NewServiceHandler(svc))
+
+	response := importDefinition(t, router, "viewer-token", "", billingSwagger)
+	assert.Equal(t, http.StatusForbidden, response.Code)
+	for name, definition := range map[string]string{
+		"empty":        "",
+		"not an API":   "services: []",
+		"invalid spec": `{"openapi": "3.0.3", "info": {"title": "No version"}, "paths": {}}`,
+	} {
+		response := importDefinition(t, router, "admin-token", "", definition)
+		assert.Equal(t, http.StatusBadRequest, response.Code, name)
+	}
+	assert.Empty(t, published)
+
+	// Swagger files are converted to OpenAPI 3
+	response = importDefinition(t, router, "admin-token", "", billingSwagger)
+	require.Equal(t, http.StatusCreated, response.Code, response.Body.String())
+	var billing domain.ServiceWithVersions
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &billing))
+	assert.Equal(t, "Billing", billing.Name)
+	assert.Equal(t, "Invoices and payments", billing.Description)
+	assert.Equal(t, domain.StatusActive, billing.Status)
+	assert.Equal(t, []string{"invoices"}, billing.Tags)
+	require.Len(t, billing.Versions, 1)
+	assert.Equal(t, "3.2.0", billing.Versions[0].Version)
+	require.NotNil(t, billing.Versions[0].Spec)
+	assert.Equal(t, "3.0.3", billing.Versions[0].Spec.OpenAPI)
+	assert.Equal(t, 1, billing.Versions[0].Spec.Operations)
+	assert.Equal(t, []string{domain.EventServiceCreated, domain.EventVersionCreated, domain.EventVersionSpecUpdated}, published)
+
+	response = doRequest(t, router, "GET", "/api/v1/services/"+itoa(billing.ID)+"/versions/"+itoa(billing.Versions[0].ID)+"/spec", "viewer-token", nil)
+	require.Equal(t, http.StatusOK, response.Code)
+	assert.Equal(t, "application/json", response.Header().Get("Content-Type"))
+	assert.Contains(t, response.Body.String(), `"openapi":"3.0.3"`)
+
+	response = importDefinition(t, router, "admin-token", "", billingSwagger)
+	assert.Equal(t, http.StatusConflict, response.Code, "names are unique")
+
+	// The name, owner and version of a definition can be overridden
+	response = importDefinition(t, router, "admin-token", "?name=Parcel+Shipping&owner=logistics&version=0.9.0", shippingCollection)
+	require.Equal(t, http.StatusCreated, response.Code, response.Body.String())
+	var shipping domain.ServiceWithVersions
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &shipping))
+	assert.Equal(t, "Parcel Shipping", shipping.Name)
+	assert.Equal(t, "Ships parcels", shipping.Description)
+	assert.Equal(t, "logistics", shipping.Owner)
+	assert.Equal(t, []string{"parcels", "rates"}, shipping.Tags)
+	require.Len(t, shipping.Versions, 1)
+	assert.Equal(t, "0.9.0", shipping.Versions[0].Version)
+	assert.Equal(t, 2, shipping.Versions[0].Spec.Paths)
+
+	// Imports are audited as created services and versions
+	response = doRequest(t, router, "GET", "/api/v1/audit-logs?action=create&resource_type=version", "admin-token", nil)
+	require.Equal(t, http.StatusOK, response.Code)
+	var audit domain.AuditListResponse
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &audit))
+	assert.Equal(t, 2, audit.Total)
+}
@@ -0,0 +1,111 @@
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"com.kong.connect/domain"
+	"com.kong.connect/email"
+	"com.kong.connect/handler"
+	"com.kong.connect/repository"
+	"com.kong.connect/service"
+	"com.kong.connect/testsupport"
+)
+
+// sentEmail is a message given to fakeSender
+type sentEmail struct {
+	to      string
+	message string
+}
+
+// fakeSender records the messages instead of sending them
+type fakeSender chan sentEmail
+
+func (s fakeSender) Send(_ context.Context, _, to string, message []byte) error {
+	s <- sentEmail{to: to, message: string(message)}
+	return nil
+}
+
+func TestOwnersAndSubscribersAreEmailed(t *testing.T) {
+	sent := make(fakeSender, 10)
+	repo := repository.NewServiceRepository(testsupport.NewDB(t))
+	notifier, err := email.NewNotifier(email.Config{From: "catalog@example.com"}, sent, repo, nil)
+	require.NoError(t, err)
+	router := handler.SetupRouter(handler.NewServiceHandler(service.NewServiceService(repo, service.WithPublisher(notifier))))
+
+	// Users receive every notification once they set an email address
+	response := doRequest(t, router, "GET", "/api/v1/me/notification-preferences", "viewer-token", nil)
+	require.Equal(t, http.StatusOK, response.Code)
+	assert.JSONEq(t, `{"email":"","deprecations":true,"ownership_changes":true,"mentions":true}`, response.Body.String())
+
+	response = doRequest(t, router, "PUT", "/api/v1/me/notification-preferences", "viewer-token",
+		domain.NotificationPreferences{Email: "Viewer <viewer@example.com>", Deprecations: true})
+	assert.Equal(t, http.StatusBadRequest, response.Code)
+	response = doRequest(t, router, "PUT", "/api/v1/me/notification-preferences", "viewer-token",
+		domain.NotificationPreferences{Email: " viewer@example.com ", Deprecations: true})
+	require.Equal(t, http.StatusOK, response.Code, response.Body.String())
+	assert.JSONEq(t, `{"email":"viewer@example.com","deprecations":true,"ownership_changes":false,"mentions":false}`, response.Body.String())
+	response = doRequest(t, router, "PUT", "/api/v1/me/notification-preferences", "admin-token",
+		domain.NotificationPreferences{Email: "admin@example.com", Deprecations: true, OwnershipChanges: true})
+	require.Equal(t, http.StatusOK, response.Code, response.Body.String())
+
+	// The viewer subscribes to "Locate Us", owned by web-team
+	response = doRequest(t, router, "PUT", "/api/v1/services/1/subscription", "viewer-token", nil)
+	require.Equal(t, http.StatusNoContent, response.Code, response.Body.String())
+	response = doRequest(t, router, "PUT", "/api/v1/services/1/subscription", "viewer-token", nil)
+	require.Equal(t, http.StatusNoContent, response.Code, "subscribing twice is a no-op")
+	response = doRequest(t, router, "PUT", "/api/v1/services/999/subscription", "viewer-token", nil)
+	assert.Equal(t, http.StatusNotFound, response.Code)
+
+	response = doRequest(t, router, "GET", "/api/v1/me/subscriptions", "viewer-token", nil)
+	require.Equal(t, http.StatusOK, response.Code)
+	var subscriptions domain.SubscriptionListResponse
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &subscriptions))
+	require.Len(t, subscriptions.Subscriptions, 1)
+	assert.Equal(t, "Locate Us", subscriptions.Subscriptions[0].ServiceName)
+
+	receive := func() sentEmail {
+		t.Helper()
+		select {
+		case message := <-sent:
+			return message
+		case <-time.After(5 * time.Second):
+			t.Fatal("no email sent")
+			return sentEmail{}
+		}
+	}
+
+	// The ownership goes to the admin, who is emailed as the new owner; the
+	// viewer turned ownership changes off
+	response = doRequest(t, router, "PUT", "/api/v1/services/1", "admin-token",
+		domain.ServiceInput{Name: "Locate Us", Owner: "admin", Status: domain.StatusActive})
+	require.Equal(t, http.StatusOK, response.Code, response.Body.String())
+	message := receive()
+	assert.Equal(t, "admin@example.com", message.to)
+	assert.Contains(t, message.message, "Subject: [Kong Connect] Locate Us is now owned by admin\r\n")
+	assert.Contains(t, message.message, "from web-team to admin.")
+
+	// The deprecation reaches the owner and the subscriber
+	response = doRequest(t, router, "PUT", "/api/v1/services/1", "admin-token",
+		domain.ServiceInput{Name: "Locate Us", Owner: "admin", Status: domain.StatusDeprecated})
+	require.Equal(t, http.StatusOK, response.Code, response.Body.String())
+	recipients := []string{receive().to, receive().to}
+	assert.ElementsMatch(t, []string{"admin@example.com", "viewer@example.com"}, recipients)
+	select {
+	case message := <-sent:
+		t.Fatalf("unexpected email to %s: %s", message.to, strings.SplitN(message.message, "\r\n", 4)[2])
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	response = doRequest(t, router, "DELETE", "/api/v1/services/1/subscription", "viewer-token", nil)
+	assert.Equal(t, http.StatusNoContent, response.Code)
+	response = doRequest(t, router, "DELETE", "/api/v1/services/1/subscription", "viewer-token", nil)
+	assert.Equal(t, http.StatusNotFound, response.Code)
+}
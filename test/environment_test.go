@@ -0,0 +1,80 @@
+package integration
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"com.kong.connect/domain"
+)
+
+func TestEnvironmentsRunDifferentVersions(t *testing.T) {
+	router := newTestRouter(t)
+
+	// Service 1 is seeded with versions 1.0.0, 1.1.0 and 2.0.0
+	deploy := func(environment, version string) *domain.ServiceVersion {
+		response := doRequest(t, router, "PUT", "/api/v1/services/1/environments/"+environment, "admin-token", domain.DeploymentInput{Version: version})
+		require.Equal(t, http.StatusOK, response.Code, response.Body.String())
+		var deployed domain.ServiceVersion
+		require.NoError(t, json.Unmarshal(response.Body.Bytes(), &deployed))
+		return &deployed
+	}
+	assert.Equal(t, []string{"prod"}, deploy("prod", "1.1.0").Environments)
+	assert.Equal(t, []string{"prod", "staging"}, deploy("Staging", "1.1.0").Environments)
+	assert.Equal(t, []string{"staging"}, deploy("staging", "2.0.0").Environments)
+
+	response := doRequest(t, router, "GET", "/api/v1/services/1", "viewer-token", nil)
+	require.Equal(t, http.StatusOK, response.Code)
+	var service domain.ServiceWithVersions
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &service))
+	environments := map[string][]string{}
+	for _, version := range service.Versions {
+		environments[version.Version] = version.Environments
+	}
+	assert.Equal(t, map[string][]string{"2.0.0": {"staging"}, "1.1.0": {"prod"}, "1.0.0": nil}, environments)
+
+	listed := func(query string) []int {
+		response := doRequest(t, router, "GET", "/api/v1/services"+query, "viewer-token", nil)
+		require.Equal(t, http.StatusOK, response.Code, response.Body.String())
+		var listing domain.ServiceListResponse
+		require.NoError(t, json.Unmarshal(response.Body.Bytes(), &listing))
+		ids := []int{}
+		for _, service := range listing.Services {
+			ids = append(ids, service.ID)
+		}
+		assert.Equal(t, len(ids), listing.Total)
+		return ids
+	}
+	assert.Equal(t, []int{1}, listed("?environment=prod"))
+	assert.Equal(t, []int{1}, listed("?environment=staging&search=locate"))
+	assert.Empty(t, listed("?environment=dev"))
+
+	for _, request := range []struct {
+		method, path string
+		body         interface{}
+		status       int
+	}{
+		{"PUT", "/api/v1/services/1/environments/prod", domain.DeploymentInput{Version: "9.9.9"}, http.StatusNotFound},
+		{"PUT", "/api/v1/services/999/environments/prod", domain.DeploymentInput{Version: "1.0.0"}, http.StatusNotFound},
+		{"PUT", "/api/v1/services/1/environments/pr%20od", domain.DeploymentInput{Version: "1.0.0"}, http.StatusBadRequest},
+		{"DELETE", "/api/v1/services/1/environments/dev", nil, http.StatusNotFound},
+	} {
+		response = doRequest(t, router, request.method, request.path, "admin-token", request.body)
+		assert.Equal(t, request.status, response.Code, request.path)
+	}
+	response = doRequest(t, router, "PUT", "/api/v1/services/1/environments/prod", "viewer-token", domain.DeploymentInput{Version: "1.0.0"})
+	assert.Equal(t, http.StatusForbidden, response.Code)
+
+	// Undeploying, or deleting the version an environment runs, takes the
+	// service out of the environment
+	response = doRequest(t, router, "DELETE", "/api/v1/services/1/environments/prod", "admin-token", nil)
+	require.Equal(t, http.StatusNoContent, response.Code, response.Body.String())
+	assert.Empty(t, listed("?environment=prod"))
+	staged := deploy("staging", "2.0.0")
+	response = doRequest(t, router, "DELETE", "/api/v1/services/1/versions/"+itoa(staged.ID), "admin-token", nil)
+	require.Equal(t, http.StatusNoContent, response.Code, response.Body.String())
+	assert.Empty(t, listed("?environment=staging"))
+}
@@ -0,0 +1,52 @@
+package integration
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"com.kong.connect/domain"
+	"com.kong.connect/problem"
+)
+
+func TestServiceErrorsAreProblemsOfTheirKind(t *testing.T) {
+	router := newTestRouter(t)
+
+	// Viewers may only delete their own comments
+	response := doRequest(t, router, "POST", "/api/v1/services/1/comments", "admin-token", domain.CommentInput{Body: "Pinned"})
+	require.Equal(t, http.StatusCreated, response.Code, response.Body.String())
+	var comment domain.Comment
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &comment))
+
+	for _, test := range []struct {
+		method, path, token string
+		body                interface{}
+		want                problem.Details
+	}{
+		{"GET", "/api/v1/services/999", "viewer-token", nil,
+			problem.Details{Type: problem.TypeNotFound, Status: http.StatusNotFound, Detail: "Service not found"}},
+		{"GET", "/api/v1/services/999/versions", "viewer-token", nil,
+			problem.Details{Type: problem.TypeNotFound, Status: http.StatusNotFound, Detail: "Service not found"}},
+		{"DELETE", "/api/v1/services/1/slo", "admin-token", nil,
+			problem.Details{Type: problem.TypeNotFound, Status: http.StatusNotFound, Detail: "SLO not found"}},
+		{"POST", "/api/v1/services", "admin-token", domain.ServiceInput{},
+			problem.Details{Type: problem.TypeValidation, Status: http.StatusBadRequest, Detail: "invalid service: name is required"}},
+		{"POST", "/api/v1/services", "admin-token", domain.ServiceInput{Name: "Locate Us"},
+			problem.Details{Type: problem.TypeConflict, Status: http.StatusConflict, Detail: "Service already exists"}},
+		{"DELETE", "/api/v1/services/1/comments/" + itoa(comment.ID), "viewer-token", nil,
+			problem.Details{Type: problem.TypeForbidden, Status: http.StatusForbidden, Detail: "only the author of a comment or an admin can delete it"}},
+	} {
+		response := doRequest(t, router, test.method, test.path, test.token, test.body)
+		require.Equal(t, test.want.Status, response.Code, "%s %s", test.method, test.path)
+		assert.Equal(t, "application/problem+json", response.Header().Get("Content-Type"))
+		var details problem.Details
+		require.NoError(t, json.Unmarshal(response.Body.Bytes(), &details))
+		test.want.Title = http.StatusText(test.want.Status)
+		test.want.Instance = test.path
+		details.RequestID = ""
+		assert.Equal(t, test.want, details, "%s %s", test.method, test.path)
+	}
+}
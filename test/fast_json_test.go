@@ -0,0 +1,28 @@
+package integration
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"com.kong.connect/handler"
+	"com.kong.connect/repository"
+	"com.kong.connect/service"
+	"com.kong.connect/testsupport"
+)
+
+func TestFastJSONMatchesEncodingJSON(t *testing.T) {
+	svc := service.NewServiceService(repository.NewServiceRepository(testsupport.NewDB(t)))
+	standard := handler.SetupRouter(handler.NewServiceHandler(svc))
+	fast := handler.SetupRouter(handler.NewServiceHandler(svc, handler.WithFastJSON()))
+
+	for _, path := range []string{"/api/v1/services?page_size=5&sort_by=updated_at", "/api/v1/services?search=none", "/api/v1/services/3"} {
+		expected := doRequest(t, standard, "GET", path, "viewer-token", nil)
+		got := doRequest(t, fast, "GET", path, "viewer-token", nil)
+		require.Equal(t, http.StatusOK, got.Code, path)
+		assert.Equal(t, expected.Header().Get("Content-Type"), got.Header().Get("Content-Type"), path)
+		assert.Equal(t, expected.Body.String(), got.Body.String(), path)
+	}
+}
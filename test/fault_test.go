@@ -0,0 +1,54 @@
+package integration
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"com.kong.connect/handler"
+	"com.kong.connect/middleware"
+	"com.kong.connect/service"
+	"com.kong.connect/testsupport"
+)
+
+func newFaultyRouter(t *testing.T, faults middleware.Faults) http.Handler {
+	t.Helper()
+	serviceHandler := handler.NewServiceHandler(service.NewServiceService(testsupport.NewRepository(t)))
+	return handler.SetupRouter(serviceHandler, handler.WithFaultInjection(faults))
+}
+
+func TestFaultInjectionFailsAPIRequests(t *testing.T) {
+	router := newFaultyRouter(t, middleware.Faults{ErrorPercent: 100, ErrorStatuses: []int{http.StatusBadGateway}})
+
+	response := doRequest(t, router, "GET", "/api/v1/services", "viewer-token", nil)
+	assert.Equal(t, http.StatusBadGateway, response.Code)
+	assert.Equal(t, "application/problem+json", response.Header().Get("Content-Type"))
+	assert.Equal(t, "error", response.Header().Get(middleware.FaultHeader))
+	assert.NotEmpty(t, response.Header().Get("X-Request-ID"), "injected errors pass through the middleware stack")
+
+	response = doRequest(t, router, "GET", "/health", "", nil)
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.Empty(t, response.Header().Get(middleware.FaultHeader))
+}
+
+func TestFaultInjectionDropsConnections(t *testing.T) {
+	server := httptest.NewServer(newFaultyRouter(t, middleware.Faults{DropPercent: 100}))
+	t.Cleanup(server.Close)
+
+	req, err := http.NewRequest("GET", server.URL+"/api/v1/services", nil)
+	require.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer viewer-token")
+	response, err := http.DefaultClient.Do(req)
+	if err == nil {
+		response.Body.Close()
+	}
+	assert.Error(t, err, "the connection is closed without a response")
+
+	response, err = http.Get(server.URL + "/health")
+	require.NoError(t, err)
+	response.Body.Close()
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+}
@@ -0,0 +1,68 @@
+package integration
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"com.kong.connect/domain"
+	"com.kong.connect/handler"
+	"com.kong.connect/repository"
+	"com.kong.connect/service"
+	"com.kong.connect/testsupport"
+)
+
+func TestFavoritesArePerUser(t *testing.T) {
+	svc := service.NewServiceService(repository.NewServiceRepository(testsupport.NewDB(t)))
+	router := handler.SetupRouter(handler.NewServiceHandler(svc))
+
+	listFavorites := func(token string) []int {
+		t.Helper()
+		response := doRequest(t, router, "GET", "/api/v1/services?favorites=true", token, nil)
+		require.Equal(t, http.StatusOK, response.Code, response.Body.String())
+		var listing domain.ServiceListResponse
+		require.NoError(t, json.Unmarshal(response.Body.Bytes(), &listing))
+		assert.Equal(t, len(listing.Services), listing.Total)
+		ids := []int{}
+		for _, s := range listing.Services {
+			ids = append(ids, s.ID)
+		}
+		return ids
+	}
+
+	assert.Empty(t, listFavorites("viewer-token"))
+
+	for _, id := range []int{1, 2, 2} {
+		response := doRequest(t, router, "PUT", "/api/v1/services/"+itoa(id)+"/favorite", "viewer-token", nil)
+		require.Equal(t, http.StatusNoContent, response.Code)
+	}
+	response := doRequest(t, router, "PUT", "/api/v1/services/999/favorite", "viewer-token", nil)
+	assert.Equal(t, http.StatusNotFound, response.Code)
+	response = doRequest(t, router, "PUT", "/api/v1/services/1/favorite", "admin-token", nil)
+	require.Equal(t, http.StatusNoContent, response.Code)
+
+	// Listed by name, like every listing
+	assert.Equal(t, []int{2, 1}, listFavorites("viewer-token"))
+	assert.Equal(t, []int{1}, listFavorites("admin-token"))
+
+	response = doRequest(t, router, "GET", "/api/v1/services?favorites=true&search=Locate", "viewer-token", nil)
+	require.Equal(t, http.StatusOK, response.Code)
+	assert.Equal(t, "no-store", response.Header().Get("Cache-Control"))
+	response = doRequest(t, router, "GET", "/api/v1/services?favorites=maybe", "viewer-token", nil)
+	assert.Equal(t, http.StatusBadRequest, response.Code)
+
+	response = doRequest(t, router, "DELETE", "/api/v1/services/2/favorite", "viewer-token", nil)
+	assert.Equal(t, http.StatusNoContent, response.Code)
+	response = doRequest(t, router, "DELETE", "/api/v1/services/2/favorite", "viewer-token", nil)
+	assert.Equal(t, http.StatusNotFound, response.Code)
+	assert.Equal(t, []int{1}, listFavorites("viewer-token"))
+
+	// Deleting a service unstars it
+	response = doRequest(t, router, "DELETE", "/api/v1/services/1", "admin-token", nil)
+	require.Equal(t, http.StatusNoContent, response.Code)
+	assert.Empty(t, listFavorites("viewer-token"))
+	assert.Empty(t, listFavorites("admin-token"))
+}
@@ -0,0 +1,107 @@
+package integration
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"com.kong.connect/domain"
+	"com.kong.connect/handler"
+	"com.kong.connect/repository"
+	"com.kong.connect/service"
+	"com.kong.connect/testsupport"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files of the response shape tests with the current responses")
+
+// Values that change from run to run, replaced in responses before they are
+// compared with their golden file
+var (
+	goldenTimestamps = regexp.MustCompile(`"\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}(\.\d+)?(Z|[+-]\d{2}:\d{2})"`)
+	goldenRequestIDs = regexp.MustCompile(`"request_id":"[^"]*"`)
+)
+
+// golden compares body, indented and with its volatile values replaced, with
+// testdata/golden/name.json, or rewrites the file with it when -update is set
+func golden(t *testing.T, name string, body []byte) {
+	t.Helper()
+
+	body = goldenTimestamps.ReplaceAll(body, []byte(`"<timestamp>"`))
+	body = goldenRequestIDs.ReplaceAll(body, []byte(`"request_id":"<request-id>"`))
+	var indented bytes.Buffer
+	require.NoError(t, json.Indent(&indented, bytes.TrimSpace(body), "", "  "))
+	indented.WriteByte('\n')
+
+	path := filepath.Join("testdata", "golden", name+".json")
+	if *update {
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, indented.Bytes(), 0o644))
+		return
+	}
+	expected, err := os.ReadFile(path)
+	require.NoError(t, err, "run go test ./test -run TestResponseShapes -update to create it")
+	assert.Equal(t, string(expected), indented.String(), "%s changed; if on purpose, run go test ./test -run TestResponseShapes -update and review the diff", path)
+}
+
+func TestResponseShapes(t *testing.T) {
+	svc := service.NewServiceService(repository.NewServiceRepository(testsupport.NewDB(t)))
+	standard := handler.SetupRouter(handler.NewServiceHandler(svc))
+	fast := handler.SetupRouter(handler.NewServiceHandler(svc, handler.WithFastJSON()))
+
+	// Service 1 carries every optional field, so that none goes missing
+	// unnoticed
+	later := time.Now().AddDate(1, 0, 0)
+	for _, write := range []struct {
+		path string
+		body interface{}
+	}{
+		{"/api/v1/services/1/translations/de", domain.TranslationInput{Description: "Filialen finden"}},
+		{"/api/v1/services/1/slo", domain.ServiceSLO{AvailabilityTarget: 99.9, LatencyTargetMS: 300}},
+		{"/api/v1/services/1/sunset", domain.SunsetInput{DeprecateAt: &later, ArchiveAt: later.AddDate(0, 1, 0)}},
+		{"/api/v1/services/1/versions/1/sunset", domain.SunsetInput{ArchiveAt: later}},
+		{"/api/v1/services/1/health-check", domain.HealthCheckInput{URL: "https://locate.example.com/healthz"}},
+		{"/api/v1/services/1/repository", domain.SourceRepositoryInput{URL: "https://github.com/kong/locate-us"}},
+		{"/api/v1/services/1/environments/production", domain.DeploymentInput{Version: "1.1.0"}},
+	} {
+		response := doRequest(t, standard, "PUT", write.path, "admin-token", write.body)
+		require.Equal(t, http.StatusOK, response.Code, "%s: %s", write.path, response.Body.String())
+	}
+	require.Equal(t, http.StatusOK, uploadSpec(t, standard, "/api/v1/services/1/versions/3", locateSpec).Code)
+
+	tests := []struct {
+		name   string
+		path   string
+		token  string
+		status int
+	}{
+		{"list", "/api/v1/services?page_size=2&sort_by=name", "viewer-token", http.StatusOK},
+		{"list_empty", "/api/v1/services?search=nothing-matches", "viewer-token", http.StatusOK},
+		{"detail", "/api/v1/services/1", "viewer-token", http.StatusOK},
+		{"versions", "/api/v1/services/1/versions?sort_by=semver", "viewer-token", http.StatusOK},
+		{"search", "/api/v1/search?q=contact", "viewer-token", http.StatusOK},
+		{"stats", "/api/v1/stats?recent=2", "viewer-token", http.StatusOK},
+		{"error_not_found", "/api/v1/services/999", "viewer-token", http.StatusNotFound},
+		{"error_invalid_params", "/api/v1/services?page_size=1000&sort_by=colour", "viewer-token", http.StatusBadRequest},
+		{"error_unauthorized", "/api/v1/services", "", http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response := doRequest(t, standard, "GET", tt.path, tt.token, nil)
+			require.Equal(t, tt.status, response.Code, response.Body.String())
+			golden(t, tt.name, response.Body.Bytes())
+
+			// The fast encoder answers the same, byte for byte
+			fastResponse := doRequest(t, fast, "GET", tt.path, tt.token, nil)
+			assert.Equal(t, goldenRequestIDs.ReplaceAllString(response.Body.String(), ""), goldenRequestIDs.ReplaceAllString(fastResponse.Body.String(), ""))
+		})
+	}
+}
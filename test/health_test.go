@@ -0,0 +1,117 @@
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"com.kong.connect/domain"
+	"com.kong.connect/events"
+	"com.kong.connect/handler"
+	"com.kong.connect/probe"
+	"com.kong.connect/repository"
+	"com.kong.connect/service"
+	"com.kong.connect/testsupport"
+)
+
+func TestHealthChecksAreProbedAndRecorded(t *testing.T) {
+	var healthy atomic.Bool
+	healthy.Store(true)
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !healthy.Load() {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+		}
+	}))
+	defer target.Close()
+
+	bus := events.NewBus()
+	var changes []domain.ChangeEvent
+	bus.Subscribe(func(event domain.ChangeEvent) {
+		if event.Type == domain.EventServiceHealthChanged {
+			changes = append(changes, event)
+		}
+	})
+	svc := service.NewServiceService(repository.NewServiceRepository(testsupport.NewDB(t)), service.WithPublisher(bus))
+	router := handler.SetupRouter(handler.NewServiceHandler(svc))
+	prober := probe.NewProber(probe.Config{Interval: time.Minute, Timeout: time.Second}, svc, nil, nil)
+
+	// Services without a health check have no health
+	response := doRequest(t, router, "GET", "/api/v1/services/1", "viewer-token", nil)
+	require.Equal(t, http.StatusOK, response.Code)
+	assert.NotContains(t, response.Body.String(), `"health"`)
+
+	response = doRequest(t, router, "PUT", "/api/v1/services/1/health-check", "viewer-token", domain.HealthCheckInput{URL: target.URL})
+	assert.Equal(t, http.StatusForbidden, response.Code)
+	response = doRequest(t, router, "PUT", "/api/v1/services/1/health-check", "admin-token", domain.HealthCheckInput{URL: "ftp://example.com/"})
+	assert.Equal(t, http.StatusBadRequest, response.Code)
+	response = doRequest(t, router, "PUT", "/api/v1/services/999/health-check", "admin-token", domain.HealthCheckInput{URL: target.URL})
+	assert.Equal(t, http.StatusNotFound, response.Code)
+
+	response = doRequest(t, router, "PUT", "/api/v1/services/1/health-check", "admin-token", domain.HealthCheckInput{URL: target.URL + "/healthz"})
+	require.Equal(t, http.StatusOK, response.Code, response.Body.String())
+	var health domain.ServiceHealth
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &health))
+	assert.Equal(t, domain.ServiceHealth{URL: target.URL + "/healthz", Status: domain.HealthUnknown}, health)
+
+	require.NoError(t, prober.Probe(context.Background()))
+	healthy.Store(false)
+	require.NoError(t, prober.Probe(context.Background()))
+	require.NoError(t, prober.Probe(context.Background()))
+
+	// The detail and the listing show the last probe
+	response = doRequest(t, router, "GET", "/api/v1/services/1", "viewer-token", nil)
+	require.Equal(t, http.StatusOK, response.Code)
+	var locate domain.ServiceWithVersions
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &locate))
+	require.NotNil(t, locate.Health)
+	assert.Equal(t, domain.HealthDown, locate.Health.Status)
+	assert.Equal(t, "unexpected status 503", locate.Health.Error)
+	assert.NotNil(t, locate.Health.CheckedAt)
+
+	response = doRequest(t, router, "GET", "/api/v1/services?search=Locate", "viewer-token", nil)
+	require.Equal(t, http.StatusOK, response.Code)
+	var listing domain.ServiceListResponse
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &listing))
+	require.Len(t, listing.Services, 1)
+	require.NotNil(t, listing.Services[0].Health)
+	assert.Equal(t, domain.HealthDown, listing.Services[0].Health.Status)
+
+	// Every probe is kept, newest first; status changes are published
+	response = doRequest(t, router, "GET", "/api/v1/services/1/health?page_size=2", "viewer-token", nil)
+	require.Equal(t, http.StatusOK, response.Code)
+	var history domain.HealthHistoryResponse
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &history))
+	assert.Equal(t, 3, history.Total)
+	assert.Equal(t, 2, history.TotalPages)
+	require.Len(t, history.Results, 2)
+	assert.Equal(t, domain.HealthDown, history.Results[0].Status)
+	assert.Equal(t, domain.HealthDown, history.Health.Status)
+	response = doRequest(t, router, "GET", "/api/v1/services/1/health?page=2&page_size=2", "viewer-token", nil)
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &history))
+	require.Len(t, history.Results, 1)
+	assert.Equal(t, domain.HealthUp, history.Results[0].Status)
+
+	require.Len(t, changes, 2)
+	assert.Equal(t, domain.HealthUp, changes[0].Service.Health.Status)
+	assert.Equal(t, domain.HealthDown, changes[1].Service.Health.Status)
+
+	// Removing the health check removes its history
+	response = doRequest(t, router, "DELETE", "/api/v1/services/1/health-check", "admin-token", nil)
+	assert.Equal(t, http.StatusNoContent, response.Code)
+	response = doRequest(t, router, "DELETE", "/api/v1/services/1/health-check", "admin-token", nil)
+	assert.Equal(t, http.StatusNotFound, response.Code)
+	response = doRequest(t, router, "GET", "/api/v1/services/1/health", "viewer-token", nil)
+	require.Equal(t, http.StatusOK, response.Code)
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &history))
+	assert.Nil(t, history.Health)
+	assert.Empty(t, history.Results)
+	response = doRequest(t, router, "GET", "/api/v1/services/999/health", "viewer-token", nil)
+	assert.Equal(t, http.StatusNotFound, response.Code)
+}
@@ -0,0 +1,58 @@
+package integration
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"com.kong.connect/handler"
+	"com.kong.connect/middleware"
+	"com.kong.connect/repository"
+	"com.kong.connect/service"
+	"com.kong.connect/testsupport"
+)
+
+func TestCatalogReadsCarryCacheHeaders(t *testing.T) {
+	svc := service.NewServiceService(repository.NewServiceRepository(testsupport.NewDB(t)))
+	policy := middleware.HTTPCache{MaxAge: time.Minute, StaleWhileRevalidate: 5 * time.Minute}
+	router := handler.SetupRouter(handler.NewServiceHandler(svc), handler.WithHTTPCache(policy))
+
+	response := doRequest(t, router, "GET", "/api/v1/services", "viewer-token", nil)
+	require.Equal(t, http.StatusOK, response.Code)
+	assert.Equal(t, "public, max-age=60, stale-while-revalidate=300", response.Header().Get("Cache-Control"))
+	assert.Subset(t, response.Header().Values("Vary"), []string{"Accept-Language", "Authorization"})
+	assert.Equal(t, "services", response.Header().Get("Surrogate-Key"))
+
+	response = doRequest(t, router, "GET", "/api/v1/services/1", "viewer-token", nil)
+	require.Equal(t, http.StatusOK, response.Code)
+	assert.Equal(t, "service-1", response.Header().Get("Surrogate-Key"))
+
+	// Misses and admin-only reads are never cached
+	response = doRequest(t, router, "GET", "/api/v1/services/999", "viewer-token", nil)
+	assert.Equal(t, http.StatusNotFound, response.Code)
+	assert.Empty(t, response.Header().Get("Cache-Control"))
+
+	response = doRequest(t, router, "GET", "/api/v1/audit-logs", "admin-token", nil)
+	require.Equal(t, http.StatusOK, response.Code)
+	assert.Empty(t, response.Header().Get("Cache-Control"))
+}
+
+func TestReadsChangedWithoutCatalogWritesAreNotCached(t *testing.T) {
+	svc := service.NewServiceService(repository.NewServiceRepository(testsupport.NewDB(t)))
+	router := handler.SetupRouter(handler.NewServiceHandler(svc), handler.WithHTTPCache(middleware.HTTPCache{MaxAge: time.Minute}))
+
+	for _, path := range []string{
+		"/api/v1/me/subscriptions",
+		"/api/v1/me/notification-preferences",
+		"/api/v1/services/1/comments",
+		"/api/v1/services/1/ownership-transfers",
+	} {
+		response := doRequest(t, router, "GET", path, "viewer-token", nil)
+		require.Equal(t, http.StatusOK, response.Code, path)
+		assert.Equal(t, "no-store", response.Header().Get("Cache-Control"), path)
+		assert.Empty(t, response.Header().Get("Surrogate-Key"), path)
+	}
+}
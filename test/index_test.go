@@ -0,0 +1,52 @@
+package integration
+
+import (
+	"database/sql"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"com.kong.connect/testsupport"
+)
+
+// queryPlan returns the EXPLAIN QUERY PLAN details of query, one per line
+func queryPlan(t *testing.T, db *sql.DB, query string, args ...interface{}) string {
+	t.Helper()
+	rows, err := db.Query("EXPLAIN QUERY PLAN "+query, args...)
+	require.NoError(t, err)
+	defer rows.Close()
+
+	var details []string
+	for rows.Next() {
+		var id, parent, unused int
+		var detail string
+		require.NoError(t, rows.Scan(&id, &parent, &unused, &detail))
+		details = append(details, detail)
+	}
+	require.NoError(t, rows.Err())
+	return strings.Join(details, "\n")
+}
+
+func TestListingQueriesUseIndexes(t *testing.T) {
+	db := testsupport.NewDB(t)
+
+	plans := map[string]string{
+		"SELECT id FROM services s WHERE s.org_id = 1 ORDER BY s.name ASC, s.id ASC LIMIT 12":                        "sqlite_autoindex_services_1",
+		"SELECT id FROM services s WHERE s.org_id = 1 ORDER BY s.created_at DESC, s.id DESC LIMIT 12":                "idx_services_org_created_at",
+		"SELECT id FROM services s WHERE s.org_id = 1 ORDER BY s.updated_at ASC, s.id ASC LIMIT 12":                  "idx_services_org_updated_at",
+		"SELECT status, COUNT(*) FROM services WHERE org_id = 1 GROUP BY status":                                     "idx_services_org_status",
+		"SELECT id FROM service_versions WHERE service_id = 1 ORDER BY created_at DESC, id ASC":                      "idx_service_versions_service_id_created_at",
+		"SELECT id FROM service_versions WHERE service_id = 1 ORDER BY created_at ASC, id ASC LIMIT 5":               "idx_service_versions_service_id_created_at",
+		"SELECT id FROM service_versions WHERE service_id IN (1, 2, 3) ORDER BY service_id, created_at DESC, id ASC": "idx_service_versions_service_id_created_at",
+		"SELECT COUNT(*) FROM service_versions WHERE service_id = 1":                                                 "service_id=?",
+		"SELECT tag FROM service_tags WHERE service_id = 1 ORDER BY tag":                                             "service_id=?",
+		"SELECT id FROM audit_logs WHERE created_at < '2024-01-01'":                                                  "idx_audit_logs_created_at",
+	}
+	for query, index := range plans {
+		plan := queryPlan(t, db, query)
+		assert.Contains(t, plan, index, query)
+		assert.NotContains(t, plan, "USE TEMP B-TREE FOR ORDER BY", query)
+	}
+}
@@ -1,38 +1,43 @@
 package integration
 
 import (
+	"bytes"
 	"encoding/json"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"strconv"
+	"strings"
 	"testing"
+	"testing/fstest"
 
+	"github.com/gorilla/mux"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
-	"com.kong.connect/database"
 	"com.kong.connect/domain"
+	"com.kong.connect/features"
 	"com.kong.connect/handler"
+	"com.kong.connect/metrics"
+	"com.kong.connect/middleware"
+	"com.kong.connect/problem"
+	"com.kong.connect/ratelimit"
 	"com.kong.connect/repository"
 	"com.kong.connect/service"
+	"com.kong.connect/testsupport"
+	"com.kong.connect/web"
 )
 
 func TestGetServicesWithSimpleAuth(t *testing.T) {
-	// Setup environment variables for DB and token
-	testDBPath := "./test_services.db"
-	os.Setenv("DB_PATH", testDBPath)
+	// Setup environment variables for the token
 	os.Setenv("ADMIN_TOKEN", "admin-token")
 
-	// Cleanup old test DB file if any
-	_ = os.Remove(testDBPath)
-
 	// Initialize DB
-	err := database.InitDB(testDBPath)
-	assert.NoError(t, err)
-	defer os.Remove(testDBPath)
+	db := testsupport.NewDB(t)
 
 	// Setup router and handler
-	repo := repository.NewServiceRepository(database.DB)
+	repo := repository.NewServiceRepository(db)
 	serviceSvc := service.NewServiceService(repo)
 	serviceHandler := handler.NewServiceHandler(serviceSvc)
 
@@ -93,21 +98,14 @@ func TestGetServicesWithSimpleAuth(t *testing.T) {
 }
 
 func TestGetServicesWithIdSimpleAuth(t *testing.T) {
-	// Setup environment variables for DB and token
-	testDBPath := "./test_services_empty.db"
-	os.Setenv("DB_PATH", testDBPath)
+	// Setup environment variables for the token
 	os.Setenv("ADMIN_TOKEN", "admin-token")
 
-	// Cleanup old test DB file if any
-	_ = os.Remove(testDBPath)
-
 	// Initialize DB (without inserting test data)
-	err := database.InitDB(testDBPath)
-	assert.NoError(t, err)
-	defer os.Remove(testDBPath)
+	db := testsupport.NewDB(t)
 
 	// Setup router and handler
-	repo := repository.NewServiceRepository(database.DB)
+	repo := repository.NewServiceRepository(db)
 	serviceSvc := service.NewServiceService(repo)
 	serviceHandler := handler.NewServiceHandler(serviceSvc)
 
@@ -144,21 +142,14 @@ func TestGetServicesWithIdSimpleAuth(t *testing.T) {
 }
 
 func TestGetServicesUnauthorized(t *testing.T) {
-	// Setup environment variables for DB and token
-	testDBPath := "./test_services_unauth.db"
-	os.Setenv("DB_PATH", testDBPath)
+	// Setup environment variables for the token
 	os.Setenv("ADMIN_TOKEN", "admin-token")
 
-	// Cleanup old test DB file if any
-	_ = os.Remove(testDBPath)
-
 	// Initialize DB
-	err := database.InitDB(testDBPath)
-	assert.NoError(t, err)
-	defer os.Remove(testDBPath)
+	db := testsupport.NewDB(t)
 
 	// Setup router and handler
-	repo := repository.NewServiceRepository(database.DB)
+	repo := repository.NewServiceRepository(db)
 	serviceSvc := service.NewServiceService(repo)
 	serviceHandler := handler.NewServiceHandler(serviceSvc)
 
@@ -176,3 +167,531 @@ func TestGetServicesUnauthorized(t *testing.T) {
 	// Should return unauthorized
 	assert.Equal(t, http.StatusUnauthorized, response.Code)
 }
+
+// newTestRouter initializes a seeded database and returns the full router
+func newTestRouter(t *testing.T) http.Handler {
+	t.Helper()
+
+	repo := testsupport.NewRepository(t)
+	serviceSvc := service.NewServiceService(repo, service.WithStaticUsers(middleware.IsStaticUser))
+	serviceHandler := handler.NewServiceHandler(serviceSvc)
+
+	return handler.SetupRouter(serviceHandler)
+}
+
+// doRequest performs a request against the router with the given bearer token and JSON body
+func doRequest(t *testing.T, router http.Handler, method, path, token string, body interface{}) *httptest.ResponseRecorder {
+	t.Helper()
+
+	var reader io.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		require.NoError(t, err)
+		reader = bytes.NewReader(payload)
+	}
+
+	req, err := http.NewRequest(method, path, reader)
+	require.NoError(t, err)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	response := httptest.NewRecorder()
+	router.ServeHTTP(response, req)
+	return response
+}
+
+func itoa(i int) string {
+	return strconv.Itoa(i)
+}
+
+func TestSearchServicesRanksNameMatchesFirst(t *testing.T) {
+	router := newTestRouter(t)
+
+	req, err := http.NewRequest("GET", "/api/v1/search?q=us+ipsum", nil)
+	require.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer viewer-token")
+
+	response := httptest.NewRecorder()
+	router.ServeHTTP(response, req)
+	assert.Equal(t, http.StatusOK, response.Code)
+
+	var searchResponse domain.SearchResponse
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &searchResponse))
+
+	// "us" matches the names "Locate Us" and "Contact Us", "ipsum" matches every description
+	require.Equal(t, 8, searchResponse.Total)
+	top := []string{searchResponse.Results[0].Service.Name, searchResponse.Results[1].Service.Name}
+	assert.ElementsMatch(t, []string{"Contact Us", "Locate Us"}, top)
+	assert.Greater(t, searchResponse.Results[0].Score, searchResponse.Results[2].Score)
+	assert.Contains(t, searchResponse.Results[0].Highlight.Name, "<mark>Us</mark>")
+}
+
+func TestSearchServicesRequiresQuery(t *testing.T) {
+	router := newTestRouter(t)
+
+	req, err := http.NewRequest("GET", "/api/v1/search", nil)
+	require.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer viewer-token")
+
+	response := httptest.NewRecorder()
+	router.ServeHTTP(response, req)
+	assert.Equal(t, http.StatusBadRequest, response.Code)
+}
+
+func TestGetStats(t *testing.T) {
+	router := newTestRouter(t)
+
+	req, err := http.NewRequest("GET", "/api/v1/stats?recent=3", nil)
+	require.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer viewer-token")
+
+	response := httptest.NewRecorder()
+	router.ServeHTTP(response, req)
+	assert.Equal(t, http.StatusOK, response.Code)
+
+	var stats domain.CatalogStats
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &stats))
+
+	assert.Equal(t, 8, stats.TotalServices)
+	assert.Equal(t, 24, stats.TotalVersions)
+	assert.Equal(t, []domain.StatBucket{{Value: domain.StatusActive, Count: 8}}, stats.ByStatus)
+	assert.Equal(t, domain.StatBucket{Value: "platform-team", Count: 3}, stats.ByOwner[0])
+	assert.Equal(t, domain.StatBucket{Value: "public", Count: 3}, stats.ByTag[0])
+	assert.Len(t, stats.RecentlyUpdated, 3)
+}
+
+func TestGetServiceVersionsPaginatedBySemver(t *testing.T) {
+	router := newTestRouter(t)
+
+	req, err := http.NewRequest("GET", "/api/v1/services/4/versions?sort_by=semver&sort_dir=desc&page=1&page_size=2", nil)
+	require.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer viewer-token")
+
+	response := httptest.NewRecorder()
+	router.ServeHTTP(response, req)
+	assert.Equal(t, http.StatusOK, response.Code)
+
+	var versionList domain.VersionListResponse
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &versionList))
+
+	assert.Equal(t, 3, versionList.Total)
+	assert.Equal(t, 2, versionList.TotalPages)
+	require.Len(t, versionList.Versions, 2)
+	assert.Equal(t, "3.0.0", versionList.Versions[0].Version)
+	assert.Equal(t, "2.0.0", versionList.Versions[1].Version)
+}
+
+func TestGetServiceVersionsNotFound(t *testing.T) {
+	router := newTestRouter(t)
+
+	req, err := http.NewRequest("GET", "/api/v1/services/999/versions", nil)
+	require.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer viewer-token")
+
+	response := httptest.NewRecorder()
+	router.ServeHTTP(response, req)
+	assert.Equal(t, http.StatusNotFound, response.Code)
+}
+
+func TestListServicesPaginationHeaders(t *testing.T) {
+	router := newTestRouter(t)
+
+	response := doRequest(t, router, "GET", "/api/v1/services?search=e&page=2&page_size=3", "viewer-token", nil)
+	require.Equal(t, http.StatusOK, response.Code)
+
+	var serviceListResponse domain.ServiceListResponse
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &serviceListResponse))
+
+	assert.Equal(t, itoa(serviceListResponse.Total), response.Header().Get("X-Total-Count"))
+	link := response.Header().Get("Link")
+	assert.Contains(t, link, `</api/v1/services?page=1&page_size=3&search=e>; rel="first"`)
+	assert.Contains(t, link, `</api/v1/services?page=1&page_size=3&search=e>; rel="prev"`)
+	assert.Contains(t, link, `</api/v1/services?page=3&page_size=3&search=e>; rel="next"`)
+	assert.Contains(t, link, `</api/v1/services?page=3&page_size=3&search=e>; rel="last"`)
+}
+
+func TestListServicesPagesBreakTiesByID(t *testing.T) {
+	router := newTestRouter(t)
+
+	// The seeded services are created in the same second
+	pages := func(query string) []int {
+		var ids []int
+		for page := 1; page <= 3; page++ {
+			response := doRequest(t, router, "GET", "/api/v1/services?"+query+"&page_size=3&page="+itoa(page), "viewer-token", nil)
+			require.Equal(t, http.StatusOK, response.Code, response.Body.String())
+			var listing domain.ServiceListResponse
+			require.NoError(t, json.Unmarshal(response.Body.Bytes(), &listing))
+			for _, service := range listing.Services {
+				ids = append(ids, service.ID)
+			}
+		}
+		return ids
+	}
+	assert.Equal(t, []int{1, 2, 3, 4, 5, 6, 7, 8}, pages("sort_by=created_at&sort_dir=asc"))
+	assert.Equal(t, []int{8, 7, 6, 5, 4, 3, 2, 1}, pages("sort_by=updated_at&sort_dir=desc"))
+}
+
+func TestListServicesByVersionCount(t *testing.T) {
+	router := newTestRouter(t)
+
+	// Every seeded service has 3 versions
+	response := doRequest(t, router, "POST", "/api/v1/services", "admin-token", domain.ServiceInput{Name: "Unversioned", Description: "Registered without versions"})
+	require.Equal(t, http.StatusCreated, response.Code, response.Body.String())
+	response = doRequest(t, router, "POST", "/api/v1/services/1/versions", "admin-token", domain.VersionInput{Version: "3.0.0"})
+	require.Equal(t, http.StatusCreated, response.Code, response.Body.String())
+
+	list := func(query string) (int, []string) {
+		response := doRequest(t, router, "GET", "/api/v1/services?"+query, "viewer-token", nil)
+		require.Equal(t, http.StatusOK, response.Code, response.Body.String())
+		var listing domain.ServiceListResponse
+		require.NoError(t, json.Unmarshal(response.Body.Bytes(), &listing))
+		names := []string{}
+		for _, service := range listing.Services {
+			names = append(names, service.Name)
+		}
+		return listing.Total, names
+	}
+	total, names := list("has_versions=false")
+	assert.Equal(t, 1, total)
+	assert.Equal(t, []string{"Unversioned"}, names)
+	total, _ = list("has_versions=true")
+	assert.Equal(t, 8, total)
+	total, _ = list("min_versions=3")
+	assert.Equal(t, 8, total)
+	total, names = list("min_versions=4")
+	assert.Equal(t, 1, total)
+	assert.Equal(t, []string{"Locate Us"}, names)
+	total, _ = list("has_versions=false&min_versions=1")
+	assert.Equal(t, 0, total)
+
+	for _, query := range []string{"has_versions=maybe", "min_versions=0", "min_versions=many"} {
+		response := doRequest(t, router, "GET", "/api/v1/services?"+query, "viewer-token", nil)
+		assert.Equal(t, http.StatusBadRequest, response.Code, query)
+	}
+}
+
+func TestListServicesRejectsInvalidQueryParameters(t *testing.T) {
+	router := newTestRouter(t)
+
+	response := doRequest(t, router, "GET", "/api/v1/services?sort_by=bogus&page_size=0&colour=red", "viewer-token", nil)
+	require.Equal(t, http.StatusBadRequest, response.Code)
+	assert.Equal(t, "application/problem+json", response.Header().Get("Content-Type"))
+
+	var details problem.Details
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &details))
+	assert.Equal(t, http.StatusBadRequest, details.Status)
+	assert.Equal(t, []problem.InvalidParam{
+		{Name: "colour", Reason: "unknown parameter"},
+		{Name: "page_size", Reason: "must be a positive integer"},
+		{Name: "sort_by", Reason: "must be one of: name, created_at, updated_at"},
+	}, details.InvalidParams)
+}
+
+func TestPaginationRejectsOutOfRangePages(t *testing.T) {
+	router := newTestRouter(t)
+
+	for path, invalid := range map[string]problem.InvalidParam{
+		"/api/v1/services?page_size=101":                 {Name: "page_size", Reason: "must be at most 100"},
+		"/api/v1/services?page=3&page_size=5":            {Name: "page", Reason: "must be at most 2, the last page"},
+		"/api/v1/services?search=nothing&page=2":         {Name: "page", Reason: "must be at most 1, the last page"},
+		"/api/v1/services/1/versions?page=2":             {Name: "page", Reason: "must be at most 1, the last page"},
+		"/api/v1/search?q=us&page_size=1000":             {Name: "page_size", Reason: "must be at most 100"},
+		"/api/v1/services/1/health?page_size=501":        {Name: "page_size", Reason: "must be at most 500"},
+		"/api/v1/services/1/comments?page=2&page_size=1": {Name: "page", Reason: "must be at most 1, the last page"},
+	} {
+		response := doRequest(t, router, "GET", path, "admin-token", nil)
+		require.Equal(t, http.StatusBadRequest, response.Code, path)
+		var details problem.Details
+		require.NoError(t, json.Unmarshal(response.Body.Bytes(), &details))
+		assert.Equal(t, []problem.InvalidParam{invalid}, details.InvalidParams, path)
+	}
+
+	// The last page, and the first one of no results, are in range
+	for _, path := range []string{"/api/v1/services?page=2&page_size=5", "/api/v1/services?search=nothing", "/api/v1/services?page_size=100"} {
+		response := doRequest(t, router, "GET", path, "viewer-token", nil)
+		assert.Equal(t, http.StatusOK, response.Code, path)
+	}
+}
+
+func TestListServicesLenientQueryParameters(t *testing.T) {
+	db := testsupport.NewDB(t)
+
+	repo := repository.NewServiceRepository(db)
+	serviceHandler := handler.NewServiceHandler(service.NewServiceService(repo), handler.WithLenientQueryParams())
+	router := handler.SetupRouter(serviceHandler)
+
+	response := doRequest(t, router, "GET", "/api/v1/services?sort_by=bogus&page_size=0&colour=red", "viewer-token", nil)
+	require.Equal(t, http.StatusOK, response.Code)
+
+	var serviceListResponse domain.ServiceListResponse
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &serviceListResponse))
+	assert.Equal(t, 12, serviceListResponse.PageSize)
+
+	// Page sizes are clamped, and pages past the end are empty
+	response = doRequest(t, router, "GET", "/api/v1/services?page_size=1000&page=5", "viewer-token", nil)
+	require.Equal(t, http.StatusOK, response.Code)
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &serviceListResponse))
+	assert.Equal(t, 100, serviceListResponse.PageSize)
+	assert.Empty(t, serviceListResponse.Services)
+}
+
+func TestLenientQueryParametersFeatureFlag(t *testing.T) {
+	db := testsupport.NewDB(t)
+
+	// Half of the users get lenient parsing; buckets are stable per user
+	store := features.NewStore(features.Rollouts{features.LenientQueryParams: 50})
+	repo := repository.NewServiceRepository(db)
+	router := handler.SetupRouter(handler.NewServiceHandler(service.NewServiceService(repo)), handler.WithFeatures(store))
+
+	// Every request authenticates as a different user
+	defer middleware.SetTokens(map[string]middleware.UserClaims{
+		"admin-token":  {Username: "admin", Roles: []string{"admin"}},
+		"viewer-token": {Username: "viewer", Roles: []string{"viewer"}},
+	})
+	codes := map[int]int{}
+	for i := 0; i < 20; i++ {
+		user := "user-" + strconv.Itoa(i)
+		tokens, err := middleware.ParseTokens(user + "=" + user + ":viewer")
+		require.NoError(t, err)
+		middleware.SetTokens(tokens)
+
+		response := doRequest(t, router, "GET", "/api/v1/services?colour=red", user, nil)
+		codes[response.Code]++
+		assert.Equal(t, features.Rollouts{features.LenientQueryParams: 50}.Enabled(features.LenientQueryParams, user), response.Code == http.StatusOK, user)
+	}
+	assert.NotZero(t, codes[http.StatusOK])
+	assert.NotZero(t, codes[http.StatusBadRequest])
+
+	// Reloading the flags applies to the next request
+	store.Set(features.Rollouts{})
+	tokens, err := middleware.ParseTokens("t=user-0:viewer")
+	require.NoError(t, err)
+	middleware.SetTokens(tokens)
+	response := doRequest(t, router, "GET", "/api/v1/services?colour=red", "t", nil)
+	assert.Equal(t, http.StatusBadRequest, response.Code)
+}
+
+func TestRequestIDPropagation(t *testing.T) {
+	router := newTestRouter(t)
+
+	// A well-formed client ID is echoed back and included in error responses
+	req, err := http.NewRequest("GET", "/api/v1/services/999", nil)
+	require.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer viewer-token")
+	req.Header.Set("X-Request-ID", "support-ticket-42")
+
+	response := httptest.NewRecorder()
+	router.ServeHTTP(response, req)
+	require.Equal(t, http.StatusNotFound, response.Code)
+	assert.Equal(t, "support-ticket-42", response.Header().Get("X-Request-ID"))
+	assert.Equal(t, "v1", response.Header().Get("API-Version"))
+	assert.Regexp(t, `^app;dur=\d+\.\d{3}$`, response.Header().Get("Server-Timing"))
+
+	var details problem.Details
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &details))
+	assert.Equal(t, "support-ticket-42", details.RequestID)
+
+	// Malformed IDs are replaced with a generated one
+	req.Header.Set("X-Request-ID", "bad id\r\n")
+	response = httptest.NewRecorder()
+	router.ServeHTTP(response, req)
+	generated := response.Header().Get("X-Request-ID")
+	assert.Len(t, generated, 32)
+	assert.NotEqual(t, "bad id\r\n", generated)
+}
+
+func TestDebugEndpointsRequireFlagAndAdmin(t *testing.T) {
+	router := newTestRouter(t)
+	response := doRequest(t, router, "GET", "/debug/vars", "admin-token", nil)
+	assert.Equal(t, http.StatusNotFound, response.Code)
+
+	db := testsupport.NewDB(t)
+
+	repo := repository.NewServiceRepository(db)
+	router = handler.SetupRouter(handler.NewServiceHandler(service.NewServiceService(repo)), handler.WithDebugEndpoints())
+
+	response = doRequest(t, router, "GET", "/debug/vars", "viewer-token", nil)
+	assert.Equal(t, http.StatusForbidden, response.Code)
+
+	response = doRequest(t, router, "GET", "/debug/vars", "admin-token", nil)
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.Contains(t, response.Body.String(), "memstats")
+
+	response = doRequest(t, router, "GET", "/debug/pprof/heap?debug=1", "admin-token", nil)
+	assert.Equal(t, http.StatusOK, response.Code)
+}
+
+func TestWebUIDoesNotShadowAPIRoutes(t *testing.T) {
+	db := testsupport.NewDB(t)
+
+	ui, err := web.NewHandler(fstest.MapFS{"index.html": {Data: []byte("<div id=app></div>")}})
+	require.NoError(t, err)
+	repo := repository.NewServiceRepository(db)
+	router := handler.SetupRouter(handler.NewServiceHandler(service.NewServiceService(repo)), handler.WithUI(ui))
+
+	// Client side routes need no token
+	response := doRequest(t, router, "GET", "/services/1", "", nil)
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.Contains(t, response.Body.String(), "<div id=app>")
+
+	response = doRequest(t, router, "GET", "/api/v1/services/1", "viewer-token", nil)
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.Contains(t, response.Header().Get("Content-Type"), "application/json")
+
+	assert.Equal(t, http.StatusUnauthorized, doRequest(t, router, "GET", "/api/v1/services", "", nil).Code)
+	assert.Equal(t, http.StatusNotFound, doRequest(t, router, "GET", "/api/v1/unknown", "viewer-token", nil).Code)
+	assert.Equal(t, http.StatusOK, doRequest(t, router, "GET", "/health", "", nil).Code)
+	assert.Equal(t, http.StatusMethodNotAllowed, doRequest(t, router, "POST", "/services/1", "admin-token", nil).Code)
+}
+
+func TestNonCanonicalPathsAreRouted(t *testing.T) {
+	db := testsupport.NewDB(t)
+
+	ui, err := web.NewHandler(fstest.MapFS{"index.html": {Data: []byte("<div id=app></div>")}})
+	require.NoError(t, err)
+	repo := repository.NewServiceRepository(db)
+	router := handler.SetupRouter(handler.NewServiceHandler(service.NewServiceService(repo)), handler.WithUI(ui))
+
+	do := func(method, path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Authorization", "Bearer admin-token")
+		response := httptest.NewRecorder()
+		router.ServeHTTP(response, req)
+		return response
+	}
+
+	for _, path := range []string{"/api/v1/services/", "//api/v1/services", "/api/v1//services", "/api/v1/./services/"} {
+		response := do("GET", path, "")
+		assert.Equal(t, http.StatusOK, response.Code, path)
+		assert.Contains(t, response.Header().Get("Content-Type"), "application/json", path)
+		assert.NotEmpty(t, response.Header().Get("X-Request-ID"), "%s goes through the middleware", path)
+	}
+	assert.Equal(t, http.StatusOK, do("GET", "/api/v1/services/1/", "").Code)
+	assert.Equal(t, http.StatusOK, do("GET", "/api/v1/services/2/../1", "").Code)
+
+	// Writes keep their method and body rather than following a redirect
+	response := do("POST", "/api/v1/services/", `{"name": "Billing"}`)
+	assert.Equal(t, http.StatusCreated, response.Code, response.Body.String())
+
+	assert.Equal(t, http.StatusNotFound, do("GET", "/api/v1/unknown/", "").Code)
+	assert.Equal(t, http.StatusMethodNotAllowed, do("PATCH", "/api/v1/services/", "").Code)
+}
+
+func TestRateLimitPerRouteGroup(t *testing.T) {
+	db := testsupport.NewDB(t)
+
+	repo := repository.NewServiceRepository(db)
+	limits := map[string]ratelimit.Limit{"search": {Rate: 0.001, Burst: 1}, "read": {Rate: 100, Burst: 100}}
+	router := handler.SetupRouter(handler.NewServiceHandler(service.NewServiceService(repo)),
+		handler.WithRateLimit(ratelimit.NewMemoryStore(), ratelimit.NewPolicy(limits)))
+
+	response := doRequest(t, router, "GET", "/api/v1/search?q=us", "viewer-token", nil)
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.Equal(t, "1", response.Header().Get("X-RateLimit-Limit"))
+
+	response = doRequest(t, router, "GET", "/api/v1/search?q=us", "viewer-token", nil)
+	assert.Equal(t, http.StatusTooManyRequests, response.Code)
+	assert.NotEmpty(t, response.Header().Get("Retry-After"))
+
+	// Other groups keep their own budget, and groups without a limit are not limited
+	response = doRequest(t, router, "GET", "/api/v1/services", "viewer-token", nil)
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.Equal(t, "99", response.Header().Get("X-RateLimit-Remaining"))
+
+	response = doRequest(t, router, "POST", "/api/v1/services", "admin-token", map[string]string{"name": "Billing"})
+	assert.Equal(t, http.StatusCreated, response.Code)
+	assert.Empty(t, response.Header().Get("X-RateLimit-Limit"))
+}
+
+func TestMetricsRecordRouteLatencyAndSLOs(t *testing.T) {
+	db := testsupport.NewDB(t)
+
+	slos, err := metrics.ParseLatencySLOs("GET /api/v1/services/{id}=300ms@99")
+	require.NoError(t, err)
+	repo := repository.NewServiceRepository(db)
+	router := handler.SetupRouter(handler.NewServiceHandler(service.NewServiceService(repo)),
+		handler.WithMetrics(metrics.New(slos)))
+
+	assert.Equal(t, http.StatusOK, doRequest(t, router, "GET", "/api/v1/services/1", "viewer-token", nil).Code)
+	assert.Equal(t, http.StatusOK, doRequest(t, router, "GET", "/api/v1/services/2", "viewer-token", nil).Code)
+
+	// Scraping requires no token
+	response := doRequest(t, router, "GET", "/metrics", "", nil)
+	assert.Equal(t, http.StatusOK, response.Code)
+	body := response.Body.String()
+	assert.Contains(t, body, `http_request_duration_seconds_count{method="GET",route="/api/v1/services/{id}",status="200"} 2`)
+	assert.Contains(t, body, `http_slo_requests_total{method="GET",route="/api/v1/services/{id}"} 2`)
+	assert.Contains(t, body, `http_slo_objective_ratio{method="GET",route="/api/v1/services/{id}"} 0.99`)
+}
+
+func TestEveryAPIRouteRequiresAuthentication(t *testing.T) {
+	router := newTestRouter(t).(*mux.Router)
+
+	checked := 0
+	err := router.Walk(func(route *mux.Route, _ *mux.Router, _ []*mux.Route) error {
+		template, err := route.GetPathTemplate()
+		if err != nil || template == "/health" || template == "/api/v1/openapi.yaml" {
+			return nil // Public, see the route table
+		}
+		methods, err := route.GetMethods()
+		if err != nil {
+			return nil
+		}
+
+		path := strings.NewReplacer("{id}", "1", "{versionId}", "1").Replace(template)
+		for _, method := range methods {
+			response := doRequest(t, router, method, path, "", nil)
+			assert.Equal(t, http.StatusUnauthorized, response.Code, "%s %s", method, template)
+			checked++
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Greater(t, checked, 10)
+}
+
+func TestRoutesAdvertiseTheirMethods(t *testing.T) {
+	db := testsupport.NewDB(t)
+
+	ui, err := web.NewHandler(fstest.MapFS{"index.html": {Data: []byte("<div id=app></div>")}})
+	require.NoError(t, err)
+	repo := repository.NewServiceRepository(db)
+	router := handler.SetupRouter(handler.NewServiceHandler(service.NewServiceService(repo)), handler.WithUI(ui))
+
+	do := func(method, path string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, nil)
+		req.Header.Set("Origin", "https://portal.example.com")
+		req.Header.Set("Access-Control-Request-Method", "POST")
+		response := httptest.NewRecorder()
+		router.ServeHTTP(response, req)
+		return response
+	}
+
+	for path, allow := range map[string]string{
+		"/api/v1/services":              "GET, OPTIONS, POST",
+		"/api/v1/services/1":            "DELETE, GET, OPTIONS, PUT",
+		"/api/v1/services/1/versions/2": "DELETE, OPTIONS",
+		"/api/v1/services:import":       "OPTIONS, POST",
+		"/api/v1/services/":             "GET, OPTIONS, POST",
+		"/services/1":                   "GET, HEAD, OPTIONS",
+	} {
+		// Preflight requests carry no credentials
+		response := do("OPTIONS", path)
+		assert.Equal(t, http.StatusNoContent, response.Code, path)
+		assert.Equal(t, allow, response.Header().Get("Allow"), path)
+		assert.Equal(t, allow, response.Header().Get("Access-Control-Allow-Methods"), path)
+		assert.Equal(t, "*", response.Header().Get("Access-Control-Allow-Origin"), path)
+	}
+	assert.Equal(t, http.StatusNotFound, do("OPTIONS", "/api/v1/unknown").Code)
+
+	response := do("PATCH", "/api/v1/services/1")
+	assert.Equal(t, http.StatusMethodNotAllowed, response.Code)
+	assert.Equal(t, "DELETE, GET, OPTIONS, PUT", response.Header().Get("Allow"))
+	assert.Contains(t, response.Header().Get("Content-Type"), "application/problem+json")
+	assert.NotEmpty(t, response.Header().Get("X-Request-ID"))
+	assert.Equal(t, "GET, OPTIONS, POST", do("DELETE", "/api/v1/services").Header().Get("Allow"))
+}
@@ -0,0 +1,72 @@
+package integration
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"com.kong.connect/domain"
+	"com.kong.connect/handler"
+	"com.kong.connect/notify"
+	"com.kong.connect/repository"
+	"com.kong.connect/service"
+	"com.kong.connect/testsupport"
+)
+
+func TestChannelsAreNotifiedOfServiceLifecycle(t *testing.T) {
+	titles := make(chan string, 10)
+	slack := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var message struct {
+			Text string `json:"text"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&message))
+		titles <- message.Text
+	}))
+	defer slack.Close()
+
+	notifier := notify.NewNotifier([]notify.Channel{{Kind: notify.KindSlack, URL: slack.URL, Notifications: notify.Notifications}}, slack.Client(), nil)
+	repo := repository.NewServiceRepository(testsupport.NewDB(t))
+	router := handler.SetupRouter(handler.NewServiceHandler(service.NewServiceService(repo, service.WithPublisher(notifier))))
+
+	response := doRequest(t, router, "POST", "/api/v1/services", "admin-token", domain.ServiceInput{Name: "Ledger"})
+	require.Equal(t, http.StatusCreated, response.Code, response.Body.String())
+	var created domain.ServiceWithVersions
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &created))
+	path := "/api/v1/services/" + itoa(created.ID)
+
+	// Only the update deprecating the service notifies
+	for _, input := range []domain.ServiceInput{
+		{Name: "Ledger", Description: "Accounts"},
+		{Name: "Ledger", Status: domain.StatusDeprecated},
+		{Name: "Ledger", Status: domain.StatusDeprecated, Owner: "finance"},
+	} {
+		response = doRequest(t, router, "PUT", path, "admin-token", input)
+		require.Equal(t, http.StatusOK, response.Code, response.Body.String())
+	}
+	response = doRequest(t, router, "DELETE", path, "admin-token", nil)
+	require.Equal(t, http.StatusNoContent, response.Code)
+
+	// Posts are made in the background, so they may arrive in any order
+	var received []string
+	for range 3 {
+		select {
+		case title := <-titles:
+			received = append(received, title)
+		case <-time.After(5 * time.Second):
+			t.Fatalf("received %v", received)
+		}
+	}
+	assert.ElementsMatch(t, []string{
+		"Service Ledger was created", "Service Ledger was deprecated", "Service Ledger was deleted",
+	}, received)
+	select {
+	case title := <-titles:
+		t.Fatalf("unexpected notification %q", title)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
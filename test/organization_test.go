@@ -0,0 +1,166 @@
+package integration
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"com.kong.connect/cache"
+	"com.kong.connect/database"
+	"com.kong.connect/domain"
+	"com.kong.connect/handler"
+	"com.kong.connect/middleware"
+	"com.kong.connect/repository"
+	"com.kong.connect/service"
+	"com.kong.connect/testsupport"
+)
+
+// doOrgRequest is doRequest acting for the organization selected by org
+func doOrgRequest(t *testing.T, router http.Handler, method, path, token, org string, body interface{}) *httptest.ResponseRecorder {
+	t.Helper()
+
+	payload, err := json.Marshal(body)
+	require.NoError(t, err)
+	req, err := http.NewRequest(method, path, bytes.NewReader(payload))
+	require.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(middleware.OrgHeader, org)
+
+	response := httptest.NewRecorder()
+	router.ServeHTTP(response, req)
+	return response
+}
+
+func TestOrganizationsAreIsolated(t *testing.T) {
+	details := cache.NewLRU[service.DetailKey, *domain.ServiceWithVersions]("service", 10, time.Minute)
+	repo := repository.NewServiceRepository(testsupport.NewDB(t))
+	svc := service.NewServiceService(repo, service.WithDetailCache(details))
+	router := handler.SetupRouter(handler.NewServiceHandler(svc))
+	middleware.SetTokenLookup(handler.TokenLookup(svc))
+	defer middleware.SetTokenLookup(nil)
+	middleware.SetOrgLookup(handler.OrgLookup(svc))
+	defer middleware.SetOrgLookup(nil)
+
+	response := doRequest(t, router, "POST", "/api/v1/organizations", "admin-token", domain.OrganizationInput{Slug: "Payments", Name: "Payments team"})
+	require.Equal(t, http.StatusCreated, response.Code, response.Body.String())
+	var organization domain.Organization
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &organization))
+	assert.Equal(t, "payments", organization.Slug)
+	response = doRequest(t, router, "POST", "/api/v1/organizations", "admin-token", domain.OrganizationInput{Slug: "payments"})
+	assert.Equal(t, http.StatusConflict, response.Code)
+	response = doRequest(t, router, "POST", "/api/v1/organizations", "admin-token", domain.OrganizationInput{Slug: "payments team"})
+	assert.Equal(t, http.StatusBadRequest, response.Code)
+
+	total := func(token, org string) int {
+		response := doOrgRequest(t, router, "GET", "/api/v1/services", token, org, nil)
+		require.Equal(t, http.StatusOK, response.Code, response.Body.String())
+		var listing domain.ServiceListResponse
+		require.NoError(t, json.Unmarshal(response.Body.Bytes(), &listing))
+		return listing.Total
+	}
+	assert.Equal(t, 8, total("viewer-token", ""))
+	assert.Equal(t, 8, total("viewer-token", "default"))
+	assert.Equal(t, 0, total("viewer-token", "payments"))
+	response = doOrgRequest(t, router, "GET", "/api/v1/services", "viewer-token", "unknown", nil)
+	assert.Equal(t, http.StatusNotFound, response.Code)
+
+	// Names are unique within an organization only
+	response = doOrgRequest(t, router, "POST", "/api/v1/services", "admin-token", "payments", domain.ServiceInput{Name: "Locate Us", Description: "Branches"})
+	require.Equal(t, http.StatusCreated, response.Code, response.Body.String())
+	var created domain.ServiceWithVersions
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &created))
+	assert.Equal(t, 1, total("viewer-token", "payments"))
+	assert.Equal(t, 8, total("viewer-token", ""))
+
+	// Services of another organization are not found, cached or not
+	response = doRequest(t, router, "GET", "/api/v1/services/1", "viewer-token", nil)
+	require.Equal(t, http.StatusOK, response.Code)
+	for _, request := range []struct{ method, path, org string }{
+		{"GET", "/api/v1/services/1", "payments"},
+		{"DELETE", "/api/v1/services/1", "payments"},
+		{"POST", "/api/v1/services/1/versions", "payments"},
+		{"GET", "/api/v1/services/1/versions", "payments"},
+		{"GET", "/api/v1/services/" + strconv.Itoa(created.ID), "default"},
+	} {
+		response = doOrgRequest(t, router, request.method, request.path, "admin-token", request.org, domain.VersionInput{Version: "9.0.0"})
+		assert.Equal(t, http.StatusNotFound, response.Code, request)
+	}
+	assert.Equal(t, 8, total("viewer-token", ""))
+
+	// Tokens issued in an organization act for it alone
+	response = doRequest(t, router, "POST", "/api/v1/users", "admin-token", domain.UserInput{Username: "bob", Roles: []string{"admin"}})
+	require.Equal(t, http.StatusCreated, response.Code, response.Body.String())
+	response = doOrgRequest(t, router, "POST", "/api/v1/tokens", "admin-token", "payments", domain.TokenInput{Username: "bob"})
+	require.Equal(t, http.StatusCreated, response.Code, response.Body.String())
+	var issued domain.IssuedToken
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &issued))
+	assert.Equal(t, organization.ID, issued.OrgID)
+
+	assert.Equal(t, 1, total(issued.Token, ""))
+	assert.Equal(t, 1, total(issued.Token, "payments"))
+	response = doOrgRequest(t, router, "GET", "/api/v1/services", issued.Token, "default", nil)
+	assert.Equal(t, http.StatusForbidden, response.Code)
+	for _, path := range []string{"/api/v1/users", "/api/v1/organizations"} {
+		response = doRequest(t, router, "GET", path, issued.Token, nil)
+		assert.Equal(t, http.StatusForbidden, response.Code, path)
+	}
+
+	tokens := func(token, org string) []domain.APIToken {
+		response := doOrgRequest(t, router, "GET", "/api/v1/tokens", token, org, nil)
+		require.Equal(t, http.StatusOK, response.Code, response.Body.String())
+		var listing domain.TokenListResponse
+		require.NoError(t, json.Unmarshal(response.Body.Bytes(), &listing))
+		return listing.Tokens
+	}
+	assert.Len(t, tokens(issued.Token, ""), 1)
+	assert.Empty(t, tokens("admin-token", ""))
+	response = doRequest(t, router, "DELETE", "/api/v1/tokens/"+strconv.Itoa(issued.ID), "admin-token", nil)
+	assert.Equal(t, http.StatusNotFound, response.Code)
+}
+
+func TestMigrateMovesServicesToTheDefaultOrganization(t *testing.T) {
+	db, err := database.Open(filepath.Join(t.TempDir(), "organizations.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	// The services table of a release without organizations
+	_, err = db.Exec(`CREATE TABLE services (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		name TEXT NOT NULL UNIQUE,
+		description TEXT NOT NULL,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+	)`)
+	require.NoError(t, err)
+	_, err = db.Exec("INSERT INTO services (name, description) VALUES ('Billing', ''), ('Payments', ''), ('Removed', '')")
+	require.NoError(t, err)
+	_, err = db.Exec("DELETE FROM services WHERE name = 'Removed'")
+	require.NoError(t, err)
+
+	require.NoError(t, database.Migrate(db))
+	var scoped, count int
+	require.NoError(t, db.QueryRow("SELECT COUNT(*) FROM services WHERE org_id = 1").Scan(&scoped))
+	assert.Equal(t, 2, scoped)
+	require.NoError(t, db.QueryRow("SELECT count FROM row_counts WHERE name = 'services:1'").Scan(&count))
+	assert.Equal(t, 2, count)
+
+	// IDs of deleted services are not reused, and names are unique per organization
+	_, err = db.Exec("INSERT INTO organizations (slug, name) VALUES ('payments', 'Payments')")
+	require.NoError(t, err)
+	result, err := db.Exec("INSERT INTO services (org_id, name, description) VALUES (2, 'Billing', '')")
+	require.NoError(t, err)
+	id, err := result.LastInsertId()
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), id)
+	_, err = db.Exec("INSERT INTO services (org_id, name, description) VALUES (1, 'Billing', '')")
+	assert.Error(t, err)
+}
@@ -0,0 +1,101 @@
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"com.kong.connect/domain"
+	"com.kong.connect/handler"
+	"com.kong.connect/middleware"
+	"com.kong.connect/outbox"
+	"com.kong.connect/repository"
+	"com.kong.connect/service"
+	"com.kong.connect/testsupport"
+)
+
+// sinkFunc adapts a function to outbox.Sink
+type sinkFunc func(ctx context.Context, events []domain.OutboxEvent) error
+
+func (f sinkFunc) Send(ctx context.Context, events []domain.OutboxEvent) error {
+	return f(ctx, events)
+}
+
+func TestWritesRecordTheirEventsInTheOutbox(t *testing.T) {
+	repo := repository.NewServiceRepository(testsupport.NewDB(t), repository.WithOutbox())
+	svc := service.NewServiceService(repo)
+	router := handler.SetupRouter(handler.NewServiceHandler(svc))
+	middleware.SetOrgLookup(handler.OrgLookup(svc))
+	defer middleware.SetOrgLookup(nil)
+
+	// Service 1 is seeded with versions 1.0.0, 1.1.0 and 2.0.0, service 2 as "Collect Monday"
+	for _, request := range []struct {
+		method, path string
+		body         interface{}
+		status       int
+	}{
+		{"PUT", "/api/v1/services/1", domain.ServiceInput{Name: "Locate Us", Description: "Branches", Tags: []string{"maps", "branches"}}, http.StatusOK},
+		{"PUT", "/api/v1/services/1", domain.ServiceInput{Name: "Contact Us", Description: "Taken"}, http.StatusConflict},
+		{"POST", "/api/v1/services/1/versions", domain.VersionInput{Version: "3.0.0"}, http.StatusCreated},
+		{"POST", "/api/v1/services/1/versions", domain.VersionInput{Version: "3.0.0"}, http.StatusConflict},
+		{"PUT", "/api/v1/services/1/environments/prod", domain.DeploymentInput{Version: "3.0.0"}, http.StatusOK},
+		{"DELETE", "/api/v1/services/1/environments/prod", nil, http.StatusNoContent},
+		{"DELETE", "/api/v1/services/999", nil, http.StatusNotFound},
+		{"DELETE", "/api/v1/services/2", nil, http.StatusNoContent},
+	} {
+		response := doRequest(t, router, request.method, request.path, "admin-token", request.body)
+		require.Equal(t, request.status, response.Code, request.path+" "+response.Body.String())
+	}
+
+	// Catalog applies record each change, here in an organization of its own
+	response := doRequest(t, router, "POST", "/api/v1/organizations", "admin-token", domain.OrganizationInput{Slug: "payments"})
+	require.Equal(t, http.StatusCreated, response.Code, response.Body.String())
+	for _, versions := range [][]string{{"1.0.0"}, {"2.0.0"}} {
+		document := domain.CatalogDocument{Services: []domain.CatalogService{{Name: "Billing", Description: "Invoices", Versions: versions}}}
+		response = doOrgRequest(t, router, "POST", "/api/v1/catalog:apply", "admin-token", "payments", document)
+		require.Equal(t, http.StatusOK, response.Code, response.Body.String())
+	}
+
+	var shipped []domain.ChangeEvent
+	var keys []string
+	sink := sinkFunc(func(ctx context.Context, events []domain.OutboxEvent) error {
+		for _, recorded := range events {
+			var event domain.ChangeEvent
+			require.NoError(t, json.Unmarshal(recorded.Payload, &event))
+			assert.Equal(t, recorded.Type, event.Type)
+			shipped = append(shipped, event)
+			keys = append(keys, itoa(recorded.OrgID)+":"+itoa(recorded.ServiceID))
+		}
+		return nil
+	})
+	require.NoError(t, outbox.NewShipper(repo, sink, nil).Ship(context.Background()))
+
+	types := make([]string, len(shipped))
+	for i, event := range shipped {
+		types[i] = event.Type
+	}
+	assert.Equal(t, []string{
+		domain.EventServiceUpdated, domain.EventVersionCreated, domain.EventVersionDeployed, domain.EventVersionUndeployed, domain.EventServiceDeleted,
+		domain.EventServiceCreated, domain.EventVersionCreated, domain.EventVersionCreated, domain.EventVersionDeleted,
+	}, types)
+	assert.Equal(t, []string{"1:1", "1:1", "1:1", "1:1", "1:2", "2:9", "2:9", "2:9", "2:9"}, keys)
+
+	updated := shipped[0]
+	assert.Equal(t, []string{"branches", "maps"}, updated.Tags)
+	assert.Equal(t, "Branches", updated.Service.Description)
+	assert.Equal(t, "3.0.0", shipped[1].Version.Version)
+	assert.Equal(t, []string{"prod"}, shipped[2].Version.Environments)
+	assert.Empty(t, shipped[3].Version.Environments)
+	assert.Equal(t, "Collect Monday", shipped[4].Service.Name)
+	assert.Equal(t, 2, shipped[5].OrgID)
+	assert.Equal(t, "1.0.0", shipped[8].Version.Version)
+
+	// Shipped events leave the outbox
+	shipped = nil
+	require.NoError(t, outbox.NewShipper(repo, sink, nil).Ship(context.Background()))
+	assert.Empty(t, shipped)
+}
@@ -0,0 +1,44 @@
+package integration
+
+import (
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"com.kong.connect/domain"
+	"com.kong.connect/handler"
+	"com.kong.connect/repository"
+	"com.kong.connect/service"
+	"com.kong.connect/testsupport"
+)
+
+func TestResponsesCarryContentLength(t *testing.T) {
+	router := newTestRouter(t)
+
+	for _, path := range []string{"/api/v1/services", "/api/v1/services/1", "/api/v1/services/1/versions", "/api/v1/search?q=us", "/api/v1/stats"} {
+		response := doRequest(t, router, "GET", path, "viewer-token", nil)
+		require.Equal(t, http.StatusOK, response.Code, path)
+		assert.Equal(t, strconv.Itoa(response.Body.Len()), response.Header().Get("Content-Length"), path)
+		assert.Equal(t, "application/json", response.Header().Get("Content-Type"), path)
+	}
+
+	response := doRequest(t, router, "POST", "/api/v1/services", "admin-token", domain.ServiceInput{Name: "Billing"})
+	require.Equal(t, http.StatusCreated, response.Code)
+	assert.Equal(t, strconv.Itoa(response.Body.Len()), response.Header().Get("Content-Length"))
+}
+
+func TestResponsesOverTheSizeLimitAreRefused(t *testing.T) {
+	repo := repository.NewServiceRepository(testsupport.NewDB(t))
+	router := handler.SetupRouter(handler.NewServiceHandler(service.NewServiceService(repo), handler.WithMaxResponseSize(1024)))
+
+	response := doRequest(t, router, "GET", "/api/v1/services/1", "viewer-token", nil)
+	require.Equal(t, http.StatusOK, response.Code)
+	assert.Less(t, response.Body.Len(), 1024)
+
+	response = doRequest(t, router, "GET", "/api/v1/services?page_size=100", "viewer-token", nil)
+	assert.Equal(t, http.StatusInternalServerError, response.Code)
+	assert.Equal(t, "application/problem+json", response.Header().Get("Content-Type"))
+}
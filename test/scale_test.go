@@ -0,0 +1,84 @@
+package integration
+
+import (
+	"encoding/json"
+	"flag"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"com.kong.connect/database"
+	"com.kong.connect/domain"
+	"com.kong.connect/handler"
+	"com.kong.connect/repository"
+	"com.kong.connect/service"
+	"com.kong.connect/testsupport"
+)
+
+// scale is the size of the generated catalog; raise it to check pagination
+// and search at production sizes, e.g. go test ./test -run Scale -scale 100000
+var scale = flag.Int("scale", 5_000, "services generated by the scale tests")
+
+func TestPaginationAndSearchAtScale(t *testing.T) {
+	services, err := database.Generate(database.GenerateOptions{Services: *scale, MaxVersions: 5, Seed: 42})
+	require.NoError(t, err)
+
+	db := testsupport.NewEmptyDB(t)
+	seeded, err := database.SeedServices(db, services)
+	require.NoError(t, err)
+	require.True(t, seeded)
+
+	router := handler.SetupRouter(handler.NewServiceHandler(service.NewServiceService(repository.NewServiceRepository(db))))
+
+	versions := make(map[string][]string, len(services))
+	for _, s := range services {
+		versions[s.Name] = s.Versions
+	}
+
+	// Walking every page lists every service exactly once, in name order and
+	// with its own versions
+	seen := make(map[string]bool, len(services))
+	previous := ""
+	pages := 1
+	for page := 1; page <= pages; page++ {
+		var listing domain.ServiceListResponse
+		response := doRequest(t, router, "GET", "/api/v1/services?page_size=100&sort_by=name&page="+itoa(page), "viewer-token", nil)
+		require.Equal(t, http.StatusOK, response.Code, response.Body.String())
+		require.NoError(t, json.Unmarshal(response.Body.Bytes(), &listing))
+		require.Equal(t, len(services), listing.Total)
+		pages = listing.TotalPages
+		for _, s := range listing.Services {
+			require.False(t, seen[s.Name], "%s listed twice", s.Name)
+			require.LessOrEqual(t, previous, s.Name)
+			seen[s.Name], previous = true, s.Name
+
+			listed := make([]string, len(s.Versions))
+			for i, version := range s.Versions {
+				require.Equal(t, s.ID, version.ServiceID)
+				listed[i] = version.Version
+			}
+			require.ElementsMatch(t, versions[s.Name], listed, s.Name)
+		}
+	}
+	assert.Len(t, seen, len(services))
+
+	// Search totals match the services whose name or description match
+	for _, term := range []string{"payments", "Gateway EU", "media"} {
+		expected := 0
+		for _, s := range services {
+			if strings.Contains(strings.ToLower(s.Name), strings.ToLower(term)) || strings.Contains(strings.ToLower(s.Description), strings.ToLower(term)) {
+				expected++
+			}
+		}
+
+		var listing domain.ServiceListResponse
+		response := doRequest(t, router, "GET", "/api/v1/services?page_size=100&search="+strings.ReplaceAll(term, " ", "+"), "viewer-token", nil)
+		require.Equal(t, http.StatusOK, response.Code, response.Body.String())
+		require.NoError(t, json.Unmarshal(response.Body.Bytes(), &listing))
+		assert.Equal(t, expected, listing.Total, term)
+		assert.Len(t, listing.Services, min(expected, 100), term)
+	}
+}
@@ -0,0 +1,162 @@
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"com.kong.connect/database"
+	"com.kong.connect/domain"
+	"com.kong.connect/handler"
+	"com.kong.connect/repository"
+	"com.kong.connect/semver"
+	"com.kong.connect/service"
+	"com.kong.connect/testsupport"
+)
+
+// searchNames returns the names of the services found by the search endpoint
+func searchNames(t *testing.T, router http.Handler, q string) []string {
+	t.Helper()
+	response := doRequest(t, router, "GET", "/api/v1/search?q="+q, "viewer-token", nil)
+	require.Equal(t, http.StatusOK, response.Code, response.Body.String())
+	var results domain.SearchResponse
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &results))
+	names := []string{}
+	for _, result := range results.Results {
+		names = append(names, result.Service.Name)
+	}
+	return names
+}
+
+// listNames returns the names of the services listed with a search filter
+func listNames(t *testing.T, router http.Handler, search string) []string {
+	t.Helper()
+	response := doRequest(t, router, "GET", "/api/v1/services?search="+search, "viewer-token", nil)
+	require.Equal(t, http.StatusOK, response.Code, response.Body.String())
+	var listing domain.ServiceListResponse
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &listing))
+	names := []string{}
+	for _, service := range listing.Services {
+		names = append(names, service.Name)
+	}
+	return names
+}
+
+func TestSearchIgnoresCaseAndAccents(t *testing.T) {
+	router := newTestRouter(t)
+	for _, input := range []domain.ServiceInput{
+		{Name: "Sécurité", Description: "Contrôle d'accès"},
+		{Name: "Straßenverkehr", Description: "Traffic data"},
+	} {
+		response := doRequest(t, router, "POST", "/api/v1/services", "admin-token", input)
+		require.Equal(t, http.StatusCreated, response.Code, response.Body.String())
+	}
+
+	assert.Equal(t, []string{"Sécurité"}, searchNames(t, router, "Securite"))
+	assert.Equal(t, []string{"Sécurité"}, searchNames(t, router, "SÉCURITÉ"))
+	assert.Equal(t, []string{"Sécurité"}, searchNames(t, router, "acces"))
+	assert.Equal(t, []string{"Straßenverkehr"}, searchNames(t, router, "strassen"))
+	assert.Equal(t, []string{"Sécurité"}, listNames(t, router, "securite"))
+	assert.Equal(t, []string{"Straßenverkehr"}, listNames(t, router, "STRASSE"))
+
+	response := doRequest(t, router, "GET", "/api/v1/search?q=securite", "viewer-token", nil)
+	var results domain.SearchResponse
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &results))
+	require.Len(t, results.Results, 1)
+	assert.Equal(t, 100, results.Results[0].Score, "an exact name match")
+	assert.Equal(t, "<mark>Sécurité</mark>", results.Results[0].Highlight.Name)
+
+	// Renamed services are found by their new name
+	response = doRequest(t, router, "PUT", "/api/v1/services/1", "admin-token", domain.ServiceInput{Name: "Géolocalisation", Description: "Find us"})
+	require.Equal(t, http.StatusOK, response.Code, response.Body.String())
+	assert.Equal(t, []string{"Géolocalisation"}, searchNames(t, router, "geolocalisation"))
+}
+
+func TestSearchHighlightsAreEscaped(t *testing.T) {
+	router := newTestRouter(t)
+
+	input := domain.ServiceInput{Name: "Widgets <b>", Description: `Widgets <script>alert("widgets")</script> & more`}
+	response := doRequest(t, router, "POST", "/api/v1/services", "admin-token", input)
+	require.Equal(t, http.StatusCreated, response.Code, response.Body.String())
+
+	response = doRequest(t, router, "GET", "/api/v1/search?q=widgets", "viewer-token", nil)
+	var results domain.SearchResponse
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &results))
+	require.Len(t, results.Results, 1)
+	assert.Equal(t, "<mark>Widgets</mark> &lt;b&gt;", results.Results[0].Highlight.Name)
+	assert.Equal(t, `<mark>Widgets</mark> &lt;script&gt;alert(&#34;<mark>widgets</mark>&#34;)&lt;/script&gt; &amp; more`, results.Results[0].Highlight.Description)
+	assert.Equal(t, input.Description, results.Results[0].Service.Description, "the service itself is returned as stored")
+}
+
+func TestAccentSensitiveSearch(t *testing.T) {
+	repo := repository.NewServiceRepository(testsupport.NewDB(t), repository.WithAccentSensitiveSearch())
+	router := handler.SetupRouter(handler.NewServiceHandler(service.NewServiceService(repo)))
+	response := doRequest(t, router, "POST", "/api/v1/services", "admin-token", domain.ServiceInput{Name: "Sécurité", Description: "Access control"})
+	require.Equal(t, http.StatusCreated, response.Code, response.Body.String())
+
+	assert.Empty(t, searchNames(t, router, "securite"))
+	assert.Empty(t, listNames(t, router, "securite"))
+	assert.Equal(t, []string{"Sécurité"}, searchNames(t, router, "SÉCURITÉ"))
+	assert.Equal(t, []string{"Sécurité"}, listNames(t, router, "sécurité"))
+}
+
+func TestMigrateFoldsServicesForSearch(t *testing.T) {
+	db := testsupport.NewEmptyDB(t)
+
+	// Rows written while the triggers were missing, e.g. by an older release
+	_, err := db.Exec("DROP TRIGGER services_search_insert")
+	require.NoError(t, err)
+	_, err = db.Exec("INSERT INTO services (name, description) VALUES ('Sécurité', '')")
+	require.NoError(t, err)
+
+	require.NoError(t, database.Migrate(db))
+	var folded, unaccented string
+	require.NoError(t, db.QueryRow("SELECT folded_name, unaccented_name FROM services").Scan(&folded, &unaccented))
+	assert.Equal(t, "sécurité", folded)
+	assert.Equal(t, "securite", unaccented)
+}
+
+func TestSearchFindsServicesByVersion(t *testing.T) {
+	router := newTestRouter(t)
+
+	// Collect Monday and Priority Services ship 2.1.0
+	assert.Equal(t, []string{"Collect Monday", "Priority Services"}, searchNames(t, router, "2.1.0"))
+	assert.Equal(t, []string{"Collect Monday", "Priority Services"}, listNames(t, router, "2.1.0"))
+	assert.Equal(t, []string{"Collect Monday", "Priority Services"}, searchNames(t, router, "v2.1"), "versions match in any convention")
+	assert.Empty(t, searchNames(t, router, "2"), "versions match whole")
+
+	// A name match still ranks first
+	assert.Equal(t, []string{"Priority Services", "Collect Monday"}, searchNames(t, router, "priority+2.1.0"))
+}
+
+// FuzzListingSearch lists the seeded catalog with arbitrary search filters,
+// which must neither fail nor find services not containing the filter
+func FuzzListingSearch(f *testing.F) {
+	for _, search := range []string{"us", "Us", "1.0", "v2.1", "%", "_", `\`, "50%_", "'", `" OR 1=1 --`, "'; DROP TABLE services; --", "é", "\x00", "\xff"} {
+		f.Add(search)
+	}
+	repo := testsupport.NewRepository(f)
+	ctx := context.Background()
+	fold := repo.SearchFold()
+
+	f.Fuzz(func(t *testing.T, search string) {
+		services, total, err := repo.GetAll(ctx, testsupport.Query().Search(search).Page(1, 100).Build())
+		require.NoError(t, err)
+		require.Equal(t, total, len(services))
+		for _, service := range services {
+			if strings.Contains(fold(service.Name), fold(search)) || strings.Contains(fold(service.Description), fold(search)) {
+				continue
+			}
+			hasVersion := false
+			for _, version := range service.Versions {
+				hasVersion = hasVersion || version.Version == semver.Canonical(search)
+			}
+			assert.True(t, hasVersion, "%q does not contain %q", service.Name, search)
+		}
+	})
+}
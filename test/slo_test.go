@@ -0,0 +1,118 @@
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"com.kong.connect/domain"
+	"com.kong.connect/handler"
+	"com.kong.connect/repository"
+	"com.kong.connect/service"
+	"com.kong.connect/testsupport"
+)
+
+func TestSLOReportMeasuresProbeResults(t *testing.T) {
+	svc := service.NewServiceService(repository.NewServiceRepository(testsupport.NewDB(t)))
+	router := handler.SetupRouter(handler.NewServiceHandler(svc))
+	ctx := context.Background()
+
+	response := doRequest(t, router, "PUT", "/api/v1/services/1/slo", "viewer-token", domain.ServiceSLO{AvailabilityTarget: 99})
+	assert.Equal(t, http.StatusForbidden, response.Code)
+	for _, invalid := range []domain.ServiceSLO{{}, {AvailabilityTarget: 100}, {AvailabilityTarget: 99, LatencyTargetMS: -1}} {
+		response = doRequest(t, router, "PUT", "/api/v1/services/1/slo", "admin-token", invalid)
+		assert.Equal(t, http.StatusBadRequest, response.Code, "%+v", invalid)
+	}
+	response = doRequest(t, router, "PUT", "/api/v1/services/999/slo", "admin-token", domain.ServiceSLO{AvailabilityTarget: 99})
+	assert.Equal(t, http.StatusNotFound, response.Code)
+
+	slos := map[int]domain.ServiceSLO{
+		1: {AvailabilityTarget: 70, LatencyTargetMS: 200},
+		2: {AvailabilityTarget: 99.9},
+		3: {AvailabilityTarget: 99.5},
+	}
+	for id, slo := range slos {
+		response = doRequest(t, router, "PUT", "/api/v1/services/"+itoa(id)+"/slo", "admin-token", slo)
+		require.Equal(t, http.StatusOK, response.Code, response.Body.String())
+	}
+	response = doRequest(t, router, "GET", "/api/v1/services/1", "viewer-token", nil)
+	require.Equal(t, http.StatusOK, response.Code)
+	var locate domain.ServiceWithVersions
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &locate))
+	assert.Equal(t, &domain.ServiceSLO{AvailabilityTarget: 70, LatencyTargetMS: 200}, locate.SLO)
+
+	// Service 1 is up 3 times out of 4, 2 of them within its latency target;
+	// service 2 is always up; service 3 is not probed
+	now := time.Now().UTC()
+	results := map[int][]domain.HealthResult{
+		1: {
+			{Status: domain.HealthUp, LatencyMS: 100},
+			{Status: domain.HealthUp, LatencyMS: 200},
+			{Status: domain.HealthUp, LatencyMS: 900},
+			{Status: domain.HealthDown, Error: "unexpected status 503"},
+		},
+		2: {{Status: domain.HealthUp, LatencyMS: 5000}, {Status: domain.HealthUp, LatencyMS: 10}},
+	}
+	for id, probes := range results {
+		_, err := svc.SetHealthCheck(ctx, id, domain.HealthCheckInput{URL: "https://example.com/healthz"})
+		require.NoError(t, err)
+		for i, result := range probes {
+			result.ServiceID, result.URL, result.CheckedAt = id, "https://example.com/healthz", now.Add(-time.Duration(i+1)*time.Minute)
+			require.NoError(t, svc.RecordHealthResult(ctx, result))
+		}
+	}
+	// Probes before the window do not count
+	require.NoError(t, svc.RecordHealthResult(ctx, domain.HealthResult{
+		ServiceID: 2, URL: "https://example.com/healthz", Status: domain.HealthDown, CheckedAt: now.Add(-48 * time.Hour),
+	}))
+
+	query := url.Values{"since": {now.Add(-24 * time.Hour).Format(time.RFC3339)}, "until": {now.Add(time.Minute).Format(time.RFC3339)}}
+	response = doRequest(t, router, "GET", "/api/v1/slo-report?"+query.Encode(), "viewer-token", nil)
+	require.Equal(t, http.StatusOK, response.Code, response.Body.String())
+	var report domain.SLOReport
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &report))
+	require.Len(t, report.Services, 3)
+	assert.Equal(t, 1, report.Met)
+	assert.Equal(t, 1, report.Breached)
+	assert.Equal(t, 1, report.NoData)
+
+	attainments := make(map[int]domain.SLOAttainment)
+	for _, attainment := range report.Services {
+		attainments[attainment.ServiceID] = attainment
+	}
+	locateUs := attainments[1]
+	assert.Equal(t, "Locate Us", locateUs.ServiceName)
+	assert.Equal(t, 4, locateUs.Probes)
+	assert.Equal(t, 3, locateUs.UpProbes)
+	assert.Equal(t, 75.0, locateUs.Availability)
+	assert.Equal(t, 66.667, locateUs.LatencyAttainment)
+	assert.Equal(t, 16.667, locateUs.ErrorBudgetRemaining)
+	assert.Equal(t, domain.SLOBreached, locateUs.Status)
+
+	assert.Equal(t, 2, attainments[2].Probes)
+	assert.Equal(t, 100.0, attainments[2].Availability)
+	assert.Equal(t, 100.0, attainments[2].ErrorBudgetRemaining)
+	assert.Equal(t, domain.SLOMet, attainments[2].Status)
+
+	assert.Equal(t, 0, attainments[3].Probes)
+	assert.Equal(t, domain.SLONoData, attainments[3].Status)
+
+	response = doRequest(t, router, "GET", "/api/v1/slo-report?since="+url.QueryEscape(now.Format(time.RFC3339))+"&until="+url.QueryEscape(now.Add(-time.Hour).Format(time.RFC3339)), "viewer-token", nil)
+	assert.Equal(t, http.StatusBadRequest, response.Code)
+
+	// Services without SLOs leave the report
+	response = doRequest(t, router, "DELETE", "/api/v1/services/3/slo", "admin-token", nil)
+	assert.Equal(t, http.StatusNoContent, response.Code)
+	response = doRequest(t, router, "DELETE", "/api/v1/services/3/slo", "admin-token", nil)
+	assert.Equal(t, http.StatusNotFound, response.Code)
+	response = doRequest(t, router, "GET", "/api/v1/slo-report", "viewer-token", nil)
+	require.Equal(t, http.StatusOK, response.Code)
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &report))
+	assert.Len(t, report.Services, 2)
+}
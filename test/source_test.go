@@ -0,0 +1,118 @@
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"com.kong.connect/domain"
+	"com.kong.connect/events"
+	"com.kong.connect/handler"
+	"com.kong.connect/repository"
+	"com.kong.connect/scm"
+	"com.kong.connect/service"
+	"com.kong.connect/testsupport"
+)
+
+func TestSourceRepositories(t *testing.T) {
+	bus := events.NewBus()
+	var published []domain.ChangeEvent
+	bus.Subscribe(func(event domain.ChangeEvent) {
+		published = append(published, event)
+	})
+	svc := service.NewServiceService(repository.NewServiceRepository(testsupport.NewDB(t)), service.WithPublisher(bus))
+	router := handler.SetupRouter(handler.NewServiceHandler(svc))
+
+	release := "v1.0.0"
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/repos/kong/locate":
+			w.Write([]byte(`{"default_branch": "main"}`))
+		case "/repos/kong/locate/releases/latest":
+			w.Write([]byte(`{"tag_name": "` + release + `", "published_at": "2026-03-01T12:00:00Z"}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer api.Close()
+	refresher := scm.NewRefresher(scm.Config{GitHubURL: api.URL}, svc, api.Client(), nil)
+
+	get := func() *domain.SourceRepository {
+		t.Helper()
+		response := doRequest(t, router, "GET", "/api/v1/services/1", "viewer-token", nil)
+		require.Equal(t, http.StatusOK, response.Code)
+		var service domain.ServiceWithVersions
+		require.NoError(t, json.Unmarshal(response.Body.Bytes(), &service))
+		return service.Repository
+	}
+
+	for _, test := range []struct {
+		token string
+		path  string
+		url   string
+		want  int
+	}{
+		{"viewer-token", "/api/v1/services/1/repository", "https://github.com/kong/locate", http.StatusForbidden},
+		{"admin-token", "/api/v1/services/1/repository", "https://bitbucket.org/kong/locate", http.StatusBadRequest},
+		{"admin-token", "/api/v1/services/1/repository", "https://github.com/kong", http.StatusBadRequest},
+		{"admin-token", "/api/v1/services/999/repository", "https://github.com/kong/locate", http.StatusNotFound},
+	} {
+		response := doRequest(t, router, "PUT", test.path, test.token, domain.SourceRepositoryInput{URL: test.url})
+		assert.Equal(t, test.want, response.Code, "%s %s", test.path, test.url)
+	}
+
+	response := doRequest(t, router, "PUT", "/api/v1/services/1/repository", "admin-token", domain.SourceRepositoryInput{URL: "git@github.com:kong/locate.git"})
+	require.Equal(t, http.StatusOK, response.Code, response.Body.String())
+	var linked domain.SourceRepository
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &linked))
+	assert.Equal(t, domain.SourceRepository{URL: "https://github.com/kong/locate", Provider: domain.ProviderGitHub, Path: "kong/locate"}, linked)
+	assert.Equal(t, &linked, get())
+
+	// The refresh fills in the default branch and the latest release
+	published = nil
+	require.NoError(t, refresher.Refresh(context.Background()))
+	refreshed := get()
+	require.NotNil(t, refreshed)
+	assert.Equal(t, "main", refreshed.DefaultBranch)
+	require.NotNil(t, refreshed.LatestRelease)
+	assert.Equal(t, "v1.0.0", refreshed.LatestRelease.Tag)
+	assert.NotNil(t, refreshed.RefreshedAt)
+	require.Len(t, published, 1)
+	assert.Equal(t, domain.EventServiceUpdated, published[0].Type)
+	assert.Nil(t, published[0].Previous.Repository.LatestRelease)
+
+	// Refreshes changing nothing publish nothing; new releases are picked up
+	published = nil
+	require.NoError(t, refresher.Refresh(context.Background()))
+	assert.Empty(t, published)
+	release = "v1.1.0"
+	require.NoError(t, refresher.Refresh(context.Background()))
+	assert.Len(t, published, 1)
+	assert.Equal(t, "v1.1.0", get().LatestRelease.Tag)
+
+	// Failed refreshes keep the details of the refresh before
+	response = doRequest(t, router, "PUT", "/api/v1/services/1/repository", "admin-token", domain.SourceRepositoryInput{URL: "https://github.com/kong/missing"})
+	require.Equal(t, http.StatusOK, response.Code, response.Body.String())
+	require.NoError(t, refresher.Refresh(context.Background()))
+	missing := get()
+	assert.Equal(t, "https://github.com/kong/missing", missing.URL)
+	assert.Equal(t, "repository kong/missing not found", missing.Error)
+	assert.Empty(t, missing.DefaultBranch)
+
+	// Linking and unlinking are audited as updates of the service
+	response = doRequest(t, router, "DELETE", "/api/v1/services/1/repository", "admin-token", nil)
+	assert.Equal(t, http.StatusNoContent, response.Code)
+	response = doRequest(t, router, "DELETE", "/api/v1/services/1/repository", "admin-token", nil)
+	assert.Equal(t, http.StatusNotFound, response.Code)
+	assert.Nil(t, get())
+	response = doRequest(t, router, "GET", "/api/v1/audit-logs?resource_type=service&resource_id=1", "admin-token", nil)
+	require.Equal(t, http.StatusOK, response.Code)
+	var audit domain.AuditListResponse
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &audit))
+	assert.Equal(t, 3, audit.Total)
+}
@@ -0,0 +1,186 @@
+package integration
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"com.kong.connect/domain"
+	"com.kong.connect/handler"
+	"com.kong.connect/openapi"
+	"com.kong.connect/repository"
+	"com.kong.connect/service"
+	"com.kong.connect/testsupport"
+)
+
+const locateSpec = `openapi: 3.0.3
+info:
+  title: Locate Us
+  version: 2.0.0
+paths:
+  /locations:
+    get:
+      summary: List the store locations
+      responses:
+        "200": {description: The locations}
+    post:
+      summary: Add a location
+      responses:
+        "201": {description: Created}
+`
+
+// uploadSpec puts a spec to a version as admin
+func uploadSpec(t *testing.T, router http.Handler, path, spec string) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest("PUT", path+"/spec", strings.NewReader(spec))
+	req.Header.Set("Authorization", "Bearer admin-token")
+	req.Header.Set("Content-Type", "application/yaml")
+	response := httptest.NewRecorder()
+	router.ServeHTTP(response, req)
+	return response
+}
+
+func TestVersionSpecsAreValidatedAndDocumented(t *testing.T) {
+	svc := service.NewServiceService(repository.NewServiceRepository(testsupport.NewDB(t)))
+	router := handler.SetupRouter(handler.NewServiceHandler(svc))
+
+	response := doRequest(t, router, "GET", "/api/v1/services/1", "viewer-token", nil)
+	require.Equal(t, http.StatusOK, response.Code)
+	var locate domain.ServiceWithVersions
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &locate))
+	require.NotEmpty(t, locate.Versions)
+	version := locate.Versions[0]
+	path := "/api/v1/services/1/versions/" + itoa(version.ID)
+
+	// Versions have no spec until one is uploaded
+	assert.Nil(t, version.Spec)
+	response = doRequest(t, router, "GET", path+"/docs", "viewer-token", nil)
+	assert.Equal(t, http.StatusNotFound, response.Code)
+	response = doRequest(t, router, "GET", path+"/postman", "viewer-token", nil)
+	assert.Equal(t, http.StatusNotFound, response.Code)
+
+	// Invalid specs are refused
+	response = uploadSpec(t, router, path, "openapi: 3.0.3\ninfo: {title: T}\npaths: {}\n")
+	assert.Equal(t, http.StatusBadRequest, response.Code, response.Body.String())
+	response = uploadSpec(t, router, path, `{"swagger": "2.0", "info": {"title": "T", "version": "1"}, "paths": {}}`)
+	assert.Equal(t, http.StatusBadRequest, response.Code, response.Body.String())
+	response = uploadSpec(t, router, "/api/v1/services/2/versions/"+itoa(version.ID), locateSpec)
+	assert.Equal(t, http.StatusNotFound, response.Code, "the version belongs to another service")
+
+	response = uploadSpec(t, router, path, locateSpec)
+	require.Equal(t, http.StatusOK, response.Code, response.Body.String())
+	var uploaded domain.ServiceVersion
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &uploaded))
+	require.NotNil(t, uploaded.Spec)
+	assert.Equal(t, 1, uploaded.Spec.Paths)
+	assert.Equal(t, 2, uploaded.Spec.Operations)
+	assert.Equal(t, map[string]int{"get": 1, "post": 1}, uploaded.Spec.OperationsByMethod)
+	assert.Equal(t, "2.0.0", uploaded.Spec.APIVersion)
+
+	// The metadata is part of the version wherever it is listed
+	response = doRequest(t, router, "GET", "/api/v1/services/1/versions", "viewer-token", nil)
+	require.Equal(t, http.StatusOK, response.Code)
+	var versions domain.VersionListResponse
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &versions))
+	for _, v := range versions.Versions {
+		if v.ID == version.ID {
+			require.NotNil(t, v.Spec)
+			assert.Equal(t, 2, v.Spec.Operations)
+		} else {
+			assert.Nil(t, v.Spec)
+		}
+	}
+
+	// The spec is served as uploaded, and rendered
+	response = doRequest(t, router, "GET", path+"/spec", "viewer-token", nil)
+	require.Equal(t, http.StatusOK, response.Code)
+	assert.Equal(t, "application/yaml", response.Header().Get("Content-Type"))
+	assert.Equal(t, locateSpec, response.Body.String())
+
+	response = doRequest(t, router, "GET", path+"/docs", "viewer-token", nil)
+	require.Equal(t, http.StatusOK, response.Code)
+	assert.Equal(t, "text/html; charset=utf-8", response.Header().Get("Content-Type"))
+	assert.NotEmpty(t, response.Header().Get("Content-Security-Policy"))
+	assert.Contains(t, response.Body.String(), "List the store locations")
+	assert.Contains(t, response.Body.String(), "Locate Us version "+version.Version)
+
+	// and exported as a Postman collection
+	response = doRequest(t, router, "GET", path+"/postman", "viewer-token", nil)
+	require.Equal(t, http.StatusOK, response.Code, response.Body.String())
+	assert.Equal(t, "application/json", response.Header().Get("Content-Type"))
+	assert.Equal(t, `attachment; filename="Locate Us `+version.Version+`.postman_collection.json"`, response.Header().Get("Content-Disposition"))
+	var collection openapi.PostmanCollection
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &collection))
+	assert.Equal(t, "Locate Us", collection.Info.Name)
+	assert.Equal(t, version.Version, collection.Info.Version)
+	require.Len(t, collection.Item, 2)
+	assert.Equal(t, "List the store locations", collection.Item[0].Name)
+	assert.Equal(t, "{{baseUrl}}/locations", collection.Item[0].Request.URL.Raw)
+	response = doRequest(t, router, "GET", "/api/v1/services/2/versions/"+itoa(version.ID)+"/postman", "viewer-token", nil)
+	assert.Equal(t, http.StatusNotFound, response.Code, "the version belongs to another service")
+
+	response = doRequest(t, router, "DELETE", path+"/spec", "viewer-token", nil)
+	assert.Equal(t, http.StatusForbidden, response.Code)
+	response = doRequest(t, router, "DELETE", path+"/spec", "admin-token", nil)
+	assert.Equal(t, http.StatusNoContent, response.Code)
+	response = doRequest(t, router, "GET", path+"/spec", "viewer-token", nil)
+	assert.Equal(t, http.StatusNotFound, response.Code)
+	response = doRequest(t, router, "DELETE", path+"/spec", "admin-token", nil)
+	assert.Equal(t, http.StatusNotFound, response.Code)
+}
+
+func TestCompareVersionSpecs(t *testing.T) {
+	svc := service.NewServiceService(repository.NewServiceRepository(testsupport.NewDB(t)))
+	router := handler.SetupRouter(handler.NewServiceHandler(svc))
+
+	response := doRequest(t, router, "GET", "/api/v1/services/1", "viewer-token", nil)
+	require.Equal(t, http.StatusOK, response.Code)
+	var locate domain.ServiceWithVersions
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &locate))
+	ids := make(map[string]int)
+	for _, v := range locate.Versions {
+		ids[v.Version] = v.ID
+	}
+	require.Contains(t, ids, "1.0.0")
+	require.Contains(t, ids, "2.0.0")
+
+	path := "/api/v1/services/1/versions/compare?from=1.0.0&to=2.0.0"
+	response = doRequest(t, router, "GET", path, "viewer-token", nil)
+	assert.Equal(t, http.StatusNotFound, response.Code, "the versions have no spec yet")
+
+	oldSpec := strings.Replace(locateSpec, `"201": {description: Created}`, `"200": {description: Added}`, 1)
+	response = uploadSpec(t, router, "/api/v1/services/1/versions/"+itoa(ids["1.0.0"]), oldSpec)
+	require.Equal(t, http.StatusOK, response.Code, response.Body.String())
+	response = uploadSpec(t, router, "/api/v1/services/1/versions/"+itoa(ids["2.0.0"]), locateSpec)
+	require.Equal(t, http.StatusOK, response.Code, response.Body.String())
+
+	response = doRequest(t, router, "GET", path, "viewer-token", nil)
+	require.Equal(t, http.StatusOK, response.Code, response.Body.String())
+	var comparison domain.SpecComparison
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &comparison))
+	assert.Equal(t, "1.0.0", comparison.From)
+	assert.Equal(t, "2.0.0", comparison.To)
+	assert.True(t, comparison.Breaking)
+	assert.Empty(t, comparison.Added)
+	assert.Empty(t, comparison.Removed)
+	assert.Equal(t, []domain.EndpointChanges{{Method: "POST", Path: "/locations", Breaking: true, Changes: []domain.SpecChange{
+		{Description: "response 200 removed", Breaking: true},
+		{Description: "response 201 added"},
+	}}}, comparison.Changed)
+
+	response = doRequest(t, router, "GET", "/api/v1/services/1/versions/compare?from=2.0.0&to=2.0.0", "viewer-token", nil)
+	require.Equal(t, http.StatusOK, response.Code)
+	assert.Contains(t, response.Body.String(), `"breaking":false`)
+
+	response = doRequest(t, router, "GET", "/api/v1/services/1/versions/compare?from=1.0.0", "viewer-token", nil)
+	assert.Equal(t, http.StatusBadRequest, response.Code)
+	response = doRequest(t, router, "GET", "/api/v1/services/1/versions/compare?from=1.0.0&to=9.9.9", "viewer-token", nil)
+	assert.Equal(t, http.StatusNotFound, response.Code)
+	response = doRequest(t, router, "GET", "/api/v1/services/999/versions/compare?from=1.0.0&to=2.0.0", "viewer-token", nil)
+	assert.Equal(t, http.StatusNotFound, response.Code)
+}
@@ -0,0 +1,121 @@
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"com.kong.connect/domain"
+	"com.kong.connect/events"
+	"com.kong.connect/handler"
+	"com.kong.connect/repository"
+	"com.kong.connect/service"
+	"com.kong.connect/testsupport"
+)
+
+func TestSunsetsDeprecateAndArchiveOnSchedule(t *testing.T) {
+	db := testsupport.NewDB(t)
+	bus := events.NewBus()
+	var published []string
+	bus.Subscribe(func(event domain.ChangeEvent) {
+		published = append(published, event.Type)
+	})
+	svc := service.NewServiceService(repository.NewServiceRepository(db), service.WithPublisher(bus))
+	router := handler.SetupRouter(handler.NewServiceHandler(svc))
+	ctx := context.Background()
+
+	get := func(id int) domain.ServiceWithVersions {
+		t.Helper()
+		response := doRequest(t, router, "GET", "/api/v1/services/"+itoa(id), "viewer-token", nil)
+		require.Equal(t, http.StatusOK, response.Code)
+		var service domain.ServiceWithVersions
+		require.NoError(t, json.Unmarshal(response.Body.Bytes(), &service))
+		return service
+	}
+
+	now := time.Now().UTC().Truncate(time.Second)
+	past, later := now.Add(-time.Minute), now.AddDate(0, 0, 10)
+	for _, test := range []struct {
+		token string
+		path  string
+		input domain.SunsetInput
+		want  int
+	}{
+		{"viewer-token", "/api/v1/services/1/sunset", domain.SunsetInput{ArchiveAt: later}, http.StatusForbidden},
+		{"admin-token", "/api/v1/services/1/sunset", domain.SunsetInput{}, http.StatusBadRequest},
+		{"admin-token", "/api/v1/services/1/sunset", domain.SunsetInput{ArchiveAt: past}, http.StatusBadRequest},
+		{"admin-token", "/api/v1/services/1/sunset", domain.SunsetInput{DeprecateAt: &later, ArchiveAt: later}, http.StatusBadRequest},
+		{"admin-token", "/api/v1/services/999/sunset", domain.SunsetInput{ArchiveAt: later}, http.StatusNotFound},
+		{"admin-token", "/api/v1/services/1/versions/999/sunset", domain.SunsetInput{ArchiveAt: later}, http.StatusNotFound},
+	} {
+		response := doRequest(t, router, "PUT", test.path, test.token, test.input)
+		assert.Equal(t, test.want, response.Code, "%s %+v", test.path, test.input)
+	}
+
+	// Deprecated at once, archived in 10 days
+	response := doRequest(t, router, "PUT", "/api/v1/services/1/sunset", "admin-token", domain.SunsetInput{DeprecateAt: &past, ArchiveAt: later})
+	require.Equal(t, http.StatusOK, response.Code, response.Body.String())
+	var sunset domain.Sunset
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &sunset))
+	assert.Equal(t, domain.Sunset{DeprecateAt: &past, ArchiveAt: later, Status: domain.StatusActive}, sunset)
+	locate := get(1)
+	require.NotNil(t, locate.Sunset)
+	assert.True(t, later.Equal(locate.Sunset.ArchiveAt))
+	assert.Equal(t, domain.StatusActive, locate.Status)
+
+	// The oldest version of "Collect Monday" is archived in 12 hours
+	collect := get(2)
+	version := collect.Versions[len(collect.Versions)-1]
+	response = doRequest(t, router, "PUT", "/api/v1/services/1/versions/"+itoa(version.ID)+"/sunset", "admin-token", domain.SunsetInput{ArchiveAt: later})
+	assert.Equal(t, http.StatusNotFound, response.Code, "the version of another service")
+	response = doRequest(t, router, "PUT", "/api/v1/services/2/versions/"+itoa(version.ID)+"/sunset", "admin-token",
+		domain.SunsetInput{ArchiveAt: now.Add(12 * time.Hour)})
+	require.Equal(t, http.StatusOK, response.Code, response.Body.String())
+
+	published = nil
+	require.NoError(t, svc.ApplySunsets(ctx))
+	assert.ElementsMatch(t, []string{domain.EventServiceUpdated, domain.EventServiceSunsetReminder, domain.EventVersionSunsetReminder}, published)
+	locate = get(1)
+	assert.Equal(t, domain.StatusDeprecated, locate.Status)
+	assert.Equal(t, domain.StatusDeprecated, locate.Sunset.Status)
+	collect = get(2)
+	assert.Equal(t, domain.StatusActive, collect.Status)
+	require.NotNil(t, collect.Versions[len(collect.Versions)-1].Sunset)
+	assert.Equal(t, domain.StatusActive, collect.Versions[len(collect.Versions)-1].Sunset.Status)
+
+	// Reminders are published once
+	published = nil
+	require.NoError(t, svc.ApplySunsets(ctx))
+	assert.Empty(t, published)
+
+	// Archived once their time has come
+	_, err := db.Exec("UPDATE sunsets SET archive_at = ?", past.Format("2006-01-02 15:04:05"))
+	require.NoError(t, err)
+	require.NoError(t, svc.ApplySunsets(ctx))
+	assert.ElementsMatch(t, []string{domain.EventServiceUpdated, domain.EventVersionSunsetUpdated}, published)
+	assert.Equal(t, domain.StatusArchived, get(1).Status)
+	collect = get(2)
+	assert.Equal(t, domain.StatusArchived, collect.Versions[len(collect.Versions)-1].Sunset.Status)
+
+	// The changes are audited as made by the sunset
+	response = doRequest(t, router, "GET", "/api/v1/audit-logs?principal=sunset&resource_type=service&resource_id=1", "admin-token", nil)
+	require.Equal(t, http.StatusOK, response.Code)
+	var audit domain.AuditListResponse
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &audit))
+	require.Equal(t, 2, audit.Total)
+	assert.Equal(t, domain.AuditChange{Before: domain.StatusDeprecated, After: domain.StatusArchived}, audit.Entries[0].Changes["status"])
+
+	// Cancelling a sunset keeps the status it applied
+	response = doRequest(t, router, "DELETE", "/api/v1/services/1/sunset", "admin-token", nil)
+	assert.Equal(t, http.StatusNoContent, response.Code)
+	response = doRequest(t, router, "DELETE", "/api/v1/services/1/sunset", "admin-token", nil)
+	assert.Equal(t, http.StatusNotFound, response.Code)
+	locate = get(1)
+	assert.Nil(t, locate.Sunset)
+	assert.Equal(t, domain.StatusArchived, locate.Status)
+}
@@ -0,0 +1,48 @@
+package integration
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"com.kong.connect/tracing"
+)
+
+func TestTracingContinuesIncomingTrace(t *testing.T) {
+	_, err := tracing.Init(context.Background())
+	require.NoError(t, err)
+
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(provider)
+	defer otel.SetTracerProvider(previous)
+
+	router := newTestRouter(t)
+
+	req, err := http.NewRequest("GET", "/api/v1/services/2", nil)
+	require.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer viewer-token")
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+
+	response := httptest.NewRecorder()
+	router.ServeHTTP(response, req)
+	require.Equal(t, http.StatusOK, response.Code)
+
+	names := map[string]bool{}
+	for _, span := range recorder.Ended() {
+		names[span.Name()] = true
+		assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", span.SpanContext().TraceID().String(),
+			"span %s should belong to the incoming trace", span.Name())
+	}
+	assert.True(t, names["GET /api/v1/services/{id}"])
+	assert.True(t, names["ServiceService.GetServiceByID"])
+	assert.True(t, names["ServiceRepository.GetByID"])
+}
@@ -0,0 +1,138 @@
+package integration
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"com.kong.connect/domain"
+	"com.kong.connect/handler"
+	"com.kong.connect/middleware"
+	"com.kong.connect/repository"
+	"com.kong.connect/service"
+	"com.kong.connect/testsupport"
+)
+
+func TestOwnershipTransfers(t *testing.T) {
+	svc := service.NewServiceService(repository.NewServiceRepository(testsupport.NewDB(t)), service.WithStaticUsers(middleware.IsStaticUser))
+	router := handler.SetupRouter(handler.NewServiceHandler(svc))
+
+	transfer := func(token string, want int, input domain.TransferInput) domain.OwnershipTransfer {
+		t.Helper()
+		response := doRequest(t, router, "POST", "/api/v1/services/1/transfer-ownership", token, input)
+		require.Equal(t, want, response.Code, response.Body.String())
+		var transfer domain.OwnershipTransfer
+		require.NoError(t, json.Unmarshal(response.Body.Bytes(), &transfer))
+		return transfer
+	}
+	resolve := func(token, action string, want int) domain.OwnershipTransfer {
+		t.Helper()
+		response := doRequest(t, router, "POST", "/api/v1/services/1/transfer-ownership/"+action, token, nil)
+		require.Equal(t, want, response.Code, response.Body.String())
+		var transfer domain.OwnershipTransfer
+		if response.Code == http.StatusOK {
+			require.NoError(t, json.Unmarshal(response.Body.Bytes(), &transfer))
+		}
+		return transfer
+	}
+	owner := func() string {
+		t.Helper()
+		response := doRequest(t, router, "GET", "/api/v1/services/1", "viewer-token", nil)
+		require.Equal(t, http.StatusOK, response.Code)
+		var service domain.ServiceWithVersions
+		require.NoError(t, json.Unmarshal(response.Body.Bytes(), &service))
+		return service.Owner
+	}
+
+	// Only the owner, web-team, or an admin requests a transfer
+	response := doRequest(t, router, "POST", "/api/v1/services/1/transfer-ownership", "viewer-token", domain.TransferInput{Owner: "viewer"})
+	assert.Equal(t, http.StatusForbidden, response.Code)
+	for _, test := range []struct {
+		path  string
+		input domain.TransferInput
+		want  int
+	}{
+		{"/api/v1/services/1/transfer-ownership", domain.TransferInput{Owner: " "}, http.StatusBadRequest},
+		{"/api/v1/services/1/transfer-ownership", domain.TransferInput{Owner: "web-team"}, http.StatusBadRequest},
+		{"/api/v1/services/999/transfer-ownership", domain.TransferInput{Owner: "viewer"}, http.StatusNotFound},
+		{"/api/v1/services/1/transfer-ownership", domain.TransferInput{Owner: "maps-team"}, http.StatusUnprocessableEntity},
+		{"/api/v1/services/1/transfer-ownership", domain.TransferInput{Owner: "nobody"}, http.StatusUnprocessableEntity},
+	} {
+		response := doRequest(t, router, "POST", test.path, "admin-token", test.input)
+		assert.Equal(t, test.want, response.Code, "%s %+v", test.path, test.input)
+	}
+
+	pending := transfer("admin-token", http.StatusAccepted, domain.TransferInput{Owner: " viewer ", Reason: "Reorg"})
+	assert.Equal(t, domain.TransferPending, pending.Status)
+	assert.Equal(t, "web-team", pending.FromOwner)
+	assert.Equal(t, "viewer", pending.ToOwner)
+	assert.Equal(t, "admin", pending.RequestedBy)
+	assert.Nil(t, pending.ResolvedAt)
+	response = doRequest(t, router, "POST", "/api/v1/services/1/transfer-ownership", "admin-token", domain.TransferInput{Owner: "admin"})
+	assert.Equal(t, http.StatusConflict, response.Code)
+	assert.Equal(t, "web-team", owner(), "pending until confirmed")
+
+	// The receiving owner confirms
+	confirmed := resolve("viewer-token", "confirm", http.StatusOK)
+	assert.Equal(t, pending.ID, confirmed.ID)
+	assert.Equal(t, domain.TransferCompleted, confirmed.Status)
+	assert.Equal(t, "viewer", confirmed.ResolvedBy)
+	assert.NotNil(t, confirmed.ResolvedAt)
+	assert.Equal(t, "viewer", owner())
+	response = doRequest(t, router, "POST", "/api/v1/services/1/transfer-ownership/confirm", "viewer-token", nil)
+	assert.Equal(t, http.StatusNotFound, response.Code)
+
+	// Only admins override the confirmation
+	response = doRequest(t, router, "POST", "/api/v1/services/1/transfer-ownership", "viewer-token", domain.TransferInput{Owner: "maps-team", Override: true})
+	assert.Equal(t, http.StatusForbidden, response.Code)
+	transfer("viewer-token", http.StatusAccepted, domain.TransferInput{Owner: "admin"})
+	response = doRequest(t, router, "POST", "/api/v1/services/1/transfer-ownership/confirm", "viewer-token", nil)
+	assert.Equal(t, http.StatusForbidden, response.Code)
+	declined := resolve("viewer-token", "decline", http.StatusOK)
+	assert.Equal(t, domain.TransferDeclined, declined.Status)
+	assert.Equal(t, "viewer", owner())
+
+	overridden := transfer("admin-token", http.StatusOK, domain.TransferInput{Owner: "web-team", Override: true})
+	assert.Equal(t, domain.TransferCompleted, overridden.Status)
+	assert.Equal(t, "admin", overridden.ResolvedBy)
+	assert.Equal(t, "web-team", owner())
+
+	// Transfers outdated by another change of owner cannot be confirmed
+	transfer("admin-token", http.StatusAccepted, domain.TransferInput{Owner: "viewer"})
+	response = doRequest(t, router, "PUT", "/api/v1/services/1", "admin-token",
+		domain.ServiceInput{Name: "Locate Us", Owner: "maps-team", Status: domain.StatusActive})
+	require.Equal(t, http.StatusOK, response.Code, response.Body.String())
+	resolve("viewer-token", "confirm", http.StatusConflict)
+	resolve("admin-token", "decline", http.StatusOK)
+
+	response = doRequest(t, router, "GET", "/api/v1/services/1/ownership-transfers", "viewer-token", nil)
+	require.Equal(t, http.StatusOK, response.Code)
+	var history domain.TransferListResponse
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &history))
+	statuses := []string{}
+	for _, transfer := range history.Transfers {
+		statuses = append(statuses, transfer.Status)
+	}
+	assert.Equal(t, []string{domain.TransferDeclined, domain.TransferCompleted, domain.TransferDeclined, domain.TransferCompleted}, statuses)
+	assert.Equal(t, "Reorg", history.Transfers[3].Reason)
+
+	// Requests and resolutions are audited, as are the changes of owner
+	response = doRequest(t, router, "GET", "/api/v1/audit-logs?resource_type=ownership_transfer", "admin-token", nil)
+	require.Equal(t, http.StatusOK, response.Code)
+	var audit domain.AuditListResponse
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &audit))
+	assert.Equal(t, 7, audit.Total)
+	response = doRequest(t, router, "GET", "/api/v1/audit-logs?resource_type=service&resource_id=1", "admin-token", nil)
+	require.Equal(t, http.StatusOK, response.Code)
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &audit))
+	assert.Equal(t, 3, audit.Total)
+	assert.Equal(t, domain.AuditChange{Before: "viewer", After: "web-team"}, audit.Entries[1].Changes["owner"])
+
+	response = doRequest(t, router, "DELETE", "/api/v1/services/1", "admin-token", nil)
+	require.Equal(t, http.StatusNoContent, response.Code)
+	response = doRequest(t, router, "GET", "/api/v1/services/1/ownership-transfers", "viewer-token", nil)
+	assert.Equal(t, http.StatusNotFound, response.Code)
+}
@@ -0,0 +1,114 @@
+package integration
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"com.kong.connect/cache"
+	"com.kong.connect/domain"
+	"com.kong.connect/handler"
+	"com.kong.connect/problem"
+	"com.kong.connect/repository"
+	"com.kong.connect/service"
+	"com.kong.connect/testsupport"
+)
+
+func TestDescriptionsAreServedInTheLanguageOfTheClient(t *testing.T) {
+	details := cache.NewLRU[service.DetailKey, *domain.ServiceWithVersions]("details", 10, time.Minute)
+	svc := service.NewServiceService(repository.NewServiceRepository(testsupport.NewDB(t)), service.WithDetailCache(details))
+	router := handler.SetupRouter(handler.NewServiceHandler(svc))
+
+	get := func(path, acceptLanguage string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", path, nil)
+		req.Header.Set("Authorization", "Bearer viewer-token")
+		if acceptLanguage != "" {
+			req.Header.Set("Accept-Language", acceptLanguage)
+		}
+		response := httptest.NewRecorder()
+		router.ServeHTTP(response, req)
+		return response
+	}
+	description := func(response *httptest.ResponseRecorder) string {
+		var service domain.ServiceWithVersions
+		require.NoError(t, json.Unmarshal(response.Body.Bytes(), &service))
+		return service.Description
+	}
+
+	original := description(get("/api/v1/services/1", ""))
+
+	response := doRequest(t, router, "PUT", "/api/v1/services/1/translations/de", "viewer-token", domain.TranslationInput{Description: "Findet Standorte"})
+	assert.Equal(t, http.StatusForbidden, response.Code)
+	for language, input := range map[string]domain.TranslationInput{
+		"de": {Description: "  "},
+		"en": {Description: "Locates things"},
+		"it": {Description: "Trova luoghi"},
+	} {
+		response = doRequest(t, router, "PUT", "/api/v1/services/1/translations/"+language, "admin-token", input)
+		assert.Equal(t, http.StatusBadRequest, response.Code, language)
+	}
+	response = doRequest(t, router, "PUT", "/api/v1/services/999/translations/de", "admin-token", domain.TranslationInput{Description: "Findet Standorte"})
+	assert.Equal(t, http.StatusNotFound, response.Code)
+
+	response = doRequest(t, router, "PUT", "/api/v1/services/1/translations/de", "admin-token", domain.TranslationInput{Description: "Findet Standorte"})
+	require.Equal(t, http.StatusOK, response.Code, response.Body.String())
+	var translation domain.Translation
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &translation))
+	assert.Equal(t, domain.Translation{Language: "de", Description: "Findet Standorte"}, translation)
+
+	response = get("/api/v1/services/1", "de-CH, en;q=0.5")
+	require.Equal(t, http.StatusOK, response.Code)
+	assert.Equal(t, "de", response.Header().Get("Content-Language"))
+	assert.Equal(t, "Findet Standorte", description(response))
+	// The cached service keeps its original description
+	assert.Equal(t, original, description(get("/api/v1/services/1", "")))
+	assert.Equal(t, original, description(get("/api/v1/services/1", "fr")), "untranslated languages fall back to the original")
+
+	var listing domain.ServiceListResponse
+	require.NoError(t, json.Unmarshal(get("/api/v1/services?search=locate", "de").Body.Bytes(), &listing))
+	require.NotEmpty(t, listing.Services)
+	assert.Equal(t, "Findet Standorte", listing.Services[0].Description)
+	assert.Equal(t, map[string]string{"de": "Findet Standorte"}, listing.Services[0].Translations)
+
+	response = doRequest(t, router, "DELETE", "/api/v1/services/1/translations/de", "admin-token", nil)
+	assert.Equal(t, http.StatusNoContent, response.Code)
+	assert.Equal(t, original, description(get("/api/v1/services/1", "de")))
+	response = doRequest(t, router, "DELETE", "/api/v1/services/1/translations/de", "admin-token", nil)
+	assert.Equal(t, http.StatusNotFound, response.Code)
+}
+
+func TestProblemsAreTranslated(t *testing.T) {
+	svc := service.NewServiceService(repository.NewServiceRepository(testsupport.NewDB(t)))
+	router := handler.SetupRouter(handler.NewServiceHandler(svc))
+
+	get := func(path, acceptLanguage string) problem.Details {
+		req := httptest.NewRequest("GET", path, nil)
+		req.Header.Set("Authorization", "Bearer viewer-token")
+		req.Header.Set("Accept-Language", acceptLanguage)
+		response := httptest.NewRecorder()
+		router.ServeHTTP(response, req)
+		var details problem.Details
+		require.NoError(t, json.Unmarshal(response.Body.Bytes(), &details))
+		return details
+	}
+
+	details := get("/api/v1/services/999", "fr-FR, de;q=0.9")
+	assert.Equal(t, "Introuvable", details.Title)
+	assert.Equal(t, "Service introuvable", details.Detail)
+	assert.Equal(t, problem.TypeNotFound, details.Type, "types are not translated")
+
+	details = get("/api/v1/services?page=0&colour=red", "de")
+	assert.Equal(t, "Ungültige Abfrageparameter", details.Title)
+	assert.Equal(t, []problem.InvalidParam{
+		{Name: "colour", Reason: "unbekannter Parameter"},
+		{Name: "page", Reason: "muss eine positive ganze Zahl sein"},
+	}, details.InvalidParams)
+
+	details = get("/api/v1/services/999", "it, *;q=0.1")
+	assert.Equal(t, "Service not found", details.Detail)
+}
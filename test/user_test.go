@@ -0,0 +1,93 @@
+package integration
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"com.kong.connect/domain"
+	"com.kong.connect/handler"
+	"com.kong.connect/middleware"
+	"com.kong.connect/repository"
+	"com.kong.connect/service"
+	"com.kong.connect/testsupport"
+)
+
+func TestIssuedTokensAuthenticateUntilRevoked(t *testing.T) {
+	db := testsupport.NewDB(t)
+
+	svc := service.NewServiceService(repository.NewServiceRepository(db))
+	router := handler.SetupRouter(handler.NewServiceHandler(svc))
+	middleware.SetTokenLookup(handler.TokenLookup(svc))
+	defer middleware.SetTokenLookup(nil)
+
+	// Bootstrap an admin with the static token
+	response := doRequest(t, router, "POST", "/api/v1/users", "admin-token", domain.UserInput{Username: "alice", Roles: []string{"Admin", "viewer", "admin"}})
+	require.Equal(t, http.StatusCreated, response.Code, response.Body.String())
+	var user domain.User
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &user))
+	assert.Equal(t, []string{"admin", "viewer"}, user.Roles)
+
+	response = doRequest(t, router, "POST", "/api/v1/users", "admin-token", domain.UserInput{Username: "alice", Roles: []string{"viewer"}})
+	assert.Equal(t, http.StatusConflict, response.Code)
+
+	response = doRequest(t, router, "POST", "/api/v1/tokens", "admin-token", domain.TokenInput{Username: "alice", Name: "laptop"})
+	require.Equal(t, http.StatusCreated, response.Code, response.Body.String())
+	var issued domain.IssuedToken
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &issued))
+	assert.True(t, strings.HasPrefix(issued.Token, "kc_"))
+	assert.Equal(t, "alice", issued.Username)
+
+	// The issued token carries the user's roles
+	response = doRequest(t, router, "POST", "/api/v1/services", issued.Token, domain.ServiceInput{Name: "Billing"})
+	assert.Equal(t, http.StatusCreated, response.Code)
+
+	// Listings never include the secret
+	response = doRequest(t, router, "GET", "/api/v1/tokens", issued.Token, nil)
+	require.Equal(t, http.StatusOK, response.Code)
+	assert.NotContains(t, response.Body.String(), issued.Token)
+
+	// Revoked tokens stop authenticating
+	response = doRequest(t, router, "DELETE", "/api/v1/tokens/"+itoa(issued.ID), "admin-token", nil)
+	assert.Equal(t, http.StatusNoContent, response.Code)
+	assert.Equal(t, http.StatusUnauthorized, doRequest(t, router, "GET", "/api/v1/services", issued.Token, nil).Code)
+	assert.Equal(t, http.StatusNotFound, doRequest(t, router, "DELETE", "/api/v1/tokens/999", "admin-token", nil).Code)
+
+	// So do the tokens of disabled users, and none can be issued to them
+	response = doRequest(t, router, "POST", "/api/v1/tokens", "admin-token", domain.TokenInput{Username: "alice"})
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &issued))
+	assert.Equal(t, http.StatusOK, doRequest(t, router, "GET", "/api/v1/services", issued.Token, nil).Code)
+
+	response = doRequest(t, router, "POST", "/api/v1/users/alice/disable", "admin-token", nil)
+	require.Equal(t, http.StatusOK, response.Code)
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &user))
+	assert.True(t, user.Disabled)
+	assert.Equal(t, http.StatusUnauthorized, doRequest(t, router, "GET", "/api/v1/services", issued.Token, nil).Code)
+	assert.Equal(t, http.StatusBadRequest, doRequest(t, router, "POST", "/api/v1/tokens", "admin-token", domain.TokenInput{Username: "alice"}).Code)
+	assert.Equal(t, http.StatusNotFound, doRequest(t, router, "POST", "/api/v1/users/bob/disable", "admin-token", nil).Code)
+
+	// User and token changes are audited
+	response = doRequest(t, router, "GET", "/api/v1/audit-logs?resource_type=token", "admin-token", nil)
+	var entries domain.AuditListResponse
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &entries))
+	assert.Equal(t, 3, entries.Total)
+}
+
+func TestCreateUserValidation(t *testing.T) {
+	router := newTestRouter(t)
+
+	for _, input := range []domain.UserInput{
+		{Username: "", Roles: []string{"viewer"}},
+		{Username: "has space", Roles: []string{"viewer"}},
+		{Username: "carol"},
+		{Username: "carol", Roles: []string{"owner"}},
+	} {
+		response := doRequest(t, router, "POST", "/api/v1/users", "admin-token", input)
+		assert.Equal(t, http.StatusBadRequest, response.Code, input)
+	}
+	assert.Equal(t, http.StatusForbidden, doRequest(t, router, "GET", "/api/v1/users", "viewer-token", nil).Code)
+}
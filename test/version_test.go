@@ -0,0 +1,80 @@
+package integration
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"com.kong.connect/database"
+	"com.kong.connect/domain"
+	"com.kong.connect/testsupport"
+)
+
+func TestVersionsAreStoredCanonical(t *testing.T) {
+	router := newTestRouter(t)
+
+	response := doRequest(t, router, "POST", "/api/v1/services/1/versions", "admin-token", domain.VersionInput{Version: " v2.1 "})
+	require.Equal(t, http.StatusCreated, response.Code, response.Body.String())
+	var version domain.ServiceVersion
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &version))
+	assert.Equal(t, "2.1.0", version.Version)
+	assert.Equal(t, "v2.1", version.OriginalVersion)
+
+	// The same version in another convention is a duplicate
+	response = doRequest(t, router, "POST", "/api/v1/services/1/versions", "admin-token", domain.VersionInput{Version: "2.1.0"})
+	assert.Equal(t, http.StatusConflict, response.Code, response.Body.String())
+
+	// Canonical versions and those that are not semantic are kept as they are
+	for _, raw := range []string{"3.0.0", "latest"} {
+		response = doRequest(t, router, "POST", "/api/v1/services/1/versions", "admin-token", domain.VersionInput{Version: raw})
+		require.Equal(t, http.StatusCreated, response.Code, response.Body.String())
+		var version domain.ServiceVersion
+		require.NoError(t, json.Unmarshal(response.Body.Bytes(), &version))
+		assert.Equal(t, raw, version.Version)
+		assert.Empty(t, version.OriginalVersion)
+	}
+
+	// Versions are sorted, deployed and found whatever their convention
+	response = doRequest(t, router, "GET", "/api/v1/services/1/versions?sort_dir=desc", "viewer-token", nil)
+	require.Equal(t, http.StatusOK, response.Code, response.Body.String())
+	var versions domain.VersionListResponse
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &versions))
+	names := []string{}
+	for _, version := range versions.Versions {
+		names = append(names, version.Version)
+	}
+	assert.Equal(t, []string{"3.0.0", "2.1.0", "2.0.0", "1.1.0", "1.0.0", "latest"}, names)
+
+	response = doRequest(t, router, "PUT", "/api/v1/services/1/environments/prod", "admin-token", domain.DeploymentInput{Version: "v2.1.0"})
+	require.Equal(t, http.StatusOK, response.Code, response.Body.String())
+	assert.Equal(t, []string{"Collect Monday", "Locate Us", "Priority Services"}, searchNames(t, router, "v2.1"))
+}
+
+func TestMigrateNormalizesVersions(t *testing.T) {
+	db := testsupport.NewEmptyDB(t)
+
+	// Versions written by an older release
+	_, err := db.Exec("ALTER TABLE service_versions DROP COLUMN original_version")
+	require.NoError(t, err)
+	_, err = db.Exec("INSERT INTO services (name, description) VALUES ('Billing', '')")
+	require.NoError(t, err)
+	_, err = db.Exec("INSERT INTO service_versions (service_id, version) VALUES (1, 'v1.2'), (1, '1.2'), (1, 'v2'), (1, 'latest')")
+	require.NoError(t, err)
+
+	require.NoError(t, database.Migrate(db))
+	rows, err := db.Query("SELECT version, COALESCE(original_version, '') FROM service_versions ORDER BY id")
+	require.NoError(t, err)
+	defer rows.Close()
+	var versions [][2]string
+	for rows.Next() {
+		var version, original string
+		require.NoError(t, rows.Scan(&version, &original))
+		versions = append(versions, [2]string{version, original})
+	}
+	require.NoError(t, rows.Err())
+	// The second version with the canonical form of the first is left as is
+	assert.Equal(t, [][2]string{{"1.2.0", "v1.2"}, {"1.2", ""}, {"2.0.0", "v2"}, {"latest", ""}}, versions)
+}
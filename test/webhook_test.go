@@ -0,0 +1,166 @@
+package integration
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"com.kong.connect/domain"
+	"com.kong.connect/handler"
+	"com.kong.connect/middleware"
+	"com.kong.connect/repository"
+	"com.kong.connect/service"
+	"com.kong.connect/testsupport"
+	"com.kong.connect/webhook"
+)
+
+func TestWebhooksReceiveSignedChanges(t *testing.T) {
+	const secret = "receiver-secret-0123"
+	received := make(chan domain.ChangeEvent, 10)
+	var failures atomic.Int32
+	failures.Store(1)
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		mac := hmac.New(sha256.New, []byte(secret))
+		mac.Write(body)
+		assert.Equal(t, "sha256="+hex.EncodeToString(mac.Sum(nil)), r.Header.Get(webhook.SignatureHeader))
+		if failures.Add(-1) >= 0 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		var event domain.ChangeEvent
+		assert.NoError(t, json.Unmarshal(body, &event))
+		assert.Equal(t, event.Type, r.Header.Get(webhook.EventHeader))
+		received <- event
+	}))
+	defer receiver.Close()
+
+	db := testsupport.NewDB(t)
+	repo := repository.NewServiceRepository(db)
+	dispatcher := webhook.NewDispatcher(repo, 3, nil, nil)
+	svc := service.NewServiceService(repo, service.WithPublisher(dispatcher))
+	router := handler.SetupRouter(handler.NewServiceHandler(svc))
+	middleware.SetOrgLookup(handler.OrgLookup(svc))
+	defer middleware.SetOrgLookup(nil)
+
+	response := doRequest(t, router, "POST", "/api/v1/webhooks", "admin-token", domain.WebhookInput{
+		URL:    receiver.URL,
+		Events: []string{"version.created", " Service.Updated", "service.updated"},
+		Secret: secret,
+	})
+	require.Equal(t, http.StatusCreated, response.Code, response.Body.String())
+	var created domain.CreatedWebhook
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &created))
+	assert.Equal(t, []string{"service.updated", "version.created"}, created.Events)
+	assert.Equal(t, secret, created.Secret)
+	id := strconv.Itoa(created.ID)
+
+	for _, input := range []domain.WebhookInput{
+		{URL: "ftp://example.com/hook"},
+		{URL: "/hook"},
+		{URL: receiver.URL, Events: []string{"service.renamed"}},
+		{URL: receiver.URL, Secret: "short"},
+	} {
+		response = doRequest(t, router, "POST", "/api/v1/webhooks", "admin-token", input)
+		assert.Equal(t, http.StatusBadRequest, response.Code, input)
+	}
+	response = doRequest(t, router, "GET", "/api/v1/webhooks", "viewer-token", nil)
+	assert.Equal(t, http.StatusForbidden, response.Code)
+
+	// Secrets are only returned when a webhook is created
+	response = doRequest(t, router, "GET", "/api/v1/webhooks", "admin-token", nil)
+	require.Equal(t, http.StatusOK, response.Code)
+	assert.NotContains(t, response.Body.String(), secret)
+	var listing domain.WebhookListResponse
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &listing))
+	require.Len(t, listing.Webhooks, 1)
+	assert.Equal(t, created.Webhook, listing.Webhooks[0])
+
+	deliveries := func() []domain.WebhookDelivery {
+		response := doRequest(t, router, "GET", "/api/v1/webhooks/"+id+"/deliveries", "admin-token", nil)
+		require.Equal(t, http.StatusOK, response.Code, response.Body.String())
+		var listing domain.WebhookDeliveryListResponse
+		require.NoError(t, json.Unmarshal(response.Body.Bytes(), &listing))
+		return listing.Deliveries
+	}
+
+	// The first attempt is refused, and retried once due
+	response = doRequest(t, router, "PUT", "/api/v1/services/1", "admin-token", domain.ServiceInput{Name: "Locate Us", Description: "Find branches"})
+	require.Equal(t, http.StatusOK, response.Code, response.Body.String())
+	require.Eventually(t, func() bool {
+		logged := deliveries()
+		return len(logged) == 1 && logged[0].Attempts == 1
+	}, 5*time.Second, 10*time.Millisecond)
+	failed := deliveries()[0]
+	assert.Equal(t, domain.DeliveryPending, failed.Status)
+	assert.Equal(t, http.StatusBadGateway, failed.ResponseCode)
+	assert.Contains(t, failed.Error, "502")
+	require.NotNil(t, failed.NextAttemptAt)
+
+	require.NoError(t, dispatcher.Retry(t.Context()))
+	assert.Empty(t, received, "not due yet")
+	_, err := db.Exec("UPDATE webhook_deliveries SET next_attempt_at = 0")
+	require.NoError(t, err)
+	require.NoError(t, dispatcher.Retry(t.Context()))
+	select {
+	case event := <-received:
+		assert.Equal(t, domain.EventServiceUpdated, event.Type)
+		assert.Equal(t, 1, event.ServiceID)
+		assert.Equal(t, "Find branches", event.Service.Description)
+	case <-time.After(5 * time.Second):
+		t.Fatal("no delivery")
+	}
+	delivered := deliveries()[0]
+	assert.Equal(t, domain.DeliveryDelivered, delivered.Status)
+	assert.Equal(t, 2, delivered.Attempts)
+	assert.Empty(t, delivered.Error)
+	assert.NotNil(t, delivered.DeliveredAt)
+	assert.Nil(t, delivered.NextAttemptAt)
+
+	// Deletions are not among the filtered events
+	response = doRequest(t, router, "DELETE", "/api/v1/services/2", "admin-token", nil)
+	require.Equal(t, http.StatusNoContent, response.Code, response.Body.String())
+	response = doRequest(t, router, "POST", "/api/v1/services/1/versions", "admin-token", domain.VersionInput{Version: "3.0.0"})
+	require.Equal(t, http.StatusCreated, response.Code, response.Body.String())
+	select {
+	case event := <-received:
+		assert.Equal(t, domain.EventVersionCreated, event.Type)
+	case <-time.After(5 * time.Second):
+		t.Fatal("no delivery")
+	}
+
+	// Webhooks belong to the organization they were created in
+	response = doRequest(t, router, "POST", "/api/v1/organizations", "admin-token", domain.OrganizationInput{Slug: "payments"})
+	require.Equal(t, http.StatusCreated, response.Code, response.Body.String())
+	response = doOrgRequest(t, router, "GET", "/api/v1/webhooks", "admin-token", "payments", nil)
+	require.Equal(t, http.StatusOK, response.Code)
+	assert.JSONEq(t, `{"webhooks": []}`, response.Body.String())
+	for _, method := range []string{"GET", "DELETE"} {
+		path := "/api/v1/webhooks/" + id
+		if method == "GET" {
+			path += "/deliveries"
+		}
+		response = doOrgRequest(t, router, method, path, "admin-token", "payments", nil)
+		assert.Equal(t, http.StatusNotFound, response.Code, method)
+	}
+
+	response = doRequest(t, router, "DELETE", "/api/v1/webhooks/"+id, "admin-token", nil)
+	require.Equal(t, http.StatusNoContent, response.Code)
+	response = doRequest(t, router, "GET", "/api/v1/webhooks/"+id+"/deliveries", "admin-token", nil)
+	assert.Equal(t, http.StatusNotFound, response.Code)
+	var logged int
+	require.NoError(t, db.QueryRow("SELECT COUNT(*) FROM webhook_deliveries").Scan(&logged))
+	assert.Zero(t, logged)
+}
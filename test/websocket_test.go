@@ -0,0 +1,110 @@
+package integration
+
+import (
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"com.kong.connect/domain"
+	"com.kong.connect/events"
+	"com.kong.connect/handler"
+	"com.kong.connect/realtime"
+	"com.kong.connect/repository"
+	"com.kong.connect/service"
+	"com.kong.connect/tenant"
+	"com.kong.connect/testsupport"
+)
+
+func TestWebSocketReceivesSubscribedChanges(t *testing.T) {
+	db := testsupport.NewDB(t)
+
+	bus := events.NewBus()
+	hub := realtime.NewHub(nil)
+	bus.Subscribe(hub.Publish)
+
+	repo := repository.NewServiceRepository(db)
+	serviceSvc := service.NewServiceService(repo, service.WithPublisher(bus))
+	router := handler.SetupRouter(handler.NewServiceHandler(serviceSvc), handler.WithWebSocket(hub))
+
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http")+"/ws", nil)
+	require.NoError(t, err)
+	defer conn.Close()
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+
+	// Authenticate with the first frame
+	require.NoError(t, conn.WriteJSON(realtime.Message{Type: realtime.MessageAuth, Token: "viewer-token"}))
+	var msg realtime.Message
+	require.NoError(t, conn.ReadJSON(&msg))
+	require.Equal(t, realtime.MessageAck, msg.Type)
+
+	require.NoError(t, conn.WriteJSON(realtime.Message{Type: realtime.MessageSubscribe, Tags: []string{"payments"}}))
+	require.NoError(t, conn.ReadJSON(&msg))
+	require.Equal(t, realtime.MessageAck, msg.Type)
+	assert.Equal(t, []string{"payments"}, msg.Tags)
+
+	// Changes outside the subscription or the organization are not delivered
+	_, err = serviceSvc.CreateService(context.Background(), domain.ServiceInput{Name: "Search", Tags: []string{"internal"}})
+	require.NoError(t, err)
+	organization, err := serviceSvc.CreateOrganization(context.Background(), domain.OrganizationInput{Slug: "payments"})
+	require.NoError(t, err)
+	_, err = serviceSvc.CreateService(tenant.NewContext(context.Background(), organization.ID), domain.ServiceInput{Name: "Ledger", Tags: []string{"payments"}})
+	require.NoError(t, err)
+	created, err := serviceSvc.CreateService(context.Background(), domain.ServiceInput{Name: "Billing", Tags: []string{"payments"}})
+	require.NoError(t, err)
+
+	require.NoError(t, conn.ReadJSON(&msg))
+	require.Equal(t, realtime.MessageEvent, msg.Type)
+	assert.Equal(t, domain.EventServiceCreated, msg.Event.Type)
+	assert.Equal(t, created.ID, msg.Event.ServiceID)
+}
+
+func TestWebSocketRejectsInvalidToken(t *testing.T) {
+	hub := realtime.NewHub(nil)
+	server := httptest.NewServer(handler.SetupRouter(handler.NewServiceHandler(nil), handler.WithWebSocket(hub)))
+	defer server.Close()
+
+	header := map[string][]string{"Authorization": {"Bearer bogus"}}
+	_, response, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http")+"/ws", header)
+	require.Error(t, err)
+	assert.Equal(t, 401, response.StatusCode)
+}
+
+func TestWebSocketClosedOnHubShutdown(t *testing.T) {
+	hub := realtime.NewHub(nil)
+	server := httptest.NewServer(handler.SetupRouter(handler.NewServiceHandler(nil), handler.WithWebSocket(hub)))
+	defer server.Close()
+
+	header := map[string][]string{"Authorization": {"Bearer viewer-token"}}
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http")+"/ws", header)
+	require.NoError(t, err)
+	defer conn.Close()
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+
+	var msg realtime.Message
+	require.NoError(t, conn.ReadJSON(&msg))
+	require.Equal(t, realtime.MessageAck, msg.Type)
+	require.Eventually(t, func() bool { return hub.ClientCount() == 1 }, time.Second, 10*time.Millisecond)
+
+	hub.Close()
+
+	_, _, err = conn.ReadMessage()
+	assert.True(t, websocket.IsCloseError(err, websocket.CloseGoingAway), "unexpected error: %v", err)
+
+	// New connections are turned away once the hub is closed
+	conn, _, err = websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http")+"/ws", header)
+	require.NoError(t, err)
+	defer conn.Close()
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+
+	_, _, err = conn.ReadMessage()
+	assert.True(t, websocket.IsCloseError(err, websocket.CloseGoingAway), "unexpected error: %v", err)
+}
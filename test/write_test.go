@@ -0,0 +1,183 @@
+package integration
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"com.kong.connect/domain"
+	"com.kong.connect/handler"
+	"com.kong.connect/problem"
+	"com.kong.connect/repository"
+	"com.kong.connect/service"
+	"com.kong.connect/testsupport"
+)
+
+func TestServiceLifecycle(t *testing.T) {
+	router := newTestRouter(t)
+
+	// Create
+	input := domain.ServiceInput{Name: "Billing", Description: "Invoices", Owner: "payments-team", Tags: []string{"Payments", "payments"}}
+	response := doRequest(t, router, "POST", "/api/v1/services", "admin-token", input)
+	require.Equal(t, http.StatusCreated, response.Code, response.Body.String())
+
+	var created domain.ServiceWithVersions
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &created))
+	assert.Equal(t, "Billing", created.Name)
+	assert.Equal(t, domain.StatusActive, created.Status)
+	assert.Equal(t, []string{"payments"}, created.Tags)
+
+	// Duplicate names conflict
+	response = doRequest(t, router, "POST", "/api/v1/services", "admin-token", input)
+	assert.Equal(t, http.StatusConflict, response.Code)
+
+	// Update
+	input.Status = domain.StatusDeprecated
+	response = doRequest(t, router, "PUT", "/api/v1/services/"+itoa(created.ID), "admin-token", input)
+	require.Equal(t, http.StatusOK, response.Code, response.Body.String())
+
+	var updated domain.ServiceWithVersions
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &updated))
+	assert.Equal(t, domain.StatusDeprecated, updated.Status)
+
+	// Add and remove a version
+	response = doRequest(t, router, "POST", "/api/v1/services/"+itoa(created.ID)+"/versions", "admin-token", domain.VersionInput{Version: "1.0.0"})
+	require.Equal(t, http.StatusCreated, response.Code, response.Body.String())
+
+	var version domain.ServiceVersion
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &version))
+	assert.Equal(t, created.ID, version.ServiceID)
+
+	response = doRequest(t, router, "POST", "/api/v1/services/"+itoa(created.ID)+"/versions", "admin-token", domain.VersionInput{Version: "1.0.0"})
+	assert.Equal(t, http.StatusConflict, response.Code)
+
+	response = doRequest(t, router, "DELETE", "/api/v1/services/"+itoa(created.ID)+"/versions/"+itoa(version.ID), "admin-token", nil)
+	assert.Equal(t, http.StatusNoContent, response.Code)
+
+	// Delete
+	response = doRequest(t, router, "DELETE", "/api/v1/services/"+itoa(created.ID), "admin-token", nil)
+	assert.Equal(t, http.StatusNoContent, response.Code)
+
+	response = doRequest(t, router, "GET", "/api/v1/services/"+itoa(created.ID), "admin-token", nil)
+	assert.Equal(t, http.StatusNotFound, response.Code)
+}
+
+func TestWriteEndpointsRequireAdmin(t *testing.T) {
+	router := newTestRouter(t)
+
+	response := doRequest(t, router, "POST", "/api/v1/services", "viewer-token", domain.ServiceInput{Name: "Billing"})
+	assert.Equal(t, http.StatusForbidden, response.Code)
+
+	response = doRequest(t, router, "DELETE", "/api/v1/services/1", "viewer-token", nil)
+	assert.Equal(t, http.StatusForbidden, response.Code)
+}
+
+func TestCreateServiceValidation(t *testing.T) {
+	router := newTestRouter(t)
+
+	response := doRequest(t, router, "POST", "/api/v1/services", "admin-token", domain.ServiceInput{Name: "  "})
+	assert.Equal(t, http.StatusBadRequest, response.Code)
+
+	response = doRequest(t, router, "POST", "/api/v1/services", "admin-token", domain.ServiceInput{Name: "Billing", Status: "retired"})
+	assert.Equal(t, http.StatusBadRequest, response.Code)
+}
+
+func TestWriteEndpointsRejectOversizedBodies(t *testing.T) {
+	db := testsupport.NewDB(t)
+
+	repo := repository.NewServiceRepository(db)
+	router := handler.SetupRouter(handler.NewServiceHandler(service.NewServiceService(repo)), handler.WithMaxBodySize(128))
+
+	body := `{"name": "Billing", "description": "` + strings.Repeat("x", 200) + `"}`
+
+	// Rejected upfront by Content-Length
+	req := httptest.NewRequest("POST", "/api/v1/services", strings.NewReader(body))
+	req.Header.Set("Authorization", "Bearer admin-token")
+	response := httptest.NewRecorder()
+	router.ServeHTTP(response, req)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, response.Code)
+	assert.Equal(t, "application/problem+json", response.Header().Get("Content-Type"))
+
+	// Rejected while reading when the length is unknown
+	req = httptest.NewRequest("POST", "/api/v1/services", strings.NewReader(body))
+	req.ContentLength = -1
+	req.Header.Set("Authorization", "Bearer admin-token")
+	response = httptest.NewRecorder()
+	router.ServeHTTP(response, req)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, response.Code)
+
+	response = doRequest(t, router, "POST", "/api/v1/services", "admin-token", domain.ServiceInput{Name: "Billing"})
+	assert.Equal(t, http.StatusCreated, response.Code)
+}
+
+func TestWriteEndpointsRejectUnknownFields(t *testing.T) {
+	router := newTestRouter(t)
+
+	body := json.RawMessage(`{"name": "Billing", "descripton": "Invoices", "colour": "red", "OWNER": "payments-team"}`)
+	response := doRequest(t, router, "POST", "/api/v1/services", "admin-token", body)
+	require.Equal(t, http.StatusBadRequest, response.Code, response.Body.String())
+
+	var details problem.Details
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &details))
+	assert.Equal(t, problem.TypeInvalidBody, details.Type)
+	assert.Equal(t, []problem.InvalidParam{
+		{Name: "colour", Reason: "unknown field"},
+		{Name: "descripton", Reason: `unknown field, did you mean "description"?`},
+	}, details.InvalidParams)
+
+	response = doRequest(t, router, "GET", "/api/v1/services?search=billing", "viewer-token", nil)
+	assert.Contains(t, response.Body.String(), `"total":0`, "nothing is created")
+
+	response = doRequest(t, router, "POST", "/api/v1/services/1/versions", "admin-token", json.RawMessage(`{"version": 2}`))
+	require.Equal(t, http.StatusBadRequest, response.Code)
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &details))
+	assert.Equal(t, []problem.InvalidParam{{Name: "version", Reason: "must be a string"}}, details.InvalidParams)
+
+	response = doRequest(t, router, "POST", "/api/v1/services", "admin-token", json.RawMessage(`{"name": "Billing", "Description": "Invoices"}`))
+	assert.Equal(t, http.StatusCreated, response.Code, "fields match regardless of case")
+}
+
+func TestWritesAreRetriedWhileTheDatabaseIsBusy(t *testing.T) {
+	// Without a busy timeout, writes fail at once while another connection
+	// holds the lock, instead of waiting for it
+	db := testsupport.InitTestDB(t, "_busy_timeout=0")
+	repo := repository.NewServiceRepository(db)
+
+	ctx := context.Background()
+	lock := func(t *testing.T) *sql.Conn {
+		conn, err := db.Conn(ctx)
+		require.NoError(t, err)
+		_, err = conn.ExecContext(ctx, "BEGIN IMMEDIATE")
+		require.NoError(t, err)
+		return conn
+	}
+
+	// Succeeds once the lock is released
+	conn := lock(t)
+	go func() {
+		time.Sleep(30 * time.Millisecond)
+		conn.ExecContext(ctx, "COMMIT")
+		conn.Close()
+	}()
+	id, err := repo.Create(ctx, domain.ServiceInput{Name: "Billing", Status: domain.StatusActive})
+	require.NoError(t, err)
+	_, err = repo.CreateVersion(ctx, id, domain.VersionInput{Version: "1.0.0"})
+	require.NoError(t, err)
+
+	// Gives up while the lock is held for longer
+	conn = lock(t)
+	defer conn.Close()
+	_, err = repo.Create(ctx, domain.ServiceInput{Name: "Invoicing", Status: domain.StatusActive})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "database is locked")
+	_, err = conn.ExecContext(ctx, "ROLLBACK")
+	require.NoError(t, err)
+}
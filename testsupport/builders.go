@@ -0,0 +1,231 @@
+// Package testsupport builds the fixtures of tests: services, versions and
+// queries with sensible defaults, and seeded databases in temporary
+// directories
+package testsupport
+
+import (
+	"time"
+
+	"com.kong.connect/domain"
+)
+
+// ServiceBuilder builds a service with its versions. Services are active,
+// created and updated at the time of building, unless set otherwise.
+type ServiceBuilder struct {
+	service       domain.ServiceWithVersions
+	firstVersion  int
+	versions      []string
+	at            time.Time
+	hasTimestamps bool
+}
+
+// Service starts building the service with the given ID and name
+func Service(id int, name string) *ServiceBuilder {
+	return &ServiceBuilder{service: domain.ServiceWithVersions{Service: domain.Service{
+		ID:     id,
+		Name:   name,
+		Status: domain.StatusActive,
+	}}}
+}
+
+// Description sets the description of the service
+func (b *ServiceBuilder) Description(description string) *ServiceBuilder {
+	b.service.Description = description
+	return b
+}
+
+// Status sets the status of the service
+func (b *ServiceBuilder) Status(status string) *ServiceBuilder {
+	b.service.Status = status
+	return b
+}
+
+// Owner sets the owner of the service
+func (b *ServiceBuilder) Owner(owner string) *ServiceBuilder {
+	b.service.Owner = owner
+	return b
+}
+
+// Tags sets the tags of the service
+func (b *ServiceBuilder) Tags(tags ...string) *ServiceBuilder {
+	b.service.Tags = tags
+	return b
+}
+
+// At sets when the service and its versions were created and last updated
+func (b *ServiceBuilder) At(at time.Time) *ServiceBuilder {
+	b.at = at
+	b.hasTimestamps = true
+	return b
+}
+
+// Versions adds versions to the service, numbered from firstID on
+func (b *ServiceBuilder) Versions(firstID int, versions ...string) *ServiceBuilder {
+	b.firstVersion = firstID
+	b.versions = versions
+	return b
+}
+
+// Build returns the service. Every call returns a new copy.
+func (b *ServiceBuilder) Build() domain.ServiceWithVersions {
+	at := b.at
+	if !b.hasTimestamps {
+		at = time.Now()
+	}
+	service := b.service
+	service.Tags = append([]string(nil), b.service.Tags...)
+	service.CreatedAt, service.UpdatedAt = at, at
+	service.Versions = nil
+	for i, version := range b.versions {
+		service.Versions = append(service.Versions, Version(b.firstVersion+i, service.ID, version).At(at).Build())
+	}
+	return service
+}
+
+// VersionBuilder builds a version of a service, created at the time of
+// building unless set otherwise
+type VersionBuilder struct {
+	version       domain.ServiceVersion
+	hasTimestamps bool
+}
+
+// Version starts building the version with the given ID of a service
+func Version(id, serviceID int, version string) *VersionBuilder {
+	return &VersionBuilder{version: domain.ServiceVersion{ID: id, ServiceID: serviceID, Version: version}}
+}
+
+// Original sets the version as it was submitted, before canonicalization
+func (b *VersionBuilder) Original(original string) *VersionBuilder {
+	b.version.OriginalVersion = original
+	return b
+}
+
+// Environments sets the environments the version is deployed to
+func (b *VersionBuilder) Environments(environments ...string) *VersionBuilder {
+	b.version.Environments = environments
+	return b
+}
+
+// At sets when the version was created
+func (b *VersionBuilder) At(at time.Time) *VersionBuilder {
+	b.version.CreatedAt = at
+	b.hasTimestamps = true
+	return b
+}
+
+// Build returns the version
+func (b *VersionBuilder) Build() domain.ServiceVersion {
+	version := b.version
+	if !b.hasTimestamps {
+		version.CreatedAt = time.Now()
+	}
+	version.Environments = append([]string(nil), b.version.Environments...)
+	if len(version.Environments) == 0 {
+		version.Environments = nil
+	}
+	return version
+}
+
+// QueryBuilder builds a query of the service listing, of the first page of
+// DefaultPageSize services unless set otherwise
+type QueryBuilder struct {
+	query domain.ServiceQuery
+}
+
+// DefaultPageSize is the page size of built queries
+const DefaultPageSize = 10
+
+// Query starts building a query of the service listing
+func Query() *QueryBuilder {
+	return &QueryBuilder{query: domain.ServiceQuery{Page: 1, PageSize: DefaultPageSize}}
+}
+
+// Search sets the search text of the query
+func (b *QueryBuilder) Search(search string) *QueryBuilder {
+	b.query.Search = search
+	return b
+}
+
+// Environment limits the query to the services deployed to environment
+func (b *QueryBuilder) Environment(environment string) *QueryBuilder {
+	b.query.Environment = environment
+	return b
+}
+
+// HasVersions limits the query to services with versions, or without any
+func (b *QueryBuilder) HasVersions(hasVersions bool) *QueryBuilder {
+	b.query.HasVersions = &hasVersions
+	return b
+}
+
+// MinVersions limits the query to services with at least that many versions
+func (b *QueryBuilder) MinVersions(minVersions int) *QueryBuilder {
+	b.query.MinVersions = minVersions
+	return b
+}
+
+// Sort sets the field and direction the query sorts by
+func (b *QueryBuilder) Sort(by, dir string) *QueryBuilder {
+	b.query.SortBy, b.query.SortDir = by, dir
+	return b
+}
+
+// Page sets the page and page size of the query
+func (b *QueryBuilder) Page(page, pageSize int) *QueryBuilder {
+	b.query.Page, b.query.PageSize = page, pageSize
+	return b
+}
+
+// Favorites limits the query to the services user starred
+func (b *QueryBuilder) Favorites(user string) *QueryBuilder {
+	b.query.Favorites, b.query.User = true, user
+	return b
+}
+
+// Build returns the query
+func (b *QueryBuilder) Build() domain.ServiceQuery {
+	return b.query
+}
+
+// sampleDescription is the description of the sample services
+const sampleDescription = "Lorem ipsum dolor sit amet, consectetur adipiscing elit. Turpis non a, pellentesque ipsum aliquet id..."
+
+// SampleServices returns eight services with three versions each, the
+// services numbered from 1 and their versions from 1 across services
+func SampleServices() []domain.ServiceWithVersions {
+	at := time.Now()
+	samples := []struct {
+		name     string
+		versions []string
+	}{
+		{"Locate Us", []string{"1.0.0", "1.1.0", "2.0.0"}},
+		{"Collect Monday", []string{"1.0.0", "1.2.0", "2.1.0"}},
+		{"Contact Us", []string{"1.0.0", "1.1.0", "1.2.0"}},
+		{"FX Rates International", []string{"1.0.0", "2.0.0", "3.0.0"}},
+		{"Notifications", []string{"1.0.0", "1.1.0", "1.2.0"}},
+		{"Priority Services", []string{"1.0.0", "2.0.0", "2.1.0"}},
+		{"Reporting", []string{"1.0.0", "1.1.0", "2.0.0"}},
+		{"Security", []string{"1.0.0", "1.1.0", "1.2.0"}},
+	}
+	services := make([]domain.ServiceWithVersions, 0, len(samples))
+	versionID := 1
+	for i, sample := range samples {
+		services = append(services, Service(i+1, sample.name).
+			Description(sampleDescription).
+			Versions(versionID, sample.versions...).
+			At(at).
+			Build())
+		versionID += len(sample.versions)
+	}
+	return services
+}
+
+// SampleService returns the sample service with the given ID, or nil
+func SampleService(id int) *domain.ServiceWithVersions {
+	for _, service := range SampleServices() {
+		if service.ID == id {
+			return &service
+		}
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package testsupport
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"com.kong.connect/domain"
+)
+
+func TestServiceBuilder(t *testing.T) {
+	at := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	builder := Service(7, "Billing").Owner("payments-team").Tags("payments").Versions(40, "1.0.0", "1.1.0").At(at)
+
+	service := builder.Build()
+	assert.Equal(t, domain.StatusActive, service.Status)
+	assert.Equal(t, at, service.CreatedAt)
+	require.Len(t, service.Versions, 2)
+	assert.Equal(t, domain.ServiceVersion{ID: 41, ServiceID: 7, Version: "1.1.0", CreatedAt: at}, service.Versions[1])
+
+	// Builds are independent of each other
+	service.Tags[0] = "changed"
+	service.Versions[0].Version = "changed"
+	again := builder.Build()
+	assert.Equal(t, []string{"payments"}, again.Tags)
+	assert.Equal(t, "1.0.0", again.Versions[0].Version)
+}
+
+func TestSampleServices(t *testing.T) {
+	services := SampleServices()
+	require.Len(t, services, 8)
+	for i, service := range services {
+		assert.Equal(t, i+1, service.ID)
+		for j, version := range service.Versions {
+			assert.Equal(t, 3*i+j+1, version.ID)
+			assert.Equal(t, service.ID, version.ServiceID)
+		}
+	}
+	assert.Equal(t, "Contact Us", SampleService(3).Name)
+	assert.Nil(t, SampleService(9))
+}
+
+func TestQueryBuilder(t *testing.T) {
+	assert.Equal(t, domain.ServiceQuery{Page: 1, PageSize: DefaultPageSize}, Query().Build())
+	assert.Equal(t, domain.ServiceQuery{Search: "pay", SortBy: "name", SortDir: "desc", Page: 2, PageSize: 5, Favorites: true, User: "alice"},
+		Query().Search("pay").Sort("name", "desc").Page(2, 5).Favorites("alice").Build())
+}
+
+func TestNewDB(t *testing.T) {
+	var count int
+	require.NoError(t, NewDB(t).QueryRow("SELECT COUNT(*) FROM services").Scan(&count))
+	assert.Positive(t, count)
+	require.NoError(t, NewEmptyDB(t).QueryRow("SELECT COUNT(*) FROM services").Scan(&count))
+	assert.Zero(t, count)
+}
@@ -0,0 +1,58 @@
+package testsupport
+
+import (
+	"database/sql"
+	"strings"
+	"testing"
+
+	"com.kong.connect/database"
+	"com.kong.connect/repository"
+)
+
+// InitTestDB returns an in-memory database of the test's own, initialized as
+// database.InitDB does and closed when the test ends. Options such as
+// _busy_timeout=0 are passed to the driver.
+func InitTestDB(t testing.TB, options ...string) *sql.DB {
+	t.Helper()
+	db, err := database.InitDB(memoryPath(options))
+	if err != nil {
+		t.Fatalf("failed to initialize test database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+// NewDB returns a database seeded with the sample catalog, as InitTestDB
+func NewDB(t testing.TB) *sql.DB {
+	t.Helper()
+	return InitTestDB(t)
+}
+
+// NewEmptyDB returns an in-memory database with the tables of the catalog
+// but no rows, closed when the test ends
+func NewEmptyDB(t testing.TB) *sql.DB {
+	t.Helper()
+	db, err := database.Open(database.MemoryPath)
+	if err != nil {
+		t.Fatalf("failed to open test database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	if err := database.Migrate(db); err != nil {
+		t.Fatalf("failed to migrate test database: %v", err)
+	}
+	return db
+}
+
+// NewRepository returns a repository over a database seeded with the sample
+// catalog, as NewDB
+func NewRepository(t testing.TB, opts ...repository.Option) *repository.ServiceRepository {
+	t.Helper()
+	return repository.NewServiceRepository(NewDB(t), opts...)
+}
+
+func memoryPath(options []string) string {
+	if len(options) == 0 {
+		return database.MemoryPath
+	}
+	return database.MemoryPath + "?" + strings.Join(options, "&")
+}
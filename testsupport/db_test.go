@@ -0,0 +1,35 @@
+package testsupport
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInitTestDBIsolatesTests(t *testing.T) {
+	ctx := context.Background()
+	db := InitTestDB(t)
+	_, err := db.ExecContext(ctx, "DELETE FROM services WHERE id = 1")
+	require.NoError(t, err)
+
+	// The connections of the pool share the database
+	first, err := db.Conn(ctx)
+	require.NoError(t, err)
+	defer first.Close()
+	second, err := db.Conn(ctx)
+	require.NoError(t, err)
+	defer second.Close()
+	_, err = first.ExecContext(ctx, "INSERT INTO organizations (slug, name) VALUES ('acme', 'Acme')")
+	require.NoError(t, err)
+	var count int
+	require.NoError(t, second.QueryRowContext(ctx, "SELECT COUNT(*) FROM organizations WHERE slug = 'acme'").Scan(&count))
+	assert.Equal(t, 1, count)
+
+	// Other databases are not
+	require.NoError(t, NewDB(t).QueryRowContext(ctx, "SELECT COUNT(*) FROM services WHERE id = 1").Scan(&count))
+	assert.Equal(t, 1, count)
+	require.NoError(t, NewEmptyDB(t).QueryRowContext(ctx, "SELECT COUNT(*) FROM organizations WHERE slug = 'acme'").Scan(&count))
+	assert.Equal(t, 0, count)
+}
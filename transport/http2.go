@@ -0,0 +1,21 @@
+package transport
+
+import "net/http"
+
+// HTTP2Config selects the HTTP/2 variants the server accepts next to HTTP/1.1
+type HTTP2Config struct {
+	// Disabled turns HTTP/2 over TLS off, which net/http otherwise negotiates by default
+	Disabled bool
+	// Cleartext accepts h2c with prior knowledge on plain connections, as
+	// used by gRPC-gateway and other clients behind a TLS terminating proxy
+	Cleartext bool
+}
+
+// Apply sets the protocols srv accepts
+func (c HTTP2Config) Apply(srv *http.Server) {
+	protocols := new(http.Protocols)
+	protocols.SetHTTP1(true)
+	protocols.SetHTTP2(!c.Disabled)
+	protocols.SetUnencryptedHTTP2(!c.Disabled && c.Cleartext)
+	srv.Protocols = protocols
+}
@@ -0,0 +1,58 @@
+package transport
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTP2Cleartext(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(r.Proto))
+	})
+
+	tests := []struct {
+		name   string
+		config HTTP2Config
+		proto  string
+	}{
+		{"h2c enabled", HTTP2Config{Cleartext: true}, "HTTP/2.0"},
+		{"h2c disabled", HTTP2Config{}, "HTTP/1.1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewUnstartedServer(handler)
+			tt.config.Apply(srv.Config)
+			srv.Start()
+			defer srv.Close()
+
+			// The client only speaks h2c with prior knowledge, falling back to HTTP/1.1
+			protocols := new(http.Protocols)
+			protocols.SetUnencryptedHTTP2(tt.config.Cleartext)
+			protocols.SetHTTP1(!tt.config.Cleartext)
+			client := &http.Client{Transport: &http.Transport{Protocols: protocols}}
+
+			response, err := client.Get(srv.URL)
+			require.NoError(t, err)
+			defer response.Body.Close()
+			assert.Equal(t, tt.proto, response.Proto)
+		})
+	}
+}
+
+func TestHTTP2OverTLS(t *testing.T) {
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	srv.EnableHTTP2 = true
+	HTTP2Config{}.Apply(srv.Config)
+	srv.StartTLS()
+	defer srv.Close()
+
+	response, err := srv.Client().Get(srv.URL)
+	require.NoError(t, err)
+	defer response.Body.Close()
+	assert.Equal(t, 2, response.ProtoMajor)
+}
@@ -0,0 +1,85 @@
+package transport
+
+import (
+	"crypto/tls"
+	"errors"
+	"net"
+	"net/http"
+
+	"golang.org/x/crypto/acme/autocert"
+)
+
+// TLSConfig describes how the server terminates TLS. Certificates come either
+// from files or from an ACME CA such as Let's Encrypt via autocert.
+type TLSConfig struct {
+	CertFile string
+	KeyFile  string
+
+	AutocertDomains  []string
+	AutocertCacheDir string
+	AutocertEmail    string
+
+	// RedirectAddr is the address of the plain HTTP listener that redirects
+	// to HTTPS and answers ACME HTTP-01 challenges; empty disables it
+	RedirectAddr string
+}
+
+// Enabled reports whether the server should serve HTTPS
+func (c TLSConfig) Enabled() bool {
+	return c.CertFile != "" || c.KeyFile != "" || len(c.AutocertDomains) > 0
+}
+
+// Validate checks that exactly one certificate source is configured
+func (c TLSConfig) Validate() error {
+	if (c.CertFile == "") != (c.KeyFile == "") {
+		return errors.New("TLS_CERT_FILE and TLS_KEY_FILE must be set together")
+	}
+	if c.CertFile != "" && len(c.AutocertDomains) > 0 {
+		return errors.New("certificate files and autocert domains are mutually exclusive")
+	}
+	return nil
+}
+
+// Apply configures TLS on srv and returns the handler for the plain HTTP
+// listener: a redirect to HTTPS, wrapped to answer ACME challenges when
+// certificates are obtained automatically
+func (c TLSConfig) Apply(srv *http.Server) (http.Handler, error) {
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+
+	srv.TLSConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+	redirect := RedirectHandler(srv.Addr)
+	if len(c.AutocertDomains) == 0 {
+		return redirect, nil
+	}
+
+	manager := &autocert.Manager{
+		Prompt:     autocert.AcceptTOS,
+		HostPolicy: autocert.HostWhitelist(c.AutocertDomains...),
+		Cache:      autocert.DirCache(c.AutocertCacheDir),
+		Email:      c.AutocertEmail,
+	}
+	srv.TLSConfig.GetCertificate = manager.GetCertificate
+	srv.TLSConfig.NextProtos = append(srv.TLSConfig.NextProtos, "h2", "http/1.1", "acme-tls/1")
+	return manager.HTTPHandler(redirect), nil
+}
+
+// RedirectHandler permanently redirects requests to the same host and path
+// over HTTPS on the port of httpsAddr
+func RedirectHandler(httpsAddr string) http.Handler {
+	_, port, _ := net.SplitHostPort(httpsAddr)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host := r.Host
+		if h, _, err := net.SplitHostPort(host); err == nil {
+			host = h
+		}
+		if port != "" && port != "443" {
+			host = net.JoinHostPort(host, port)
+		}
+
+		target := "https://" + host + r.URL.RequestURI()
+		http.Redirect(w, r, target, http.StatusPermanentRedirect)
+	})
+}
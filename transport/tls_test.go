@@ -0,0 +1,47 @@
+package transport
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTLSConfigValidate(t *testing.T) {
+	assert.NoError(t, TLSConfig{CertFile: "cert.pem", KeyFile: "key.pem"}.Validate())
+	assert.NoError(t, TLSConfig{AutocertDomains: []string{"catalog.example.com"}}.Validate())
+	assert.Error(t, TLSConfig{CertFile: "cert.pem"}.Validate())
+	assert.Error(t, TLSConfig{CertFile: "cert.pem", KeyFile: "key.pem", AutocertDomains: []string{"catalog.example.com"}}.Validate())
+}
+
+func TestRedirectHandler(t *testing.T) {
+	tests := []struct {
+		addr, host, want string
+	}{
+		{":443", "catalog.example.com", "https://catalog.example.com/api/v1/services?page=2"},
+		{":8443", "catalog.example.com:8080", "https://catalog.example.com:8443/api/v1/services?page=2"},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest("GET", "http://"+tt.host+"/api/v1/services?page=2", nil)
+		rec := httptest.NewRecorder()
+		RedirectHandler(tt.addr).ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusPermanentRedirect, rec.Code)
+		assert.Equal(t, tt.want, rec.Header().Get("Location"))
+	}
+}
+
+func TestApplyAutocert(t *testing.T) {
+	srv := &http.Server{Addr: ":443"}
+	handler, err := TLSConfig{AutocertDomains: []string{"catalog.example.com"}, AutocertCacheDir: t.TempDir()}.Apply(srv)
+	require.NoError(t, err)
+	require.NotNil(t, srv.TLSConfig.GetCertificate)
+
+	// Non-challenge requests on the HTTP listener still redirect
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("GET", "http://catalog.example.com/health", nil))
+	assert.Equal(t, http.StatusPermanentRedirect, rec.Code)
+}
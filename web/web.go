@@ -0,0 +1,132 @@
+package web
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"embed"
+	"encoding/hex"
+	"io/fs"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+)
+
+// dist holds the frontend build; `npm run build` in the frontend writes it here
+//
+//go:embed all:dist
+var dist embed.FS
+
+// Cache-Control values: fingerprinted assets never change under their name,
+// while index.html must be revalidated to pick up new asset names
+const (
+	cacheImmutable   = "public, max-age=31536000, immutable"
+	cacheRevalidate  = "no-cache"
+	assetsDir        = "assets/"
+	indexFile        = "index.html"
+	notBuiltFallback = `<!doctype html>
+<html lang="en">
+<head><meta charset="utf-8"><title>Kong Connect</title></head>
+<body>
+<h1>Kong Connect</h1>
+<p>The web UI is not part of this build. Build the frontend into web/dist and rebuild the server to include it.</p>
+<p>The API is served under <code>/api/v1</code>.</p>
+</body>
+</html>
+`
+)
+
+// Assets returns the embedded frontend build
+func Assets() fs.FS {
+	assets, err := fs.Sub(dist, "dist")
+	if err != nil {
+		panic(err)
+	}
+	return assets
+}
+
+// Handler serves a single page application: files of the build are served as
+// they are, and every other path without a file extension gets index.html so
+// that the client side router can handle it
+type Handler struct {
+	files map[string]file
+	index file
+}
+
+type file struct {
+	content []byte
+	etag    string
+}
+
+// NewHandler reads every file of assets into memory, as embedded files are
+// anyway, and computes their ETags
+func NewHandler(assets fs.FS) (*Handler, error) {
+	h := &Handler{files: map[string]file{}}
+	err := fs.WalkDir(assets, ".", func(name string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() || strings.HasPrefix(path.Base(name), ".") {
+			return err
+		}
+		content, err := fs.ReadFile(assets, name)
+		if err != nil {
+			return err
+		}
+		h.files[name] = newFile(content)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	index, ok := h.files[indexFile]
+	if !ok {
+		index = newFile([]byte(notBuiltFallback))
+	}
+	h.index = index
+	return h, nil
+}
+
+func newFile(content []byte) file {
+	sum := sha256.Sum256(content)
+	return file{content: content, etag: `"` + hex.EncodeToString(sum[:16]) + `"`}
+}
+
+// ServeHTTP serves the file at the request path, or index.html for client side routes
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	name := strings.TrimPrefix(path.Clean("/"+r.URL.Path), "/")
+
+	f, ok := h.files[name]
+	switch {
+	case ok && name != indexFile:
+		if strings.HasPrefix(name, assetsDir) {
+			w.Header().Set("Cache-Control", cacheImmutable)
+		} else {
+			w.Header().Set("Cache-Control", cacheRevalidate)
+		}
+	case path.Ext(name) != "" && name != indexFile:
+		// A missing script or image must not be answered with the HTML page
+		http.NotFound(w, r)
+		return
+	default:
+		name, f = indexFile, h.index
+		w.Header().Set("Cache-Control", cacheRevalidate)
+	}
+
+	w.Header().Set("ETag", f.etag)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	// ServeContent sets Content-Type from the name and answers If-None-Match with 304
+	http.ServeContent(w, r, name, time.Time{}, bytes.NewReader(f.content))
+}
+
+// reservedPaths, and the paths below them, are served by the API and never
+// fall back to the UI
+var reservedPaths = []string{"/api", "/ws", "/metrics", "/debug", "/health", "/readyz", "/drain"}
+
+// Serves reports whether the UI handles path, i.e. it is not an API path
+func Serves(path string) bool {
+	for _, reserved := range reservedPaths {
+		if path == reserved || strings.HasPrefix(path, reserved+"/") {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,99 @@
+package web
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestHandler(t *testing.T) *Handler {
+	t.Helper()
+	h, err := NewHandler(fstest.MapFS{
+		"index.html":           {Data: []byte("<!doctype html><div id=app></div>")},
+		"favicon.ico":          {Data: []byte("icon")},
+		"assets/app-3f9a1c.js": {Data: []byte("console.log('catalog')")},
+		".gitkeep":             {},
+	})
+	require.NoError(t, err)
+	return h
+}
+
+func get(h http.Handler, path string, header ...string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest("GET", path, nil)
+	for i := 0; i+1 < len(header); i += 2 {
+		req.Header.Set(header[i], header[i+1])
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestHandlerServesAssetsWithCacheHeaders(t *testing.T) {
+	h := newTestHandler(t)
+
+	rec := get(h, "/assets/app-3f9a1c.js")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "console.log('catalog')", rec.Body.String())
+	assert.Contains(t, rec.Header().Get("Content-Type"), "javascript")
+	assert.Equal(t, cacheImmutable, rec.Header().Get("Cache-Control"))
+
+	rec = get(h, "/favicon.ico")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, cacheRevalidate, rec.Header().Get("Cache-Control"))
+
+	// Dot files of the build directory are not served
+	assert.Equal(t, http.StatusNotFound, get(h, "/.gitkeep").Code)
+}
+
+func TestHandlerFallsBackToIndexForClientRoutes(t *testing.T) {
+	h := newTestHandler(t)
+
+	for _, path := range []string{"/", "/index.html", "/services/3", "/services/3/versions/", "/../index.html"} {
+		rec := get(h, path)
+		assert.Equal(t, http.StatusOK, rec.Code, path)
+		assert.Contains(t, rec.Body.String(), "<div id=app>", path)
+		assert.Contains(t, rec.Header().Get("Content-Type"), "text/html", path)
+		assert.Equal(t, cacheRevalidate, rec.Header().Get("Cache-Control"), path)
+	}
+
+	// Missing files are not answered with the page
+	assert.Equal(t, http.StatusNotFound, get(h, "/assets/app-0000.js").Code)
+}
+
+func TestHandlerRevalidatesWithETag(t *testing.T) {
+	h := newTestHandler(t)
+
+	etag := get(h, "/services").Header().Get("ETag")
+	require.NotEmpty(t, etag)
+	assert.Equal(t, http.StatusNotModified, get(h, "/services", "If-None-Match", etag).Code)
+	assert.Equal(t, http.StatusOK, get(h, "/services", "If-None-Match", `"stale"`).Code)
+}
+
+func TestHandlerWithoutBuild(t *testing.T) {
+	h, err := NewHandler(fstest.MapFS{".gitkeep": {}})
+	require.NoError(t, err)
+
+	rec := get(h, "/services")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), "web UI is not part of this build")
+}
+
+func TestServes(t *testing.T) {
+	for path, want := range map[string]bool{
+		"/":                 true,
+		"/services/1":       true,
+		"/healthy-services": true,
+		"/api/v1/services":  false,
+		"/api":              false,
+		"/ws":               false,
+		"/metrics":          false,
+		"/debug/vars":       false,
+		"/health":           false,
+	} {
+		assert.Equal(t, want, Serves(path), path)
+	}
+}
@@ -0,0 +1,205 @@
+// Package webhook delivers catalog change events to the webhooks registered by
+// organizations. Each delivery is logged, POSTed with an HMAC-SHA256
+// signature of its body, and retried with exponential backoff until the
+// endpoint accepts it or the attempts run out.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+
+	"com.kong.connect/domain"
+	"com.kong.connect/logging"
+	"com.kong.connect/tenant"
+)
+
+// Headers of a delivery request. SignatureHeader holds "sha256=" followed by
+// the hex HMAC-SHA256 of the body keyed with the webhook secret.
+const (
+	SignatureHeader = "X-Signature-256"
+	EventHeader     = "X-Webhook-Event"
+	DeliveryHeader  = "X-Webhook-Delivery"
+)
+
+const (
+	// RetryInterval is how often the deliveries due for a retry are attempted
+	RetryInterval = 30 * time.Second
+
+	firstRetryDelay = 30 * time.Second
+	maxRetryDelay   = time.Hour
+	// attemptTimeout bounds a delivery request; it is below firstRetryDelay so
+	// that a first attempt has finished before its retry is due
+	attemptTimeout = 10 * time.Second
+	// storeTimeout bounds logging deliveries and their attempts
+	storeTimeout = 5 * time.Second
+	// retryBatch and retryConcurrency bound the attempts of one retry run
+	retryBatch       = 100
+	retryConcurrency = 8
+	// maxErrorLength truncates the errors kept in the delivery log
+	maxErrorLength = 500
+)
+
+// Store logs deliveries and their attempts
+type Store interface {
+	// QueueWebhookDeliveries logs a pending delivery of an event to every
+	// webhook of the organization of ctx filtering on its type
+	QueueWebhookDeliveries(ctx context.Context, event string, payload []byte, retryAt time.Time) ([]domain.PendingDelivery, error)
+	// DueWebhookDeliveries returns the pending deliveries of every
+	// organization due for an attempt at now
+	DueWebhookDeliveries(ctx context.Context, now time.Time, limit int) ([]domain.PendingDelivery, error)
+	RecordWebhookAttempt(ctx context.Context, delivery domain.WebhookDelivery) error
+}
+
+// Dispatcher delivers every change event published to it to the matching
+// webhooks of the event's organization
+type Dispatcher struct {
+	store       Store
+	maxAttempts int
+	client      *http.Client
+	logger      *slog.Logger
+}
+
+// NewDispatcher creates a dispatcher making up to maxAttempts attempts per
+// delivery; a nil client uses http.DefaultClient
+func NewDispatcher(store Store, maxAttempts int, client *http.Client, logger *slog.Logger) *Dispatcher {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &Dispatcher{store: store, maxAttempts: maxAttempts, client: client, logger: logging.Component(logger, "webhook")}
+}
+
+// Publish logs the deliveries of event and makes their first attempts in the
+// background. Failed attempts are left to Retry.
+func (d *Dispatcher) Publish(event domain.ChangeEvent) {
+	go func() {
+		payload, err := json.Marshal(event)
+		if err != nil {
+			d.logger.Error("failed to encode webhook payload", "event", event.Type, "error", err)
+			return
+		}
+		ctx, cancel := context.WithTimeout(tenant.NewContext(context.Background(), event.OrgID), storeTimeout)
+		defer cancel()
+		deliveries, err := d.store.QueueWebhookDeliveries(ctx, event.Type, payload, time.Now().Add(Backoff(1)))
+		if err != nil {
+			d.logger.Error("failed to queue webhook deliveries", "event", event.Type, "org_id", event.OrgID, "error", err)
+			return
+		}
+		for _, delivery := range deliveries {
+			go d.attempt(context.Background(), delivery)
+		}
+	}()
+}
+
+// Retry attempts the deliveries of every organization due for a retry; it is
+// run by a job every RetryInterval
+func (d *Dispatcher) Retry(ctx context.Context) error {
+	deliveries, err := d.store.DueWebhookDeliveries(ctx, time.Now(), retryBatch)
+	if err != nil {
+		return fmt.Errorf("failed to get due webhook deliveries: %v", err)
+	}
+
+	var group errgroup.Group
+	group.SetLimit(retryConcurrency)
+	for _, delivery := range deliveries {
+		group.Go(func() error {
+			d.attempt(ctx, delivery)
+			return nil
+		})
+	}
+	return group.Wait()
+}
+
+// attempt posts a delivery and logs the outcome, scheduling the next attempt
+// with backoff when it failed and attempts remain
+func (d *Dispatcher) attempt(ctx context.Context, delivery domain.PendingDelivery) {
+	attemptCtx, cancel := context.WithTimeout(ctx, attemptTimeout)
+	code, err := d.post(attemptCtx, delivery)
+	cancel()
+
+	result := delivery.WebhookDelivery
+	result.Attempts++
+	result.ResponseCode = code
+	result.Error = ""
+	result.NextAttemptAt = nil
+	switch {
+	case err == nil:
+		result.Status = domain.DeliveryDelivered
+	case result.Attempts >= d.maxAttempts:
+		result.Status = domain.DeliveryFailed
+		result.Error = truncate(err.Error())
+		d.logger.Warn("webhook delivery failed, giving up", "delivery_id", result.ID, "webhook_id", result.WebhookID, "attempts", result.Attempts, "error", err)
+	default:
+		next := time.Now().Add(Backoff(result.Attempts))
+		result.Status = domain.DeliveryPending
+		result.Error = truncate(err.Error())
+		result.NextAttemptAt = &next
+		d.logger.Info("webhook delivery failed, retrying", "delivery_id", result.ID, "webhook_id", result.WebhookID, "attempts", result.Attempts, "retry_at", next, "error", err)
+	}
+
+	storeCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), storeTimeout)
+	defer cancel()
+	if err := d.store.RecordWebhookAttempt(storeCtx, result); err != nil {
+		d.logger.Error("failed to record webhook attempt", "delivery_id", result.ID, "error", err)
+	}
+}
+
+// post sends a delivery and returns the response status; any status other
+// than 2xx is an error
+func (d *Dispatcher) post(ctx context.Context, delivery domain.PendingDelivery) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "kong-connect-webhooks")
+	req.Header.Set(EventHeader, delivery.Event)
+	req.Header.Set(DeliveryHeader, strconv.Itoa(delivery.ID))
+	req.Header.Set(SignatureHeader, Sign(delivery.Secret, delivery.Payload))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("endpoint answered %s", resp.Status)
+	}
+	return resp.StatusCode, nil
+}
+
+// Sign returns the SignatureHeader value of a delivery body
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Backoff returns the delay before retrying a delivery that failed attempts
+// times: 30 seconds, doubling with every attempt up to an hour
+func Backoff(attempts int) time.Duration {
+	delay := firstRetryDelay
+	for i := 1; i < attempts && delay < maxRetryDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, maxRetryDelay)
+}
+
+func truncate(message string) string {
+	if len(message) > maxErrorLength {
+		return message[:maxErrorLength]
+	}
+	return message
+}
@@ -0,0 +1,137 @@
+package webhook
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"com.kong.connect/domain"
+	"com.kong.connect/tenant"
+)
+
+// memoryStore keeps deliveries in memory, all of them due at once
+type memoryStore struct {
+	mu         sync.Mutex
+	url        string
+	deliveries map[int]domain.PendingDelivery
+	orgs       []int
+	recorded   chan domain.WebhookDelivery
+}
+
+func newMemoryStore(url string) *memoryStore {
+	return &memoryStore{url: url, deliveries: map[int]domain.PendingDelivery{}, recorded: make(chan domain.WebhookDelivery, 10)}
+}
+
+func (s *memoryStore) QueueWebhookDeliveries(ctx context.Context, event string, payload []byte, retryAt time.Time) ([]domain.PendingDelivery, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.orgs = append(s.orgs, tenant.FromContext(ctx))
+	delivery := domain.PendingDelivery{URL: s.url, Secret: "0123456789abcdef"}
+	delivery.ID = len(s.deliveries) + 1
+	delivery.Event = event
+	delivery.Payload = payload
+	delivery.Status = domain.DeliveryPending
+	delivery.NextAttemptAt = &retryAt
+	s.deliveries[delivery.ID] = delivery
+	return []domain.PendingDelivery{delivery}, nil
+}
+
+func (s *memoryStore) DueWebhookDeliveries(ctx context.Context, now time.Time, limit int) ([]domain.PendingDelivery, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var due []domain.PendingDelivery
+	for _, delivery := range s.deliveries {
+		if delivery.Status == domain.DeliveryPending {
+			due = append(due, delivery)
+		}
+	}
+	return due, nil
+}
+
+func (s *memoryStore) RecordWebhookAttempt(ctx context.Context, delivery domain.WebhookDelivery) error {
+	s.mu.Lock()
+	pending := s.deliveries[delivery.ID]
+	pending.WebhookDelivery = delivery
+	s.deliveries[delivery.ID] = pending
+	s.mu.Unlock()
+	s.recorded <- delivery
+	return nil
+}
+
+func (s *memoryStore) nextRecorded(t *testing.T) domain.WebhookDelivery {
+	t.Helper()
+	select {
+	case delivery := <-s.recorded:
+		return delivery
+	case <-time.After(5 * time.Second):
+		t.Fatal("no delivery attempt")
+		return domain.WebhookDelivery{}
+	}
+}
+
+func TestDispatcherSignsDeliveries(t *testing.T) {
+	endpoint := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+		assert.Equal(t, Sign("0123456789abcdef", body), r.Header.Get(SignatureHeader))
+		assert.Equal(t, domain.EventServiceUpdated, r.Header.Get(EventHeader))
+		assert.Equal(t, "1", r.Header.Get(DeliveryHeader))
+	}))
+	defer endpoint.Close()
+
+	store := newMemoryStore(endpoint.URL)
+	NewDispatcher(store, 3, nil, nil).Publish(domain.ChangeEvent{Type: domain.EventServiceUpdated, OrgID: 2, ServiceID: 4})
+	delivery := store.nextRecorded(t)
+	assert.Equal(t, domain.DeliveryDelivered, delivery.Status)
+	assert.Equal(t, 1, delivery.Attempts)
+	assert.Equal(t, http.StatusOK, delivery.ResponseCode)
+	assert.Nil(t, delivery.NextAttemptAt)
+	assert.Equal(t, []int{2}, store.orgs)
+}
+
+func TestDispatcherRetriesUntilAttemptsRunOut(t *testing.T) {
+	endpoint := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer endpoint.Close()
+
+	store := newMemoryStore(endpoint.URL)
+	dispatcher := NewDispatcher(store, 3, nil, nil)
+	dispatcher.Publish(domain.ChangeEvent{Type: domain.EventVersionCreated, ServiceID: 1})
+	delivery := store.nextRecorded(t)
+	assert.Equal(t, domain.DeliveryPending, delivery.Status)
+	assert.Equal(t, http.StatusServiceUnavailable, delivery.ResponseCode)
+	assert.Contains(t, delivery.Error, "503")
+	require.NotNil(t, delivery.NextAttemptAt)
+	assert.WithinDuration(t, time.Now().Add(30*time.Second), *delivery.NextAttemptAt, 5*time.Second)
+
+	require.NoError(t, dispatcher.Retry(context.Background()))
+	delivery = store.nextRecorded(t)
+	assert.Equal(t, domain.DeliveryPending, delivery.Status)
+	assert.Equal(t, 2, delivery.Attempts)
+	assert.WithinDuration(t, time.Now().Add(time.Minute), *delivery.NextAttemptAt, 5*time.Second)
+
+	require.NoError(t, dispatcher.Retry(context.Background()))
+	delivery = store.nextRecorded(t)
+	assert.Equal(t, domain.DeliveryFailed, delivery.Status)
+	assert.Equal(t, 3, delivery.Attempts)
+	assert.Nil(t, delivery.NextAttemptAt)
+
+	require.NoError(t, dispatcher.Retry(context.Background()))
+	assert.Empty(t, store.recorded)
+}
+
+func TestBackoffDoublesUpToAnHour(t *testing.T) {
+	assert.Equal(t, 30*time.Second, Backoff(1))
+	assert.Equal(t, time.Minute, Backoff(2))
+	assert.Equal(t, 8*time.Minute, Backoff(5))
+	assert.Equal(t, time.Hour, Backoff(8))
+	assert.Equal(t, time.Hour, Backoff(50))
+}